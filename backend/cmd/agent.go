@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/agent"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity"
+	"github.com/eko/authz/backend/internal/event"
+	internal_fx "github.com/eko/authz/backend/internal/fx"
+	"github.com/eko/authz/backend/internal/helper"
+	internal_log "github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/internal/security"
+	"go.uber.org/fx"
+)
+
+const commandAgent = "agent"
+
+// runAgent answers checks locally from the decision data of the configured
+// resource kinds, kept in memory and synchronized from a central server.
+func runAgent() {
+	fx.New(
+		fx.Provide(context.Background),
+		internal_fx.Logger,
+
+		agent.FxModule(),
+		configs.FxModule(),
+		database.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		internal_log.FxModule(),
+		security.FxModule(),
+
+		fx.Decorate(agent.LocalDatabase),
+	).Run()
+}
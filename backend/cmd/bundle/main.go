@@ -0,0 +1,300 @@
+// Command bundle exports, plans and applies policy-as-code bundles against a
+// running Authz server, authenticating as a user.
+//
+//	bundle -server http://localhost:8080 export > bundle.yaml
+//	bundle -server http://localhost:8080 -file bundle.yaml plan
+//	bundle -server http://localhost:8080 -file bundle.yaml -prune apply
+//
+// It also migrates Casbin models and policies, either by converting them to a
+// bundle file to review or by importing them directly:
+//
+//	bundle -casbin-model model.conf -casbin-policy policy.csv -file bundle.yaml convert-casbin
+//	bundle -server http://localhost:8080 -casbin-model model.conf -casbin-policy policy.csv import-casbin
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/eko/authz/backend/internal/bundle/casbin"
+	"golang.org/x/oauth2"
+)
+
+const (
+	commandExport = "export"
+	commandPlan   = "plan"
+	commandApply  = "apply"
+
+	commandConvertCasbin = "convert-casbin"
+	commandImportCasbin  = "import-casbin"
+)
+
+type options struct {
+	server   string
+	username string
+	password string
+	file     string
+	format   string
+	prune    bool
+
+	casbinModel        string
+	casbinPolicy       string
+	casbinResourceKind string
+}
+
+func main() {
+	var opts = &options{}
+
+	flag.StringVar(&opts.server, "server", envOrDefault("AUTHZ_SERVER", "http://localhost:8080"), "Authz HTTP server URL")
+	flag.StringVar(&opts.username, "username", os.Getenv("AUTHZ_USERNAME"), "user name to authenticate with")
+	flag.StringVar(&opts.password, "password", os.Getenv("AUTHZ_PASSWORD"), "user password to authenticate with")
+	flag.StringVar(&opts.file, "file", "-", "bundle file to read (plan, apply) or write (export), - for stdin/stdout")
+	flag.StringVar(&opts.format, "format", "", "bundle format: yaml or json (defaults to the file extension, or yaml)")
+	flag.BoolVar(&opts.prune, "prune", false, "delete objects that are not declared in the bundle")
+	flag.StringVar(&opts.casbinModel, "casbin-model", "", "Casbin model file to convert or import")
+	flag.StringVar(&opts.casbinPolicy, "casbin-policy", "", "Casbin CSV policy file to convert or import")
+	flag.StringVar(&opts.casbinResourceKind, "casbin-resource-kind", casbin.DefaultResourceKind, "kind of resources created from Casbin objects")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] export|plan|apply|convert-casbin|import-casbin\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), flag.Arg(0), opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, command string, opts *options) error {
+	format, err := bundle.ParseFormat(formatName(opts))
+	if err != nil {
+		return err
+	}
+
+	opts.server = strings.TrimSuffix(opts.server, "/")
+
+	if command == commandConvertCasbin {
+		converted, err := convertCasbin(opts)
+		if err != nil {
+			return err
+		}
+
+		data, err := bundle.Encode(converted, format)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(opts.file, data)
+	}
+
+	client, err := authenticate(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case commandExport:
+		body, err := call(client, http.MethodGet, opts.server+"/v1/bundle?format="+string(format), "", nil)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(opts.file, body)
+
+	case commandPlan, commandApply:
+		data, err := readInput(opts.file)
+		if err != nil {
+			return err
+		}
+
+		// Decode locally first to report bundle errors before calling the server.
+		if _, err := bundle.Decode(data, format); err != nil {
+			return err
+		}
+
+		return send(client, opts, command, format, data)
+
+	case commandImportCasbin:
+		converted, err := convertCasbin(opts)
+		if err != nil {
+			return err
+		}
+
+		data, err := bundle.Encode(converted, bundle.FormatJSON)
+		if err != nil {
+			return err
+		}
+
+		return send(client, opts, commandApply, bundle.FormatJSON, data)
+
+	default:
+		return fmt.Errorf(
+			"unknown command %q, expected one of: %s, %s, %s, %s, %s",
+			command, commandExport, commandPlan, commandApply, commandConvertCasbin, commandImportCasbin,
+		)
+	}
+}
+
+// send posts the bundle to the plan or apply endpoint and prints the resulting plan.
+func send(client *http.Client, opts *options, command string, format bundle.Format, data []byte) error {
+	contentType := "application/yaml"
+	if format == bundle.FormatJSON {
+		contentType = "application/json"
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/bundle/%s?prune=%t", opts.server, command, opts.prune)
+
+	body, err := call(client, http.MethodPost, endpoint, contentType, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	plan := &bundle.Plan{}
+	if err := json.Unmarshal(body, plan); err != nil {
+		return fmt.Errorf("unable to decode plan: %v", err)
+	}
+
+	fmt.Print(plan.String())
+
+	return nil
+}
+
+// convertCasbin converts the Casbin model and policy files to a bundle and
+// prints the constructs that could not be translated on the standard error.
+func convertCasbin(opts *options) (*bundle.Bundle, error) {
+	if opts.casbinModel == "" || opts.casbinPolicy == "" {
+		return nil, fmt.Errorf("both -casbin-model and -casbin-policy are required")
+	}
+
+	modelData, err := os.ReadFile(opts.casbinModel)
+	if err != nil {
+		return nil, err
+	}
+
+	policyData, err := os.ReadFile(opts.casbinPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, report, err := casbin.Convert(modelData, policyData, casbin.WithResourceKind(opts.casbinResourceKind))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprint(os.Stderr, report.String())
+
+	return converted, nil
+}
+
+// authenticate retrieves an access token for the user and returns an HTTP
+// client sending it on each request.
+func authenticate(ctx context.Context, opts *options) (*http.Client, error) {
+	payload, err := json.Marshal(map[string]string{
+		"username": opts.username,
+		"password": opts.password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := call(http.DefaultClient, http.MethodPost, opts.server+"/v1/auth", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("unable to authenticate: %v", err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, fmt.Errorf("unable to decode authentication response: %v", err)
+	}
+
+	return oauth2.NewClient(ctx, oauth2.StaticTokenSource(token)), nil
+}
+
+func call(client *http.Client, method string, endpoint string, contentType string, body io.Reader) ([]byte, error) {
+	request, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to call server: %v", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read server response: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var errorResponse struct {
+			Message string `json:"message"`
+		}
+
+		if json.Unmarshal(data, &errorResponse) == nil && errorResponse.Message != "" {
+			return nil, fmt.Errorf("server returned %d: %s", response.StatusCode, errorResponse.Message)
+		}
+
+		return nil, fmt.Errorf("server returned %d", response.StatusCode)
+	}
+
+	return data, nil
+}
+
+func formatName(opts *options) string {
+	if opts.format != "" {
+		return opts.format
+	}
+
+	if strings.HasSuffix(opts.file, ".json") {
+		return string(bundle.FormatJSON)
+	}
+
+	return string(bundle.FormatYAML)
+}
+
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(file)
+}
+
+func writeOutput(file string, data []byte) error {
+	if file == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o600)
+}
+
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/eko/authz/backend/configs"
 	"github.com/eko/authz/backend/internal/audit"
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/eko/authz/backend/internal/changelog"
 	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/decision"
 	"github.com/eko/authz/backend/internal/entity"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/internal/fixtures"
@@ -14,34 +18,55 @@ import (
 	"github.com/eko/authz/backend/internal/grpc"
 	"github.com/eko/authz/backend/internal/helper"
 	"github.com/eko/authz/backend/internal/http"
+	"github.com/eko/authz/backend/internal/ldap"
 	"github.com/eko/authz/backend/internal/log"
 	"github.com/eko/authz/backend/internal/oauth"
 	"github.com/eko/authz/backend/internal/observability"
+	"github.com/eko/authz/backend/internal/review"
+	"github.com/eko/authz/backend/internal/scim"
 	"github.com/eko/authz/backend/internal/security"
 	"github.com/eko/authz/backend/internal/stats"
+	"github.com/eko/authz/backend/internal/sweeper"
 	"go.uber.org/fx"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == commandRebuildCompiled {
+		rebuildCompiled()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == commandAgent {
+		runAgent()
+		return
+	}
+
 	fx.New(
 		fx.Provide(context.Background),
 		internal_fx.Logger,
 
 		audit.FxModule(),
+		bundle.FxModule(),
+		changelog.FxModule(),
 		compile.FxModule(),
 		configs.FxModule(),
 		database.FxModule(),
+		decision.FxModule(),
 		event.FxModule(),
 		fixtures.FxModule(),
 		grpc.FxModule(),
 		helper.FxModule(),
 		http.FxModule(),
+		ldap.FxModule(),
 		log.FxModule(),
 		entity.FxModule(),
 		oauth.FxModule(),
 		observability.FxModule(),
+		review.FxModule(),
+		scim.FxModule(),
 		security.FxModule(),
 		stats.FxModule(),
+		sweeper.FxModule(),
 
 		fx.Invoke(
 			grpc.Run,
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity"
+	"github.com/eko/authz/backend/internal/event"
+	internal_fx "github.com/eko/authz/backend/internal/fx"
+	"github.com/eko/authz/backend/internal/helper"
+	internal_log "github.com/eko/authz/backend/internal/log"
+	"go.uber.org/fx"
+)
+
+const commandRebuildCompiled = "rebuild-compiled"
+
+// rebuildCompiled rebuilds compiled policies from scratch, then exits.
+// Changes made meanwhile are compiled again by the running servers.
+func rebuildCompiled() {
+	var rebuilder compile.Rebuilder
+
+	app := fx.New(
+		fx.Provide(context.Background),
+		internal_fx.Logger,
+
+		compile.RebuildFxModule(),
+		configs.FxModule(),
+		database.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		internal_log.FxModule(),
+
+		fx.Populate(&rebuilder),
+	)
+
+	if err := app.Start(context.Background()); err != nil {
+		log.Fatalf("unable to start application: %v", err)
+	}
+
+	err := rebuilder.Rebuild()
+
+	if stopErr := app.Stop(context.Background()); stopErr != nil {
+		log.Printf("unable to stop application: %v", stopErr)
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,23 @@
+package configs
+
+import "time"
+
+// Agent configures the agent mode, answering checks locally from the decision
+// data of some resource kinds synchronized from a central server.
+type Agent struct {
+	ClientID       string        `config:"agent_client_id"`
+	ClientSecret   string        `config:"agent_client_secret"`
+	ListenAddr     string        `config:"agent_listen_addr"`
+	ReconnectDelay time.Duration `config:"agent_reconnect_delay"`
+	ResourceKinds  string        `config:"agent_resource_kinds"`
+	ServerAddr     string        `config:"agent_server_addr"`
+	ServerTLS      bool          `config:"agent_server_tls"`
+}
+
+func newAgent() *Agent {
+	return &Agent{
+		ListenAddr:     "localhost:8091",
+		ReconnectDelay: 1 * time.Second,
+		ServerAddr:     "localhost:8081",
+	}
+}
@@ -3,23 +3,42 @@ package configs
 import "time"
 
 type App struct {
-	AuditCleanDelay            time.Duration `config:"app_audit_clean_delay"`
-	AuditCleanDaysToKeep       int           `config:"app_audit_clean_days_to_keep"`
-	AuditFlushDelay            time.Duration `config:"app_audit_flush_delay"`
-	AuditResourceKindRegex     string        `config:"app_audit_resource_kind_regex"`
-	DispatcherEventChannelSize int           `config:"dispatcher_event_channel_size"`
-	MetricsEnabled             bool          `config:"app_metrics_enabled"`
-	StatsCleanDelay            time.Duration `config:"app_stats_clean_delay"`
-	StatsCleanDaysToKeep       int           `config:"app_stats_clean_days_to_keep"`
-	StatsFlushDelay            time.Duration `config:"app_stats_flush_delay"`
-	StatsResourceKindRegex     string        `config:"app_stats_resource_kind_regex"`
-	TraceEnabled               bool          `config:"app_trace_enabled"`
-	TraceExporter              string        `config:"app_trace_exporter"`
-	TraceJaegerEndpoint        string        `config:"app_trace_jaeger_endpoint"`
-	TraceOtlpDialTimeout       time.Duration `config:"app_trace_otlp_dial_timeout"`
-	TraceOtlpEndpoint          string        `config:"app_trace_otlp_endpoint"`
-	TraceZipkinURL             string        `config:"app_trace_zipkin_url"`
-	TraceSampleRatio           float64       `config:"app_trace_sample_ratio"`
+	AuditCleanDelay                time.Duration `config:"app_audit_clean_delay"`
+	AuditCleanDaysToKeep           int           `config:"app_audit_clean_days_to_keep"`
+	AuditFlushDelay                time.Duration `config:"app_audit_flush_delay"`
+	AuditResourceKindRegex         string        `config:"app_audit_resource_kind_regex"`
+	ChangeLogCleanDelay            time.Duration `config:"app_change_log_clean_delay"`
+	ChangeLogRetention             time.Duration `config:"app_change_log_retention"`
+	CompileJobClaimTimeout         time.Duration `config:"app_compile_job_claim_timeout"`
+	CompileJobMaxAttempts          int           `config:"app_compile_job_max_attempts"`
+	CompileJobMaxRetryDelay        time.Duration `config:"app_compile_job_max_retry_delay"`
+	CompileJobPollDelay            time.Duration `config:"app_compile_job_poll_delay"`
+	CompileJobRetryDelay           time.Duration `config:"app_compile_job_retry_delay"`
+	CompileJobWorkers              int           `config:"app_compile_job_workers"`
+	ConsistencyTimeout             time.Duration `config:"app_consistency_timeout"`
+	DecisionCacheSize              int           `config:"app_decision_cache_size"`
+	DecisionCacheTTL               time.Duration `config:"app_decision_cache_ttl"`
+	DecisionStore                  string        `config:"app_decision_store"`
+	DecisionStoreReloadDelay       time.Duration `config:"app_decision_store_reload_delay"`
+	DispatcherEventChannelSize     int           `config:"dispatcher_event_channel_size"`
+	MetricsEnabled                 bool          `config:"app_metrics_enabled"`
+	PolicyCombiningAlgorithm       string        `config:"app_policy_combining_algorithm"`
+	PolicyCombiningAlgorithmByKind string        `config:"app_policy_combining_algorithm_by_kind"`
+	PolicyLintBlocking             bool          `config:"app_policy_lint_blocking"`
+	PolicySweepDelay               time.Duration `config:"app_policy_sweep_delay"`
+	ReviewCheckDelay               time.Duration `config:"app_review_check_delay"`
+	ReviewReminderDelay            time.Duration `config:"app_review_reminder_delay"`
+	StatsCleanDelay                time.Duration `config:"app_stats_clean_delay"`
+	StatsCleanDaysToKeep           int           `config:"app_stats_clean_days_to_keep"`
+	StatsFlushDelay                time.Duration `config:"app_stats_flush_delay"`
+	StatsResourceKindRegex         string        `config:"app_stats_resource_kind_regex"`
+	TraceEnabled                   bool          `config:"app_trace_enabled"`
+	TraceExporter                  string        `config:"app_trace_exporter"`
+	TraceJaegerEndpoint            string        `config:"app_trace_jaeger_endpoint"`
+	TraceOtlpDialTimeout           time.Duration `config:"app_trace_otlp_dial_timeout"`
+	TraceOtlpEndpoint              string        `config:"app_trace_otlp_endpoint"`
+	TraceZipkinURL                 string        `config:"app_trace_zipkin_url"`
+	TraceSampleRatio               float64       `config:"app_trace_sample_ratio"`
 }
 
 func newApp() *App {
@@ -28,8 +47,26 @@ func newApp() *App {
 		AuditCleanDaysToKeep:       7,
 		AuditFlushDelay:            3 * time.Second,
 		AuditResourceKindRegex:     `.*`,
+		ChangeLogCleanDelay:        1 * time.Minute,
+		ChangeLogRetention:         1 * time.Hour,
+		CompileJobClaimTimeout:     1 * time.Minute,
+		CompileJobMaxAttempts:      5,
+		CompileJobMaxRetryDelay:    5 * time.Minute,
+		CompileJobPollDelay:        1 * time.Second,
+		CompileJobRetryDelay:       1 * time.Second,
+		CompileJobWorkers:          4,
+		ConsistencyTimeout:         5 * time.Second,
+		DecisionCacheSize:          0,
+		DecisionCacheTTL:           30 * time.Second,
+		DecisionStore:              "sql",
+		DecisionStoreReloadDelay:   1 * time.Minute,
 		DispatcherEventChannelSize: 10000,
 		MetricsEnabled:             false,
+		PolicyCombiningAlgorithm:   "deny-overrides",
+		PolicyLintBlocking:         false,
+		PolicySweepDelay:           1 * time.Minute,
+		ReviewCheckDelay:           1 * time.Minute,
+		ReviewReminderDelay:        24 * time.Hour,
 		StatsCleanDelay:            1 * time.Hour,
 		StatsCleanDaysToKeep:       30,
 		StatsFlushDelay:            3 * time.Second,
@@ -13,27 +13,39 @@ const (
 )
 
 type Base struct {
+	Agent      *Agent
 	App        *App
 	Auth       *Auth
 	Database   *Database
 	Logger     *Logger
 	GRPCServer *GRPCServer
 	HTTPServer *HTTPServer
+	LDAP       *LDAP
 	OAuth      *OAuth
+	SCIM       *SCIM
 	User       *User
 }
 
-func Load(ctx context.Context) *Base {
-	var cfg = &Base{
+// New returns the default configuration.
+func New() *Base {
+	return &Base{
+		Agent:      newAgent(),
 		App:        newApp(),
 		Auth:       newAuth(),
 		Database:   newDatabase(),
 		Logger:     newLogger(),
 		GRPCServer: newGRPCServer(),
 		HTTPServer: newHTTPServer(),
+		LDAP:       newLDAP(),
 		OAuth:      newOAuth(),
+		SCIM:       newSCIM(),
 		User:       newUser(),
 	}
+}
+
+// Load returns the default configuration overridden by environment variables.
+func Load(ctx context.Context) *Base {
+	var cfg = New()
 
 	loader := confita.NewLoader(
 		env.NewBackend(),
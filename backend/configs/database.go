@@ -17,6 +17,9 @@ type Database struct {
 	Password string `config:"database_password"`
 	Dbname   string `config:"database_name"`
 	Timezone string `config:"database_timezone"`
+
+	// InMemory keeps a sqlite database in memory, Dbname being its name.
+	InMemory bool
 }
 
 func (d Database) MysqlDSN() string {
@@ -41,6 +44,10 @@ func (d Database) PostgresDSN() string {
 }
 
 func (d Database) SqliteDSN() string {
+	if d.InMemory {
+		return fmt.Sprintf("file:%s?cache=shared&mode=memory&_pragma=foreign_keys(1)", d.Dbname)
+	}
+
 	return fmt.Sprintf("file:%s?cache=shared&mode=rwc&_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)", d.Dbname)
 }
 
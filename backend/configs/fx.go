@@ -6,16 +6,32 @@ import (
 
 func FxModule() fx.Option {
 	return fx.Module("configs",
-		fx.Provide(
-			Load,
-			func(cfg *Base) *App { return cfg.App },
-			func(cfg *Base) *Auth { return cfg.Auth },
-			func(cfg *Base) *Database { return cfg.Database },
-			func(cfg *Base) *GRPCServer { return cfg.GRPCServer },
-			func(cfg *Base) *HTTPServer { return cfg.HTTPServer },
-			func(cfg *Base) *Logger { return cfg.Logger },
-			func(cfg *Base) *OAuth { return cfg.OAuth },
-			func(cfg *Base) *User { return cfg.User },
-		),
+		fx.Provide(Load),
+		provideSections(),
+	)
+}
+
+// StaticFxModule provides the given configuration instead of loading it from
+// environment variables.
+func StaticFxModule(cfg *Base) fx.Option {
+	return fx.Module("configs",
+		fx.Supply(cfg),
+		provideSections(),
+	)
+}
+
+func provideSections() fx.Option {
+	return fx.Provide(
+		func(cfg *Base) *Agent { return cfg.Agent },
+		func(cfg *Base) *App { return cfg.App },
+		func(cfg *Base) *Auth { return cfg.Auth },
+		func(cfg *Base) *Database { return cfg.Database },
+		func(cfg *Base) *GRPCServer { return cfg.GRPCServer },
+		func(cfg *Base) *HTTPServer { return cfg.HTTPServer },
+		func(cfg *Base) *LDAP { return cfg.LDAP },
+		func(cfg *Base) *Logger { return cfg.Logger },
+		func(cfg *Base) *OAuth { return cfg.OAuth },
+		func(cfg *Base) *SCIM { return cfg.SCIM },
+		func(cfg *Base) *User { return cfg.User },
 	)
 }
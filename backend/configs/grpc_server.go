@@ -1,11 +1,17 @@
 package configs
 
+import "time"
+
 type GRPCServer struct {
-	Addr string `config:"grpc_server_addr"`
+	Addr                   string        `config:"grpc_server_addr"`
+	AgentSyncDelay         time.Duration `config:"grpc_server_agent_sync_delay"`
+	CheckStreamConcurrency int           `config:"grpc_server_check_stream_concurrency"`
 }
 
 func newGRPCServer() *GRPCServer {
 	return &GRPCServer{
-		Addr: ":8081",
+		Addr:                   ":8081",
+		AgentSyncDelay:         1 * time.Second,
+		CheckStreamConcurrency: 16,
 	}
 }
@@ -0,0 +1,34 @@
+package configs
+
+import "time"
+
+type LDAP struct {
+	AttributesMapping    []string      `config:"ldap_attributes"`
+	BaseDNs              []string      `config:"ldap_base_dns"`
+	BindDN               string        `config:"ldap_bind_dn"`
+	BindPassword         string        `config:"ldap_bind_password"`
+	GroupBaseDNs         []string      `config:"ldap_group_base_dns"`
+	GroupFilter          string        `config:"ldap_group_filter"`
+	GroupMemberAttribute string        `config:"ldap_group_member_attribute"`
+	GroupsAttribute      string        `config:"ldap_groups_attribute"`
+	GroupsRoles          []string      `config:"ldap_groups_roles"`
+	InsecureSkipVerify   bool          `config:"ldap_insecure_skip_verify"`
+	PageSize             uint32        `config:"ldap_page_size"`
+	StartTLS             bool          `config:"ldap_start_tls"`
+	SyncDelay            time.Duration `config:"ldap_sync_delay"`
+	URL                  string        `config:"ldap_url"`
+	UserFilter           string        `config:"ldap_user_filter"`
+	UserIDAttribute      string        `config:"ldap_user_id_attribute"`
+}
+
+func newLDAP() *LDAP {
+	return &LDAP{
+		GroupFilter:          "(|(objectClass=groupOfNames)(objectClass=group))",
+		GroupMemberAttribute: "member",
+		GroupsAttribute:      "memberOf",
+		PageSize:             500,
+		SyncDelay:            15 * time.Minute,
+		UserFilter:           "(objectClass=person)",
+		UserIDAttribute:      "uid",
+	}
+}
@@ -1,10 +1,13 @@
 package configs
 
 type OAuth struct {
+	ClaimsAttributes    []string `config:"oauth_claims_attributes"`
 	ClientID            string   `config:"oauth_client_id"`
 	ClientSecret        string   `config:"oauth_client_secret"`
 	CookiesDomainName   string   `config:"oauth_cookies_domain_name"`
 	FrontendRedirectURL string   `config:"oauth_frontend_redirect_url"`
+	GroupsClaim         string   `config:"oauth_groups_claim"`
+	GroupsRoles         []string `config:"oauth_groups_roles"`
 	IssuerURL           string   `config:"oauth_issuer_url"`
 	RedirectURL         string   `config:"oauth_redirect_url"`
 	Scopes              []string `config:"oauth_scopes"`
@@ -14,6 +17,7 @@ func newOAuth() *OAuth {
 	return &OAuth{
 		CookiesDomainName:   "localhost",
 		FrontendRedirectURL: "http://localhost:3000",
+		GroupsClaim:         "groups",
 		RedirectURL:         "http://localhost:8080/v1/oauth/callback",
 		Scopes:              []string{"profile", "email"},
 	}
@@ -0,0 +1,9 @@
+package configs
+
+type SCIM struct {
+	ClientID string `config:"scim_client_id"`
+}
+
+func newSCIM() *SCIM {
+	return &SCIM{}
+}
@@ -0,0 +1,67 @@
+//go:build functional
+// +build functional
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eko/authz/backend/pkg/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	agentAddr = "localhost:8091"
+)
+
+type agentFeature struct {
+	api *apiFeature
+	err error
+}
+
+func (a *agentFeature) reset() {
+	a.err = nil
+}
+
+func (a *agentFeature) iSendACheckRequestToTheAgent() error {
+	if a.api.token == "" {
+		return fmt.Errorf("no access token has been returned by a previous authentication")
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+a.api.token)
+
+	return a.check(ctx)
+}
+
+func (a *agentFeature) iSendACheckRequestToTheAgentWithoutAuthentication() error {
+	return a.check(context.Background())
+}
+
+func (a *agentFeature) check(ctx context.Context) error {
+	conn, err := grpc.NewClient(agentAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("unable to create agent client: %v", err)
+	}
+
+	defer conn.Close()
+
+	_, a.err = authz.NewApiClient(conn).Check(ctx, &authz.CheckRequest{
+		Checks: []*authz.Check{
+			{Principal: "authz-user-admin", ResourceKind: "post", ResourceValue: "123", Action: "read"},
+		},
+	})
+
+	return nil
+}
+
+func (a *agentFeature) theGRPCStatusCodeShouldBe(expected string) error {
+	if code := status.Code(a.err).String(); code != expected {
+		return fmt.Errorf("expected gRPC status code %s, got %s (%v)", expected, code, a.err)
+	}
+
+	return nil
+}
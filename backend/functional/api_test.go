@@ -12,6 +12,7 @@ import (
 
 	"github.com/cucumber/godog"
 	"github.com/eko/authz/backend/internal/http/handler"
+	"github.com/eko/authz/backend/internal/http/middleware"
 )
 
 var (
@@ -19,15 +20,17 @@ var (
 )
 
 type apiFeature struct {
-	httpClient *http.Client
-	req        *http.Request
-	resp       *http.Response
-	token      string
+	httpClient       *http.Client
+	req              *http.Request
+	resp             *http.Response
+	token            string
+	consistencyToken string
 }
 
 func (a *apiFeature) reset(*godog.Scenario) error {
 	a.req = nil
 	a.resp = nil
+	a.consistencyToken = ""
 	return nil
 }
 
@@ -64,6 +67,26 @@ func (a *apiFeature) iSendRequestToWithPayload(method, endpoint string, body *go
 	return a.httpCall(method, endpoint, reader, nil)
 }
 
+func (a *apiFeature) iSendRequestToWithConsistencyTokenAndPayload(method, endpoint string, body *godog.DocString) error {
+	if a.consistencyToken == "" {
+		return fmt.Errorf("no consistency token has been returned by previous requests")
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(body.Content), &payload); err != nil {
+		return fmt.Errorf("unable to unmarshal payload: %v", err)
+	}
+
+	payload["consistency_token"] = a.consistencyToken
+
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal payload: %v", err)
+	}
+
+	return a.httpCall(method, endpoint, bytes.NewReader(content), nil)
+}
+
 func (a *apiFeature) httpCall(method, endpoint string, content io.Reader, writer *multipart.Writer) error {
 	url := baseURL + endpoint
 
@@ -90,6 +113,10 @@ func (a *apiFeature) httpCall(method, endpoint string, content io.Reader, writer
 	a.req = req
 	a.resp = resp
 
+	if token := resp.Header.Get(middleware.ConsistencyTokenHeader); token != "" {
+		a.consistencyToken = token
+	}
+
 	return nil
 }
 
@@ -113,6 +140,12 @@ func (a *apiFeature) theResponseCodeShouldBe(code int) error {
 }
 
 func (a *apiFeature) theResponseShouldMatchJSON(body *godog.DocString) (err error) {
+	return a.theResponseShouldMatchJSONIgnoring("", body)
+}
+
+// theResponseShouldMatchJSONIgnoring matches the response without the given
+// comma-separated fields, whose values cannot be predicted.
+func (a *apiFeature) theResponseShouldMatchJSONIgnoring(fields string, body *godog.DocString) (err error) {
 	if a.resp == nil {
 		return fmt.Errorf("http response is nil")
 	}
@@ -133,6 +166,13 @@ func (a *apiFeature) theResponseShouldMatchJSON(body *godog.DocString) (err erro
 		return
 	}
 
+	if fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			removeField(expected, field)
+			removeField(actual, field)
+		}
+	}
+
 	sortArray(expected)
 	sortArray(actual)
 
@@ -146,6 +186,21 @@ func (a *apiFeature) theResponseShouldMatchJSON(body *godog.DocString) (err erro
 	return nil
 }
 
+func removeField(data any, field string) {
+	switch value := data.(type) {
+	case []any:
+		for _, item := range value {
+			removeField(item, field)
+		}
+	case map[string]any:
+		delete(value, field)
+
+		for _, item := range value {
+			removeField(item, field)
+		}
+	}
+}
+
 func sortArray(data map[string]interface{}) {
 	for _, v := range data {
 		switch vv := v.(type) {
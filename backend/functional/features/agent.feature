@@ -0,0 +1,12 @@
+@agent
+Feature: agent
+  Test agent gRPC server authentication
+
+  Scenario: Check without authentication
+    When I send a check gRPC request to the agent without authentication
+    Then the gRPC status code should be "Unauthenticated"
+
+  Scenario: Check with an access token issued by the server
+    Given I authenticate with username "admin" and password "changeme"
+    When I send a check gRPC request to the agent
+    Then the gRPC status code should be "OK"
@@ -0,0 +1,107 @@
+@bundle
+Feature: bundle
+  Test bundle-related APIs
+
+  Scenario: Plan and apply a bundle
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {"id": "legacy-principal"}
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/bundle/plan?prune=true" with payload:
+      """
+      {
+        "version": 1,
+        "resources": [
+          {"id": "post.123", "kind": "post", "value": "123"}
+        ],
+        "policies": [
+          {"id": "post-readers", "resources": ["post.123"], "actions": ["read"]}
+        ],
+        "roles": [
+          {"id": "reader", "policies": ["post-readers"]}
+        ],
+        "principals": [
+          {"id": "my-principal", "roles": ["reader"]}
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "changes": [
+          {"operation": "create", "object": "resource", "id": "post.123"},
+          {"operation": "create", "object": "policy", "id": "post-readers"},
+          {"operation": "create", "object": "role", "id": "reader"},
+          {"operation": "create", "object": "principal", "id": "my-principal"},
+          {"operation": "delete", "object": "principal", "id": "legacy-principal"}
+        ]
+      }
+      """
+    When I send "POST" request to "/v1/bundle/apply?prune=true" with payload:
+      """
+      {
+        "version": 1,
+        "resources": [
+          {"id": "post.123", "kind": "post", "value": "123"}
+        ],
+        "policies": [
+          {"id": "post-readers", "resources": ["post.123"], "actions": ["read"]}
+        ],
+        "roles": [
+          {"id": "reader", "policies": ["post-readers"]}
+        ],
+        "principals": [
+          {"id": "my-principal", "roles": ["reader"]}
+        ]
+      }
+      """
+    Then the response code should be 200
+    And I wait "500ms"
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "read"
+          }
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "action": "read",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": true
+          }
+        ]
+      }
+      """
+    When I send "GET" request to "/v1/principals/legacy-principal"
+    Then the response code should be 404
+
+  Scenario: Apply an invalid bundle
+    Given I authenticate with username "admin" and password "changeme"
+    When I send "POST" request to "/v1/bundle/apply" with payload:
+      """
+      {
+        "version": 1,
+        "roles": [
+          {"id": "reader", "policies": ["unknown-policy"]}
+        ]
+      }
+      """
+    Then the response code should be 400
+    When I send "GET" request to "/v1/roles/reader"
+    Then the response code should be 404
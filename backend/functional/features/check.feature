@@ -52,8 +52,7 @@ Feature: check
       }
       """
     And the response code should be 200
-    And I wait "500ms"
-    When I send "POST" request to "/v1/check" with payload:
+    When I send "POST" request to "/v1/check" with consistency token and payload:
       """
       {
         "checks": [
@@ -530,3 +529,535 @@ Feature: check
         ]
       }
       """
+
+  Scenario: Check for access (using policy validity windows)
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.123", "kind": "post", "value": "123"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-123-policy-expired",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["create"],
+        "not_after": "2099-12-31T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-123-policy-valid",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["update"],
+        "not_before": "2099-12-31T00:00:00Z",
+        "not_after": "2100-01-02T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-123-policy-scheduled",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["delete"],
+        "schedule": "0 9 * * *",
+        "schedule_duration": "8h"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "my-post-123-role",
+        "policies": [
+            "my-post-123-policy-expired",
+            "my-post-123-policy-valid",
+            "my-post-123-policy-scheduled"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "my-principal",
+        "roles": [
+            "my-post-123-role"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "create"
+          },
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "update"
+          },
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "delete"
+          }
+        ]
+      }
+      """
+    And the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "action": "create",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": false
+          },
+          {
+            "action": "update",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": true
+          },
+          {
+            "action": "delete",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+
+  Scenario: Check for access (using deny policies)
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.123", "kind": "post", "value": "123"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.456", "kind": "post", "value": "456"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-policy-allow",
+        "resources": [
+            "post.*"
+        ],
+        "actions": ["update"],
+        "priority": 10
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-123-policy-deny",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["update"],
+        "effect": "deny"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "my-post-role",
+        "policies": [
+            "my-post-policy-allow",
+            "my-post-123-policy-deny"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "my-principal",
+        "roles": [
+            "my-post-role"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "update"
+          },
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "456",
+            "action": "update"
+          }
+        ]
+      }
+      """
+    And the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "action": "update",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": false
+          },
+          {
+            "action": "update",
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "456",
+            "is_allowed": true
+          }
+        ]
+      }
+      """
+
+  Scenario: Check for access (using cedar policies)
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {
+        "id": "post.123",
+        "kind": "post",
+        "value": "123",
+        "attributes": [
+          {"key": "team", "value": "blue"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-edit",
+        "resources": ["post.*"],
+        "actions": ["edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "alice",
+        "attributes": [
+          {"key": "team", "value": "blue"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "bob",
+        "attributes": [
+          {"key": "team", "value": "red"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/cedar-policies" with payload:
+      """
+      {
+        "id": "team-edit",
+        "source": "permit (principal, action == Action::\"edit\", resource is post) when { resource.team == principal.team && context.ip == \"10.0.0.1\" };"
+      }
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "alice",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "context": {"ip": "10.0.0.1"}
+          },
+          {
+            "principal": "bob",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "context": {"ip": "10.0.0.1"}
+          },
+          {
+            "principal": "alice",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit"
+          }
+        ]
+      }
+      """
+    And the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "action": "edit",
+            "principal": "alice",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "context": {"ip": "10.0.0.1"},
+            "is_allowed": true
+          },
+          {
+            "action": "edit",
+            "principal": "bob",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "context": {"ip": "10.0.0.1"},
+            "is_allowed": false
+          },
+          {
+            "action": "edit",
+            "principal": "alice",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+
+  Scenario: Create a cedar policy referencing an unknown action
+    Given I authenticate with username "admin" and password "changeme"
+    When I send "POST" request to "/v1/cedar-policies" with payload:
+      """
+      {
+        "id": "invalid",
+        "source": "permit (principal, action == Action::\"unknown\", resource);"
+      }
+      """
+    Then the response code should be 400
+    And the response should match json:
+      """
+      {
+        "error": true,
+        "message": "invalid cedar policy: unknown action \"unknown\""
+      }
+      """
+
+  Scenario: Check for access once items are deleted
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {
+        "id": "post.123",
+        "kind": "post",
+        "value": "123",
+        "attributes": [
+          {"key": "owner_id", "value": "owner-123"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-owner-policy",
+        "resources": [
+            "post.*"
+        ],
+        "actions": ["edit"],
+        "attribute_rules": [
+          "principal.owner_id == resource.owner_id"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "my-post-readers-policy",
+        "resources": [
+            "post.*"
+        ],
+        "actions": ["read"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "my-post-readers-role",
+        "policies": ["my-post-readers-policy"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "my-principal",
+        "attributes": [
+          {"key": "owner_id", "value": "owner-123"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "my-other-principal",
+        "roles": ["my-post-readers-role"],
+        "attributes": [
+          {"key": "owner_id", "value": "owner-123"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/check" with consistency token and payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit"
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit"
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "read"
+          }
+        ]
+      }
+      """
+    And the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "is_allowed": true
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "is_allowed": true
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "read",
+            "is_allowed": true
+          }
+        ]
+      }
+      """
+    And I send "DELETE" request to "/v1/principals/my-principal"
+    And the response code should be 200
+    And I send "DELETE" request to "/v1/resources/post.123"
+    And the response code should be 200
+    And I send "DELETE" request to "/v1/policies/my-post-readers-policy"
+    And the response code should be 200
+    When I send "POST" request to "/v1/check" with consistency token and payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit"
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "read"
+          }
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "is_allowed": false
+          },
+          {
+            "principal": "my-other-principal",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "read",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+    When I send "GET" request to "/v1/compiled?filter=principal_id:contains:my-principal"
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "data": [],
+        "page": 0,
+        "size": 100,
+        "total": 0
+      }
+      """
@@ -43,7 +43,7 @@ Feature: compiled
     And I wait "500ms"
     When I send "GET" request to "/v1/compiled?filter=policy_id:contains:my-post-policy&sort=action_id:asc"
     Then the response code should be 200
-    And the response should match json:
+    And the response should match json ignoring "version":
       """
       {
         "data": [
@@ -54,8 +54,7 @@ Feature: compiled
             "principal_id": "my-principal",
             "resource_kind": "post",
             "resource_value": "123",
-            "updated_at": "2100-01-01T01:00:00Z",
-            "version": 4102448400
+            "updated_at": "2100-01-01T01:00:00Z"
           },
           {
             "action_id": "update",
@@ -64,8 +63,7 @@ Feature: compiled
             "principal_id": "my-principal",
             "resource_kind": "post",
             "resource_value": "123",
-            "updated_at": "2100-01-01T01:00:00Z",
-            "version": 4102448400
+            "updated_at": "2100-01-01T01:00:00Z"
           }
         ],
         "page": 0,
@@ -0,0 +1,238 @@
+@delegation
+Feature: delegation
+  Test delegation-related APIs
+
+  Scenario: Delegate permissions to another principal
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "calendar.ceo", "kind": "calendar", "value": "ceo"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "ceo-calendar",
+        "resources": [
+            "calendar.ceo"
+        ],
+        "actions": ["read", "edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "ceo",
+        "policies": [
+            "ceo-calendar"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "ceo",
+        "roles": [
+            "ceo"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "assistant"
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    And I send "POST" request to "/v1/delegations" with payload:
+      """
+      {
+        "id": "ceo-calendar-to-assistant",
+        "delegator_id": "ceo",
+        "delegate_id": "assistant",
+        "resources": [
+            "calendar.ceo"
+        ],
+        "actions": ["edit"],
+        "expires_at": "2100-01-02T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "edit"
+          },
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "read"
+          }
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "edit",
+            "is_allowed": true
+          },
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "read",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+
+  Scenario: Delegation is revoked when delegator loses access
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "calendar.ceo", "kind": "calendar", "value": "ceo"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "ceo-calendar",
+        "resources": [
+            "calendar.ceo"
+        ],
+        "actions": ["edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "ceo",
+        "policies": [
+            "ceo-calendar"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "ceo",
+        "roles": [
+            "ceo"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "assistant"
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    And I send "POST" request to "/v1/delegations" with payload:
+      """
+      {
+        "id": "ceo-calendar-to-assistant",
+        "delegator_id": "ceo",
+        "delegate_id": "assistant",
+        "resources": [
+            "calendar.ceo"
+        ],
+        "actions": ["edit"],
+        "expires_at": "2100-01-02T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    And I send "PUT" request to "/v1/principals/ceo" with payload:
+      """
+      {
+        "roles": []
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "edit"
+          }
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "principal": "assistant",
+            "resource_kind": "calendar",
+            "resource_value": "ceo",
+            "action": "edit",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+
+  Scenario: Cannot delegate permissions the delegator does not have
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "calendar.ceo", "kind": "calendar", "value": "ceo"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "ceo"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "assistant"
+      }
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/delegations" with payload:
+      """
+      {
+        "id": "ceo-calendar-to-assistant",
+        "delegator_id": "ceo",
+        "delegate_id": "assistant",
+        "resources": [
+            "calendar.ceo"
+        ],
+        "actions": ["edit"],
+        "expires_at": "2100-01-02T00:00:00Z"
+      }
+      """
+    Then the response code should be 400
@@ -0,0 +1,75 @@
+@lint
+Feature: lint
+  Test lint-related APIs
+
+  Scenario: Lint the authorization model
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {
+        "id": "post.123",
+        "kind": "post",
+        "value": "123",
+        "attributes": [
+          {"key": "owner_id", "value": "alice"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-owner",
+        "resources": ["post.*"],
+        "actions": ["edit"],
+        "attribute_rules": ["resource.owner_id == principal.id"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-123-edit",
+        "resources": ["post.123"],
+        "actions": ["edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "editor",
+        "policies": ["post-owner"]
+      }
+      """
+    And the response code should be 200
+    When I send "GET" request to "/v1/lint"
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "findings": [
+          {
+            "rule": "unknown-attribute",
+            "severity": "error",
+            "object_type": "policy",
+            "object_id": "post-owner",
+            "message": "policy \"post-owner\" attribute rule \"resource.owner_id == principal.id\" references principal attribute \"id\" that no principal has"
+          },
+          {
+            "rule": "unattached-policy",
+            "severity": "warning",
+            "object_type": "policy",
+            "object_id": "post-123-edit",
+            "message": "policy \"post-123-edit\" is not attached to any role"
+          },
+          {
+            "rule": "role-without-principals",
+            "severity": "warning",
+            "object_type": "role",
+            "object_id": "editor",
+            "message": "role \"editor\" is not given to any principal"
+          }
+        ]
+      }
+      """
@@ -30,6 +30,8 @@ Feature: policy
             "updated_at": "2100-01-01T01:00:00Z"
           }
         ],
+        "effect": "allow",
+        "priority": 0,
         "attribute_rules": null,
         "id": "my-post-123-policy",
         "resources": [
@@ -111,6 +113,8 @@ Feature: policy
             "updated_at": "2100-01-01T01:00:00Z"
           }
         ],
+        "effect": "allow",
+        "priority": 0,
         "attribute_rules": null,
         "id": "my-post-policy",
         "resources": [
@@ -158,6 +162,8 @@ Feature: policy
             "updated_at": "2100-01-01T01:00:00Z"
           }
         ],
+        "effect": "allow",
+        "priority": 0,
         "attribute_rules": null,
         "id": "my-post-123-policy",
         "resources": [
@@ -247,6 +253,8 @@ Feature: policy
                 "updated_at": "2100-01-01T01:00:00Z"
               }
             ],
+            "effect": "allow",
+            "priority": 0,
             "attribute_rules": null,
             "id": "my-post-123-policy-1",
             "resources": [
@@ -270,6 +278,8 @@ Feature: policy
                 "updated_at": "2100-01-01T01:00:00Z"
               }
             ],
+            "effect": "allow",
+            "priority": 0,
             "attribute_rules": null,
             "id": "my-post-123-policy-2",
             "resources": [
@@ -0,0 +1,101 @@
+@resource_kind
+Feature: resource_kind
+  Test resource kind-related APIs
+
+  Scenario: Create a policy using an action that is not declared on the resource kind
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resource-kinds" with payload:
+      """
+      {"id": "post", "actions": ["read", "edit"]}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.123", "kind": "post", "value": "123"}
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-123",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["raed"]
+      }
+      """
+    Then the response code should be 500
+    And the response should match json:
+      """
+      {
+        "error": true,
+        "message": "unknown action \"raed\" on resource kind \"post\" (declared actions: edit, read)"
+      }
+      """
+
+  Scenario: Check for an action that is not declared on the resource kind
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resource-kinds" with payload:
+      """
+      {"id": "post", "actions": ["read", "edit"]}
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "user-123",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "raed"
+          }
+        ]
+      }
+      """
+    Then the response code should be 400
+    And the response should match json:
+      """
+      {
+        "error": true,
+        "message": "unknown action \"raed\" on resource kind \"post\" (declared actions: edit, read)"
+      }
+      """
+
+  Scenario: Create a resource with attributes that do not comply with the resource kind schema
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resource-kinds" with payload:
+      """
+      {
+        "id": "post",
+        "actions": ["read", "edit"],
+        "attributes_schema": {
+          "type": "object",
+          "properties": {
+            "owner_id": {"type": "string"},
+            "words": {"type": "integer", "minimum": 0}
+          },
+          "required": ["owner_id"]
+        }
+      }
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/resources" with payload:
+      """
+      {
+        "id": "post.123",
+        "kind": "post",
+        "value": "123",
+        "attributes": [
+          {"key": "words", "value": -1}
+        ]
+      }
+      """
+    Then the response code should be 500
+    And the response should match json:
+      """
+      {
+        "error": true,
+        "message": "invalid attributes for resource kind \"post\": owner_id is required, words must be greater than or equal to 0"
+      }
+      """
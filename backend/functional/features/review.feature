@@ -0,0 +1,161 @@
+@review
+Feature: review
+  Test access review campaign-related APIs
+
+  Scenario: Revoke a role during an access review campaign
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.123", "kind": "post", "value": "123"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-edit",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "post-editor",
+        "policies": [
+            "post-edit"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "user-123",
+        "roles": [
+            "post-editor"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/review-campaigns" with payload:
+      """
+      {
+        "id": "quarterly-review",
+        "reviewers": ["authz-user-admin"],
+        "deadline": "2100-01-02T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/review-campaigns/quarterly-review/decisions" with payload:
+      """
+      {
+        "principal_id": "user-123",
+        "role_id": "post-editor",
+        "decision": "revoked",
+        "comment": "left the team"
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    When I send "POST" request to "/v1/check" with payload:
+      """
+      {
+        "checks": [
+          {
+            "principal": "user-123",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit"
+          }
+        ]
+      }
+      """
+    Then the response code should be 200
+    And the response should match json:
+      """
+      {
+        "checks": [
+          {
+            "principal": "user-123",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "action": "edit",
+            "is_allowed": false
+          }
+        ]
+      }
+      """
+
+  Scenario: Decide on a role as a principal which is not a reviewer
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {"id": "post.123", "kind": "post", "value": "123"}
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "post-edit",
+        "resources": [
+            "post.123"
+        ],
+        "actions": ["edit"]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/roles" with payload:
+      """
+      {
+        "id": "post-editor",
+        "policies": [
+            "post-edit"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "user-123",
+        "roles": [
+            "post-editor"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/principals" with payload:
+      """
+      {
+        "id": "reviewer"
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/review-campaigns" with payload:
+      """
+      {
+        "id": "quarterly-review",
+        "reviewers": ["reviewer"],
+        "deadline": "2100-01-02T00:00:00Z"
+      }
+      """
+    And the response code should be 200
+    When I send "POST" request to "/v1/review-campaigns/quarterly-review/decisions" with payload:
+      """
+      {
+        "principal_id": "user-123",
+        "role_id": "post-editor",
+        "decision": "confirmed"
+      }
+      """
+    Then the response code should be 400
+    And the response should match json:
+      """
+      {
+        "error": true,
+        "message": "principal \"authz-user-admin\" is not a reviewer of review campaign \"quarterly-review\""
+      }
+      """
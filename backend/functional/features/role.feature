@@ -36,6 +36,8 @@ Feature: role
         "id": "my-post-123-role",
         "policies": [
           {
+            "effect": "allow",
+            "priority": 0,
             "attribute_rules": null,
             "id": "my-post-123-policy",
             "created_at": "2100-01-01T01:00:00Z",
@@ -101,6 +103,8 @@ Feature: role
         "id": "my-post-role",
         "policies": [
           {
+            "effect": "allow",
+            "priority": 0,
             "attribute_rules": null,
             "id": "my-post-policy-update",
             "created_at": "2100-01-01T01:00:00Z",
@@ -148,6 +152,8 @@ Feature: role
         "id": "my-post-123-role",
         "policies": [
           {
+            "effect": "allow",
+            "priority": 0,
             "attribute_rules": null,
             "id": "my-post-123-policy",
             "updated_at": "2100-01-01T01:00:00Z",
@@ -257,6 +263,8 @@ Feature: role
             "id": "my-post-123-role-create",
             "policies": [
               {
+                "effect": "allow",
+                "priority": 0,
                 "attribute_rules": null,
                 "id": "my-post-123-policy-create",
                 "created_at": "2100-01-01T01:00:00Z",
@@ -270,6 +278,8 @@ Feature: role
             "id": "my-post-123-role-update",
             "policies": [
               {
+                "effect": "allow",
+                "priority": 0,
                 "attribute_rules": null,
                 "id": "my-post-123-policy-update",
                 "created_at": "2100-01-01T01:00:00Z",
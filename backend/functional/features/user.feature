@@ -13,3 +13,83 @@ Feature: user
         "message": "a user cannot delete their own account"
       }
       """
+
+  Scenario: Delete a user along with its compiled policies
+    Given I authenticate with username "admin" and password "changeme"
+    And I send "POST" request to "/v1/users" with payload:
+      """
+      {"username": "john"}
+      """
+    And the response code should be 200
+    And I send "PUT" request to "/v1/principals/authz-user-john" with payload:
+      """
+      {
+        "attributes": [
+          {"key": "team", "value": "blue"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/resources" with payload:
+      """
+      {
+        "id": "post.123",
+        "kind": "post",
+        "value": "123",
+        "attributes": [
+          {"key": "team", "value": "blue"}
+        ]
+      }
+      """
+    And the response code should be 200
+    And I send "POST" request to "/v1/policies" with payload:
+      """
+      {
+        "id": "team-posts",
+        "resources": [
+            "post.*"
+        ],
+        "actions": ["read"],
+        "attribute_rules": [
+            "principal.team == resource.team"
+        ]
+      }
+      """
+    And the response code should be 200
+    And I wait "500ms"
+    And I send "GET" request to "/v1/compiled?filter=principal_id:contains:authz-user-john"
+    And the response code should be 200
+    And the response should match json ignoring "version":
+      """
+      {
+        "data": [
+          {
+            "action_id": "read",
+            "created_at": "2100-01-01T01:00:00Z",
+            "policy_id": "team-posts",
+            "principal_id": "authz-user-john",
+            "resource_kind": "post",
+            "resource_value": "123",
+            "updated_at": "2100-01-01T01:00:00Z"
+          }
+        ],
+        "page": 0,
+        "size": 100,
+        "total": 1
+      }
+      """
+    When I send "DELETE" request to "/v1/users/john"
+    Then the response code should be 200
+    And I send "GET" request to "/v1/principals/authz-user-john"
+    And the response code should be 404
+    And I send "GET" request to "/v1/compiled?filter=principal_id:contains:authz-user-john"
+    And the response code should be 200
+    And the response should match json:
+      """
+      {
+        "data": [],
+        "page": 0,
+        "size": 100,
+        "total": 0
+      }
+      """
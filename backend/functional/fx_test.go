@@ -9,18 +9,23 @@ import (
 	l "log"
 
 	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/agent"
+	"github.com/eko/authz/backend/internal/bundle"
 	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/decision"
 	"github.com/eko/authz/backend/internal/entity"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/internal/fixtures"
+	"github.com/eko/authz/backend/internal/grpc/handler"
 	"github.com/eko/authz/backend/internal/helper/time"
 	"github.com/eko/authz/backend/internal/helper/token"
 	"github.com/eko/authz/backend/internal/http"
 	"github.com/eko/authz/backend/internal/log"
 	"github.com/eko/authz/backend/internal/oauth"
 	"github.com/eko/authz/backend/internal/observability"
+	"github.com/eko/authz/backend/internal/scim"
 	"github.com/eko/authz/backend/internal/security"
 	"github.com/eko/authz/backend/internal/stats"
 	"go.uber.org/fx"
@@ -33,14 +38,17 @@ func FxApp() *fx.App {
 		fx.NopLogger,
 		fx.Provide(context.Background),
 
+		bundle.FxModule(),
 		compile.FxModule(),
 		database.FxModule(),
+		decision.FxModule(),
 		entity.FxModule(),
 		event.FxModule(),
 		http.FxModule(),
 		log.FxModule(),
 		oauth.FxModule(),
 		observability.FxModule(),
+		scim.FxModule(),
 		security.FxModule(),
 		stats.FxModule(),
 
@@ -63,19 +71,29 @@ func FxApp() *fx.App {
 			token.NewGenerator,
 		),
 
+		// Agent gRPC server, answering checks from the server database.
+		fx.Provide(
+			agent.NewClient,
+			agent.NewServer,
+			handler.NewCheck,
+		),
+
 		fx.Provide(
 			configs.Load,
+			func(cfg *configs.Base) *configs.Agent { return cfg.Agent },
 			func(cfg *configs.Base) *configs.App {
 				return cfg.App
 			},
 			func(cfg *configs.Base) *configs.Auth { return cfg.Auth },
 			func(cfg *configs.Base) *configs.Database { return cfg.Database },
+			func(cfg *configs.Base) *configs.GRPCServer { return cfg.GRPCServer },
 			func(cfg *configs.Base) *configs.HTTPServer { return cfg.HTTPServer },
 			func(cfg *configs.Base) *configs.Logger {
 				cfg.Logger.Level = "ERROR"
 				return cfg.Logger
 			},
 			func(cfg *configs.Base) *configs.OAuth { return cfg.OAuth },
+			func(cfg *configs.Base) *configs.SCIM { return cfg.SCIM },
 			func(cfg *configs.Base) *configs.User { return cfg.User },
 		),
 
@@ -84,6 +102,7 @@ func FxApp() *fx.App {
 		),
 
 		fx.Invoke(http.Run),
+		fx.Invoke(agent.Run),
 		fx.Invoke(func(initializer fixtures.Initializer) {
 			if err := initializer.Initialize(); err != nil {
 				l.Fatalf("Cannot initialize fixtures: %v\n", err)
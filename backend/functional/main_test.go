@@ -60,9 +60,21 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 		httpClient: &lib_http.Client{},
 	}
 
+	agent := &agentFeature{api: api}
+
 	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
 		if err := db.Exec(`TRUNCATE TABLE
+		authz_compile_jobs,
 		authz_compiled_policies,
+		authz_compiled_versions,
+		authz_delegations_actions,
+		authz_delegations_resources,
+		authz_delegations,
+		authz_cedar_policies,
+		authz_resource_kinds_actions,
+		authz_resource_kinds,
+		authz_review_items,
+		authz_review_campaigns,
 		authz_roles_policies,
 		authz_roles,
 		authz_principals_roles,
@@ -84,6 +96,8 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 			l.Fatalf("Cannot reset api: %v\n", err)
 		}
 
+		agent.reset()
+
 		if err := initializer.Initialize(); err != nil {
 			l.Fatalf("Cannot initialize fixtures: %v\n", err)
 		}
@@ -107,6 +121,11 @@ func InitializeScenario(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I authenticate with username "([^"]*)" and password "([^"]*)"$`, api.iAuthenticateWithUsernameAndPassword)
 	ctx.Step(`^I send "(GET|POST|PUT|DELETE)" request to "([^"]*)"$`, api.iSendRequestTo)
 	ctx.Step(`^I send "(GET|POST|PUT|DELETE)" request to "([^"]*)" with payload:$`, api.iSendRequestToWithPayload)
+	ctx.Step(`^I send "(GET|POST|PUT|DELETE)" request to "([^"]*)" with consistency token and payload:$`, api.iSendRequestToWithConsistencyTokenAndPayload)
 	ctx.Step(`^the response code should be (\d+)$`, api.theResponseCodeShouldBe)
 	ctx.Step(`^the response should match json:$`, api.theResponseShouldMatchJSON)
+	ctx.Step(`^the response should match json ignoring "([^"]*)":$`, api.theResponseShouldMatchJSONIgnoring)
+	ctx.Step(`^I send a check gRPC request to the agent$`, agent.iSendACheckRequestToTheAgent)
+	ctx.Step(`^I send a check gRPC request to the agent without authentication$`, agent.iSendACheckRequestToTheAgentWithoutAuthentication)
+	ctx.Step(`^the gRPC status code should be "([^"]*)"$`, agent.theGRPCStatusCodeShouldBe)
 }
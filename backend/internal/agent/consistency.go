@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
+)
+
+const consistencyPollDelay = 20 * lib_time.Millisecond
+
+// consistency waits for the decision data to be synchronized from the server
+// until consistency tokens issued by the server.
+type consistency struct {
+	timeout lib_time.Duration
+
+	mutex       sync.RWMutex
+	syncedUntil int64
+}
+
+func NewConsistency(cfg *configs.App) *consistency {
+	return &consistency{
+		timeout: cfg.ConsistencyTimeout,
+	}
+}
+
+// Revision returns the revision until which changes are synchronized from
+// the server.
+func (c *consistency) Revision() (int64, error) {
+	return c.synced(), nil
+}
+
+// Wait blocks until every change covered by the given token is synchronized.
+// It returns compile.ErrCompilationPending when it takes longer than the
+// configured timeout, for instance while the server is unreachable.
+func (c *consistency) Wait(ctx context.Context, token string) error {
+	_, changedUntil, err := compile.ParseConsistencyToken(token)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ticker := lib_time.NewTicker(consistencyPollDelay)
+	defer ticker.Stop()
+
+	for {
+		if c.synced() >= changedUntil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return compile.ErrCompilationPending
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *consistency) synced() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.syncedUntil
+}
+
+// setSynced records that every change covered by the token is synchronized.
+func (c *consistency) setSynced(token string) error {
+	_, syncedUntil, err := compile.ParseConsistencyToken(token)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if syncedUntil > c.syncedUntil {
+		c.syncedUntil = syncedUntil
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/stretchr/testify/assert"
+)
+
+func newToken(revision int64) string {
+	return compile.FormatConsistencyToken(revision, revision)
+}
+
+func TestConsistency_Wait(t *testing.T) {
+	// Given
+	consistency := NewConsistency(&configs.App{ConsistencyTimeout: time.Second})
+
+	token := newToken(10)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = consistency.setSynced(newToken(11))
+	}()
+
+	// When
+	err := consistency.Wait(context.Background(), token)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(int64(11), consistency.synced())
+}
+
+func TestConsistency_Wait_WhenNotSynced(t *testing.T) {
+	// Given
+	consistency := NewConsistency(&configs.App{ConsistencyTimeout: 50 * time.Millisecond})
+
+	assert.Nil(t, consistency.setSynced(newToken(10)))
+
+	// When
+	err := consistency.Wait(context.Background(), newToken(11))
+
+	// Then
+	assert.ErrorIs(t, err, compile.ErrCompilationPending)
+}
+
+func TestConsistency_SetSynced_WhenOlderToken(t *testing.T) {
+	// Given
+	consistency := NewConsistency(&configs.App{ConsistencyTimeout: time.Second})
+
+	assert.Nil(t, consistency.setSynced(newToken(10)))
+
+	// When
+	err := consistency.setSynced(newToken(9))
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(int64(10), consistency.synced())
+}
@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/grpc/handler"
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("agent",
+		fx.Provide(
+			handler.NewCheck,
+
+			NewClient,
+			NewConsistency,
+			NewServer,
+			NewStore,
+			NewSyncer,
+
+			func(consistency *consistency) compile.Consistency { return consistency },
+		),
+		fx.Invoke(
+			Run,
+			RunSyncer,
+		),
+	)
+}
+
+// LocalDatabase replaces the configured database by an in-memory one, in
+// which the decision data synchronized from the server is kept.
+func LocalDatabase(*configs.Database) *configs.Database {
+	return &configs.Database{
+		Driver:   configs.DriverSqlite,
+		Dbname:   ":memory:",
+		InMemory: true,
+	}
+}
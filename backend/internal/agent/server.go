@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"net"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/grpc/handler"
+	"github.com/eko/authz/backend/internal/grpc/interceptor"
+	"github.com/eko/authz/backend/internal/security/jwt"
+	"github.com/eko/authz/backend/pkg/authz"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server answers checks from the local decision data. Calls are authenticated
+// with the access tokens issued by the server, authentication requests being
+// forwarded to it. Other methods of the API are not implemented by agents.
+type Server struct {
+	authz.UnimplementedApiServer
+
+	client       authz.ApiClient
+	checkHandler handler.Check
+
+	addr       string
+	GrpcServer *grpc.Server
+}
+
+func NewServer(
+	cfg *configs.Agent,
+	tokenManager jwt.Manager,
+	client authz.ApiClient,
+	checkHandler handler.Check,
+) *Server {
+	server := &Server{
+		addr:         cfg.ListenAddr,
+		client:       client,
+		checkHandler: checkHandler,
+	}
+
+	authenticateFunc := interceptor.AuthenticateFunc(tokenManager)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainStreamInterceptor(
+			otelgrpc.StreamServerInterceptor(), // nolint:staticcheck
+			grpc_auth.StreamServerInterceptor(authenticateFunc),
+		),
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(), // nolint:staticcheck
+			authenticationUnaryServerInterceptor(
+				grpc_auth.UnaryServerInterceptor(authenticateFunc),
+			),
+		),
+	)
+
+	authz.RegisterApiServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	server.GrpcServer = grpcServer
+
+	return server
+}
+
+// Authenticate forwards the authentication request to the server, which issues
+// the access token.
+func (s *Server) Authenticate(ctx context.Context, req *authz.AuthenticateRequest) (*authz.AuthenticateResponse, error) {
+	return s.client.Authenticate(ctx, req)
+}
+
+func (s *Server) Check(ctx context.Context, req *authz.CheckRequest) (*authz.CheckResponse, error) {
+	return s.checkHandler.Check(ctx, req)
+}
+
+func (s *Server) CheckStream(stream authz.Api_CheckStreamServer) error {
+	return s.checkHandler.CheckStream(stream)
+}
+
+// authenticationUnaryServerInterceptor authenticates all the unary calls but
+// authentication requests. Unlike the server, checks are not answered publicly.
+func authenticationUnaryServerInterceptor(authenticate grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == "/authz.Api/Authenticate" {
+			return handler(ctx, req)
+		}
+
+		return authenticate(ctx, req, info, handler)
+	}
+}
+
+func Run(lc fx.Lifecycle, logger *slog.Logger, server *Server) error {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			listener, err := net.Listen("tcp", server.addr)
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Starting agent gRPC server", slog.String("addr", server.addr))
+
+			go func() {
+				if err := server.GrpcServer.Serve(listener); err != nil {
+					logger.Error("Unable to start agent gRPC server", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("Stopping agent gRPC server")
+
+			server.GrpcServer.GracefulStop()
+			return nil
+		},
+	})
+
+	return nil
+}
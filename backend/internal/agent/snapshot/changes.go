@@ -0,0 +1,70 @@
+package snapshot
+
+import (
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+)
+
+// Changes are the data changed since the previous synchronization of an
+// agent, read from the change log.
+type Changes struct {
+	// All is set when all the data has to be loaded again.
+	All bool
+
+	CedarPolicies bool
+	Delegations   bool
+	ResourceKinds bool
+	Roles         bool
+
+	// Policies, principals and resources are changed along with their
+	// compiled policies, by identifier.
+	Policies   map[string]bool
+	Principals map[string]bool
+	Resources  map[string]bool
+}
+
+// NewChanges returns the data changed by the given change log entries.
+func NewChanges(changes []*model.Change) *Changes {
+	result := &Changes{
+		Policies:   map[string]bool{},
+		Principals: map[string]bool{},
+		Resources:  map[string]bool{},
+	}
+
+	for _, change := range changes {
+		switch change.ItemType {
+		case model.ChangeItemTypeCompiled:
+			result.All = true
+		case string(event.EventTypeCedarPolicy):
+			result.CedarPolicies = true
+		case string(event.EventTypeDelegation):
+			result.Delegations = true
+		case string(event.EventTypeResourceKind):
+			result.ResourceKinds = true
+		case string(event.EventTypeRole):
+			result.Roles = true
+		// Compilations are recorded under the type of their target, which is
+		// the type of the events about it.
+		case string(event.EventTypePolicy):
+			result.Policies[change.ItemID] = true
+		case string(event.EventTypePrincipal):
+			result.Principals[change.ItemID] = true
+		case string(event.EventTypeResource):
+			result.Resources[change.ItemID] = true
+		}
+	}
+
+	return result
+}
+
+// IsEmpty returns whether no data changed.
+func (c *Changes) IsEmpty() bool {
+	return !c.All &&
+		!c.CedarPolicies &&
+		!c.Delegations &&
+		!c.ResourceKinds &&
+		!c.Roles &&
+		len(c.Policies) == 0 &&
+		len(c.Principals) == 0 &&
+		len(c.Resources) == 0
+}
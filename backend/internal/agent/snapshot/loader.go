@@ -0,0 +1,627 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/entity/transformer"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/pkg/authz"
+)
+
+// Loader loads the data needed to decide checks on some resource kinds.
+type Loader interface {
+	Load(resourceKinds []string) (*authz.AgentData, error)
+	LoadChanges(resourceKinds []string, changes *Changes, state *State) (*authz.AgentData, *authz.AgentData, error)
+}
+
+type loader struct {
+	clock               time.Clock
+	compiledManager     manager.CompiledPolicy
+	cedarPolicyManager  manager.CedarPolicy
+	delegationManager   manager.Delegation
+	policyManager       manager.Policy
+	principalManager    manager.Principal
+	resourceManager     manager.Resource
+	resourceKindManager manager.ResourceKind
+	roleManager         manager.Role
+}
+
+func NewLoader(
+	clock time.Clock,
+	compiledManager manager.CompiledPolicy,
+	cedarPolicyManager manager.CedarPolicy,
+	delegationManager manager.Delegation,
+	policyManager manager.Policy,
+	principalManager manager.Principal,
+	resourceManager manager.Resource,
+	resourceKindManager manager.ResourceKind,
+	roleManager manager.Role,
+) Loader {
+	return &loader{
+		clock:               clock,
+		compiledManager:     compiledManager,
+		cedarPolicyManager:  cedarPolicyManager,
+		delegationManager:   delegationManager,
+		policyManager:       policyManager,
+		principalManager:    principalManager,
+		resourceManager:     resourceManager,
+		resourceKindManager: resourceKindManager,
+		roleManager:         roleManager,
+	}
+}
+
+// Load returns the compiled policies of the resource kinds along with the
+// policies, roles, principals, resources, resource kinds, Cedar policies and
+// active delegations checks on them depend on. Roles only keep the policies
+// applying to the resource kinds and principals only keep these roles.
+func (l *loader) Load(resourceKinds []string) (*authz.AgentData, error) {
+	var (
+		data = &authz.AgentData{}
+		err  error
+	)
+
+	data.CompiledPolicies, err = l.loadCompiledPolicies(map[string]repository.FieldValue{
+		"resource_kind": {Operator: "IN", Value: resourceKinds},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var policyIDs = map[string]bool{}
+	for _, compiledPolicy := range data.CompiledPolicies {
+		policyIDs[compiledPolicy.GetPolicyId()] = true
+	}
+
+	if data.Policies, err = l.loadPolicies(policyIDs); err != nil {
+		return nil, err
+	}
+
+	if data.Roles, err = l.loadRoles(policyIDs); err != nil {
+		return nil, err
+	}
+
+	if data.Principals, err = l.loadPrincipals(nil, roleSet(data.Roles)); err != nil {
+		return nil, err
+	}
+
+	if data.Resources, err = l.loadResources(resourceKinds, nil); err != nil {
+		return nil, err
+	}
+
+	if data.ResourceKinds, err = l.loadResourceKinds(resourceKinds); err != nil {
+		return nil, err
+	}
+
+	if data.CedarPolicies, err = l.loadCedarPolicies(resourceKinds); err != nil {
+		return nil, err
+	}
+
+	if data.Delegations, err = l.loadDelegations(resourceKinds); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// LoadChanges loads the data of the resource kinds the given changes apply
+// to, updates the state of the agent with it and returns the data upserted
+// and deleted. Data not depending on the changes is not loaded again.
+func (l *loader) LoadChanges(resourceKinds []string, changes *Changes, state *State) (*authz.AgentData, *authz.AgentData, error) {
+	if changes.All {
+		data, err := l.Load(resourceKinds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted, deleted := state.Replace(data)
+
+		return upserted, deleted, nil
+	}
+
+	var upserted, deleted = &authz.AgentData{}, &authz.AgentData{}
+
+	resources, err := l.loadResources(resourceKinds, changes.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Compiled policies of changed resources, including the ones deleted or
+	// moved to another value.
+	var resourceValues = map[string]bool{}
+
+	for _, resource := range resources {
+		resourceValues[resourceKey(resource.GetKind(), resource.GetValue())] = true
+	}
+
+	for resourceID := range changes.Resources {
+		if resource, ok := state.resources.items[resourceID]; ok {
+			resourceValues[resourceKey(resource.GetKind(), resource.GetValue())] = true
+		}
+	}
+
+	compiledPolicies, err := l.loadChangedCompiledPolicies(resourceKinds, changes, resourceValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var transitioned map[string]bool
+
+	upserted.CompiledPolicies, deleted.CompiledPolicies, transitioned = state.replaceCompiledPolicies(
+		func(compiledPolicy *authz.AgentCompiledPolicy) bool {
+			return changes.Policies[compiledPolicy.GetPolicyId()] ||
+				changes.Principals[compiledPolicy.GetPrincipalId()] ||
+				resourceValues[resourceKey(compiledPolicy.GetResourceKind(), compiledPolicy.GetResourceValue())]
+		},
+		compiledPolicies,
+	)
+
+	// Policies changed, along with the ones that gained or lost compiled
+	// policies: only the policies having compiled policies are sent.
+	var policyIDs, referencedIDs = map[string]bool{}, map[string]bool{}
+
+	for policyID := range changes.Policies {
+		policyIDs[policyID] = true
+	}
+
+	for policyID := range transitioned {
+		policyIDs[policyID] = true
+	}
+
+	for policyID := range policyIDs {
+		if state.isReferenced(policyID) {
+			referencedIDs[policyID] = true
+		}
+	}
+
+	policies, err := l.loadPolicies(referencedIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	upserted.Policies, deleted.Policies = state.policies.replace(func(policy *authz.AgentPolicy) bool {
+		return policyIDs[policy.GetId()]
+	}, policies)
+
+	// Roles only keep the policies sent, so they are loaded again when these
+	// policies change.
+	var principalsChanged = false
+
+	if changes.Roles || len(transitioned) > 0 {
+		roles, err := l.loadRoles(state.referencedPolicies())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var previousRoleIDs = map[string]bool{}
+		for roleID := range state.roles.items {
+			previousRoleIDs[roleID] = true
+		}
+
+		upserted.Roles, deleted.Roles = state.roles.replace(all[*authz.Role], roles)
+
+		// Principals only keep the roles sent.
+		for _, role := range upserted.Roles {
+			principalsChanged = principalsChanged || !previousRoleIDs[role.GetId()]
+		}
+
+		principalsChanged = principalsChanged || len(deleted.Roles) > 0
+	}
+
+	var roleIDs = map[string]bool{}
+	for roleID := range state.roles.items {
+		roleIDs[roleID] = true
+	}
+
+	if principalsChanged {
+		principals, err := l.loadPrincipals(nil, roleIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted.Principals, deleted.Principals = state.principals.replace(all[*authz.Principal], principals)
+	} else if len(changes.Principals) > 0 {
+		principals, err := l.loadPrincipals(changes.Principals, roleIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted.Principals, deleted.Principals = state.principals.replace(func(principal *authz.Principal) bool {
+			return changes.Principals[principal.GetId()]
+		}, principals)
+	}
+
+	upserted.Resources, deleted.Resources = state.resources.replace(func(resource *authz.Resource) bool {
+		return changes.Resources[resource.GetId()]
+	}, resources)
+
+	if changes.ResourceKinds {
+		resourceKindsData, err := l.loadResourceKinds(resourceKinds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted.ResourceKinds, deleted.ResourceKinds = state.resourceKinds.replace(all[*authz.AgentResourceKind], resourceKindsData)
+	}
+
+	if changes.CedarPolicies {
+		cedarPolicies, err := l.loadCedarPolicies(resourceKinds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted.CedarPolicies, deleted.CedarPolicies = state.cedarPolicies.replace(all[*authz.AgentCedarPolicy], cedarPolicies)
+	}
+
+	// Delegations embed their resources and are deleted along with their
+	// principals or resources, without a delegation change being recorded.
+	if changes.Delegations || state.delegationsDependOn(changes.Principals, changes.Resources) {
+		delegations, err := l.loadDelegations(resourceKinds)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		upserted.Delegations, deleted.Delegations = state.delegations.replace(all[*authz.AgentDelegation], delegations)
+	}
+
+	return upserted, deleted, nil
+}
+
+func (l *loader) loadCompiledPolicies(filter map[string]repository.FieldValue) ([]*authz.AgentCompiledPolicy, error) {
+	compiledPolicies, _, err := l.compiledManager.GetRepository().Find(
+		repository.WithFilter(filter),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve compiled policies: %v", err)
+	}
+
+	var result = make([]*authz.AgentCompiledPolicy, 0, len(compiledPolicies))
+	for _, compiledPolicy := range compiledPolicies {
+		result = append(result, transformer.NewAgentCompiledPolicy(compiledPolicy).ToProto())
+	}
+
+	return result, nil
+}
+
+// loadChangedCompiledPolicies loads the compiled policies of the resource
+// kinds for the changed policies, principals and resource values.
+func (l *loader) loadChangedCompiledPolicies(
+	resourceKinds []string,
+	changes *Changes,
+	resourceValues map[string]bool,
+) ([]*authz.AgentCompiledPolicy, error) {
+	var filters = make([]map[string]repository.FieldValue, 0)
+
+	if len(changes.Policies) > 0 {
+		filters = append(filters, map[string]repository.FieldValue{
+			"resource_kind": {Operator: "IN", Value: resourceKinds},
+			"policy_id":     {Operator: "IN", Value: keys(changes.Policies)},
+		})
+	}
+
+	if len(changes.Principals) > 0 {
+		filters = append(filters, map[string]repository.FieldValue{
+			"resource_kind": {Operator: "IN", Value: resourceKinds},
+			"principal_id":  {Operator: "IN", Value: keys(changes.Principals)},
+		})
+	}
+
+	var valuesByKind = map[string][]string{}
+	for key := range resourceValues {
+		kind, value, _ := strings.Cut(key, "\x00")
+		valuesByKind[kind] = append(valuesByKind[kind], value)
+	}
+
+	for _, kind := range resourceKinds {
+		if values, ok := valuesByKind[kind]; ok {
+			filters = append(filters, map[string]repository.FieldValue{
+				"resource_kind":  {Operator: "=", Value: kind},
+				"resource_value": {Operator: "IN", Value: values},
+			})
+		}
+	}
+
+	var (
+		result = make([]*authz.AgentCompiledPolicy, 0)
+		seen   = map[string]bool{}
+	)
+
+	for _, filter := range filters {
+		compiledPolicies, err := l.loadCompiledPolicies(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, compiledPolicy := range compiledPolicies {
+			if key := CompiledPolicyKey(compiledPolicy); !seen[key] {
+				seen[key] = true
+				result = append(result, compiledPolicy)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *loader) loadPolicies(policyIDs map[string]bool) ([]*authz.AgentPolicy, error) {
+	if len(policyIDs) == 0 {
+		return nil, nil
+	}
+
+	policies, _, err := l.policyManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "IN", Value: keys(policyIDs)},
+		}),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve policies: %v", err)
+	}
+
+	var result = make([]*authz.AgentPolicy, 0, len(policies))
+	for _, policy := range policies {
+		result = append(result, transformer.NewAgentPolicy(policy).ToProto())
+	}
+
+	return result, nil
+}
+
+// loadRoles loads the roles having some of the given policies, only keeping
+// these policies.
+func (l *loader) loadRoles(policyIDs map[string]bool) ([]*authz.Role, error) {
+	roles, _, err := l.roleManager.GetRepository().Find(
+		repository.WithPreloads("Policies"),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve roles: %v", err)
+	}
+
+	var result = make([]*authz.Role, 0)
+
+	for _, role := range roles {
+		var policies = []string{}
+		for _, policy := range role.Policies {
+			if policyIDs[policy.ID] {
+				policies = append(policies, policy.ID)
+			}
+		}
+
+		if len(policies) == 0 {
+			continue
+		}
+
+		sort.Strings(policies)
+
+		result = append(result, &authz.Role{Id: role.ID, Policies: policies})
+	}
+
+	return result, nil
+}
+
+// loadPrincipals loads the given principals, or all the principals when nil:
+// a check fails when its principal does not exist, even when no policy
+// applies to it. Principals only keep the given roles.
+func (l *loader) loadPrincipals(principalIDs map[string]bool, roleIDs map[string]bool) ([]*authz.Principal, error) {
+	var options = []repository.QueryOption{
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	}
+
+	if principalIDs != nil {
+		options = append(options, repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "IN", Value: keys(principalIDs)},
+		}))
+	}
+
+	principals, _, err := l.principalManager.GetRepository().Find(options...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	var result = make([]*authz.Principal, 0, len(principals))
+
+	for _, principal := range principals {
+		var roles = []string{}
+		for _, role := range principal.Roles {
+			if roleIDs[role.ID] {
+				roles = append(roles, role.ID)
+			}
+		}
+
+		sort.Strings(roles)
+
+		result = append(result, &authz.Principal{
+			Id:         principal.ID,
+			Roles:      roles,
+			Attributes: sortedAttributes(principal.Attributes),
+		})
+	}
+
+	return result, nil
+}
+
+// loadResources loads the resources of the resource kinds, only the given
+// ones when not nil.
+func (l *loader) loadResources(resourceKinds []string, resourceIDs map[string]bool) ([]*authz.Resource, error) {
+	var filter = map[string]repository.FieldValue{
+		"kind": {Operator: "IN", Value: resourceKinds},
+	}
+
+	if resourceIDs != nil {
+		if len(resourceIDs) == 0 {
+			return nil, nil
+		}
+
+		filter["id"] = repository.FieldValue{Operator: "IN", Value: keys(resourceIDs)}
+	}
+
+	resources, _, err := l.resourceManager.GetRepository().Find(
+		repository.WithFilter(filter),
+		repository.WithPreloads("Attributes"),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resources: %v", err)
+	}
+
+	var result = make([]*authz.Resource, 0, len(resources))
+
+	for _, resource := range resources {
+		result = append(result, &authz.Resource{
+			Id:         resource.ID,
+			Kind:       resource.Kind,
+			Value:      resource.Value,
+			Attributes: sortedAttributes(resource.Attributes),
+		})
+	}
+
+	return result, nil
+}
+
+func (l *loader) loadResourceKinds(resourceKinds []string) ([]*authz.AgentResourceKind, error) {
+	kinds, _, err := l.resourceKindManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "IN", Value: resourceKinds},
+		}),
+		repository.WithPreloads("Actions"),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource kinds: %v", err)
+	}
+
+	var result = make([]*authz.AgentResourceKind, 0, len(kinds))
+
+	for _, kind := range kinds {
+		resourceKind := transformer.NewAgentResourceKind(kind).ToProto()
+		sort.Strings(resourceKind.Actions)
+
+		result = append(result, resourceKind)
+	}
+
+	return result, nil
+}
+
+// loadCedarPolicies loads the Cedar policies of the resource kinds and the
+// ones applying to all resource kinds.
+func (l *loader) loadCedarPolicies(resourceKinds []string) ([]*authz.AgentCedarPolicy, error) {
+	cedarPolicies, _, err := l.cedarPolicyManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"resource_kind": {Operator: "IN", Value: append([]string{""}, resourceKinds...)},
+		}),
+		repository.WithSort("id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve cedar policies: %v", err)
+	}
+
+	var result = make([]*authz.AgentCedarPolicy, 0, len(cedarPolicies))
+	for _, cedarPolicy := range cedarPolicies {
+		result = append(result, transformer.NewAgentCedarPolicy(cedarPolicy).ToProto())
+	}
+
+	return result, nil
+}
+
+// loadDelegations loads the active delegations on resources of the resource
+// kinds, only keeping these resources.
+func (l *loader) loadDelegations(resourceKinds []string) ([]*authz.AgentDelegation, error) {
+	delegations, _, err := l.delegationManager.GetRepository().Find(
+		repository.WithJoin(
+			"INNER JOIN authz_delegations_resources ON authz_delegations_resources.delegation_id = authz_delegations.id",
+			"INNER JOIN authz_resources ON authz_resources.id = authz_delegations_resources.resource_id",
+		),
+		repository.WithFilter(map[string]repository.FieldValue{
+			"authz_delegations.expires_at": {Operator: ">", Value: l.clock.Now()},
+			"authz_resources.kind":         {Operator: "IN", Value: resourceKinds},
+		}),
+		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSort("authz_delegations.id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve delegations: %v", err)
+	}
+
+	var (
+		kinds  = map[string]bool{}
+		seen   = map[string]bool{}
+		result = make([]*authz.AgentDelegation, 0)
+	)
+
+	for _, resourceKind := range resourceKinds {
+		kinds[resourceKind] = true
+	}
+
+	for _, delegation := range delegations {
+		if seen[delegation.ID] {
+			continue
+		}
+
+		seen[delegation.ID] = true
+
+		var resources = make([]*model.Resource, 0, len(delegation.Resources))
+		for _, resource := range delegation.Resources {
+			if kinds[resource.Kind] {
+				resources = append(resources, resource)
+			}
+		}
+
+		sort.Slice(resources, func(i, j int) bool { return resources[i].ID < resources[j].ID })
+		sort.Slice(delegation.Actions, func(i, j int) bool { return delegation.Actions[i].ID < delegation.Actions[j].ID })
+
+		delegation.Resources = resources
+
+		result = append(result, transformer.NewAgentDelegation(delegation).ToProto())
+	}
+
+	return result, nil
+}
+
+func sortedAttributes(attributes model.Attributes) []*authz.Attribute {
+	result := transformer.NewAttributes(attributes).ToProto()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+
+		return result[i].Value < result[j].Value
+	})
+
+	return result
+}
+
+func roleSet(roles []*authz.Role) map[string]bool {
+	var result = make(map[string]bool, len(roles))
+	for _, role := range roles {
+		result[role.GetId()] = true
+	}
+
+	return result
+}
+
+func resourceKey(kind string, value string) string {
+	return kind + "\x00" + value
+}
+
+func keys(values map[string]bool) []string {
+	var result = make([]string, 0, len(values))
+	for value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
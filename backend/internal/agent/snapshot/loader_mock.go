@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/agent/snapshot/loader.go
+
+// Package snapshot is a generated GoMock package.
+package snapshot
+
+import (
+	reflect "reflect"
+
+	authz "github.com/eko/authz/backend/pkg/authz"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoader is a mock of Loader interface.
+type MockLoader struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoaderMockRecorder
+}
+
+// MockLoaderMockRecorder is the mock recorder for MockLoader.
+type MockLoaderMockRecorder struct {
+	mock *MockLoader
+}
+
+// NewMockLoader creates a new mock instance.
+func NewMockLoader(ctrl *gomock.Controller) *MockLoader {
+	mock := &MockLoader{ctrl: ctrl}
+	mock.recorder = &MockLoaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoader) EXPECT() *MockLoaderMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockLoader) Load(resourceKinds []string) (*authz.AgentData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", resourceKinds)
+	ret0, _ := ret[0].(*authz.AgentData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockLoaderMockRecorder) Load(resourceKinds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockLoader)(nil).Load), resourceKinds)
+}
+
+// LoadChanges mocks base method.
+func (m *MockLoader) LoadChanges(resourceKinds []string, changes *Changes, state *State) (*authz.AgentData, *authz.AgentData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadChanges", resourceKinds, changes, state)
+	ret0, _ := ret[0].(*authz.AgentData)
+	ret1, _ := ret[1].(*authz.AgentData)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LoadChanges indicates an expected call of LoadChanges.
+func (mr *MockLoaderMockRecorder) LoadChanges(resourceKinds, changes, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadChanges", reflect.TypeOf((*MockLoader)(nil).LoadChanges), resourceKinds, changes, state)
+}
@@ -0,0 +1,254 @@
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+type loaderDependencies struct {
+	fx.In
+
+	CompiledManager  manager.CompiledPolicy
+	PolicyManager    manager.Policy
+	PrincipalManager manager.Principal
+	ResourceManager  manager.Resource
+	RoleManager      manager.Role
+}
+
+// newDatabaseLoader returns a loader on a sqlite database having a "readers"
+// role with "posts-readers" and "post-1-readers" policies, only the first one
+// being compiled, an "alice" principal having the role, a "bob" principal and
+// "post.1" and "post.2" resources.
+func newDatabaseLoader(t *testing.T) (Loader, *loaderDependencies) {
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_NAME", filepath.Join(t.TempDir(), "authz.db"))
+	t.Setenv("LOGGER_LEVEL", "ERROR")
+
+	var (
+		loaderInstance Loader
+		deps           loaderDependencies
+	)
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(context.Background),
+		configs.FxModule(),
+		database.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		log.FxModule(),
+		fx.Provide(NewLoader),
+		fx.Populate(&loaderInstance, &deps),
+	)
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("unable to start application: %v", err)
+	}
+
+	t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+	assert := assert.New(t)
+
+	policies := []*model.Policy{
+		{ID: "posts-readers", Effect: model.PolicyEffectAllow},
+		{ID: "post-1-readers", Effect: model.PolicyEffectAllow},
+	}
+
+	for _, policy := range policies {
+		assert.Nil(deps.PolicyManager.GetRepository().Create(policy))
+	}
+
+	assert.Nil(deps.RoleManager.GetRepository().Create(&model.Role{ID: "readers", Policies: policies}))
+
+	assert.Nil(deps.PrincipalManager.GetRepository().Create(&model.Principal{ID: "alice", Roles: []*model.Role{{ID: "readers"}}}))
+	assert.Nil(deps.PrincipalManager.GetRepository().Create(&model.Principal{ID: "bob"}))
+
+	assert.Nil(deps.ResourceManager.GetRepository().Create(&model.Resource{ID: "post.1", Kind: "post", Value: "1"}))
+	assert.Nil(deps.ResourceManager.GetRepository().Create(&model.Resource{ID: "post.2", Kind: "post", Value: "2"}))
+
+	assert.Nil(deps.CompiledManager.Create([]*model.CompiledPolicy{
+		{PolicyID: "posts-readers", ResourceKind: "post", ResourceValue: "*", ActionID: "read"},
+	}))
+
+	return loaderInstance, &deps
+}
+
+func identifiers[T interface{ GetId() string }](items []T) []string {
+	var result = make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.GetId())
+	}
+
+	return result
+}
+
+func TestLoader_Load(t *testing.T) {
+	// Given
+	loaderInstance, _ := newDatabaseLoader(t)
+
+	// When
+	data, err := loaderInstance.Load([]string{"post"})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	assert.Len(data.CompiledPolicies, 1)
+	assert.Equal([]string{"posts-readers"}, identifiers(data.Policies))
+	assert.Equal([]string{"readers"}, identifiers(data.Roles))
+	assert.Equal([]string{"posts-readers"}, data.Roles[0].Policies)
+	assert.Equal([]string{"alice", "bob"}, identifiers(data.Principals))
+	assert.Equal([]string{"readers"}, data.Principals[0].Roles)
+	assert.Equal([]string{"post.1", "post.2"}, identifiers(data.Resources))
+}
+
+func TestLoader_LoadChanges_WhenPolicyIsCompiled(t *testing.T) {
+	// Given
+	loaderInstance, deps := newDatabaseLoader(t)
+
+	assert := assert.New(t)
+
+	data, err := loaderInstance.Load([]string{"post"})
+	assert.Nil(err)
+
+	state := NewState(data)
+
+	assert.Nil(deps.CompiledManager.Create([]*model.CompiledPolicy{
+		{PolicyID: "post-1-readers", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	}))
+
+	// When
+	upserted, deleted, err := loaderInstance.LoadChanges([]string{"post"}, NewChanges([]*model.Change{
+		{ItemType: "policy", ItemID: "post-1-readers"},
+	}), state)
+
+	// Then
+	assert.Nil(err)
+
+	assert.Len(upserted.CompiledPolicies, 1)
+	assert.Equal([]string{"post-1-readers"}, identifiers(upserted.Policies))
+	assert.Equal([]string{"readers"}, identifiers(upserted.Roles))
+	assert.Equal([]string{"post-1-readers", "posts-readers"}, upserted.Roles[0].Policies)
+
+	// Principals keep the same roles.
+	assert.Empty(upserted.Principals)
+	assert.True(IsEmpty(deleted))
+}
+
+func TestLoader_LoadChanges_WhenResourceIsDeleted(t *testing.T) {
+	// Given
+	loaderInstance, deps := newDatabaseLoader(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(deps.CompiledManager.Create([]*model.CompiledPolicy{
+		{PolicyID: "post-1-readers", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	}))
+
+	data, err := loaderInstance.Load([]string{"post"})
+	assert.Nil(err)
+
+	state := NewState(data)
+
+	assert.Nil(deps.ResourceManager.GetRepository().Delete(&model.Resource{ID: "post.1"}))
+	assert.Nil(deps.CompiledManager.GetRepository().DB().
+		Where("resource_kind = ? AND resource_value = ?", "post", "1").
+		Delete(&model.CompiledPolicy{}).Error)
+
+	// When
+	upserted, deleted, err := loaderInstance.LoadChanges([]string{"post"}, NewChanges([]*model.Change{
+		{ItemType: "resource", ItemID: "post.1"},
+	}), state)
+
+	// Then
+	assert.Nil(err)
+
+	assert.Equal([]string{"post.1"}, identifiers(deleted.Resources))
+	assert.Len(deleted.CompiledPolicies, 1)
+	assert.Equal([]string{"post-1-readers"}, identifiers(deleted.Policies))
+	assert.Equal([]string{"readers"}, identifiers(upserted.Roles))
+	assert.Equal([]string{"posts-readers"}, upserted.Roles[0].Policies)
+
+	assert.Empty(upserted.CompiledPolicies)
+	assert.Empty(upserted.Principals)
+}
+
+func TestLoader_LoadChanges_WhenPrincipalChanges(t *testing.T) {
+	// Given
+	loaderInstance, deps := newDatabaseLoader(t)
+
+	assert := assert.New(t)
+
+	data, err := loaderInstance.Load([]string{"post"})
+	assert.Nil(err)
+
+	state := NewState(data)
+
+	assert.Nil(deps.PrincipalManager.GetRepository().Create(&model.Principal{ID: "carol", Roles: []*model.Role{{ID: "readers"}}}))
+
+	// When
+	upserted, deleted, err := loaderInstance.LoadChanges([]string{"post"}, NewChanges([]*model.Change{
+		{ItemType: "principal", ItemID: "carol"},
+	}), state)
+
+	// Then
+	assert.Nil(err)
+
+	assert.Equal([]string{"carol"}, identifiers(upserted.Principals))
+	assert.Equal([]string{"readers"}, upserted.Principals[0].Roles)
+	assert.True(IsEmpty(deleted))
+
+	// When - the role loses its policy: principals lose the role
+	assert.Nil(deps.CompiledManager.GetRepository().DB().
+		Where("policy_id = ?", "posts-readers").
+		Delete(&model.CompiledPolicy{}).Error)
+
+	upserted, deleted, err = loaderInstance.LoadChanges([]string{"post"}, NewChanges([]*model.Change{
+		{ItemType: "policy", ItemID: "posts-readers"},
+	}), state)
+
+	// Then
+	assert.Nil(err)
+
+	assert.Equal([]string{"readers"}, identifiers(deleted.Roles))
+	assert.Equal([]string{"alice", "carol"}, identifiers(upserted.Principals))
+	assert.Empty(upserted.Principals[0].Roles)
+}
+
+func TestLoader_LoadChanges_WhenAllChanged(t *testing.T) {
+	// Given
+	loaderInstance, _ := newDatabaseLoader(t)
+
+	assert := assert.New(t)
+
+	state := NewState(&authz.AgentData{
+		Principals: []*authz.Principal{{Id: "dave"}},
+	})
+
+	// When
+	upserted, deleted, err := loaderInstance.LoadChanges([]string{"post"}, NewChanges([]*model.Change{
+		{ItemType: model.ChangeItemTypeCompiled},
+	}), state)
+
+	// Then
+	assert.Nil(err)
+
+	assert.Equal([]string{"alice", "bob"}, identifiers(upserted.Principals))
+	assert.Equal([]string{"dave"}, identifiers(deleted.Principals))
+	assert.Len(upserted.CompiledPolicies, 1)
+}
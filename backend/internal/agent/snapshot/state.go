@@ -0,0 +1,218 @@
+package snapshot
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/pkg/authz"
+	"google.golang.org/protobuf/proto"
+)
+
+// State is the decision data sent to an agent, indexed so the changes made on
+// it are sent as differences.
+type State struct {
+	compiledPolicies *section[*authz.AgentCompiledPolicy]
+	policies         *section[*authz.AgentPolicy]
+	roles            *section[*authz.Role]
+	principals       *section[*authz.Principal]
+	resources        *section[*authz.Resource]
+	resourceKinds    *section[*authz.AgentResourceKind]
+	cedarPolicies    *section[*authz.AgentCedarPolicy]
+	delegations      *section[*authz.AgentDelegation]
+
+	// policyReferences counts the compiled policies of each policy: only the
+	// policies having compiled policies are sent.
+	policyReferences map[string]int
+}
+
+// NewState returns the state of an agent the given data has been sent to.
+func NewState(data *authz.AgentData) *State {
+	state := &State{
+		compiledPolicies: newSection(CompiledPolicyKey),
+		policies:         newSection((*authz.AgentPolicy).GetId),
+		roles:            newSection((*authz.Role).GetId),
+		principals:       newSection((*authz.Principal).GetId),
+		resources:        newSection((*authz.Resource).GetId),
+		resourceKinds:    newSection((*authz.AgentResourceKind).GetId),
+		cedarPolicies:    newSection((*authz.AgentCedarPolicy).GetId),
+		delegations:      newSection((*authz.AgentDelegation).GetId),
+		policyReferences: map[string]int{},
+	}
+
+	state.Replace(data)
+
+	return state
+}
+
+// Replace replaces all the data of the state and returns the data upserted
+// and deleted.
+func (s *State) Replace(data *authz.AgentData) (*authz.AgentData, *authz.AgentData) {
+	var upserted, deleted = &authz.AgentData{}, &authz.AgentData{}
+
+	upserted.CompiledPolicies, deleted.CompiledPolicies, _ = s.replaceCompiledPolicies(all[*authz.AgentCompiledPolicy], data.GetCompiledPolicies())
+	upserted.Policies, deleted.Policies = s.policies.replace(all[*authz.AgentPolicy], data.GetPolicies())
+	upserted.Roles, deleted.Roles = s.roles.replace(all[*authz.Role], data.GetRoles())
+	upserted.Principals, deleted.Principals = s.principals.replace(all[*authz.Principal], data.GetPrincipals())
+	upserted.Resources, deleted.Resources = s.resources.replace(all[*authz.Resource], data.GetResources())
+	upserted.ResourceKinds, deleted.ResourceKinds = s.resourceKinds.replace(all[*authz.AgentResourceKind], data.GetResourceKinds())
+	upserted.CedarPolicies, deleted.CedarPolicies = s.cedarPolicies.replace(all[*authz.AgentCedarPolicy], data.GetCedarPolicies())
+	upserted.Delegations, deleted.Delegations = s.delegations.replace(all[*authz.AgentDelegation], data.GetDelegations())
+
+	return upserted, deleted
+}
+
+// replaceCompiledPolicies replaces the compiled policies matching and returns
+// the compiled policies upserted and deleted, along with the policies that
+// gained their first compiled policy or lost their last one.
+func (s *State) replaceCompiledPolicies(
+	matches func(*authz.AgentCompiledPolicy) bool,
+	compiledPolicies []*authz.AgentCompiledPolicy,
+) ([]*authz.AgentCompiledPolicy, []*authz.AgentCompiledPolicy, map[string]bool) {
+	upserted, deleted := s.compiledPolicies.replace(matches, compiledPolicies)
+
+	var referenced = map[string]bool{}
+
+	count := func(policyID string, delta int) {
+		if _, ok := referenced[policyID]; !ok {
+			referenced[policyID] = s.policyReferences[policyID] > 0
+		}
+
+		s.policyReferences[policyID] += delta
+		if s.policyReferences[policyID] == 0 {
+			delete(s.policyReferences, policyID)
+		}
+	}
+
+	// Compiled policies only have key fields: the upserted ones are new.
+	for _, compiledPolicy := range upserted {
+		count(compiledPolicy.GetPolicyId(), 1)
+	}
+
+	for _, compiledPolicy := range deleted {
+		count(compiledPolicy.GetPolicyId(), -1)
+	}
+
+	var transitioned = map[string]bool{}
+
+	for policyID, wasReferenced := range referenced {
+		if wasReferenced != s.isReferenced(policyID) {
+			transitioned[policyID] = true
+		}
+	}
+
+	return upserted, deleted, transitioned
+}
+
+func (s *State) isReferenced(policyID string) bool {
+	return s.policyReferences[policyID] > 0
+}
+
+// referencedPolicies returns the policies having compiled policies.
+func (s *State) referencedPolicies() map[string]bool {
+	var result = make(map[string]bool, len(s.policyReferences))
+	for policyID := range s.policyReferences {
+		result[policyID] = true
+	}
+
+	return result
+}
+
+// delegationsDependOn returns whether delegations sent have some of the given
+// principals or resources.
+func (s *State) delegationsDependOn(principalIDs map[string]bool, resourceIDs map[string]bool) bool {
+	for _, delegation := range s.delegations.items {
+		if principalIDs[delegation.GetDelegatorId()] || principalIDs[delegation.GetDelegateId()] {
+			return true
+		}
+
+		for _, resource := range delegation.GetResources() {
+			if resourceIDs[resource.GetId()] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsEmpty returns whether there is no data at all.
+func IsEmpty(data *authz.AgentData) bool {
+	return len(data.GetCompiledPolicies()) == 0 &&
+		len(data.GetPolicies()) == 0 &&
+		len(data.GetRoles()) == 0 &&
+		len(data.GetPrincipals()) == 0 &&
+		len(data.GetResources()) == 0 &&
+		len(data.GetResourceKinds()) == 0 &&
+		len(data.GetCedarPolicies()) == 0 &&
+		len(data.GetDelegations()) == 0
+}
+
+// CompiledPolicyKey identifies a compiled policy, which has no identifier.
+func CompiledPolicyKey(compiledPolicy *authz.AgentCompiledPolicy) string {
+	return strings.Join([]string{
+		compiledPolicy.GetPolicyId(),
+		compiledPolicy.GetPrincipalId(),
+		compiledPolicy.GetResourceKind(),
+		compiledPolicy.GetResourceValue(),
+		compiledPolicy.GetActionId(),
+	}, "\x00")
+}
+
+// section is the data of a type sent to an agent, by key.
+type section[T proto.Message] struct {
+	key   func(T) string
+	items map[string]T
+}
+
+func newSection[T proto.Message](key func(T) string) *section[T] {
+	return &section[T]{
+		key:   key,
+		items: map[string]T{},
+	}
+}
+
+// replace replaces the items matching by the given ones, which all have to
+// match, and returns the items that are new or have changed and the items
+// that do not exist anymore, sorted by key.
+func (s *section[T]) replace(matches func(T) bool, items []T) ([]T, []T) {
+	var (
+		current  = make(map[string]T, len(items))
+		upserted []T
+		deleted  []T
+	)
+
+	for _, item := range items {
+		itemKey := s.key(item)
+		current[itemKey] = item
+
+		if previousItem, ok := s.items[itemKey]; !ok || !proto.Equal(previousItem, item) {
+			upserted = append(upserted, item)
+		}
+
+		s.items[itemKey] = item
+	}
+
+	for itemKey, item := range s.items {
+		if _, ok := current[itemKey]; ok || !matches(item) {
+			continue
+		}
+
+		deleted = append(deleted, item)
+		delete(s.items, itemKey)
+	}
+
+	s.sort(upserted)
+	s.sort(deleted)
+
+	return upserted, deleted
+}
+
+func (s *section[T]) sort(items []T) {
+	sort.Slice(items, func(i, j int) bool {
+		return s.key(items[i]) < s.key(items[j])
+	})
+}
+
+func all[T any](T) bool {
+	return true
+}
@@ -0,0 +1,119 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState_Replace(t *testing.T) {
+	// Given
+	previous := &authz.AgentData{
+		CompiledPolicies: []*authz.AgentCompiledPolicy{
+			{PolicyId: "policy-1", ResourceKind: "post", ResourceValue: "*", ActionId: "read"},
+			{PolicyId: "policy-2", ResourceKind: "post", ResourceValue: "1", ActionId: "edit"},
+		},
+		Principals: []*authz.Principal{
+			{Id: "user-1", Roles: []string{"role-1"}},
+			{Id: "user-2"},
+		},
+	}
+
+	current := &authz.AgentData{
+		CompiledPolicies: []*authz.AgentCompiledPolicy{
+			{PolicyId: "policy-1", ResourceKind: "post", ResourceValue: "*", ActionId: "read"},
+			{PolicyId: "policy-2", ResourceKind: "post", ResourceValue: "2", ActionId: "edit"},
+		},
+		Principals: []*authz.Principal{
+			{Id: "user-1", Roles: []string{"role-1", "role-2"}},
+			{Id: "user-2"},
+			{Id: "user-3"},
+		},
+	}
+
+	state := NewState(previous)
+
+	// When
+	upserted, deleted := state.Replace(current)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Equal([]*authz.AgentCompiledPolicy{current.CompiledPolicies[1]}, upserted.CompiledPolicies)
+	assert.Equal([]*authz.AgentCompiledPolicy{previous.CompiledPolicies[1]}, deleted.CompiledPolicies)
+
+	assert.Equal([]*authz.Principal{current.Principals[0], current.Principals[2]}, upserted.Principals)
+	assert.Empty(deleted.Principals)
+
+	assert.False(IsEmpty(upserted))
+	assert.False(IsEmpty(deleted))
+}
+
+func TestState_Replace_WhenNothingChanged(t *testing.T) {
+	// Given
+	data := &authz.AgentData{
+		Policies: []*authz.AgentPolicy{{Id: "policy-1", Effect: "allow"}},
+		Roles:    []*authz.Role{{Id: "role-1", Policies: []string{"policy-1"}}},
+	}
+
+	state := NewState(data)
+
+	// When
+	upserted, deleted := state.Replace(data)
+
+	// Then
+	assert.True(t, IsEmpty(upserted))
+	assert.True(t, IsEmpty(deleted))
+}
+
+func TestState_ReplaceCompiledPolicies(t *testing.T) {
+	// Given
+	state := NewState(&authz.AgentData{
+		CompiledPolicies: []*authz.AgentCompiledPolicy{
+			{PolicyId: "policy-1", PrincipalId: "alice", ResourceKind: "post", ResourceValue: "1", ActionId: "read"},
+			{PolicyId: "policy-1", PrincipalId: "bob", ResourceKind: "post", ResourceValue: "1", ActionId: "read"},
+			{PolicyId: "policy-2", PrincipalId: "alice", ResourceKind: "post", ResourceValue: "2", ActionId: "read"},
+		},
+	})
+
+	alice := func(compiledPolicy *authz.AgentCompiledPolicy) bool {
+		return compiledPolicy.GetPrincipalId() == "alice"
+	}
+
+	// When - Alice loses her compiled policies and gets one of a new policy
+	upserted, deleted, transitioned := state.replaceCompiledPolicies(alice, []*authz.AgentCompiledPolicy{
+		{PolicyId: "policy-3", PrincipalId: "alice", ResourceKind: "post", ResourceValue: "3", ActionId: "read"},
+	})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Len(upserted, 1)
+	assert.Len(deleted, 2)
+
+	// Policy 1 still has the compiled policy of Bob.
+	assert.Equal(map[string]bool{"policy-2": true, "policy-3": true}, transitioned)
+	assert.Equal(map[string]bool{"policy-1": true, "policy-3": true}, state.referencedPolicies())
+}
+
+func TestState_DelegationsDependOn(t *testing.T) {
+	// Given
+	state := NewState(&authz.AgentData{
+		Delegations: []*authz.AgentDelegation{
+			{
+				Id:          "delegation-1",
+				DelegatorId: "alice",
+				DelegateId:  "bob",
+				Resources:   []*authz.Resource{{Id: "post.1", Kind: "post", Value: "1"}},
+			},
+		},
+	})
+
+	// When - Then
+	assert := assert.New(t)
+
+	assert.True(state.delegationsDependOn(map[string]bool{"bob": true}, nil))
+	assert.True(state.delegationsDependOn(nil, map[string]bool{"post.1": true}))
+	assert.False(state.delegationsDependOn(map[string]bool{"carol": true}, map[string]bool{"post.2": true}))
+}
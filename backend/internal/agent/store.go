@@ -0,0 +1,445 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/pkg/authz"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store keeps the decision data synchronized from the server in the local
+// database checks are decided from.
+type Store interface {
+	Apply(response *authz.AgentSyncResponse) error
+}
+
+type store struct {
+	transactionManager database.TransactionManager
+	compiledManager    manager.CompiledPolicy
+	decisionCache      *manager.DecisionCache
+	decisionStore      manager.DecisionStore
+}
+
+func NewStore(
+	transactionManager database.TransactionManager,
+	compiledManager manager.CompiledPolicy,
+	decisionCache *manager.DecisionCache,
+	decisionStore manager.DecisionStore,
+) Store {
+	return &store{
+		transactionManager: transactionManager,
+		compiledManager:    compiledManager,
+		decisionCache:      decisionCache,
+		decisionStore:      decisionStore,
+	}
+}
+
+// Apply applies a response of the server in a single transaction: checks
+// either see the data before the response or all of its changes. A snapshot
+// replaces all the local data, other responses only refresh what they change.
+func (s *store) Apply(response *authz.AgentSyncResponse) error {
+	transaction := s.transactionManager.New()
+
+	if err := apply(transaction.DB(), response); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit decision data: %v", err)
+	}
+
+	if !response.GetSnapshot() {
+		if err := s.refresh(response.GetUpserted()); err != nil {
+			return err
+		}
+
+		return s.refresh(response.GetDeleted())
+	}
+
+	if err := s.decisionStore.Load(); err != nil {
+		return err
+	}
+
+	// Cached decisions may depend on any of the data.
+	s.decisionCache.Purge()
+
+	return nil
+}
+
+// refresh refreshes the decision store and invalidates the cached decisions
+// the given data changes, as the server does for changes made on it.
+func (s *store) refresh(data *authz.AgentData) error {
+	var policyIDs = map[string]bool{}
+
+	for _, compiledPolicy := range data.GetCompiledPolicies() {
+		policyIDs[compiledPolicy.GetPolicyId()] = true
+		s.decisionCache.InvalidateResource(compiledPolicy.GetResourceKind(), compiledPolicy.GetResourceValue())
+	}
+
+	for _, policy := range data.GetPolicies() {
+		policyIDs[policy.GetId()] = true
+		s.decisionCache.InvalidatePolicy(&model.Policy{ID: policy.GetId()})
+	}
+
+	for policyID := range policyIDs {
+		if err := s.decisionStore.RefreshPolicy(policyID); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range data.GetRoles() {
+		if err := s.decisionStore.RefreshRole(role.GetId()); err != nil {
+			return err
+		}
+
+		s.decisionCache.InvalidateRole(role.GetId())
+	}
+
+	for _, principal := range data.GetPrincipals() {
+		if err := s.decisionStore.RefreshPrincipal(principal.GetId()); err != nil {
+			return err
+		}
+
+		s.decisionCache.InvalidatePrincipal(principal.GetId())
+	}
+
+	for _, resource := range data.GetResources() {
+		if err := s.decisionStore.RefreshResource(resource.GetKind(), resource.GetValue()); err != nil {
+			return err
+		}
+
+		s.decisionCache.InvalidateResource(resource.GetKind(), resource.GetValue())
+	}
+
+	for _, resourceKind := range data.GetResourceKinds() {
+		s.decisionCache.InvalidateResource(resourceKind.GetId(), manager.WildcardValue)
+	}
+
+	for _, cedarPolicy := range data.GetCedarPolicies() {
+		if err := s.decisionStore.RefreshCedarPolicy(cedarPolicy.GetId()); err != nil {
+			return err
+		}
+
+		s.compiledManager.EvictCedarPolicy(cedarPolicy.GetId())
+		s.decisionCache.InvalidateCedarPolicy(&model.CedarPolicy{
+			ID:           cedarPolicy.GetId(),
+			ResourceKind: cedarPolicy.GetResourceKind(),
+		})
+	}
+
+	for _, delegation := range data.GetDelegations() {
+		if err := s.decisionStore.RefreshDelegation(delegation.GetId()); err != nil {
+			return err
+		}
+
+		s.decisionCache.InvalidatePrincipal(delegation.GetDelegateId())
+	}
+
+	return nil
+}
+
+func apply(db *gorm.DB, response *authz.AgentSyncResponse) error {
+	if response.GetSnapshot() {
+		if err := clearData(db); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteData(db, response.GetDeleted()); err != nil {
+		return err
+	}
+
+	return upsertData(db, response.GetUpserted())
+}
+
+// clearData deletes all the local data, associations being deleted in cascade.
+func clearData(db *gorm.DB) error {
+	db = db.Session(&gorm.Session{AllowGlobalUpdate: true})
+
+	for _, value := range []any{
+		&model.CompiledPolicy{},
+		&model.Delegation{},
+		&model.CedarPolicy{},
+		&model.ResourceKind{},
+		&model.Resource{},
+		&model.Principal{},
+		&model.Role{},
+		&model.Policy{},
+		&model.Attribute{},
+		&model.Action{},
+	} {
+		if err := db.Delete(value).Error; err != nil {
+			return fmt.Errorf("unable to clear decision data: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteData deletes the given data, associations being deleted in cascade.
+func deleteData(db *gorm.DB, data *authz.AgentData) error {
+	for _, compiledPolicy := range data.GetCompiledPolicies() {
+		if err := db.Where(map[string]any{
+			"policy_id":      compiledPolicy.GetPolicyId(),
+			"principal_id":   compiledPolicy.GetPrincipalId(),
+			"resource_kind":  compiledPolicy.GetResourceKind(),
+			"resource_value": compiledPolicy.GetResourceValue(),
+			"action_id":      compiledPolicy.GetActionId(),
+		}).Delete(&model.CompiledPolicy{}).Error; err != nil {
+			return fmt.Errorf("unable to delete compiled policy: %v", err)
+		}
+	}
+
+	var values = make([]any, 0)
+
+	for _, delegation := range data.GetDelegations() {
+		values = append(values, &model.Delegation{ID: delegation.GetId()})
+	}
+
+	for _, cedarPolicy := range data.GetCedarPolicies() {
+		values = append(values, &model.CedarPolicy{ID: cedarPolicy.GetId()})
+	}
+
+	for _, resourceKind := range data.GetResourceKinds() {
+		values = append(values, &model.ResourceKind{ID: resourceKind.GetId()})
+	}
+
+	for _, resource := range data.GetResources() {
+		values = append(values, &model.Resource{ID: resource.GetId()})
+	}
+
+	for _, principal := range data.GetPrincipals() {
+		values = append(values, &model.Principal{ID: principal.GetId()})
+	}
+
+	for _, role := range data.GetRoles() {
+		values = append(values, &model.Role{ID: role.GetId()})
+	}
+
+	for _, policy := range data.GetPolicies() {
+		values = append(values, &model.Policy{ID: policy.GetId()})
+	}
+
+	for _, value := range values {
+		if err := db.Delete(value).Error; err != nil {
+			return fmt.Errorf("unable to delete decision data: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// upsertData creates or updates the given data along with their associations,
+// data being applied before the ones referencing them.
+func upsertData(db *gorm.DB, data *authz.AgentData) error {
+	for _, policy := range data.GetPolicies() {
+		value, err := toPolicy(policy)
+		if err != nil {
+			return err
+		}
+
+		if err := save(db, value); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range data.GetRoles() {
+		value := &model.Role{ID: role.GetId()}
+
+		var policies = make([]*model.Policy, 0, len(role.GetPolicies()))
+		for _, policyID := range role.GetPolicies() {
+			policies = append(policies, &model.Policy{ID: policyID})
+		}
+
+		if err := save(db, value, association{"Policies", policies}); err != nil {
+			return err
+		}
+	}
+
+	for _, principal := range data.GetPrincipals() {
+		value := &model.Principal{ID: principal.GetId()}
+
+		var roles = make([]*model.Role, 0, len(principal.GetRoles()))
+		for _, roleID := range principal.GetRoles() {
+			roles = append(roles, &model.Role{ID: roleID})
+		}
+
+		attributes, err := findAttributes(db, principal.GetAttributes())
+		if err != nil {
+			return err
+		}
+
+		if err := save(db, value, association{"Roles", roles}, association{"Attributes", attributes}); err != nil {
+			return err
+		}
+	}
+
+	for _, resource := range data.GetResources() {
+		value := &model.Resource{ID: resource.GetId(), Kind: resource.GetKind(), Value: resource.GetValue()}
+
+		attributes, err := findAttributes(db, resource.GetAttributes())
+		if err != nil {
+			return err
+		}
+
+		if err := save(db, value, association{"Attributes", attributes}); err != nil {
+			return err
+		}
+	}
+
+	for _, resourceKind := range data.GetResourceKinds() {
+		value := &model.ResourceKind{ID: resourceKind.GetId()}
+
+		if err := save(db, value, association{"Actions", toActions(resourceKind.GetActions())}); err != nil {
+			return err
+		}
+	}
+
+	for _, cedarPolicy := range data.GetCedarPolicies() {
+		if err := save(db, &model.CedarPolicy{
+			ID:           cedarPolicy.GetId(),
+			Source:       cedarPolicy.GetSource(),
+			Effect:       model.PolicyEffect(cedarPolicy.GetEffect()),
+			ResourceKind: cedarPolicy.GetResourceKind(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, delegation := range data.GetDelegations() {
+		expiresAt, err := parseTime(delegation.GetExpiresAt())
+		if err != nil {
+			return err
+		}
+
+		value := &model.Delegation{
+			ID:          delegation.GetId(),
+			DelegatorID: delegation.GetDelegatorId(),
+			DelegateID:  delegation.GetDelegateId(),
+		}
+
+		if expiresAt != nil {
+			value.ExpiresAt = *expiresAt
+		}
+
+		var resources = make([]*model.Resource, 0, len(delegation.GetResources()))
+		for _, resource := range delegation.GetResources() {
+			resources = append(resources, &model.Resource{ID: resource.GetId(), Kind: resource.GetKind(), Value: resource.GetValue()})
+		}
+
+		if err := save(db, value, association{"Resources", resources}, association{"Actions", toActions(delegation.GetActions())}); err != nil {
+			return err
+		}
+	}
+
+	for _, compiledPolicy := range data.GetCompiledPolicies() {
+		if err := db.Create(&model.CompiledPolicy{
+			PolicyID:      compiledPolicy.GetPolicyId(),
+			PrincipalID:   compiledPolicy.GetPrincipalId(),
+			ResourceKind:  compiledPolicy.GetResourceKind(),
+			ResourceValue: compiledPolicy.GetResourceValue(),
+			ActionID:      compiledPolicy.GetActionId(),
+		}).Error; err != nil {
+			return fmt.Errorf("unable to create compiled policy: %v", err)
+		}
+	}
+
+	return nil
+}
+
+type association struct {
+	name   string
+	values any
+}
+
+// save creates or updates the given value, then replaces its associations.
+// Associated values that do not exist yet are created.
+func save(db *gorm.DB, value any, associations ...association) error {
+	if err := db.Omit(clause.Associations).Clauses(clause.OnConflict{UpdateAll: true}).Create(value).Error; err != nil {
+		return fmt.Errorf("unable to save decision data: %v", err)
+	}
+
+	for _, association := range associations {
+		if err := db.Model(value).Association(association.name).Replace(association.values); err != nil {
+			return fmt.Errorf("unable to save decision data %s: %v", association.name, err)
+		}
+	}
+
+	return nil
+}
+
+// findAttributes returns the local attributes having the given keys and
+// values, creating the missing ones: attributes are shared between principals
+// and resources.
+func findAttributes(db *gorm.DB, attributes []*authz.Attribute) ([]*model.Attribute, error) {
+	var result = make([]*model.Attribute, 0, len(attributes))
+
+	for _, attribute := range attributes {
+		value := &model.Attribute{}
+
+		if err := db.Where(map[string]any{
+			"key_name": attribute.GetKey(),
+			"value":    attribute.GetValue(),
+		}).Attrs(model.Attribute{
+			Key:   attribute.GetKey(),
+			Value: attribute.GetValue(),
+		}).FirstOrCreate(value).Error; err != nil {
+			return nil, fmt.Errorf("unable to save attribute: %v", err)
+		}
+
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+func toPolicy(policy *authz.AgentPolicy) (*model.Policy, error) {
+	notBefore, err := parseTime(policy.GetNotBefore())
+	if err != nil {
+		return nil, err
+	}
+
+	notAfter, err := parseTime(policy.GetNotAfter())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Policy{
+		ID:               policy.GetId(),
+		Effect:           model.PolicyEffect(policy.GetEffect()),
+		Priority:         int(policy.GetPriority()),
+		NotBefore:        notBefore,
+		NotAfter:         notAfter,
+		Schedule:         policy.GetSchedule(),
+		ScheduleDuration: policy.GetScheduleDuration(),
+		ScheduleTimezone: policy.GetScheduleTimezone(),
+	}, nil
+}
+
+func toActions(actionIDs []string) []*model.Action {
+	var actions = make([]*model.Action, 0, len(actionIDs))
+	for _, actionID := range actionIDs {
+		actions = append(actions, &model.Action{ID: actionID})
+	}
+
+	return actions
+}
+
+func parseTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	result, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse time %q: %v", value, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+)
+
+func newDatabaseStore(t *testing.T) (Store, manager.CompiledPolicy) {
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_NAME", filepath.Join(t.TempDir(), "authz.db"))
+	t.Setenv("LOGGER_LEVEL", "ERROR")
+
+	var (
+		storeInstance   Store
+		compiledManager manager.CompiledPolicy
+	)
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(context.Background),
+		configs.FxModule(),
+		database.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		log.FxModule(),
+		fx.Provide(NewStore),
+		fx.Populate(&storeInstance, &compiledManager),
+	)
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("unable to start application: %v", err)
+	}
+
+	t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+	return storeInstance, compiledManager
+}
+
+func newSnapshot() *authz.AgentSyncResponse {
+	return &authz.AgentSyncResponse{
+		Snapshot: true,
+		Upserted: &authz.AgentData{
+			CompiledPolicies: []*authz.AgentCompiledPolicy{
+				{PolicyId: "posts-readers", ResourceKind: "post", ResourceValue: "*", ActionId: "read"},
+				{PolicyId: "post-2-deny", ResourceKind: "post", ResourceValue: "2", ActionId: "read"},
+			},
+			Policies: []*authz.AgentPolicy{
+				{Id: "posts-readers", Effect: "allow"},
+				{Id: "post-2-deny", Effect: "deny"},
+			},
+			Roles: []*authz.Role{
+				{Id: "readers", Policies: []string{"posts-readers", "post-2-deny"}},
+			},
+			Principals: []*authz.Principal{
+				{Id: "alice", Roles: []string{"readers"}},
+				{Id: "bob", Attributes: []*authz.Attribute{{Key: "team", Value: "blue"}}},
+			},
+			Resources: []*authz.Resource{
+				{Id: "post.1", Kind: "post", Value: "1"},
+				{Id: "post.2", Kind: "post", Value: "2"},
+			},
+			ResourceKinds: []*authz.AgentResourceKind{
+				{Id: "post", Actions: []string{"read", "edit"}},
+			},
+			Delegations: []*authz.AgentDelegation{
+				{
+					Id:          "delegation-1",
+					DelegatorId: "alice",
+					DelegateId:  "bob",
+					Resources:   []*authz.Resource{{Id: "post.1", Kind: "post", Value: "1"}},
+					Actions:     []string{"read"},
+					ExpiresAt:   "2100-01-01T00:00:00Z",
+				},
+			},
+		},
+		Deleted: &authz.AgentData{},
+	}
+}
+
+func decisions(t *testing.T, compiledManager manager.CompiledPolicy) []bool {
+	results, err := compiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "2", ActionID: "read"},
+		{PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	})
+	assert.Nil(t, err)
+
+	return results
+}
+
+func TestStore_Apply(t *testing.T) {
+	// Given
+	storeInstance, compiledManager := newDatabaseStore(t)
+
+	// When
+	err := storeInstance.Apply(newSnapshot())
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal([]bool{true, false, true}, decisions(t, compiledManager))
+
+	_, err = compiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "1", ActionID: "delete"},
+	})
+	assert.ErrorIs(err, manager.ErrUnknownAction)
+}
+
+func TestStore_Apply_WhenChanged(t *testing.T) {
+	// Given
+	storeInstance, compiledManager := newDatabaseStore(t)
+
+	assert := assert.New(t)
+	assert.Nil(storeInstance.Apply(newSnapshot()))
+
+	// When
+	err := storeInstance.Apply(&authz.AgentSyncResponse{
+		Upserted: &authz.AgentData{
+			Principals: []*authz.Principal{{Id: "alice"}},
+		},
+		Deleted: &authz.AgentData{
+			CompiledPolicies: []*authz.AgentCompiledPolicy{
+				{PolicyId: "post-2-deny", ResourceKind: "post", ResourceValue: "2", ActionId: "read"},
+			},
+		},
+	})
+
+	// Then
+	assert.Nil(err)
+
+	// Alice lost her role, so does Bob through the delegation.
+	assert.Equal([]bool{false, false, false}, decisions(t, compiledManager))
+}
+
+func TestStore_Apply_WhenSnapshotReplacesData(t *testing.T) {
+	// Given
+	storeInstance, compiledManager := newDatabaseStore(t)
+
+	assert := assert.New(t)
+	assert.Nil(storeInstance.Apply(newSnapshot()))
+
+	snapshot := newSnapshot()
+	snapshot.Upserted.Principals = snapshot.Upserted.Principals[:1]
+	snapshot.Upserted.Delegations = nil
+
+	// When
+	err := storeInstance.Apply(snapshot)
+
+	// Then
+	assert.Nil(err)
+
+	_, err = compiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	})
+	assert.EqualError(err, `unable to retrieve principal "bob": record not found`)
+}
+
+func TestStore_Apply_WhenChangedInMemory(t *testing.T) {
+	// Given
+	t.Setenv("APP_DECISION_STORE", manager.DecisionStoreMemory)
+	t.Setenv("APP_DECISION_CACHE_SIZE", "100")
+
+	storeInstance, compiledManager := newDatabaseStore(t)
+
+	assert := assert.New(t)
+	assert.Nil(storeInstance.Apply(newSnapshot()))
+	assert.Equal([]bool{true, false, true}, decisions(t, compiledManager))
+
+	// When
+	err := storeInstance.Apply(&authz.AgentSyncResponse{
+		Upserted: &authz.AgentData{
+			Roles: []*authz.Role{{Id: "readers", Policies: []string{"post-2-deny"}}},
+		},
+		Deleted: &authz.AgentData{
+			Policies: []*authz.AgentPolicy{{Id: "posts-readers"}},
+			CompiledPolicies: []*authz.AgentCompiledPolicy{
+				{PolicyId: "posts-readers", ResourceKind: "post", ResourceValue: "*", ActionId: "read"},
+			},
+		},
+	})
+
+	// Then
+	assert.Nil(err)
+
+	// Decisions kept in memory and in cache are refreshed.
+	assert.Equal([]bool{false, false, false}, decisions(t, compiledManager))
+}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/pkg/authz"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// ErrNoResourceKinds is returned when no resource kind to synchronize is configured.
+	ErrNoResourceKinds = errors.New("no resource kinds to synchronize")
+)
+
+// Syncer synchronizes the decision data of the configured resource kinds from
+// the server.
+type Syncer interface {
+	Sync(ctx context.Context) error
+}
+
+type syncer struct {
+	client        authz.ApiClient
+	store         Store
+	consistency   *consistency
+	logger        *slog.Logger
+	clientID      string
+	clientSecret  string
+	resourceKinds []string
+}
+
+func NewSyncer(
+	cfg *configs.Agent,
+	client authz.ApiClient,
+	store Store,
+	consistency *consistency,
+	logger *slog.Logger,
+) (Syncer, error) {
+	var resourceKinds = make([]string, 0)
+
+	for _, resourceKind := range strings.Split(cfg.ResourceKinds, ",") {
+		if resourceKind = strings.TrimSpace(resourceKind); resourceKind != "" {
+			resourceKinds = append(resourceKinds, resourceKind)
+		}
+	}
+
+	if len(resourceKinds) == 0 {
+		return nil, ErrNoResourceKinds
+	}
+
+	return &syncer{
+		client:        client,
+		store:         store,
+		consistency:   consistency,
+		logger:        logger,
+		clientID:      cfg.ClientID,
+		clientSecret:  cfg.ClientSecret,
+		resourceKinds: resourceKinds,
+	}, nil
+}
+
+// Sync authenticates on the server, then applies the decision data it sends
+// until the stream fails or the context is canceled.
+func (s *syncer) Sync(ctx context.Context) error {
+	authentication, err := s.client.Authenticate(ctx, &authz.AuthenticateRequest{
+		ClientId:     s.clientID,
+		ClientSecret: s.clientSecret,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to authenticate: %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "bearer "+authentication.GetToken())
+
+	stream, err := s.client.AgentSync(ctx, &authz.AgentSyncRequest{
+		ResourceKinds: s.resourceKinds,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to open sync stream: %v", err)
+	}
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("unable to receive decision data: %v", err)
+		}
+
+		if err := s.store.Apply(response); err != nil {
+			return fmt.Errorf("unable to apply decision data: %v", err)
+		}
+
+		if token := response.GetConsistencyToken(); token != "" {
+			if err := s.consistency.setSynced(token); err != nil {
+				return fmt.Errorf("unable to record consistency token: %v", err)
+			}
+		}
+
+		if response.GetSnapshot() {
+			s.logger.Info("Agent: decision data synchronized",
+				slog.Any("resource_kinds", s.resourceKinds),
+				slog.Int("compiled_policies", len(response.GetUpserted().GetCompiledPolicies())),
+				slog.Int("principals", len(response.GetUpserted().GetPrincipals())),
+			)
+		}
+	}
+}
+
+// NewClient returns a client of the server the decision data is synchronized from.
+func NewClient(lc fx.Lifecycle, cfg *configs.Agent) (authz.ApiClient, error) {
+	transportCredentials := insecure.NewCredentials()
+	if cfg.ServerTLS {
+		transportCredentials = credentials.NewTLS(&tls.Config{})
+	}
+
+	conn, err := grpc.NewClient(cfg.ServerAddr, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create server client: %v", err)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return conn.Close()
+		},
+	})
+
+	return authz.NewApiClient(conn), nil
+}
+
+// RunSyncer synchronizes the decision data in background, reconnecting to the
+// server when the synchronization fails. Checks keep being answered from the
+// last synchronized data meanwhile.
+func RunSyncer(lc fx.Lifecycle, cfg *configs.Agent, logger *slog.Logger, syncer Syncer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				for {
+					err := syncer.Sync(ctx)
+					if ctx.Err() != nil {
+						return
+					}
+
+					logger.Error("Agent: decision data synchronization failed", err,
+						slog.Duration("reconnect_delay", cfg.ReconnectDelay),
+					)
+
+					select {
+					case <-lib_time.After(cfg.ReconnectDelay):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+
+			logger.Info("Agent: decision data synchronization started", slog.String("server_addr", cfg.ServerAddr))
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+
+			logger.Info("Agent: decision data synchronization stopped")
+
+			return nil
+		},
+	})
+}
@@ -82,12 +82,21 @@ func (s *subscriber) handleCheckEvents(eventChan chan *event.Event) {
 				ResourceValue: checkEvent.ResourceValue,
 				Action:        checkEvent.Action,
 				IsAllowed:     checkEvent.IsAllowed,
+				Algorithm:     checkEvent.Algorithm,
 			}
 
 			if checkEvent.CompiledPolicy != nil {
 				audit.PolicyID = checkEvent.CompiledPolicy.PolicyID
 			}
 
+			if checkEvent.CedarPolicy != nil {
+				audit.CedarPolicyID = checkEvent.CedarPolicy.ID
+			}
+
+			if checkEvent.Delegation != nil {
+				audit.DelegationID = checkEvent.Delegation.ID
+			}
+
 			audits = append(audits, audit)
 		}
 
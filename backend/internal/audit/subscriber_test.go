@@ -7,6 +7,7 @@ import (
 
 	"github.com/eko/authz/backend/configs"
 	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/internal/log"
 	"github.com/golang/mock/gomock"
@@ -133,3 +134,52 @@ func TestHandleCheckEvents_WithResourceKindRegexp(t *testing.T) {
 	// Wait 20ms to ensure the spool is triggered.
 	<-time.After(20 * time.Millisecond)
 }
+
+func TestHandleCheckEvents_WithDelegation(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		AuditFlushDelay: 10 * time.Millisecond,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+
+	auditManager := manager.NewMockAudit(ctrl)
+	auditManager.EXPECT().BatchAdd(gomock.Len(1)).
+		Do(func(audits []*model.Audit) {
+			assert.Equal(t, "assistant", audits[0].Principal)
+			assert.Equal(t, "executive-policy", audits[0].PolicyID)
+			assert.Equal(t, "executive-to-assistant", audits[0].DelegationID)
+			assert.Equal(t, "deny-overrides", audits[0].Algorithm)
+		}).
+		Times(1)
+
+	subscriber := NewSubscriber(cfg, logger, dispatcher, auditManager)
+
+	eventChan := make(chan *event.Event, 1)
+
+	// When - Then
+	go subscriber.handleCheckEvents(eventChan)
+
+	eventChan <- &event.Event{
+		Timestamp: 123456,
+		Data: &event.CheckEvent{
+			Principal:      "assistant",
+			ResourceKind:   "calendar",
+			ResourceValue:  "1",
+			Action:         "edit",
+			IsAllowed:      true,
+			Algorithm:      "deny-overrides",
+			CompiledPolicy: &model.CompiledPolicy{PolicyID: "executive-policy"},
+			Delegation:     &model.Delegation{ID: "executive-to-assistant"},
+		},
+	}
+
+	close(eventChan)
+
+	// Wait 20ms to ensure the spool is triggered.
+	<-time.After(20 * time.Millisecond)
+}
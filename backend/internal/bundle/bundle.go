@@ -0,0 +1,227 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the version of the bundle format written on export.
+const CurrentVersion = 1
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var (
+	ErrUnknownFormat      = errors.New("unknown bundle format, expected json or yaml")
+	ErrUnsupportedVersion = fmt.Errorf("unsupported bundle version, expected %d", CurrentVersion)
+)
+
+// Bundle is a declarative description of the whole authorization state.
+type Bundle struct {
+	Version    int          `json:"version" yaml:"version"`
+	Actions    []string     `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Resources  []*Resource  `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Policies   []*Policy    `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Roles      []*Role      `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Principals []*Principal `json:"principals,omitempty" yaml:"principals,omitempty"`
+}
+
+type Resource struct {
+	ID         string            `json:"id" yaml:"id"`
+	Kind       string            `json:"kind" yaml:"kind"`
+	Value      string            `json:"value" yaml:"value"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+type Policy struct {
+	ID               string     `json:"id" yaml:"id"`
+	Resources        []string   `json:"resources" yaml:"resources"`
+	Actions          []string   `json:"actions" yaml:"actions"`
+	AttributeRules   []string   `json:"attribute_rules,omitempty" yaml:"attribute_rules,omitempty"`
+	Effect           string     `json:"effect,omitempty" yaml:"effect,omitempty"`
+	Priority         int        `json:"priority,omitempty" yaml:"priority,omitempty"`
+	NotBefore        *time.Time `json:"not_before,omitempty" yaml:"not_before,omitempty"`
+	NotAfter         *time.Time `json:"not_after,omitempty" yaml:"not_after,omitempty"`
+	Schedule         string     `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	ScheduleDuration string     `json:"schedule_duration,omitempty" yaml:"schedule_duration,omitempty"`
+	ScheduleTimezone string     `json:"schedule_timezone,omitempty" yaml:"schedule_timezone,omitempty"`
+}
+
+type Role struct {
+	ID       string   `json:"id" yaml:"id"`
+	Policies []string `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+type Principal struct {
+	ID         string            `json:"id" yaml:"id"`
+	Roles      []string          `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// ParseFormat returns the bundle format matching given name (defaults to YAML).
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// Decode reads a bundle using the given format and checks its version.
+func Decode(data []byte, format Format) (*Bundle, error) {
+	bundle := &Bundle{}
+
+	var err error
+
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, bundle)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, bundle)
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode bundle: %v", err)
+	}
+
+	if bundle.Version != CurrentVersion {
+		return nil, ErrUnsupportedVersion
+	}
+
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+
+	bundle.Normalize()
+
+	return bundle, nil
+}
+
+// Encode writes the bundle using the given format.
+func Encode(bundle *Bundle, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(bundle, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(bundle)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// isReserved returns whether the identifier belongs to objects managed by Authz
+// itself (admin policies, user and client principals, ...), which are never
+// part of a bundle.
+func isReserved(identifier string) bool {
+	return strings.HasPrefix(identifier, configs.ApplicationName+"-") ||
+		strings.HasPrefix(identifier, configs.ApplicationName+".")
+}
+
+// Validate checks identifiers are set, unique and not reserved.
+func (b *Bundle) Validate() error {
+	var identifiers = map[string]map[string]bool{}
+
+	checkDuplicate := func(objectKind string, identifier string) error {
+		if identifier == "" {
+			return fmt.Errorf("a %s has no identifier", objectKind)
+		}
+
+		if objectKind != ObjectAction && isReserved(identifier) {
+			return fmt.Errorf("%s identifier %q is reserved", objectKind, identifier)
+		}
+
+		if identifiers[objectKind] == nil {
+			identifiers[objectKind] = map[string]bool{}
+		}
+
+		if identifiers[objectKind][identifier] {
+			return fmt.Errorf("%s %q is declared multiple times", objectKind, identifier)
+		}
+
+		identifiers[objectKind][identifier] = true
+
+		return nil
+	}
+
+	for _, action := range b.Actions {
+		if err := checkDuplicate(ObjectAction, action); err != nil {
+			return err
+		}
+	}
+
+	for _, resource := range b.Resources {
+		if err := checkDuplicate(ObjectResource, resource.ID); err != nil {
+			return err
+		}
+
+		if isReserved(resource.Kind) {
+			return fmt.Errorf("resource kind %q is reserved", resource.Kind)
+		}
+	}
+
+	for _, policy := range b.Policies {
+		if err := checkDuplicate(ObjectPolicy, policy.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range b.Roles {
+		if err := checkDuplicate(ObjectRole, role.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, principal := range b.Principals {
+		if err := checkDuplicate(ObjectPrincipal, principal.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Normalize sorts objects and their references so bundles can be compared
+// and exported in a stable way.
+func (b *Bundle) Normalize() {
+	sort.Strings(b.Actions)
+
+	sort.Slice(b.Resources, func(i, j int) bool { return b.Resources[i].ID < b.Resources[j].ID })
+
+	sort.Slice(b.Policies, func(i, j int) bool { return b.Policies[i].ID < b.Policies[j].ID })
+	for _, policy := range b.Policies {
+		sort.Strings(policy.Resources)
+		sort.Strings(policy.Actions)
+
+		// Allow is the default effect, it is omitted to keep bundles concise.
+		if policy.Effect == string(model.PolicyEffectAllow) {
+			policy.Effect = ""
+		}
+	}
+
+	sort.Slice(b.Roles, func(i, j int) bool { return b.Roles[i].ID < b.Roles[j].ID })
+	for _, role := range b.Roles {
+		sort.Strings(role.Policies)
+	}
+
+	sort.Slice(b.Principals, func(i, j int) bool { return b.Principals[i].ID < b.Principals[j].ID })
+	for _, principal := range b.Principals {
+		sort.Strings(principal.Roles)
+	}
+}
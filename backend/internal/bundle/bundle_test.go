@@ -0,0 +1,131 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	// When - Then
+	format, err := ParseFormat("")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatYAML, format)
+
+	format, err = ParseFormat("json")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = ParseFormat("xml")
+	assert.Equal(t, ErrUnknownFormat, err)
+}
+
+func TestDecode(t *testing.T) {
+	// Given
+	data := []byte(`
+version: 1
+actions: [read, edit]
+policies:
+  - id: post-editors
+    resources: [post.2, post.1]
+    actions: [edit]
+    effect: allow
+  - id: post-readers
+    resources: [post.*]
+    actions: [read]
+    effect: deny
+    priority: 10
+principals:
+  - id: alice
+    roles: [reader]
+    attributes:
+      team: blue
+`)
+
+	// When
+	bundle, err := Decode(data, FormatYAML)
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"edit", "read"}, bundle.Actions)
+
+	assert.Len(t, bundle.Policies, 2)
+	assert.Equal(t, []string{"post.1", "post.2"}, bundle.Policies[0].Resources)
+	assert.Equal(t, "", bundle.Policies[0].Effect)
+	assert.Equal(t, "deny", bundle.Policies[1].Effect)
+	assert.Equal(t, 10, bundle.Policies[1].Priority)
+
+	assert.Equal(t, map[string]string{"team": "blue"}, bundle.Principals[0].Attributes)
+}
+
+func TestDecode_WhenInvalid(t *testing.T) {
+	// Given
+	testCases := []struct {
+		name          string
+		data          string
+		format        Format
+		expectedError string
+	}{
+		{
+			name:          "Unsupported version",
+			data:          `{"version": 2}`,
+			format:        FormatJSON,
+			expectedError: "unsupported bundle version, expected 1",
+		},
+		{
+			name:          "Duplicated role",
+			data:          `{"version": 1, "roles": [{"id": "reader"}, {"id": "reader"}]}`,
+			format:        FormatJSON,
+			expectedError: `role "reader" is declared multiple times`,
+		},
+		{
+			name:          "Reserved identifier",
+			data:          `{"version": 1, "policies": [{"id": "authz-policies-admin"}]}`,
+			format:        FormatJSON,
+			expectedError: `policy identifier "authz-policies-admin" is reserved`,
+		},
+		{
+			name:          "Reserved resource kind",
+			data:          `{"version": 1, "resources": [{"id": "my-resource", "kind": "authz.users", "value": "1"}]}`,
+			format:        FormatJSON,
+			expectedError: `resource kind "authz.users" is reserved`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			bundle, err := Decode([]byte(testCase.data), testCase.format)
+
+			// Then
+			assert.Nil(t, bundle)
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	// Given
+	bundle := &Bundle{
+		Version: CurrentVersion,
+		Actions: []string{"read"},
+		Roles: []*Role{
+			{ID: "reader", Policies: []string{"post-readers"}},
+		},
+	}
+
+	// When
+	data, err := Encode(bundle, FormatYAML)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, `version: 1
+actions:
+    - read
+roles:
+    - id: reader
+      policies:
+        - post-readers
+`, string(data))
+}
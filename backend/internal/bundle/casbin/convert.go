@@ -0,0 +1,496 @@
+// Package casbin converts Casbin models and policies into Authz bundles so an
+// existing Casbin deployment can be migrated by applying the resulting bundle.
+package casbin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+)
+
+// DefaultResourceKind is the kind given to resources created from Casbin objects.
+const DefaultResourceKind = "casbin"
+
+const (
+	fieldSubject = "sub"
+	fieldObject  = "obj"
+	fieldAction  = "act"
+	fieldEffect  = "eft"
+
+	policyType   = "p"
+	groupingType = "g"
+)
+
+var (
+	// supportedEffects maps the Casbin policy effects that Authz combining
+	// algorithms can express to whether deny policy lines are evaluated.
+	supportedEffects = map[string]bool{
+		"some(where(p.eft==allow))":                            false,
+		"some(where(p.eft==allow))&&!some(where(p.eft==deny))": true,
+	}
+
+	// supportedConditions are the matcher conditions performing exact matching,
+	// which is how Authz compares principals, resources and actions.
+	supportedConditions = map[string]bool{
+		"g(r.sub,p.sub)": true,
+		"r.sub==p.sub":   true,
+		"p.sub==r.sub":   true,
+		"r.obj==p.obj":   true,
+		"p.obj==r.obj":   true,
+		"r.act==p.act":   true,
+		"p.act==r.act":   true,
+	}
+
+	actionPattern         = regexp.MustCompile(`^[A-Za-z0-9_\-.:]+$`)
+	slugReplaceCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Report lists the Casbin constructs that could not be translated.
+type Report struct {
+	Untranslated []string `json:"untranslated"`
+}
+
+// IsEmpty returns whether everything has been translated.
+func (r *Report) IsEmpty() bool {
+	return len(r.Untranslated) == 0
+}
+
+func (r *Report) String() string {
+	if r.IsEmpty() {
+		return "Every Casbin construct has been translated.\n"
+	}
+
+	var builder strings.Builder
+
+	for _, untranslated := range r.Untranslated {
+		fmt.Fprintf(&builder, "! %s\n", untranslated)
+	}
+
+	return builder.String()
+}
+
+func (r *Report) add(format string, args ...any) {
+	r.Untranslated = append(r.Untranslated, fmt.Sprintf(format, args...))
+}
+
+type Option func(*converter)
+
+// WithResourceKind sets the kind of resources created from Casbin objects.
+func WithResourceKind(kind string) Option {
+	return func(c *converter) {
+		c.resourceKind = kind
+	}
+}
+
+type converter struct {
+	resourceKind string
+	report       *Report
+}
+
+type rule struct {
+	subject string
+	object  string
+	action  string
+	effect  string
+}
+
+// Convert translates a Casbin model and its CSV policy lines into a bundle:
+//
+//   - each subject of a "p" line is a role when it is granted to others by a
+//     "g" line, or a principal otherwise;
+//   - objects become resources of a single kind (see WithResourceKind), "*" a
+//     wildcard resource;
+//   - "p" lines sharing the same subject, object and effect become one policy
+//     attached to the subject role, principals with direct permissions being
+//     given a role named after them;
+//   - role hierarchies are flattened: principals get every inherited role.
+//
+// Constructs Authz cannot express (domains, pattern matching functions, custom
+// effects, ...) are skipped and listed in the returned report.
+func Convert(modelData []byte, policyData []byte, options ...Option) (*bundle.Bundle, *Report, error) {
+	c := &converter{
+		resourceKind: DefaultResourceKind,
+		report:       &Report{Untranslated: make([]string, 0)},
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	casbinModel, err := ParseModel(modelData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fieldIndexes, err := c.checkModel(casbinModel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules, groupings, err := c.readPolicy(casbinModel, fieldIndexes, policyData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := c.build(rules, groupings)
+
+	if err := result.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("unable to convert casbin policy: %v", err)
+	}
+
+	result.Normalize()
+
+	return result, c.report, nil
+}
+
+// checkModel reports model constructs that cannot be translated and returns
+// the index of each policy definition field.
+func (c *converter) checkModel(casbinModel Model) (map[string]int, error) {
+	for _, field := range casbinModel.fields(sectionRequest, "r") {
+		if field != fieldSubject && field != fieldObject && field != fieldAction {
+			c.report.add("request field %q is not supported", field)
+		}
+	}
+
+	var fieldIndexes = map[string]int{}
+
+	for index, field := range casbinModel.fields(sectionPolicy, policyType) {
+		fieldIndexes[field] = index
+	}
+
+	for _, field := range []string{fieldSubject, fieldObject, fieldAction} {
+		if _, ok := fieldIndexes[field]; !ok {
+			return nil, fmt.Errorf("policy definition must declare the %q field", field)
+		}
+	}
+
+	for _, key := range casbinModel.keys(sectionPolicy) {
+		if key != policyType {
+			c.report.add("policy definition %q is not supported, its lines are skipped", key)
+		}
+	}
+
+	for _, key := range casbinModel.keys(sectionRole) {
+		if key != groupingType {
+			c.report.add("role definition %q is not supported, its lines are skipped", key)
+		} else if len(casbinModel.fields(sectionRole, key)) != 2 {
+			c.report.add("role definition %q = %s uses domains which are not supported, its lines are skipped", key, casbinModel[sectionRole][key])
+		}
+	}
+
+	if effect, ok := casbinModel[sectionEffect]["e"]; ok {
+		if _, supported := supportedEffects[strings.Join(strings.Fields(effect), "")]; !supported {
+			c.report.add("policy effect %q is not supported, policies are combined using the configured combining algorithm", effect)
+		}
+	}
+
+	if matcher, ok := casbinModel[sectionMatcher]["m"]; ok {
+		c.checkMatcher(matcher)
+	}
+
+	return fieldIndexes, nil
+}
+
+func (c *converter) checkMatcher(matcher string) {
+	compacted := strings.Join(strings.Fields(matcher), "")
+
+	if strings.Contains(compacted, "||") {
+		c.report.add("matcher %q uses a disjunction which is not supported, exact matching is used", matcher)
+		return
+	}
+
+	for _, condition := range strings.Split(compacted, "&&") {
+		// Drop grouping parentheses while keeping the ones of function calls.
+		condition = strings.TrimLeft(condition, "(")
+		for strings.Count(condition, ")") > strings.Count(condition, "(") {
+			condition = strings.TrimSuffix(condition, ")")
+		}
+
+		if !supportedConditions[condition] {
+			c.report.add("matcher condition %q is not supported, exact matching is used", condition)
+		}
+	}
+}
+
+// readPolicy reads the CSV policy lines, skipping and reporting the ones that
+// cannot be translated.
+func (c *converter) readPolicy(casbinModel Model, fieldIndexes map[string]int, policyData []byte) ([]*rule, [][2]string, error) {
+	var (
+		rules     []*rule
+		groupings [][2]string
+
+		policyFields    = casbinModel.fields(sectionPolicy, policyType)
+		supportedFields = len(policyFields) == len(fieldIndexes)
+		denyEvaluated   = true
+		requiredFields  = max(fieldIndexes[fieldSubject], fieldIndexes[fieldObject], fieldIndexes[fieldAction]) + 1
+	)
+
+	for _, field := range policyFields {
+		if field != fieldSubject && field != fieldObject && field != fieldAction && field != fieldEffect {
+			c.report.add("policy field %q is not supported, policy lines are skipped", field)
+			supportedFields = false
+		}
+	}
+
+	if evaluated, ok := supportedEffects[strings.Join(strings.Fields(casbinModel[sectionEffect]["e"]), "")]; ok {
+		denyEvaluated = evaluated
+	}
+
+	groupingSupported := len(casbinModel.fields(sectionRole, groupingType)) == 2
+
+	reader := csv.NewReader(bytes.NewReader(policyData))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("unable to read policy: %v", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		for index := range record {
+			record[index] = strings.TrimSpace(record[index])
+		}
+
+		switch {
+		case record[0] == policyType && supportedFields:
+			// Trailing fields such as the effect can be omitted.
+			if len(record)-1 > len(policyFields) || len(record)-1 < requiredFields {
+				c.report.add("line %d: expected %d policy fields, got %d", line, len(policyFields), len(record)-1)
+				continue
+			}
+
+			r := &rule{
+				subject: record[fieldIndexes[fieldSubject]+1],
+				object:  record[fieldIndexes[fieldObject]+1],
+				action:  record[fieldIndexes[fieldAction]+1],
+				effect:  string(model.PolicyEffectAllow),
+			}
+
+			if index, ok := fieldIndexes[fieldEffect]; ok && index+1 < len(record) && record[index+1] != "" {
+				r.effect = record[index+1]
+			}
+
+			if c.checkRule(line, r, denyEvaluated) {
+				rules = append(rules, r)
+			}
+
+		case record[0] == groupingType && groupingSupported:
+			if len(record) != 3 || record[1] == "" || record[2] == "" {
+				c.report.add("line %d: expected a member and a role", line)
+				continue
+			}
+
+			groupings = append(groupings, [2]string{record[1], record[2]})
+
+		case record[0] == policyType, record[0] == groupingType:
+			// Already reported on the definition.
+
+		default:
+			c.report.add("line %d: %q lines are not supported", line, record[0])
+		}
+	}
+
+	return rules, groupings, nil
+}
+
+// checkRule returns whether a policy line can be translated, reporting why otherwise.
+func (c *converter) checkRule(line int, r *rule, denyEvaluated bool) bool {
+	switch {
+	case r.subject == "" || r.object == "" || r.action == "":
+		c.report.add("line %d: subject, object and action are required", line)
+
+	case r.object != manager.WildcardValue && strings.Contains(r.object, manager.WildcardValue):
+		c.report.add("line %d: object pattern %q is not supported", line, r.object)
+
+	case !actionPattern.MatchString(r.action):
+		c.report.add("line %d: action %q is not supported, actions are matched exactly", line, r.action)
+
+	case r.effect != string(model.PolicyEffectAllow) && r.effect != string(model.PolicyEffectDeny):
+		c.report.add("line %d: effect %q is not supported", line, r.effect)
+
+	case r.effect == string(model.PolicyEffectDeny) && !denyEvaluated:
+		c.report.add("line %d: deny effect is ignored by the policy effect", line)
+
+	default:
+		return true
+	}
+
+	return false
+}
+
+func (c *converter) build(rules []*rule, groupings [][2]string) *bundle.Bundle {
+	var (
+		result = &bundle.Bundle{Version: bundle.CurrentVersion}
+
+		isRole      = map[string]bool{}
+		memberships = map[string][]string{}
+	)
+
+	for _, grouping := range groupings {
+		isRole[grouping[1]] = true
+		memberships[grouping[0]] = append(memberships[grouping[0]], grouping[1])
+	}
+
+	var (
+		actions      = map[string]bool{}
+		resources    = map[string]bool{}
+		roles        = map[string]*bundle.Role{}
+		principals   = map[string]bool{}
+		policies     = map[string]*bundle.Policy{}
+		policyIDs    = map[string]bool{}
+		subjectRoles = map[string]bool{}
+	)
+
+	for role := range isRole {
+		roles[role] = &bundle.Role{ID: role}
+	}
+
+	for _, r := range rules {
+		if !isRole[r.subject] {
+			principals[r.subject] = true
+			subjectRoles[r.subject] = true
+		}
+
+		if roles[r.subject] == nil {
+			roles[r.subject] = &bundle.Role{ID: r.subject}
+		}
+
+		resourceID := c.resourceKind + manager.ResourceSeparator + r.object
+		if r.object != manager.WildcardValue && !resources[resourceID] {
+			resources[resourceID] = true
+			result.Resources = append(result.Resources, &bundle.Resource{
+				ID:    resourceID,
+				Kind:  c.resourceKind,
+				Value: r.object,
+			})
+		}
+
+		actions[r.action] = true
+
+		key := strings.Join([]string{r.subject, r.object, r.effect}, "\x00")
+
+		policy, ok := policies[key]
+		if !ok {
+			policy = &bundle.Policy{
+				ID:        uniqueID(policyIDs, policyID(r)),
+				Resources: []string{resourceID},
+			}
+
+			if r.effect == string(model.PolicyEffectDeny) {
+				policy.Effect = r.effect
+			}
+
+			policies[key] = policy
+			roles[r.subject].Policies = append(roles[r.subject].Policies, policy.ID)
+			result.Policies = append(result.Policies, policy)
+		}
+
+		if !slices.Contains(policy.Actions, r.action) {
+			policy.Actions = append(policy.Actions, r.action)
+		}
+	}
+
+	for member := range memberships {
+		if !isRole[member] {
+			principals[member] = true
+		}
+	}
+
+	for principal := range principals {
+		var principalRoles []string
+
+		if subjectRoles[principal] {
+			principalRoles = append(principalRoles, principal)
+		}
+
+		principalRoles = append(principalRoles, inheritedRoles(memberships, principal)...)
+
+		result.Principals = append(result.Principals, &bundle.Principal{
+			ID:    principal,
+			Roles: principalRoles,
+		})
+	}
+
+	for action := range actions {
+		result.Actions = append(result.Actions, action)
+	}
+
+	for _, role := range roles {
+		result.Roles = append(result.Roles, role)
+	}
+
+	return result
+}
+
+// inheritedRoles returns the roles granted to a member, directly or through
+// other roles.
+func inheritedRoles(memberships map[string][]string, member string) []string {
+	var (
+		result  []string
+		visited = map[string]bool{member: true}
+		queue   = append([]string{}, memberships[member]...)
+	)
+
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+
+		if visited[role] {
+			continue
+		}
+
+		visited[role] = true
+		result = append(result, role)
+		queue = append(queue, memberships[role]...)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func policyID(r *rule) string {
+	object := r.object
+	if object == manager.WildcardValue {
+		object = "all"
+	}
+
+	identifier := slug(r.subject) + "-" + slug(object)
+	if r.effect == string(model.PolicyEffectDeny) {
+		identifier += "-deny"
+	}
+
+	return identifier
+}
+
+func uniqueID(used map[string]bool, identifier string) string {
+	candidate := identifier
+
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", identifier, i)
+	}
+
+	used[candidate] = true
+
+	return candidate
+}
+
+func slug(value string) string {
+	return strings.Trim(slugReplaceCharacters.ReplaceAllString(strings.ToLower(value), "-"), "-")
+}
@@ -0,0 +1,181 @@
+package casbin
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/stretchr/testify/assert"
+)
+
+const rbacModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act, eft
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj \
+  && r.act == p.act
+`
+
+func TestParseModel(t *testing.T) {
+	// When
+	model, err := ParseModel([]byte(rbacModel))
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"sub", "obj", "act", "eft"}, model.fields(sectionPolicy, "p"))
+	assert.Equal(t, []string{"_", "_"}, model.fields(sectionRole, "g"))
+	assert.Equal(t, "g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act", model[sectionMatcher]["m"])
+}
+
+func TestParseModel_WhenNoPolicyDefinition(t *testing.T) {
+	// When
+	_, err := ParseModel([]byte("[request_definition]\nr = sub, obj, act\n"))
+
+	// Then
+	assert.EqualError(t, err, "model has no policy_definition")
+}
+
+func TestConvert(t *testing.T) {
+	// Given
+	policy := `
+p, admin, data1, read
+p, admin, data1, write
+p, reader, *, read
+p, bob, data2, write
+p, bob, data2, read, deny
+g, alice, admin
+g, admin, reader
+g, charlie, reader
+`
+
+	// When
+	result, report, err := Convert([]byte(rbacModel), []byte(policy))
+
+	// Then
+	assert.Nil(t, err)
+	assert.True(t, report.IsEmpty())
+
+	assert.Equal(t, []string{"read", "write"}, result.Actions)
+
+	assert.Equal(t, []*bundle.Resource{
+		{ID: "casbin.data1", Kind: "casbin", Value: "data1"},
+		{ID: "casbin.data2", Kind: "casbin", Value: "data2"},
+	}, result.Resources)
+
+	assert.Equal(t, []*bundle.Policy{
+		{ID: "admin-data1", Resources: []string{"casbin.data1"}, Actions: []string{"read", "write"}},
+		{ID: "bob-data2", Resources: []string{"casbin.data2"}, Actions: []string{"write"}},
+		{ID: "bob-data2-deny", Resources: []string{"casbin.data2"}, Actions: []string{"read"}, Effect: "deny"},
+		{ID: "reader-all", Resources: []string{"casbin.*"}, Actions: []string{"read"}},
+	}, result.Policies)
+
+	assert.Equal(t, []*bundle.Role{
+		{ID: "admin", Policies: []string{"admin-data1"}},
+		{ID: "bob", Policies: []string{"bob-data2", "bob-data2-deny"}},
+		{ID: "reader", Policies: []string{"reader-all"}},
+	}, result.Roles)
+
+	assert.Equal(t, []*bundle.Principal{
+		{ID: "alice", Roles: []string{"admin", "reader"}},
+		{ID: "bob", Roles: []string{"bob"}},
+		{ID: "charlie", Roles: []string{"reader"}},
+	}, result.Principals)
+}
+
+func TestConvert_ReportsUntranslatedConstructs(t *testing.T) {
+	// Given
+	model := `
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, obj, act
+p2 = sub, act
+
+[role_definition]
+g = _, _, _
+
+[policy_effect]
+e = priority(p.eft) || deny
+
+[matchers]
+m = r.sub == p.sub && keyMatch(r.obj, p.obj) && r.act == p.act
+`
+
+	policy := `
+p, alice, /data/*, GET
+p, alice, data1, (read)|(write)
+p, alice, data1, read
+p2, alice, read
+g, alice, admin, domain1
+`
+
+	// When
+	result, report, err := Convert([]byte(model), []byte(policy), WithResourceKind("data"))
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		`request field "dom" is not supported`,
+		`policy definition "p2" is not supported, its lines are skipped`,
+		`role definition "g" = _, _, _ uses domains which are not supported, its lines are skipped`,
+		`policy effect "priority(p.eft) || deny" is not supported, policies are combined using the configured combining algorithm`,
+		`matcher condition "keyMatch(r.obj,p.obj)" is not supported, exact matching is used`,
+		`line 2: object pattern "/data/*" is not supported`,
+		`line 3: action "(read)|(write)" is not supported, actions are matched exactly`,
+		`line 5: "p2" lines are not supported`,
+	}, report.Untranslated)
+
+	assert.Equal(t, []*bundle.Policy{
+		{ID: "alice-data1", Resources: []string{"data.data1"}, Actions: []string{"read"}},
+	}, result.Policies)
+
+	assert.Equal(t, []*bundle.Principal{
+		{ID: "alice", Roles: []string{"alice"}},
+	}, result.Principals)
+}
+
+func TestConvert_WhenDenyIsIgnoredByEffect(t *testing.T) {
+	// Given
+	model := `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act, eft
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = r.sub == p.sub && r.obj == p.obj && r.act == p.act
+`
+
+	// When
+	result, report, err := Convert([]byte(model), []byte("p, alice, data1, read, deny\n"))
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"line 1: deny effect is ignored by the policy effect"}, report.Untranslated)
+	assert.Empty(t, result.Policies)
+}
+
+func TestConvert_WhenReservedIdentifier(t *testing.T) {
+	// When
+	_, _, err := Convert([]byte(rbacModel), []byte("g, authz-admin, admin\n"))
+
+	// Then
+	assert.EqualError(t, err, `unable to convert casbin policy: principal identifier "authz-admin" is reserved`)
+}
@@ -0,0 +1,108 @@
+package casbin
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	sectionRequest = "request_definition"
+	sectionPolicy  = "policy_definition"
+	sectionRole    = "role_definition"
+	sectionEffect  = "policy_effect"
+	sectionMatcher = "matchers"
+)
+
+// Model is a parsed Casbin model configuration: each section maps its keys
+// (r, p, g, g2, e, m, ...) to their raw value.
+type Model map[string]map[string]string
+
+// ParseModel reads a Casbin model configuration (INI-like format). Values can be
+// continued on the next line using a trailing backslash.
+func ParseModel(data []byte) (Model, error) {
+	var (
+		model   = Model{}
+		section string
+		pending string
+		line    int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+
+		if pending != "" {
+			text = pending + " " + text
+			pending = ""
+		}
+
+		if strings.HasSuffix(text, `\`) {
+			pending = strings.TrimSpace(strings.TrimSuffix(text, `\`))
+			continue
+		}
+
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			model[section] = map[string]string{}
+
+			continue
+		}
+
+		key, value, found := strings.Cut(text, "=")
+		if !found || section == "" {
+			return nil, fmt.Errorf("unable to parse model line %d: %q", line, text)
+		}
+
+		model[section][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read model: %v", err)
+	}
+
+	if model[sectionPolicy]["p"] == "" {
+		return nil, fmt.Errorf("model has no %s", sectionPolicy)
+	}
+
+	return model, nil
+}
+
+// keys returns the sorted keys declared in a section.
+func (m Model) keys(section string) []string {
+	var result []string
+
+	for key := range m[section] {
+		result = append(result, key)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// fields returns the comma separated field names declared by a definition
+// (e.g. "sub, obj, act" or "_, _").
+func (m Model) fields(section string, key string) []string {
+	value, ok := m[section][key]
+	if !ok {
+		return nil
+	}
+
+	var result []string
+
+	for _, field := range strings.Split(value, ",") {
+		result = append(result, strings.TrimSpace(field))
+	}
+
+	return result
+}
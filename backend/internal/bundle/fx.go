@@ -0,0 +1,11 @@
+package bundle
+
+import "go.uber.org/fx"
+
+func FxModule() fx.Option {
+	return fx.Module("bundle",
+		fx.Provide(
+			NewManager,
+		),
+	)
+}
@@ -0,0 +1,466 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/lint"
+)
+
+type Manager interface {
+	Export() (*Bundle, error)
+	Plan(desired *Bundle, prune bool) (*Plan, error)
+	Apply(desired *Bundle, prune bool) (*Plan, error)
+}
+
+type bundleManager struct {
+	cfg                *configs.App
+	transactionManager database.TransactionManager
+	actionManager      manager.Action
+	policyManager      manager.Policy
+	principalManager   manager.Principal
+	resourceManager    manager.Resource
+	roleManager        manager.Role
+	compileJobManager  manager.CompileJob
+	dispatcher         event.Dispatcher
+}
+
+// NewManager initializes a new bundle manager.
+func NewManager(
+	cfg *configs.App,
+	transactionManager database.TransactionManager,
+	actionManager manager.Action,
+	policyManager manager.Policy,
+	principalManager manager.Principal,
+	resourceManager manager.Resource,
+	roleManager manager.Role,
+	compileJobManager manager.CompileJob,
+	dispatcher event.Dispatcher,
+) Manager {
+	return &bundleManager{
+		cfg:                cfg,
+		transactionManager: transactionManager,
+		actionManager:      actionManager,
+		policyManager:      policyManager,
+		principalManager:   principalManager,
+		resourceManager:    resourceManager,
+		roleManager:        roleManager,
+		compileJobManager:  compileJobManager,
+		dispatcher:         dispatcher,
+	}
+}
+
+// Export returns the current state as a bundle. Objects managed by Authz itself
+// (locked resources, admin policies and roles, user and client principals) are skipped.
+func (m *bundleManager) Export() (*Bundle, error) {
+	bundle := &Bundle{Version: CurrentVersion}
+
+	actions, _, err := m.actionManager.GetRepository().Find(repository.WithSkipPagination())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve actions: %v", err)
+	}
+
+	for _, action := range actions {
+		bundle.Actions = append(bundle.Actions, action.ID)
+	}
+
+	resources, _, err := m.resourceManager.GetRepository().Find(
+		repository.WithPreloads("Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resources: %v", err)
+	}
+
+	for _, resource := range resources {
+		if resource.IsLocked || isReserved(resource.ID) || isReserved(resource.Kind) {
+			continue
+		}
+
+		bundle.Resources = append(bundle.Resources, &Resource{
+			ID:         resource.ID,
+			Kind:       resource.Kind,
+			Value:      resource.Value,
+			Attributes: attributesToMap(resource.Attributes),
+		})
+	}
+
+	policies, _, err := m.policyManager.GetRepository().Find(
+		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve policies: %v", err)
+	}
+
+	for _, policy := range policies {
+		if isReserved(policy.ID) {
+			continue
+		}
+
+		bundlePolicy := &Policy{
+			ID:               policy.ID,
+			AttributeRules:   policy.AttributeRules.Data(),
+			Effect:           string(policy.Effect),
+			Priority:         policy.Priority,
+			NotBefore:        policy.NotBefore,
+			NotAfter:         policy.NotAfter,
+			Schedule:         policy.Schedule,
+			ScheduleDuration: policy.ScheduleDuration,
+			ScheduleTimezone: policy.ScheduleTimezone,
+		}
+
+		for _, resource := range policy.Resources {
+			bundlePolicy.Resources = append(bundlePolicy.Resources, resource.ID)
+		}
+
+		for _, action := range policy.Actions {
+			bundlePolicy.Actions = append(bundlePolicy.Actions, action.ID)
+		}
+
+		bundle.Policies = append(bundle.Policies, bundlePolicy)
+	}
+
+	roles, _, err := m.roleManager.GetRepository().Find(
+		repository.WithPreloads("Policies"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve roles: %v", err)
+	}
+
+	for _, role := range roles {
+		if isReserved(role.ID) {
+			continue
+		}
+
+		bundleRole := &Role{ID: role.ID}
+
+		for _, policy := range role.Policies {
+			bundleRole.Policies = append(bundleRole.Policies, policy.ID)
+		}
+
+		bundle.Roles = append(bundle.Roles, bundleRole)
+	}
+
+	principals, _, err := m.principalManager.GetRepository().Find(
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	for _, principal := range principals {
+		if principal.IsLocked || isReserved(principal.ID) {
+			continue
+		}
+
+		bundlePrincipal := &Principal{
+			ID:         principal.ID,
+			Attributes: attributesToMap(principal.Attributes),
+		}
+
+		for _, role := range principal.Roles {
+			bundlePrincipal.Roles = append(bundlePrincipal.Roles, role.ID)
+		}
+
+		bundle.Principals = append(bundle.Principals, bundlePrincipal)
+	}
+
+	bundle.Normalize()
+
+	return bundle, nil
+}
+
+// Plan returns the changes needed to reach the desired bundle, without applying them.
+func (m *bundleManager) Plan(desired *Bundle, prune bool) (*Plan, error) {
+	current, err := m.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(current, desired, prune), nil
+}
+
+// Apply applies the changes needed to reach the desired bundle in a single
+// transaction and returns them. Events are only dispatched once the
+// transaction is committed so policies are compiled against the new state.
+func (m *bundleManager) Apply(desired *Bundle, prune bool) (*Plan, error) {
+	current, err := m.Export()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := Diff(current, desired, prune)
+	if plan.IsEmpty() {
+		return plan, nil
+	}
+
+	transaction := m.transactionManager.New()
+	dispatcher := event.NewBufferedDispatcher(m.dispatcher)
+
+	managers := newTransactionalManagers(m.cfg, transaction, m.compileJobManager.WithTransaction(transaction), dispatcher)
+
+	for _, change := range plan.Changes {
+		if err := managers.apply(desired, change); err != nil {
+			_ = transaction.Rollback()
+			dispatcher.Discard()
+
+			return nil, fmt.Errorf("unable to %s %s %q: %v", change.Operation, change.Object, change.ID, err)
+		}
+	}
+
+	if m.cfg.PolicyLintBlocking {
+		if err := managers.lint(plan); err != nil {
+			_ = transaction.Rollback()
+			dispatcher.Discard()
+
+			return nil, err
+		}
+	}
+
+	if err := transaction.Commit(); err != nil {
+		dispatcher.Discard()
+
+		return nil, fmt.Errorf("unable to commit bundle changes: %v", err)
+	}
+
+	if err := dispatcher.Flush(); err != nil {
+		return nil, fmt.Errorf("unable to dispatch events: %v", err)
+	}
+
+	return plan, nil
+}
+
+// transactionalManagers are entity managers working inside a single transaction.
+type transactionalManagers struct {
+	action    manager.Action
+	linter    lint.Linter
+	policy    manager.Policy
+	principal manager.Principal
+	resource  manager.Resource
+	role      manager.Role
+}
+
+func newTransactionalManagers(
+	cfg *configs.App,
+	transaction database.Transaction,
+	compileJobManager manager.CompileJob,
+	dispatcher event.Dispatcher,
+) *transactionalManagers {
+	var (
+		db                  = transaction.DB()
+		transactionManager  = database.NewSavePointTransactionManager(transaction)
+		compiledRepository  = repository.NewCompiledPolicy(repository.New[model.CompiledPolicy](db))
+		policyRepository    = repository.New[model.Policy](db)
+		principalRepository = repository.NewPrincipal(repository.New[model.Principal](db))
+		resourceRepository  = repository.NewResource(repository.New[model.Resource](db))
+		roleRepository      = repository.New[model.Role](db)
+	)
+
+	// Policies are linted once the whole bundle is applied (see lint) as they
+	// may rely on objects that are created after them, such as principals
+	// attributes, so the policy manager must not block on its own.
+	policyManagerCfg := *cfg
+	policyManagerCfg.PolicyLintBlocking = false
+
+	linter := lint.NewLinter(cfg, policyRepository, principalRepository, resourceRepository, roleRepository)
+
+	actionManager := manager.NewAction(repository.New[model.Action](db))
+	attributeManager := manager.NewAttribute(repository.New[model.Attribute](db))
+
+	resourceKindManager := manager.NewResourceKind(
+		cfg,
+		repository.New[model.ResourceKind](db),
+		actionManager,
+		policyRepository,
+		resourceRepository,
+		transactionManager,
+		dispatcher,
+		time.NewClock(),
+	)
+
+	resourceManager := manager.NewResource(
+		resourceRepository,
+		attributeManager,
+		resourceKindManager,
+		transactionManager,
+		compileJobManager,
+		compiledRepository,
+		dispatcher,
+	)
+
+	return &transactionalManagers{
+		action:   actionManager,
+		linter:   linter,
+		resource: resourceManager,
+		policy: manager.NewPolicy(
+			policyRepository,
+			resourceManager,
+			resourceKindManager,
+			actionManager,
+			lint.NewLinter(&policyManagerCfg, policyRepository, principalRepository, resourceRepository, roleRepository),
+			transactionManager,
+			compileJobManager,
+			compiledRepository,
+			dispatcher,
+		),
+		role: manager.NewRole(
+			roleRepository,
+			policyRepository,
+			transactionManager,
+			dispatcher,
+		),
+		principal: manager.NewPrincipal(
+			principalRepository,
+			roleRepository,
+			attributeManager,
+			transactionManager,
+			compileJobManager,
+			compiledRepository,
+			dispatcher,
+		),
+	}
+}
+
+func (m *transactionalManagers) apply(desired *Bundle, change *Change) error {
+	if change.Operation == OperationDelete {
+		return m.delete(change)
+	}
+
+	isCreate := change.Operation == OperationCreate
+
+	var err error
+
+	switch change.Object {
+	case ObjectAction:
+		_, err = m.action.Create(change.ID)
+
+	case ObjectResource:
+		resource := indexBy(desired.Resources, resourceID)[change.ID]
+
+		if isCreate {
+			_, err = m.resource.Create(resource.ID, resource.Kind, resource.Value, mapToAttributes(resource.Attributes))
+		} else {
+			_, err = m.resource.Update(resource.ID, resource.Kind, resource.Value, mapToAttributes(resource.Attributes))
+		}
+
+	case ObjectPolicy:
+		policy := indexBy(desired.Policies, policyID)[change.ID]
+
+		options := []manager.PolicyOption{
+			manager.WithEffect(model.PolicyEffect(policy.Effect)),
+			manager.WithPriority(policy.Priority),
+			manager.WithValidity(policy.NotBefore, policy.NotAfter),
+			manager.WithSchedule(policy.Schedule, policy.ScheduleDuration, policy.ScheduleTimezone),
+		}
+
+		if isCreate {
+			_, err = m.policy.Create(policy.ID, policy.Resources, policy.Actions, policy.AttributeRules, options...)
+		} else {
+			_, err = m.policy.Update(policy.ID, policy.Resources, policy.Actions, policy.AttributeRules, options...)
+		}
+
+	case ObjectRole:
+		role := indexBy(desired.Roles, roleID)[change.ID]
+
+		if isCreate {
+			_, err = m.role.Create(role.ID, role.Policies)
+		} else {
+			_, err = m.role.Update(role.ID, role.Policies)
+		}
+
+	case ObjectPrincipal:
+		principal := indexBy(desired.Principals, principalID)[change.ID]
+
+		if isCreate {
+			_, err = m.principal.Create(principal.ID, principal.Roles, mapToAttributes(principal.Attributes))
+		} else {
+			_, err = m.principal.Update(principal.ID, principal.Roles, mapToAttributes(principal.Attributes))
+		}
+	}
+
+	return err
+}
+
+// lint returns an error when the policies created or updated by the plan have
+// lint errors.
+func (m *transactionalManagers) lint(plan *Plan) error {
+	var changedPolicies = map[string]bool{}
+
+	for _, change := range plan.Changes {
+		if change.Object == ObjectPolicy && change.Operation != OperationDelete {
+			changedPolicies[change.ID] = true
+		}
+	}
+
+	if len(changedPolicies) == 0 {
+		return nil
+	}
+
+	report, err := m.linter.Lint()
+	if err != nil {
+		return fmt.Errorf("unable to lint bundle policies: %v", err)
+	}
+
+	var messages []string
+
+	for _, finding := range report.Errors() {
+		if finding.ObjectType == lint.ObjectTypePolicy && changedPolicies[finding.ObjectID] {
+			messages = append(messages, finding.Message)
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("policies have lint errors: %s", strings.Join(messages, ", "))
+	}
+
+	return nil
+}
+
+func (m *transactionalManagers) delete(change *Change) error {
+	switch change.Object {
+	case ObjectResource:
+		return m.resource.Delete(change.ID)
+	case ObjectPolicy:
+		return m.policy.Delete(change.ID)
+	case ObjectRole:
+		return m.role.Delete(change.ID)
+	case ObjectPrincipal:
+		return m.principal.Delete(change.ID)
+	default:
+		return fmt.Errorf("cannot delete %s objects", change.Object)
+	}
+}
+
+func attributesToMap(attributes model.Attributes) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	var result = make(map[string]string, len(attributes))
+
+	for _, attribute := range attributes {
+		result[attribute.Key] = attribute.Value
+	}
+
+	return result
+}
+
+func mapToAttributes(attributes map[string]string) map[string]any {
+	var result = make(map[string]any, len(attributes))
+
+	for key, value := range attributes {
+		result[key] = value
+	}
+
+	return result
+}
@@ -0,0 +1,228 @@
+package bundle
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	ObjectAction    = "action"
+	ObjectResource  = "resource"
+	ObjectPolicy    = "policy"
+	ObjectRole      = "role"
+	ObjectPrincipal = "principal"
+)
+
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+var operationSymbols = map[Operation]string{
+	OperationCreate: "+",
+	OperationUpdate: "~",
+	OperationDelete: "-",
+}
+
+type Change struct {
+	Operation Operation `json:"operation"`
+	Object    string    `json:"object"`
+	ID        string    `json:"id"`
+}
+
+// Plan lists the changes to apply on the current state to reach a bundle.
+//
+// Changes are ordered so they can be applied one after the other: creations
+// and updates of dependencies first, then deletions of dependents first.
+type Plan struct {
+	Changes []*Change `json:"changes"`
+}
+
+// IsEmpty returns whether the current state already matches the bundle.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "No changes: state is up-to-date.\n"
+	}
+
+	var builder strings.Builder
+
+	for _, change := range p.Changes {
+		fmt.Fprintf(&builder, "%s %s %s\n", operationSymbols[change.Operation], change.Object, change.ID)
+	}
+
+	return builder.String()
+}
+
+func (p *Plan) add(operation Operation, object string, identifier string) {
+	p.Changes = append(p.Changes, &Change{
+		Operation: operation,
+		Object:    object,
+		ID:        identifier,
+	})
+}
+
+// Diff computes the plan to go from the current bundle to the desired one.
+// Objects that only exist in the current bundle are deleted only when prune is
+// enabled. Actions are never deleted as they are shared by policies.
+func Diff(current *Bundle, desired *Bundle, prune bool) *Plan {
+	plan := &Plan{Changes: make([]*Change, 0)}
+
+	currentActions := toSet(current.Actions)
+	for _, action := range desired.Actions {
+		if !currentActions[action] {
+			plan.add(OperationCreate, ObjectAction, action)
+		}
+	}
+
+	diffObjects(plan, ObjectResource, indexBy(current.Resources, resourceID), desired.Resources, resourceID, resourceEqual)
+	diffObjects(plan, ObjectPolicy, indexBy(current.Policies, policyID), desired.Policies, policyID, policyEqual)
+	diffObjects(plan, ObjectRole, indexBy(current.Roles, roleID), desired.Roles, roleID, roleEqual)
+	diffObjects(plan, ObjectPrincipal, indexBy(current.Principals, principalID), desired.Principals, principalID, principalEqual)
+
+	if !prune {
+		return plan
+	}
+
+	pruneObjects(plan, ObjectPrincipal, current.Principals, toSet(mapIDs(desired.Principals, principalID)), principalID)
+	pruneObjects(plan, ObjectRole, current.Roles, toSet(mapIDs(desired.Roles, roleID)), roleID)
+	pruneObjects(plan, ObjectPolicy, current.Policies, toSet(mapIDs(desired.Policies, policyID)), policyID)
+
+	// Wildcard resources are automatically created by policies: keep them as long
+	// as a policy references them, even when they are not declared.
+	desiredResources := toSet(mapIDs(desired.Resources, resourceID))
+	for _, policy := range desired.Policies {
+		for _, resource := range policy.Resources {
+			desiredResources[resource] = true
+		}
+	}
+
+	pruneObjects(plan, ObjectResource, current.Resources, desiredResources, resourceID)
+
+	return plan
+}
+
+func diffObjects[T any](
+	plan *Plan,
+	object string,
+	current map[string]T,
+	desired []T,
+	identifier func(T) string,
+	equal func(T, T) bool,
+) {
+	for _, desiredObject := range desired {
+		currentObject, exists := current[identifier(desiredObject)]
+
+		switch {
+		case !exists:
+			plan.add(OperationCreate, object, identifier(desiredObject))
+		case !equal(currentObject, desiredObject):
+			plan.add(OperationUpdate, object, identifier(desiredObject))
+		}
+	}
+}
+
+func pruneObjects[T any](plan *Plan, object string, current []T, desired map[string]bool, identifier func(T) string) {
+	for _, currentObject := range current {
+		if !desired[identifier(currentObject)] {
+			plan.add(OperationDelete, object, identifier(currentObject))
+		}
+	}
+}
+
+func resourceID(r *Resource) string   { return r.ID }
+func policyID(p *Policy) string       { return p.ID }
+func roleID(r *Role) string           { return r.ID }
+func principalID(p *Principal) string { return p.ID }
+
+func resourceEqual(a *Resource, b *Resource) bool {
+	return a.Kind == b.Kind &&
+		a.Value == b.Value &&
+		mapEqual(a.Attributes, b.Attributes)
+}
+
+func policyEqual(a *Policy, b *Policy) bool {
+	return sliceEqual(a.Resources, b.Resources) &&
+		sliceEqual(a.Actions, b.Actions) &&
+		sliceEqual(a.AttributeRules, b.AttributeRules) &&
+		a.Effect == b.Effect &&
+		a.Priority == b.Priority &&
+		timeEqual(a.NotBefore, b.NotBefore) &&
+		timeEqual(a.NotAfter, b.NotAfter) &&
+		a.Schedule == b.Schedule &&
+		a.ScheduleDuration == b.ScheduleDuration &&
+		a.ScheduleTimezone == b.ScheduleTimezone
+}
+
+func roleEqual(a *Role, b *Role) bool {
+	return sliceEqual(a.Policies, b.Policies)
+}
+
+func principalEqual(a *Principal, b *Principal) bool {
+	return sliceEqual(a.Roles, b.Roles) &&
+		mapEqual(a.Attributes, b.Attributes)
+}
+
+func indexBy[T any](objects []T, identifier func(T) string) map[string]T {
+	var result = make(map[string]T, len(objects))
+
+	for _, object := range objects {
+		result[identifier(object)] = object
+	}
+
+	return result
+}
+
+func mapIDs[T any](objects []T, identifier func(T) string) []string {
+	var result = make([]string, 0, len(objects))
+
+	for _, object := range objects {
+		result = append(result, identifier(object))
+	}
+
+	return result
+}
+
+func toSet(values []string) map[string]bool {
+	var result = make(map[string]bool, len(values))
+
+	for _, value := range values {
+		result[value] = true
+	}
+
+	return result
+}
+
+// sliceEqual considers nil and empty slices as equal.
+func sliceEqual(a []string, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// mapEqual considers nil and empty maps as equal.
+func mapEqual(a map[string]string, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func timeEqual(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
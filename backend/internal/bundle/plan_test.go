@@ -0,0 +1,92 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	// Given
+	current := &Bundle{
+		Version: CurrentVersion,
+		Actions: []string{"read"},
+		Resources: []*Resource{
+			{ID: "post.*", Kind: "post", Value: "*"},
+			{ID: "post.1", Kind: "post", Value: "1"},
+			{ID: "post.2", Kind: "post", Value: "2"},
+		},
+		Policies: []*Policy{
+			{ID: "post-readers", Resources: []string{"post.*"}, Actions: []string{"read"}},
+		},
+		Roles: []*Role{
+			{ID: "reader", Policies: []string{"post-readers"}},
+			{ID: "old-role"},
+		},
+		Principals: []*Principal{
+			{ID: "alice", Roles: []string{"reader"}},
+		},
+	}
+
+	desired := &Bundle{
+		Version: CurrentVersion,
+		Actions: []string{"edit", "read"},
+		Resources: []*Resource{
+			{ID: "post.1", Kind: "post", Value: "1", Attributes: map[string]string{"owner": "alice"}},
+		},
+		Policies: []*Policy{
+			{ID: "post-readers", Resources: []string{"post.*"}, Actions: []string{"read"}},
+			{ID: "post-editors", Resources: []string{"post.1"}, Actions: []string{"edit"}},
+		},
+		Roles: []*Role{
+			{ID: "reader", Policies: []string{"post-readers"}},
+		},
+		Principals: []*Principal{
+			{ID: "alice", Roles: []string{"reader"}},
+		},
+	}
+
+	// When - Then
+	assert.Equal(t, &Plan{Changes: []*Change{
+		{Operation: OperationCreate, Object: ObjectAction, ID: "edit"},
+		{Operation: OperationUpdate, Object: ObjectResource, ID: "post.1"},
+		{Operation: OperationCreate, Object: ObjectPolicy, ID: "post-editors"},
+	}}, Diff(current, desired, false))
+
+	assert.Equal(t, &Plan{Changes: []*Change{
+		{Operation: OperationCreate, Object: ObjectAction, ID: "edit"},
+		{Operation: OperationUpdate, Object: ObjectResource, ID: "post.1"},
+		{Operation: OperationCreate, Object: ObjectPolicy, ID: "post-editors"},
+		{Operation: OperationDelete, Object: ObjectRole, ID: "old-role"},
+		{Operation: OperationDelete, Object: ObjectResource, ID: "post.2"},
+	}}, Diff(current, desired, true))
+}
+
+func TestDiff_WhenUpToDate(t *testing.T) {
+	// Given
+	bundle := &Bundle{
+		Version: CurrentVersion,
+		Roles: []*Role{
+			{ID: "reader", Policies: []string{}},
+		},
+	}
+
+	// When
+	plan := Diff(bundle, &Bundle{Version: CurrentVersion, Roles: []*Role{{ID: "reader"}}}, true)
+
+	// Then
+	assert.True(t, plan.IsEmpty())
+	assert.Equal(t, "No changes: state is up-to-date.\n", plan.String())
+}
+
+func TestPlan_String(t *testing.T) {
+	// Given
+	plan := &Plan{Changes: []*Change{
+		{Operation: OperationCreate, Object: ObjectPolicy, ID: "post-editors"},
+		{Operation: OperationUpdate, Object: ObjectRole, ID: "reader"},
+		{Operation: OperationDelete, Object: ObjectPrincipal, ID: "bob"},
+	}}
+
+	// When - Then
+	assert.Equal(t, "+ policy post-editors\n~ role reader\n- principal bob\n", plan.String())
+}
@@ -0,0 +1,521 @@
+package cedar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Request is an authorization request evaluated against Cedar policies.
+type Request struct {
+	Principal EntityUID
+	Action    EntityUID
+	Resource  EntityUID
+	Context   Record
+}
+
+// Evaluate returns whether the policy applies to the request: the request
+// matches its scope, every "when" condition is true and every "unless"
+// condition is false. As in Cedar, a policy whose evaluation fails does not
+// apply and the error is returned so it can be reported.
+func (p *Policy) Evaluate(request *Request, entities Entities) (bool, error) {
+	e := &evaluator{request: request, entities: entities}
+
+	if !e.matchScope(p.Principal, request.Principal) ||
+		!e.matchScope(p.Action, request.Action) ||
+		!e.matchScope(p.Resource, request.Resource) {
+		return false, nil
+	}
+
+	for _, condition := range p.Conditions {
+		result, err := e.evaluateBoolean(condition.Expression)
+		if err != nil {
+			return false, err
+		}
+
+		if result == condition.Unless {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+type evaluator struct {
+	request  *Request
+	entities Entities
+}
+
+func (e *evaluator) matchScope(scope *Scope, uid EntityUID) bool {
+	switch scope.Operator {
+	case "==":
+		return uid == scope.Entities[0]
+
+	case "in":
+		for _, entity := range scope.Entities {
+			if e.isIn(uid, entity) {
+				return true
+			}
+		}
+
+		return false
+
+	case "is":
+		return uid.Type == scope.Type && (len(scope.Entities) == 0 || e.isIn(uid, scope.Entities[0]))
+
+	default:
+		return true
+	}
+}
+
+// isIn returns whether the entity is the given ancestor or one of its descendants.
+func (e *evaluator) isIn(uid EntityUID, ancestor EntityUID) bool {
+	var (
+		visited = map[EntityUID]bool{}
+		queue   = []EntityUID{uid}
+	)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current == ancestor {
+			return true
+		}
+
+		if visited[current] {
+			continue
+		}
+
+		visited[current] = true
+
+		if entity, ok := e.entities[current]; ok {
+			queue = append(queue, entity.Parents...)
+		}
+	}
+
+	return false
+}
+
+func (e *evaluator) evaluateBoolean(expression Expression) (bool, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return false, err
+	}
+
+	result, ok := value.(Boolean)
+	if !ok {
+		return false, fmt.Errorf("expected a bool, got a %s", typeName(value))
+	}
+
+	return bool(result), nil
+}
+
+func (e *evaluator) evaluateLong(expression Expression) (Long, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := value.(Long)
+	if !ok {
+		return 0, fmt.Errorf("expected a long, got a %s", typeName(value))
+	}
+
+	return result, nil
+}
+
+func (e *evaluator) evaluateSet(expression Expression) (Set, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := value.(Set)
+	if !ok {
+		return nil, fmt.Errorf("expected a set, got a %s", typeName(value))
+	}
+
+	return result, nil
+}
+
+func (e *evaluator) evaluate(expression Expression) (Value, error) {
+	switch x := expression.(type) {
+	case *literalExpression:
+		return x.value, nil
+
+	case *entityExpression:
+		return x.uid, nil
+
+	case *variableExpression:
+		switch x.name {
+		case variablePrincipal:
+			return e.request.Principal, nil
+		case variableAction:
+			return e.request.Action, nil
+		case variableResource:
+			return e.request.Resource, nil
+		default:
+			if e.request.Context == nil {
+				return Record{}, nil
+			}
+
+			return e.request.Context, nil
+		}
+
+	case *setExpression:
+		var set = make(Set, 0, len(x.elements))
+
+		for _, element := range x.elements {
+			value, err := e.evaluate(element)
+			if err != nil {
+				return nil, err
+			}
+
+			set = append(set, value)
+		}
+
+		return set, nil
+
+	case *recordExpression:
+		var record = make(Record, len(x.keys))
+
+		for i, key := range x.keys {
+			value, err := e.evaluate(x.values[i])
+			if err != nil {
+				return nil, err
+			}
+
+			record[key] = value
+		}
+
+		return record, nil
+
+	case *ifExpression:
+		condition, err := e.evaluateBoolean(x.condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if condition {
+			return e.evaluate(x.then)
+		}
+
+		return e.evaluate(x.otherwise)
+
+	case *unaryExpression:
+		if x.operator == "!" {
+			value, err := e.evaluateBoolean(x.operand)
+			return Boolean(!value), err
+		}
+
+		value, err := e.evaluateLong(x.operand)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == math.MinInt64 {
+			return nil, fmt.Errorf("integer overflow")
+		}
+
+		return -value, nil
+
+	case *binaryExpression:
+		return e.evaluateBinary(x)
+
+	case *attributeExpression:
+		attributes, err := e.attributes(x.object)
+		if err != nil {
+			return nil, err
+		}
+
+		value, ok := attributes[x.attribute]
+		if !ok {
+			return nil, fmt.Errorf("attribute %q does not exist", x.attribute)
+		}
+
+		return value, nil
+
+	case *hasExpression:
+		attributes, err := e.attributes(x.object)
+		if err != nil {
+			return nil, err
+		}
+
+		_, ok := attributes[x.attribute]
+
+		return Boolean(ok), nil
+
+	case *likeExpression:
+		value, err := e.evaluate(x.object)
+		if err != nil {
+			return nil, err
+		}
+
+		text, ok := value.(String)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got a %s", typeName(value))
+		}
+
+		return Boolean(like(string(text), x.pattern)), nil
+
+	case *isExpression:
+		value, err := e.evaluate(x.object)
+		if err != nil {
+			return nil, err
+		}
+
+		uid, ok := value.(EntityUID)
+		if !ok {
+			return nil, fmt.Errorf("expected an entity, got a %s", typeName(value))
+		}
+
+		if uid.Type != x.entityType {
+			return Boolean(false), nil
+		}
+
+		if x.in == nil {
+			return Boolean(true), nil
+		}
+
+		return e.in(uid, x.in)
+
+	case *callExpression:
+		return e.evaluateCall(x)
+	}
+
+	return nil, fmt.Errorf("unsupported expression %T", expression)
+}
+
+func (e *evaluator) evaluateBinary(x *binaryExpression) (Value, error) {
+	switch x.operator {
+	case "&&", "||":
+		left, err := e.evaluateBoolean(x.left)
+		if err != nil {
+			return nil, err
+		}
+
+		// Short-circuit evaluation.
+		if (x.operator == "&&" && !left) || (x.operator == "||" && left) {
+			return Boolean(left), nil
+		}
+
+		right, err := e.evaluateBoolean(x.right)
+
+		return Boolean(right), err
+
+	case "==", "!=":
+		left, err := e.evaluate(x.left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := e.evaluate(x.right)
+		if err != nil {
+			return nil, err
+		}
+
+		return Boolean(equal(left, right) == (x.operator == "==")), nil
+
+	case "in":
+		left, err := e.evaluate(x.left)
+		if err != nil {
+			return nil, err
+		}
+
+		uid, ok := left.(EntityUID)
+		if !ok {
+			return nil, fmt.Errorf("expected an entity, got a %s", typeName(left))
+		}
+
+		return e.in(uid, x.right)
+	}
+
+	left, err := e.evaluateLong(x.left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.evaluateLong(x.right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x.operator {
+	case "<":
+		return Boolean(left < right), nil
+	case "<=":
+		return Boolean(left <= right), nil
+	case ">":
+		return Boolean(left > right), nil
+	case ">=":
+		return Boolean(left >= right), nil
+	case "+":
+		result := left + right
+		if (result > left) != (right > 0) {
+			return nil, fmt.Errorf("integer overflow")
+		}
+
+		return result, nil
+	case "-":
+		result := left - right
+		if (result < left) != (right > 0) {
+			return nil, fmt.Errorf("integer overflow")
+		}
+
+		return result, nil
+	case "*":
+		result := left * right
+		if left != 0 && (result/left != right || (left == -1 && right == math.MinInt64)) {
+			return nil, fmt.Errorf("integer overflow")
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator %q", x.operator)
+}
+
+// in evaluates "uid in expression" where the expression is an entity or a set of entities.
+func (e *evaluator) in(uid EntityUID, expression Expression) (Value, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ancestors := value.(type) {
+	case EntityUID:
+		return Boolean(e.isIn(uid, ancestors)), nil
+
+	case Set:
+		for _, element := range ancestors {
+			ancestor, ok := element.(EntityUID)
+			if !ok {
+				return nil, fmt.Errorf("expected a set of entities, got a %s element", typeName(element))
+			}
+
+			if e.isIn(uid, ancestor) {
+				return Boolean(true), nil
+			}
+		}
+
+		return Boolean(false), nil
+
+	default:
+		return nil, fmt.Errorf("expected an entity or a set, got a %s", typeName(value))
+	}
+}
+
+func (e *evaluator) evaluateCall(x *callExpression) (Value, error) {
+	set, err := e.evaluateSet(x.object)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(x.arguments) != 1 {
+		return nil, fmt.Errorf("method %q expects a single argument", x.method)
+	}
+
+	switch x.method {
+	case "contains":
+		value, err := e.evaluate(x.arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return Boolean(contains(set, value)), nil
+
+	case "containsAll", "containsAny":
+		values, err := e.evaluateSet(x.arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if x.method == "containsAll" {
+			return Boolean(containsAll(set, values)), nil
+		}
+
+		for _, value := range values {
+			if contains(set, value) {
+				return Boolean(true), nil
+			}
+		}
+
+		return Boolean(false), nil
+	}
+
+	return nil, fmt.Errorf("unsupported method %q", x.method)
+}
+
+// attributes returns the attributes of a record or of an entity.
+func (e *evaluator) attributes(expression Expression) (Record, error) {
+	value, err := e.evaluate(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	switch object := value.(type) {
+	case Record:
+		return object, nil
+
+	case EntityUID:
+		entity, ok := e.entities[object]
+		if !ok || entity.Attributes == nil {
+			return Record{}, nil
+		}
+
+		return entity.Attributes, nil
+
+	default:
+		return nil, fmt.Errorf("expected an entity or a record, got a %s", typeName(value))
+	}
+}
+
+// like matches the text against a pattern where "*" matches any sequence of
+// characters and "\*" a literal star.
+func like(text string, pattern string) bool {
+	var (
+		parts    []string
+		current  strings.Builder
+		wildcard = "\x00"
+	)
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && pattern[i+1] == '*':
+			current.WriteByte('*')
+			i++
+		case pattern[i] == '*':
+			parts = append(parts, current.String(), wildcard)
+			current.Reset()
+		default:
+			current.WriteByte(pattern[i])
+		}
+	}
+
+	parts = append(parts, current.String())
+
+	return matchParts(text, parts, wildcard)
+}
+
+func matchParts(text string, parts []string, wildcard string) bool {
+	if len(parts) == 0 {
+		return text == ""
+	}
+
+	if parts[0] != wildcard {
+		if !strings.HasPrefix(text, parts[0]) {
+			return false
+		}
+
+		return matchParts(text[len(parts[0]):], parts[1:], wildcard)
+	}
+
+	for i := 0; i <= len(text); i++ {
+		if matchParts(text[i:], parts[1:], wildcard) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package cedar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRequest() (*Request, Entities) {
+	alice := EntityUID{Type: PrincipalType, ID: "alice"}
+	post := EntityUID{Type: "post", ID: "123"}
+
+	entities := Entities{}
+	entities.Add(
+		&Entity{
+			UID:        alice,
+			Attributes: Record{"team": String("blue"), "level": Long(3)},
+			Parents:    []EntityUID{{Type: RoleType, ID: "editor"}},
+		},
+		&Entity{
+			UID:        post,
+			Attributes: Record{"team": String("blue"), "title": String("Hello world")},
+		},
+	)
+
+	return &Request{
+		Principal: alice,
+		Action:    EntityUID{Type: ActionType, ID: "edit"},
+		Resource:  post,
+		Context:   Record{"ip": String("10.0.0.1")},
+	}, entities
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	// Given
+	request, entities := newTestRequest()
+
+	testCases := []struct {
+		source   string
+		expected bool
+	}{
+		{source: `permit (principal, action, resource);`, expected: true},
+		{source: `permit (principal == Principal::"alice", action == Action::"edit", resource == post::"123");`, expected: true},
+		{source: `permit (principal == Principal::"bob", action, resource);`, expected: false},
+		{source: `permit (principal in Role::"editor", action in [Action::"read", Action::"edit"], resource is post);`, expected: true},
+		{source: `permit (principal in Role::"admin", action, resource);`, expected: false},
+		{source: `permit (principal, action, resource is comment);`, expected: false},
+		{source: `permit (principal, action, resource) when { resource.team == principal.team };`, expected: true},
+		{source: `permit (principal, action, resource) when { principal.level >= 3 && principal.level < 5 };`, expected: true},
+		{source: `permit (principal, action, resource) when { principal.level + 1 * 2 == 5 };`, expected: true},
+		{source: `permit (principal, action, resource) unless { resource has archived };`, expected: true},
+		{source: `permit (principal, action, resource) when { resource.title like "Hello*" };`, expected: true},
+		{source: `permit (principal, action, resource) when { resource.title like "*moon" };`, expected: false},
+		{source: `permit (principal, action, resource) when { context.ip == "10.0.0.1" };`, expected: true},
+		{source: `permit (principal, action, resource) when { principal in Role::"editor" };`, expected: true},
+		{source: `permit (principal, action, resource) when { principal is Principal in [Role::"x", Role::"editor"] };`, expected: true},
+		{source: `permit (principal, action, resource) when { ["blue", "red"].contains(principal.team) };`, expected: true},
+		{source: `permit (principal, action, resource) when { ["blue"].containsAll(["blue", "red"]) };`, expected: false},
+		{source: `permit (principal, action, resource) when { if principal.team == "red" then true else !false };`, expected: true},
+		{source: `permit (principal, action, resource) when { {a: 1, "b": [1, 2]} == {"b": [2, 1], a: 1} };`, expected: true},
+		{source: `permit (principal, action, resource) when { false && principal.missing };`, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		policy, err := ParsePolicy(testCase.source)
+		assert.Nil(t, err, testCase.source)
+
+		// When
+		result, err := policy.Evaluate(request, entities)
+
+		// Then
+		assert.Nil(t, err, testCase.source)
+		assert.Equal(t, testCase.expected, result, testCase.source)
+	}
+}
+
+func TestPolicy_Evaluate_WhenError(t *testing.T) {
+	// Given
+	request, entities := newTestRequest()
+
+	testCases := []struct {
+		source        string
+		expectedError string
+	}{
+		{source: `permit (principal, action, resource) when { principal.missing == "x" };`, expectedError: `attribute "missing" does not exist`},
+		{source: `permit (principal, action, resource) when { principal.team > 1 };`, expectedError: "expected a long, got a string"},
+		{source: `permit (principal, action, resource) when { principal.team };`, expectedError: "expected a bool, got a string"},
+		{source: `permit (principal, action, resource) when { 9223372036854775807 + 1 > 0 };`, expectedError: "integer overflow"},
+	}
+
+	for _, testCase := range testCases {
+		policy, err := ParsePolicy(testCase.source)
+		assert.Nil(t, err, testCase.source)
+
+		// When
+		result, err := policy.Evaluate(request, entities)
+
+		// Then
+		assert.False(t, result)
+		assert.EqualError(t, err, testCase.expectedError, testCase.source)
+	}
+}
+
+func TestAttributeValue(t *testing.T) {
+	// When - Then
+	assert.Equal(t, Long(42), AttributeValue("42"))
+	assert.Equal(t, Long(-3), AttributeValue("-3"))
+	assert.Equal(t, String("007"), AttributeValue("007"))
+	assert.Equal(t, String("blue"), AttributeValue("blue"))
+}
@@ -0,0 +1,150 @@
+package cedar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenInteger
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// operators are sorted so that longer operators are matched first.
+var operators = []string{
+	"::", "==", "!=", "<=", ">=", "&&", "||",
+	"(", ")", "{", "}", "[", "]", ",", ";", ".", ":", "<", ">", "!", "+", "-", "*", "@",
+}
+
+func tokenize(source string) ([]token, error) {
+	var (
+		tokens   []token
+		position int
+	)
+
+	for position < len(source) {
+		character := rune(source[position])
+
+		switch {
+		case unicode.IsSpace(character):
+			position++
+
+		case strings.HasPrefix(source[position:], "//"):
+			end := strings.IndexByte(source[position:], '\n')
+			if end < 0 {
+				position = len(source)
+			} else {
+				position += end
+			}
+
+		case character == '"':
+			value, length, err := readString(source[position:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at offset %d", err, position)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value, position: position})
+			position += length
+
+		case character >= '0' && character <= '9':
+			start := position
+			for position < len(source) && source[position] >= '0' && source[position] <= '9' {
+				position++
+			}
+
+			tokens = append(tokens, token{kind: tokenInteger, value: source[start:position], position: start})
+
+		case character == '_' || unicode.IsLetter(character):
+			start := position
+			for position < len(source) && (source[position] == '_' || unicode.IsLetter(rune(source[position])) || unicode.IsDigit(rune(source[position]))) {
+				position++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdentifier, value: source[start:position], position: start})
+
+		default:
+			operator := ""
+
+			for _, candidate := range operators {
+				if strings.HasPrefix(source[position:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", character, position)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, position: position})
+			position += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: position}), nil
+}
+
+// readString reads a double quoted string at the beginning of the source and
+// returns its unescaped value and its length in the source.
+func readString(source string) (string, int, error) {
+	var builder strings.Builder
+
+	for i := 1; i < len(source); i++ {
+		switch source[i] {
+		case '"':
+			return builder.String(), i + 1, nil
+
+		case '\\':
+			if i+1 >= len(source) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+
+			i++
+
+			switch source[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			case 'r':
+				builder.WriteByte('\r')
+			case '0':
+				builder.WriteByte(0)
+			case '*':
+				// Escaped wildcards are only meaningful in like patterns.
+				builder.WriteString(`\*`)
+			default:
+				builder.WriteByte(source[i])
+			}
+
+		default:
+			builder.WriteByte(source[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string")
+}
@@ -0,0 +1,745 @@
+package cedar
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type Effect string
+
+const (
+	EffectPermit Effect = "permit"
+	EffectForbid Effect = "forbid"
+)
+
+// Policy is a parsed Cedar policy statement.
+type Policy struct {
+	Effect      Effect
+	Annotations map[string]string
+	Principal   *Scope
+	Action      *Scope
+	Resource    *Scope
+	Conditions  []*Condition
+}
+
+// Scope constrains the principal, action or resource of a request. A nil
+// operator means any value is accepted.
+type Scope struct {
+	Operator string // "", "==", "in" or "is"
+	Type     string // entity type of "is" scopes
+	Entities []EntityUID
+}
+
+// Condition is a "when" (Unless is false) or "unless" clause.
+type Condition struct {
+	Unless     bool
+	Expression Expression
+}
+
+// Expression is a node of a condition expression tree.
+type Expression interface {
+	expression()
+}
+
+type (
+	literalExpression struct {
+		value Value
+	}
+
+	variableExpression struct {
+		name string
+	}
+
+	entityExpression struct {
+		uid EntityUID
+	}
+
+	setExpression struct {
+		elements []Expression
+	}
+
+	recordExpression struct {
+		keys   []string
+		values []Expression
+	}
+
+	unaryExpression struct {
+		operator string
+		operand  Expression
+	}
+
+	binaryExpression struct {
+		operator string
+		left     Expression
+		right    Expression
+	}
+
+	ifExpression struct {
+		condition Expression
+		then      Expression
+		otherwise Expression
+	}
+
+	attributeExpression struct {
+		object    Expression
+		attribute string
+	}
+
+	hasExpression struct {
+		object    Expression
+		attribute string
+	}
+
+	likeExpression struct {
+		object  Expression
+		pattern string
+	}
+
+	isExpression struct {
+		object     Expression
+		entityType string
+		in         Expression
+	}
+
+	callExpression struct {
+		object    Expression
+		method    string
+		arguments []Expression
+	}
+)
+
+func (literalExpression) expression()   {}
+func (variableExpression) expression()  {}
+func (entityExpression) expression()    {}
+func (setExpression) expression()       {}
+func (recordExpression) expression()    {}
+func (unaryExpression) expression()     {}
+func (binaryExpression) expression()    {}
+func (ifExpression) expression()        {}
+func (attributeExpression) expression() {}
+func (hasExpression) expression()       {}
+func (likeExpression) expression()      {}
+func (isExpression) expression()        {}
+func (callExpression) expression()      {}
+
+const (
+	variablePrincipal = "principal"
+	variableAction    = "action"
+	variableResource  = "resource"
+	variableContext   = "context"
+)
+
+// Parse parses Cedar source containing one or more policy statements.
+func Parse(source string) ([]*Policy, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse cedar policy: %v", err)
+	}
+
+	p := &parser{tokens: tokens}
+
+	var policies []*Policy
+
+	for p.peek().kind != tokenEOF {
+		policy, err := p.parsePolicy()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse cedar policy: %v", err)
+		}
+
+		policies = append(policies, policy)
+	}
+
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("unable to parse cedar policy: no policy statement found")
+	}
+
+	return policies, nil
+}
+
+// ParsePolicy parses Cedar source that must contain exactly one policy statement.
+func ParsePolicy(source string) (*Policy, error) {
+	policies, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) != 1 {
+		return nil, fmt.Errorf("expected a single cedar policy statement, got %d", len(policies))
+	}
+
+	return policies[0], nil
+}
+
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEOF {
+		p.position++
+	}
+
+	return t
+}
+
+func (p *parser) is(value string) bool {
+	t := p.peek()
+	return (t.kind == tokenOperator || t.kind == tokenIdentifier) && t.value == value
+}
+
+func (p *parser) accept(value string) bool {
+	if p.is(value) {
+		p.next()
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(value string) error {
+	if !p.accept(value) {
+		return p.unexpected(fmt.Sprintf("%q", value))
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	return fmt.Errorf("expected %s at offset %d, got %s", expected, t.position, t)
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdentifier {
+		return "", p.unexpected("an identifier")
+	}
+
+	p.next()
+
+	return t.value, nil
+}
+
+func (p *parser) parsePolicy() (*Policy, error) {
+	policy := &Policy{Annotations: map[string]string{}}
+
+	for p.accept("@") {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		value := p.next()
+		if value.kind != tokenString {
+			return nil, fmt.Errorf("expected an annotation value at offset %d", value.position)
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		policy.Annotations[name] = value.value
+	}
+
+	switch {
+	case p.accept(string(EffectPermit)):
+		policy.Effect = EffectPermit
+	case p.accept(string(EffectForbid)):
+		policy.Effect = EffectForbid
+	default:
+		return nil, p.unexpected(`"permit" or "forbid"`)
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	if policy.Principal, err = p.parseScope(variablePrincipal); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+
+	if policy.Action, err = p.parseScope(variableAction); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+
+	if policy.Resource, err = p.parseScope(variableResource); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	for p.is("when") || p.is("unless") {
+		condition := &Condition{Unless: p.next().value == "unless"}
+
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+
+		if condition.Expression, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+
+		policy.Conditions = append(policy.Conditions, condition)
+	}
+
+	if err := p.expect(";"); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (p *parser) parseScope(variable string) (*Scope, error) {
+	if err := p.expect(variable); err != nil {
+		return nil, err
+	}
+
+	scope := &Scope{}
+
+	switch {
+	case p.accept("=="):
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return nil, err
+		}
+
+		scope.Operator = "=="
+		scope.Entities = []EntityUID{uid}
+
+	case p.accept("in"):
+		scope.Operator = "in"
+
+		if variable == variableAction && p.accept("[") {
+			for !p.accept("]") {
+				uid, err := p.parseEntityUID()
+				if err != nil {
+					return nil, err
+				}
+
+				scope.Entities = append(scope.Entities, uid)
+
+				if !p.is("]") {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			break
+		}
+
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return nil, err
+		}
+
+		scope.Entities = []EntityUID{uid}
+
+	case variable != variableAction && p.accept("is"):
+		entityType, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+
+		scope.Operator = "is"
+		scope.Type = entityType
+
+		if p.accept("in") {
+			uid, err := p.parseEntityUID()
+			if err != nil {
+				return nil, err
+			}
+
+			scope.Entities = []EntityUID{uid}
+		}
+	}
+
+	return scope, nil
+}
+
+// parsePath parses an entity type name such as "Post" or "App::Post".
+func (p *parser) parsePath() (string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", err
+	}
+
+	for p.is("::") && p.tokens[p.position+1].kind == tokenIdentifier {
+		p.next()
+
+		part, _ := p.identifier()
+		name += "::" + part
+	}
+
+	return name, nil
+}
+
+func (p *parser) parseEntityUID() (EntityUID, error) {
+	entityType, err := p.parsePath()
+	if err != nil {
+		return EntityUID{}, err
+	}
+
+	if err := p.expect("::"); err != nil {
+		return EntityUID{}, err
+	}
+
+	identifier := p.next()
+	if identifier.kind != tokenString {
+		return EntityUID{}, fmt.Errorf("expected an entity identifier string at offset %d", identifier.position)
+	}
+
+	return EntityUID{Type: entityType, ID: identifier.value}, nil
+}
+
+func (p *parser) parseExpression() (Expression, error) {
+	if p.accept("if") {
+		condition, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+
+		then, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("else"); err != nil {
+			return nil, err
+		}
+
+		otherwise, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ifExpression{condition: condition, then: then, otherwise: otherwise}, nil
+	}
+
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{operator: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{operator: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseRelation() (Expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.accept("has"):
+		t := p.next()
+		if t.kind != tokenIdentifier && t.kind != tokenString {
+			return nil, fmt.Errorf("expected an attribute name at offset %d", t.position)
+		}
+
+		return &hasExpression{object: left, attribute: t.value}, nil
+
+	case p.accept("like"):
+		t := p.next()
+		if t.kind != tokenString {
+			return nil, fmt.Errorf("expected a pattern string at offset %d", t.position)
+		}
+
+		return &likeExpression{object: left, pattern: t.value}, nil
+
+	case p.accept("is"):
+		entityType, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+
+		expression := &isExpression{object: left, entityType: entityType}
+
+		if p.accept("in") {
+			if expression.in, err = p.parseAdditive(); err != nil {
+				return nil, err
+			}
+		}
+
+		return expression, nil
+	}
+
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(operator) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+
+			return &binaryExpression{operator: operator, left: left, right: right}, nil
+		}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is("+") || p.is("-") {
+		operator := p.next().value
+
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("*") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryExpression{operator: "*", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.is("!") || p.is("-") {
+		operator := p.next().value
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryExpression{operator: operator, operand: operand}, nil
+	}
+
+	return p.parseMember()
+}
+
+func (p *parser) parseMember() (Expression, error) {
+	expression, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.accept("."):
+			name, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+
+			if !p.accept("(") {
+				expression = &attributeExpression{object: expression, attribute: name}
+				continue
+			}
+
+			call := &callExpression{object: expression, method: name}
+
+			for !p.accept(")") {
+				argument, err := p.parseExpression()
+				if err != nil {
+					return nil, err
+				}
+
+				call.arguments = append(call.arguments, argument)
+
+				if !p.is(")") {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			expression = call
+
+		case p.is("[") && p.tokens[p.position+1].kind == tokenString:
+			p.next()
+			name := p.next().value
+
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			expression = &attributeExpression{object: expression, attribute: name}
+
+		default:
+			return expression, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == tokenString:
+		p.next()
+		return &literalExpression{value: String(t.value)}, nil
+
+	case t.kind == tokenInteger:
+		p.next()
+
+		value, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s at offset %d", t.value, t.position)
+		}
+
+		return &literalExpression{value: Long(value)}, nil
+
+	case p.accept("("):
+		expression, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		return expression, p.expect(")")
+
+	case p.accept("["):
+		set := &setExpression{}
+
+		for !p.accept("]") {
+			element, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+
+			set.elements = append(set.elements, element)
+
+			if !p.is("]") {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return set, nil
+
+	case p.accept("{"):
+		record := &recordExpression{}
+
+		for !p.accept("}") {
+			key := p.next()
+			if key.kind != tokenIdentifier && key.kind != tokenString {
+				return nil, fmt.Errorf("expected a record key at offset %d", key.position)
+			}
+
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+
+			record.keys = append(record.keys, key.value)
+			record.values = append(record.values, value)
+
+			if !p.is("}") {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return record, nil
+
+	case t.kind == tokenIdentifier:
+		switch t.value {
+		case "true", "false":
+			p.next()
+			return &literalExpression{value: Boolean(t.value == "true")}, nil
+
+		case variablePrincipal, variableAction, variableResource, variableContext:
+			p.next()
+			return &variableExpression{name: t.value}, nil
+		}
+
+		uid, err := p.parseEntityUID()
+		if err != nil {
+			return nil, err
+		}
+
+		return &entityExpression{uid: uid}, nil
+	}
+
+	return nil, p.unexpected("an expression")
+}
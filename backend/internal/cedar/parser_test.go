@@ -0,0 +1,95 @@
+package cedar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// Given
+	source := `
+// Editors can edit posts of their team.
+@id("editors")
+permit (
+	principal in Role::"editor",
+	action in [Action::"edit", Action::"publish"],
+	resource is post
+) when {
+	resource.team == principal.team
+} unless {
+	resource has archived && resource.archived == "true"
+};
+
+forbid (principal == Principal::"mallory", action, resource);
+`
+
+	// When
+	policies, err := Parse(source)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Len(t, policies, 2)
+
+	editors := policies[0]
+	assert.Equal(t, EffectPermit, editors.Effect)
+	assert.Equal(t, map[string]string{"id": "editors"}, editors.Annotations)
+	assert.Equal(t, &Scope{Operator: "in", Entities: []EntityUID{{Type: "Role", ID: "editor"}}}, editors.Principal)
+	assert.Equal(t, &Scope{Operator: "in", Entities: []EntityUID{{Type: "Action", ID: "edit"}, {Type: "Action", ID: "publish"}}}, editors.Action)
+	assert.Equal(t, &Scope{Operator: "is", Type: "post"}, editors.Resource)
+	assert.Len(t, editors.Conditions, 2)
+	assert.False(t, editors.Conditions[0].Unless)
+	assert.True(t, editors.Conditions[1].Unless)
+	assert.Equal(t, "post", editors.ResourceKind())
+
+	mallory := policies[1]
+	assert.Equal(t, EffectForbid, mallory.Effect)
+	assert.Equal(t, &Scope{}, mallory.Action)
+	assert.Empty(t, mallory.Conditions)
+	assert.Equal(t, "", mallory.ResourceKind())
+}
+
+func TestParse_WhenInvalid(t *testing.T) {
+	// Given
+	testCases := []struct {
+		source        string
+		expectedError string
+	}{
+		{
+			source:        ``,
+			expectedError: "unable to parse cedar policy: no policy statement found",
+		},
+		{
+			source:        `allow (principal, action, resource);`,
+			expectedError: `unable to parse cedar policy: expected "permit" or "forbid" at offset 0, got "allow"`,
+		},
+		{
+			source:        `permit (principal, action, resource)`,
+			expectedError: `unable to parse cedar policy: expected ";" at offset 36, got end of input`,
+		},
+		{
+			source:        `permit (principal == "alice", action, resource);`,
+			expectedError: `unable to parse cedar policy: expected an identifier at offset 21, got "alice"`,
+		},
+		{
+			source:        `permit (principal, action, resource) when { resource.name == "x };`,
+			expectedError: "unable to parse cedar policy: unterminated string at offset 61",
+		},
+	}
+
+	for _, testCase := range testCases {
+		// When
+		_, err := Parse(testCase.source)
+
+		// Then
+		assert.EqualError(t, err, testCase.expectedError)
+	}
+}
+
+func TestParsePolicy_WhenSeveralStatements(t *testing.T) {
+	// When
+	_, err := ParsePolicy(`permit (principal, action, resource); forbid (principal, action, resource);`)
+
+	// Then
+	assert.EqualError(t, err, "expected a single cedar policy statement, got 2")
+}
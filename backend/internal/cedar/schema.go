@@ -0,0 +1,181 @@
+package cedar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ActionType is the entity type of actions.
+	ActionType = "Action"
+
+	// PrincipalType is the entity type of principals.
+	PrincipalType = "Principal"
+
+	// RoleType is the entity type of roles, principals being members of their roles.
+	RoleType = "Role"
+)
+
+// Schema declares the entity types and actions policies can reference.
+type Schema struct {
+	EntityTypes map[string]bool
+	Actions     map[string]bool
+}
+
+// NewSchema returns a schema knowing the principal, role and action entity
+// types, the given resource kinds as entity types and the given actions.
+func NewSchema(resourceKinds []string, actions []string) *Schema {
+	schema := &Schema{
+		EntityTypes: map[string]bool{
+			ActionType:    true,
+			PrincipalType: true,
+			RoleType:      true,
+		},
+		Actions: map[string]bool{},
+	}
+
+	for _, kind := range resourceKinds {
+		schema.EntityTypes[kind] = true
+	}
+
+	for _, action := range actions {
+		schema.Actions[action] = true
+	}
+
+	return schema
+}
+
+// Validate checks that the policy only references known entity types and
+// actions, and that its scope uses entities of the expected types.
+func (s *Schema) Validate(policy *Policy) error {
+	var errors []string
+
+	checkEntity := func(uid EntityUID) {
+		switch {
+		case !s.EntityTypes[uid.Type]:
+			errors = append(errors, fmt.Sprintf("unknown entity type %q", uid.Type))
+		case uid.Type == ActionType && !s.Actions[uid.ID]:
+			errors = append(errors, fmt.Sprintf("unknown action %q", uid.ID))
+		}
+	}
+
+	checkType := func(entityType string) {
+		if !s.EntityTypes[entityType] {
+			errors = append(errors, fmt.Sprintf("unknown entity type %q", entityType))
+		}
+	}
+
+	for _, uid := range policy.Principal.Entities {
+		checkEntity(uid)
+
+		if uid.Type != PrincipalType && uid.Type != RoleType {
+			errors = append(errors, fmt.Sprintf("principal scope expects a %s or a %s, got %s", PrincipalType, RoleType, uid))
+		}
+	}
+
+	if policy.Principal.Operator == "is" && policy.Principal.Type != PrincipalType {
+		errors = append(errors, fmt.Sprintf("principal scope expects the %s type, got %q", PrincipalType, policy.Principal.Type))
+	}
+
+	for _, uid := range policy.Action.Entities {
+		checkEntity(uid)
+
+		if uid.Type != ActionType {
+			errors = append(errors, fmt.Sprintf("action scope expects an %s, got %s", ActionType, uid))
+		}
+	}
+
+	for _, uid := range policy.Resource.Entities {
+		checkEntity(uid)
+	}
+
+	if policy.Resource.Operator == "is" {
+		checkType(policy.Resource.Type)
+	}
+
+	for _, condition := range policy.Conditions {
+		walk(condition.Expression, func(expression Expression) {
+			switch x := expression.(type) {
+			case *entityExpression:
+				checkEntity(x.uid)
+			case *isExpression:
+				checkType(x.entityType)
+			}
+		})
+	}
+
+	if len(errors) > 0 {
+		sort.Strings(errors)
+		return fmt.Errorf("invalid cedar policy: %s", strings.Join(unique(errors), ", "))
+	}
+
+	return nil
+}
+
+// ResourceKind returns the resource kind the policy is restricted to by its
+// scope, or an empty string when it may apply to any kind.
+func (p *Policy) ResourceKind() string {
+	switch p.Resource.Operator {
+	case "is":
+		return p.Resource.Type
+	case "==":
+		return p.Resource.Entities[0].Type
+	default:
+		return ""
+	}
+}
+
+func walk(expression Expression, visit func(Expression)) {
+	if expression == nil {
+		return
+	}
+
+	visit(expression)
+
+	switch x := expression.(type) {
+	case *setExpression:
+		for _, element := range x.elements {
+			walk(element, visit)
+		}
+	case *recordExpression:
+		for _, value := range x.values {
+			walk(value, visit)
+		}
+	case *unaryExpression:
+		walk(x.operand, visit)
+	case *binaryExpression:
+		walk(x.left, visit)
+		walk(x.right, visit)
+	case *ifExpression:
+		walk(x.condition, visit)
+		walk(x.then, visit)
+		walk(x.otherwise, visit)
+	case *attributeExpression:
+		walk(x.object, visit)
+	case *hasExpression:
+		walk(x.object, visit)
+	case *likeExpression:
+		walk(x.object, visit)
+	case *isExpression:
+		walk(x.object, visit)
+		walk(x.in, visit)
+	case *callExpression:
+		walk(x.object, visit)
+		for _, argument := range x.arguments {
+			walk(argument, visit)
+		}
+	}
+}
+
+func unique(values []string) []string {
+	var result []string
+
+	for i, value := range values {
+		if i == 0 || values[i-1] != value {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,59 @@
+package cedar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	// Given
+	schema := NewSchema([]string{"post"}, []string{"read", "edit"})
+
+	policy, err := ParsePolicy(`
+permit (principal in Role::"editor", action in [Action::"read", Action::"edit"], resource is post)
+when { resource in post::"1" || principal is Principal };
+`)
+	assert.Nil(t, err)
+
+	// When - Then
+	assert.Nil(t, schema.Validate(policy))
+}
+
+func TestSchema_Validate_WhenInvalid(t *testing.T) {
+	// Given
+	schema := NewSchema([]string{"post"}, []string{"read"})
+
+	testCases := []struct {
+		source        string
+		expectedError string
+	}{
+		{
+			source:        `permit (principal, action == Action::"delete", resource);`,
+			expectedError: `invalid cedar policy: unknown action "delete"`,
+		},
+		{
+			source:        `permit (principal, action, resource is comment);`,
+			expectedError: `invalid cedar policy: unknown entity type "comment"`,
+		},
+		{
+			source:        `permit (principal == post::"1", action == Role::"admin", resource);`,
+			expectedError: `invalid cedar policy: action scope expects an Action, got Role::"admin", principal scope expects a Principal or a Role, got post::"1"`,
+		},
+		{
+			source:        `permit (principal, action, resource) when { resource in comment::"1" && resource in comment::"2" };`,
+			expectedError: `invalid cedar policy: unknown entity type "comment"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		policy, err := ParsePolicy(testCase.source)
+		assert.Nil(t, err, testCase.source)
+
+		// When
+		err = schema.Validate(policy)
+
+		// Then
+		assert.EqualError(t, err, testCase.expectedError, testCase.source)
+	}
+}
@@ -0,0 +1,133 @@
+package cedar
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Value is a Cedar value: Boolean, Long, String, EntityUID, Set or Record.
+type Value interface {
+	value()
+}
+
+type (
+	Boolean bool
+	Long    int64
+	String  string
+	Set     []Value
+	Record  map[string]Value
+)
+
+// EntityUID identifies an entity by its type and identifier, e.g. Post::"123".
+type EntityUID struct {
+	Type string
+	ID   string
+}
+
+func (Boolean) value()   {}
+func (Long) value()      {}
+func (String) value()    {}
+func (Set) value()       {}
+func (Record) value()    {}
+func (EntityUID) value() {}
+
+func (e EntityUID) String() string {
+	return e.Type + "::" + strconv.Quote(e.ID)
+}
+
+// Entity is an entity known by the evaluator, with its attributes and the
+// entities it is a member of (used by the "in" operator).
+type Entity struct {
+	UID        EntityUID
+	Attributes Record
+	Parents    []EntityUID
+}
+
+// Entities indexes entities by their identifier.
+type Entities map[EntityUID]*Entity
+
+// Add registers the given entities.
+func (e Entities) Add(entities ...*Entity) {
+	for _, entity := range entities {
+		e[entity.UID] = entity
+	}
+}
+
+// AttributeValue converts an attribute value stored as a string into a Cedar
+// value: integers in their canonical form become Longs, anything else a String.
+func AttributeValue(value string) Value {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(number, 10) == value {
+		return Long(number)
+	}
+
+	return String(value)
+}
+
+func typeName(v Value) string {
+	switch v.(type) {
+	case Boolean:
+		return "bool"
+	case Long:
+		return "long"
+	case String:
+		return "string"
+	case Set:
+		return "set"
+	case Record:
+		return "record"
+	case EntityUID:
+		return "entity"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func equal(a Value, b Value) bool {
+	switch av := a.(type) {
+	case Set:
+		bv, ok := b.(Set)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		return containsAll(av, bv) && containsAll(bv, av)
+
+	case Record:
+		bv, ok := b.(Record)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+
+		for key, value := range av {
+			other, ok := bv[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+
+		return true
+
+	default:
+		return a == b
+	}
+}
+
+func contains(set Set, value Value) bool {
+	for _, element := range set {
+		if equal(element, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsAll(set Set, values Set) bool {
+	for _, value := range values {
+		if !contains(set, value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+package changelog
+
+import (
+	"context"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+type cleaner struct {
+	logger        *slog.Logger
+	clock         time.Clock
+	changeManager manager.Change
+	cleanDelay    lib_time.Duration
+	retention     lib_time.Duration
+}
+
+func NewCleaner(
+	cfg *configs.App,
+	logger *slog.Logger,
+	clock time.Clock,
+	changeManager manager.Change,
+) *cleaner {
+	return &cleaner{
+		logger:        logger,
+		clock:         clock,
+		changeManager: changeManager,
+		cleanDelay:    cfg.ChangeLogCleanDelay,
+		retention:     cfg.ChangeLogRetention,
+	}
+}
+
+func RunCleaner(lc fx.Lifecycle, cleaner *cleaner) {
+	var (
+		ticker = lib_time.NewTicker(cleaner.cleanDelay)
+		done   = make(chan struct{})
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+					}
+
+					if err := cleaner.changeManager.Prune(cleaner.clock.Now().Add(-cleaner.retention)); err != nil {
+						cleaner.logger.Error("Change log: unable to clean changes", err)
+					}
+				}
+			}()
+
+			cleaner.logger.Info("Change log: cleaner started")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			ticker.Stop()
+			close(done)
+
+			cleaner.logger.Info("Change log: cleaner stopped")
+
+			return nil
+		},
+	})
+}
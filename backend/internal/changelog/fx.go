@@ -0,0 +1,18 @@
+package changelog
+
+import (
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("changelog",
+		fx.Provide(
+			NewCleaner,
+			NewSubscriber,
+		),
+		fx.Invoke(
+			RunCleaner,
+			RunSubscriber,
+		),
+	)
+}
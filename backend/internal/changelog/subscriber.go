@@ -0,0 +1,118 @@
+package changelog
+
+import (
+	"context"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+// recordedEventTypes are the types of the events about data agents
+// synchronize.
+var recordedEventTypes = []event.EventType{
+	event.EventTypeCedarPolicy,
+	event.EventTypeDelegation,
+	event.EventTypePolicy,
+	event.EventTypePrincipal,
+	event.EventTypeResource,
+	event.EventTypeResourceKind,
+	event.EventTypeRole,
+}
+
+// subscriber records the changes made on this instance in the change log
+// agents are synchronized from. Compiled policies changes are recorded by the
+// compiler once compiled.
+type subscriber struct {
+	logger        *slog.Logger
+	dispatcher    event.Dispatcher
+	changeManager manager.Change
+}
+
+func NewSubscriber(
+	logger *slog.Logger,
+	dispatcher event.Dispatcher,
+	changeManager manager.Change,
+) *subscriber {
+	return &subscriber{
+		logger:        logger,
+		dispatcher:    dispatcher,
+		changeManager: changeManager,
+	}
+}
+
+func (s *subscriber) subscribe(lc fx.Lifecycle) {
+	var eventChans = make(map[event.EventType]chan *event.Event, len(recordedEventTypes))
+	for _, eventType := range recordedEventTypes {
+		eventChans[eventType] = s.dispatcher.Subscribe(eventType)
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for eventType, eventChan := range eventChans {
+				go s.handleItemEvents(eventType, eventChan)
+			}
+
+			s.logger.Info("Change log: subscribed to event dispatchers")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			for _, eventChan := range eventChans {
+				close(eventChan)
+			}
+
+			s.logger.Info("Change log: subscription to event dispatcher stopped")
+
+			return nil
+		},
+	})
+}
+
+func (s *subscriber) handleItemEvents(eventType event.EventType, eventChan chan *event.Event) {
+	for eventItem := range eventChan {
+		itemEvent, ok := eventItem.Data.(*event.ItemEvent)
+		if !ok {
+			continue
+		}
+
+		itemID, ok := identifier(itemEvent.Data)
+		if !ok {
+			continue
+		}
+
+		if err := s.changeManager.Record(string(eventType), itemID); err != nil {
+			s.logger.Error("Change log: unable to record change", err,
+				slog.String("item_type", string(eventType)),
+				slog.String("item_id", itemID),
+			)
+		}
+	}
+}
+
+func identifier(data any) (string, bool) {
+	switch data := data.(type) {
+	case *model.CedarPolicy:
+		return data.ID, true
+	case *model.Delegation:
+		return data.ID, true
+	case *model.Policy:
+		return data.ID, true
+	case *model.Principal:
+		return data.ID, true
+	case *model.Resource:
+		return data.ID, true
+	case *model.ResourceKind:
+		return data.ID, true
+	case *model.Role:
+		return data.ID, true
+	}
+
+	return "", false
+}
+
+func RunSubscriber(lc fx.Lifecycle, subscriber *subscriber) {
+	subscriber.subscribe(lc)
+}
@@ -0,0 +1,58 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewSubscriber(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+	dispatcher := event.NewMockDispatcher(ctrl)
+	changeManager := manager.NewMockChange(ctrl)
+
+	// When
+	subscriberInstance := NewSubscriber(logger, dispatcher, changeManager)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(subscriber), subscriberInstance)
+
+	assert.Equal(logger, subscriberInstance.logger)
+	assert.Equal(dispatcher, subscriberInstance.dispatcher)
+	assert.Equal(changeManager, subscriberInstance.changeManager)
+}
+
+func TestHandleItemEvents(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	changeManager := manager.NewMockChange(ctrl)
+	gomock.InOrder(
+		changeManager.EXPECT().Record("role", "editor").Return(nil),
+		changeManager.EXPECT().Record("role", "reader").Return(nil),
+	)
+
+	subscriberInstance := NewSubscriber(slog.New(log.NewNopHandler()), event.NewMockDispatcher(ctrl), changeManager)
+
+	eventChan := make(chan *event.Event, 3)
+
+	eventChan <- &event.Event{Data: &event.ItemEvent{Action: event.ItemActionUpdate, Data: &model.Role{ID: "editor"}}}
+	eventChan <- &event.Event{Data: &event.CheckEvent{Principal: "alice"}}
+	eventChan <- &event.Event{Data: &event.ItemEvent{Action: event.ItemActionDelete, Data: &model.Role{ID: "reader"}}}
+
+	close(eventChan)
+
+	// When - Then
+	subscriberInstance.handleItemEvents(event.EventTypeRole, eventChan)
+}
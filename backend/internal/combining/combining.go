@@ -0,0 +1,140 @@
+package combining
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+)
+
+type Algorithm string
+
+const (
+	// DenyOverrides denies access as soon as one applicable policy denies it.
+	DenyOverrides Algorithm = "deny-overrides"
+
+	// PermitOverrides allows access as soon as one applicable policy allows it.
+	PermitOverrides Algorithm = "permit-overrides"
+
+	// FirstApplicable uses the effect of the applicable policy having the highest priority.
+	FirstApplicable Algorithm = "first-applicable"
+)
+
+const (
+	kindSeparator      = ","
+	algorithmSeparator = ":"
+)
+
+var algorithms = map[Algorithm]bool{
+	DenyOverrides:   true,
+	PermitOverrides: true,
+	FirstApplicable: true,
+}
+
+// ParseAlgorithm returns the algorithm matching given name or an error if it is unknown.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	algorithm := Algorithm(strings.TrimSpace(name))
+
+	if !algorithms[algorithm] {
+		return "", fmt.Errorf("unknown combining algorithm %q", name)
+	}
+
+	return algorithm, nil
+}
+
+// Resolve applies the algorithm on the given applicable policies and returns
+// the policy that made the decision and whether access is allowed.
+//
+// Policies are considered by descending priority (and identifier for equal
+// priorities) so the decisive policy is always the same for a given set.
+// A nil policy is returned when no policy applies, which means access is denied.
+func (a Algorithm) Resolve(policies []*model.Policy) (*model.Policy, bool) {
+	if len(policies) == 0 {
+		return nil, false
+	}
+
+	sorted := make([]*model.Policy, len(policies))
+	copy(sorted, policies)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	switch a {
+	case PermitOverrides:
+		for _, policy := range sorted {
+			if !policy.IsDeny() {
+				return policy, true
+			}
+		}
+
+		return sorted[0], false
+
+	case FirstApplicable:
+		return sorted[0], !sorted[0].IsDeny()
+
+	default:
+		for _, policy := range sorted {
+			if policy.IsDeny() {
+				return policy, false
+			}
+		}
+
+		return sorted[0], true
+	}
+}
+
+// Resolver gives the combining algorithm to use for a resource kind.
+type Resolver struct {
+	defaultAlgorithm Algorithm
+	kindAlgorithms   map[string]Algorithm
+}
+
+// NewResolver initializes a resolver from the global algorithm and the per
+// resource kind ones (formatted as "kind:algorithm,other-kind:algorithm").
+func NewResolver(cfg *configs.App) (*Resolver, error) {
+	defaultAlgorithm, err := ParseAlgorithm(cfg.PolicyCombiningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var kindAlgorithms = map[string]Algorithm{}
+
+	for _, entry := range strings.Split(cfg.PolicyCombiningAlgorithmByKind, kindSeparator) {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		kind, name, found := strings.Cut(entry, algorithmSeparator)
+		if !found || strings.TrimSpace(kind) == "" {
+			return nil, fmt.Errorf("invalid combining algorithm entry %q, expected \"kind:algorithm\"", entry)
+		}
+
+		algorithm, err := ParseAlgorithm(name)
+		if err != nil {
+			return nil, err
+		}
+
+		kindAlgorithms[strings.TrimSpace(kind)] = algorithm
+	}
+
+	return &Resolver{
+		defaultAlgorithm: defaultAlgorithm,
+		kindAlgorithms:   kindAlgorithms,
+	}, nil
+}
+
+// AlgorithmFor returns the algorithm configured for given resource kind or the global one.
+func (r *Resolver) AlgorithmFor(resourceKind string) Algorithm {
+	if algorithm, ok := r.kindAlgorithms[resourceKind]; ok {
+		return algorithm
+	}
+
+	return r.defaultAlgorithm
+}
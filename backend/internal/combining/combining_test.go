@@ -0,0 +1,129 @@
+package combining
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAlgorithm(t *testing.T) {
+	// When - Then
+	algorithm, err := ParseAlgorithm("first-applicable")
+	assert.Nil(t, err)
+	assert.Equal(t, FirstApplicable, algorithm)
+
+	algorithm, err = ParseAlgorithm("unknown")
+	assert.Equal(t, Algorithm(""), algorithm)
+	assert.EqualError(t, err, `unknown combining algorithm "unknown"`)
+}
+
+func TestAlgorithm_Resolve(t *testing.T) {
+	// Given
+	allowLow := &model.Policy{ID: "allow-low", Effect: model.PolicyEffectAllow, Priority: 1}
+	allowHigh := &model.Policy{ID: "allow-high", Effect: model.PolicyEffectAllow, Priority: 10}
+	denyMedium := &model.Policy{ID: "deny-medium", Effect: model.PolicyEffectDeny, Priority: 5}
+
+	testCases := []struct {
+		name              string
+		algorithm         Algorithm
+		policies          []*model.Policy
+		expectedPolicy    *model.Policy
+		expectedIsAllowed bool
+	}{
+		{
+			name:              "No applicable policy",
+			algorithm:         DenyOverrides,
+			policies:          []*model.Policy{},
+			expectedPolicy:    nil,
+			expectedIsAllowed: false,
+		},
+		{
+			name:              "Deny overrides",
+			algorithm:         DenyOverrides,
+			policies:          []*model.Policy{allowLow, denyMedium, allowHigh},
+			expectedPolicy:    denyMedium,
+			expectedIsAllowed: false,
+		},
+		{
+			name:              "Deny overrides without deny policy",
+			algorithm:         DenyOverrides,
+			policies:          []*model.Policy{allowLow, allowHigh},
+			expectedPolicy:    allowHigh,
+			expectedIsAllowed: true,
+		},
+		{
+			name:              "Permit overrides",
+			algorithm:         PermitOverrides,
+			policies:          []*model.Policy{denyMedium, allowLow},
+			expectedPolicy:    allowLow,
+			expectedIsAllowed: true,
+		},
+		{
+			name:              "Permit overrides without allow policy",
+			algorithm:         PermitOverrides,
+			policies:          []*model.Policy{denyMedium},
+			expectedPolicy:    denyMedium,
+			expectedIsAllowed: false,
+		},
+		{
+			name:              "First applicable allows with highest priority",
+			algorithm:         FirstApplicable,
+			policies:          []*model.Policy{allowLow, denyMedium, allowHigh},
+			expectedPolicy:    allowHigh,
+			expectedIsAllowed: true,
+		},
+		{
+			name:              "First applicable denies with highest priority",
+			algorithm:         FirstApplicable,
+			policies:          []*model.Policy{allowLow, denyMedium},
+			expectedPolicy:    denyMedium,
+			expectedIsAllowed: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			policy, isAllowed := testCase.algorithm.Resolve(testCase.policies)
+
+			// Then
+			assert.Equal(t, testCase.expectedPolicy, policy)
+			assert.Equal(t, testCase.expectedIsAllowed, isAllowed)
+		})
+	}
+}
+
+func TestNewResolver(t *testing.T) {
+	// Given
+	cfg := &configs.App{
+		PolicyCombiningAlgorithm:       "deny-overrides",
+		PolicyCombiningAlgorithmByKind: "post:first-applicable, document:permit-overrides",
+	}
+
+	// When
+	resolver, err := NewResolver(cfg)
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.Equal(t, FirstApplicable, resolver.AlgorithmFor("post"))
+	assert.Equal(t, PermitOverrides, resolver.AlgorithmFor("document"))
+	assert.Equal(t, DenyOverrides, resolver.AlgorithmFor("other"))
+}
+
+func TestNewResolver_WhenInvalid(t *testing.T) {
+	// Given
+	cfg := &configs.App{
+		PolicyCombiningAlgorithm:       "deny-overrides",
+		PolicyCombiningAlgorithmByKind: "post",
+	}
+
+	// When
+	resolver, err := NewResolver(cfg)
+
+	// Then
+	assert.Nil(t, resolver)
+	assert.EqualError(t, err, `invalid combining algorithm entry "post", expected "kind:algorithm"`)
+}
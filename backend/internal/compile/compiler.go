@@ -4,10 +4,10 @@ import (
 	"fmt"
 
 	"github.com/eko/authz/backend/internal/attribute"
+	"github.com/eko/authz/backend/internal/database"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
-	"github.com/eko/authz/backend/internal/helper/time"
 )
 
 type CompileOption func(*compileOptions)
@@ -36,56 +36,140 @@ type Compiler interface {
 }
 
 type compiler struct {
-	clock            time.Clock
-	compiledManager  manager.CompiledPolicy
-	policyManager    manager.Policy
-	principalManager manager.Principal
-	resourceManager  manager.Resource
+	transactionManager database.TransactionManager
+	compiledManager    manager.CompiledPolicy
+	policyManager      manager.Policy
+	principalManager   manager.Principal
+	resourceManager    manager.Resource
+	decisionCache      *manager.DecisionCache
+	decisionStore      manager.DecisionStore
+	locker             *targetLocker
 }
 
 func NewCompiler(
-	clock time.Clock,
+	transactionManager database.TransactionManager,
 	compiledManager manager.CompiledPolicy,
 	policyManager manager.Policy,
 	principalManager manager.Principal,
 	resourceManager manager.Resource,
+	decisionCache *manager.DecisionCache,
+	decisionStore manager.DecisionStore,
 ) *compiler {
 	return &compiler{
-		clock:            clock,
-		compiledManager:  compiledManager,
-		policyManager:    policyManager,
-		principalManager: principalManager,
-		resourceManager:  resourceManager,
+		transactionManager: transactionManager,
+		compiledManager:    compiledManager,
+		policyManager:      policyManager,
+		principalManager:   principalManager,
+		resourceManager:    resourceManager,
+		decisionCache:      decisionCache,
+		decisionStore:      decisionStore,
+		locker:             newTargetLocker(),
 	}
 }
 
+// withCompiledManager returns a copy of the compiler writing compiled
+// policies using the given manager.
+func (c *compiler) withCompiledManager(compiledManager manager.CompiledPolicy) *compiler {
+	clone := *c
+	clone.compiledManager = compiledManager
+
+	return &clone
+}
+
+// compile runs the compilation of a target in a transaction with a new
+// version: checks either see all the previous compiled policies of the target
+// or all the new ones. Compilations writing the same compiled policies are
+// serialized.
+func (c *compiler) compile(kind string, identifier string, compile func(c *compiler, version int64) error) error {
+	unlock := c.locker.Lock(kind, identifier)
+	defer unlock()
+
+	transaction := c.transactionManager.New()
+	transactionCompiler := c.withCompiledManager(c.compiledManager.WithTransaction(transaction))
+
+	version, err := transactionCompiler.compiledManager.NextVersion()
+	if err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := compile(transactionCompiler, version); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit compiled policies: %v", err)
+	}
+
+	return c.compiledManager.PruneVersions(version)
+}
+
+// deleteCompiled deletes the compiled policies of a target older than the
+// given version. It is done first in the transaction so the rows stay locked
+// until the new ones are committed, on databases supporting row locks.
+func (c *compiler) deleteCompiled(fields map[string]repository.FieldValue, version int64) error {
+	fields["version"] = repository.FieldValue{Operator: "<", Value: version}
+
+	if err := c.compiledManager.GetRepository().DeleteByFields(fields); err != nil {
+		return fmt.Errorf("unable to delete compiled policies: %v", err)
+	}
+
+	return nil
+}
+
 func (c *compiler) CompilePolicy(policy *model.Policy) error {
+	var compiledPolicy *model.Policy
+
+	if err := c.compile("policy", policy.ID, func(c *compiler, version int64) (err error) {
+		compiledPolicy, err = c.compilePolicy(policy.ID, version)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := c.decisionStore.RefreshPolicy(policy.ID); err != nil {
+		return err
+	}
+
+	// Decisions may have been cached from the previous compiled policies
+	// since the policy changed.
+	c.decisionCache.InvalidatePolicy(compiledPolicy)
+
+	return nil
+}
+
+func (c *compiler) compilePolicy(policyID string, version int64) (*model.Policy, error) {
+	if err := c.deleteCompiled(map[string]repository.FieldValue{
+		"policy_id": {Operator: "=", Value: policyID},
+	}, version); err != nil {
+		return nil, err
+	}
+
 	policy, err := c.policyManager.GetRepository().Get(
-		policy.ID,
+		policyID,
 		repository.WithPreloads("Resources", "Actions"),
 	)
 	if err != nil {
-		return fmt.Errorf("cannot retrieve policy: %v", err)
+		return nil, fmt.Errorf("cannot retrieve policy: %v", err)
 	}
 
 	// In case policy has attribute rules, just compile them.
 	if len(policy.AttributeRules.Data()) > 0 {
-		return c.compilePolicyAttributes(policy)
+		return policy, c.compileAttributeRules(policy, version)
 	}
 
 	if len(policy.Resources) == 0 || len(policy.Actions) == 0 {
 		// Nothing to update
-		return nil
+		return policy, nil
 	}
 
-	version := c.clock.Now().Unix()
-
 	var compiled = make([]*model.CompiledPolicy, 0)
 	for _, resource := range policy.Resources {
 		for _, action := range policy.Actions {
 			if len(compiled) == 100 {
 				if err := c.compiledManager.Create(compiled); err != nil {
-					return err
+					return nil, err
 				}
 				compiled = make([]*model.CompiledPolicy, 0)
 			}
@@ -101,22 +185,15 @@ func (c *compiler) CompilePolicy(policy *model.Policy) error {
 	}
 
 	if len(compiled) == 0 {
-		return nil
-	}
-
-	if err := c.compiledManager.Create(compiled); err != nil {
-		return err
+		return policy, nil
 	}
 
-	return c.compiledManager.GetRepository().DeleteByFields(map[string]repository.FieldValue{
-		"policy_id": {Operator: "=", Value: policy.ID},
-		"version":   {Operator: "<", Value: version},
-	})
+	return policy, c.compiledManager.Create(compiled)
 }
 
-func (c *compiler) compilePolicyAttributes(policy *model.Policy) error {
-	version := c.clock.Now().Unix()
-
+// compileAttributeRules compiles each attribute rule of the policy, restricted to
+// the principals or resources given as options.
+func (c *compiler) compileAttributeRules(policy *model.Policy, version int64, options ...CompileOption) error {
 	for _, attributeRuleStr := range policy.AttributeRules.Data() {
 		attributeRule, err := attribute.ConvertStringToRuleOperator(attributeRuleStr)
 		if err != nil {
@@ -124,20 +201,17 @@ func (c *compiler) compilePolicyAttributes(policy *model.Policy) error {
 		}
 
 		if attributeRule.Value != "" {
-			if err := c.compilePolicyAttributesWithValue(policy, attributeRule, version); err != nil {
+			if err := c.compilePolicyAttributesWithValue(policy, attributeRule, version, options...); err != nil {
 				return err
 			}
 		} else {
-			if err := c.compilePolicyAttributesWithMatching(policy, attributeRule, version); err != nil {
+			if err := c.compilePolicyAttributesWithMatching(policy, attributeRule, version, options...); err != nil {
 				return err
 			}
 		}
 	}
 
-	return c.compiledManager.GetRepository().DeleteByFields(map[string]repository.FieldValue{
-		"policy_id": {Operator: "=", Value: policy.ID},
-		"version":   {Operator: "<", Value: version},
-	})
+	return nil
 }
 
 func (c *compiler) compilePolicyAttributesWithValue(
@@ -155,6 +229,12 @@ func (c *compiler) compilePolicyAttributesWithValue(
 		if err != nil {
 			return fmt.Errorf("cannot retrieve resources: %v", err)
 		}
+	} else {
+		opts.resources = filterResources(policy, attributeRule, opts.resources)
+	}
+
+	if len(opts.resources) == 0 {
+		return nil
 	}
 
 	if opts.principals == nil {
@@ -162,6 +242,8 @@ func (c *compiler) compilePolicyAttributesWithValue(
 		if err != nil {
 			return fmt.Errorf("cannot retrieve principals: %v", err)
 		}
+	} else {
+		opts.principals = filterPrincipals(attributeRule, opts.principals)
 	}
 
 	for _, resource := range opts.resources {
@@ -207,40 +289,20 @@ func (c *compiler) compilePolicyAttributesWithMatching(
 ) (err error) {
 	opts := applyOptions(options)
 
-	var compiled = make([]*model.CompiledPolicy, 0)
-
-	queryOptions := []repository.ResourceQueryOption{}
-
-	if len(opts.resources) > 0 {
-		var resourceIDs = make([]string, len(opts.resources))
-		for index, resource := range opts.resources {
-			resourceIDs[index] = resource.ID
-		}
-
-		queryOptions = append(queryOptions, repository.WithResourceIDs(resourceIDs))
-	}
-
-	resourcesMatches, err := c.resourceManager.GetRepository().FindMatchingAttribute(
-		attributeRule.ResourceAttribute,
-		queryOptions...,
-	)
+	resourcesMatches, principalsMatches, err := c.retrieveMatches(attributeRule, opts)
 	if err != nil {
 		return fmt.Errorf("cannot retrieve resource and principals matches: %v", err)
 	}
 
-	principalsMatches, err := c.principalManager.GetRepository().FindMatchingAttribute(
-		attributeRule.PrincipalAttribute,
-	)
-	if err != nil {
-		return fmt.Errorf("cannot retrieve resource and principals matches: %v", err)
+	var principalsByValue = map[string][]*repository.PrincipalMatchingAttribute{}
+	for _, principalMatch := range principalsMatches {
+		principalsByValue[principalMatch.AttributeValue] = append(principalsByValue[principalMatch.AttributeValue], principalMatch)
 	}
 
-	for _, resourceMatch := range resourcesMatches {
-		for _, principalMatch := range principalsMatches {
-			if resourceMatch.AttributeValue != principalMatch.AttributeValue {
-				continue
-			}
+	var compiled = make([]*model.CompiledPolicy, 0)
 
+	for _, resourceMatch := range resourcesMatches {
+		for _, principalMatch := range principalsByValue[resourceMatch.AttributeValue] {
 			for _, action := range policy.Actions {
 				if len(compiled) == 100 {
 					if err := c.compiledManager.Create(compiled); err != nil {
@@ -268,12 +330,110 @@ func (c *compiler) compilePolicyAttributesWithMatching(
 	return c.compiledManager.Create(compiled)
 }
 
+// retrieveMatches returns the resources and principals having the attributes of the rule.
+// When restricted to some principals (or resources), their attribute values are retrieved
+// first so that only the resources (or principals) having the same values are retrieved.
+func (c *compiler) retrieveMatches(
+	attributeRule *attribute.Rule,
+	opts *compileOptions,
+) ([]*repository.ResourceMatchingAttribute, []*repository.PrincipalMatchingAttribute, error) {
+	var (
+		resourceOptions  = []repository.ResourceQueryOption{}
+		principalOptions = []repository.PrincipalQueryOption{}
+	)
+
+	if opts.resources != nil {
+		var resourceIDs = make([]string, len(opts.resources))
+		for index, resource := range opts.resources {
+			resourceIDs[index] = resource.ID
+		}
+
+		resourceOptions = append(resourceOptions, repository.WithResourceIDs(resourceIDs))
+	}
+
+	if opts.principals != nil {
+		var principalIDs = make([]string, len(opts.principals))
+		for index, principal := range opts.principals {
+			principalIDs[index] = principal.ID
+		}
+
+		principalOptions = append(principalOptions, repository.WithPrincipalIDs(principalIDs))
+
+		principalsMatches, err := c.principalManager.GetRepository().FindMatchingAttribute(
+			attributeRule.PrincipalAttribute,
+			principalOptions...,
+		)
+		if err != nil || len(principalsMatches) == 0 {
+			return nil, nil, err
+		}
+
+		resourceOptions = append(resourceOptions, repository.WithResourceAttributeValues(principalsValues(principalsMatches)))
+
+		resourcesMatches, err := c.resourceManager.GetRepository().FindMatchingAttribute(
+			attributeRule.ResourceAttribute,
+			resourceOptions...,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return resourcesMatches, principalsMatches, nil
+	}
+
+	resourcesMatches, err := c.resourceManager.GetRepository().FindMatchingAttribute(
+		attributeRule.ResourceAttribute,
+		resourceOptions...,
+	)
+	if err != nil || len(resourcesMatches) == 0 {
+		return nil, nil, err
+	}
+
+	if opts.resources != nil {
+		principalOptions = append(principalOptions, repository.WithPrincipalAttributeValues(resourcesValues(resourcesMatches)))
+	}
+
+	principalsMatches, err := c.principalManager.GetRepository().FindMatchingAttribute(
+		attributeRule.PrincipalAttribute,
+		principalOptions...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resourcesMatches, principalsMatches, nil
+}
+
+func principalsValues(matches []*repository.PrincipalMatchingAttribute) []string {
+	var values = make([]string, len(matches))
+	for index, match := range matches {
+		values[index] = match.AttributeValue
+	}
+
+	return values
+}
+
+func resourcesValues(matches []*repository.ResourceMatchingAttribute) []string {
+	var values = make([]string, len(matches))
+	for index, match := range matches {
+		values[index] = match.AttributeValue
+	}
+
+	return values
+}
+
 func (c *compiler) retrieveResources(resources []*model.Resource, rule *attribute.Rule) ([]*model.Resource, error) {
-	var result = make([]*model.Resource, 0)
+	var (
+		result = make([]*model.Resource, 0)
+		seen   = map[string]bool{}
+	)
 
 	for _, resource := range resources {
 		if resource.Value != manager.WildcardValue {
-			result = append(result, resource)
+			if !seen[resource.ID] {
+				seen[resource.ID] = true
+				result = append(result, resource)
+			}
+
 			continue
 		}
 
@@ -302,17 +462,15 @@ func (c *compiler) retrieveResources(resources []*model.Resource, rule *attribut
 			return nil, err
 		}
 
-		matchingResources := []*model.Resource{}
-
 		for _, resource := range allResources {
-			if !rule.MatchResource(resource.Attributes) {
+			// Joined attributes return a resource once per attribute.
+			if seen[resource.ID] || !rule.MatchResource(resource.Attributes) {
 				continue
 			}
 
-			matchingResources = append(matchingResources, resource)
+			seen[resource.ID] = true
+			result = append(result, resource)
 		}
-
-		result = append(result, matchingResources...)
 	}
 
 	return result, nil
@@ -339,98 +497,205 @@ func (c *compiler) retrievePrincipals(rule *attribute.Rule) ([]*model.Principal,
 		return nil, err
 	}
 
-	matchingPrincipals := []*model.Principal{}
+	var (
+		matchingPrincipals = []*model.Principal{}
+		seen               = map[string]bool{}
+	)
 
 	for _, principal := range allPrincipals {
-		if !rule.MatchPrincipal(principal.Attributes) {
+		// Joined attributes return a principal once per attribute.
+		if seen[principal.ID] || !rule.MatchPrincipal(principal.Attributes) {
 			continue
 		}
 
+		seen[principal.ID] = true
 		matchingPrincipals = append(matchingPrincipals, principal)
 	}
 
 	return matchingPrincipals, nil
 }
 
+// CompilePrincipal compiles the attribute rules of all policies for the given
+// principal only: compiled policies of other principals are left untouched.
 func (c *compiler) CompilePrincipal(principal *model.Principal) error {
-	principal, err := c.principalManager.GetRepository().Get(principal.ID)
-	if err != nil {
-		return fmt.Errorf("cannot retrieve principal: %v", err)
+	if err := c.compile("principal", principal.ID, func(c *compiler, version int64) error {
+		return c.compilePrincipal(principal.ID, version)
+	}); err != nil {
+		return err
 	}
 
-	version := c.clock.Now().Unix()
+	if err := c.decisionStore.RefreshPrincipal(principal.ID); err != nil {
+		return err
+	}
 
-	policies, _, err := c.policyManager.GetRepository().Find(
-		repository.WithPreloads("Resources", "Actions"),
+	c.decisionCache.InvalidatePrincipal(principal.ID)
+
+	return nil
+}
+
+func (c *compiler) compilePrincipal(principalID string, version int64) error {
+	if err := c.deleteCompiled(map[string]repository.FieldValue{
+		"principal_id": {Operator: "=", Value: principalID},
+	}, version); err != nil {
+		return err
+	}
+
+	principal, err := c.principalManager.GetRepository().Get(
+		principalID,
+		repository.WithPreloads("Attributes"),
 	)
 	if err != nil {
-		return fmt.Errorf("cannot retrieve policies: %v", err)
+		return fmt.Errorf("cannot retrieve principal: %v", err)
 	}
 
-	for _, policy := range policies {
-		for _, attributeRuleStr := range policy.AttributeRules.Data() {
-			attributeRule, err := attribute.ConvertStringToRuleOperator(attributeRuleStr)
-			if err != nil {
-				return fmt.Errorf("cannot convert attribute rule string to object: %v", err)
-			}
+	policies, err := c.retrieveAttributePolicies()
+	if err != nil {
+		return err
+	}
 
-			if attributeRule.Value != "" {
-				if err := c.compilePolicyAttributesWithValue(policy, attributeRule, version); err != nil {
-					return err
-				}
-			} else {
-				if err := c.compilePolicyAttributesWithMatching(policy, attributeRule, version); err != nil {
-					return err
-				}
-			}
+	for _, policy := range policies {
+		if err := c.compileAttributeRules(policy, version, WithPrincipals(principal)); err != nil {
+			return err
 		}
 	}
 
-	return c.compiledManager.GetRepository().DeleteByFields(map[string]repository.FieldValue{
-		"principal_id": {Operator: "=", Value: principal.ID},
-		"version":      {Operator: "<", Value: version},
-	})
+	return nil
 }
 
+// CompileResource compiles the attribute rules of all policies for the given
+// resource only: compiled policies of other resources are left untouched.
 func (c *compiler) CompileResource(resource *model.Resource) error {
-	resource, err := c.resourceManager.GetRepository().Get(resource.ID)
+	var compiledResource *model.Resource
+
+	if err := c.compile("resource", resource.ID, func(c *compiler, version int64) (err error) {
+		compiledResource, err = c.compileResource(resource.ID, version)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := c.decisionStore.RefreshResource(compiledResource.Kind, compiledResource.Value); err != nil {
+		return err
+	}
+
+	c.decisionCache.InvalidateResource(compiledResource.Kind, compiledResource.Value)
+
+	return nil
+}
+
+func (c *compiler) compileResource(resourceID string, version int64) (*model.Resource, error) {
+	resource, err := c.resourceManager.GetRepository().Get(
+		resourceID,
+		repository.WithPreloads("Attributes"),
+	)
 	if err != nil {
-		return fmt.Errorf("cannot retrieve resource: %v", err)
+		return nil, fmt.Errorf("cannot retrieve resource: %v", err)
+	}
+
+	// Compiled policies without principal come from policies without attribute
+	// rules, they don't depend on resource attributes.
+	if err := c.deleteCompiled(map[string]repository.FieldValue{
+		"resource_kind":  {Operator: "=", Value: resource.Kind},
+		"resource_value": {Operator: "=", Value: resource.Value},
+		"principal_id":   {Operator: "<>", Value: ""},
+	}, version); err != nil {
+		return nil, err
+	}
+
+	policies, err := c.retrieveAttributePolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, policy := range policies {
+		if err := c.compileAttributeRules(policy, version, WithResources(resource)); err != nil {
+			return nil, err
+		}
 	}
 
-	version := c.clock.Now().Unix()
+	return resource, nil
+}
 
+// retrieveAttributePolicies returns the policies having attribute rules.
+func (c *compiler) retrieveAttributePolicies() ([]*model.Policy, error) {
 	policies, _, err := c.policyManager.GetRepository().Find(
 		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSkipPagination(),
 	)
 	if err != nil {
-		return fmt.Errorf("cannot retrieve policies: %v", err)
+		return nil, fmt.Errorf("cannot retrieve policies: %v", err)
 	}
 
+	var result = make([]*model.Policy, 0, len(policies))
 	for _, policy := range policies {
-		for _, attributeRuleStr := range policy.AttributeRules.Data() {
-			attributeRule, err := attribute.ConvertStringToRuleOperator(attributeRuleStr)
-			if err != nil {
-				return fmt.Errorf("cannot convert attribute rule string to object: %v", err)
+		if len(policy.AttributeRules.Data()) > 0 {
+			result = append(result, policy)
+		}
+	}
+
+	return result, nil
+}
+
+// filterResources returns the given resources the policy applies to for a rule
+// with a value, as retrieveResources would have retrieved them.
+func filterResources(policy *model.Policy, rule *attribute.Rule, resources []*model.Resource) []*model.Resource {
+	var result = make([]*model.Resource, 0, len(resources))
+
+	for _, resource := range resources {
+		if resource.Value == manager.WildcardValue {
+			continue
+		}
+
+		for _, policyResource := range policy.Resources {
+			if policyResource.ID == resource.ID {
+				result = append(result, resource)
+				break
 			}
 
-			if attributeRule.Value != "" {
-				if err := c.compilePolicyAttributesWithValue(policy, attributeRule, version, WithResources(resource)); err != nil {
-					return err
-				}
-			} else {
-				if err := c.compilePolicyAttributesWithMatching(policy, attributeRule, version, WithResources(resource)); err != nil {
-					return err
-				}
+			if policyResource.Value != manager.WildcardValue || policyResource.Kind != resource.Kind {
+				continue
+			}
+
+			if rule.ResourceAttribute != "" && !hasAttribute(resource.Attributes, rule.ResourceAttribute) {
+				continue
+			}
+
+			if rule.MatchResource(resource.Attributes) {
+				result = append(result, resource)
+				break
 			}
 		}
 	}
 
-	return c.compiledManager.GetRepository().DeleteByFields(map[string]repository.FieldValue{
-		"resource_kind":  {Operator: "=", Value: resource.Kind},
-		"resource_value": {Operator: "=", Value: resource.Value},
-		"version":        {Operator: "<", Value: version},
-	})
+	return result
+}
+
+// filterPrincipals returns the given principals matching a rule with a value,
+// as retrievePrincipals would have retrieved them.
+func filterPrincipals(rule *attribute.Rule, principals []*model.Principal) []*model.Principal {
+	var result = make([]*model.Principal, 0, len(principals))
+
+	for _, principal := range principals {
+		if rule.PrincipalAttribute != "" && !hasAttribute(principal.Attributes, rule.PrincipalAttribute) {
+			continue
+		}
+
+		if rule.MatchPrincipal(principal.Attributes) {
+			result = append(result, principal)
+		}
+	}
+
+	return result
+}
+
+func hasAttribute(attributes model.Attributes, key string) bool {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return true
+		}
+	}
+
+	return false
 }
 
 func applyOptions(options []CompileOption) *compileOptions {
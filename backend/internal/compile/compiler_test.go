@@ -1,31 +1,56 @@
 package compile
 
 import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	lib_time "time"
 
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity"
 	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper"
 	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+	"gorm.io/gorm"
 )
 
 func TestNewCompiler(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
 
-	clock := time.NewMockClock(ctrl)
+	transactionManager := database.NewTransactionManager(nil)
 	compiledManager := manager.NewMockCompiledPolicy(ctrl)
 	policyManager := manager.NewMockPolicy(ctrl)
 	principalManager := manager.NewMockPrincipal(ctrl)
 	resourceManager := manager.NewMockResource(ctrl)
+	decisionCache := manager.NewDecisionCache(&configs.App{}, time.NewMockClock(ctrl))
+	decisionStore := manager.NewMockDecisionStore(ctrl)
 
 	// When
 	compilerInstance := NewCompiler(
-		clock,
+		transactionManager,
 		compiledManager,
 		policyManager,
 		principalManager,
 		resourceManager,
+		decisionCache,
+		decisionStore,
 	)
 
 	// Then
@@ -33,9 +58,393 @@ func TestNewCompiler(t *testing.T) {
 
 	assert.IsType(new(compiler), compilerInstance)
 
-	assert.Equal(clock, compilerInstance.clock)
+	assert.Equal(transactionManager, compilerInstance.transactionManager)
 	assert.Equal(compiledManager, compilerInstance.compiledManager)
 	assert.Equal(policyManager, compilerInstance.policyManager)
 	assert.Equal(principalManager, compilerInstance.principalManager)
 	assert.Equal(resourceManager, compilerInstance.resourceManager)
+	assert.Equal(decisionCache, compilerInstance.decisionCache)
+	assert.Equal(decisionStore, compilerInstance.decisionStore)
+}
+
+// incrementClock returns a time one second later on each call.
+type incrementClock struct {
+	now lib_time.Time
+}
+
+func (c *incrementClock) Now() lib_time.Time {
+	c.now = c.now.Add(lib_time.Second)
+	return c.now
+}
+
+type compilerDependencies struct {
+	fx.In
+
+	DB                  *gorm.DB
+	TransactionManager  database.TransactionManager
+	CedarPolicyManager  manager.CedarPolicy
+	ChangeManager       manager.Change
+	CompileJobManager   manager.CompileJob
+	CompiledManager     manager.CompiledPolicy
+	DecisionCache       *manager.DecisionCache
+	DecisionStore       manager.DecisionStore
+	DelegationManager   manager.Delegation
+	Dispatcher          event.Dispatcher
+	PolicyManager       manager.Policy
+	PrincipalManager    manager.Principal
+	ResourceManager     manager.Resource
+	ResourceKindManager manager.ResourceKind
+	RoleManager         manager.Role
+}
+
+func newDatabaseCompiler(t *testing.T, options ...fx.Option) (*compiler, *compilerDependencies) {
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_NAME", filepath.Join(t.TempDir(), "authz.db"))
+	t.Setenv("LOGGER_LEVEL", "ERROR")
+
+	var deps compilerDependencies
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Provide(context.Background),
+		configs.FxModule(),
+		database.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		log.FxModule(),
+		fx.Options(options...),
+		fx.Populate(&deps),
+	)
+
+	if err := app.Start(context.Background()); err != nil {
+		t.Fatalf("unable to start application: %v", err)
+	}
+
+	t.Cleanup(func() { _ = app.Stop(context.Background()) })
+
+	compilerInstance := NewCompiler(
+		deps.TransactionManager,
+		deps.CompiledManager,
+		deps.PolicyManager,
+		deps.PrincipalManager,
+		deps.ResourceManager,
+		deps.DecisionCache,
+		deps.DecisionStore,
+	)
+
+	return compilerInstance, &deps
+}
+
+// compiledRows returns the distinct compiled policies, without their version.
+func compiledRows(t *testing.T, deps *compilerDependencies) []string {
+	compiled, _, err := deps.CompiledManager.GetRepository().Find(repository.WithSkipPagination())
+	assert.Nil(t, err)
+
+	var (
+		rows = []string{}
+		seen = map[string]bool{}
+	)
+
+	for _, item := range compiled {
+		row := strings.Join([]string{item.PolicyID, item.PrincipalID, item.ResourceKind, item.ResourceValue, item.ActionID}, "|")
+		if !seen[row] {
+			seen[row] = true
+			rows = append(rows, row)
+		}
+	}
+
+	sort.Strings(rows)
+
+	return rows
+}
+
+// rebuild compiles all policies from scratch.
+func rebuild(t *testing.T, compilerInstance *compiler, deps *compilerDependencies) {
+	assert.Nil(t, deps.CompiledManager.GetRepository().DeleteByFields(map[string]repository.FieldValue{
+		"version": {Operator: ">=", Value: 0},
+	}))
+
+	policies, _, err := deps.PolicyManager.GetRepository().Find(repository.WithSkipPagination())
+	assert.Nil(t, err)
+
+	for _, policy := range policies {
+		assert.Nil(t, compilerInstance.CompilePolicy(policy))
+	}
+}
+
+func TestCompiler_IncrementalCompilationMatchesFullRebuild(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	var (
+		random = rand.New(rand.NewSource(42))
+		teams  = []string{"blue", "green", "red"}
+	)
+
+	randomAttributes := func(prefix string) map[string]any {
+		attributes := map[string]any{}
+
+		if random.Intn(5) > 0 {
+			attributes[prefix+"team"] = teams[random.Intn(len(teams))]
+		}
+
+		if random.Intn(5) > 0 {
+			attributes["level"] = random.Intn(5)
+		}
+
+		return attributes
+	}
+
+	for i := 0; i < 8; i++ {
+		_, err := deps.PrincipalManager.Create(fmt.Sprintf("principal-%d", i), nil, randomAttributes(""))
+		assert.Nil(t, err)
+	}
+
+	for _, kind := range []string{"post", "doc"} {
+		for i := 0; i < 6; i++ {
+			_, err := deps.ResourceManager.Create(fmt.Sprintf("%s.%d", kind, i), kind, strconv.Itoa(i), randomAttributes("owner_"))
+			assert.Nil(t, err)
+		}
+	}
+
+	policies := []struct {
+		id             string
+		resources      []string
+		attributeRules []string
+	}{
+		{id: "same-team", resources: []string{"post.*"}, attributeRules: []string{"principal.team == resource.owner_team"}},
+		{id: "high-level-posts", resources: []string{"post.*"}, attributeRules: []string{"resource.level > 2"}},
+		{id: "senior-docs", resources: []string{"doc.*", "post.1"}, attributeRules: []string{"principal.level >= 3"}},
+		{id: "blue-or-green", resources: []string{"doc.2"}, attributeRules: []string{"principal.team == blue", "principal.team == green"}},
+		{id: "plain", resources: []string{"post.*"}},
+	}
+
+	for _, policy := range policies {
+		_, err := deps.PolicyManager.Create(policy.id, policy.resources, []string{"read", "edit"}, policy.attributeRules)
+		assert.Nil(t, err)
+	}
+
+	rebuild(t, compilerInstance, deps)
+
+	// When - Then
+	for step := 0; step < 30; step++ {
+		if random.Intn(2) == 0 {
+			identifier := fmt.Sprintf("principal-%d", random.Intn(10))
+
+			principal, err := deps.PrincipalManager.GetRepository().Get(identifier)
+			if err != nil {
+				principal, err = deps.PrincipalManager.Create(identifier, nil, randomAttributes(""))
+			} else {
+				principal, err = deps.PrincipalManager.Update(identifier, nil, randomAttributes(""))
+			}
+			assert.Nil(t, err)
+
+			assert.Nil(t, compilerInstance.CompilePrincipal(principal))
+		} else {
+			kind := []string{"post", "doc"}[random.Intn(2)]
+			value := strconv.Itoa(random.Intn(8))
+			identifier := kind + "." + value
+
+			resource, err := deps.ResourceManager.GetRepository().Get(identifier)
+			if err != nil {
+				resource, err = deps.ResourceManager.Create(identifier, kind, value, randomAttributes("owner_"))
+			} else {
+				resource, err = deps.ResourceManager.Update(identifier, kind, value, randomAttributes("owner_"))
+			}
+			assert.Nil(t, err)
+
+			assert.Nil(t, compilerInstance.CompileResource(resource))
+		}
+
+		incremental := compiledRows(t, deps)
+
+		rebuild(t, compilerInstance, deps)
+
+		if !assert.Equal(t, compiledRows(t, deps), incremental, "step %d", step) {
+			return
+		}
+	}
+}
+
+func TestCompiler_CompilePolicy_VersionsAreMonotonic(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.ResourceManager.Create("post.1", "post", "1", nil)
+	assert.Nil(err)
+
+	policy, err := deps.PolicyManager.Create("policy-1", []string{"post.1"}, []string{"read"}, nil)
+	assert.Nil(err)
+
+	var versions []int64
+
+	// When
+	for i := 0; i < 3; i++ {
+		assert.Nil(compilerInstance.CompilePolicy(policy))
+
+		compiled, _, err := deps.CompiledManager.GetRepository().Find(repository.WithSkipPagination())
+		assert.Nil(err)
+		assert.Len(compiled, 1)
+
+		versions = append(versions, compiled[0].Version)
+	}
+
+	// Then
+	assert.Less(versions[0], versions[1])
+	assert.Less(versions[1], versions[2])
+}
+
+func TestCompiler_CompilePolicy_WhenUpgradedFromDateVersions(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	for _, identifier := range []string{"post.1", "post.2"} {
+		_, err := deps.ResourceManager.Create(identifier, "post", strings.TrimPrefix(identifier, "post."), nil)
+		assert.Nil(err)
+	}
+
+	policy, err := deps.PolicyManager.Create("policy-1", []string{"post.1", "post.2"}, []string{"read"}, nil)
+	assert.Nil(err)
+	assert.Nil(compilerInstance.CompilePolicy(policy))
+
+	// Previous versions of the backend used Unix timestamps as versions.
+	assert.Nil(deps.DB.Model(&model.CompiledPolicy{}).Where("1 = 1").Update("version", 1674000000).Error)
+
+	_, err = database.New(&configs.Database{
+		Driver: configs.DriverSqlite,
+		Dbname: os.Getenv("DATABASE_NAME"),
+	}, slog.New(log.NewNopHandler()), time.NewClock())
+	assert.Nil(err)
+
+	policy, err = deps.PolicyManager.Update("policy-1", []string{"post.1"}, []string{"read"}, nil)
+	assert.Nil(err)
+
+	// When
+	assert.Nil(compilerInstance.CompilePolicy(policy))
+
+	// Then
+	assert.Equal([]string{"policy-1||post|1|read"}, compiledRows(t, deps))
+}
+
+func TestCompiler_CompilePolicy_IsSerialized(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	var resources = []string{}
+
+	for i := 0; i < 30; i++ {
+		identifier := fmt.Sprintf("post.%d", i)
+
+		_, err := deps.ResourceManager.Create(identifier, "post", strconv.Itoa(i), nil)
+		assert.Nil(err)
+
+		resources = append(resources, identifier)
+	}
+
+	// More compiled policies than a single batch.
+	policy, err := deps.PolicyManager.Create("policy-1", resources, []string{"read", "edit", "delete", "share"}, nil)
+	assert.Nil(err)
+
+	// When
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			assert.Nil(compilerInstance.CompilePolicy(policy))
+		}()
+	}
+
+	wg.Wait()
+
+	// Then
+	compiled, _, err := deps.CompiledManager.GetRepository().Find(repository.WithSkipPagination())
+	assert.Nil(err)
+	assert.Len(compiled, len(resources)*4)
+
+	for _, item := range compiled {
+		assert.Equal(compiled[0].Version, item.Version)
+	}
+}
+
+func TestCompiler_CompilePolicy_IsAtomic(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	var resources = []string{}
+
+	for i := 0; i < 60; i++ {
+		identifier := fmt.Sprintf("post.%d", i)
+
+		_, err := deps.ResourceManager.Create(identifier, "post", strconv.Itoa(i), nil)
+		assert.Nil(err)
+
+		resources = append(resources, identifier)
+	}
+
+	policy, err := deps.PolicyManager.Create("policy-1", resources[:30], []string{"read", "edit"}, nil)
+	assert.Nil(err)
+	assert.Nil(compilerInstance.CompilePolicy(policy))
+
+	policy, err = deps.PolicyManager.Update("policy-1", resources, []string{"read", "edit"}, nil)
+	assert.Nil(err)
+
+	var (
+		started = make(chan struct{})
+		stop    = make(chan struct{})
+		done    = make(chan struct{})
+		once    sync.Once
+		counts  = map[int64]bool{}
+	)
+
+	// Compiled policies are counted before, while and right after compiling.
+	go func() {
+		defer close(done)
+
+		for stopped := false; !stopped; {
+			if counts[60] {
+				once.Do(func() { close(started) })
+			}
+
+			select {
+			case <-stop:
+				stopped = true
+			default:
+			}
+
+			count, err := deps.CompiledManager.GetRepository().CountByFields(map[string]repository.FieldValue{
+				"policy_id": {Operator: "=", Value: "policy-1"},
+			})
+			if assert.Nil(err) {
+				counts[count] = true
+			}
+		}
+	}()
+
+	<-started
+
+	// When
+	assert.Nil(compilerInstance.CompilePolicy(policy))
+
+	close(stop)
+	<-done
+
+	// Then
+	assert.True(counts[60])
+	assert.True(counts[120])
+
+	for count := range counts {
+		assert.Contains([]int64{60, 120}, count)
+	}
 }
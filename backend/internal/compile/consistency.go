@@ -0,0 +1,151 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+)
+
+const (
+	consistencyPollDelay      = 20 * lib_time.Millisecond
+	consistencyTokenSeparator = "."
+)
+
+var (
+	ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+	ErrCompilationPending      = errors.New("compilation has not caught up with the consistency token yet")
+	ErrCompilationFailed       = errors.New("compilation failed for a change covered by the consistency token")
+)
+
+// Consistency gives the revisions consistency tokens are issued from and
+// waits for the compilation of every change made before a token.
+//
+// Revisions are allocated to changes from the compiled policies versions. A
+// token covers the changes made from a revision (taken before a write) until
+// another one (taken after it): every change made until the latter is waited
+// for, but only failures of changes made since the former fail the wait.
+type Consistency interface {
+	Revision() (int64, error)
+	Wait(ctx context.Context, token string) error
+}
+
+type consistency struct {
+	jobManager manager.CompileJob
+	timeout    lib_time.Duration
+}
+
+func NewConsistency(
+	cfg *configs.App,
+	jobManager manager.CompileJob,
+) *consistency {
+	return &consistency{
+		jobManager: jobManager,
+		timeout:    cfg.ConsistencyTimeout,
+	}
+}
+
+// Revision returns the revision of the last change made so far.
+func (c *consistency) Revision() (int64, error) {
+	return c.jobManager.LastRevision()
+}
+
+// Wait blocks until every change covered by the given token is compiled.
+// It returns ErrCompilationPending when it takes longer than the configured
+// timeout and ErrCompilationFailed when the compilation of a change made
+// since the token revision has definitely failed.
+func (c *consistency) Wait(ctx context.Context, token string) error {
+	changedSince, changedUntil, err := ParseConsistencyToken(token)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	ticker := lib_time.NewTicker(consistencyPollDelay)
+	defer ticker.Stop()
+
+	for {
+		jobs, err := c.jobManager.FindOutdated(changedUntil)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve outdated compile jobs: %v", err)
+		}
+
+		// Pending and running jobs may still succeed: only give up once they
+		// are done. Jobs failed before the token changes are not covered by it.
+		pending, failed := pendingOrFailed(jobs, changedSince)
+		if !pending && failed == nil {
+			return nil
+		}
+
+		if !pending {
+			return fmt.Errorf(
+				"%w: %s %q: %s",
+				ErrCompilationFailed,
+				failed.TargetType,
+				failed.TargetID,
+				failed.LastError,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrCompilationPending
+		case <-ticker.C:
+		}
+	}
+}
+
+// FormatConsistencyToken returns a token covering the changes made after the
+// "since" revision and until the "until" one.
+func FormatConsistencyToken(since int64, until int64) string {
+	return strconv.FormatInt(since, 36) + consistencyTokenSeparator + strconv.FormatInt(until, 36)
+}
+
+// ParseConsistencyToken returns the revisions after which and until which
+// changes are covered by the token.
+func ParseConsistencyToken(token string) (int64, int64, error) {
+	sinceValue, untilValue, ok := strings.Cut(token, consistencyTokenSeparator)
+	if !ok {
+		return 0, 0, ErrInvalidConsistencyToken
+	}
+
+	since, err := strconv.ParseInt(sinceValue, 36, 64)
+	if err != nil || since < 0 {
+		return 0, 0, ErrInvalidConsistencyToken
+	}
+
+	until, err := strconv.ParseInt(untilValue, 36, 64)
+	if err != nil || until < since {
+		return 0, 0, ErrInvalidConsistencyToken
+	}
+
+	return since, until, nil
+}
+
+// pendingOrFailed returns whether some of the given jobs are still pending or
+// running and the first failed job having changes made after the given revision.
+func pendingOrFailed(jobs []*model.CompileJob, changedSince int64) (bool, *model.CompileJob) {
+	var (
+		pending bool
+		failed  *model.CompileJob
+	)
+
+	for _, job := range jobs {
+		switch {
+		case job.Status != model.CompileJobStatusFailed:
+			pending = true
+		case failed == nil && job.Revision > changedSince:
+			failed = job
+		}
+	}
+
+	return pending, failed
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/compile/consistency.go
+
+// Package compile is a generated GoMock package.
+package compile
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockConsistency is a mock of Consistency interface.
+type MockConsistency struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsistencyMockRecorder
+}
+
+// MockConsistencyMockRecorder is the mock recorder for MockConsistency.
+type MockConsistencyMockRecorder struct {
+	mock *MockConsistency
+}
+
+// NewMockConsistency creates a new mock instance.
+func NewMockConsistency(ctrl *gomock.Controller) *MockConsistency {
+	mock := &MockConsistency{ctrl: ctrl}
+	mock.recorder = &MockConsistencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsistency) EXPECT() *MockConsistencyMockRecorder {
+	return m.recorder
+}
+
+// Revision mocks base method.
+func (m *MockConsistency) Revision() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revision indicates an expected call of Revision.
+func (mr *MockConsistencyMockRecorder) Revision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockConsistency)(nil).Revision))
+}
+
+// Wait mocks base method.
+func (m *MockConsistency) Wait(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Wait", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Wait indicates an expected call of Wait.
+func (mr *MockConsistencyMockRecorder) Wait(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wait", reflect.TypeOf((*MockConsistency)(nil).Wait), ctx, token)
+}
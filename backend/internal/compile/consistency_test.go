@@ -0,0 +1,252 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDatabaseConsistency(t *testing.T) (*consistency, manager.CompileJob) {
+	_, deps := newDatabaseCompiler(t)
+
+	cfg := &configs.App{ConsistencyTimeout: 100 * lib_time.Millisecond}
+
+	return NewConsistency(cfg, deps.CompileJobManager), deps.CompileJobManager
+}
+
+// revision returns the revision of the last change made so far.
+func revision(t *testing.T, consistency *consistency) int64 {
+	revision, err := consistency.Revision()
+	assert.Nil(t, err)
+
+	return revision
+}
+
+// issueToken returns a consistency token covering the changes made since the
+// given revision.
+func issueToken(t *testing.T, consistency *consistency, since int64) string {
+	return FormatConsistencyToken(since, revision(t, consistency))
+}
+
+func TestParseConsistencyToken(t *testing.T) {
+	// When
+	since, until, err := ParseConsistencyToken(FormatConsistencyToken(12, 345))
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(int64(12), since)
+	assert.Equal(int64(345), until)
+}
+
+func TestConsistency_Wait_WhenTokenIsInvalid(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	consistencyInstance := NewConsistency(&configs.App{}, manager.NewMockCompileJob(ctrl))
+
+	for _, token := range []string{"not a token!", "1a2b3c", "z.a", "-1.a"} {
+		// When
+		err := consistencyInstance.Wait(context.Background(), token)
+
+		// Then
+		assert.Equal(t, ErrInvalidConsistencyToken, err, token)
+	}
+}
+
+func TestConsistency_Wait_WhenCompiled(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Succeed(job))
+
+	token := issueToken(t, consistencyInstance, 0)
+
+	// Changes made after the token is issued are not waited for.
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-2"))
+
+	// When
+	err = consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.Nil(err)
+}
+
+func TestConsistency_Wait_WhenCompiledWhileWaiting(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+	consistencyInstance.timeout = 5 * lib_time.Second
+
+	assert := assert.New(t)
+
+	since := revision(t, consistencyInstance)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	token := issueToken(t, consistencyInstance, since)
+
+	go func() {
+		lib_time.Sleep(50 * lib_time.Millisecond)
+
+		job, _ := jobManager.Claim()
+		_ = jobManager.Succeed(job)
+	}()
+
+	// When
+	err := consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.Nil(err)
+}
+
+func TestConsistency_Wait_WhenPending(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	since := revision(t, consistencyInstance)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypeResource, "resource-1"))
+
+	token := issueToken(t, consistencyInstance, since)
+
+	// The target changes again before its first change is compiled: the token
+	// still has to wait for it.
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypeResource, "resource-1"))
+
+	// When
+	err := consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.Equal(ErrCompilationPending, err)
+}
+
+func TestConsistency_Wait_WhenFailed(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	since := revision(t, consistencyInstance)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	token := issueToken(t, consistencyInstance, since)
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Fail(job, errors.New("database is unavailable"), nil))
+
+	// When
+	err = consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.ErrorIs(err, ErrCompilationFailed)
+	assert.ErrorContains(err, `policy "policy-1": database is unavailable`)
+}
+
+func TestConsistency_Wait_WhenFailedBeforeToken(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Fail(job, errors.New("database is unavailable"), nil))
+
+	// Another target changes and is compiled: the failure of the first one
+	// is not covered by the token of this change.
+	since := revision(t, consistencyInstance)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-2"))
+
+	token := issueToken(t, consistencyInstance, since)
+
+	job, err = jobManager.Claim()
+	assert.Nil(err)
+	assert.Equal("policy-2", job.TargetID)
+	assert.Nil(jobManager.Succeed(job))
+
+	// When
+	err = consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.Nil(err)
+}
+
+func TestConsistency_Wait_WhenFailedThenChangedAgain(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Fail(job, errors.New("database is unavailable"), nil))
+
+	since := revision(t, consistencyInstance)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	token := issueToken(t, consistencyInstance, since)
+
+	job, err = jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Fail(job, errors.New("database is still unavailable"), nil))
+
+	// When
+	err = consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.ErrorIs(err, ErrCompilationFailed)
+	assert.ErrorContains(err, `policy "policy-1": database is still unavailable`)
+}
+
+func TestConsistency_Wait_WhenFailedJobIsSuperseded(t *testing.T) {
+	// Given
+	consistencyInstance, jobManager := newDatabaseConsistency(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	token := issueToken(t, consistencyInstance, 0)
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(jobManager.Fail(job, errors.New("database is unavailable"), nil))
+
+	// The policy is compiled later on, for instance by a rebuild.
+	assert.Nil(jobManager.Supersede(lib_time.Now().Add(lib_time.Second)))
+
+	// When
+	err = consistencyInstance.Wait(context.Background(), token)
+
+	// Then
+	assert.Nil(err)
+
+	job, err = jobManager.GetRepository().Get(strconv.FormatInt(job.ID, 10))
+	assert.Nil(err)
+	assert.Equal(model.CompileJobStatusSucceeded, job.Status)
+}
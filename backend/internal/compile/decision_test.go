@@ -0,0 +1,525 @@
+package compile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/decision"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// newDecisionFixtures creates principals, resources and policies covering
+// the different ways a check can be decided, and compiles them.
+func newDecisionFixtures(t *testing.T, options ...fx.Option) (*compiler, *compilerDependencies) {
+	compilerInstance, deps := newDatabaseCompiler(t, options...)
+
+	assert := assert.New(t)
+
+	for _, identifier := range []string{"post.1", "post.2", "doc.1"} {
+		kind, value, _ := strings.Cut(identifier, ".")
+
+		attributes := map[string]any{}
+		if kind == "doc" {
+			attributes["owner_team"] = "blue"
+		}
+
+		_, err := deps.ResourceManager.Create(identifier, kind, value, attributes)
+		assert.Nil(err)
+	}
+
+	past := lib_time.Now().Add(-1 * lib_time.Hour)
+
+	policies := []struct {
+		id             string
+		resources      []string
+		actions        []string
+		attributeRules []string
+		options        []manager.PolicyOption
+	}{
+		{id: "posts-readers", resources: []string{"post.*"}, actions: []string{"read"}},
+		{id: "post-2-deny", resources: []string{"post.2"}, actions: []string{"read"}, options: []manager.PolicyOption{manager.WithEffect(model.PolicyEffectDeny)}},
+		{id: "expired", resources: []string{"doc.1"}, actions: []string{"read"}, options: []manager.PolicyOption{manager.WithValidity(nil, &past)}},
+		{id: "same-team", resources: []string{"doc.*"}, actions: []string{"edit"}, attributeRules: []string{"principal.team == resource.owner_team"}},
+	}
+
+	for _, policy := range policies {
+		created, err := deps.PolicyManager.Create(policy.id, policy.resources, policy.actions, policy.attributeRules, policy.options...)
+		assert.Nil(err)
+
+		assert.Nil(compilerInstance.CompilePolicy(created))
+	}
+
+	_, err := deps.RoleManager.Create("readers", []string{"posts-readers", "post-2-deny", "expired"})
+	assert.Nil(err)
+
+	_, err = deps.PrincipalManager.Create("alice", []string{"readers"}, map[string]any{"team": "blue"})
+	assert.Nil(err)
+
+	_, err = deps.PrincipalManager.Create("bob", nil, map[string]any{"team": "red"})
+	assert.Nil(err)
+
+	for _, principalID := range []string{"alice", "bob"} {
+		principal, err := deps.PrincipalManager.GetRepository().Get(principalID)
+		assert.Nil(err)
+
+		assert.Nil(compilerInstance.CompilePrincipal(principal))
+	}
+
+	// As on start, roles being refreshed on their events otherwise.
+	assert.Nil(deps.DecisionStore.Load())
+
+	_, err = deps.DelegationManager.Create("alice-to-bob", "alice", "bob", []string{"post.1"}, []string{"read"}, lib_time.Now().Add(lib_time.Hour))
+	assert.Nil(err)
+
+	assert.Nil(deps.DecisionStore.RefreshDelegation("alice-to-bob"))
+
+	return compilerInstance, deps
+}
+
+// countQueries returns a function returning the number of queries run on the
+// database since countQueries has been called.
+func countQueries(t *testing.T, db *gorm.DB) func() int {
+	var count int
+
+	callback := func(*gorm.DB) { count++ }
+
+	assert.Nil(t, db.Callback().Query().After("gorm:query").Register("test:count_queries", callback))
+	assert.Nil(t, db.Callback().Row().After("gorm:row").Register("test:count_rows", callback))
+
+	t.Cleanup(func() {
+		_ = db.Callback().Query().Remove("test:count_queries")
+		_ = db.Callback().Row().Remove("test:count_rows")
+	})
+
+	return func() int {
+		return count
+	}
+}
+
+func TestCompiledPolicy_IsAllowedBulk(t *testing.T) {
+	for _, decisionStore := range []string{manager.DecisionStoreSQL, manager.DecisionStoreMemory} {
+		t.Run(decisionStore, func(t *testing.T) {
+			t.Setenv("APP_DECISION_STORE", decisionStore)
+
+			testIsAllowedBulk(t)
+		})
+	}
+}
+
+func testIsAllowedBulk(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t)
+
+	testCases := []struct {
+		check    *manager.Check
+		expected bool
+	}{
+		{check: &manager.Check{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "1", ActionID: "read"}, expected: true},
+		{check: &manager.Check{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "2", ActionID: "read"}, expected: false},
+		{check: &manager.Check{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "3", ActionID: "read"}, expected: true},
+		{check: &manager.Check{PrincipalID: "alice", ResourceKind: "doc", ResourceValue: "1", ActionID: "read"}, expected: false},
+		{check: &manager.Check{PrincipalID: "alice", ResourceKind: "doc", ResourceValue: "1", ActionID: "edit"}, expected: true},
+		{check: &manager.Check{PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"}, expected: true},
+		{check: &manager.Check{PrincipalID: "bob", ResourceKind: "post", ResourceValue: "2", ActionID: "read"}, expected: false},
+		{check: &manager.Check{PrincipalID: "bob", ResourceKind: "doc", ResourceValue: "1", ActionID: "edit"}, expected: false},
+	}
+
+	var (
+		checks   = make([]*manager.Check, len(testCases))
+		expected = make([]bool, len(testCases))
+	)
+
+	for i, testCase := range testCases {
+		checks[i] = testCase.check
+		expected[i] = testCase.expected
+	}
+
+	// When
+	results, err := deps.CompiledManager.IsAllowedBulk(checks)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(expected, results)
+
+	for i, check := range checks {
+		isAllowed, err := deps.CompiledManager.IsAllowed(check.PrincipalID, check.ResourceKind, check.ResourceValue, check.ActionID)
+		assert.Nil(err)
+		assert.Equal(expected[i], isAllowed, "check %d", i)
+	}
+}
+
+func TestCompiledPolicy_IsAllowedBulk_WhenPrincipalDoesNotExist(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t)
+
+	// When
+	results, err := deps.CompiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		{PrincipalID: "unknown", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	})
+
+	// Then
+	assert.Nil(t, results)
+	assert.ErrorContains(t, err, `unable to retrieve principal "unknown"`)
+}
+
+func TestCompiledPolicy_IsAllowedBulk_RunsAConstantNumberOfQueries(t *testing.T) {
+	for _, decisionStore := range []string{manager.DecisionStoreSQL, manager.DecisionStoreMemory} {
+		t.Run(decisionStore, func(t *testing.T) {
+			t.Setenv("APP_DECISION_STORE", decisionStore)
+
+			testIsAllowedBulkRunsAConstantNumberOfQueries(t)
+		})
+	}
+}
+
+func testIsAllowedBulkRunsAConstantNumberOfQueries(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t)
+
+	checksOf := func(count int) []*manager.Check {
+		var checks = make([]*manager.Check, count)
+
+		for i := range checks {
+			checks[i] = &manager.Check{
+				PrincipalID:   []string{"alice", "bob"}[i%2],
+				ResourceKind:  []string{"post", "doc"}[i%3%2],
+				ResourceValue: fmt.Sprint(i % 4),
+				ActionID:      []string{"read", "edit"}[i%5%2],
+			}
+		}
+
+		return checks
+	}
+
+	queries := countQueries(t, deps.DB)
+
+	// When
+	_, err := deps.CompiledManager.IsAllowedBulk(checksOf(10))
+	assert.Nil(t, err)
+
+	fewChecksQueries := queries()
+
+	_, err = deps.CompiledManager.IsAllowedBulk(checksOf(50))
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, fewChecksQueries, queries()-fewChecksQueries)
+}
+
+func TestCompiledPolicy_IsAllowedBulk_WhenMemoryDecisionStoreRunsNoQueries(t *testing.T) {
+	// Given
+	t.Setenv("APP_DECISION_STORE", manager.DecisionStoreMemory)
+
+	_, deps := newDecisionFixtures(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.CedarPolicyManager.Create("blue-docs-readers", `permit (principal, action == Action::"read", resource is doc) when { resource.owner_team == principal.team };`)
+	assert.Nil(err)
+	assert.Nil(deps.DecisionStore.RefreshCedarPolicy("blue-docs-readers"))
+
+	checks := []*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "doc", ResourceValue: "1", ActionID: "read"},
+		{PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		{PrincipalID: "bob", ResourceKind: "doc", ResourceValue: "1", ActionID: "read"},
+	}
+
+	// Registered resource kinds are loaded on the first check.
+	_, err = deps.CompiledManager.IsAllowedBulk(checks)
+	assert.Nil(err)
+
+	queries := countQueries(t, deps.DB)
+
+	// When
+	results, err := deps.CompiledManager.IsAllowedBulk(checks)
+
+	// Then
+	assert.Nil(err)
+	assert.Equal([]bool{true, true, false}, results)
+	assert.Equal(0, queries())
+}
+
+func TestCompiledPolicy_IsAllowedBulk_WhenMemoryDecisionStoreIsRefreshed(t *testing.T) {
+	// Given
+	t.Setenv("APP_DECISION_STORE", manager.DecisionStoreMemory)
+
+	compilerInstance, deps := newDecisionFixtures(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.RoleManager.Update("readers", []string{"posts-readers", "expired"})
+	assert.Nil(err)
+
+	_, err = deps.PrincipalManager.Update("bob", nil, map[string]any{"team": "blue"})
+	assert.Nil(err)
+
+	bob, err := deps.PrincipalManager.GetRepository().Get("bob")
+	assert.Nil(err)
+
+	// When
+	assert.Nil(deps.DecisionStore.RefreshRole("readers"))
+	assert.Nil(compilerInstance.CompilePrincipal(bob))
+
+	// Then
+	results, err := deps.CompiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "2", ActionID: "read"},
+		{PrincipalID: "bob", ResourceKind: "doc", ResourceValue: "1", ActionID: "edit"},
+	})
+	assert.Nil(err)
+	assert.Equal([]bool{true, true}, results)
+
+	// When
+	assert.Nil(deps.PolicyManager.Delete("posts-readers"))
+	assert.Nil(deps.DecisionStore.RefreshPolicy("posts-readers"))
+
+	// Then
+	results, err = deps.CompiledManager.IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "alice", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	})
+	assert.Nil(err)
+	assert.Equal([]bool{false}, results)
+}
+
+func TestCompiledPolicy_IsAllowedBulk_WhenItemsAreDeleted(t *testing.T) {
+	for _, decisionStore := range []string{manager.DecisionStoreSQL, manager.DecisionStoreMemory} {
+		t.Run(decisionStore, func(t *testing.T) {
+			t.Setenv("APP_DECISION_STORE", decisionStore)
+
+			testIsAllowedBulkWhenItemsAreDeleted(t)
+		})
+	}
+}
+
+func testIsAllowedBulkWhenItemsAreDeleted(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDecisionFixtures(t)
+
+	assert := assert.New(t)
+
+	isAllowed := func(principalID string, resourceKind string, resourceValue string, actionID string) bool {
+		results, err := deps.CompiledManager.IsAllowedBulk([]*manager.Check{
+			{PrincipalID: principalID, ResourceKind: resourceKind, ResourceValue: resourceValue, ActionID: actionID},
+		})
+		assert.Nil(err)
+
+		return results[0]
+	}
+
+	assert.True(isAllowed("alice", "doc", "1", "edit"))
+
+	// When - the resource is deleted
+	assert.Nil(deps.ResourceManager.Delete("doc.1"))
+	assert.Nil(deps.DecisionStore.RefreshResource("doc", "1"))
+
+	// Then
+	assert.False(isAllowed("alice", "doc", "1", "edit"))
+
+	// Given - the resource is created again for another team
+	resource, err := deps.ResourceManager.Create("doc.1", "doc", "1", map[string]any{"owner_team": "red"})
+	assert.Nil(err)
+	assert.Nil(compilerInstance.CompileResource(resource))
+
+	assert.True(isAllowed("bob", "doc", "1", "edit"))
+
+	// When - the principal is deleted and created again, not compiled yet
+	assert.Nil(deps.PrincipalManager.Delete("bob"))
+	assert.Nil(deps.DecisionStore.RefreshPrincipal("bob"))
+
+	_, err = deps.PrincipalManager.Create("bob", nil, nil)
+	assert.Nil(err)
+
+	// Then
+	assert.False(isAllowed("bob", "doc", "1", "edit"))
+
+	// When - the policy is deleted
+	assert.Nil(deps.PolicyManager.Delete("posts-readers"))
+	assert.Nil(deps.DecisionStore.RefreshPolicy("posts-readers"))
+
+	// Then
+	assert.False(isAllowed("alice", "post", "1", "read"))
+
+	// The compile jobs of deleted items are cancelled.
+	_, err = deps.CompileJobManager.GetRepository().GetByFields(map[string]repository.FieldValue{
+		"target_type": {Operator: "=", Value: model.CompileJobTargetTypePolicy},
+		"target_id":   {Operator: "=", Value: "posts-readers"},
+	})
+	assert.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestCompiledPolicy_IsAllowed_WhenDelegationEnds(t *testing.T) {
+	for _, decisionStore := range []string{manager.DecisionStoreSQL, manager.DecisionStoreMemory} {
+		t.Run(decisionStore, func(t *testing.T) {
+			t.Setenv("APP_DECISION_STORE", decisionStore)
+			t.Setenv("APP_DECISION_CACHE_SIZE", "100")
+
+			testIsAllowedWhenDelegationEnds(t)
+		})
+	}
+}
+
+func testIsAllowedWhenDelegationEnds(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t, decision.FxModule())
+
+	assert := assert.New(t)
+
+	isAllowed := func(principalID string) func() bool {
+		return func() bool {
+			isAllowed, err := deps.CompiledManager.IsAllowed(principalID, "post", "1", "read")
+			assert.Nil(err)
+
+			return isAllowed
+		}
+	}
+
+	_, err := deps.PrincipalManager.Create("carol", nil, nil)
+	assert.Nil(err)
+
+	_, err = deps.DelegationManager.Create("alice-to-carol", "alice", "carol", []string{"post.1"}, []string{"read"}, lib_time.Now().Add(lib_time.Hour))
+	assert.Nil(err)
+
+	// Decisions are allowed by delegations, and cached.
+	assert.Eventually(isAllowed("bob"), lib_time.Second, 10*lib_time.Millisecond)
+	assert.Eventually(isAllowed("carol"), lib_time.Second, 10*lib_time.Millisecond)
+
+	// When - the delegation is revoked
+	assert.Nil(deps.DelegationManager.Delete("alice-to-bob"))
+
+	// Then
+	assert.Eventually(func() bool { return !isAllowed("bob")() }, lib_time.Second, 10*lib_time.Millisecond)
+
+	// When - the delegation expires, as detected by the sweeper
+	delegation, err := deps.DelegationManager.GetRepository().Get("alice-to-carol")
+	assert.Nil(err)
+
+	delegation.ExpiresAt = lib_time.Now().Add(-1 * lib_time.Second)
+	assert.Nil(deps.DelegationManager.GetRepository().Update(delegation))
+
+	assert.True(isAllowed("carol")(), "decision is still cached")
+
+	assert.Nil(deps.Dispatcher.Dispatch(event.EventTypeDelegation, &event.ItemEvent{
+		Action: event.ItemActionExpire,
+		Data:   delegation,
+	}))
+
+	// Then
+	assert.Eventually(func() bool { return !isAllowed("carol")() }, lib_time.Second, 10*lib_time.Millisecond)
+}
+
+func TestCompiledPolicy_IsAllowed_WhenCedarPolicyOrResourceKindIsDeleted(t *testing.T) {
+	for _, decisionStore := range []string{manager.DecisionStoreSQL, manager.DecisionStoreMemory} {
+		t.Run(decisionStore, func(t *testing.T) {
+			t.Setenv("APP_DECISION_STORE", decisionStore)
+			t.Setenv("APP_DECISION_CACHE_SIZE", "100")
+
+			testIsAllowedWhenCedarPolicyOrResourceKindIsDeleted(t)
+		})
+	}
+}
+
+func testIsAllowedWhenCedarPolicyOrResourceKindIsDeleted(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t, decision.FxModule())
+
+	assert := assert.New(t)
+
+	checks := deps.Dispatcher.Subscribe(event.EventTypeCheck)
+
+	isAllowed := func() (bool, event.CacheStatus) {
+		isAllowed, err := deps.CompiledManager.IsAllowed("alice", "post", "1", "read")
+		assert.Nil(err)
+
+		checkEvent := (<-checks).Data.(*event.CheckEvent)
+
+		return isAllowed, checkEvent.CacheStatus
+	}
+
+	isCached := func() bool {
+		_, cacheStatus := isAllowed()
+		return cacheStatus == event.CacheStatusHit
+	}
+
+	_, err := deps.ResourceKindManager.Create("post", "Posts", []string{"read"}, nil)
+	assert.Nil(err)
+
+	assert.Eventually(isCached, lib_time.Second, 10*lib_time.Millisecond)
+
+	// When - the resource kind is deleted
+	assert.Nil(deps.ResourceKindManager.Delete("post"))
+
+	// Then
+	assert.Eventually(func() bool { return !isCached() }, lib_time.Second, 10*lib_time.Millisecond)
+
+	// Given - a Cedar policy forbids the decision
+	_, err = deps.CedarPolicyManager.Create("no-post-reads", `forbid (principal, action == Action::"read", resource is post);`)
+	assert.Nil(err)
+
+	assert.Eventually(func() bool {
+		allowed, cacheStatus := isAllowed()
+		return !allowed && cacheStatus == event.CacheStatusHit
+	}, lib_time.Second, 10*lib_time.Millisecond)
+
+	// When - the Cedar policy is deleted
+	assert.Nil(deps.CedarPolicyManager.Delete("no-post-reads"))
+
+	// Then
+	assert.Eventually(func() bool {
+		allowed, _ := isAllowed()
+		return allowed
+	}, lib_time.Second, 10*lib_time.Millisecond)
+}
+
+func TestCompiledPolicy_IsAllowed_WhenResourceKindIsUpdated(t *testing.T) {
+	// Given
+	_, deps := newDecisionFixtures(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.ResourceKindManager.Create("post", "Posts", []string{"read"}, nil)
+	assert.Nil(err)
+
+	var resourceKindQueries int
+
+	assert.Nil(deps.DB.Callback().Query().After("gorm:query").Register("test:count_resource_kinds", func(db *gorm.DB) {
+		if db.Statement.Table == "authz_resource_kinds" {
+			resourceKindQueries++
+		}
+	}))
+
+	t.Cleanup(func() {
+		_ = deps.DB.Callback().Query().Remove("test:count_resource_kinds")
+	})
+
+	_, err = deps.CompiledManager.IsAllowed("alice", "post", "1", "edit")
+	assert.ErrorIs(err, manager.ErrUnknownAction)
+
+	// Registered resource kinds are then kept in memory.
+	loadQueries := resourceKindQueries
+
+	for i := 0; i < 3; i++ {
+		_, err = deps.CompiledManager.IsAllowed("alice", "post", "1", "edit")
+		assert.ErrorIs(err, manager.ErrUnknownAction)
+	}
+
+	assert.Equal(loadQueries, resourceKindQueries)
+
+	// When
+	_, err = deps.ResourceKindManager.Update("post", "Posts", []string{"read", "edit"}, nil)
+	assert.Nil(err)
+
+	// Then
+	isAllowed, err := deps.CompiledManager.IsAllowed("alice", "post", "1", "edit")
+	assert.Nil(err)
+	assert.False(isAllowed)
+}
@@ -8,9 +8,28 @@ func FxModule() fx.Option {
 	return fx.Module("compile",
 		fx.Provide(
 			NewCompiler,
+			NewConsistency,
+			NewQueue,
+			NewRebuilder,
 			NewSubscriber,
 			func(compiler *compiler) Compiler { return compiler },
+			func(consistency *consistency) Consistency { return consistency },
+			func(queue *queue) Queue { return queue },
+			func(rebuilder *rebuilder) Rebuilder { return rebuilder },
 		),
+		fx.Invoke(RunQueue),
 		fx.Invoke(RunSubscriber),
 	)
 }
+
+// RebuildFxModule only provides the rebuilder of compiled policies, without
+// processing compile jobs.
+func RebuildFxModule() fx.Option {
+	return fx.Module("compile",
+		fx.Provide(
+			NewCompiler,
+			NewRebuilder,
+			func(rebuilder *rebuilder) Rebuilder { return rebuilder },
+		),
+	)
+}
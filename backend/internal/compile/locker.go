@@ -0,0 +1,69 @@
+package compile
+
+import "sync"
+
+// targetLocker serializes the compilations writing the same compiled policies.
+//
+// Compilations of targets of a same kind (for instance two principals) never
+// write the same compiled policies and run concurrently, unless they compile
+// the same target. Compilations of different kinds may write the same compiled
+// policies (a policy and a principal both compile the rows of the principal
+// for the policy) so they never run concurrently.
+type targetLocker struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	kind    string
+	running map[string]bool
+	// turn is the kind waiting for the running one to be done: compilations
+	// of the running kind started afterwards wait for it, so it never starves.
+	turn string
+}
+
+func newTargetLocker() *targetLocker {
+	locker := &targetLocker{
+		running: map[string]bool{},
+	}
+	locker.cond = sync.NewCond(&locker.mutex)
+
+	return locker
+}
+
+// Lock blocks until the given target can be compiled and returns the function
+// releasing it.
+func (l *targetLocker) Lock(kind string, identifier string) func() {
+	target := kind + ":" + identifier
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for !l.available(kind, target) {
+		if l.turn == "" && kind != l.kind {
+			l.turn = kind
+		}
+
+		l.cond.Wait()
+	}
+
+	if l.turn == kind {
+		l.turn = ""
+	}
+
+	l.kind = kind
+	l.running[target] = true
+
+	return func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		delete(l.running, target)
+		l.cond.Broadcast()
+	}
+}
+
+func (l *targetLocker) available(kind string, target string) bool {
+	if l.running[target] || (l.turn != "" && l.turn != kind) {
+		return false
+	}
+
+	return len(l.running) == 0 || l.kind == kind
+}
@@ -0,0 +1,90 @@
+package compile
+
+import (
+	"testing"
+	lib_time "time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lockAsync locks the given target in background and returns a channel
+// receiving the unlock function once locked.
+func lockAsync(locker *targetLocker, kind string, identifier string) chan func() {
+	locked := make(chan func(), 1)
+
+	go func() {
+		locked <- locker.Lock(kind, identifier)
+	}()
+
+	return locked
+}
+
+func isLocked(locked chan func()) (func(), bool) {
+	select {
+	case unlock := <-locked:
+		return unlock, true
+	case <-lib_time.After(50 * lib_time.Millisecond):
+		return nil, false
+	}
+}
+
+func TestTargetLocker_Lock_WhenSameKind(t *testing.T) {
+	// Given
+	locker := newTargetLocker()
+
+	unlock := locker.Lock("principal", "alice")
+
+	// When
+	other := lockAsync(locker, "principal", "bob")
+	same := lockAsync(locker, "principal", "alice")
+
+	// Then
+	assert := assert.New(t)
+
+	unlockOther, locked := isLocked(other)
+	assert.True(locked)
+	unlockOther()
+
+	_, locked = isLocked(same)
+	assert.False(locked)
+
+	unlock()
+
+	_, locked = isLocked(same)
+	assert.True(locked)
+}
+
+func TestTargetLocker_Lock_WhenOverlappingKinds(t *testing.T) {
+	// Given
+	locker := newTargetLocker()
+
+	unlock := locker.Lock("principal", "alice")
+
+	// When
+	policy := lockAsync(locker, "policy", "policy-1")
+
+	// Then
+	assert := assert.New(t)
+
+	_, locked := isLocked(policy)
+	assert.False(locked)
+
+	// Principals compiled after the policy is waiting wait for it.
+	principal := lockAsync(locker, "principal", "bob")
+
+	_, locked = isLocked(principal)
+	assert.False(locked)
+
+	unlock()
+
+	unlockPolicy, locked := isLocked(policy)
+	assert.True(locked)
+
+	_, locked = isLocked(principal)
+	assert.False(locked)
+
+	unlockPolicy()
+
+	_, locked = isLocked(principal)
+	assert.True(locked)
+}
@@ -0,0 +1,353 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/observability/metric"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+type Queue interface {
+	WakeUp()
+}
+
+type queue struct {
+	logger        *slog.Logger
+	clock         time.Clock
+	compiler      Compiler
+	changeManager manager.Change
+	jobManager    manager.CompileJob
+	observer      metric.Observer
+	maxAttempts   int
+	maxRetryDelay lib_time.Duration
+	pollDelay     lib_time.Duration
+	retryDelay    lib_time.Duration
+	workers       int
+	wakeUp        chan struct{}
+	done          chan struct{}
+
+	runningMutex sync.Mutex
+	running      map[int64]*runningJob
+}
+
+// runningJob is a job being compiled by a worker of this queue. When the job
+// is claimed again in the meantime, because its target changed, the claim is
+// handed over to that worker that compiles the target once more afterwards.
+type runningJob struct {
+	job     *model.CompileJob
+	claimed *model.CompileJob
+}
+
+// NewQueue initializes a queue persisting compile jobs and processing them
+// with retries.
+func NewQueue(
+	cfg *configs.App,
+	logger *slog.Logger,
+	clock time.Clock,
+	compiler Compiler,
+	changeManager manager.Change,
+	jobManager manager.CompileJob,
+	observer metric.Observer,
+) *queue {
+	workers := cfg.CompileJobWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &queue{
+		logger:        logger,
+		clock:         clock,
+		compiler:      compiler,
+		changeManager: changeManager,
+		jobManager:    jobManager,
+		observer:      observer,
+		maxAttempts:   cfg.CompileJobMaxAttempts,
+		maxRetryDelay: cfg.CompileJobMaxRetryDelay,
+		pollDelay:     cfg.CompileJobPollDelay,
+		retryDelay:    cfg.CompileJobRetryDelay,
+		workers:       workers,
+		wakeUp:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		running:       map[int64]*runningJob{},
+	}
+}
+
+// WakeUp notifies the workers that compile jobs have been enqueued.
+func (q *queue) WakeUp() {
+	select {
+	case q.wakeUp <- struct{}{}:
+	default:
+		// A worker is already going to check for pending jobs.
+	}
+}
+
+// processPending processes the pending jobs until there is no job left to run
+// or the queue is stopped.
+func (q *queue) processPending() {
+	for {
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+
+		job, err := q.jobManager.Claim()
+		if err != nil {
+			q.logger.Error("Compiler: unable to claim compile job", err)
+			return
+		}
+
+		if job == nil {
+			return
+		}
+
+		// Other jobs may be pending: an idle worker is woken up to process them.
+		q.WakeUp()
+
+		if !q.start(job) {
+			continue
+		}
+
+		q.process(job)
+	}
+}
+
+// start registers the given job as running. It returns false when the job
+// is already being compiled by another worker: the compilation is coalesced
+// with the running one, which is done once more afterwards.
+func (q *queue) start(job *model.CompileJob) bool {
+	q.runningMutex.Lock()
+	defer q.runningMutex.Unlock()
+
+	if running, ok := q.running[job.ID]; ok {
+		running.claimed = job
+		return false
+	}
+
+	q.running[job.ID] = &runningJob{job: job}
+
+	return true
+}
+
+// next returns the job claimed while the given one was compiled, or nil when
+// the job is not running anymore.
+func (q *queue) next(job *model.CompileJob) *model.CompileJob {
+	q.runningMutex.Lock()
+	defer q.runningMutex.Unlock()
+
+	running := q.running[job.ID]
+	if running.claimed == nil {
+		delete(q.running, job.ID)
+		return nil
+	}
+
+	claimed := running.claimed
+	running.job = claimed
+	running.claimed = nil
+
+	return claimed
+}
+
+// renewClaims extends the claims of the jobs being compiled by this queue, so
+// they are not processed again by another instance, and processes again the
+// jobs of instances that stopped renewing theirs.
+func (q *queue) renewClaims() {
+	q.runningMutex.Lock()
+	jobs := make([]*model.CompileJob, 0, len(q.running))
+	for _, running := range q.running {
+		jobs = append(jobs, running.job)
+		if running.claimed != nil {
+			jobs = append(jobs, running.claimed)
+		}
+	}
+	q.runningMutex.Unlock()
+
+	if err := q.jobManager.Renew(jobs); err != nil {
+		q.logger.Error("Compiler: unable to renew compile job claims", err)
+	}
+
+	if err := q.jobManager.ResetRunning(); err != nil {
+		q.logger.Error("Compiler: unable to reset expired compile jobs", err)
+	}
+}
+
+func (q *queue) process(job *model.CompileJob) {
+	err := q.compile(job)
+
+	// The target changed during the compilation: only the outcome of the
+	// latest compilation is stored.
+	for claimed := q.next(job); claimed != nil; claimed = q.next(job) {
+		job = claimed
+		err = q.compile(job)
+	}
+
+	// Agents synchronize the compiled policies of the target once the change
+	// is recorded: the job is only done once it is.
+	if err == nil {
+		err = q.changeManager.Record(string(job.TargetType), job.TargetID)
+	}
+
+	if err == nil {
+		q.finish(job, q.jobManager.Succeed(job))
+		return
+	}
+
+	var retryAt *lib_time.Time
+	if job.Attempts < q.maxAttempts {
+		nextRunAt := q.clock.Now().Add(q.retryDelayFor(job.Attempts))
+		retryAt = &nextRunAt
+	}
+
+	q.logger.Warn(
+		"Compiler: unable to compile",
+		err,
+		slog.String("target_type", string(job.TargetType)),
+		slog.String("target_id", job.TargetID),
+		slog.Int("attempts", job.Attempts),
+		slog.Bool("will_retry", retryAt != nil),
+	)
+
+	q.finish(job, q.jobManager.Fail(job, err, retryAt))
+}
+
+// finish logs the error of the update of a finished job. A job whose claim
+// was lost is processed again by the worker holding the new claim.
+func (q *queue) finish(job *model.CompileJob, err error) {
+	if errors.Is(err, manager.ErrCompileJobNotClaimed) {
+		q.logger.Debug("Compiler: compile job claimed again while running", slog.Int64("job_id", job.ID))
+	} else if err != nil {
+		q.logger.Error("Compiler: unable to update compile job", err, slog.Int64("job_id", job.ID))
+	}
+}
+
+func (q *queue) compile(job *model.CompileJob) (err error) {
+	startedAt := q.clock.Now()
+
+	defer func() {
+		if q.observer != nil {
+			q.observer.ObserveCompileDuration(string(job.TargetType), err == nil, q.clock.Now().Sub(startedAt))
+		}
+	}()
+
+	switch job.TargetType {
+	case model.CompileJobTargetTypePolicy:
+		return q.compiler.CompilePolicy(&model.Policy{ID: job.TargetID})
+	case model.CompileJobTargetTypePrincipal:
+		return q.compiler.CompilePrincipal(&model.Principal{ID: job.TargetID})
+	case model.CompileJobTargetTypeResource:
+		return q.compiler.CompileResource(&model.Resource{ID: job.TargetID})
+	}
+
+	return fmt.Errorf("unknown compile job target type %q", job.TargetType)
+}
+
+// retryDelayFor returns the delay before the next attempt of a job, doubled
+// on each attempt and capped to the maximum retry delay.
+func (q *queue) retryDelayFor(attempts int) lib_time.Duration {
+	delay := q.retryDelay
+
+	for i := 1; i < attempts && delay < q.maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > q.maxRetryDelay {
+		delay = q.maxRetryDelay
+	}
+
+	return delay
+}
+
+func (q *queue) observeQueue() {
+	if q.observer == nil {
+		return
+	}
+
+	counts, err := q.jobManager.CountByStatus()
+	if err != nil {
+		q.logger.Error("Compiler: unable to count compile jobs", err)
+		return
+	}
+
+	for status, total := range counts {
+		q.observer.ObserveCompileJobs(string(status), total)
+	}
+}
+
+func RunQueue(lc fx.Lifecycle, queue *queue) {
+	var (
+		ticker = lib_time.NewTicker(queue.pollDelay)
+		wg     sync.WaitGroup
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			// Jobs interrupted by a previous stop or crash are processed again
+			// once their claim expired.
+			if err := queue.jobManager.ResetRunning(); err != nil {
+				return err
+			}
+
+			wg.Add(queue.workers + 1)
+
+			for i := 0; i < queue.workers; i++ {
+				go func() {
+					defer wg.Done()
+
+					for {
+						select {
+						case <-queue.done:
+							return
+						case <-queue.wakeUp:
+						}
+
+						queue.processPending()
+					}
+				}()
+			}
+
+			// Jobs whose retry date is reached are processed on next tick,
+			// which also renews the claims of running jobs and refreshes the
+			// compile jobs metric.
+			go func() {
+				defer wg.Done()
+
+				for {
+					select {
+					case <-queue.done:
+						return
+					case <-ticker.C:
+					}
+
+					queue.renewClaims()
+					queue.observeQueue()
+					queue.WakeUp()
+				}
+			}()
+
+			queue.logger.Info("Compiler: compile jobs workers started", slog.Int("workers", queue.workers))
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			ticker.Stop()
+			close(queue.done)
+
+			// Running compilations are completed, remaining jobs are
+			// processed on next start.
+			wg.Wait()
+
+			queue.logger.Info("Compiler: compile jobs workers stopped")
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,446 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/internal/observability/metric"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewQueue(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		CompileJobMaxAttempts:   5,
+		CompileJobMaxRetryDelay: 5 * lib_time.Minute,
+		CompileJobPollDelay:     1 * lib_time.Second,
+		CompileJobRetryDelay:    1 * lib_time.Second,
+		CompileJobWorkers:       4,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+	clock := time.NewMockClock(ctrl)
+	compiler := NewMockCompiler(ctrl)
+	changeManager := manager.NewMockChange(ctrl)
+	jobManager := manager.NewMockCompileJob(ctrl)
+	observer := metric.NewMockObserver(ctrl)
+
+	// When
+	queueInstance := NewQueue(cfg, logger, clock, compiler, changeManager, jobManager, observer)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(queue), queueInstance)
+
+	assert.Equal(logger, queueInstance.logger)
+	assert.Equal(clock, queueInstance.clock)
+	assert.Equal(compiler, queueInstance.compiler)
+	assert.Equal(changeManager, queueInstance.changeManager)
+	assert.Equal(jobManager, queueInstance.jobManager)
+	assert.Equal(observer, queueInstance.observer)
+	assert.Equal(cfg.CompileJobMaxAttempts, queueInstance.maxAttempts)
+	assert.Equal(cfg.CompileJobMaxRetryDelay, queueInstance.maxRetryDelay)
+	assert.Equal(cfg.CompileJobPollDelay, queueInstance.pollDelay)
+	assert.Equal(cfg.CompileJobRetryDelay, queueInstance.retryDelay)
+	assert.Equal(cfg.CompileJobWorkers, queueInstance.workers)
+}
+
+func TestNewQueue_WhenNoWorkerIsConfigured(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	// When
+	queueInstance := NewQueue(&configs.App{}, slog.New(log.NewNopHandler()), time.NewMockClock(ctrl), NewMockCompiler(ctrl), manager.NewMockChange(ctrl), manager.NewMockCompileJob(ctrl), nil)
+
+	// Then
+	assert.Equal(t, 1, queueInstance.workers)
+}
+
+func TestQueue_WakeUp(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	queueInstance := NewQueue(&configs.App{}, slog.New(log.NewNopHandler()), time.NewMockClock(ctrl), NewMockCompiler(ctrl), manager.NewMockChange(ctrl), manager.NewMockCompileJob(ctrl), nil)
+
+	// When
+	queueInstance.WakeUp()
+	queueInstance.WakeUp()
+
+	// Then
+	assert.Len(t, queueInstance.wakeUp, 1, "worker should be woken up once")
+}
+
+func TestQueue_RetryDelayFor(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	queueInstance := NewQueue(&configs.App{
+		CompileJobMaxRetryDelay: 10 * lib_time.Second,
+		CompileJobRetryDelay:    1 * lib_time.Second,
+	}, slog.New(log.NewNopHandler()), time.NewMockClock(ctrl), NewMockCompiler(ctrl), manager.NewMockChange(ctrl), manager.NewMockCompileJob(ctrl), nil)
+
+	// When - Then
+	assert := assert.New(t)
+
+	assert.Equal(1*lib_time.Second, queueInstance.retryDelayFor(1))
+	assert.Equal(2*lib_time.Second, queueInstance.retryDelayFor(2))
+	assert.Equal(4*lib_time.Second, queueInstance.retryDelayFor(3))
+	assert.Equal(8*lib_time.Second, queueInstance.retryDelayFor(4))
+	assert.Equal(10*lib_time.Second, queueInstance.retryDelayFor(5))
+	assert.Equal(10*lib_time.Second, queueInstance.retryDelayFor(50))
+}
+
+func newDatabaseQueue(t *testing.T, cfg *configs.App, compiler Compiler, observer metric.Observer) (*queue, manager.CompileJob) {
+	_, deps := newDatabaseCompiler(t)
+
+	return NewQueue(cfg, slog.New(log.NewNopHandler()), time.NewClock(), compiler, deps.ChangeManager, deps.CompileJobManager, observer), deps.CompileJobManager
+}
+
+func TestQueue_ProcessPending(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiler := NewMockCompiler(ctrl)
+	compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).Return(nil)
+	compiler.EXPECT().CompilePrincipal(&model.Principal{ID: "principal-1"}).
+		Return(errors.New("database is unavailable")).
+		Times(2)
+
+	observer := metric.NewMockObserver(ctrl)
+	observer.EXPECT().ObserveCompileDuration("policy", true, gomock.Any())
+	observer.EXPECT().ObserveCompileDuration("principal", false, gomock.Any()).Times(2)
+	observer.EXPECT().ObserveCompileJobs("pending", int64(0))
+	observer.EXPECT().ObserveCompileJobs("running", int64(0))
+	observer.EXPECT().ObserveCompileJobs("succeeded", int64(1))
+	observer.EXPECT().ObserveCompileJobs("failed", int64(1))
+
+	// No retry delay so the failing job is attempted again right away.
+	queueInstance, jobManager := newDatabaseQueue(t, &configs.App{CompileJobMaxAttempts: 2}, compiler, observer)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	// When
+	queueInstance.processPending()
+	queueInstance.observeQueue()
+
+	// Then
+	jobs, _, err := jobManager.GetRepository().Find()
+	assert.Nil(err)
+	assert.Len(jobs, 2)
+
+	assert.Equal(model.CompileJobTargetTypePolicy, jobs[0].TargetType)
+	assert.Equal(model.CompileJobStatusSucceeded, jobs[0].Status)
+	assert.Equal(1, jobs[0].Attempts)
+	assert.Equal("", jobs[0].LastError)
+
+	assert.Equal(model.CompileJobTargetTypePrincipal, jobs[1].TargetType)
+	assert.Equal(model.CompileJobStatusFailed, jobs[1].Status)
+	assert.Equal(2, jobs[1].Attempts)
+	assert.Equal("database is unavailable", jobs[1].LastError)
+
+	// When - a failed job is retried
+	retried, err := jobManager.Retry(strconv.FormatInt(jobs[1].ID, 10))
+
+	// Then
+	assert.Nil(err)
+	assert.Equal(model.CompileJobStatusPending, retried.Status)
+	assert.Equal(0, retried.Attempts)
+	assert.Equal("database is unavailable", retried.LastError, "last error should be kept until success")
+}
+
+func TestQueue_ProcessPending_RecordsChanges(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiler := NewMockCompiler(ctrl)
+	compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).Return(nil)
+	compiler.EXPECT().CompilePrincipal(&model.Principal{ID: "principal-1"}).Return(nil)
+
+	_, deps := newDatabaseCompiler(t)
+
+	changeManager := manager.NewMockChange(ctrl)
+	changeManager.EXPECT().Record("policy", "policy-1").Return(nil)
+	changeManager.EXPECT().Record("principal", "principal-1").Return(errors.New("database is unavailable"))
+
+	queueInstance := NewQueue(&configs.App{CompileJobMaxAttempts: 1}, slog.New(log.NewNopHandler()), time.NewClock(), compiler, changeManager, deps.CompileJobManager, nil)
+
+	assert := assert.New(t)
+
+	assert.Nil(deps.CompileJobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+	assert.Nil(deps.CompileJobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	// When
+	queueInstance.processPending()
+
+	// Then
+	jobs, _, err := deps.CompileJobManager.GetRepository().Find()
+	assert.Nil(err)
+	assert.Len(jobs, 2)
+
+	assert.Equal(model.CompileJobStatusSucceeded, jobs[0].Status)
+
+	// Agents would not synchronize the compiled policies of the principal.
+	assert.Equal(model.CompileJobStatusFailed, jobs[1].Status)
+	assert.Equal("database is unavailable", jobs[1].LastError)
+}
+
+func TestQueue_ProcessPending_WhenRetryIsDelayed(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiler := NewMockCompiler(ctrl)
+	compiler.EXPECT().CompileResource(&model.Resource{ID: "resource-1"}).Return(errors.New("database is unavailable"))
+
+	queueInstance, jobManager := newDatabaseQueue(t, &configs.App{
+		CompileJobMaxAttempts:   5,
+		CompileJobMaxRetryDelay: 1 * lib_time.Hour,
+		CompileJobRetryDelay:    1 * lib_time.Hour,
+	}, compiler, nil)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypeResource, "resource-1"))
+
+	// When
+	queueInstance.processPending()
+
+	// Then
+	jobs, _, err := jobManager.GetRepository().Find()
+	assert.Nil(err)
+	assert.Len(jobs, 1)
+
+	assert.Equal(model.CompileJobStatusPending, jobs[0].Status)
+	assert.Equal(1, jobs[0].Attempts)
+	assert.Equal("database is unavailable", jobs[0].LastError)
+	assert.True(jobs[0].NextRunAt.After(lib_time.Now().Add(59 * lib_time.Minute)))
+}
+
+func TestQueue_ProcessPending_WhenEnqueuedWhileRunning(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiler := NewMockCompiler(ctrl)
+
+	queueInstance, jobManager := newDatabaseQueue(t, &configs.App{CompileJobMaxAttempts: 1}, compiler, nil)
+
+	// The policy changes during its first compilation: it has to be compiled again.
+	gomock.InOrder(
+		compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).DoAndReturn(func(policy *model.Policy) error {
+			return jobManager.Enqueue(model.CompileJobTargetTypePolicy, policy.ID)
+		}),
+		compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).Return(nil),
+	)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	// When
+	queueInstance.processPending()
+
+	// Then
+	jobs, _, err := jobManager.GetRepository().Find()
+	assert.Nil(err)
+	assert.Len(jobs, 1)
+
+	assert.Equal(model.CompileJobStatusSucceeded, jobs[0].Status)
+}
+
+func TestQueue_ProcessPending_WhenClaimedWhileRunning(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiler := NewMockCompiler(ctrl)
+
+	queueInstance, jobManager := newDatabaseQueue(t, &configs.App{CompileJobMaxAttempts: 1}, compiler, nil)
+
+	// The policy changes during its compilation and another worker claims its
+	// job: the compilation is coalesced with the running one.
+	gomock.InOrder(
+		compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).DoAndReturn(func(policy *model.Policy) error {
+			if err := jobManager.Enqueue(model.CompileJobTargetTypePolicy, policy.ID); err != nil {
+				return err
+			}
+
+			queueInstance.processPending()
+
+			return nil
+		}),
+		compiler.EXPECT().CompilePolicy(&model.Policy{ID: "policy-1"}).Return(nil),
+	)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePolicy, "policy-1"))
+
+	// When
+	queueInstance.processPending()
+
+	// Then
+	jobs, _, err := jobManager.GetRepository().Find()
+	assert.Nil(err)
+	assert.Len(jobs, 1)
+
+	assert.Equal(model.CompileJobStatusSucceeded, jobs[0].Status)
+	assert.Equal(1, jobs[0].Attempts)
+	assert.Empty(queueInstance.running)
+}
+
+func TestRunQueue(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	var running, maxRunning int32
+
+	compiler := NewMockCompiler(ctrl)
+	compiler.EXPECT().CompilePrincipal(gomock.Any()).DoAndReturn(func(*model.Principal) error {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+
+		lib_time.Sleep(20 * lib_time.Millisecond)
+
+		return nil
+	}).Times(20)
+
+	queueInstance, jobManager := newDatabaseQueue(t, &configs.App{
+		CompileJobMaxAttempts: 1,
+		CompileJobPollDelay:   1 * lib_time.Hour,
+		CompileJobWorkers:     4,
+	}, compiler, nil)
+
+	assert := assert.New(t)
+
+	for i := 0; i < 20; i++ {
+		assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, fmt.Sprintf("principal-%d", i)))
+	}
+
+	app := fx.New(
+		fx.NopLogger,
+		fx.Supply(queueInstance),
+		fx.Invoke(RunQueue),
+	)
+
+	// When
+	assert.Nil(app.Start(context.Background()))
+	queueInstance.WakeUp()
+
+	// Then
+	assert.Eventually(func() bool {
+		counts, err := jobManager.CountByStatus()
+		return err == nil && counts[model.CompileJobStatusSucceeded] == 20
+	}, 5*lib_time.Second, 10*lib_time.Millisecond)
+
+	assert.Nil(app.Stop(context.Background()))
+
+	assert.Greater(atomic.LoadInt32(&maxRunning), int32(1), "jobs should be compiled concurrently")
+	assert.LessOrEqual(atomic.LoadInt32(&maxRunning), int32(4), "concurrency should be bounded by the number of workers")
+}
+
+func TestQueue_ResetRunning(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	_, jobManager := newDatabaseQueue(t, &configs.App{}, NewMockCompiler(ctrl), nil)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	claimed, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Equal(model.CompileJobStatusRunning, claimed.Status)
+
+	// The claim of the job is still renewed by its instance.
+	assert.Nil(jobManager.ResetRunning())
+
+	job, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(job)
+
+	// The instance stopped renewing the claim.
+	assert.Nil(jobManager.GetRepository().DB().Model(&model.CompileJob{}).
+		Where("id = ?", claimed.ID).
+		Update("claim_expires_at", lib_time.Now().Add(-lib_time.Second)).Error)
+
+	// When
+	err = jobManager.ResetRunning()
+
+	// Then
+	assert.Nil(err)
+
+	job, err = jobManager.Claim()
+	assert.Nil(err)
+	assert.Equal(claimed.ID, job.ID)
+	assert.Equal(2, job.Attempts)
+	assert.NotEqual(claimed.ClaimID, job.ClaimID)
+
+	// The interrupted worker does not hold the claim anymore.
+	assert.Equal(manager.ErrCompileJobNotClaimed, jobManager.Succeed(claimed))
+	assert.Nil(jobManager.Succeed(job))
+}
+
+func TestQueue_FinishWhenClaimedAgain(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	_, jobManager := newDatabaseQueue(t, &configs.App{}, NewMockCompiler(ctrl), nil)
+
+	assert := assert.New(t)
+
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	first, err := jobManager.Claim()
+	assert.Nil(err)
+
+	// The target changes and the job is claimed by another instance while
+	// the first one is still compiling it.
+	assert.Nil(jobManager.Enqueue(model.CompileJobTargetTypePrincipal, "principal-1"))
+
+	second, err := jobManager.Claim()
+	assert.Nil(err)
+	assert.Equal(first.ID, second.ID)
+
+	// When
+	err = jobManager.Succeed(first)
+
+	// Then
+	assert.Equal(manager.ErrCompileJobNotClaimed, err)
+
+	outdated, err := jobManager.FindOutdated(second.Revision)
+	assert.Nil(err)
+	assert.Len(outdated, 1)
+	assert.Equal(model.CompileJobStatusRunning, outdated[0].Status)
+
+	assert.Nil(jobManager.Renew([]*model.CompileJob{first, second}))
+	assert.Nil(jobManager.Succeed(second))
+
+	outdated, err = jobManager.FindOutdated(second.Revision)
+	assert.Nil(err)
+	assert.Len(outdated, 0)
+}
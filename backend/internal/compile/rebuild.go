@@ -0,0 +1,287 @@
+package compile
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"golang.org/x/exp/slog"
+)
+
+const (
+	rebuildPageSize = 100
+
+	// rebuildLockKind locks all the compilations of this instance while
+	// compiled policies are swapped.
+	rebuildLockKind = "rebuild"
+)
+
+var ErrRebuildRunning = errors.New("compiled policies are already being rebuilt")
+
+type RebuildState string
+
+const (
+	// RebuildStateIdle means no rebuild has been started yet.
+	RebuildStateIdle RebuildState = "idle"
+	// RebuildStateRunning means policies are being compiled in the shadow table.
+	RebuildStateRunning RebuildState = "running"
+	// RebuildStateSucceeded means the shadow table has replaced compiled policies.
+	RebuildStateSucceeded RebuildState = "succeeded"
+	// RebuildStateFailed means the rebuild stopped, compiled policies are unchanged.
+	RebuildStateFailed RebuildState = "failed"
+)
+
+// RebuildStatus reports the progress of the last rebuild of compiled policies.
+type RebuildStatus struct {
+	State            RebuildState   `json:"state"`
+	TotalPolicies    int64          `json:"total_policies"`
+	CompiledPolicies int64          `json:"compiled_policies"`
+	Error            string         `json:"error,omitempty"`
+	StartedAt        *lib_time.Time `json:"started_at,omitempty"`
+	FinishedAt       *lib_time.Time `json:"finished_at,omitempty"`
+}
+
+type Rebuilder interface {
+	Rebuild() error
+	Start() (*RebuildStatus, error)
+	Status() *RebuildStatus
+}
+
+type rebuilder struct {
+	logger          *slog.Logger
+	clock           time.Clock
+	compiler        *compiler
+	changeManager   manager.Change
+	compiledManager manager.CompiledPolicy
+	jobManager      manager.CompileJob
+	policyManager   manager.Policy
+
+	mutex  sync.Mutex
+	status RebuildStatus
+}
+
+// NewRebuilder initializes a rebuilder compiling all policies from scratch
+// in a shadow table that replaces compiled policies once complete.
+func NewRebuilder(
+	logger *slog.Logger,
+	clock time.Clock,
+	compiler *compiler,
+	changeManager manager.Change,
+	compiledManager manager.CompiledPolicy,
+	jobManager manager.CompileJob,
+	policyManager manager.Policy,
+) *rebuilder {
+	return &rebuilder{
+		logger:          logger,
+		clock:           clock,
+		compiler:        compiler,
+		changeManager:   changeManager,
+		compiledManager: compiledManager,
+		jobManager:      jobManager,
+		policyManager:   policyManager,
+		status:          RebuildStatus{State: RebuildStateIdle},
+	}
+}
+
+// Rebuild rebuilds compiled policies and returns once they are replaced.
+func (r *rebuilder) Rebuild() error {
+	startedAt, err := r.begin()
+	if err != nil {
+		return err
+	}
+
+	err = r.rebuild(startedAt)
+	r.end(err)
+
+	return err
+}
+
+// Start rebuilds compiled policies in background and returns the status of
+// the rebuild it started.
+func (r *rebuilder) Start() (*RebuildStatus, error) {
+	startedAt, err := r.begin()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		r.end(r.rebuild(startedAt))
+	}()
+
+	return r.Status(), nil
+}
+
+// Status returns the status of the current or last rebuild.
+func (r *rebuilder) Status() *RebuildStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	status := r.status
+
+	return &status
+}
+
+func (r *rebuilder) begin() (lib_time.Time, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.status.State == RebuildStateRunning {
+		return lib_time.Time{}, ErrRebuildRunning
+	}
+
+	startedAt := r.clock.Now()
+
+	r.status = RebuildStatus{
+		State:     RebuildStateRunning,
+		StartedAt: &startedAt,
+	}
+
+	r.logger.Info("Compiler: rebuild of compiled policies started")
+
+	return startedAt, nil
+}
+
+func (r *rebuilder) end(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	finishedAt := r.clock.Now()
+	r.status.FinishedAt = &finishedAt
+
+	if err != nil {
+		r.status.State = RebuildStateFailed
+		r.status.Error = err.Error()
+
+		r.logger.Error("Compiler: unable to rebuild compiled policies", err)
+
+		return
+	}
+
+	r.status.State = RebuildStateSucceeded
+
+	r.logger.Info(
+		"Compiler: rebuild of compiled policies succeeded",
+		slog.Int64("compiled_policies", r.status.CompiledPolicies),
+	)
+}
+
+func (r *rebuilder) progress(compiled int, total int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.status.CompiledPolicies += int64(compiled)
+	r.status.TotalPolicies = total
+
+	r.logger.Info(
+		"Compiler: rebuilding compiled policies",
+		slog.Int64("compiled_policies", r.status.CompiledPolicies),
+		slog.Int64("total_policies", r.status.TotalPolicies),
+	)
+}
+
+func (r *rebuilder) rebuild(startedAt lib_time.Time) error {
+	shadowManager, err := r.compiledManager.NewShadow()
+	if err != nil {
+		return err
+	}
+
+	if err := r.compileAll(r.compiler.withCompiledManager(shadowManager)); err != nil {
+		if dropErr := r.compiledManager.DropShadow(); dropErr != nil {
+			r.logger.Error("Compiler: unable to drop compiled policies shadow table", dropErr)
+		}
+
+		return err
+	}
+
+	if err := r.swap(startedAt); err != nil {
+		if dropErr := r.compiledManager.DropShadow(); dropErr != nil {
+			r.logger.Error("Compiler: unable to drop compiled policies shadow table", dropErr)
+		}
+
+		return err
+	}
+
+	if err := r.compiledManager.DropShadow(); err != nil {
+		return err
+	}
+
+	// All the compiled policies have been replaced: agents load them again.
+	if err := r.changeManager.Record(model.ChangeItemTypeCompiled, ""); err != nil {
+		return err
+	}
+
+	if err := r.compiler.decisionStore.Load(); err != nil {
+		return err
+	}
+
+	r.compiler.decisionCache.Purge()
+
+	return nil
+}
+
+// swap replaces the compiled policies by the ones of the shadow table and
+// requeues the targets changed during the rebuild in the same transaction, so
+// checks never miss these changes. Compilations of this instance wait for the
+// swap to be done.
+func (r *rebuilder) swap(startedAt lib_time.Time) error {
+	unlock := r.compiler.locker.Lock(rebuildLockKind, "")
+	defer unlock()
+
+	transaction := r.compiler.transactionManager.New()
+
+	if err := r.compiledManager.WithTransaction(transaction).SwapShadow(); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	jobManager := r.jobManager.WithTransaction(transaction)
+
+	// Targets changed during the rebuild may have been compiled after the
+	// shadow table: they are compiled again in the new compiled policies.
+	if err := jobManager.Requeue(startedAt); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	// Changes that failed to compile before are compiled by the rebuild.
+	if err := jobManager.Supersede(startedAt); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit compiled policies swap: %v", err)
+	}
+
+	return nil
+}
+
+func (r *rebuilder) compileAll(compiler *compiler) error {
+	for page := int64(0); ; page++ {
+		policies, total, err := r.policyManager.GetRepository().Find(
+			repository.WithPage(page),
+			repository.WithSize(rebuildPageSize),
+			repository.WithSort("id ASC"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve policies: %v", err)
+		}
+
+		if len(policies) == 0 {
+			return nil
+		}
+
+		for _, policy := range policies {
+			if err := compiler.CompilePolicy(policy); err != nil {
+				return fmt.Errorf("unable to compile policy %q: %v", policy.ID, err)
+			}
+		}
+
+		r.progress(len(policies), total)
+	}
+}
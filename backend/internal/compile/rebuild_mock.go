@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/compile/rebuild.go
+
+// Package compile is a generated GoMock package.
+package compile
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRebuilder is a mock of Rebuilder interface.
+type MockRebuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockRebuilderMockRecorder
+}
+
+// MockRebuilderMockRecorder is the mock recorder for MockRebuilder.
+type MockRebuilderMockRecorder struct {
+	mock *MockRebuilder
+}
+
+// NewMockRebuilder creates a new mock instance.
+func NewMockRebuilder(ctrl *gomock.Controller) *MockRebuilder {
+	mock := &MockRebuilder{ctrl: ctrl}
+	mock.recorder = &MockRebuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRebuilder) EXPECT() *MockRebuilderMockRecorder {
+	return m.recorder
+}
+
+// Rebuild mocks base method.
+func (m *MockRebuilder) Rebuild() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockRebuilderMockRecorder) Rebuild() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockRebuilder)(nil).Rebuild))
+}
+
+// Start mocks base method.
+func (m *MockRebuilder) Start() (*RebuildStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(*RebuildStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRebuilderMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRebuilder)(nil).Start))
+}
+
+// Status mocks base method.
+func (m *MockRebuilder) Status() *RebuildStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(*RebuildStatus)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockRebuilderMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockRebuilder)(nil).Status))
+}
@@ -0,0 +1,239 @@
+package compile
+
+import (
+	"fmt"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewRebuilder(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+	clock := time.NewMockClock(ctrl)
+	compilerInstance := &compiler{}
+	changeManager := manager.NewMockChange(ctrl)
+	compiledManager := manager.NewMockCompiledPolicy(ctrl)
+	jobManager := manager.NewMockCompileJob(ctrl)
+	policyManager := manager.NewMockPolicy(ctrl)
+
+	// When
+	rebuilderInstance := NewRebuilder(logger, clock, compilerInstance, changeManager, compiledManager, jobManager, policyManager)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(rebuilder), rebuilderInstance)
+
+	assert.Equal(compilerInstance, rebuilderInstance.compiler)
+	assert.Equal(changeManager, rebuilderInstance.changeManager)
+	assert.Equal(compiledManager, rebuilderInstance.compiledManager)
+	assert.Equal(jobManager, rebuilderInstance.jobManager)
+	assert.Equal(policyManager, rebuilderInstance.policyManager)
+	assert.Equal(RebuildStateIdle, rebuilderInstance.Status().State)
+}
+
+func TestRebuilder_Rebuild(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.ResourceManager.Create("post.1", "post", "1", nil)
+	assert.Nil(err)
+
+	var expected = []string{}
+
+	// More policies than a single page.
+	for i := 0; i < rebuildPageSize+20; i++ {
+		identifier := fmt.Sprintf("policy-%03d", i)
+
+		_, err := deps.PolicyManager.Create(identifier, []string{"post.1"}, []string{"read"}, nil)
+		assert.Nil(err)
+
+		expected = append(expected, identifier+"||post|1|read")
+	}
+
+	// A row that does not match any policy anymore.
+	assert.Nil(deps.CompiledManager.Create([]*model.CompiledPolicy{
+		{PolicyID: "deleted-policy", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	}))
+
+	// Jobs processed before the rebuild started are left as is.
+	for {
+		job, err := deps.CompileJobManager.Claim()
+		assert.Nil(err)
+
+		if job == nil {
+			break
+		}
+
+		assert.Nil(deps.CompileJobManager.Succeed(job))
+	}
+
+	// The rebuild is considered started an hour ago: all the jobs have been
+	// processed meanwhile and must be processed again.
+	clock := &incrementClock{now: lib_time.Now().Add(-1 * lib_time.Hour)}
+
+	rebuilderInstance := NewRebuilder(
+		slog.New(log.NewNopHandler()),
+		clock,
+		compilerInstance,
+		deps.ChangeManager,
+		deps.CompiledManager,
+		deps.CompileJobManager,
+		deps.PolicyManager,
+	)
+
+	// When
+	err = rebuilderInstance.Rebuild()
+
+	// Then
+	assert.Nil(err)
+
+	assert.Equal(expected, compiledRows(t, deps))
+	assert.False(deps.CompiledManager.GetRepository().DB().Migrator().HasTable("authz_compiled_policies_shadow"))
+
+	status := rebuilderInstance.Status()
+	assert.Equal(RebuildStateSucceeded, status.State)
+	assert.Equal(int64(len(expected)), status.TotalPolicies)
+	assert.Equal(int64(len(expected)), status.CompiledPolicies)
+	assert.Empty(status.Error)
+	assert.NotNil(status.StartedAt)
+	assert.NotNil(status.FinishedAt)
+
+	jobs, _, err := deps.CompileJobManager.GetRepository().Find(repository.WithSkipPagination())
+	assert.Nil(err)
+	assert.Len(jobs, len(expected)+1)
+
+	for _, job := range jobs {
+		assert.Equal(model.CompileJobStatusPending, job.Status)
+	}
+
+	// Agents load all the compiled policies again.
+	cursor := &manager.ChangeCursor{}
+
+	changes, err := deps.ChangeManager.Next(cursor, 10)
+	assert.Nil(err)
+	assert.Len(changes, 1)
+	assert.Equal(model.ChangeItemTypeCompiled, changes[0].ItemType)
+}
+
+func TestRebuilder_Rebuild_WhenJobRunning(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.ResourceManager.Create("post.1", "post", "1", nil)
+	assert.Nil(err)
+
+	_, err = deps.PolicyManager.Create("policy-1", []string{"post.1"}, []string{"read"}, nil)
+	assert.Nil(err)
+
+	_, err = deps.PolicyManager.Create("policy-2", []string{"post.1"}, []string{"edit"}, nil)
+	assert.Nil(err)
+
+	succeeded, err := deps.CompileJobManager.Claim()
+	assert.Nil(err)
+	assert.Nil(deps.CompileJobManager.Succeed(succeeded))
+
+	// A worker is compiling this job while the rebuild swaps compiled policies.
+	running, err := deps.CompileJobManager.Claim()
+	assert.Nil(err)
+
+	rebuilderInstance := NewRebuilder(
+		slog.New(log.NewNopHandler()),
+		&incrementClock{now: lib_time.Now().Add(-1 * lib_time.Hour)},
+		compilerInstance,
+		deps.ChangeManager,
+		deps.CompiledManager,
+		deps.CompileJobManager,
+		deps.PolicyManager,
+	)
+
+	// When
+	err = rebuilderInstance.Rebuild()
+
+	// Then
+	assert.Nil(err)
+
+	// The running job is compiled again once its worker is done.
+	assert.Equal(manager.ErrCompileJobNotClaimed, deps.CompileJobManager.Succeed(running))
+
+	for _, identifier := range []int64{succeeded.ID, running.ID} {
+		job, err := deps.CompileJobManager.GetRepository().Get(fmt.Sprint(identifier))
+		assert.Nil(err)
+		assert.Equal(model.CompileJobStatusPending, job.Status)
+		assert.Equal(0, job.Attempts)
+	}
+}
+
+func TestRebuilder_Start(t *testing.T) {
+	// Given
+	compilerInstance, deps := newDatabaseCompiler(t)
+
+	assert := assert.New(t)
+
+	_, err := deps.ResourceManager.Create("post.1", "post", "1", nil)
+	assert.Nil(err)
+
+	_, err = deps.PolicyManager.Create("policy-1", []string{"post.*"}, []string{"read"}, nil)
+	assert.Nil(err)
+
+	rebuilderInstance := NewRebuilder(
+		slog.New(log.NewNopHandler()),
+		time.NewClock(),
+		compilerInstance,
+		deps.ChangeManager,
+		deps.CompiledManager,
+		deps.CompileJobManager,
+		deps.PolicyManager,
+	)
+
+	// When
+	status, err := rebuilderInstance.Start()
+
+	// Then
+	assert.Nil(err)
+	assert.Equal(RebuildStateRunning, status.State)
+
+	assert.Eventually(func() bool {
+		return rebuilderInstance.Status().State == RebuildStateSucceeded
+	}, 5*lib_time.Second, 10*lib_time.Millisecond)
+
+	assert.Equal([]string{"policy-1||post|*|read"}, compiledRows(t, deps))
+}
+
+func TestRebuilder_Start_WhenRunning(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	rebuilderInstance := NewRebuilder(
+		slog.New(log.NewNopHandler()),
+		time.NewMockClock(ctrl),
+		&compiler{},
+		manager.NewMockChange(ctrl),
+		manager.NewMockCompiledPolicy(ctrl),
+		manager.NewMockCompileJob(ctrl),
+		manager.NewMockPolicy(ctrl),
+	)
+	rebuilderInstance.status.State = RebuildStateRunning
+
+	// When
+	status, err := rebuilderInstance.Start()
+
+	// Then
+	assert.Nil(t, status)
+	assert.Equal(t, ErrRebuildRunning, err)
+}
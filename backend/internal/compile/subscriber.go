@@ -3,26 +3,29 @@ package compile
 import (
 	"context"
 
-	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/event"
 	"go.uber.org/fx"
 	"golang.org/x/exp/slog"
 )
 
+// subscriber wakes the compile jobs workers up on changes. Compile jobs are
+// enqueued by the entity managers, in the same transaction as the change.
+// Deletions don't need to be compiled: compiled policies are deleted along
+// with the deleted item.
 type subscriber struct {
 	logger     *slog.Logger
-	compiler   Compiler
+	queue      Queue
 	dispatcher event.Dispatcher
 }
 
 func NewSubscriber(
 	logger *slog.Logger,
-	compiler Compiler,
+	queue Queue,
 	dispatcher event.Dispatcher,
 ) *subscriber {
 	return &subscriber{
 		logger:     logger,
-		compiler:   compiler,
+		queue:      queue,
 		dispatcher: dispatcher,
 	}
 }
@@ -61,53 +64,38 @@ func (s *subscriber) handlePolicyEvents(eventChan chan *event.Event) {
 			continue
 		}
 
-		policy := itemEvent.Data.(*model.Policy)
+		// Time windows are evaluated at check time: compiled policies don't change.
+		if itemEvent.Action == event.ItemActionWindowOpen || itemEvent.Action == event.ItemActionWindowClose {
+			continue
+		}
 
-		if err := s.compiler.CompilePolicy(policy); err != nil {
-			s.logger.Warn(
-				"Compiler: unable to compile policy",
-				err,
-				slog.Any("policy_id", policy.ID),
-			)
+		if itemEvent.Action == event.ItemActionDelete {
+			continue
 		}
+
+		s.queue.WakeUp()
 	}
 }
 
 func (s *subscriber) handleResourceEvents(eventChan chan *event.Event) {
 	for eventItem := range eventChan {
 		itemEvent, ok := eventItem.Data.(*event.ItemEvent)
-		if !ok {
+		if !ok || itemEvent.Action == event.ItemActionDelete {
 			continue
 		}
 
-		resource := itemEvent.Data.(*model.Resource)
-
-		if err := s.compiler.CompileResource(resource); err != nil {
-			s.logger.Warn(
-				"Compiler: unable to compile resource",
-				err,
-				slog.Any("policy_id", resource.ID),
-			)
-		}
+		s.queue.WakeUp()
 	}
 }
 
 func (s *subscriber) handlePrincipalEvents(eventChan chan *event.Event) {
 	for eventItem := range eventChan {
 		itemEvent, ok := eventItem.Data.(*event.ItemEvent)
-		if !ok {
+		if !ok || itemEvent.Action == event.ItemActionDelete {
 			continue
 		}
 
-		principal := itemEvent.Data.(*model.Principal)
-
-		if err := s.compiler.CompilePrincipal(principal); err != nil {
-			s.logger.Warn(
-				"Compiler: unable to compile principal",
-				err,
-				slog.Any("policy_id", principal.ID),
-			)
-		}
+		s.queue.WakeUp()
 	}
 }
 
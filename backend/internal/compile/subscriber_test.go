@@ -17,12 +17,12 @@ func TestNewSubscriber(t *testing.T) {
 
 	logger := slog.New(log.NewNopHandler())
 
-	compiler := NewMockCompiler(ctrl)
+	queue := NewMockQueue(ctrl)
 
 	dispatcher := event.NewMockDispatcher(ctrl)
 
 	// When
-	subscriberInstance := NewSubscriber(logger, compiler, dispatcher)
+	subscriberInstance := NewSubscriber(logger, queue, dispatcher)
 
 	// Then
 	assert := assert.New(t)
@@ -30,7 +30,7 @@ func TestNewSubscriber(t *testing.T) {
 	assert.IsType(new(subscriber), subscriberInstance)
 
 	assert.Equal(logger, subscriberInstance.logger)
-	assert.Equal(compiler, subscriberInstance.compiler)
+	assert.Equal(queue, subscriberInstance.queue)
 	assert.Equal(dispatcher, subscriberInstance.dispatcher)
 }
 
@@ -42,12 +42,12 @@ func TestHandlePolicyEvents(t *testing.T) {
 
 	policy := &model.Policy{ID: "identifier-123"}
 
-	compiler := NewMockCompiler(ctrl)
-	compiler.EXPECT().CompilePolicy(policy).Return(nil)
+	queue := NewMockQueue(ctrl)
+	queue.EXPECT().WakeUp()
 
 	dispatcher := event.NewMockDispatcher(ctrl)
 
-	subscriber := NewSubscriber(logger, compiler, dispatcher)
+	subscriber := NewSubscriber(logger, queue, dispatcher)
 
 	eventChan := make(chan *event.Event)
 
@@ -63,6 +63,37 @@ func TestHandlePolicyEvents(t *testing.T) {
 	subscriber.handlePolicyEvents(eventChan)
 }
 
+func TestHandlePolicyEvents_WhenTimeWindowChanges(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+
+	policy := &model.Policy{ID: "identifier-123"}
+
+	queue := NewMockQueue(ctrl)
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+
+	subscriber := NewSubscriber(logger, queue, dispatcher)
+
+	eventChan := make(chan *event.Event)
+
+	// When - Then
+	go func() {
+		eventChan <- &event.Event{
+			Data: &event.ItemEvent{Action: event.ItemActionWindowOpen, Data: policy},
+		}
+		eventChan <- &event.Event{
+			Data: &event.ItemEvent{Action: event.ItemActionWindowClose, Data: policy},
+		}
+
+		close(eventChan)
+	}()
+
+	subscriber.handlePolicyEvents(eventChan)
+}
+
 func TestHandleResourceEvents(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
@@ -71,12 +102,12 @@ func TestHandleResourceEvents(t *testing.T) {
 
 	resource := &model.Resource{ID: "resource-123"}
 
-	compiler := NewMockCompiler(ctrl)
-	compiler.EXPECT().CompileResource(resource).Return(nil)
+	queue := NewMockQueue(ctrl)
+	queue.EXPECT().WakeUp()
 
 	dispatcher := event.NewMockDispatcher(ctrl)
 
-	subscriber := NewSubscriber(logger, compiler, dispatcher)
+	subscriber := NewSubscriber(logger, queue, dispatcher)
 
 	eventChan := make(chan *event.Event)
 
@@ -86,6 +117,11 @@ func TestHandleResourceEvents(t *testing.T) {
 			Data: &event.ItemEvent{Data: resource},
 		}
 
+		// Deletions don't need to be compiled.
+		eventChan <- &event.Event{
+			Data: &event.ItemEvent{Action: event.ItemActionDelete, Data: resource},
+		}
+
 		close(eventChan)
 	}()
 
@@ -100,12 +136,12 @@ func TestHandlePrincipalEvents(t *testing.T) {
 
 	principal := &model.Principal{ID: "principal-123"}
 
-	compiler := NewMockCompiler(ctrl)
-	compiler.EXPECT().CompilePrincipal(principal).Return(nil)
+	queue := NewMockQueue(ctrl)
+	queue.EXPECT().WakeUp()
 
 	dispatcher := event.NewMockDispatcher(ctrl)
 
-	subscriber := NewSubscriber(logger, compiler, dispatcher)
+	subscriber := NewSubscriber(logger, queue, dispatcher)
 
 	eventChan := make(chan *event.Event)
 
@@ -115,6 +151,11 @@ func TestHandlePrincipalEvents(t *testing.T) {
 			Data: &event.ItemEvent{Data: principal},
 		}
 
+		// Deletions don't need to be compiled.
+		eventChan <- &event.Event{
+			Data: &event.ItemEvent{Action: event.ItemActionDelete, Data: principal},
+		}
+
 		close(eventChan)
 	}()
 
@@ -48,23 +48,49 @@ func New(
 	}
 
 	if cfg.Driver == configs.DriverSqlite {
-		checkErr(slogLogger, db.AutoMigrate(model.Action{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Attribute{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Audit{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Client{}))
-		checkErr(slogLogger, db.AutoMigrate(model.CompiledPolicy{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Policy{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Principal{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Stats{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Resource{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Role{}))
-		checkErr(slogLogger, db.AutoMigrate(model.Token{}))
-		checkErr(slogLogger, db.AutoMigrate(model.User{}))
+		migrate(slogLogger, db)
 	}
 
+	upgrade(slogLogger, db)
+
 	return db, nil
 }
 
+// migrate creates or updates the schema of sqlite databases, other databases
+// having it created from schema files.
+func migrate(logger *slog.Logger, db *gorm.DB) {
+	checkErr(logger, db.AutoMigrate(model.Action{}))
+	checkErr(logger, db.AutoMigrate(model.Attribute{}))
+	checkErr(logger, db.AutoMigrate(model.Audit{}))
+	checkErr(logger, db.AutoMigrate(model.CedarPolicy{}))
+	checkErr(logger, db.AutoMigrate(model.Change{}))
+	checkErr(logger, db.AutoMigrate(model.Client{}))
+	checkErr(logger, db.AutoMigrate(model.CompileJob{}))
+	checkErr(logger, db.AutoMigrate(model.CompiledPolicy{}))
+	checkErr(logger, db.AutoMigrate(model.CompiledVersion{}))
+	checkErr(logger, db.AutoMigrate(model.Delegation{}))
+	checkErr(logger, db.AutoMigrate(model.Policy{}))
+	checkErr(logger, db.AutoMigrate(model.Principal{}))
+	checkErr(logger, db.AutoMigrate(model.Stats{}))
+	checkErr(logger, db.AutoMigrate(model.Resource{}))
+	checkErr(logger, db.AutoMigrate(model.ResourceKind{}))
+	checkErr(logger, db.AutoMigrate(model.ReviewCampaign{}))
+	checkErr(logger, db.AutoMigrate(model.ReviewItem{}))
+	checkErr(logger, db.AutoMigrate(model.Role{}))
+	checkErr(logger, db.AutoMigrate(model.Token{}))
+	checkErr(logger, db.AutoMigrate(model.User{}))
+}
+
+// upgrade updates data written by previous versions of the backend.
+func upgrade(logger *slog.Logger, db *gorm.DB) {
+	// Compiled policies versions used to be Unix timestamps, far greater than
+	// the versions now allocated from the authz_compiled_versions table: they
+	// are reset so the next compilation of their target replaces them.
+	checkErr(logger, db.Model(&model.CompiledPolicy{}).
+		Where("version > (?)", db.Model(&model.CompiledVersion{}).Select("COALESCE(MAX(id), 0)")).
+		Update("version", 0).Error)
+}
+
 func checkErr(logger *slog.Logger, err error) {
 	if err != nil {
 		logger.Error("Cannot migrate database", err)
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"github.com/eko/authz/backend/configs"
 	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+	"gorm.io/gorm"
 )
 
 func FxModule() fx.Option {
@@ -12,3 +15,20 @@ func FxModule() fx.Option {
 		),
 	)
 }
+
+// ExistingFxModule uses the given database instead of opening the configured
+// one. Sqlite databases are migrated like configured ones.
+func ExistingFxModule(db *gorm.DB) fx.Option {
+	return fx.Module("database",
+		fx.Provide(
+			func(logger *slog.Logger) *gorm.DB {
+				if db.Dialector.Name() == configs.DriverSqlite {
+					migrate(logger, db)
+				}
+
+				return db
+			},
+			NewTransactionManager,
+		),
+	)
+}
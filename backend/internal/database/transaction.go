@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"gorm.io/gorm"
 )
 
@@ -45,3 +47,47 @@ func (t *transaction) Commit() error {
 func (t *transaction) Rollback() error {
 	return t.db.Rollback().Error
 }
+
+// NewSavePointTransactionManager returns a transaction manager whose transactions
+// are save points inside the given parent transaction. It allows to compose
+// managers that open their own transactions into a single parent one.
+func NewSavePointTransactionManager(parent Transaction) TransactionManager {
+	return &savePointTransactionManager{
+		db: parent.DB(),
+	}
+}
+
+type savePointTransactionManager struct {
+	db      *gorm.DB
+	counter int
+}
+
+func (m *savePointTransactionManager) New() Transaction {
+	m.counter++
+
+	name := fmt.Sprintf("sp%d", m.counter)
+
+	return &savePointTransaction{
+		db:   m.db.SavePoint(name),
+		name: name,
+	}
+}
+
+type savePointTransaction struct {
+	db   *gorm.DB
+	name string
+}
+
+func (t *savePointTransaction) DB() *gorm.DB {
+	return t.db
+}
+
+// Commit keeps changes made since the save point: they will be committed
+// (or rolled back) with the parent transaction.
+func (t *savePointTransaction) Commit() error {
+	return t.db.Error
+}
+
+func (t *savePointTransaction) Rollback() error {
+	return t.db.RollbackTo(t.name).Error
+}
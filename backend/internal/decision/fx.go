@@ -0,0 +1,17 @@
+package decision
+
+import (
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("decision",
+		fx.Provide(
+			NewSubscriber,
+		),
+		fx.Invoke(
+			RunStore,
+			RunSubscriber,
+		),
+	)
+}
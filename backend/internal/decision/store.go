@@ -0,0 +1,58 @@
+package decision
+
+import (
+	"context"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+// RunStore loads the decision store on start. The memory decision store is
+// then reloaded periodically, to take into account the changes compiled by
+// other instances.
+func RunStore(
+	lc fx.Lifecycle,
+	cfg *configs.App,
+	logger *slog.Logger,
+	decisionStore manager.DecisionStore,
+) {
+	reload := cfg.DecisionStore == manager.DecisionStoreMemory && cfg.DecisionStoreReloadDelay > 0
+
+	var ticker *time.Ticker
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if err := decisionStore.Load(); err != nil {
+				return err
+			}
+
+			if !reload {
+				return nil
+			}
+
+			ticker = time.NewTicker(cfg.DecisionStoreReloadDelay)
+
+			go func() {
+				for range ticker.C {
+					if err := decisionStore.Load(); err != nil {
+						logger.Error("Decision store: unable to reload compiled policies", err)
+					}
+				}
+			}()
+
+			logger.Info("Decision store: compiled policies loaded in memory")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			if ticker != nil {
+				ticker.Stop()
+			}
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package decision
+
+import (
+	"context"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+// invalidatingEventTypes are the types of the events about data decisions
+// are computed from.
+var invalidatingEventTypes = []event.EventType{
+	event.EventTypeCedarPolicy,
+	event.EventTypeDelegation,
+	event.EventTypePolicy,
+	event.EventTypePrincipal,
+	event.EventTypeResource,
+	event.EventTypeResourceKind,
+	event.EventTypeRole,
+}
+
+// subscriber invalidates cached decisions when the data they depend on
+// changes. As compilation is asynchronous, the compiler also invalidates them
+// once a change has been compiled.
+// Changes not compiled (role policies, deletions, and the principals,
+// resources, Cedar policies and delegations checks read) are also refreshed
+// in the decision store, before decisions are invalidated.
+// Parsed Cedar policies are evicted once updated or deleted and registered
+// resource kinds are invalidated once changed, even when decisions are neither
+// cached nor kept in memory.
+type subscriber struct {
+	enabled             bool
+	logger              *slog.Logger
+	dispatcher          event.Dispatcher
+	decisionCache       *manager.DecisionCache
+	decisionStore       manager.DecisionStore
+	compiledManager     manager.CompiledPolicy
+	resourceKindManager manager.ResourceKind
+}
+
+func NewSubscriber(
+	cfg *configs.App,
+	logger *slog.Logger,
+	dispatcher event.Dispatcher,
+	decisionCache *manager.DecisionCache,
+	decisionStore manager.DecisionStore,
+	compiledManager manager.CompiledPolicy,
+	resourceKindManager manager.ResourceKind,
+) *subscriber {
+	return &subscriber{
+		enabled:             cfg.DecisionCacheSize > 0 || cfg.DecisionStore == manager.DecisionStoreMemory,
+		logger:              logger,
+		dispatcher:          dispatcher,
+		decisionCache:       decisionCache,
+		decisionStore:       decisionStore,
+		compiledManager:     compiledManager,
+		resourceKindManager: resourceKindManager,
+	}
+}
+
+func (s *subscriber) subscribe(lc fx.Lifecycle) {
+	eventTypes := invalidatingEventTypes
+	if !s.enabled {
+		eventTypes = []event.EventType{event.EventTypeCedarPolicy, event.EventTypeResourceKind}
+	}
+
+	var eventChans = make([]chan *event.Event, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventChans = append(eventChans, s.dispatcher.Subscribe(eventType))
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			for _, eventChan := range eventChans {
+				go s.handleItemEvents(eventChan)
+			}
+
+			s.logger.Info("Decision cache: subscribed to event dispatchers")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			for _, eventChan := range eventChans {
+				close(eventChan)
+			}
+
+			s.logger.Info("Decision cache: subscription to event dispatcher stopped")
+
+			return nil
+		},
+	})
+}
+
+func (s *subscriber) handleItemEvents(eventChan chan *event.Event) {
+	for eventItem := range eventChan {
+		itemEvent, ok := eventItem.Data.(*event.ItemEvent)
+		if !ok {
+			continue
+		}
+
+		switch data := itemEvent.Data.(type) {
+		case *model.CedarPolicy:
+			s.refreshed(s.decisionStore.RefreshCedarPolicy(data.ID), slog.String("cedar_policy_id", data.ID))
+
+			if itemEvent.Action != event.ItemActionCreate {
+				s.compiledManager.EvictCedarPolicy(data.ID)
+			}
+
+			s.decisionCache.InvalidateCedarPolicy(data)
+		case *model.Delegation:
+			s.refreshed(s.decisionStore.RefreshDelegation(data.ID), slog.String("delegation_id", data.ID))
+			s.decisionCache.InvalidatePrincipal(data.DelegateID)
+		case *model.Policy:
+			if itemEvent.Action == event.ItemActionDelete {
+				s.refreshed(s.decisionStore.RefreshPolicy(data.ID), slog.String("policy_id", data.ID))
+			}
+
+			s.decisionCache.InvalidatePolicy(data)
+		case *model.Principal:
+			s.refreshed(s.decisionStore.RefreshPrincipal(data.ID), slog.String("principal_id", data.ID))
+			s.decisionCache.InvalidatePrincipal(data.ID)
+		case *model.Resource:
+			s.refreshed(s.decisionStore.RefreshResource(data.Kind, data.Value), slog.String("resource_id", data.ID))
+			s.decisionCache.InvalidateResource(data.Kind, data.Value)
+		case *model.ResourceKind:
+			s.resourceKindManager.InvalidateKinds()
+			s.decisionCache.InvalidateResource(data.ID, manager.WildcardValue)
+		case *model.Role:
+			s.refreshed(s.decisionStore.RefreshRole(data.ID), slog.String("role_id", data.ID))
+			s.decisionCache.InvalidateRole(data.ID)
+		}
+	}
+}
+
+func (s *subscriber) refreshed(err error, attribute slog.Attr) {
+	if err != nil {
+		s.logger.Error("Decision store: unable to refresh decision data", err, attribute)
+	}
+}
+
+func RunSubscriber(lc fx.Lifecycle, subscriber *subscriber) {
+	subscriber.subscribe(lc)
+}
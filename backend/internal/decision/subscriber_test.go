@@ -0,0 +1,135 @@
+package decision
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewSubscriber(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{DecisionCacheSize: 100}
+	logger := slog.New(log.NewNopHandler())
+	dispatcher := event.NewMockDispatcher(ctrl)
+	decisionCache := manager.NewDecisionCache(cfg, nil)
+	decisionStore := manager.NewMockDecisionStore(ctrl)
+	compiledManager := manager.NewMockCompiledPolicy(ctrl)
+	resourceKindManager := manager.NewMockResourceKind(ctrl)
+
+	// When
+	subscriberInstance := NewSubscriber(cfg, logger, dispatcher, decisionCache, decisionStore, compiledManager, resourceKindManager)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(subscriber), subscriberInstance)
+
+	assert.True(subscriberInstance.enabled)
+	assert.Equal(logger, subscriberInstance.logger)
+	assert.Equal(dispatcher, subscriberInstance.dispatcher)
+	assert.Equal(decisionCache, subscriberInstance.decisionCache)
+	assert.Equal(decisionStore, subscriberInstance.decisionStore)
+	assert.Equal(compiledManager, subscriberInstance.compiledManager)
+	assert.Equal(resourceKindManager, subscriberInstance.resourceKindManager)
+}
+
+func TestNewSubscriber_WhenMemoryDecisionStore(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{DecisionStore: manager.DecisionStoreMemory}
+
+	// When
+	subscriberInstance := NewSubscriber(
+		cfg,
+		slog.New(log.NewNopHandler()),
+		event.NewMockDispatcher(ctrl),
+		manager.NewDecisionCache(cfg, nil),
+		manager.NewMockDecisionStore(ctrl),
+		manager.NewMockCompiledPolicy(ctrl),
+		manager.NewMockResourceKind(ctrl),
+	)
+
+	// Then
+	assert.True(t, subscriberInstance.enabled)
+}
+
+func TestHandleItemEvents(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{DecisionCacheSize: 100}
+
+	decisionStore := manager.NewMockDecisionStore(ctrl)
+	decisionStore.EXPECT().RefreshCedarPolicy("cedar-1").Return(nil)
+	decisionStore.EXPECT().RefreshCedarPolicy("cedar-2").Return(nil)
+	decisionStore.EXPECT().RefreshDelegation("delegation-1").Return(nil)
+	decisionStore.EXPECT().RefreshPolicy("policy-2").Return(nil)
+	decisionStore.EXPECT().RefreshPrincipal("user-1").Return(nil)
+	decisionStore.EXPECT().RefreshPrincipal("user-2").Return(nil)
+	decisionStore.EXPECT().RefreshResource("post", "1").Return(nil)
+	decisionStore.EXPECT().RefreshResource("post", "2").Return(nil)
+	decisionStore.EXPECT().RefreshRole("role-1").Return(nil)
+
+	compiledManager := manager.NewMockCompiledPolicy(ctrl)
+	compiledManager.EXPECT().EvictCedarPolicy("cedar-1")
+	compiledManager.EXPECT().EvictCedarPolicy("cedar-2")
+
+	resourceKindManager := manager.NewMockResourceKind(ctrl)
+	resourceKindManager.EXPECT().InvalidateKinds().Times(2)
+
+	subscriber := NewSubscriber(
+		cfg,
+		slog.New(log.NewNopHandler()),
+		event.NewMockDispatcher(ctrl),
+		manager.NewDecisionCache(cfg, nil),
+		decisionStore,
+		compiledManager,
+		resourceKindManager,
+	)
+
+	eventChan := make(chan *event.Event)
+
+	// When - Then
+	go func() {
+		for _, data := range []any{
+			&model.CedarPolicy{ID: "cedar-1", ResourceKind: "post"},
+			&model.Delegation{ID: "delegation-1", DelegateID: "user-1"},
+			&model.Policy{ID: "policy-1"},
+			&model.Principal{ID: "user-1"},
+			&model.Resource{Kind: "post", Value: "1"},
+			&model.ResourceKind{ID: "post"},
+			&model.Role{ID: "role-1"},
+			"unexpected data",
+		} {
+			eventChan <- &event.Event{
+				Data: &event.ItemEvent{Action: event.ItemActionUpdate, Data: data},
+			}
+		}
+
+		for _, data := range []any{
+			&model.CedarPolicy{ID: "cedar-2", ResourceKind: "post"},
+			&model.Policy{ID: "policy-2"},
+			&model.Principal{ID: "user-2"},
+			&model.Resource{Kind: "post", Value: "2"},
+			&model.ResourceKind{ID: "doc"},
+		} {
+			eventChan <- &event.Event{
+				Data: &event.ItemEvent{Action: event.ItemActionDelete, Data: data},
+			}
+		}
+
+		close(eventChan)
+	}()
+
+	subscriber.handleItemEvents(eventChan)
+}
@@ -1,9 +1,11 @@
 package entity
 
 import (
+	"github.com/eko/authz/backend/internal/combining"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/lint"
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
@@ -11,14 +13,25 @@ import (
 func FxModule() fx.Option {
 	return fx.Module("entity",
 		fx.Provide(
+			combining.NewResolver,
+			lint.NewLinter,
+
 			manager.NewAction,
 			manager.NewAttribute,
 			manager.NewAudit,
+			manager.NewCedarPolicy,
+			manager.NewChange,
 			manager.NewClient,
+			manager.NewCompileJob,
 			manager.NewCompiledPolicy,
+			manager.NewDecisionCache,
+			manager.NewDecisionStore,
+			manager.NewDelegation,
 			manager.NewPolicy,
 			manager.NewPrincipal,
 			manager.NewResource,
+			manager.NewResourceKind,
+			manager.NewReviewCampaign,
 			manager.NewRole,
 			manager.NewStats,
 			manager.NewUser,
@@ -50,6 +63,24 @@ func FxModule() fx.Option {
 				return repository
 			},
 
+			// CedarPolicy
+			func(db *gorm.DB) repository.Base[model.CedarPolicy] {
+				return repository.New[model.CedarPolicy](db)
+			},
+
+			func(repository repository.Base[model.CedarPolicy]) manager.CedarPolicyRepository {
+				return repository
+			},
+
+			// Change
+			func(db *gorm.DB) repository.Base[model.Change] {
+				return repository.New[model.Change](db)
+			},
+
+			func(repository repository.Base[model.Change]) manager.ChangeRepository {
+				return repository
+			},
+
 			// Client
 			func(db *gorm.DB) repository.Base[model.Client] {
 				return repository.New[model.Client](db)
@@ -59,12 +90,30 @@ func FxModule() fx.Option {
 				return repository
 			},
 
+			// CompileJob
+			func(db *gorm.DB) repository.Base[model.CompileJob] {
+				return repository.New[model.CompileJob](db)
+			},
+
+			func(repository repository.Base[model.CompileJob]) manager.CompileJobRepository {
+				return repository
+			},
+
 			// CompiledPolicy
 			func(db *gorm.DB) repository.Base[model.CompiledPolicy] {
 				return repository.New[model.CompiledPolicy](db)
 			},
 
-			func(repository repository.Base[model.CompiledPolicy]) manager.CompiledPolicyRepository {
+			func(base repository.Base[model.CompiledPolicy]) manager.CompiledPolicyRepository {
+				return repository.NewCompiledPolicy(base)
+			},
+
+			// Delegation
+			func(db *gorm.DB) repository.Base[model.Delegation] {
+				return repository.New[model.Delegation](db)
+			},
+
+			func(repository repository.Base[model.Delegation]) manager.DelegationRepository {
 				return repository
 			},
 
@@ -95,6 +144,33 @@ func FxModule() fx.Option {
 				return repository.NewResource(base)
 			},
 
+			// ResourceKind
+			func(db *gorm.DB) repository.Base[model.ResourceKind] {
+				return repository.New[model.ResourceKind](db)
+			},
+
+			func(repository repository.Base[model.ResourceKind]) manager.ResourceKindRepository {
+				return repository
+			},
+
+			// ReviewCampaign
+			func(db *gorm.DB) repository.Base[model.ReviewCampaign] {
+				return repository.New[model.ReviewCampaign](db)
+			},
+
+			func(repository repository.Base[model.ReviewCampaign]) manager.ReviewCampaignRepository {
+				return repository
+			},
+
+			// ReviewItem
+			func(db *gorm.DB) repository.Base[model.ReviewItem] {
+				return repository.New[model.ReviewItem](db)
+			},
+
+			func(repository repository.Base[model.ReviewItem]) manager.ReviewItemRepository {
+				return repository
+			},
+
 			// Role
 			func(db *gorm.DB) repository.Base[model.Role] {
 				return repository.New[model.Role](db)
@@ -0,0 +1,168 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eko/authz/backend/internal/cedar"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"gorm.io/gorm"
+)
+
+type CedarPolicyRepository repository.Base[model.CedarPolicy]
+
+type CedarPolicy interface {
+	Create(identifier string, source string) (*model.CedarPolicy, error)
+	Delete(identifier string) error
+	GetRepository() CedarPolicyRepository
+	Update(identifier string, source string) (*model.CedarPolicy, error)
+}
+
+type cedarPolicyManager struct {
+	repository         CedarPolicyRepository
+	actionRepository   ActionRepository
+	resourceRepository repository.Resource
+	dispatcher         event.Dispatcher
+}
+
+// NewCedarPolicy initializes a new Cedar policy manager.
+func NewCedarPolicy(
+	repository CedarPolicyRepository,
+	actionRepository ActionRepository,
+	resourceRepository repository.Resource,
+	dispatcher event.Dispatcher,
+) CedarPolicy {
+	return &cedarPolicyManager{
+		repository:         repository,
+		actionRepository:   actionRepository,
+		resourceRepository: resourceRepository,
+		dispatcher:         dispatcher,
+	}
+}
+
+func (m *cedarPolicyManager) GetRepository() CedarPolicyRepository {
+	return m.repository
+}
+
+func (m *cedarPolicyManager) Create(identifier string, source string) (*model.CedarPolicy, error) {
+	exists, err := m.repository.Get(identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("unable to check for existing cedar policy: %v", err)
+	}
+
+	if exists != nil {
+		return nil, fmt.Errorf("a cedar policy already exists with identifier %q", identifier)
+	}
+
+	cedarPolicy := &model.CedarPolicy{ID: identifier}
+
+	if err := m.apply(cedarPolicy, source); err != nil {
+		return nil, err
+	}
+
+	if err := m.repository.Create(cedarPolicy); err != nil {
+		return nil, fmt.Errorf("unable to create cedar policy: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeCedarPolicy, &event.ItemEvent{
+		Action: event.ItemActionCreate,
+		Data:   cedarPolicy,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return cedarPolicy, nil
+}
+
+func (m *cedarPolicyManager) Delete(identifier string) error {
+	cedarPolicy, err := m.repository.Get(identifier)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve cedar policy: %v", err)
+	}
+
+	if err := m.repository.Delete(cedarPolicy); err != nil {
+		return fmt.Errorf("cannot delete cedar policy: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeCedarPolicy, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   cedarPolicy,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return nil
+}
+
+func (m *cedarPolicyManager) Update(identifier string, source string) (*model.CedarPolicy, error) {
+	cedarPolicy, err := m.repository.Get(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve cedar policy: %v", err)
+	}
+
+	if err := m.apply(cedarPolicy, source); err != nil {
+		return nil, err
+	}
+
+	if err := m.repository.Update(cedarPolicy); err != nil {
+		return nil, fmt.Errorf("unable to update cedar policy: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeCedarPolicy, &event.ItemEvent{
+		Action: event.ItemActionUpdate,
+		Data:   cedarPolicy,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return cedarPolicy, nil
+}
+
+// apply parses the source, validates it against the schema made of existing
+// actions and resource kinds and sets the fields deduced from it.
+func (m *cedarPolicyManager) apply(cedarPolicy *model.CedarPolicy, source string) error {
+	parsed, err := cedar.ParsePolicy(source)
+	if err != nil {
+		return err
+	}
+
+	schema, err := m.schema()
+	if err != nil {
+		return err
+	}
+
+	if err := schema.Validate(parsed); err != nil {
+		return err
+	}
+
+	cedarPolicy.Source = source
+	cedarPolicy.ResourceKind = parsed.ResourceKind()
+	cedarPolicy.Effect = model.PolicyEffectAllow
+
+	if parsed.Effect == cedar.EffectForbid {
+		cedarPolicy.Effect = model.PolicyEffectDeny
+	}
+
+	return nil
+}
+
+func (m *cedarPolicyManager) schema() (*cedar.Schema, error) {
+	actions, _, err := m.actionRepository.Find(repository.WithSkipPagination())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve actions: %v", err)
+	}
+
+	var actionIDs = make([]string, 0, len(actions))
+	for _, action := range actions {
+		actionIDs = append(actionIDs, action.ID)
+	}
+
+	kinds, err := m.resourceRepository.FindKinds()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource kinds: %v", err)
+	}
+
+	return cedar.NewSchema(kinds, actionIDs), nil
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/cedar.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCedarPolicy is a mock of CedarPolicy interface.
+type MockCedarPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockCedarPolicyMockRecorder
+}
+
+// MockCedarPolicyMockRecorder is the mock recorder for MockCedarPolicy.
+type MockCedarPolicyMockRecorder struct {
+	mock *MockCedarPolicy
+}
+
+// NewMockCedarPolicy creates a new mock instance.
+func NewMockCedarPolicy(ctrl *gomock.Controller) *MockCedarPolicy {
+	mock := &MockCedarPolicy{ctrl: ctrl}
+	mock.recorder = &MockCedarPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCedarPolicy) EXPECT() *MockCedarPolicyMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCedarPolicy) Create(identifier, source string) (*model.CedarPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", identifier, source)
+	ret0, _ := ret[0].(*model.CedarPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCedarPolicyMockRecorder) Create(identifier, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCedarPolicy)(nil).Create), identifier, source)
+}
+
+// Delete mocks base method.
+func (m *MockCedarPolicy) Delete(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCedarPolicyMockRecorder) Delete(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCedarPolicy)(nil).Delete), identifier)
+}
+
+// GetRepository mocks base method.
+func (m *MockCedarPolicy) GetRepository() CedarPolicyRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(CedarPolicyRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockCedarPolicyMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockCedarPolicy)(nil).GetRepository))
+}
+
+// Update mocks base method.
+func (m *MockCedarPolicy) Update(identifier, source string) (*model.CedarPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", identifier, source)
+	ret0, _ := ret[0].(*model.CedarPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCedarPolicyMockRecorder) Update(identifier, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCedarPolicy)(nil).Update), identifier, source)
+}
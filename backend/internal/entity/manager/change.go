@@ -0,0 +1,152 @@
+package manager
+
+import (
+	"fmt"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+)
+
+const (
+	// changeSkippedExpiry is how long skipped change identifiers are read
+	// again: their changes are then considered as rolled back.
+	changeSkippedExpiry = 1 * lib_time.Minute
+
+	// changeMaxSkipped is the maximum number of skipped change identifiers
+	// tracked by a cursor.
+	changeMaxSkipped = 1000
+)
+
+type ChangeRepository repository.Base[model.Change]
+
+type Change interface {
+	Cursor() (*ChangeCursor, error)
+	GetRepository() ChangeRepository
+	Next(cursor *ChangeCursor, limit int) ([]*model.Change, error)
+	Prune(createdBefore lib_time.Time) error
+	Record(itemType string, itemID string) error
+}
+
+// ChangeCursor is the position of a reader in the change log.
+//
+// Identifiers of changes are allocated before they are committed, so a change
+// may show up after changes having greater identifiers: the identifiers
+// skipped by a read are read again until their change shows up or they
+// expire, their change having been rolled back. The zero value reads the
+// change log from its beginning.
+type ChangeCursor struct {
+	lastID  int64
+	skipped map[int64]lib_time.Time
+}
+
+type changeManager struct {
+	repository ChangeRepository
+	clock      time.Clock
+}
+
+// NewChange initializes a new change log manager.
+func NewChange(
+	repository ChangeRepository,
+	clock time.Clock,
+) Change {
+	return &changeManager{
+		repository: repository,
+		clock:      clock,
+	}
+}
+
+func (m *changeManager) GetRepository() ChangeRepository {
+	return m.repository
+}
+
+// Record adds a change of the given item to the change log.
+func (m *changeManager) Record(itemType string, itemID string) error {
+	if err := m.repository.Create(&model.Change{
+		ItemType: itemType,
+		ItemID:   itemID,
+	}); err != nil {
+		return fmt.Errorf("unable to record change: %v", err)
+	}
+
+	return nil
+}
+
+// Cursor returns a cursor positioned after the last change recorded so far.
+func (m *changeManager) Cursor() (*ChangeCursor, error) {
+	var lastID int64
+
+	err := m.repository.DB().Model(&model.Change{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&lastID).Error
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve last change: %v", err)
+	}
+
+	return &ChangeCursor{lastID: lastID}, nil
+}
+
+// Next returns the changes skipped by the cursor that showed up since, along
+// with at most limit changes recorded after it, and moves the cursor after them.
+func (m *changeManager) Next(cursor *ChangeCursor, limit int) ([]*model.Change, error) {
+	var (
+		now     = m.clock.Now()
+		changes = make([]*model.Change, 0)
+		skipped = make([]int64, 0, len(cursor.skipped))
+	)
+
+	if cursor.skipped == nil {
+		cursor.skipped = map[int64]lib_time.Time{}
+	}
+
+	for id, skippedAt := range cursor.skipped {
+		if now.Sub(skippedAt) > changeSkippedExpiry {
+			delete(cursor.skipped, id)
+			continue
+		}
+
+		skipped = append(skipped, id)
+	}
+
+	if len(skipped) > 0 {
+		if err := m.repository.DB().Where("id IN ?", skipped).Find(&changes).Error; err != nil {
+			return nil, fmt.Errorf("unable to retrieve skipped changes: %v", err)
+		}
+
+		for _, change := range changes {
+			delete(cursor.skipped, change.ID)
+		}
+	}
+
+	var next = make([]*model.Change, 0)
+
+	if err := m.repository.DB().
+		Where("id > ?", cursor.lastID).
+		Order("id").
+		Limit(limit).
+		Find(&next).Error; err != nil {
+		return nil, fmt.Errorf("unable to retrieve changes: %v", err)
+	}
+
+	for _, change := range next {
+		for id := cursor.lastID + 1; id < change.ID && len(cursor.skipped) < changeMaxSkipped; id++ {
+			cursor.skipped[id] = now
+		}
+
+		cursor.lastID = change.ID
+	}
+
+	return append(changes, next...), nil
+}
+
+// Prune deletes the changes recorded before the given date.
+func (m *changeManager) Prune(createdBefore lib_time.Time) error {
+	if err := m.repository.DeleteByFields(map[string]repository.FieldValue{
+		"created_at": {Operator: "<", Value: createdBefore},
+	}); err != nil {
+		return fmt.Errorf("unable to prune changes: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/change.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+	time "time"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChange is a mock of Change interface.
+type MockChange struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeMockRecorder
+}
+
+// MockChangeMockRecorder is the mock recorder for MockChange.
+type MockChangeMockRecorder struct {
+	mock *MockChange
+}
+
+// NewMockChange creates a new mock instance.
+func NewMockChange(ctrl *gomock.Controller) *MockChange {
+	mock := &MockChange{ctrl: ctrl}
+	mock.recorder = &MockChangeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChange) EXPECT() *MockChangeMockRecorder {
+	return m.recorder
+}
+
+// Cursor mocks base method.
+func (m *MockChange) Cursor() (*ChangeCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cursor")
+	ret0, _ := ret[0].(*ChangeCursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cursor indicates an expected call of Cursor.
+func (mr *MockChangeMockRecorder) Cursor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cursor", reflect.TypeOf((*MockChange)(nil).Cursor))
+}
+
+// GetRepository mocks base method.
+func (m *MockChange) GetRepository() ChangeRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(ChangeRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockChangeMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockChange)(nil).GetRepository))
+}
+
+// Next mocks base method.
+func (m *MockChange) Next(cursor *ChangeCursor, limit int) ([]*model.Change, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", cursor, limit)
+	ret0, _ := ret[0].([]*model.Change)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockChangeMockRecorder) Next(cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockChange)(nil).Next), cursor, limit)
+}
+
+// Prune mocks base method.
+func (m *MockChange) Prune(createdBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", createdBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockChangeMockRecorder) Prune(createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockChange)(nil).Prune), createdBefore)
+}
+
+// Record mocks base method.
+func (m *MockChange) Record(itemType, itemID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", itemType, itemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockChangeMockRecorder) Record(itemType, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockChange)(nil).Record), itemType, itemID)
+}
@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func newTestChangeManager(t *testing.T) (*changeManager, *time.MockClock) {
+	ctrl := gomock.NewController(t)
+
+	db, err := database.New(&configs.Database{
+		Driver: configs.DriverSqlite,
+		Dbname: filepath.Join(t.TempDir(), "authz.db"),
+	}, slog.New(log.NewNopHandler()), time.NewClock())
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+
+	clock := time.NewMockClock(ctrl)
+
+	return NewChange(repository.New[model.Change](db), clock).(*changeManager), clock
+}
+
+func changeIDs(changes []*model.Change) []int64 {
+	var result = make([]int64, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, change.ID)
+	}
+
+	return result
+}
+
+func TestChange_Cursor(t *testing.T) {
+	// Given
+	changeManagerInstance, clock := newTestChangeManager(t)
+	clock.EXPECT().Now().Return(lib_time.Now()).AnyTimes()
+
+	assert := assert.New(t)
+
+	assert.Nil(changeManagerInstance.Record("policy", "policy-1"))
+
+	// When
+	cursor, err := changeManagerInstance.Cursor()
+
+	// Then
+	assert.Nil(err)
+
+	assert.Nil(changeManagerInstance.Record("principal", "alice"))
+
+	changes, err := changeManagerInstance.Next(cursor, 10)
+	assert.Nil(err)
+	assert.Len(changes, 1)
+	assert.Equal("principal", changes[0].ItemType)
+	assert.Equal("alice", changes[0].ItemID)
+}
+
+func TestChange_Next_WhenChangesAreCommittedOutOfOrder(t *testing.T) {
+	// Given
+	changeManagerInstance, clock := newTestChangeManager(t)
+
+	now := lib_time.Now()
+	clock.EXPECT().Now().Return(now).Times(3)
+
+	changeRepository := changeManagerInstance.GetRepository()
+
+	assert := assert.New(t)
+
+	assert.Nil(changeRepository.Create(&model.Change{ID: 1, ItemType: "policy", ItemID: "policy-1"}))
+	assert.Nil(changeRepository.Create(&model.Change{ID: 3, ItemType: "policy", ItemID: "policy-3"}))
+
+	cursor := &ChangeCursor{}
+
+	// When
+	changes, err := changeManagerInstance.Next(cursor, 10)
+
+	// Then
+	assert.Nil(err)
+	assert.Equal([]int64{1, 3}, changeIDs(changes))
+
+	// When - the skipped change is committed
+	assert.Nil(changeRepository.Create(&model.Change{ID: 2, ItemType: "policy", ItemID: "policy-2"}))
+
+	changes, err = changeManagerInstance.Next(cursor, 10)
+
+	// Then
+	assert.Nil(err)
+	assert.Equal([]int64{2}, changeIDs(changes))
+
+	// When - read once
+	changes, err = changeManagerInstance.Next(cursor, 10)
+
+	// Then
+	assert.Nil(err)
+	assert.Empty(changes)
+}
+
+func TestChange_Next_WhenSkippedChangeExpires(t *testing.T) {
+	// Given
+	changeManagerInstance, clock := newTestChangeManager(t)
+
+	now := lib_time.Now()
+
+	gomock.InOrder(
+		clock.EXPECT().Now().Return(now),
+		clock.EXPECT().Now().Return(now.Add(changeSkippedExpiry+lib_time.Second)),
+	)
+
+	changeRepository := changeManagerInstance.GetRepository()
+
+	assert := assert.New(t)
+
+	assert.Nil(changeRepository.Create(&model.Change{ID: 2, ItemType: "policy", ItemID: "policy-2"}))
+
+	cursor := &ChangeCursor{}
+
+	changes, err := changeManagerInstance.Next(cursor, 10)
+	assert.Nil(err)
+	assert.Equal([]int64{2}, changeIDs(changes))
+
+	assert.Nil(changeRepository.Create(&model.Change{ID: 1, ItemType: "policy", ItemID: "policy-1"}))
+
+	// When
+	changes, err = changeManagerInstance.Next(cursor, 10)
+
+	// Then
+	assert.Nil(err)
+	assert.Empty(changes)
+	assert.Empty(cursor.skipped)
+}
+
+func TestChange_Next_WhenLimitIsReached(t *testing.T) {
+	// Given
+	changeManagerInstance, clock := newTestChangeManager(t)
+	clock.EXPECT().Now().Return(lib_time.Now()).AnyTimes()
+
+	assert := assert.New(t)
+
+	for _, itemID := range []string{"policy-1", "policy-2", "policy-3"} {
+		assert.Nil(changeManagerInstance.Record("policy", itemID))
+	}
+
+	cursor := &ChangeCursor{}
+
+	// When
+	changes, err := changeManagerInstance.Next(cursor, 2)
+
+	// Then
+	assert.Nil(err)
+	assert.Equal([]int64{1, 2}, changeIDs(changes))
+
+	changes, err = changeManagerInstance.Next(cursor, 2)
+	assert.Nil(err)
+	assert.Equal([]int64{3}, changeIDs(changes))
+}
+
+func TestChange_Prune(t *testing.T) {
+	// Given
+	changeManagerInstance, _ := newTestChangeManager(t)
+
+	changeRepository := changeManagerInstance.GetRepository()
+
+	now := lib_time.Now()
+
+	assert := assert.New(t)
+
+	assert.Nil(changeRepository.Create(&model.Change{ItemType: "policy", ItemID: "policy-1", CreatedAt: now.Add(-2 * lib_time.Hour)}))
+	assert.Nil(changeRepository.Create(&model.Change{ItemType: "policy", ItemID: "policy-2", CreatedAt: now}))
+
+	// When
+	err := changeManagerInstance.Prune(now.Add(-1 * lib_time.Hour))
+
+	// Then
+	assert.Nil(err)
+
+	changes, _, err := changeRepository.Find(repository.WithSkipPagination())
+	assert.Nil(err)
+	assert.Len(changes, 1)
+	assert.Equal("policy-2", changes[0].ItemID)
+}
@@ -0,0 +1,409 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CompileJobRepository repository.Base[model.CompileJob]
+
+// ErrCompileJobNotClaimed is returned when a job is finished by a worker that
+// does not hold its claim anymore, because the job was enqueued or claimed
+// again in the meantime.
+var ErrCompileJobNotClaimed = errors.New("compile job is not claimed anymore")
+
+type CompileJob interface {
+	Cancel(targetType model.CompileJobTargetType, targetID string) error
+	Claim() (*model.CompileJob, error)
+	CountByStatus() (map[model.CompileJobStatus]int64, error)
+	Enqueue(targetType model.CompileJobTargetType, targetID string) error
+	Fail(job *model.CompileJob, jobErr error, retryAt *lib_time.Time) error
+	FindOutdated(changedUntil int64) ([]*model.CompileJob, error)
+	GetRepository() CompileJobRepository
+	LastRevision() (int64, error)
+	Renew(jobs []*model.CompileJob) error
+	Requeue(updatedSince lib_time.Time) error
+	ResetRunning() error
+	Retry(identifier string) (*model.CompileJob, error)
+	Succeed(job *model.CompileJob) error
+	Supersede(updatedBefore lib_time.Time) error
+	WithTransaction(transaction database.Transaction) CompileJob
+}
+
+type compileJobManager struct {
+	repository   CompileJobRepository
+	clock        time.Clock
+	claimTimeout lib_time.Duration
+}
+
+// NewCompileJob initializes a new compile job manager.
+func NewCompileJob(
+	cfg *configs.App,
+	repository CompileJobRepository,
+	clock time.Clock,
+) CompileJob {
+	return &compileJobManager{
+		repository:   repository,
+		clock:        clock,
+		claimTimeout: cfg.CompileJobClaimTimeout,
+	}
+}
+
+func (m *compileJobManager) GetRepository() CompileJobRepository {
+	return m.repository
+}
+
+// WithTransaction returns a manager enqueueing jobs in the given transaction,
+// so they are only visible to workers once the changes are committed.
+func (m *compileJobManager) WithTransaction(transaction database.Transaction) CompileJob {
+	return &compileJobManager{
+		repository:   m.repository.WithTransaction(transaction),
+		clock:        m.clock,
+		claimTimeout: m.claimTimeout,
+	}
+}
+
+// Enqueue creates the job of the given target or, when it already exists,
+// sets it back to pending so it gets processed as soon as possible with
+// all its attempts. A job currently running is processed again afterwards.
+// The pending date and revision are kept as long as a previous change is not
+// compiled.
+func (m *compileJobManager) Enqueue(targetType model.CompileJobTargetType, targetID string) error {
+	now := m.clock.Now()
+
+	// The revision is allocated in the same transaction as the change.
+	revision := &model.CompiledVersion{}
+	if err := m.repository.DB().Create(revision).Error; err != nil {
+		return fmt.Errorf("unable to allocate compile job revision: %v", err)
+	}
+
+	job := &model.CompileJob{
+		TargetType:      targetType,
+		TargetID:        targetID,
+		Status:          model.CompileJobStatusPending,
+		NextRunAt:       now,
+		PendingSince:    now,
+		PendingRevision: revision.ID,
+		Revision:        revision.ID,
+	}
+
+	// Assignments are ordered: MySQL evaluates them from left to right, so the
+	// pending date and revision have to be computed before the status changes.
+	err := m.repository.DB().Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "target_type"}, {Name: "target_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "pending_since"}, Value: gorm.Expr(
+				"CASE WHEN authz_compile_jobs.status = ? THEN ? ELSE authz_compile_jobs.pending_since END",
+				model.CompileJobStatusSucceeded, now,
+			)},
+			// Jobs enqueued before revisions were introduced have none.
+			{Column: clause.Column{Name: "pending_revision"}, Value: gorm.Expr(
+				"CASE WHEN authz_compile_jobs.status = ? OR COALESCE(authz_compile_jobs.pending_revision, 0) = 0 THEN ? ELSE authz_compile_jobs.pending_revision END",
+				model.CompileJobStatusSucceeded, revision.ID,
+			)},
+			{Column: clause.Column{Name: "revision"}, Value: revision.ID},
+			{Column: clause.Column{Name: "status"}, Value: model.CompileJobStatusPending},
+			{Column: clause.Column{Name: "attempts"}, Value: 0},
+			{Column: clause.Column{Name: "next_run_at"}, Value: now},
+			{Column: clause.Column{Name: "updated_at"}, Value: now},
+		},
+	}).Create(job).Error
+	if err != nil {
+		return fmt.Errorf("unable to enqueue compile job: %v", err)
+	}
+
+	return nil
+}
+
+// LastRevision returns the last revision allocated to a change or a
+// compilation, or 0 when none has been allocated yet.
+func (m *compileJobManager) LastRevision() (int64, error) {
+	var revision int64
+
+	err := m.repository.DB().Model(&model.CompiledVersion{}).
+		Select("COALESCE(MAX(id), 0)").
+		Scan(&revision).Error
+	if err != nil {
+		return 0, fmt.Errorf("unable to retrieve last revision: %v", err)
+	}
+
+	return revision, nil
+}
+
+// Cancel removes the job of the given target. It is used when the target is
+// deleted, its compiled policies being deleted along with it.
+func (m *compileJobManager) Cancel(targetType model.CompileJobTargetType, targetID string) error {
+	if err := m.repository.DeleteByFields(map[string]repository.FieldValue{
+		"target_type": {Operator: "=", Value: targetType},
+		"target_id":   {Operator: "=", Value: targetID},
+	}); err != nil {
+		return fmt.Errorf("unable to cancel compile job: %v", err)
+	}
+
+	return nil
+}
+
+// Claim marks the next pending job whose run date is reached as running and
+// returns it with a new claim, which has to be renewed until the job is
+// finished. Nil is returned when there is no job to process.
+func (m *compileJobManager) Claim() (*model.CompileJob, error) {
+	for {
+		now := m.clock.Now()
+
+		job, err := m.repository.GetByFields(
+			map[string]repository.FieldValue{
+				"status":      {Operator: "=", Value: model.CompileJobStatusPending},
+				"next_run_at": {Operator: "<=", Value: now},
+			},
+			repository.WithSort("next_run_at ASC, id ASC"),
+		)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve pending compile job: %v", err)
+		}
+
+		result := m.repository.DB().Model(&model.CompileJob{}).
+			Where("id = ? AND status = ?", job.ID, model.CompileJobStatusPending).
+			Updates(map[string]any{
+				"status":           model.CompileJobStatusRunning,
+				"attempts":         gorm.Expr("attempts + 1"),
+				"claim_id":         uuid.NewString(),
+				"claim_expires_at": now.Add(m.claimTimeout),
+				"updated_at":       now,
+			})
+		if result.Error != nil {
+			return nil, fmt.Errorf("unable to claim compile job: %v", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			// Claimed by another worker in the meantime.
+			continue
+		}
+
+		return m.repository.Get(strconv.FormatInt(job.ID, 10))
+	}
+}
+
+// Succeed marks the job as succeeded. ErrCompileJobNotClaimed is returned
+// when the job was enqueued or claimed again while running.
+func (m *compileJobManager) Succeed(job *model.CompileJob) error {
+	return m.finish(job, map[string]any{
+		"status":     model.CompileJobStatusSucceeded,
+		"last_error": "",
+	})
+}
+
+// Fail sets the job back to pending to be retried at the given date or,
+// when no date is given, marks it as failed. ErrCompileJobNotClaimed is
+// returned when the job was enqueued or claimed again while running.
+func (m *compileJobManager) Fail(job *model.CompileJob, jobErr error, retryAt *lib_time.Time) error {
+	values := map[string]any{
+		"status":     model.CompileJobStatusFailed,
+		"last_error": jobErr.Error(),
+	}
+
+	if retryAt != nil {
+		values["status"] = model.CompileJobStatusPending
+		values["next_run_at"] = *retryAt
+	}
+
+	return m.finish(job, values)
+}
+
+func (m *compileJobManager) finish(job *model.CompileJob, values map[string]any) error {
+	values["updated_at"] = m.clock.Now()
+
+	result := m.repository.DB().Model(&model.CompileJob{}).
+		Where("id = ? AND status = ? AND claim_id = ?", job.ID, model.CompileJobStatusRunning, job.ClaimID).
+		Updates(values)
+	if result.Error != nil {
+		return fmt.Errorf("unable to update compile job: %v", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrCompileJobNotClaimed
+	}
+
+	return nil
+}
+
+// Renew extends the claims of the given running jobs, so they are not
+// processed again by another instance. Jobs claimed again in the meantime
+// are left unchanged.
+func (m *compileJobManager) Renew(jobs []*model.CompileJob) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	claimIDs := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		claimIDs = append(claimIDs, job.ClaimID)
+	}
+
+	now := m.clock.Now()
+
+	err := m.repository.DB().Model(&model.CompileJob{}).
+		Where("status = ? AND claim_id IN ?", model.CompileJobStatusRunning, claimIDs).
+		Update("claim_expires_at", now.Add(m.claimTimeout)).Error
+	if err != nil {
+		return fmt.Errorf("unable to renew compile job claims: %v", err)
+	}
+
+	return nil
+}
+
+// Retry sets the given job back to pending so it gets processed again as soon
+// as possible, with all its attempts.
+func (m *compileJobManager) Retry(identifier string) (*model.CompileJob, error) {
+	job, err := m.repository.Get(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve compile job: %v", err)
+	}
+
+	if err := m.Enqueue(job.TargetType, job.TargetID); err != nil {
+		return nil, err
+	}
+
+	return m.repository.Get(identifier)
+}
+
+// ResetRunning sets running jobs whose claim expired back to pending. It is
+// used to process again the jobs that were interrupted by the stop or the
+// crash of an instance. Jobs of instances still renewing their claims are kept.
+func (m *compileJobManager) ResetRunning() error {
+	err := m.repository.DB().Model(&model.CompileJob{}).
+		Where(
+			"status = ? AND (claim_expires_at IS NULL OR claim_expires_at < ?)",
+			model.CompileJobStatusRunning, m.clock.Now(),
+		).
+		Updates(map[string]any{
+			"status":     model.CompileJobStatusPending,
+			"updated_at": m.clock.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("unable to reset running compile jobs: %v", err)
+	}
+
+	return nil
+}
+
+// Requeue sets the succeeded and failed jobs updated since the given date back
+// to pending, with all their attempts. Their pending date is kept: changes are
+// considered as not compiled until they are processed again. Running jobs are
+// enqueued again, so their worker does not hold their claim anymore and they
+// are processed once more afterwards.
+func (m *compileJobManager) Requeue(updatedSince lib_time.Time) error {
+	now := m.clock.Now()
+
+	err := m.repository.DB().Model(&model.CompileJob{}).
+		Where("status IN ? AND updated_at >= ?", []model.CompileJobStatus{
+			model.CompileJobStatusSucceeded,
+			model.CompileJobStatusFailed,
+		}, updatedSince).
+		Updates(map[string]any{
+			"status":      model.CompileJobStatusPending,
+			"attempts":    0,
+			"next_run_at": now,
+			"updated_at":  now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("unable to requeue compile jobs: %v", err)
+	}
+
+	running, _, err := m.repository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"status":     {Operator: "=", Value: model.CompileJobStatusRunning},
+			"updated_at": {Operator: ">=", Value: updatedSince},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve running compile jobs: %v", err)
+	}
+
+	for _, job := range running {
+		if err := m.Enqueue(job.TargetType, job.TargetID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Supersede marks the failed jobs not updated since the given date as
+// succeeded. It is used once compiled policies have been rebuilt from
+// scratch, which compiles the changes these jobs failed to compile.
+func (m *compileJobManager) Supersede(updatedBefore lib_time.Time) error {
+	err := m.repository.DB().Model(&model.CompileJob{}).
+		Where("status = ? AND updated_at < ?", model.CompileJobStatusFailed, updatedBefore).
+		Updates(map[string]any{
+			"status":     model.CompileJobStatusSucceeded,
+			"last_error": "",
+			"updated_at": m.clock.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("unable to supersede failed compile jobs: %v", err)
+	}
+
+	return nil
+}
+
+// FindOutdated returns the jobs of targets having changes made until the given
+// revision that are not compiled yet, either because they are still pending or
+// because their compilation failed.
+func (m *compileJobManager) FindOutdated(changedUntil int64) ([]*model.CompileJob, error) {
+	jobs, _, err := m.repository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"status":           {Operator: "<>", Value: model.CompileJobStatusSucceeded},
+			"pending_revision": {Operator: "<=", Value: changedUntil},
+		}),
+		repository.WithSort("pending_revision ASC"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve outdated compile jobs: %v", err)
+	}
+
+	return jobs, nil
+}
+
+// CountByStatus returns the number of jobs of each status.
+func (m *compileJobManager) CountByStatus() (map[model.CompileJobStatus]int64, error) {
+	var rows []struct {
+		Status model.CompileJobStatus
+		Total  int64
+	}
+
+	err := m.repository.DB().Model(&model.CompileJob{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("unable to count compile jobs: %v", err)
+	}
+
+	var result = map[model.CompileJobStatus]int64{
+		model.CompileJobStatusPending:   0,
+		model.CompileJobStatusRunning:   0,
+		model.CompileJobStatusSucceeded: 0,
+		model.CompileJobStatusFailed:    0,
+	}
+
+	for _, row := range rows {
+		result[row.Status] = row.Total
+	}
+
+	return result, nil
+}
@@ -0,0 +1,252 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/compile_job.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+	time "time"
+
+	database "github.com/eko/authz/backend/internal/database"
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCompileJob is a mock of CompileJob interface.
+type MockCompileJob struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompileJobMockRecorder
+}
+
+// MockCompileJobMockRecorder is the mock recorder for MockCompileJob.
+type MockCompileJobMockRecorder struct {
+	mock *MockCompileJob
+}
+
+// NewMockCompileJob creates a new mock instance.
+func NewMockCompileJob(ctrl *gomock.Controller) *MockCompileJob {
+	mock := &MockCompileJob{ctrl: ctrl}
+	mock.recorder = &MockCompileJobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompileJob) EXPECT() *MockCompileJobMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockCompileJob) Cancel(targetType model.CompileJobTargetType, targetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", targetType, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockCompileJobMockRecorder) Cancel(targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCompileJob)(nil).Cancel), targetType, targetID)
+}
+
+// Claim mocks base method.
+func (m *MockCompileJob) Claim() (*model.CompileJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim")
+	ret0, _ := ret[0].(*model.CompileJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockCompileJobMockRecorder) Claim() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockCompileJob)(nil).Claim))
+}
+
+// CountByStatus mocks base method.
+func (m *MockCompileJob) CountByStatus() (map[model.CompileJobStatus]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatus")
+	ret0, _ := ret[0].(map[model.CompileJobStatus]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockCompileJobMockRecorder) CountByStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockCompileJob)(nil).CountByStatus))
+}
+
+// Enqueue mocks base method.
+func (m *MockCompileJob) Enqueue(targetType model.CompileJobTargetType, targetID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", targetType, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockCompileJobMockRecorder) Enqueue(targetType, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockCompileJob)(nil).Enqueue), targetType, targetID)
+}
+
+// Fail mocks base method.
+func (m *MockCompileJob) Fail(job *model.CompileJob, jobErr error, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", job, jobErr, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockCompileJobMockRecorder) Fail(job, jobErr, retryAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockCompileJob)(nil).Fail), job, jobErr, retryAt)
+}
+
+// FindOutdated mocks base method.
+func (m *MockCompileJob) FindOutdated(changedUntil int64) ([]*model.CompileJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOutdated", changedUntil)
+	ret0, _ := ret[0].([]*model.CompileJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOutdated indicates an expected call of FindOutdated.
+func (mr *MockCompileJobMockRecorder) FindOutdated(changedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOutdated", reflect.TypeOf((*MockCompileJob)(nil).FindOutdated), changedUntil)
+}
+
+// GetRepository mocks base method.
+func (m *MockCompileJob) GetRepository() CompileJobRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(CompileJobRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockCompileJobMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockCompileJob)(nil).GetRepository))
+}
+
+// LastRevision mocks base method.
+func (m *MockCompileJob) LastRevision() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRevision")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastRevision indicates an expected call of LastRevision.
+func (mr *MockCompileJobMockRecorder) LastRevision() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRevision", reflect.TypeOf((*MockCompileJob)(nil).LastRevision))
+}
+
+// Renew mocks base method.
+func (m *MockCompileJob) Renew(jobs []*model.CompileJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", jobs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockCompileJobMockRecorder) Renew(jobs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockCompileJob)(nil).Renew), jobs)
+}
+
+// Requeue mocks base method.
+func (m *MockCompileJob) Requeue(updatedSince time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Requeue", updatedSince)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Requeue indicates an expected call of Requeue.
+func (mr *MockCompileJobMockRecorder) Requeue(updatedSince interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Requeue", reflect.TypeOf((*MockCompileJob)(nil).Requeue), updatedSince)
+}
+
+// ResetRunning mocks base method.
+func (m *MockCompileJob) ResetRunning() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunning")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetRunning indicates an expected call of ResetRunning.
+func (mr *MockCompileJobMockRecorder) ResetRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunning", reflect.TypeOf((*MockCompileJob)(nil).ResetRunning))
+}
+
+// Retry mocks base method.
+func (m *MockCompileJob) Retry(identifier string) (*model.CompileJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Retry", identifier)
+	ret0, _ := ret[0].(*model.CompileJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retry indicates an expected call of Retry.
+func (mr *MockCompileJobMockRecorder) Retry(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retry", reflect.TypeOf((*MockCompileJob)(nil).Retry), identifier)
+}
+
+// Succeed mocks base method.
+func (m *MockCompileJob) Succeed(job *model.CompileJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeed", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeed indicates an expected call of Succeed.
+func (mr *MockCompileJobMockRecorder) Succeed(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeed", reflect.TypeOf((*MockCompileJob)(nil).Succeed), job)
+}
+
+// Supersede mocks base method.
+func (m *MockCompileJob) Supersede(updatedBefore time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Supersede", updatedBefore)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Supersede indicates an expected call of Supersede.
+func (mr *MockCompileJobMockRecorder) Supersede(updatedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Supersede", reflect.TypeOf((*MockCompileJob)(nil).Supersede), updatedBefore)
+}
+
+// WithTransaction mocks base method.
+func (m *MockCompileJob) WithTransaction(transaction database.Transaction) CompileJob {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", transaction)
+	ret0, _ := ret[0].(CompileJob)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockCompileJobMockRecorder) WithTransaction(transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockCompileJob)(nil).WithTransaction), transaction)
+}
@@ -1,43 +1,80 @@
 package manager
 
 import (
-	"errors"
 	"fmt"
+	"sync"
+	lib_time "time"
 
+	"github.com/eko/authz/backend/internal/cedar"
+	"github.com/eko/authz/backend/internal/combining"
+	"github.com/eko/authz/backend/internal/database"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
 	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
 	"golang.org/x/exp/slog"
 	"gorm.io/gorm"
 )
 
-type CompiledPolicyRepository repository.Base[model.CompiledPolicy]
+type CompiledPolicyRepository repository.CompiledPolicy
+
+// compiledPolicyShadowTable is the table in which compiled policies are
+// rebuilt from scratch before replacing the current ones.
+const compiledPolicyShadowTable = "authz_compiled_policies_shadow"
 
 type CompiledPolicy interface {
 	Create(compiledPolicy []*model.CompiledPolicy) error
+	DropShadow() error
+	EvictCedarPolicy(identifier string)
 	GetRepository() CompiledPolicyRepository
 	IsAllowed(principalID string, resourceKind string, resourceValue string, actionID string) (bool, error)
+	IsAllowedBulk(checks []*Check) ([]bool, error)
+	IsAllowedWithContext(principalID string, resourceKind string, resourceValue string, actionID string, context map[string]string) (bool, error)
+	IsDirectlyAllowed(principalID string, resourceKind string, resourceValue string, actionID string) (bool, error)
+	NewShadow() (CompiledPolicy, error)
+	NextVersion() (int64, error)
+	PruneVersions(version int64) error
+	SwapShadow() error
+	WithTransaction(transaction database.Transaction) CompiledPolicy
 }
 
 type compiledPolicyManager struct {
 	repository          CompiledPolicyRepository
-	principalRepository repository.Base[model.Principal]
+	table               string
+	decisionStore       DecisionStore
+	resourceKindManager ResourceKind
+	combiningResolver   *combining.Resolver
+	decisionCache       *DecisionCache
+	clock               time.Clock
 	logger              *slog.Logger
+	transactionManager  database.TransactionManager
 	dispatcher          event.Dispatcher
+	parsedCedarPolicies *sync.Map
 }
 
 // NewCompiledPolicy initializes a new compiledPolicy manager.
 func NewCompiledPolicy(
 	repository CompiledPolicyRepository,
-	principalRepository repository.Base[model.Principal],
+	decisionStore DecisionStore,
+	resourceKindManager ResourceKind,
+	combiningResolver *combining.Resolver,
+	decisionCache *DecisionCache,
+	clock time.Clock,
 	logger *slog.Logger,
+	transactionManager database.TransactionManager,
 	dispatcher event.Dispatcher,
 ) CompiledPolicy {
 	return &compiledPolicyManager{
 		repository:          repository,
-		principalRepository: principalRepository,
+		decisionStore:       decisionStore,
+		resourceKindManager: resourceKindManager,
+		combiningResolver:   combiningResolver,
+		decisionCache:       decisionCache,
+		clock:               clock,
 		logger:              logger,
+		transactionManager:  transactionManager,
 		dispatcher:          dispatcher,
+		parsedCedarPolicies: &sync.Map{},
 	}
 }
 
@@ -53,94 +90,812 @@ func (m *compiledPolicyManager) Create(compiledPolicy []*model.CompiledPolicy) e
 	return nil
 }
 
+// WithTransaction returns a manager writing compiled policies in the given
+// transaction, in the same table as this manager.
+func (m *compiledPolicyManager) WithTransaction(transaction database.Transaction) CompiledPolicy {
+	db := transaction.DB()
+	if m.table != "" {
+		db = db.Table(m.table).Session(&gorm.Session{})
+	}
+
+	clone := *m
+	clone.repository = repository.NewCompiledPolicy(repository.New[model.CompiledPolicy](db))
+
+	return &clone
+}
+
+// NextVersion allocates a new version of compiled policies, greater than all
+// the versions allocated before, even in the same second or on another instance.
+func (m *compiledPolicyManager) NextVersion() (int64, error) {
+	version := &model.CompiledVersion{}
+
+	if err := m.versionsDB().Create(version).Error; err != nil {
+		return 0, fmt.Errorf("unable to allocate compiled policies version: %v", err)
+	}
+
+	return version.ID, nil
+}
+
+// PruneVersions deletes the versions allocated before the given one. The
+// given version is kept so the next allocated ones remain greater.
+func (m *compiledPolicyManager) PruneVersions(version int64) error {
+	if err := m.versionsDB().Where("id < ?", version).Delete(&model.CompiledVersion{}).Error; err != nil {
+		return fmt.Errorf("unable to delete compiled policies versions: %v", err)
+	}
+
+	return nil
+}
+
+// versionsDB returns the repository database targeting the versions table,
+// whatever the table compiled policies are written to.
+func (m *compiledPolicyManager) versionsDB() *gorm.DB {
+	return m.repository.DB().Table(model.CompiledVersion{}.TableName())
+}
+
+// NewShadow creates an empty shadow table and returns a manager writing
+// compiled policies into it. A previous shadow table is dropped first.
+func (m *compiledPolicyManager) NewShadow() (CompiledPolicy, error) {
+	if err := m.DropShadow(); err != nil {
+		return nil, err
+	}
+
+	db := m.repository.DB().Table(compiledPolicyShadowTable).Session(&gorm.Session{})
+
+	if err := db.Migrator().CreateTable(&model.CompiledPolicy{}); err != nil {
+		return nil, fmt.Errorf("unable to create compiled policies shadow table: %v", err)
+	}
+
+	shadow := *m
+	shadow.repository = repository.NewCompiledPolicy(repository.New[model.CompiledPolicy](db))
+	shadow.table = compiledPolicyShadowTable
+
+	return &shadow, nil
+}
+
+// SwapShadow replaces all the compiled policies by the ones of the shadow
+// table. It is called on a manager in a transaction, so the replacement is
+// atomic, and the shadow table is dropped once the transaction is committed.
+func (m *compiledPolicyManager) SwapShadow() error {
+	const columns = "policy_id, principal_id, resource_kind, resource_value, action_id, version, created_at, updated_at"
+
+	db := m.repository.DB()
+
+	if err := db.Exec("DELETE FROM authz_compiled_policies").Error; err != nil {
+		return fmt.Errorf("unable to delete compiled policies: %v", err)
+	}
+
+	if err := db.Exec(
+		"INSERT INTO authz_compiled_policies (" + columns + ") SELECT " + columns + " FROM " + compiledPolicyShadowTable,
+	).Error; err != nil {
+		return fmt.Errorf("unable to copy shadow compiled policies: %v", err)
+	}
+
+	return nil
+}
+
+// DropShadow drops the shadow table, if any.
+func (m *compiledPolicyManager) DropShadow() error {
+	if err := m.repository.DB().Migrator().DropTable(compiledPolicyShadowTable); err != nil {
+		return fmt.Errorf("unable to drop compiled policies shadow table: %v", err)
+	}
+
+	return nil
+}
+
 func (m *compiledPolicyManager) IsAllowed(principalID string, resourceKind string, resourceValue string, actionID string) (bool, error) {
-	principal, err := m.principalRepository.Get(principalID, repository.WithPreloads("Roles.Policies"))
+	return m.IsAllowedWithContext(principalID, resourceKind, resourceValue, actionID, nil)
+}
+
+// IsAllowedWithContext checks access like IsAllowed, the given context being
+// available to Cedar policies conditions.
+// An error wrapping ErrUnknownAction is returned when the action is not
+// declared on a registered resource kind.
+func (m *compiledPolicyManager) IsAllowedWithContext(
+	principalID string,
+	resourceKind string,
+	resourceValue string,
+	actionID string,
+	context map[string]string,
+) (bool, error) {
+	results, err := m.IsAllowedBulk([]*Check{{
+		PrincipalID:   principalID,
+		ResourceKind:  resourceKind,
+		ResourceValue: resourceValue,
+		ActionID:      actionID,
+		Context:       context,
+	}})
 	if err != nil {
-		return false, fmt.Errorf("unable to retrieve principal: %v", err)
+		return false, err
 	}
 
-	var policyIDs = make([]string, 0)
-	for _, role := range principal.Roles {
-		for _, policy := range role.Policies {
-			policyIDs = append(policyIDs, policy.ID)
+	return results[0], nil
+}
+
+// Check is an access check of a principal on a resource, its context being
+// available to Cedar policies conditions.
+type Check struct {
+	PrincipalID   string
+	ResourceKind  string
+	ResourceValue string
+	ActionID      string
+	Context       map[string]string
+}
+
+func (c *Check) key() decisionKey {
+	return decisionKey{c.PrincipalID, c.ResourceKind, c.ResourceValue, c.ActionID}
+}
+
+// IsAllowedBulk returns whether each of the given checks is allowed. All the
+// checks are evaluated together, in a constant number of queries whatever their
+// number.
+// An error wrapping ErrUnknownAction is returned when an action is not
+// declared on a registered resource kind.
+func (m *compiledPolicyManager) IsAllowedBulk(checks []*Check) ([]bool, error) {
+	if len(checks) == 0 {
+		return []bool{}, nil
+	}
+
+	var actionsByKind = map[string][]string{}
+	for _, check := range checks {
+		actionsByKind[check.ResourceKind] = append(actionsByKind[check.ResourceKind], check.ActionID)
+	}
+
+	if err := m.resourceKindManager.ValidateActionsByKind(actionsByKind); err != nil {
+		return nil, err
+	}
+
+	outcomes, err := m.decide(checks)
+	if err != nil {
+		return nil, err
+	}
+
+	var results = make([]bool, len(checks))
+
+	for i, check := range checks {
+		m.report(check, outcomes[i])
+		results[i] = outcomes[i].decision.isAllowed
+	}
+
+	return results, nil
+}
+
+// checkOutcome is the decision of a check, along with the delegation it has
+// been taken from and whether it comes from the decision cache.
+type checkOutcome struct {
+	decision    *decision
+	delegation  *model.Delegation
+	cacheStatus event.CacheStatus
+}
+
+// report logs the outcome of a check and dispatches its check event.
+func (m *compiledPolicyManager) report(check *Check, outcome *checkOutcome) {
+	result := outcome.decision
+
+	logAttributes := []any{
+		slog.String("principal_id", check.PrincipalID),
+		slog.String("resource_kind", check.ResourceKind),
+		slog.String("resource_value", check.ResourceValue),
+		slog.String("action_id", check.ActionID),
+		slog.String("algorithm", string(result.algorithm)),
+		slog.Bool("result", result.isAllowed),
+	}
+
+	if outcome.delegation != nil {
+		logAttributes = append(logAttributes, slog.String("delegation_id", outcome.delegation.ID))
+	}
+
+	if result.cedarPolicy != nil {
+		logAttributes = append(logAttributes, slog.String("cedar_policy_id", result.cedarPolicy.ID))
+	}
+
+	if outcome.cacheStatus != "" {
+		logAttributes = append(logAttributes, slog.String("cache", string(outcome.cacheStatus)))
+	}
+
+	m.logger.Debug("Call to IsAllowed method", logAttributes...)
+
+	if err := m.dispatcher.Dispatch(event.EventTypeCheck, &event.CheckEvent{
+		Principal:      check.PrincipalID,
+		ResourceKind:   check.ResourceKind,
+		ResourceValue:  check.ResourceValue,
+		Action:         check.ActionID,
+		IsAllowed:      result.isAllowed,
+		Algorithm:      string(result.algorithm),
+		CompiledPolicy: result.compiledPolicy,
+		CedarPolicy:    result.cedarPolicy,
+		Delegation:     outcome.delegation,
+		CacheStatus:    outcome.cacheStatus,
+	}); err != nil {
+		m.logger.Error("unable to dispatch check event", err)
+	}
+}
+
+// decide returns the outcomes of the checks, from the decision cache when
+// possible, the other checks being evaluated together. Checks with a context
+// are never cached, as Cedar policies conditions may depend on it.
+func (m *compiledPolicyManager) decide(checks []*Check) ([]*checkOutcome, error) {
+	var (
+		outcomes    = make([]*checkOutcome, len(checks))
+		generations = make([]uint64, len(checks))
+		pending     = make([]*Check, 0, len(checks))
+		pendingAt   = make([]int, 0, len(checks))
+	)
+
+	for i, check := range checks {
+		if m.isCacheable(check) {
+			var cached *cachedDecision
+
+			cached, generations[i] = m.decisionCache.get(check.key())
+			if cached != nil {
+				outcomes[i] = &checkOutcome{
+					decision:    cached.decision,
+					delegation:  cached.delegation,
+					cacheStatus: event.CacheStatusHit,
+				}
+				continue
+			}
+		}
+
+		pending = append(pending, check)
+		pendingAt = append(pendingAt, i)
+	}
+
+	if len(pending) == 0 {
+		return outcomes, nil
+	}
+
+	var dependencies = make([]*decisionDependencies, len(pending))
+	for j := range pending {
+		dependencies[j] = newDecisionDependencies()
+	}
+
+	results, delegations, err := m.evaluate(pending, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, i := range pendingAt {
+		outcome := &checkOutcome{
+			decision:   results[j],
+			delegation: delegations[j],
+		}
+
+		if m.isCacheable(checks[i]) {
+			outcome.cacheStatus = event.CacheStatusMiss
+
+			m.decisionCache.add(&cachedDecision{
+				key:          checks[i].key(),
+				decision:     results[j],
+				delegation:   delegations[j],
+				dependencies: dependencies[j],
+			}, generations[i])
+		}
+
+		outcomes[i] = outcome
+	}
+
+	return outcomes, nil
+}
+
+func (m *compiledPolicyManager) isCacheable(check *Check) bool {
+	return m.decisionCache.enabled() && len(check.Context) == 0
+}
+
+// evaluate returns the decisions of the checks and the delegations they have
+// been taken from, if any, recording the data they depend on.
+func (m *compiledPolicyManager) evaluate(
+	checks []*Check,
+	dependencies []*decisionDependencies,
+) ([]*decision, []*model.Delegation, error) {
+	results, err := m.directDecisions(checks, dependencies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var delegations = make([]*model.Delegation, len(checks))
+
+	// Delegations only apply when no policy of the principal itself is applicable,
+	// so an explicit deny on the delegate cannot be overridden by a delegation.
+	var (
+		undecided   = make([]*Check, 0)
+		undecidedAt = make([]int, 0)
+	)
+
+	for i, result := range results {
+		if !result.applies() {
+			undecided = append(undecided, checks[i])
+			undecidedAt = append(undecidedAt, i)
 		}
 	}
 
-	isAllowed, compiledPolicy, err := m.isPolicyAllowed(policyIDs, resourceKind, resourceValue, actionID)
+	if len(undecided) == 0 {
+		return results, delegations, nil
+	}
+
+	candidates, err := m.findDelegations(undecided)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A delegation only applies while its delegator is still directly allowed,
+	// so revoking the delegator's access also revokes it. The delegators of all
+	// the checks are evaluated together.
+	var (
+		delegatorChecks       = make([]*Check, 0)
+		delegatorDependencies = make([]*decisionDependencies, 0)
+		delegatorDelegations  = make([]*model.Delegation, 0)
+		delegatorCheckAt      = make([]int, 0)
+	)
+
+	for j, check := range undecided {
+		i := undecidedAt[j]
+
+		for _, delegation := range candidates[j] {
+			delegatorChecks = append(delegatorChecks, &Check{
+				PrincipalID:   delegation.DelegatorID,
+				ResourceKind:  check.ResourceKind,
+				ResourceValue: check.ResourceValue,
+				ActionID:      check.ActionID,
+			})
+			delegatorDependencies = append(delegatorDependencies, dependencies[i])
+			delegatorDelegations = append(delegatorDelegations, delegation)
+			delegatorCheckAt = append(delegatorCheckAt, i)
+		}
+	}
+
+	if len(delegatorChecks) == 0 {
+		return results, delegations, nil
+	}
+
+	delegatorResults, err := m.directDecisions(delegatorChecks, delegatorDependencies)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k, result := range delegatorResults {
+		i := delegatorCheckAt[k]
+
+		if delegations[i] == nil && result.isAllowed {
+			results[i] = result
+			delegations[i] = delegatorDelegations[k]
+		}
+	}
+
+	return results, delegations, nil
+}
+
+// IsDirectlyAllowed returns whether the principal is allowed by its own roles
+// and attribute rules, without taking delegations into account.
+// No check event is dispatched.
+func (m *compiledPolicyManager) IsDirectlyAllowed(principalID string, resourceKind string, resourceValue string, actionID string) (bool, error) {
+	results, err := m.directDecisions(
+		[]*Check{{
+			PrincipalID:   principalID,
+			ResourceKind:  resourceKind,
+			ResourceValue: resourceValue,
+			ActionID:      actionID,
+		}},
+		[]*decisionDependencies{newDecisionDependencies()},
+	)
 	if err != nil {
 		return false, err
 	}
 
-	if !isAllowed {
-		isAllowed, compiledPolicy, err = m.isPrincipalAllowed(principalID, resourceKind, resourceValue, actionID)
+	return results[0].isAllowed, nil
+}
+
+// decision is the result of combining all the policies applicable to a check.
+// The compiled policy (or Cedar policy) is the one of the decisive policy,
+// both are nil when no policy applies.
+type decision struct {
+	isAllowed      bool
+	algorithm      combining.Algorithm
+	compiledPolicy *model.CompiledPolicy
+	cedarPolicy    *model.CedarPolicy
+}
+
+func (d *decision) applies() bool {
+	return d.compiledPolicy != nil || d.cedarPolicy != nil
+}
+
+// directDecisions returns the decisions of the checks given by the roles and
+// attribute rules of their principals and by Cedar policies, without taking
+// delegations into account. The compiled policies of all the checks, including
+// the ones on the wildcard value, are retrieved from the decision store at once.
+func (m *compiledPolicyManager) directDecisions(
+	checks []*Check,
+	dependencies []*decisionDependencies,
+) ([]*decision, error) {
+	principals, err := m.findPrincipals(checks)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		principalList = make([]*model.Principal, 0, len(principals))
+		lookups       = make([]*repository.CompiledPolicyLookup, 0, len(checks))
+		seenLookups   = map[repository.CompiledPolicyLookup]bool{}
+	)
+
+	for _, principal := range principals {
+		principalList = append(principalList, principal)
+	}
+
+	for _, check := range checks {
+		lookup := repository.CompiledPolicyLookup{
+			ResourceKind:  check.ResourceKind,
+			ResourceValue: check.ResourceValue,
+			ActionID:      check.ActionID,
+		}
+
+		if !seenLookups[lookup] {
+			seenLookups[lookup] = true
+			lookups = append(lookups, &lookup)
+		}
+	}
+
+	compiledPolicies, err := m.decisionStore.FindApplicable(principalList, lookups)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve compiled policies: %v", err)
+	}
+
+	cedarPolicies, resources, err := m.findCedarPolicies(checks)
+	if err != nil {
+		return nil, err
+	}
+
+	var results = make([]*decision, len(checks))
+
+	for i, check := range checks {
+		principal := principals[check.PrincipalID]
+
+		dependencies[i].addPrincipal(principal)
+
+		var applicable = make([]*repository.ApplicableCompiledPolicy, 0)
+
+		for _, compiledPolicy := range compiledPolicies {
+			if compiledPolicy.AppliesTo(check.PrincipalID, check.ResourceKind, check.ResourceValue, check.ActionID) {
+				applicable = append(applicable, compiledPolicy)
+				dependencies[i].policies[compiledPolicy.PolicyID] = true
+			}
+		}
+
+		applicableCedarPolicies, err := m.applicableCedarPolicies(
+			principal,
+			check,
+			cedarPolicies,
+			resources[resourceKey{check.ResourceKind, check.ResourceValue}],
+			dependencies[i],
+		)
 		if err != nil {
-			return false, err
+			return nil, err
+		}
+
+		results[i] = m.combine(check.ResourceKind, applicable, applicableCedarPolicies)
+	}
+
+	return results, nil
+}
+
+// findPrincipals returns the principals of the checks by identifier, along
+// with their roles and attributes.
+func (m *compiledPolicyManager) findPrincipals(checks []*Check) (map[string]*model.Principal, error) {
+	var principalIDs = make([]string, 0, len(checks))
+	for _, check := range checks {
+		principalIDs = append(principalIDs, check.PrincipalID)
+	}
+
+	principals, err := m.decisionStore.FindPrincipals(principalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var principalByID = make(map[string]*model.Principal, len(principals))
+	for _, principal := range principals {
+		principalByID[principal.ID] = principal
+	}
+
+	for _, principalID := range principalIDs {
+		if _, ok := principalByID[principalID]; !ok {
+			return nil, fmt.Errorf("unable to retrieve principal %q: %v", principalID, gorm.ErrRecordNotFound)
+		}
+	}
+
+	return principalByID, nil
+}
+
+// findDelegations returns, for each check, the active delegations given to its
+// principal that cover its resource and action.
+func (m *compiledPolicyManager) findDelegations(checks []*Check) ([][]*model.Delegation, error) {
+	delegations, err := m.decisionStore.FindDelegations(checks, m.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var result = make([][]*model.Delegation, len(checks))
+
+	for i, check := range checks {
+		var seen = map[string]bool{}
+
+		for _, delegation := range delegations {
+			if seen[delegation.ID] || !delegationCovers(delegation, check) {
+				continue
+			}
+
+			seen[delegation.ID] = true
+			result[i] = append(result[i], delegation)
 		}
 	}
 
-	m.logger.Debug(
-		"Call to IsAllowed method",
-		slog.String("principal_id", principalID),
-		slog.String("resource_kind", resourceKind),
-		slog.String("resource_value", resourceValue),
-		slog.String("action_id", actionID),
-		slog.Bool("result", isAllowed),
+	return result, nil
+}
+
+// delegationCovers returns whether the delegation is given to the principal of
+// the check, on its resource (or all the resources of its kind) and action.
+func delegationCovers(delegation *model.Delegation, check *Check) bool {
+	if delegation.DelegateID != check.PrincipalID {
+		return false
+	}
+
+	var coversResource, coversAction bool
+
+	for _, resource := range delegation.Resources {
+		if resource.Kind == check.ResourceKind && (resource.Value == check.ResourceValue || resource.Value == WildcardValue) {
+			coversResource = true
+			break
+		}
+	}
+
+	for _, action := range delegation.Actions {
+		if action.ID == check.ActionID {
+			coversAction = true
+			break
+		}
+	}
+
+	return coversResource && coversAction
+}
+
+type resourceKey struct {
+	kind  string
+	value string
+}
+
+// findCedarPolicies returns the Cedar policies that may apply to the resource
+// kinds of the checks and, when there are some, the existing resources of the
+// checks along with their attributes.
+func (m *compiledPolicyManager) findCedarPolicies(checks []*Check) ([]*model.CedarPolicy, map[resourceKey]*model.Resource, error) {
+	var (
+		resourceKinds  = []string{""}
+		resourceValues = make([]string, 0, len(checks))
 	)
 
-	if err := m.dispatcher.Dispatch(event.EventTypeCheck, &event.CheckEvent{
-		Principal:      principalID,
-		ResourceKind:   resourceKind,
-		ResourceValue:  resourceValue,
-		Action:         actionID,
-		IsAllowed:      isAllowed,
-		CompiledPolicy: compiledPolicy,
-	}); err != nil {
-		m.logger.Error("unable to dispatch check event", err)
+	for _, check := range checks {
+		resourceKinds = append(resourceKinds, check.ResourceKind)
+		resourceValues = append(resourceValues, check.ResourceValue)
 	}
 
-	return isAllowed, nil
+	cedarPolicies, err := m.decisionStore.FindCedarPolicies(resourceKinds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(cedarPolicies) == 0 {
+		return nil, nil, nil
+	}
+
+	resources, err := m.decisionStore.FindResources(resourceKinds[1:], resourceValues)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resourceByKey = make(map[resourceKey]*model.Resource, len(resources))
+	for _, resource := range resources {
+		resourceByKey[resourceKey{resource.Kind, resource.Value}] = resource
+	}
+
+	return cedarPolicies, resourceByKey, nil
 }
 
-func (m *compiledPolicyManager) isPolicyAllowed(policyIDs []string, resourceKind string, resourceValue string, actionID string) (bool, *model.CompiledPolicy, error) {
-	fields := map[string]repository.FieldValue{
-		"policy_id":      {Operator: "IN", Value: policyIDs},
-		"resource_kind":  {Operator: "=", Value: resourceKind},
-		"resource_value": {Operator: "=", Value: resourceValue},
-		"action_id":      {Operator: "=", Value: actionID},
+// applicableCedarPolicies returns the given Cedar policies that may apply to
+// the resource kind of the check and whose scope and conditions match it.
+func (m *compiledPolicyManager) applicableCedarPolicies(
+	principal *model.Principal,
+	check *Check,
+	cedarPolicies []*model.CedarPolicy,
+	resource *model.Resource,
+	dependencies *decisionDependencies,
+) ([]*model.CedarPolicy, error) {
+	var candidates = make([]*model.CedarPolicy, 0)
+
+	for _, cedarPolicy := range cedarPolicies {
+		if cedarPolicy.ResourceKind == "" || cedarPolicy.ResourceKind == check.ResourceKind {
+			candidates = append(candidates, cedarPolicy)
+			dependencies.policies[cedarPolicy.ID] = true
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
 	}
 
-	commiledPolicy, err := m.repository.GetByFields(fields)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if resourceValue != WildcardValue {
-			return m.isPolicyAllowed(policyIDs, resourceKind, WildcardValue, actionID)
+	request, entities := cedarRequest(principal, check, resource)
+
+	var result = make([]*model.CedarPolicy, 0)
+
+	for _, cedarPolicy := range candidates {
+		parsed, err := m.parseCedarPolicy(cedarPolicy)
+		if err != nil {
+			m.logger.Warn("unable to parse cedar policy", err, slog.String("cedar_policy_id", cedarPolicy.ID))
+			continue
+		}
+
+		applies, err := parsed.Evaluate(request, entities)
+		if err != nil {
+			// As in Cedar, a policy whose evaluation fails is ignored.
+			m.logger.Debug("unable to evaluate cedar policy", err, slog.String("cedar_policy_id", cedarPolicy.ID))
+			continue
 		}
 
-		return false, nil, nil
-	} else if err != nil {
-		return false, nil, fmt.Errorf("unable to retrieve compiled policies: %v", err)
+		if applies {
+			result = append(result, cedarPolicy)
+		}
 	}
 
-	return true, commiledPolicy, nil
+	return result, nil
 }
 
-func (m *compiledPolicyManager) isPrincipalAllowed(principalID string, resourceKind string, resourceValue string, actionID string) (bool, *model.CompiledPolicy, error) {
-	fields := map[string]repository.FieldValue{
-		"principal_id":   {Operator: "=", Value: principalID},
-		"resource_kind":  {Operator: "=", Value: resourceKind},
-		"resource_value": {Operator: "=", Value: resourceValue},
-		"action_id":      {Operator: "=", Value: actionID},
+// cedarRequest builds the Cedar request and entities of a check: the principal
+// is a member of its roles, the resource has the attributes of the matching
+// resource when it exists.
+func cedarRequest(
+	principal *model.Principal,
+	check *Check,
+	resource *model.Resource,
+) (*cedar.Request, cedar.Entities) {
+	principalEntity := &cedar.Entity{
+		UID:        cedar.EntityUID{Type: cedar.PrincipalType, ID: principal.ID},
+		Attributes: cedarAttributes(principal.Attributes),
 	}
 
-	compiledPolicy, err := m.repository.GetByFields(fields)
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		if resourceValue != WildcardValue {
-			return m.isPrincipalAllowed(principalID, resourceKind, WildcardValue, actionID)
+	for _, role := range principal.Roles {
+		principalEntity.Parents = append(principalEntity.Parents, cedar.EntityUID{Type: cedar.RoleType, ID: role.ID})
+	}
+
+	resourceEntity := &cedar.Entity{
+		UID: cedar.EntityUID{Type: check.ResourceKind, ID: check.ResourceValue},
+	}
+
+	if resource != nil {
+		resourceEntity.Attributes = cedarAttributes(resource.Attributes)
+	}
+
+	var cedarContext = make(cedar.Record, len(check.Context))
+	for key, value := range check.Context {
+		cedarContext[key] = cedar.AttributeValue(value)
+	}
+
+	entities := cedar.Entities{}
+	entities.Add(principalEntity, resourceEntity)
+
+	return &cedar.Request{
+		Principal: principalEntity.UID,
+		Action:    cedar.EntityUID{Type: cedar.ActionType, ID: check.ActionID},
+		Resource:  resourceEntity.UID,
+		Context:   cedarContext,
+	}, entities
+}
+
+// parsedCedarPolicy is a parsed Cedar policy, along with the version of the
+// Cedar policy it has been parsed from.
+type parsedCedarPolicy struct {
+	version lib_time.Time
+	policy  *cedar.Policy
+}
+
+// parseCedarPolicy parses the policy source, parsed policies being kept by
+// identifier for next checks as long as the Cedar policy is not updated.
+func (m *compiledPolicyManager) parseCedarPolicy(cedarPolicy *model.CedarPolicy) (*cedar.Policy, error) {
+	if parsed, ok := m.parsedCedarPolicies.Load(cedarPolicy.ID); ok {
+		if parsed := parsed.(*parsedCedarPolicy); parsed.version.Equal(cedarPolicy.UpdatedAt) {
+			return parsed.policy, nil
 		}
+	}
 
-		return false, nil, nil
-	} else if err != nil {
-		return false, nil, fmt.Errorf("unable to retrieve compiled policies: %v", err)
+	policy, err := cedar.ParsePolicy(cedarPolicy.Source)
+	if err != nil {
+		return nil, err
 	}
 
-	return true, compiledPolicy, nil
+	m.parsedCedarPolicies.Store(cedarPolicy.ID, &parsedCedarPolicy{
+		version: cedarPolicy.UpdatedAt,
+		policy:  policy,
+	})
+
+	return policy, nil
+}
+
+// EvictCedarPolicy removes the parsed Cedar policy of given identifier, once
+// updated or deleted.
+func (m *compiledPolicyManager) EvictCedarPolicy(identifier string) {
+	m.parsedCedarPolicies.Delete(identifier)
+}
+
+func cedarAttributes(attributes model.Attributes) cedar.Record {
+	var record = make(cedar.Record, len(attributes))
+
+	for _, attribute := range attributes {
+		record[attribute.Key] = cedar.AttributeValue(attribute.Value)
+	}
+
+	return record
+}
+
+// combine applies the combining algorithm configured for the resource kind on
+// the policies of given compiled policies that currently apply (regarding their
+// validity window and schedule) and on the applicable Cedar policies.
+func (m *compiledPolicyManager) combine(
+	resourceKind string,
+	compiledPolicies []*repository.ApplicableCompiledPolicy,
+	cedarPolicies []*model.CedarPolicy,
+) *decision {
+	algorithm := m.combiningResolver.AlgorithmFor(resourceKind)
+
+	var (
+		now                    = m.clock.Now()
+		policies               = make([]*model.Policy, 0)
+		compiledPolicyByPolicy = map[string]*model.CompiledPolicy{}
+		evaluated              = map[string]bool{}
+	)
+
+	for _, compiledPolicy := range compiledPolicies {
+		if evaluated[compiledPolicy.PolicyID] {
+			continue
+		}
+
+		evaluated[compiledPolicy.PolicyID] = true
+
+		policy := compiledPolicy.Policy()
+
+		isActive, err := policy.IsActive(now)
+		if err != nil {
+			m.logger.Warn("unable to evaluate policy time constraints", err, slog.String("policy_id", policy.ID))
+			continue
+		}
+
+		if !isActive {
+			continue
+		}
+
+		compiledPolicyByPolicy[policy.ID] = &compiledPolicy.CompiledPolicy
+		policies = append(policies, policy)
+	}
+
+	var cedarPolicyByPolicy = map[*model.Policy]*model.CedarPolicy{}
+
+	for _, cedarPolicy := range cedarPolicies {
+		policy := &model.Policy{ID: cedarPolicy.ID, Effect: cedarPolicy.Effect}
+
+		cedarPolicyByPolicy[policy] = cedarPolicy
+		policies = append(policies, policy)
+	}
+
+	policy, isAllowed := algorithm.Resolve(policies)
+	if policy == nil {
+		return &decision{algorithm: algorithm}
+	}
+
+	if cedarPolicy, ok := cedarPolicyByPolicy[policy]; ok {
+		return &decision{
+			isAllowed:   isAllowed,
+			algorithm:   algorithm,
+			cedarPolicy: cedarPolicy,
+		}
+	}
+
+	return &decision{
+		isAllowed:      isAllowed,
+		algorithm:      algorithm,
+		compiledPolicy: compiledPolicyByPolicy[policy.ID],
+	}
 }
@@ -7,6 +7,7 @@ package manager
 import (
 	reflect "reflect"
 
+	database "github.com/eko/authz/backend/internal/database"
 	model "github.com/eko/authz/backend/internal/entity/model"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -48,6 +49,32 @@ func (mr *MockCompiledPolicyMockRecorder) Create(compiledPolicy interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCompiledPolicy)(nil).Create), compiledPolicy)
 }
 
+// DropShadow mocks base method.
+func (m *MockCompiledPolicy) DropShadow() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropShadow")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropShadow indicates an expected call of DropShadow.
+func (mr *MockCompiledPolicyMockRecorder) DropShadow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropShadow", reflect.TypeOf((*MockCompiledPolicy)(nil).DropShadow))
+}
+
+// EvictCedarPolicy mocks base method.
+func (m *MockCompiledPolicy) EvictCedarPolicy(identifier string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictCedarPolicy", identifier)
+}
+
+// EvictCedarPolicy indicates an expected call of EvictCedarPolicy.
+func (mr *MockCompiledPolicyMockRecorder) EvictCedarPolicy(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictCedarPolicy", reflect.TypeOf((*MockCompiledPolicy)(nil).EvictCedarPolicy), identifier)
+}
+
 // GetRepository mocks base method.
 func (m *MockCompiledPolicy) GetRepository() CompiledPolicyRepository {
 	m.ctrl.T.Helper()
@@ -76,3 +103,120 @@ func (mr *MockCompiledPolicyMockRecorder) IsAllowed(principalID, resourceKind, r
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAllowed", reflect.TypeOf((*MockCompiledPolicy)(nil).IsAllowed), principalID, resourceKind, resourceValue, actionID)
 }
+
+// IsAllowedBulk mocks base method.
+func (m *MockCompiledPolicy) IsAllowedBulk(checks []*Check) ([]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAllowedBulk", checks)
+	ret0, _ := ret[0].([]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAllowedBulk indicates an expected call of IsAllowedBulk.
+func (mr *MockCompiledPolicyMockRecorder) IsAllowedBulk(checks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAllowedBulk", reflect.TypeOf((*MockCompiledPolicy)(nil).IsAllowedBulk), checks)
+}
+
+// IsAllowedWithContext mocks base method.
+func (m *MockCompiledPolicy) IsAllowedWithContext(principalID, resourceKind, resourceValue, actionID string, context map[string]string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAllowedWithContext", principalID, resourceKind, resourceValue, actionID, context)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAllowedWithContext indicates an expected call of IsAllowedWithContext.
+func (mr *MockCompiledPolicyMockRecorder) IsAllowedWithContext(principalID, resourceKind, resourceValue, actionID, context interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAllowedWithContext", reflect.TypeOf((*MockCompiledPolicy)(nil).IsAllowedWithContext), principalID, resourceKind, resourceValue, actionID, context)
+}
+
+// IsDirectlyAllowed mocks base method.
+func (m *MockCompiledPolicy) IsDirectlyAllowed(principalID, resourceKind, resourceValue, actionID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDirectlyAllowed", principalID, resourceKind, resourceValue, actionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDirectlyAllowed indicates an expected call of IsDirectlyAllowed.
+func (mr *MockCompiledPolicyMockRecorder) IsDirectlyAllowed(principalID, resourceKind, resourceValue, actionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDirectlyAllowed", reflect.TypeOf((*MockCompiledPolicy)(nil).IsDirectlyAllowed), principalID, resourceKind, resourceValue, actionID)
+}
+
+// NewShadow mocks base method.
+func (m *MockCompiledPolicy) NewShadow() (CompiledPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewShadow")
+	ret0, _ := ret[0].(CompiledPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewShadow indicates an expected call of NewShadow.
+func (mr *MockCompiledPolicyMockRecorder) NewShadow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewShadow", reflect.TypeOf((*MockCompiledPolicy)(nil).NewShadow))
+}
+
+// NextVersion mocks base method.
+func (m *MockCompiledPolicy) NextVersion() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextVersion")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextVersion indicates an expected call of NextVersion.
+func (mr *MockCompiledPolicyMockRecorder) NextVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextVersion", reflect.TypeOf((*MockCompiledPolicy)(nil).NextVersion))
+}
+
+// PruneVersions mocks base method.
+func (m *MockCompiledPolicy) PruneVersions(version int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneVersions", version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneVersions indicates an expected call of PruneVersions.
+func (mr *MockCompiledPolicyMockRecorder) PruneVersions(version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneVersions", reflect.TypeOf((*MockCompiledPolicy)(nil).PruneVersions), version)
+}
+
+// SwapShadow mocks base method.
+func (m *MockCompiledPolicy) SwapShadow() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwapShadow")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SwapShadow indicates an expected call of SwapShadow.
+func (mr *MockCompiledPolicyMockRecorder) SwapShadow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapShadow", reflect.TypeOf((*MockCompiledPolicy)(nil).SwapShadow))
+}
+
+// WithTransaction mocks base method.
+func (m *MockCompiledPolicy) WithTransaction(transaction database.Transaction) CompiledPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", transaction)
+	ret0, _ := ret[0].(CompiledPolicy)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockCompiledPolicyMockRecorder) WithTransaction(transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockCompiledPolicy)(nil).WithTransaction), transaction)
+}
@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompiledPolicy_ParseCedarPolicy(t *testing.T) {
+	// Given
+	compiledManager := &compiledPolicyManager{
+		parsedCedarPolicies: &sync.Map{},
+	}
+
+	updatedAt := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+
+	cedarPolicy := &model.CedarPolicy{
+		ID:        "readers",
+		Source:    `permit(principal, action == Action::"read", resource is post);`,
+		UpdatedAt: updatedAt,
+	}
+
+	assert := assert.New(t)
+
+	parsed, err := compiledManager.parseCedarPolicy(cedarPolicy)
+	assert.Nil(err)
+
+	// When - the Cedar policy is unchanged
+	reused, err := compiledManager.parseCedarPolicy(cedarPolicy)
+
+	// Then
+	assert.Nil(err)
+	assert.Same(parsed, reused)
+
+	// When - the Cedar policy is updated
+	updated, err := compiledManager.parseCedarPolicy(&model.CedarPolicy{
+		ID:        "readers",
+		Source:    `forbid(principal, action == Action::"read", resource is post);`,
+		UpdatedAt: updatedAt.Add(lib_time.Second),
+	})
+
+	// Then
+	assert.Nil(err)
+	assert.NotSame(parsed, updated)
+
+	var count int
+	compiledManager.parsedCedarPolicies.Range(func(any, any) bool {
+		count++
+		return true
+	})
+	assert.Equal(1, count)
+
+	// When - the Cedar policy is evicted
+	compiledManager.EvictCedarPolicy("readers")
+
+	// Then
+	_, ok := compiledManager.parsedCedarPolicies.Load("readers")
+	assert.False(ok)
+}
@@ -0,0 +1,204 @@
+package manager
+
+import (
+	"container/list"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+)
+
+type decisionKey struct {
+	principalID   string
+	resourceKind  string
+	resourceValue string
+	actionID      string
+}
+
+// decisionDependencies are the data a decision has been computed from: the
+// principal and delegators, their roles and the policies taken into account.
+type decisionDependencies struct {
+	principals map[string]bool
+	roles      map[string]bool
+	policies   map[string]bool
+}
+
+func newDecisionDependencies() *decisionDependencies {
+	return &decisionDependencies{
+		principals: map[string]bool{},
+		roles:      map[string]bool{},
+		policies:   map[string]bool{},
+	}
+}
+
+func (d *decisionDependencies) addPrincipal(principal *model.Principal) {
+	d.principals[principal.ID] = true
+
+	for _, role := range principal.Roles {
+		d.roles[role.ID] = true
+	}
+}
+
+type cachedDecision struct {
+	key          decisionKey
+	decision     *decision
+	delegation   *model.Delegation
+	dependencies *decisionDependencies
+	expiresAt    lib_time.Time
+}
+
+// DecisionCache keeps the decisions of recent checks in memory. Cached
+// decisions are invalidated when the data they depend on changes.
+type DecisionCache struct {
+	clock time.Clock
+	size  int
+	ttl   lib_time.Duration
+
+	mutex      sync.Mutex
+	entries    map[decisionKey]*list.Element
+	recency    *list.List
+	generation uint64
+}
+
+// NewDecisionCache initializes a least recently used decision cache, disabled
+// when its configured size is zero.
+func NewDecisionCache(
+	cfg *configs.App,
+	clock time.Clock,
+) *DecisionCache {
+	return &DecisionCache{
+		clock:   clock,
+		size:    cfg.DecisionCacheSize,
+		ttl:     cfg.DecisionCacheTTL,
+		entries: map[decisionKey]*list.Element{},
+		recency: list.New(),
+	}
+}
+
+func (c *DecisionCache) enabled() bool {
+	return c.size > 0
+}
+
+// get returns the cached decision of the given key, if any and not expired,
+// along with the generation to give back when adding a new decision.
+func (c *DecisionCache) get(key decisionKey) (*cachedDecision, uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation
+	}
+
+	cached := element.Value.(*cachedDecision)
+
+	if !c.clock.Now().Before(cached.expiresAt) {
+		c.remove(element)
+		return nil, c.generation
+	}
+
+	c.recency.MoveToFront(element)
+
+	return cached, c.generation
+}
+
+// add caches a decision computed since the given generation. It is ignored
+// when an invalidation happened meanwhile, as the decision may be outdated.
+func (c *DecisionCache) add(cached *cachedDecision, generation uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if element, ok := c.entries[cached.key]; ok {
+		c.remove(element)
+	}
+
+	cached.expiresAt = c.clock.Now().Add(c.ttl)
+	c.entries[cached.key] = c.recency.PushFront(cached)
+
+	for c.recency.Len() > c.size {
+		c.remove(c.recency.Back())
+	}
+}
+
+func (c *DecisionCache) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*cachedDecision).key)
+}
+
+// invalidate removes the cached decisions matching the given function.
+func (c *DecisionCache) invalidate(matches func(cached *cachedDecision) bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generation++
+
+	for element := c.recency.Front(); element != nil; {
+		next := element.Next()
+
+		if matches(element.Value.(*cachedDecision)) {
+			c.remove(element)
+		}
+
+		element = next
+	}
+}
+
+// InvalidateCedarPolicy invalidates decisions the Cedar policy has been taken
+// into account for, or may now apply to.
+func (c *DecisionCache) InvalidateCedarPolicy(cedarPolicy *model.CedarPolicy) {
+	c.invalidate(func(cached *cachedDecision) bool {
+		return cedarPolicy.ResourceKind == "" ||
+			cached.key.resourceKind == cedarPolicy.ResourceKind ||
+			cached.dependencies.policies[cedarPolicy.ID]
+	})
+}
+
+// InvalidatePolicy invalidates decisions the policy has been taken into
+// account for, or may now apply to.
+func (c *DecisionCache) InvalidatePolicy(policy *model.Policy) {
+	var kinds = map[string]bool{}
+	for _, resource := range policy.Resources {
+		kinds[resource.Kind] = true
+	}
+
+	c.invalidate(func(cached *cachedDecision) bool {
+		return kinds[cached.key.resourceKind] || cached.dependencies.policies[policy.ID]
+	})
+}
+
+// InvalidatePrincipal invalidates decisions of the principal, including the
+// ones of its delegates.
+func (c *DecisionCache) InvalidatePrincipal(principalID string) {
+	c.invalidate(func(cached *cachedDecision) bool {
+		return cached.dependencies.principals[principalID]
+	})
+}
+
+// InvalidateResource invalidates decisions on the resource, or on all the
+// resources of the kind for the wildcard value.
+func (c *DecisionCache) InvalidateResource(resourceKind string, resourceValue string) {
+	c.invalidate(func(cached *cachedDecision) bool {
+		return cached.key.resourceKind == resourceKind &&
+			(resourceValue == WildcardValue || cached.key.resourceValue == resourceValue)
+	})
+}
+
+// InvalidateRole invalidates decisions of the principals having the role.
+func (c *DecisionCache) InvalidateRole(roleID string) {
+	c.invalidate(func(cached *cachedDecision) bool {
+		return cached.dependencies.roles[roleID]
+	})
+}
+
+// Purge invalidates all the cached decisions.
+func (c *DecisionCache) Purge() {
+	c.invalidate(func(*cachedDecision) bool {
+		return true
+	})
+}
@@ -0,0 +1,200 @@
+package manager
+
+import (
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDecisionCache(t *testing.T, size int) *DecisionCache {
+	ctrl := gomock.NewController(t)
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(lib_time.Date(2023, 1, 1, 0, 0, 0, 0, lib_time.UTC)).AnyTimes()
+
+	return NewDecisionCache(&configs.App{DecisionCacheSize: size, DecisionCacheTTL: lib_time.Minute}, clock)
+}
+
+// cacheDecision caches an allowed decision of the principal on the resource,
+// depending on the given role and policy.
+func cacheDecision(cache *DecisionCache, principalID, resourceKind, resourceValue, roleID, policyID string) decisionKey {
+	key := decisionKey{principalID, resourceKind, resourceValue, "read"}
+
+	dependencies := newDecisionDependencies()
+	dependencies.addPrincipal(&model.Principal{ID: principalID, Roles: []*model.Role{{ID: roleID}}})
+	dependencies.policies[policyID] = true
+
+	_, generation := cache.get(key)
+	cache.add(&cachedDecision{key: key, decision: &decision{isAllowed: true}, dependencies: dependencies}, generation)
+
+	return key
+}
+
+func isCached(cache *DecisionCache, key decisionKey) bool {
+	cached, _ := cache.get(key)
+	return cached != nil
+}
+
+func TestNewDecisionCache(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	clock := time.NewMockClock(ctrl)
+
+	// When
+	cache := NewDecisionCache(&configs.App{DecisionCacheSize: 10, DecisionCacheTTL: lib_time.Minute}, clock)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Equal(clock, cache.clock)
+	assert.Equal(10, cache.size)
+	assert.Equal(lib_time.Minute, cache.ttl)
+	assert.True(cache.enabled())
+
+	assert.False(NewDecisionCache(&configs.App{}, clock).enabled())
+}
+
+func TestDecisionCache_Get_WhenLeastRecentlyUsedIsEvicted(t *testing.T) {
+	// Given
+	cache := newTestDecisionCache(t, 2)
+
+	first := cacheDecision(cache, "user-1", "post", "1", "role-1", "policy-1")
+	second := cacheDecision(cache, "user-2", "post", "1", "role-1", "policy-1")
+
+	// The first decision becomes the most recently used one.
+	assert.True(t, isCached(cache, first))
+
+	// When
+	third := cacheDecision(cache, "user-3", "post", "1", "role-1", "policy-1")
+
+	// Then
+	assert := assert.New(t)
+
+	assert.True(isCached(cache, first))
+	assert.False(isCached(cache, second))
+	assert.True(isCached(cache, third))
+}
+
+func TestDecisionCache_Get_WhenExpired(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	now := lib_time.Date(2023, 1, 1, 0, 0, 0, 0, lib_time.UTC)
+
+	clock := time.NewMockClock(ctrl)
+	gomock.InOrder(
+		clock.EXPECT().Now().Return(now),
+		clock.EXPECT().Now().Return(now.Add(30*lib_time.Second)),
+		clock.EXPECT().Now().Return(now.Add(lib_time.Minute)),
+	)
+
+	cache := NewDecisionCache(&configs.App{DecisionCacheSize: 10, DecisionCacheTTL: lib_time.Minute}, clock)
+
+	key := decisionKey{"user-1", "post", "1", "read"}
+	cache.add(&cachedDecision{key: key, decision: &decision{}, dependencies: newDecisionDependencies()}, 0)
+
+	// When - Then
+	assert.True(t, isCached(cache, key))
+	assert.False(t, isCached(cache, key))
+}
+
+func TestDecisionCache_Add_WhenInvalidatedMeanwhile(t *testing.T) {
+	// Given
+	cache := newTestDecisionCache(t, 10)
+
+	key := decisionKey{"user-1", "post", "1", "read"}
+
+	_, generation := cache.get(key)
+
+	// When
+	cache.InvalidatePrincipal("user-2")
+	cache.add(&cachedDecision{key: key, decision: &decision{}, dependencies: newDecisionDependencies()}, generation)
+
+	// Then
+	assert.False(t, isCached(cache, key))
+}
+
+func TestDecisionCache_Invalidate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		invalidate func(cache *DecisionCache)
+		expected   []bool
+	}{
+		{
+			name:       "policy taken into account",
+			invalidate: func(cache *DecisionCache) { cache.InvalidatePolicy(&model.Policy{ID: "policy-1"}) },
+			expected:   []bool{false, true, true},
+		},
+		{
+			name: "policy applying to the resource kind",
+			invalidate: func(cache *DecisionCache) {
+				cache.InvalidatePolicy(&model.Policy{ID: "policy-3", Resources: []*model.Resource{{Kind: "doc", Value: "*"}}})
+			},
+			expected: []bool{true, true, false},
+		},
+		{
+			name: "cedar policy applying to the resource kind",
+			invalidate: func(cache *DecisionCache) {
+				cache.InvalidateCedarPolicy(&model.CedarPolicy{ID: "cedar-1", ResourceKind: "doc"})
+			},
+			expected: []bool{true, true, false},
+		},
+		{
+			name:       "cedar policy applying to all resource kinds",
+			invalidate: func(cache *DecisionCache) { cache.InvalidateCedarPolicy(&model.CedarPolicy{ID: "cedar-1"}) },
+			expected:   []bool{false, false, false},
+		},
+		{
+			name:       "principal",
+			invalidate: func(cache *DecisionCache) { cache.InvalidatePrincipal("user-2") },
+			expected:   []bool{true, false, true},
+		},
+		{
+			name:       "resource",
+			invalidate: func(cache *DecisionCache) { cache.InvalidateResource("post", "2") },
+			expected:   []bool{true, false, true},
+		},
+		{
+			name:       "all resources of a kind",
+			invalidate: func(cache *DecisionCache) { cache.InvalidateResource("post", WildcardValue) },
+			expected:   []bool{false, false, true},
+		},
+		{
+			name:       "role",
+			invalidate: func(cache *DecisionCache) { cache.InvalidateRole("role-2") },
+			expected:   []bool{true, true, false},
+		},
+		{
+			name:       "purge",
+			invalidate: func(cache *DecisionCache) { cache.Purge() },
+			expected:   []bool{false, false, false},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Given
+			cache := newTestDecisionCache(t, 10)
+
+			keys := []decisionKey{
+				cacheDecision(cache, "user-1", "post", "1", "role-1", "policy-1"),
+				cacheDecision(cache, "user-2", "post", "2", "role-1", "policy-2"),
+				cacheDecision(cache, "user-3", "doc", "1", "role-2", "policy-2"),
+			}
+
+			// When
+			testCase.invalidate(cache)
+
+			// Then
+			for index, key := range keys {
+				assert.Equal(t, testCase.expected[index], isCached(cache, key), "decision %d", index)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"fmt"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+)
+
+const (
+	// DecisionStoreSQL looks up decision data in the database on each check.
+	DecisionStoreSQL = "sql"
+
+	// DecisionStoreMemory looks up decision data in an in-memory index.
+	DecisionStoreMemory = "memory"
+)
+
+// DecisionStore finds the data decisions are taken from: compiled policies,
+// principals, resources, Cedar policies and delegations.
+// Refresh methods are called once changes are committed to the database, for
+// stores keeping a copy of this data to take them into account.
+type DecisionStore interface {
+	FindApplicable(principals []*model.Principal, lookups []*repository.CompiledPolicyLookup) ([]*repository.ApplicableCompiledPolicy, error)
+	FindCedarPolicies(resourceKinds []string) ([]*model.CedarPolicy, error)
+	FindDelegations(checks []*Check, activeAt lib_time.Time) ([]*model.Delegation, error)
+	FindPrincipals(principalIDs []string) ([]*model.Principal, error)
+	FindResources(resourceKinds []string, resourceValues []string) ([]*model.Resource, error)
+	Load() error
+	RefreshCedarPolicy(cedarPolicyID string) error
+	RefreshDelegation(delegationID string) error
+	RefreshPolicy(policyID string) error
+	RefreshPrincipal(principalID string) error
+	RefreshResource(resourceKind string, resourceValue string) error
+	RefreshRole(roleID string) error
+}
+
+// NewDecisionStore initializes the decision store configured.
+func NewDecisionStore(
+	cfg *configs.App,
+	compiledRepository CompiledPolicyRepository,
+	cedarPolicyRepository CedarPolicyRepository,
+	delegationRepository DelegationRepository,
+	policyRepository PolicyRepository,
+	principalRepository repository.Base[model.Principal],
+	resourceRepository repository.Resource,
+	roleRepository RoleRepository,
+	clock time.Clock,
+) (DecisionStore, error) {
+	switch cfg.DecisionStore {
+	case DecisionStoreSQL:
+		return &sqlDecisionStore{
+			repository:            compiledRepository,
+			cedarPolicyRepository: cedarPolicyRepository,
+			delegationRepository:  delegationRepository,
+			principalRepository:   principalRepository,
+			resourceRepository:    resourceRepository,
+		}, nil
+
+	case DecisionStoreMemory:
+		return newMemoryDecisionStore(
+			compiledRepository,
+			cedarPolicyRepository,
+			delegationRepository,
+			policyRepository,
+			principalRepository,
+			resourceRepository,
+			roleRepository,
+			clock,
+		), nil
+
+	default:
+		return nil, fmt.Errorf("unknown decision store %q", cfg.DecisionStore)
+	}
+}
+
+// sqlDecisionStore looks up decision data in the database, so there is
+// nothing to load or refresh.
+type sqlDecisionStore struct {
+	repository            CompiledPolicyRepository
+	cedarPolicyRepository CedarPolicyRepository
+	delegationRepository  DelegationRepository
+	principalRepository   repository.Base[model.Principal]
+	resourceRepository    repository.Resource
+}
+
+func (s *sqlDecisionStore) FindApplicable(
+	principals []*model.Principal,
+	lookups []*repository.CompiledPolicyLookup,
+) ([]*repository.ApplicableCompiledPolicy, error) {
+	var principalIDs = make([]string, 0, len(principals))
+	for _, principal := range principals {
+		principalIDs = append(principalIDs, principal.ID)
+	}
+
+	return s.repository.FindApplicable(principalIDs, lookups)
+}
+
+// FindCedarPolicies returns the Cedar policies of the given resource kinds,
+// the empty kind being the one of policies applying to all of them.
+func (s *sqlDecisionStore) FindCedarPolicies(resourceKinds []string) ([]*model.CedarPolicy, error) {
+	cedarPolicies, _, err := s.cedarPolicyRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"resource_kind": {Operator: "IN", Value: resourceKinds},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve cedar policies: %v", err)
+	}
+
+	return cedarPolicies, nil
+}
+
+// FindDelegations returns the delegations active at the given time, given to
+// the principals of the checks on some of their resources and actions, along
+// with their resources and actions.
+func (s *sqlDecisionStore) FindDelegations(checks []*Check, activeAt lib_time.Time) ([]*model.Delegation, error) {
+	var (
+		principalIDs   = make([]string, 0, len(checks))
+		resourceKinds  = make([]string, 0, len(checks))
+		resourceValues = []string{WildcardValue}
+		actionIDs      = make([]string, 0, len(checks))
+	)
+
+	for _, check := range checks {
+		principalIDs = append(principalIDs, check.PrincipalID)
+		resourceKinds = append(resourceKinds, check.ResourceKind)
+		resourceValues = append(resourceValues, check.ResourceValue)
+		actionIDs = append(actionIDs, check.ActionID)
+	}
+
+	delegations, _, err := s.delegationRepository.Find(
+		repository.WithJoin(
+			"INNER JOIN authz_delegations_resources ON authz_delegations_resources.delegation_id = authz_delegations.id",
+			"INNER JOIN authz_resources ON authz_resources.id = authz_delegations_resources.resource_id",
+			"INNER JOIN authz_delegations_actions ON authz_delegations_actions.delegation_id = authz_delegations.id",
+		),
+		repository.WithFilter(map[string]repository.FieldValue{
+			"authz_delegations.delegate_id":       {Operator: "IN", Value: principalIDs},
+			"authz_delegations.expires_at":        {Operator: ">", Value: activeAt},
+			"authz_resources.kind":                {Operator: "IN", Value: resourceKinds},
+			"authz_resources.value":               {Operator: "IN", Value: resourceValues},
+			"authz_delegations_actions.action_id": {Operator: "IN", Value: actionIDs},
+		}),
+		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSort("authz_delegations.id"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve delegations: %v", err)
+	}
+
+	return delegations, nil
+}
+
+// FindPrincipals returns the given principals along with their roles and
+// attributes. Principals that do not exist are omitted.
+func (s *sqlDecisionStore) FindPrincipals(principalIDs []string) ([]*model.Principal, error) {
+	principals, _, err := s.principalRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "IN", Value: principalIDs},
+		}),
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	return principals, nil
+}
+
+// FindResources returns the resources of the given kinds and values along
+// with their attributes.
+func (s *sqlDecisionStore) FindResources(resourceKinds []string, resourceValues []string) ([]*model.Resource, error) {
+	resources, _, err := s.resourceRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"kind":  {Operator: "IN", Value: resourceKinds},
+			"value": {Operator: "IN", Value: resourceValues},
+		}),
+		repository.WithPreloads("Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resources: %v", err)
+	}
+
+	return resources, nil
+}
+
+func (s *sqlDecisionStore) Load() error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshCedarPolicy(string) error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshDelegation(string) error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshPolicy(string) error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshPrincipal(string) error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshResource(string, string) error {
+	return nil
+}
+
+func (s *sqlDecisionStore) RefreshRole(string) error {
+	return nil
+}
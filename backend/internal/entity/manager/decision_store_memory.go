@@ -0,0 +1,807 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"gorm.io/gorm"
+)
+
+// compiledKey identifies a compiled policy in memory.
+type compiledKey struct {
+	policyID      string
+	principalID   string
+	resourceKind  string
+	resourceValue string
+	actionID      string
+}
+
+func newCompiledKey(compiledPolicy *model.CompiledPolicy) compiledKey {
+	return compiledKey{
+		policyID:      compiledPolicy.PolicyID,
+		principalID:   compiledPolicy.PrincipalID,
+		resourceKind:  compiledPolicy.ResourceKind,
+		resourceValue: compiledPolicy.ResourceValue,
+		actionID:      compiledPolicy.ActionID,
+	}
+}
+
+type lookupKey struct {
+	resourceKind  string
+	resourceValue string
+	actionID      string
+}
+
+type compiledSet map[compiledKey]struct{}
+
+// compiledIndex indexes compiled policies by resource and action for lookups,
+// and by the targets they are compiled for, to replace them once recompiled.
+type compiledIndex struct {
+	byLookup    map[lookupKey]compiledSet
+	byPolicy    map[string]compiledSet
+	byPrincipal map[string]compiledSet
+	byResource  map[resourceKey]compiledSet
+}
+
+func newCompiledIndex() *compiledIndex {
+	return &compiledIndex{
+		byLookup:    map[lookupKey]compiledSet{},
+		byPolicy:    map[string]compiledSet{},
+		byPrincipal: map[string]compiledSet{},
+		byResource:  map[resourceKey]compiledSet{},
+	}
+}
+
+func (i *compiledIndex) add(key compiledKey) {
+	addToSet(i.byLookup, lookupKey{key.resourceKind, key.resourceValue, key.actionID}, key)
+	addToSet(i.byPolicy, key.policyID, key)
+	addToSet(i.byResource, resourceKey{key.resourceKind, key.resourceValue}, key)
+
+	if key.principalID != "" {
+		addToSet(i.byPrincipal, key.principalID, key)
+	}
+}
+
+func (i *compiledIndex) remove(key compiledKey) {
+	removeFromSet(i.byLookup, lookupKey{key.resourceKind, key.resourceValue, key.actionID}, key)
+	removeFromSet(i.byPolicy, key.policyID, key)
+	removeFromSet(i.byResource, resourceKey{key.resourceKind, key.resourceValue}, key)
+	removeFromSet(i.byPrincipal, key.principalID, key)
+}
+
+// replace removes the given compiled policies, then adds the ones read from
+// the database in their place.
+func (i *compiledIndex) replace(keys []compiledKey, compiledPolicies []*model.CompiledPolicy) {
+	for _, key := range keys {
+		i.remove(key)
+	}
+
+	for _, compiledPolicy := range compiledPolicies {
+		i.add(newCompiledKey(compiledPolicy))
+	}
+}
+
+func addToSet[K comparable](sets map[K]compiledSet, setKey K, key compiledKey) {
+	set, ok := sets[setKey]
+	if !ok {
+		set = compiledSet{}
+		sets[setKey] = set
+	}
+
+	set[key] = struct{}{}
+}
+
+func removeFromSet[K comparable](sets map[K]compiledSet, setKey K, key compiledKey) {
+	set, ok := sets[setKey]
+	if !ok {
+		return
+	}
+
+	delete(set, key)
+
+	if len(set) == 0 {
+		delete(sets, setKey)
+	}
+}
+
+// keysOf returns the keys of the set matching the given filter.
+func keysOf(set compiledSet, filter func(key compiledKey) bool) []compiledKey {
+	var keys = make([]compiledKey, 0, len(set))
+
+	for key := range set {
+		if filter(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// memoryDecisionStore looks up compiled policies in memory, along with the
+// policies they come from, the policies of roles and the other data checks
+// are decided from: principals, resources, Cedar policies and active
+// delegations. It is loaded from the database, then refreshed once
+// compilations and changes are committed.
+// Data kept in memory is never modified once handed to checks: it is replaced.
+type memoryDecisionStore struct {
+	compiledRepository    CompiledPolicyRepository
+	cedarPolicyRepository CedarPolicyRepository
+	delegationRepository  DelegationRepository
+	policyRepository      PolicyRepository
+	principalRepository   repository.Base[model.Principal]
+	resourceRepository    repository.Resource
+	roleRepository        RoleRepository
+	clock                 time.Clock
+
+	// refreshMutex serializes loads and refreshes, so data read from the
+	// database never replaces data read after it.
+	refreshMutex sync.Mutex
+
+	mutex         sync.RWMutex
+	index         *compiledIndex
+	policies      map[string]*model.Policy
+	rolePolicies  map[string][]string
+	principals    map[string]*model.Principal
+	resources     map[resourceKey]*model.Resource
+	cedarPolicies map[string]*model.CedarPolicy
+	delegations   *delegationIndex
+}
+
+func newMemoryDecisionStore(
+	compiledRepository CompiledPolicyRepository,
+	cedarPolicyRepository CedarPolicyRepository,
+	delegationRepository DelegationRepository,
+	policyRepository PolicyRepository,
+	principalRepository repository.Base[model.Principal],
+	resourceRepository repository.Resource,
+	roleRepository RoleRepository,
+	clock time.Clock,
+) *memoryDecisionStore {
+	return &memoryDecisionStore{
+		compiledRepository:    compiledRepository,
+		cedarPolicyRepository: cedarPolicyRepository,
+		delegationRepository:  delegationRepository,
+		policyRepository:      policyRepository,
+		principalRepository:   principalRepository,
+		resourceRepository:    resourceRepository,
+		roleRepository:        roleRepository,
+		clock:                 clock,
+		index:                 newCompiledIndex(),
+		policies:              map[string]*model.Policy{},
+		rolePolicies:          map[string][]string{},
+		principals:            map[string]*model.Principal{},
+		resources:             map[resourceKey]*model.Resource{},
+		cedarPolicies:         map[string]*model.CedarPolicy{},
+		delegations:           newDelegationIndex(),
+	}
+}
+
+// FindApplicable returns the compiled policies applying to the given
+// principals (directly or through their roles, which have to be loaded) on
+// the given resources (or the wildcard value of their kind) and actions, as
+// the SQL decision store does.
+func (s *memoryDecisionStore) FindApplicable(
+	principals []*model.Principal,
+	lookups []*repository.CompiledPolicyLookup,
+) ([]*repository.ApplicableCompiledPolicy, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var (
+		principalIDs   = map[string]bool{}
+		rolePrincipals = map[string]map[string]bool{}
+	)
+
+	for _, principal := range principals {
+		principalIDs[principal.ID] = true
+
+		for _, role := range principal.Roles {
+			for _, policyID := range s.rolePolicies[role.ID] {
+				if rolePrincipals[policyID] == nil {
+					rolePrincipals[policyID] = map[string]bool{}
+				}
+
+				rolePrincipals[policyID][principal.ID] = true
+			}
+		}
+	}
+
+	var (
+		result     = make([]*repository.ApplicableCompiledPolicy, 0)
+		seenLookup = map[lookupKey]bool{}
+	)
+
+	for _, lookup := range lookups {
+		resourceValues := []string{lookup.ResourceValue}
+		if lookup.ResourceValue != WildcardValue {
+			resourceValues = append(resourceValues, WildcardValue)
+		}
+
+		for _, resourceValue := range resourceValues {
+			key := lookupKey{lookup.ResourceKind, resourceValue, lookup.ActionID}
+			if seenLookup[key] {
+				continue
+			}
+
+			seenLookup[key] = true
+
+			for compiled := range s.index.byLookup[key] {
+				policy, ok := s.policies[compiled.policyID]
+				if !ok {
+					continue
+				}
+
+				if len(rolePrincipals[compiled.policyID]) == 0 {
+					if principalIDs[compiled.principalID] {
+						result = append(result, newApplicableCompiledPolicy(compiled, policy, ""))
+					}
+
+					continue
+				}
+
+				for rolePrincipalID := range rolePrincipals[compiled.policyID] {
+					result = append(result, newApplicableCompiledPolicy(compiled, policy, rolePrincipalID))
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func newApplicableCompiledPolicy(key compiledKey, policy *model.Policy, rolePrincipalID string) *repository.ApplicableCompiledPolicy {
+	return &repository.ApplicableCompiledPolicy{
+		CompiledPolicy: model.CompiledPolicy{
+			PolicyID:      key.policyID,
+			PrincipalID:   key.principalID,
+			ResourceKind:  key.resourceKind,
+			ResourceValue: key.resourceValue,
+			ActionID:      key.actionID,
+		},
+		RolePrincipalID:  rolePrincipalID,
+		Effect:           policy.Effect,
+		Priority:         policy.Priority,
+		NotBefore:        policy.NotBefore,
+		NotAfter:         policy.NotAfter,
+		Schedule:         policy.Schedule,
+		ScheduleDuration: policy.ScheduleDuration,
+		ScheduleTimezone: policy.ScheduleTimezone,
+	}
+}
+
+// FindCedarPolicies returns the Cedar policies of the given resource kinds,
+// the empty kind being the one of policies applying to all of them.
+func (s *memoryDecisionStore) FindCedarPolicies(resourceKinds []string) ([]*model.CedarPolicy, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var kinds = make(map[string]bool, len(resourceKinds))
+	for _, resourceKind := range resourceKinds {
+		kinds[resourceKind] = true
+	}
+
+	var result = make([]*model.CedarPolicy, 0)
+
+	for _, cedarPolicy := range s.cedarPolicies {
+		if kinds[cedarPolicy.ResourceKind] {
+			result = append(result, cedarPolicy)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// FindDelegations returns the delegations active at the given time given to
+// the principals of the checks. Whether they cover the resources and actions
+// of the checks is left to the caller.
+func (s *memoryDecisionStore) FindDelegations(checks []*Check, activeAt lib_time.Time) ([]*model.Delegation, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var (
+		result = make([]*model.Delegation, 0)
+		seen   = map[string]bool{}
+	)
+
+	for _, check := range checks {
+		if seen[check.PrincipalID] {
+			continue
+		}
+
+		seen[check.PrincipalID] = true
+
+		for _, delegation := range s.delegations.byDelegate[check.PrincipalID] {
+			if delegation.IsActive(activeAt) {
+				result = append(result, delegation)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// FindPrincipals returns the given principals along with their roles and
+// attributes. Principals that are not in memory yet, being created but not
+// refreshed, are read from the database. Principals that do not exist are
+// omitted.
+func (s *memoryDecisionStore) FindPrincipals(principalIDs []string) ([]*model.Principal, error) {
+	var (
+		result  = make([]*model.Principal, 0, len(principalIDs))
+		missing = make([]string, 0)
+	)
+
+	s.mutex.RLock()
+
+	for _, principalID := range principalIDs {
+		if principal, ok := s.principals[principalID]; ok {
+			result = append(result, principal)
+		} else {
+			missing = append(missing, principalID)
+		}
+	}
+
+	s.mutex.RUnlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	principals, _, err := s.principalRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "IN", Value: missing},
+		}),
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	return append(result, principals...), nil
+}
+
+// FindResources returns the resources of the given kinds and values along
+// with their attributes.
+func (s *memoryDecisionStore) FindResources(resourceKinds []string, resourceValues []string) ([]*model.Resource, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var (
+		result = make([]*model.Resource, 0)
+		seen   = map[resourceKey]bool{}
+	)
+
+	for _, resourceKind := range resourceKinds {
+		for _, resourceValue := range resourceValues {
+			key := resourceKey{resourceKind, resourceValue}
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			if resource, ok := s.resources[key]; ok {
+				result = append(result, resource)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Load replaces everything kept in memory by the content of the database.
+func (s *memoryDecisionStore) Load() error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	compiledPolicies, _, err := s.compiledRepository.Find(repository.WithSkipPagination())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve compiled policies: %v", err)
+	}
+
+	policies, _, err := s.policyRepository.Find(repository.WithSkipPagination())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve policies: %v", err)
+	}
+
+	roles, _, err := s.roleRepository.Find(
+		repository.WithPreloads("Policies"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve roles: %v", err)
+	}
+
+	principals, _, err := s.principalRepository.Find(
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	resources, _, err := s.resourceRepository.Find(
+		repository.WithPreloads("Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve resources: %v", err)
+	}
+
+	cedarPolicies, _, err := s.cedarPolicyRepository.Find(repository.WithSkipPagination())
+	if err != nil {
+		return fmt.Errorf("unable to retrieve cedar policies: %v", err)
+	}
+
+	delegations, _, err := s.delegationRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"expires_at": {Operator: ">", Value: s.clock.Now()},
+		}),
+		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve delegations: %v", err)
+	}
+
+	var (
+		index           = newCompiledIndex()
+		policyByID      = make(map[string]*model.Policy, len(policies))
+		rolePolicies    = make(map[string][]string, len(roles))
+		principalByID   = make(map[string]*model.Principal, len(principals))
+		resourceByKey   = make(map[resourceKey]*model.Resource, len(resources))
+		cedarPolicyByID = make(map[string]*model.CedarPolicy, len(cedarPolicies))
+		delegationIndex = newDelegationIndex()
+	)
+
+	index.replace(nil, compiledPolicies)
+
+	for _, policy := range policies {
+		policyByID[policy.ID] = policy
+	}
+
+	for _, role := range roles {
+		rolePolicies[role.ID] = policyIDs(role)
+	}
+
+	for _, principal := range principals {
+		principalByID[principal.ID] = principal
+	}
+
+	for _, resource := range resources {
+		resourceByKey[resourceKey{resource.Kind, resource.Value}] = resource
+	}
+
+	for _, cedarPolicy := range cedarPolicies {
+		cedarPolicyByID[cedarPolicy.ID] = cedarPolicy
+	}
+
+	for _, delegation := range delegations {
+		delegationIndex.set(delegation)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.index = index
+	s.policies = policyByID
+	s.rolePolicies = rolePolicies
+	s.principals = principalByID
+	s.resources = resourceByKey
+	s.cedarPolicies = cedarPolicyByID
+	s.delegations = delegationIndex
+
+	return nil
+}
+
+// RefreshCedarPolicy reloads the Cedar policy, removing it when it has been
+// deleted.
+func (s *memoryDecisionStore) RefreshCedarPolicy(cedarPolicyID string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	cedarPolicy, err := s.cedarPolicyRepository.Get(cedarPolicyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve cedar policy: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cedarPolicy != nil {
+		s.cedarPolicies[cedarPolicyID] = cedarPolicy
+	} else {
+		delete(s.cedarPolicies, cedarPolicyID)
+	}
+
+	return nil
+}
+
+// RefreshDelegation reloads the delegation, removing it when it has been
+// deleted or has expired.
+func (s *memoryDecisionStore) RefreshDelegation(delegationID string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	delegation, err := s.delegationRepository.Get(delegationID, repository.WithPreloads("Resources", "Actions"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve delegation: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if delegation != nil && delegation.IsActive(s.clock.Now()) {
+		s.delegations.set(delegation)
+	} else {
+		s.delegations.remove(delegationID)
+	}
+
+	return nil
+}
+
+// RefreshPolicy reloads the policy and its compiled policies, removing them
+// when the policy has been deleted.
+func (s *memoryDecisionStore) RefreshPolicy(policyID string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	policy, err := s.policyRepository.Get(policyID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve policy: %v", err)
+	}
+
+	compiledPolicies, err := s.findCompiled(map[string]repository.FieldValue{
+		"policy_id": {Operator: "=", Value: policyID},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if policy != nil {
+		s.policies[policyID] = policy
+	} else {
+		delete(s.policies, policyID)
+	}
+
+	s.index.replace(keysOf(s.index.byPolicy[policyID], all), compiledPolicies)
+
+	return nil
+}
+
+// RefreshPrincipal reloads the principal and its compiled policies, removing
+// them along with its delegations when the principal has been deleted.
+func (s *memoryDecisionStore) RefreshPrincipal(principalID string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	principal, err := s.principalRepository.Get(principalID, repository.WithPreloads("Roles", "Attributes"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve principal: %v", err)
+	}
+
+	compiledPolicies, err := s.findCompiled(map[string]repository.FieldValue{
+		"principal_id": {Operator: "=", Value: principalID},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if principal != nil {
+		s.principals[principalID] = principal
+	} else {
+		delete(s.principals, principalID)
+		s.delegations.removePrincipal(principalID)
+	}
+
+	s.index.replace(keysOf(s.index.byPrincipal[principalID], all), compiledPolicies)
+
+	return nil
+}
+
+// RefreshResource reloads the resource and its compiled policies, removing
+// them, and the resource from delegations, when it has been deleted.
+func (s *memoryDecisionStore) RefreshResource(resourceKind string, resourceValue string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	resource, err := s.resourceRepository.GetByFields(
+		map[string]repository.FieldValue{
+			"kind":  {Operator: "=", Value: resourceKind},
+			"value": {Operator: "=", Value: resourceValue},
+		},
+		repository.WithPreloads("Attributes"),
+	)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve resource: %v", err)
+	}
+
+	compiledPolicies, err := s.findCompiled(map[string]repository.FieldValue{
+		"resource_kind":  {Operator: "=", Value: resourceKind},
+		"resource_value": {Operator: "=", Value: resourceValue},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := resourceKey{resourceKind, resourceValue}
+
+	if resource != nil {
+		s.resources[key] = resource
+	} else {
+		delete(s.resources, key)
+		s.delegations.removeResource(key)
+	}
+
+	s.index.replace(keysOf(s.index.byResource[key], all), compiledPolicies)
+
+	return nil
+}
+
+// RefreshRole reloads the policies of the role, removing them, and the role
+// from principals, when the role has been deleted.
+func (s *memoryDecisionStore) RefreshRole(roleID string) error {
+	s.refreshMutex.Lock()
+	defer s.refreshMutex.Unlock()
+
+	role, err := s.roleRepository.Get(roleID, repository.WithPreloads("Policies"))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("unable to retrieve role: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if role != nil {
+		s.rolePolicies[roleID] = policyIDs(role)
+	} else {
+		delete(s.rolePolicies, roleID)
+		s.removeRole(roleID)
+	}
+
+	return nil
+}
+
+// removeRole replaces the principals having the given role by copies without
+// it, as the role has been removed from them in the database.
+func (s *memoryDecisionStore) removeRole(roleID string) {
+	for principalID, principal := range s.principals {
+		roles := without(principal.Roles, func(role *model.Role) bool { return role.ID == roleID })
+		if len(roles) == len(principal.Roles) {
+			continue
+		}
+
+		clone := *principal
+		clone.Roles = roles
+		s.principals[principalID] = &clone
+	}
+}
+
+func (s *memoryDecisionStore) findCompiled(fields map[string]repository.FieldValue) ([]*model.CompiledPolicy, error) {
+	compiledPolicies, _, err := s.compiledRepository.Find(
+		repository.WithFilter(fields),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve compiled policies: %v", err)
+	}
+
+	return compiledPolicies, nil
+}
+
+func all(compiledKey) bool {
+	return true
+}
+
+func policyIDs(role *model.Role) []string {
+	var result = make([]string, 0, len(role.Policies))
+	for _, policy := range role.Policies {
+		result = append(result, policy.ID)
+	}
+
+	return result
+}
+
+// delegationIndex indexes active delegations by identifier, and by delegate
+// for lookups.
+type delegationIndex struct {
+	byID       map[string]*model.Delegation
+	byDelegate map[string]map[string]*model.Delegation
+}
+
+func newDelegationIndex() *delegationIndex {
+	return &delegationIndex{
+		byID:       map[string]*model.Delegation{},
+		byDelegate: map[string]map[string]*model.Delegation{},
+	}
+}
+
+func (i *delegationIndex) set(delegation *model.Delegation) {
+	i.remove(delegation.ID)
+
+	delegations, ok := i.byDelegate[delegation.DelegateID]
+	if !ok {
+		delegations = map[string]*model.Delegation{}
+		i.byDelegate[delegation.DelegateID] = delegations
+	}
+
+	delegations[delegation.ID] = delegation
+	i.byID[delegation.ID] = delegation
+}
+
+func (i *delegationIndex) remove(delegationID string) {
+	delegation, ok := i.byID[delegationID]
+	if !ok {
+		return
+	}
+
+	delete(i.byID, delegationID)
+	delete(i.byDelegate[delegation.DelegateID], delegationID)
+
+	if len(i.byDelegate[delegation.DelegateID]) == 0 {
+		delete(i.byDelegate, delegation.DelegateID)
+	}
+}
+
+// removePrincipal removes the delegations given by or to the principal, as
+// they are deleted along with it in the database.
+func (i *delegationIndex) removePrincipal(principalID string) {
+	for delegationID, delegation := range i.byID {
+		if delegation.DelegatorID == principalID || delegation.DelegateID == principalID {
+			i.remove(delegationID)
+		}
+	}
+}
+
+// removeResource replaces the delegations on the resource by copies without
+// it, as it has been removed from them in the database.
+func (i *delegationIndex) removeResource(key resourceKey) {
+	for _, delegation := range i.byID {
+		resources := without(delegation.Resources, func(resource *model.Resource) bool {
+			return resource.Kind == key.kind && resource.Value == key.value
+		})
+		if len(resources) == len(delegation.Resources) {
+			continue
+		}
+
+		clone := *delegation
+		clone.Resources = resources
+		i.set(&clone)
+	}
+}
+
+// without returns a copy of the items not matching the given filter.
+func without[T any](items []*T, filter func(item *T) bool) []*T {
+	var result = make([]*T, 0, len(items))
+
+	for _, item := range items {
+		if !filter(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
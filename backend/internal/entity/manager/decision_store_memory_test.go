@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemoryDecisionStore(compiledPolicies ...*model.CompiledPolicy) *memoryDecisionStore {
+	store := newMemoryDecisionStore(nil, nil, nil, nil, nil, nil, nil, time.NewClock())
+
+	store.index.replace(nil, compiledPolicies)
+	store.policies = map[string]*model.Policy{
+		"readers":   {ID: "readers", Effect: model.PolicyEffectAllow},
+		"same-team": {ID: "same-team", Effect: model.PolicyEffectAllow, Priority: 10},
+	}
+	store.rolePolicies = map[string][]string{
+		"reader": {"readers"},
+	}
+
+	return store
+}
+
+func TestMemoryDecisionStore_FindApplicable(t *testing.T) {
+	// Given
+	store := newTestMemoryDecisionStore(
+		&model.CompiledPolicy{PolicyID: "readers", ResourceKind: "post", ResourceValue: "*", ActionID: "read"},
+		&model.CompiledPolicy{PolicyID: "same-team", PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		&model.CompiledPolicy{PolicyID: "same-team", PrincipalID: "carol", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		&model.CompiledPolicy{PolicyID: "deleted", PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	)
+
+	principals := []*model.Principal{
+		{ID: "alice", Roles: []*model.Role{{ID: "reader"}}},
+		{ID: "bob"},
+	}
+
+	// When
+	result, err := store.FindApplicable(principals, []*repository.CompiledPolicyLookup{
+		{ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+	})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Len(result, 2)
+
+	for _, applicable := range result {
+		switch applicable.PolicyID {
+		case "readers":
+			assert.Equal("alice", applicable.RolePrincipalID)
+			assert.True(applicable.AppliesTo("alice", "post", "1", "read"))
+			assert.False(applicable.AppliesTo("bob", "post", "1", "read"))
+		case "same-team":
+			assert.Equal("", applicable.RolePrincipalID)
+			assert.Equal(10, applicable.Priority)
+			assert.True(applicable.AppliesTo("bob", "post", "1", "read"))
+		default:
+			t.Errorf("unexpected compiled policy of policy %q", applicable.PolicyID)
+		}
+	}
+}
+
+func TestCompiledIndex_Replace(t *testing.T) {
+	// Given
+	store := newTestMemoryDecisionStore(
+		&model.CompiledPolicy{PolicyID: "same-team", PrincipalID: "bob", ResourceKind: "post", ResourceValue: "1", ActionID: "read"},
+		&model.CompiledPolicy{PolicyID: "same-team", PrincipalID: "bob", ResourceKind: "post", ResourceValue: "2", ActionID: "read"},
+	)
+
+	index := store.index
+
+	// When
+	index.replace(keysOf(index.byPrincipal["bob"], all), []*model.CompiledPolicy{
+		{PolicyID: "same-team", PrincipalID: "bob", ResourceKind: "post", ResourceValue: "3", ActionID: "read"},
+	})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Len(index.byPrincipal["bob"], 1)
+	assert.Len(index.byPolicy["same-team"], 1)
+	assert.NotContains(index.byLookup, lookupKey{"post", "1", "read"})
+	assert.NotContains(index.byResource, resourceKey{"post", "2"})
+	assert.Contains(index.byLookup, lookupKey{"post", "3", "read"})
+}
+
+func TestMemoryDecisionStore_FindDelegations(t *testing.T) {
+	// Given
+	store := newTestMemoryDecisionStore()
+
+	now := lib_time.Now()
+
+	for _, delegation := range []*model.Delegation{
+		{ID: "alice-to-bob", DelegatorID: "alice", DelegateID: "bob", ExpiresAt: now.Add(lib_time.Hour), Resources: []*model.Resource{{Kind: "post", Value: "1"}, {Kind: "post", Value: "2"}}},
+		{ID: "carol-to-bob", DelegatorID: "carol", DelegateID: "bob", ExpiresAt: now.Add(lib_time.Hour), Resources: []*model.Resource{{Kind: "post", Value: "1"}}},
+		{ID: "expired", DelegatorID: "alice", DelegateID: "bob", ExpiresAt: now.Add(-1 * lib_time.Hour)},
+		{ID: "alice-to-dave", DelegatorID: "alice", DelegateID: "dave", ExpiresAt: now.Add(lib_time.Hour)},
+	} {
+		store.delegations.set(delegation)
+	}
+
+	checks := []*Check{{PrincipalID: "bob"}, {PrincipalID: "bob"}}
+
+	// When - the resource and the delegator are deleted
+	store.delegations.removeResource(resourceKey{"post", "1"})
+	store.delegations.removePrincipal("carol")
+
+	// Then
+	assert := assert.New(t)
+
+	result, err := store.FindDelegations(checks, now)
+	assert.Nil(err)
+
+	if assert.Len(result, 1) {
+		assert.Equal("alice-to-bob", result[0].ID)
+		assert.Equal([]*model.Resource{{Kind: "post", Value: "2"}}, result[0].Resources)
+	}
+}
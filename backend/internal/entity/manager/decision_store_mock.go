@@ -0,0 +1,210 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/decision_store.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+	time "time"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	repository "github.com/eko/authz/backend/internal/entity/repository"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDecisionStore is a mock of DecisionStore interface.
+type MockDecisionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDecisionStoreMockRecorder
+}
+
+// MockDecisionStoreMockRecorder is the mock recorder for MockDecisionStore.
+type MockDecisionStoreMockRecorder struct {
+	mock *MockDecisionStore
+}
+
+// NewMockDecisionStore creates a new mock instance.
+func NewMockDecisionStore(ctrl *gomock.Controller) *MockDecisionStore {
+	mock := &MockDecisionStore{ctrl: ctrl}
+	mock.recorder = &MockDecisionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDecisionStore) EXPECT() *MockDecisionStoreMockRecorder {
+	return m.recorder
+}
+
+// FindApplicable mocks base method.
+func (m *MockDecisionStore) FindApplicable(principals []*model.Principal, lookups []*repository.CompiledPolicyLookup) ([]*repository.ApplicableCompiledPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicable", principals, lookups)
+	ret0, _ := ret[0].([]*repository.ApplicableCompiledPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicable indicates an expected call of FindApplicable.
+func (mr *MockDecisionStoreMockRecorder) FindApplicable(principals, lookups interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicable", reflect.TypeOf((*MockDecisionStore)(nil).FindApplicable), principals, lookups)
+}
+
+// FindCedarPolicies mocks base method.
+func (m *MockDecisionStore) FindCedarPolicies(resourceKinds []string) ([]*model.CedarPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCedarPolicies", resourceKinds)
+	ret0, _ := ret[0].([]*model.CedarPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCedarPolicies indicates an expected call of FindCedarPolicies.
+func (mr *MockDecisionStoreMockRecorder) FindCedarPolicies(resourceKinds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCedarPolicies", reflect.TypeOf((*MockDecisionStore)(nil).FindCedarPolicies), resourceKinds)
+}
+
+// FindDelegations mocks base method.
+func (m *MockDecisionStore) FindDelegations(checks []*Check, activeAt time.Time) ([]*model.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDelegations", checks, activeAt)
+	ret0, _ := ret[0].([]*model.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDelegations indicates an expected call of FindDelegations.
+func (mr *MockDecisionStoreMockRecorder) FindDelegations(checks, activeAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDelegations", reflect.TypeOf((*MockDecisionStore)(nil).FindDelegations), checks, activeAt)
+}
+
+// FindPrincipals mocks base method.
+func (m *MockDecisionStore) FindPrincipals(principalIDs []string) ([]*model.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPrincipals", principalIDs)
+	ret0, _ := ret[0].([]*model.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPrincipals indicates an expected call of FindPrincipals.
+func (mr *MockDecisionStoreMockRecorder) FindPrincipals(principalIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPrincipals", reflect.TypeOf((*MockDecisionStore)(nil).FindPrincipals), principalIDs)
+}
+
+// FindResources mocks base method.
+func (m *MockDecisionStore) FindResources(resourceKinds, resourceValues []string) ([]*model.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindResources", resourceKinds, resourceValues)
+	ret0, _ := ret[0].([]*model.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResources indicates an expected call of FindResources.
+func (mr *MockDecisionStoreMockRecorder) FindResources(resourceKinds, resourceValues interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResources", reflect.TypeOf((*MockDecisionStore)(nil).FindResources), resourceKinds, resourceValues)
+}
+
+// Load mocks base method.
+func (m *MockDecisionStore) Load() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockDecisionStoreMockRecorder) Load() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockDecisionStore)(nil).Load))
+}
+
+// RefreshCedarPolicy mocks base method.
+func (m *MockDecisionStore) RefreshCedarPolicy(cedarPolicyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshCedarPolicy", cedarPolicyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshCedarPolicy indicates an expected call of RefreshCedarPolicy.
+func (mr *MockDecisionStoreMockRecorder) RefreshCedarPolicy(cedarPolicyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshCedarPolicy", reflect.TypeOf((*MockDecisionStore)(nil).RefreshCedarPolicy), cedarPolicyID)
+}
+
+// RefreshDelegation mocks base method.
+func (m *MockDecisionStore) RefreshDelegation(delegationID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshDelegation", delegationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshDelegation indicates an expected call of RefreshDelegation.
+func (mr *MockDecisionStoreMockRecorder) RefreshDelegation(delegationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshDelegation", reflect.TypeOf((*MockDecisionStore)(nil).RefreshDelegation), delegationID)
+}
+
+// RefreshPolicy mocks base method.
+func (m *MockDecisionStore) RefreshPolicy(policyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshPolicy", policyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshPolicy indicates an expected call of RefreshPolicy.
+func (mr *MockDecisionStoreMockRecorder) RefreshPolicy(policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshPolicy", reflect.TypeOf((*MockDecisionStore)(nil).RefreshPolicy), policyID)
+}
+
+// RefreshPrincipal mocks base method.
+func (m *MockDecisionStore) RefreshPrincipal(principalID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshPrincipal", principalID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshPrincipal indicates an expected call of RefreshPrincipal.
+func (mr *MockDecisionStoreMockRecorder) RefreshPrincipal(principalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshPrincipal", reflect.TypeOf((*MockDecisionStore)(nil).RefreshPrincipal), principalID)
+}
+
+// RefreshResource mocks base method.
+func (m *MockDecisionStore) RefreshResource(resourceKind, resourceValue string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshResource", resourceKind, resourceValue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshResource indicates an expected call of RefreshResource.
+func (mr *MockDecisionStoreMockRecorder) RefreshResource(resourceKind, resourceValue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshResource", reflect.TypeOf((*MockDecisionStore)(nil).RefreshResource), resourceKind, resourceValue)
+}
+
+// RefreshRole mocks base method.
+func (m *MockDecisionStore) RefreshRole(roleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRole", roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRole indicates an expected call of RefreshRole.
+func (mr *MockDecisionStoreMockRecorder) RefreshRole(roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRole", reflect.TypeOf((*MockDecisionStore)(nil).RefreshRole), roleID)
+}
@@ -0,0 +1,171 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"gorm.io/gorm"
+)
+
+type DelegationRepository repository.Base[model.Delegation]
+
+type Delegation interface {
+	Create(identifier string, delegatorID string, delegateID string, resources []string, actions []string, expiresAt lib_time.Time) (*model.Delegation, error)
+	Delete(identifier string) error
+	GetRepository() DelegationRepository
+}
+
+type delegationManager struct {
+	repository       DelegationRepository
+	principalManager Principal
+	resourceManager  Resource
+	actionManager    Action
+	compiledManager  CompiledPolicy
+	clock            time.Clock
+	dispatcher       event.Dispatcher
+}
+
+// NewDelegation initializes a new delegation manager.
+func NewDelegation(
+	repository DelegationRepository,
+	principalManager Principal,
+	resourceManager Resource,
+	actionManager Action,
+	compiledManager CompiledPolicy,
+	clock time.Clock,
+	dispatcher event.Dispatcher,
+) Delegation {
+	return &delegationManager{
+		repository:       repository,
+		principalManager: principalManager,
+		resourceManager:  resourceManager,
+		actionManager:    actionManager,
+		compiledManager:  compiledManager,
+		clock:            clock,
+		dispatcher:       dispatcher,
+	}
+}
+
+func (m *delegationManager) GetRepository() DelegationRepository {
+	return m.repository
+}
+
+func (m *delegationManager) Create(
+	identifier string,
+	delegatorID string,
+	delegateID string,
+	resources []string,
+	actions []string,
+	expiresAt lib_time.Time,
+) (*model.Delegation, error) {
+	exists, err := m.repository.Get(identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("unable to check for existing delegation: %v", err)
+	}
+
+	if exists != nil {
+		return nil, fmt.Errorf("a delegation already exists with identifier %q", identifier)
+	}
+
+	if delegatorID == delegateID {
+		return nil, fmt.Errorf("a principal cannot delegate permissions to itself")
+	}
+
+	if !expiresAt.After(m.clock.Now()) {
+		return nil, fmt.Errorf("delegation expiration date must be in the future")
+	}
+
+	for _, principalID := range []string{delegatorID, delegateID} {
+		if _, err := m.principalManager.GetRepository().Get(principalID); err != nil {
+			return nil, fmt.Errorf("unable to retrieve principal %v: %v", principalID, err)
+		}
+	}
+
+	var resourceObjects = []*model.Resource{}
+
+	for _, resource := range resources {
+		resourceObject, err := m.resourceManager.GetRepository().Get(resource)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve resource %v: %v", resource, err)
+		}
+
+		resourceObjects = append(resourceObjects, resourceObject)
+	}
+
+	var actionObjects = []*model.Action{}
+
+	for _, action := range actions {
+		actionObject, err := m.actionManager.GetRepository().Get(action)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve action %q: %v", action, err)
+		}
+
+		actionObjects = append(actionObjects, actionObject)
+	}
+
+	// A delegator can only delegate permissions it currently holds by itself.
+	for _, resourceObject := range resourceObjects {
+		for _, actionObject := range actionObjects {
+			isAllowed, err := m.compiledManager.IsDirectlyAllowed(delegatorID, resourceObject.Kind, resourceObject.Value, actionObject.ID)
+			if err != nil {
+				return nil, fmt.Errorf("unable to check delegator permissions: %v", err)
+			}
+
+			if !isAllowed {
+				return nil, fmt.Errorf(
+					"principal %q is not allowed to %q on resource %q and cannot delegate it",
+					delegatorID,
+					actionObject.ID,
+					resourceObject.ID,
+				)
+			}
+		}
+	}
+
+	delegation := &model.Delegation{
+		ID:          identifier,
+		DelegatorID: delegatorID,
+		DelegateID:  delegateID,
+		Resources:   resourceObjects,
+		Actions:     actionObjects,
+		ExpiresAt:   expiresAt,
+	}
+
+	if err := m.repository.Create(delegation); err != nil {
+		return nil, fmt.Errorf("unable to create delegation: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeDelegation, &event.ItemEvent{
+		Action: event.ItemActionCreate,
+		Data:   delegation,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return delegation, nil
+}
+
+func (m *delegationManager) Delete(identifier string) error {
+	delegation, err := m.repository.Get(identifier)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve delegation: %v", err)
+	}
+
+	if err := m.repository.Delete(delegation); err != nil {
+		return fmt.Errorf("cannot delete delegation: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeDelegation, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   delegation,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/delegation.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+	time "time"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDelegation is a mock of Delegation interface.
+type MockDelegation struct {
+	ctrl     *gomock.Controller
+	recorder *MockDelegationMockRecorder
+}
+
+// MockDelegationMockRecorder is the mock recorder for MockDelegation.
+type MockDelegationMockRecorder struct {
+	mock *MockDelegation
+}
+
+// NewMockDelegation creates a new mock instance.
+func NewMockDelegation(ctrl *gomock.Controller) *MockDelegation {
+	mock := &MockDelegation{ctrl: ctrl}
+	mock.recorder = &MockDelegationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDelegation) EXPECT() *MockDelegationMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDelegation) Create(identifier, delegatorID, delegateID string, resources, actions []string, expiresAt time.Time) (*model.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", identifier, delegatorID, delegateID, resources, actions, expiresAt)
+	ret0, _ := ret[0].(*model.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDelegationMockRecorder) Create(identifier, delegatorID, delegateID, resources, actions, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDelegation)(nil).Create), identifier, delegatorID, delegateID, resources, actions, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockDelegation) Delete(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDelegationMockRecorder) Delete(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDelegation)(nil).Delete), identifier)
+}
+
+// GetRepository mocks base method.
+func (m *MockDelegation) GetRepository() DelegationRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(DelegationRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockDelegationMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockDelegation)(nil).GetRepository))
+}
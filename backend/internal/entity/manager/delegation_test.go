@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+	"gorm.io/gorm"
+)
+
+type delegationDependencies struct {
+	compiledManager *MockCompiledPolicy
+	clock           *time.MockClock
+	dispatcher      *event.MockDispatcher
+}
+
+// newTestDelegationManager returns a delegation manager on a sqlite database
+// having "alice" and "bob" principals, a "post.1" resource and a "read" action.
+func newTestDelegationManager(t *testing.T) (*delegationManager, *delegationDependencies) {
+	ctrl := gomock.NewController(t)
+
+	db, err := database.New(&configs.Database{
+		Driver: configs.DriverSqlite,
+		Dbname: filepath.Join(t.TempDir(), "authz.db"),
+	}, slog.New(log.NewNopHandler()), time.NewClock())
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+
+	assert := assert.New(t)
+
+	principalRepository := repository.NewPrincipal(repository.New[model.Principal](db))
+	resourceRepository := repository.NewResource(repository.New[model.Resource](db))
+	actionRepository := repository.New[model.Action](db)
+
+	for _, principalID := range []string{"alice", "bob"} {
+		assert.Nil(principalRepository.Create(&model.Principal{ID: principalID}))
+	}
+
+	assert.Nil(resourceRepository.Create(&model.Resource{ID: "post.1", Kind: "post", Value: "1"}))
+	assert.Nil(actionRepository.Create(&model.Action{ID: "read"}))
+
+	principalManager := NewMockPrincipal(ctrl)
+	principalManager.EXPECT().GetRepository().Return(principalRepository).AnyTimes()
+
+	resourceManager := NewMockResource(ctrl)
+	resourceManager.EXPECT().GetRepository().Return(resourceRepository).AnyTimes()
+
+	actionManager := NewMockAction(ctrl)
+	actionManager.EXPECT().GetRepository().Return(actionRepository).AnyTimes()
+
+	deps := &delegationDependencies{
+		compiledManager: NewMockCompiledPolicy(ctrl),
+		clock:           time.NewMockClock(ctrl),
+		dispatcher:      event.NewMockDispatcher(ctrl),
+	}
+
+	delegationManagerInstance := NewDelegation(
+		repository.New[model.Delegation](db),
+		principalManager,
+		resourceManager,
+		actionManager,
+		deps.compiledManager,
+		deps.clock,
+		deps.dispatcher,
+	).(*delegationManager)
+
+	return delegationManagerInstance, deps
+}
+
+func TestDelegation_Create(t *testing.T) {
+	// Given
+	delegationManagerInstance, deps := newTestDelegationManager(t)
+
+	now := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+	expiresAt := now.Add(lib_time.Hour)
+
+	deps.clock.EXPECT().Now().Return(now)
+	deps.compiledManager.EXPECT().IsDirectlyAllowed("alice", "post", "1", "read").Return(true, nil)
+	deps.dispatcher.EXPECT().Dispatch(event.EventTypeDelegation, gomock.Any()).
+		DoAndReturn(func(_ event.EventType, data any) error {
+			itemEvent := data.(*event.ItemEvent)
+
+			assert.Equal(t, event.ItemActionCreate, itemEvent.Action)
+			assert.Equal(t, "alice-to-bob", itemEvent.Data.(*model.Delegation).ID)
+
+			return nil
+		})
+
+	// When
+	delegation, err := delegationManagerInstance.Create("alice-to-bob", "alice", "bob", []string{"post.1"}, []string{"read"}, expiresAt)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal("alice", delegation.DelegatorID)
+	assert.Equal("bob", delegation.DelegateID)
+	assert.Equal(expiresAt, delegation.ExpiresAt)
+
+	stored, err := delegationManagerInstance.GetRepository().Get("alice-to-bob", repository.WithPreloads("Resources", "Actions"))
+	assert.Nil(err)
+	assert.Len(stored.Resources, 1)
+	assert.Len(stored.Actions, 1)
+}
+
+func TestDelegation_Create_WhenDelegatorIsNotAllowed(t *testing.T) {
+	// Given
+	delegationManagerInstance, deps := newTestDelegationManager(t)
+
+	now := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+
+	deps.clock.EXPECT().Now().Return(now)
+	deps.compiledManager.EXPECT().IsDirectlyAllowed("alice", "post", "1", "read").Return(false, nil)
+
+	// When
+	delegation, err := delegationManagerInstance.Create("alice-to-bob", "alice", "bob", []string{"post.1"}, []string{"read"}, now.Add(lib_time.Hour))
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(delegation)
+	assert.EqualError(err, `principal "alice" is not allowed to "read" on resource "post.1" and cannot delegate it`)
+
+	_, err = delegationManagerInstance.GetRepository().Get("alice-to-bob")
+	assert.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func TestDelegation_Create_WhenAlreadyExpired(t *testing.T) {
+	// Given
+	delegationManagerInstance, deps := newTestDelegationManager(t)
+
+	now := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+
+	deps.clock.EXPECT().Now().Return(now)
+
+	// When
+	delegation, err := delegationManagerInstance.Create("alice-to-bob", "alice", "bob", []string{"post.1"}, []string{"read"}, now)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(delegation)
+	assert.EqualError(err, "delegation expiration date must be in the future")
+}
+
+func TestDelegation_Delete(t *testing.T) {
+	// Given
+	delegationManagerInstance, deps := newTestDelegationManager(t)
+
+	assert := assert.New(t)
+
+	assert.Nil(delegationManagerInstance.GetRepository().Create(&model.Delegation{
+		ID:          "alice-to-bob",
+		DelegatorID: "alice",
+		DelegateID:  "bob",
+		ExpiresAt:   lib_time.Now().Add(lib_time.Hour),
+	}))
+
+	deps.dispatcher.EXPECT().Dispatch(event.EventTypeDelegation, gomock.Any()).
+		DoAndReturn(func(_ event.EventType, data any) error {
+			itemEvent := data.(*event.ItemEvent)
+
+			assert.Equal(event.ItemActionDelete, itemEvent.Action)
+			assert.Equal("bob", itemEvent.Data.(*model.Delegation).DelegateID)
+
+			return nil
+		})
+
+	// When
+	err := delegationManagerInstance.Delete("alice-to-bob")
+
+	// Then
+	assert.Nil(err)
+
+	_, err = delegationManagerInstance.GetRepository().Get("alice-to-bob")
+	assert.ErrorIs(err, gorm.ErrRecordNotFound)
+}
@@ -3,47 +3,103 @@ package manager
 import (
 	"errors"
 	"fmt"
+	lib_time "time"
 
 	"github.com/eko/authz/backend/internal/attribute"
 	"github.com/eko/authz/backend/internal/database"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
 	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/schedule"
+	"github.com/eko/authz/backend/internal/lint"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 type PolicyRepository repository.Base[model.Policy]
 
+// PolicyOption allows to set optional policy settings on create or update.
+//
+// Settings that are not given as options are left untouched on update.
+type PolicyOption func(*model.Policy)
+
+// WithValidity restricts the policy to the [notBefore, notAfter) time range.
+// A nil value means there is no limit on this side.
+func WithValidity(notBefore *lib_time.Time, notAfter *lib_time.Time) PolicyOption {
+	return func(p *model.Policy) {
+		p.NotBefore = notBefore
+		p.NotAfter = notAfter
+	}
+}
+
+// WithSchedule restricts the policy to recurring time windows opening each time the
+// cron expression matches (in the given time zone) and lasting for the given duration.
+// An empty expression removes the schedule.
+func WithSchedule(expression string, duration string, timezone string) PolicyOption {
+	return func(p *model.Policy) {
+		p.Schedule = expression
+		p.ScheduleDuration = duration
+		p.ScheduleTimezone = timezone
+	}
+}
+
+// WithEffect sets whether the policy allows or denies access when it applies.
+// An empty effect means the policy allows access.
+func WithEffect(effect model.PolicyEffect) PolicyOption {
+	return func(p *model.Policy) {
+		p.Effect = effect
+	}
+}
+
+// WithPriority sets the policy priority used to combine it with other applicable
+// policies: the higher the priority, the sooner the policy is considered.
+func WithPriority(priority int) PolicyOption {
+	return func(p *model.Policy) {
+		p.Priority = priority
+	}
+}
+
 type Policy interface {
-	Create(identifier string, resources []string, actions []string, attributeRules []string) (*model.Policy, error)
+	Create(identifier string, resources []string, actions []string, attributeRules []string, options ...PolicyOption) (*model.Policy, error)
 	Delete(identifier string) error
-	Update(identifier string, resources []string, actions []string, attributeRules []string) (*model.Policy, error)
+	Update(identifier string, resources []string, actions []string, attributeRules []string, options ...PolicyOption) (*model.Policy, error)
 	GetRepository() PolicyRepository
 }
 
 type policyManager struct {
-	repository         PolicyRepository
-	resourceManager    Resource
-	actionManager      Action
-	transactionManager database.TransactionManager
-	dispatcher         event.Dispatcher
+	repository          PolicyRepository
+	resourceManager     Resource
+	resourceKindManager ResourceKind
+	actionManager       Action
+	linter              lint.Linter
+	transactionManager  database.TransactionManager
+	compileJobManager   CompileJob
+	compiledRepository  CompiledPolicyRepository
+	dispatcher          event.Dispatcher
 }
 
 // NewPolicy initializes a new policy manager.
 func NewPolicy(
 	repository PolicyRepository,
 	resourceManager Resource,
+	resourceKindManager ResourceKind,
 	actionManager Action,
+	linter lint.Linter,
 	transactionManager database.TransactionManager,
+	compileJobManager CompileJob,
+	compiledRepository CompiledPolicyRepository,
 	dispatcher event.Dispatcher,
 ) Policy {
 	return &policyManager{
-		repository:         repository,
-		resourceManager:    resourceManager,
-		actionManager:      actionManager,
-		transactionManager: transactionManager,
-		dispatcher:         dispatcher,
+		repository:          repository,
+		resourceManager:     resourceManager,
+		resourceKindManager: resourceKindManager,
+		actionManager:       actionManager,
+		linter:              linter,
+		transactionManager:  transactionManager,
+		compileJobManager:   compileJobManager,
+		compiledRepository:  compiledRepository,
+		dispatcher:          dispatcher,
 	}
 }
 
@@ -51,7 +107,13 @@ func (m *policyManager) GetRepository() PolicyRepository {
 	return m.repository
 }
 
-func (m *policyManager) Create(identifier string, resources []string, actions []string, attributeRules []string) (*model.Policy, error) {
+func (m *policyManager) Create(
+	identifier string,
+	resources []string,
+	actions []string,
+	attributeRules []string,
+	options ...PolicyOption,
+) (*model.Policy, error) {
 	exists, err := m.repository.Get(identifier)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("unable to check for existing policy: %v", err)
@@ -66,10 +128,30 @@ func (m *policyManager) Create(identifier string, resources []string, actions []
 		return nil, err
 	}
 
-	if err := m.repository.Create(policy); err != nil {
+	if err := applyPolicyOptions(policy, options); err != nil {
+		return nil, err
+	}
+
+	if err := m.linter.ValidatePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Create(policy); err != nil {
+		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to create policy: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypePolicy, policy.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit policy creation: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypePolicy, &event.ItemEvent{
 		Action: event.ItemActionCreate,
 		Data:   policy,
@@ -86,14 +168,48 @@ func (m *policyManager) Delete(identifier string) error {
 		return fmt.Errorf("cannot retrieve policy: %v", err)
 	}
 
-	if err := m.repository.Delete(policy); err != nil {
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Delete(policy); err != nil {
+		_ = transaction.Rollback()
 		return fmt.Errorf("cannot delete policy: %v", err)
 	}
 
+	// Compiled policies are deleted along with the policy so that it does
+	// not grant access anymore once deleted.
+	if err := m.compiledRepository.WithTransaction(transaction).DeleteByFields(map[string]repository.FieldValue{
+		"policy_id": {Operator: "=", Value: policy.ID},
+	}); err != nil {
+		_ = transaction.Rollback()
+		return fmt.Errorf("unable to delete compiled policies: %v", err)
+	}
+
+	if err := m.compileJobManager.WithTransaction(transaction).Cancel(model.CompileJobTargetTypePolicy, policy.ID); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit policy deletion: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypePolicy, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   policy,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
 	return nil
 }
 
-func (m *policyManager) Update(identifier string, resources []string, actions []string, attributeRules []string) (*model.Policy, error) {
+func (m *policyManager) Update(
+	identifier string,
+	resources []string,
+	actions []string,
+	attributeRules []string,
+	options ...PolicyOption,
+) (*model.Policy, error) {
 	policy, err := m.repository.Get(identifier)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve policy: %v", err)
@@ -103,8 +219,15 @@ func (m *policyManager) Update(identifier string, resources []string, actions []
 		return nil, err
 	}
 
+	if err := applyPolicyOptions(policy, options); err != nil {
+		return nil, err
+	}
+
+	if err := m.linter.ValidatePolicy(policy); err != nil {
+		return nil, err
+	}
+
 	transaction := m.transactionManager.New()
-	defer func() { _ = transaction.Commit() }()
 
 	policyRepository := m.repository.WithTransaction(transaction)
 
@@ -123,11 +246,19 @@ func (m *policyManager) Update(identifier string, resources []string, actions []
 		return nil, fmt.Errorf("unable to update policy: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypePolicy, policy.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit policy update: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypePolicy, &event.ItemEvent{
 		Action: event.ItemActionUpdate,
 		Data:   policy,
 	}); err != nil {
-		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to dispatch event: %v", err)
 	}
 
@@ -178,6 +309,22 @@ func (m *policyManager) attachToPolicy(
 		resourceObjects = append(resourceObjects, resourceObject)
 	}
 
+	// Actions must be declared on registered resource kinds, so unknown actions
+	// are only created for resources of kinds that are not registered.
+	var validatedKinds = map[string]bool{}
+
+	for _, resourceObject := range resourceObjects {
+		if validatedKinds[resourceObject.Kind] {
+			continue
+		}
+
+		if err := m.resourceKindManager.ValidateActions(resourceObject.Kind, actions); err != nil {
+			return err
+		}
+
+		validatedKinds[resourceObject.Kind] = true
+	}
+
 	var actionObjects = []*model.Action{}
 
 	for _, action := range actions {
@@ -201,3 +348,32 @@ func (m *policyManager) attachToPolicy(
 
 	return nil
 }
+
+func applyPolicyOptions(policy *model.Policy, options []PolicyOption) error {
+	for _, option := range options {
+		option(policy)
+	}
+
+	switch policy.Effect {
+	case "":
+		policy.Effect = model.PolicyEffectAllow
+	case model.PolicyEffectAllow, model.PolicyEffectDeny:
+	default:
+		return fmt.Errorf("policy effect must be either %q or %q", model.PolicyEffectAllow, model.PolicyEffectDeny)
+	}
+
+	if policy.NotBefore != nil && policy.NotAfter != nil && !policy.NotBefore.Before(*policy.NotAfter) {
+		return fmt.Errorf("policy not_before date must be before its not_after date")
+	}
+
+	if policy.Schedule != "" {
+		if _, err := schedule.Parse(policy.Schedule, policy.ScheduleDuration, policy.ScheduleTimezone); err != nil {
+			return fmt.Errorf("unable to parse policy schedule: %v", err)
+		}
+	} else {
+		policy.ScheduleDuration = ""
+		policy.ScheduleTimezone = ""
+	}
+
+	return nil
+}
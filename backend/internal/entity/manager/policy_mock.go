@@ -35,18 +35,23 @@ func (m *MockPolicy) EXPECT() *MockPolicyMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockPolicy) Create(identifier string, resources, actions, attributeRules []string) (*model.Policy, error) {
+func (m *MockPolicy) Create(identifier string, resources, actions, attributeRules []string, options ...PolicyOption) (*model.Policy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", identifier, resources, actions, attributeRules)
+	varargs := []interface{}{identifier, resources, actions, attributeRules}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
 	ret0, _ := ret[0].(*model.Policy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockPolicyMockRecorder) Create(identifier, resources, actions, attributeRules interface{}) *gomock.Call {
+func (mr *MockPolicyMockRecorder) Create(identifier, resources, actions, attributeRules interface{}, options ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPolicy)(nil).Create), identifier, resources, actions, attributeRules)
+	varargs := append([]interface{}{identifier, resources, actions, attributeRules}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPolicy)(nil).Create), varargs...)
 }
 
 // Delete mocks base method.
@@ -78,16 +83,21 @@ func (mr *MockPolicyMockRecorder) GetRepository() *gomock.Call {
 }
 
 // Update mocks base method.
-func (m *MockPolicy) Update(identifier string, resources, actions, attributeRules []string) (*model.Policy, error) {
+func (m *MockPolicy) Update(identifier string, resources, actions, attributeRules []string, options ...PolicyOption) (*model.Policy, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", identifier, resources, actions, attributeRules)
+	varargs := []interface{}{identifier, resources, actions, attributeRules}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(*model.Policy)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPolicyMockRecorder) Update(identifier, resources, actions, attributeRules interface{}) *gomock.Call {
+func (mr *MockPolicyMockRecorder) Update(identifier, resources, actions, attributeRules interface{}, options ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPolicy)(nil).Update), identifier, resources, actions, attributeRules)
+	varargs := append([]interface{}{identifier, resources, actions, attributeRules}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPolicy)(nil).Update), varargs...)
 }
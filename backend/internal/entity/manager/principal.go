@@ -23,6 +23,8 @@ type principalManager struct {
 	roleRepository     RoleRepository
 	attributeManager   Attribute
 	transactionManager database.TransactionManager
+	compileJobManager  CompileJob
+	compiledRepository CompiledPolicyRepository
 	dispatcher         event.Dispatcher
 }
 
@@ -32,6 +34,8 @@ func NewPrincipal(
 	roleRepository RoleRepository,
 	attributeManager Attribute,
 	transactionManager database.TransactionManager,
+	compileJobManager CompileJob,
+	compiledRepository CompiledPolicyRepository,
 	dispatcher event.Dispatcher,
 ) Principal {
 	return &principalManager{
@@ -39,6 +43,8 @@ func NewPrincipal(
 		roleRepository:     roleRepository,
 		attributeManager:   attributeManager,
 		transactionManager: transactionManager,
+		compileJobManager:  compileJobManager,
+		compiledRepository: compiledRepository,
 		dispatcher:         dispatcher,
 	}
 }
@@ -79,10 +85,22 @@ func (m *principalManager) Create(identifier string, roles []string, attributes
 		Attributes: attributeObjects,
 	}
 
-	if err := m.repository.Create(principal); err != nil {
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Create(principal); err != nil {
+		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to create principal: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypePrincipal, principal.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit principal creation: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypePrincipal, &event.ItemEvent{
 		Action: event.ItemActionCreate,
 		Data:   principal,
@@ -120,7 +138,6 @@ func (m *principalManager) Update(identifier string, roles []string, attributes
 	principal.Attributes = attributeObjects
 
 	transaction := m.transactionManager.New()
-	defer func() { _ = transaction.Commit() }()
 
 	principalRepository := m.repository.WithTransaction(transaction)
 
@@ -139,11 +156,19 @@ func (m *principalManager) Update(identifier string, roles []string, attributes
 		return nil, fmt.Errorf("unable to create principal: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypePrincipal, principal.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit principal update: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypePrincipal, &event.ItemEvent{
 		Action: event.ItemActionUpdate,
 		Data:   principal,
 	}); err != nil {
-		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to dispatch event: %v", err)
 	}
 
@@ -160,9 +185,45 @@ func (m *principalManager) Delete(identifier string) error {
 		return errors.New("cannot be deleted because it is locked")
 	}
 
-	if err := m.repository.Delete(principal); err != nil {
-		return fmt.Errorf("cannot delete principal: %v", err)
+	transaction := m.transactionManager.New()
+
+	if err := deletePrincipal(transaction, principal, m.repository, m.compiledRepository, m.compileJobManager); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit principal deletion: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypePrincipal, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   principal,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
 	}
 
 	return nil
 }
+
+// deletePrincipal deletes a principal along with its compiled policies and its
+// compile job, in the given transaction.
+func deletePrincipal(
+	transaction database.Transaction,
+	principal *model.Principal,
+	principalRepository repository.Base[model.Principal],
+	compiledRepository CompiledPolicyRepository,
+	compileJobManager CompileJob,
+) error {
+	if err := principalRepository.WithTransaction(transaction).Delete(principal); err != nil {
+		return fmt.Errorf("cannot delete principal: %v", err)
+	}
+
+	if err := compiledRepository.WithTransaction(transaction).DeleteByFields(map[string]repository.FieldValue{
+		"principal_id": {Operator: "=", Value: principal.ID},
+	}); err != nil {
+		return fmt.Errorf("unable to delete compiled policies: %v", err)
+	}
+
+	return compileJobManager.WithTransaction(transaction).Cancel(model.CompileJobTargetTypePrincipal, principal.ID)
+}
@@ -19,24 +19,33 @@ type Resource interface {
 }
 
 type resourceManager struct {
-	repository         repository.Resource
-	attributeManager   Attribute
-	transactionManager database.TransactionManager
-	dispatcher         event.Dispatcher
+	repository          repository.Resource
+	attributeManager    Attribute
+	resourceKindManager ResourceKind
+	transactionManager  database.TransactionManager
+	compileJobManager   CompileJob
+	compiledRepository  CompiledPolicyRepository
+	dispatcher          event.Dispatcher
 }
 
 // NewResource initializes a new resource manager.
 func NewResource(
 	repository repository.Resource,
 	attributeManager Attribute,
+	resourceKindManager ResourceKind,
 	transactionManager database.TransactionManager,
+	compileJobManager CompileJob,
+	compiledRepository CompiledPolicyRepository,
 	dispatcher event.Dispatcher,
 ) Resource {
 	return &resourceManager{
-		repository:         repository,
-		attributeManager:   attributeManager,
-		transactionManager: transactionManager,
-		dispatcher:         dispatcher,
+		repository:          repository,
+		attributeManager:    attributeManager,
+		resourceKindManager: resourceKindManager,
+		transactionManager:  transactionManager,
+		compileJobManager:   compileJobManager,
+		compiledRepository:  compiledRepository,
+		dispatcher:          dispatcher,
 	}
 }
 
@@ -70,6 +79,10 @@ func (m *resourceManager) Create(identifier string, kind string, value string, a
 		return nil, fmt.Errorf("a resource already exists with kind %q and value %q", kind, value)
 	}
 
+	if err := m.validateAttributes(kind, value, attributes); err != nil {
+		return nil, err
+	}
+
 	attributeObjects, err := m.attributeManager.MapToSlice(attributes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert attributes to slice: %v", err)
@@ -82,10 +95,22 @@ func (m *resourceManager) Create(identifier string, kind string, value string, a
 		Attributes: attributeObjects,
 	}
 
-	if err := m.repository.Create(resource); err != nil {
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Create(resource); err != nil {
+		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to create resource: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypeResource, resource.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit resource creation: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypeResource, &event.ItemEvent{
 		Action: event.ItemActionCreate,
 		Data:   resource,
@@ -102,10 +127,39 @@ func (m *resourceManager) Delete(identifier string) error {
 		return fmt.Errorf("cannot retrieve resource: %v", err)
 	}
 
-	if err := m.repository.Delete(resource); err != nil {
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Delete(resource); err != nil {
+		_ = transaction.Rollback()
 		return fmt.Errorf("cannot delete resource: %v", err)
 	}
 
+	// Policies referencing the resource do not reference it anymore, so the
+	// compiled policies of all principals and policies are deleted.
+	if err := m.compiledRepository.WithTransaction(transaction).DeleteByFields(map[string]repository.FieldValue{
+		"resource_kind":  {Operator: "=", Value: resource.Kind},
+		"resource_value": {Operator: "=", Value: resource.Value},
+	}); err != nil {
+		_ = transaction.Rollback()
+		return fmt.Errorf("unable to delete compiled policies: %v", err)
+	}
+
+	if err := m.compileJobManager.WithTransaction(transaction).Cancel(model.CompileJobTargetTypeResource, resource.ID); err != nil {
+		_ = transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit resource deletion: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeResource, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   resource,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
 	return nil
 }
 
@@ -115,6 +169,10 @@ func (m *resourceManager) Update(identifier string, kind string, value string, a
 		return nil, fmt.Errorf("unable to retrieve resource: %v", err)
 	}
 
+	if err := m.validateAttributes(kind, value, attributes); err != nil {
+		return nil, err
+	}
+
 	attributeObjects, err := m.attributeManager.MapToSlice(attributes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to convert attributes to slice: %v", err)
@@ -125,7 +183,6 @@ func (m *resourceManager) Update(identifier string, kind string, value string, a
 	resource.Attributes = attributeObjects
 
 	transaction := m.transactionManager.New()
-	defer func() { _ = transaction.Commit() }()
 
 	resourceRepository := m.repository.WithTransaction(transaction)
 
@@ -139,13 +196,31 @@ func (m *resourceManager) Update(identifier string, kind string, value string, a
 		return nil, fmt.Errorf("unable to update resource: %v", err)
 	}
 
+	if err := m.compileJobManager.WithTransaction(transaction).Enqueue(model.CompileJobTargetTypeResource, resource.ID); err != nil {
+		_ = transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit resource update: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypeResource, &event.ItemEvent{
 		Action: event.ItemActionUpdate,
 		Data:   resource,
 	}); err != nil {
-		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to dispatch event: %v", err)
 	}
 
 	return resource, nil
 }
+
+// validateAttributes checks the attributes against the schema of the resource
+// kind. Wildcard resources are not validated as they have no attributes.
+func (m *resourceManager) validateAttributes(kind string, value string, attributes map[string]any) error {
+	if value == WildcardValue {
+		return nil
+	}
+
+	return m.resourceKindManager.ValidateAttributes(kind, attributes)
+}
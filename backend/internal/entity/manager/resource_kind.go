@@ -0,0 +1,489 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/jsonschema"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownAction is returned when an action is not declared on a registered resource kind.
+	ErrUnknownAction = errors.New("unknown action")
+)
+
+type ResourceKindRepository repository.Base[model.ResourceKind]
+
+type ResourceKind interface {
+	Create(identifier string, description string, actions []string, attributesSchema []byte) (*model.ResourceKind, error)
+	Delete(identifier string) error
+	GetRepository() ResourceKindRepository
+	InvalidateKinds()
+	Update(identifier string, description string, actions []string, attributesSchema []byte) (*model.ResourceKind, error)
+	ValidateActions(kind string, actions []string) error
+	ValidateActionsByKind(actionsByKind map[string][]string) error
+	ValidateAttributes(kind string, attributes map[string]any) error
+}
+
+type resourceKindManager struct {
+	repository         ResourceKindRepository
+	actionManager      Action
+	policyRepository   PolicyRepository
+	resourceRepository repository.Resource
+	transactionManager database.TransactionManager
+	dispatcher         event.Dispatcher
+	clock              time.Clock
+	registry           *resourceKindRegistry
+}
+
+// resourceKindRegistry keeps the registered resource kinds, with their
+// actions, in memory so checks do not retrieve them from the database.
+// It is invalidated when a resource kind changes and expires after a while,
+// to take into account the changes made by other instances.
+type resourceKindRegistry struct {
+	mutex      sync.RWMutex
+	ttl        lib_time.Duration
+	kinds      map[string]*model.ResourceKind
+	expiresAt  lib_time.Time
+	generation uint64
+}
+
+// NewResourceKind initializes a new resource kind manager.
+func NewResourceKind(
+	cfg *configs.App,
+	repository ResourceKindRepository,
+	actionManager Action,
+	policyRepository PolicyRepository,
+	resourceRepository repository.Resource,
+	transactionManager database.TransactionManager,
+	dispatcher event.Dispatcher,
+	clock time.Clock,
+) ResourceKind {
+	return &resourceKindManager{
+		repository:         repository,
+		actionManager:      actionManager,
+		policyRepository:   policyRepository,
+		resourceRepository: resourceRepository,
+		transactionManager: transactionManager,
+		dispatcher:         dispatcher,
+		clock:              clock,
+		registry:           &resourceKindRegistry{ttl: cfg.DecisionCacheTTL},
+	}
+}
+
+func (m *resourceKindManager) GetRepository() ResourceKindRepository {
+	return m.repository
+}
+
+func (m *resourceKindManager) Create(
+	identifier string,
+	description string,
+	actions []string,
+	attributesSchema []byte,
+) (*model.ResourceKind, error) {
+	exists, err := m.repository.Get(identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("unable to check for existing resource kind: %v", err)
+	}
+
+	if exists != nil {
+		return nil, fmt.Errorf("a resource kind already exists with identifier %q", identifier)
+	}
+
+	resourceKind := &model.ResourceKind{ID: identifier}
+
+	if err := m.apply(resourceKind, description, actions, attributesSchema); err != nil {
+		return nil, err
+	}
+
+	transaction := m.transactionManager.New()
+
+	if err := m.repository.WithTransaction(transaction).Create(resourceKind); err != nil {
+		_ = transaction.Rollback()
+		return nil, fmt.Errorf("unable to create resource kind: %v", err)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit resource kind creation: %v", err)
+	}
+
+	m.InvalidateKinds()
+
+	if err := m.dispatcher.Dispatch(event.EventTypeResourceKind, &event.ItemEvent{
+		Action: event.ItemActionCreate,
+		Data:   resourceKind,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return resourceKind, nil
+}
+
+func (m *resourceKindManager) Delete(identifier string) error {
+	resourceKind, err := m.repository.Get(identifier)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve resource kind: %v", err)
+	}
+
+	transaction := m.transactionManager.New()
+
+	resourceKindRepository := m.repository.WithTransaction(transaction)
+
+	if err := resourceKindRepository.UpdateAssociation(resourceKind, "Actions", []*model.Action{}); err != nil {
+		_ = transaction.Rollback()
+		return fmt.Errorf("cannot delete resource kind actions association: %v", err)
+	}
+
+	if err := resourceKindRepository.Delete(resourceKind); err != nil {
+		_ = transaction.Rollback()
+		return fmt.Errorf("cannot delete resource kind: %v", err)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit resource kind deletion: %v", err)
+	}
+
+	m.InvalidateKinds()
+
+	if err := m.dispatcher.Dispatch(event.EventTypeResourceKind, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   resourceKind,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return nil
+}
+
+func (m *resourceKindManager) Update(
+	identifier string,
+	description string,
+	actions []string,
+	attributesSchema []byte,
+) (*model.ResourceKind, error) {
+	resourceKind, err := m.repository.Get(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource kind: %v", err)
+	}
+
+	if err := m.apply(resourceKind, description, actions, attributesSchema); err != nil {
+		return nil, err
+	}
+
+	transaction := m.transactionManager.New()
+
+	resourceKindRepository := m.repository.WithTransaction(transaction)
+
+	if err := resourceKindRepository.UpdateAssociation(resourceKind, "Actions", resourceKind.Actions); err != nil {
+		_ = transaction.Rollback()
+		return nil, fmt.Errorf("unable to update resource kind actions association: %v", err)
+	}
+
+	if err := resourceKindRepository.Update(resourceKind); err != nil {
+		_ = transaction.Rollback()
+		return nil, fmt.Errorf("unable to update resource kind: %v", err)
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit resource kind update: %v", err)
+	}
+
+	m.InvalidateKinds()
+
+	if err := m.dispatcher.Dispatch(event.EventTypeResourceKind, &event.ItemEvent{
+		Action: event.ItemActionUpdate,
+		Data:   resourceKind,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return resourceKind, nil
+}
+
+// ValidateActions returns an error wrapping ErrUnknownAction when one of the
+// given actions is not declared on the resource kind.
+// Actions are not restricted on kinds that are not registered.
+func (m *resourceKindManager) ValidateActions(kind string, actions []string) error {
+	return m.ValidateActionsByKind(map[string][]string{kind: actions})
+}
+
+// ValidateActionsByKind validates the actions of several resource kinds like
+// ValidateActions, from the registered resource kinds kept in memory.
+func (m *resourceKindManager) ValidateActionsByKind(actionsByKind map[string][]string) error {
+	var kinds = make([]string, 0, len(actionsByKind))
+	for kind := range actionsByKind {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	resourceKindByID, err := m.kinds()
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range kinds {
+		resourceKind, ok := resourceKindByID[kind]
+		if !ok {
+			continue
+		}
+
+		var (
+			unknownActions []string
+			seen           = map[string]bool{}
+		)
+
+		for _, action := range actionsByKind[kind] {
+			if !resourceKind.AllowsAction(action) && !seen[action] {
+				seen[action] = true
+				unknownActions = append(unknownActions, fmt.Sprintf("%q", action))
+			}
+		}
+
+		if len(unknownActions) == 0 {
+			continue
+		}
+
+		return fmt.Errorf("%w %s on resource kind %q (declared actions: %s)",
+			ErrUnknownAction,
+			strings.Join(unknownActions, ", "),
+			kind,
+			strings.Join(actionIDs(resourceKind.Actions), ", "),
+		)
+	}
+
+	return nil
+}
+
+// ValidateAttributes returns an error when the given attributes do not comply
+// with the attributes schema of the resource kind, if any.
+func (m *resourceKindManager) ValidateAttributes(kind string, attributes map[string]any) error {
+	resourceKind, err := m.get(kind)
+	if err != nil || resourceKind == nil || len(resourceKind.AttributesSchema) == 0 {
+		return err
+	}
+
+	schema, err := jsonschema.Compile(resourceKind.AttributesSchema)
+	if err != nil {
+		return fmt.Errorf("unable to compile attributes schema of resource kind %q: %v", kind, err)
+	}
+
+	return validateAttributes(schema, kind, attributes)
+}
+
+func (m *resourceKindManager) get(kind string) (*model.ResourceKind, error) {
+	resourceKindByID, err := m.kinds()
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceKindByID[kind], nil
+}
+
+// InvalidateKinds removes the registered resource kinds from memory, so they
+// are retrieved again on next validation.
+func (m *resourceKindManager) InvalidateKinds() {
+	m.registry.mutex.Lock()
+	defer m.registry.mutex.Unlock()
+
+	m.registry.kinds = nil
+	m.registry.generation++
+}
+
+// kinds returns the registered resource kinds by identifier, retrieving them
+// from the database when they are not in memory or expired.
+func (m *resourceKindManager) kinds() (map[string]*model.ResourceKind, error) {
+	now := m.clock.Now()
+
+	m.registry.mutex.RLock()
+	kinds, expiresAt, generation := m.registry.kinds, m.registry.expiresAt, m.registry.generation
+	m.registry.mutex.RUnlock()
+
+	if kinds != nil && (m.registry.ttl <= 0 || now.Before(expiresAt)) {
+		return kinds, nil
+	}
+
+	resourceKinds, _, err := m.repository.Find(
+		repository.WithPreloads("Actions"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource kinds: %v", err)
+	}
+
+	kinds = make(map[string]*model.ResourceKind, len(resourceKinds))
+	for _, resourceKind := range resourceKinds {
+		kinds[resourceKind.ID] = resourceKind
+	}
+
+	m.registry.mutex.Lock()
+	defer m.registry.mutex.Unlock()
+
+	// Resource kinds changed while they were retrieved are retrieved again
+	// on next validation.
+	if m.registry.generation == generation {
+		m.registry.kinds = kinds
+		m.registry.expiresAt = now.Add(m.registry.ttl)
+	}
+
+	return kinds, nil
+}
+
+// apply checks that existing policies and resources of the kind comply with
+// the given declaration and sets it on the resource kind.
+func (m *resourceKindManager) apply(
+	resourceKind *model.ResourceKind,
+	description string,
+	actions []string,
+	attributesSchema []byte,
+) error {
+	if len(actions) == 0 {
+		return fmt.Errorf("resource kind %q must declare at least one action", resourceKind.ID)
+	}
+
+	var declared = map[string]bool{}
+	for _, action := range actions {
+		declared[action] = true
+	}
+
+	if err := m.checkPolicies(resourceKind.ID, declared); err != nil {
+		return err
+	}
+
+	if len(attributesSchema) > 0 {
+		schema, err := jsonschema.Compile(attributesSchema)
+		if err != nil {
+			return fmt.Errorf("invalid attributes schema: %v", err)
+		}
+
+		if err := m.checkResources(resourceKind.ID, schema); err != nil {
+			return err
+		}
+	}
+
+	var actionObjects = make([]*model.Action, 0, len(actions))
+
+	for _, action := range actions {
+		actionObject, err := m.actionManager.GetRepository().Get(action)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			actionObject, err = m.actionManager.Create(action)
+			if err != nil {
+				return fmt.Errorf("unable to create action %q: %v", action, err)
+			}
+		} else if err != nil {
+			return fmt.Errorf("unable to retrieve action %q: %v", action, err)
+		}
+
+		actionObjects = append(actionObjects, actionObject)
+	}
+
+	resourceKind.Description = description
+	resourceKind.Actions = actionObjects
+	resourceKind.AttributesSchema = datatypes.JSON(attributesSchema)
+
+	return nil
+}
+
+// checkPolicies returns an error when existing policies use actions that are
+// not declared on resources of the kind.
+func (m *resourceKindManager) checkPolicies(kind string, declared map[string]bool) error {
+	policies, _, err := m.policyRepository.Find(
+		repository.WithPreloads("Resources", "Actions"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve policies: %v", err)
+	}
+
+	for _, policy := range policies {
+		var usesKind bool
+		for _, resource := range policy.Resources {
+			usesKind = usesKind || resource.Kind == kind
+		}
+
+		if !usesKind {
+			continue
+		}
+
+		var undeclared []string
+		for _, action := range policy.Actions {
+			if !declared[action.ID] {
+				undeclared = append(undeclared, action.ID)
+			}
+		}
+
+		if len(undeclared) > 0 {
+			return fmt.Errorf("policy %q uses actions that are not declared on resource kind %q: %s",
+				policy.ID, kind, strings.Join(undeclared, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkResources returns an error when the attributes of existing resources of
+// the kind do not comply with the given schema. Wildcard resources are ignored.
+func (m *resourceKindManager) checkResources(kind string, schema *jsonschema.Schema) error {
+	resources, _, err := m.resourceRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"kind":  {Operator: "=", Value: kind},
+			"value": {Operator: "<>", Value: WildcardValue},
+		}),
+		repository.WithPreloads("Attributes"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve resources of kind %q: %v", kind, err)
+	}
+
+	for _, resource := range resources {
+		var attributes = make(map[string]any, len(resource.Attributes))
+		for _, attribute := range resource.Attributes {
+			attributes[attribute.Key] = attribute.Value
+		}
+
+		if err := validateAttributes(schema, kind, attributes); err != nil {
+			return fmt.Errorf("resource %q does not comply: %v", resource.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func validateAttributes(schema *jsonschema.Schema, kind string, attributes map[string]any) error {
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+
+	if err := schema.Validate(attributes); err != nil {
+		return fmt.Errorf("invalid attributes for resource kind %q: %v", kind, err)
+	}
+
+	return nil
+}
+
+func actionIDs(actions []*model.Action) []string {
+	var result = make([]string, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, action.ID)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
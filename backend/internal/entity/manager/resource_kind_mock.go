@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/resource_kind.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockResourceKind is a mock of ResourceKind interface.
+type MockResourceKind struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceKindMockRecorder
+}
+
+// MockResourceKindMockRecorder is the mock recorder for MockResourceKind.
+type MockResourceKindMockRecorder struct {
+	mock *MockResourceKind
+}
+
+// NewMockResourceKind creates a new mock instance.
+func NewMockResourceKind(ctrl *gomock.Controller) *MockResourceKind {
+	mock := &MockResourceKind{ctrl: ctrl}
+	mock.recorder = &MockResourceKindMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceKind) EXPECT() *MockResourceKindMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockResourceKind) Create(identifier, description string, actions []string, attributesSchema []byte) (*model.ResourceKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", identifier, description, actions, attributesSchema)
+	ret0, _ := ret[0].(*model.ResourceKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockResourceKindMockRecorder) Create(identifier, description, actions, attributesSchema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockResourceKind)(nil).Create), identifier, description, actions, attributesSchema)
+}
+
+// Delete mocks base method.
+func (m *MockResourceKind) Delete(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockResourceKindMockRecorder) Delete(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockResourceKind)(nil).Delete), identifier)
+}
+
+// GetRepository mocks base method.
+func (m *MockResourceKind) GetRepository() ResourceKindRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(ResourceKindRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockResourceKindMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockResourceKind)(nil).GetRepository))
+}
+
+// InvalidateKinds mocks base method.
+func (m *MockResourceKind) InvalidateKinds() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateKinds")
+}
+
+// InvalidateKinds indicates an expected call of InvalidateKinds.
+func (mr *MockResourceKindMockRecorder) InvalidateKinds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateKinds", reflect.TypeOf((*MockResourceKind)(nil).InvalidateKinds))
+}
+
+// Update mocks base method.
+func (m *MockResourceKind) Update(identifier, description string, actions []string, attributesSchema []byte) (*model.ResourceKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", identifier, description, actions, attributesSchema)
+	ret0, _ := ret[0].(*model.ResourceKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockResourceKindMockRecorder) Update(identifier, description, actions, attributesSchema interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockResourceKind)(nil).Update), identifier, description, actions, attributesSchema)
+}
+
+// ValidateActions mocks base method.
+func (m *MockResourceKind) ValidateActions(kind string, actions []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateActions", kind, actions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateActions indicates an expected call of ValidateActions.
+func (mr *MockResourceKindMockRecorder) ValidateActions(kind, actions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateActions", reflect.TypeOf((*MockResourceKind)(nil).ValidateActions), kind, actions)
+}
+
+// ValidateActionsByKind mocks base method.
+func (m *MockResourceKind) ValidateActionsByKind(actionsByKind map[string][]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateActionsByKind", actionsByKind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateActionsByKind indicates an expected call of ValidateActionsByKind.
+func (mr *MockResourceKindMockRecorder) ValidateActionsByKind(actionsByKind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateActionsByKind", reflect.TypeOf((*MockResourceKind)(nil).ValidateActionsByKind), actionsByKind)
+}
+
+// ValidateAttributes mocks base method.
+func (m *MockResourceKind) ValidateAttributes(kind string, attributes map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateAttributes", kind, attributes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateAttributes indicates an expected call of ValidateAttributes.
+func (mr *MockResourceKindMockRecorder) ValidateAttributes(kind, attributes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAttributes", reflect.TypeOf((*MockResourceKind)(nil).ValidateAttributes), kind, attributes)
+}
@@ -0,0 +1,376 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type ReviewCampaignRepository repository.Base[model.ReviewCampaign]
+type ReviewItemRepository repository.Base[model.ReviewItem]
+
+type ReviewCampaign interface {
+	Close(identifier string) (*model.ReviewCampaign, error)
+	Create(identifier string, description string, reviewers []string, roles []string, deadline lib_time.Time, autoRevoke bool) (*model.ReviewCampaign, error)
+	Decide(identifier string, reviewerID string, principalID string, roleID string, decision model.ReviewDecision, comment string) (*model.ReviewItem, error)
+	Delete(identifier string) error
+	GetItemRepository() ReviewItemRepository
+	GetRepository() ReviewCampaignRepository
+	Remind(identifier string) error
+}
+
+type reviewCampaignManager struct {
+	repository       ReviewCampaignRepository
+	itemRepository   ReviewItemRepository
+	principalManager Principal
+	roleRepository   RoleRepository
+	clock            time.Clock
+	dispatcher       event.Dispatcher
+}
+
+// NewReviewCampaign initializes a new access review campaign manager.
+func NewReviewCampaign(
+	repository ReviewCampaignRepository,
+	itemRepository ReviewItemRepository,
+	principalManager Principal,
+	roleRepository RoleRepository,
+	clock time.Clock,
+	dispatcher event.Dispatcher,
+) ReviewCampaign {
+	return &reviewCampaignManager{
+		repository:       repository,
+		itemRepository:   itemRepository,
+		principalManager: principalManager,
+		roleRepository:   roleRepository,
+		clock:            clock,
+		dispatcher:       dispatcher,
+	}
+}
+
+func (m *reviewCampaignManager) GetRepository() ReviewCampaignRepository {
+	return m.repository
+}
+
+func (m *reviewCampaignManager) GetItemRepository() ReviewItemRepository {
+	return m.itemRepository
+}
+
+// Create creates a campaign reviewing the roles currently given to principals,
+// optionally restricted to the given roles. Locked principals are not reviewed.
+func (m *reviewCampaignManager) Create(
+	identifier string,
+	description string,
+	reviewers []string,
+	roles []string,
+	deadline lib_time.Time,
+	autoRevoke bool,
+) (*model.ReviewCampaign, error) {
+	exists, err := m.repository.Get(identifier)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("unable to check for existing review campaign: %v", err)
+	}
+
+	if exists != nil {
+		return nil, fmt.Errorf("a review campaign already exists with identifier %q", identifier)
+	}
+
+	if !deadline.After(m.clock.Now()) {
+		return nil, fmt.Errorf("review campaign deadline must be in the future")
+	}
+
+	for _, reviewer := range reviewers {
+		if _, err := m.principalManager.GetRepository().Get(reviewer); err != nil {
+			return nil, fmt.Errorf("unable to retrieve reviewer principal %v: %v", reviewer, err)
+		}
+	}
+
+	var reviewedRoles = map[string]bool{}
+
+	for _, role := range roles {
+		if _, err := m.roleRepository.Get(role); err != nil {
+			return nil, fmt.Errorf("unable to retrieve role %v: %v", role, err)
+		}
+
+		reviewedRoles[role] = true
+	}
+
+	principals, _, err := m.principalManager.GetRepository().Find(
+		repository.WithPreloads("Roles"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	var items = []*model.ReviewItem{}
+
+	for _, principal := range principals {
+		if principal.IsLocked {
+			continue
+		}
+
+		for _, role := range principal.Roles {
+			if len(reviewedRoles) > 0 && !reviewedRoles[role.ID] {
+				continue
+			}
+
+			items = append(items, &model.ReviewItem{
+				CampaignID:  identifier,
+				PrincipalID: principal.ID,
+				RoleID:      role.ID,
+				Decision:    model.ReviewDecisionPending,
+			})
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("there is no role given to principals to review")
+	}
+
+	campaign := &model.ReviewCampaign{
+		ID:          identifier,
+		Description: description,
+		Reviewers:   datatypes.NewJSONType(reviewers),
+		Deadline:    deadline,
+		AutoRevoke:  autoRevoke,
+		Status:      model.ReviewCampaignStatusOpen,
+		Items:       items,
+	}
+
+	if err := m.repository.Create(campaign); err != nil {
+		return nil, fmt.Errorf("unable to create review campaign: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeReviewCampaign, &event.ItemEvent{
+		Action: event.ItemActionCreate,
+		Data:   campaign,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return campaign, nil
+}
+
+// Decide records the decision of a reviewer on a role given to a principal.
+// A revoked role is immediately removed from the principal.
+func (m *reviewCampaignManager) Decide(
+	identifier string,
+	reviewerID string,
+	principalID string,
+	roleID string,
+	decision model.ReviewDecision,
+	comment string,
+) (*model.ReviewItem, error) {
+	if decision != model.ReviewDecisionConfirmed && decision != model.ReviewDecisionRevoked {
+		return nil, fmt.Errorf("review decision must be either %q or %q", model.ReviewDecisionConfirmed, model.ReviewDecisionRevoked)
+	}
+
+	campaign, err := m.repository.Get(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve review campaign: %v", err)
+	}
+
+	if campaign.Status != model.ReviewCampaignStatusOpen || !m.clock.Now().Before(campaign.Deadline) {
+		return nil, fmt.Errorf("review campaign %q is closed", identifier)
+	}
+
+	if !campaign.IsReviewer(reviewerID) {
+		return nil, fmt.Errorf("principal %q is not a reviewer of review campaign %q", reviewerID, identifier)
+	}
+
+	if reviewerID == principalID {
+		return nil, fmt.Errorf("a reviewer cannot review its own roles")
+	}
+
+	item, err := m.itemRepository.GetByFields(map[string]repository.FieldValue{
+		"campaign_id":  {Operator: "=", Value: identifier},
+		"principal_id": {Operator: "=", Value: principalID},
+		"role_id":      {Operator: "=", Value: roleID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve review item: %v", err)
+	}
+
+	if item.Decision != model.ReviewDecisionPending {
+		return nil, fmt.Errorf("role %q of principal %q has already been reviewed", roleID, principalID)
+	}
+
+	if decision == model.ReviewDecisionRevoked {
+		if err := m.revoke(item); err != nil {
+			return nil, err
+		}
+	}
+
+	now := m.clock.Now()
+
+	item.Decision = decision
+	item.ReviewerID = reviewerID
+	item.Comment = comment
+	item.DecidedAt = &now
+
+	if err := m.itemRepository.Update(item); err != nil {
+		return nil, fmt.Errorf("unable to update review item: %v", err)
+	}
+
+	return item, nil
+}
+
+// Close closes the campaign. Roles that were not reviewed are revoked when the
+// campaign automatically revokes them, or kept otherwise.
+func (m *reviewCampaignManager) Close(identifier string) (*model.ReviewCampaign, error) {
+	campaign, err := m.repository.Get(identifier, repository.WithPreloads("Items"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve review campaign: %v", err)
+	}
+
+	if campaign.Status != model.ReviewCampaignStatusOpen {
+		return nil, fmt.Errorf("review campaign %q is already closed", identifier)
+	}
+
+	now := m.clock.Now()
+
+	for _, item := range campaign.Items {
+		if item.Decision != model.ReviewDecisionPending {
+			continue
+		}
+
+		item.Decision = model.ReviewDecisionExpired
+
+		if campaign.AutoRevoke {
+			if err := m.revoke(item); err != nil {
+				return nil, err
+			}
+
+			item.Decision = model.ReviewDecisionRevoked
+			item.Comment = "automatically revoked: not reviewed before the campaign was closed"
+		}
+
+		item.DecidedAt = &now
+
+		if err := m.itemRepository.Update(item); err != nil {
+			return nil, fmt.Errorf("unable to update review item: %v", err)
+		}
+	}
+
+	campaign.Status = model.ReviewCampaignStatusClosed
+	campaign.ClosedAt = &now
+
+	if err := m.repository.Update(campaign); err != nil {
+		return nil, fmt.Errorf("unable to update review campaign: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypeReviewCampaign, &event.ItemEvent{
+		Action: event.ItemActionClose,
+		Data:   campaign,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return campaign, nil
+}
+
+// Remind dispatches a reminder event for the campaign, with the items that
+// still have to be reviewed.
+func (m *reviewCampaignManager) Remind(identifier string) error {
+	campaign, err := m.repository.Get(identifier)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve review campaign: %v", err)
+	}
+
+	pendingItems, _, err := m.itemRepository.Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"campaign_id": {Operator: "=", Value: identifier},
+			"decision":    {Operator: "=", Value: model.ReviewDecisionPending},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve pending review items: %v", err)
+	}
+
+	now := m.clock.Now()
+
+	campaign.RemindedAt = &now
+
+	if err := m.repository.Update(campaign); err != nil {
+		return fmt.Errorf("unable to update review campaign: %v", err)
+	}
+
+	if len(pendingItems) == 0 {
+		return nil
+	}
+
+	campaign.Items = pendingItems
+
+	if err := m.dispatcher.Dispatch(event.EventTypeReviewCampaign, &event.ItemEvent{
+		Action: event.ItemActionRemind,
+		Data:   campaign,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
+	return nil
+}
+
+func (m *reviewCampaignManager) Delete(identifier string) error {
+	campaign, err := m.repository.Get(identifier)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve review campaign: %v", err)
+	}
+
+	if err := m.repository.Delete(campaign); err != nil {
+		return fmt.Errorf("cannot delete review campaign: %v", err)
+	}
+
+	return nil
+}
+
+// revoke removes the role of the review item from its principal, if it still has it.
+func (m *reviewCampaignManager) revoke(item *model.ReviewItem) error {
+	principal, err := m.principalManager.GetRepository().Get(
+		item.PrincipalID,
+		repository.WithPreloads("Roles", "Attributes"),
+	)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to retrieve principal: %v", err)
+	}
+
+	var (
+		roles      = []string{}
+		attributes = map[string]any{}
+		hasRole    bool
+	)
+
+	for _, role := range principal.Roles {
+		if role.ID == item.RoleID {
+			hasRole = true
+			continue
+		}
+
+		roles = append(roles, role.ID)
+	}
+
+	if !hasRole {
+		return nil
+	}
+
+	for _, attribute := range principal.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+
+	if _, err := m.principalManager.Update(principal.ID, roles, attributes); err != nil {
+		return fmt.Errorf("unable to revoke role %q of principal %q: %v", item.RoleID, principal.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/manager/review.go
+
+// Package manager is a generated GoMock package.
+package manager
+
+import (
+	reflect "reflect"
+	time "time"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReviewCampaign is a mock of ReviewCampaign interface.
+type MockReviewCampaign struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewCampaignMockRecorder
+}
+
+// MockReviewCampaignMockRecorder is the mock recorder for MockReviewCampaign.
+type MockReviewCampaignMockRecorder struct {
+	mock *MockReviewCampaign
+}
+
+// NewMockReviewCampaign creates a new mock instance.
+func NewMockReviewCampaign(ctrl *gomock.Controller) *MockReviewCampaign {
+	mock := &MockReviewCampaign{ctrl: ctrl}
+	mock.recorder = &MockReviewCampaignMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewCampaign) EXPECT() *MockReviewCampaignMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockReviewCampaign) Close(identifier string) (*model.ReviewCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", identifier)
+	ret0, _ := ret[0].(*model.ReviewCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockReviewCampaignMockRecorder) Close(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockReviewCampaign)(nil).Close), identifier)
+}
+
+// Create mocks base method.
+func (m *MockReviewCampaign) Create(identifier, description string, reviewers, roles []string, deadline time.Time, autoRevoke bool) (*model.ReviewCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", identifier, description, reviewers, roles, deadline, autoRevoke)
+	ret0, _ := ret[0].(*model.ReviewCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReviewCampaignMockRecorder) Create(identifier, description, reviewers, roles, deadline, autoRevoke interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReviewCampaign)(nil).Create), identifier, description, reviewers, roles, deadline, autoRevoke)
+}
+
+// Decide mocks base method.
+func (m *MockReviewCampaign) Decide(identifier, reviewerID, principalID, roleID string, decision model.ReviewDecision, comment string) (*model.ReviewItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", identifier, reviewerID, principalID, roleID, decision, comment)
+	ret0, _ := ret[0].(*model.ReviewItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockReviewCampaignMockRecorder) Decide(identifier, reviewerID, principalID, roleID, decision, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockReviewCampaign)(nil).Decide), identifier, reviewerID, principalID, roleID, decision, comment)
+}
+
+// Delete mocks base method.
+func (m *MockReviewCampaign) Delete(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReviewCampaignMockRecorder) Delete(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReviewCampaign)(nil).Delete), identifier)
+}
+
+// GetItemRepository mocks base method.
+func (m *MockReviewCampaign) GetItemRepository() ReviewItemRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemRepository")
+	ret0, _ := ret[0].(ReviewItemRepository)
+	return ret0
+}
+
+// GetItemRepository indicates an expected call of GetItemRepository.
+func (mr *MockReviewCampaignMockRecorder) GetItemRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemRepository", reflect.TypeOf((*MockReviewCampaign)(nil).GetItemRepository))
+}
+
+// GetRepository mocks base method.
+func (m *MockReviewCampaign) GetRepository() ReviewCampaignRepository {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepository")
+	ret0, _ := ret[0].(ReviewCampaignRepository)
+	return ret0
+}
+
+// GetRepository indicates an expected call of GetRepository.
+func (mr *MockReviewCampaignMockRecorder) GetRepository() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepository", reflect.TypeOf((*MockReviewCampaign)(nil).GetRepository))
+}
+
+// Remind mocks base method.
+func (m *MockReviewCampaign) Remind(identifier string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remind", identifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remind indicates an expected call of Remind.
+func (mr *MockReviewCampaignMockRecorder) Remind(identifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remind", reflect.TypeOf((*MockReviewCampaign)(nil).Remind), identifier)
+}
@@ -100,6 +100,13 @@ func (m *roleManager) Delete(identifier string) error {
 		return fmt.Errorf("cannot delete role: %v", err)
 	}
 
+	if err := m.dispatcher.Dispatch(event.EventTypeRole, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   role,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
 	return nil
 }
 
@@ -123,7 +130,6 @@ func (m *roleManager) Update(identifier string, policies []string) (*model.Role,
 	role.Policies = policyObjects
 
 	transaction := m.transactionManager.New()
-	defer func() { _ = transaction.Commit() }()
 
 	roleRepository := m.repository.WithTransaction(transaction)
 
@@ -137,11 +143,14 @@ func (m *roleManager) Update(identifier string, policies []string) (*model.Role,
 		return nil, fmt.Errorf("unable to update role: %v", err)
 	}
 
+	if err := transaction.Commit(); err != nil {
+		return nil, fmt.Errorf("unable to commit role update: %v", err)
+	}
+
 	if err := m.dispatcher.Dispatch(event.EventTypeRole, &event.ItemEvent{
 		Action: event.ItemActionUpdate,
 		Data:   role,
 	}); err != nil {
-		_ = transaction.Rollback()
 		return nil, fmt.Errorf("unable to dispatch event: %v", err)
 	}
 
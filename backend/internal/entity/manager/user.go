@@ -7,6 +7,7 @@ import (
 	"github.com/eko/authz/backend/internal/database"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/internal/helper/token"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -24,22 +25,31 @@ type User interface {
 type userManager struct {
 	repository          UserRepository
 	principalRepository repository.Base[model.Principal]
+	compiledRepository  CompiledPolicyRepository
+	compileJobManager   CompileJob
 	transactionManager  database.TransactionManager
 	tokenGenerator      token.Generator
+	dispatcher          event.Dispatcher
 }
 
 // NewUser initializes a new user manager.
 func NewUser(
 	repository UserRepository,
 	principalRepository repository.Base[model.Principal],
+	compiledRepository CompiledPolicyRepository,
+	compileJobManager CompileJob,
 	transactionManager database.TransactionManager,
 	tokenGenerator token.Generator,
+	dispatcher event.Dispatcher,
 ) User {
 	return &userManager{
 		repository:          repository,
 		principalRepository: principalRepository,
+		compiledRepository:  compiledRepository,
+		compileJobManager:   compileJobManager,
 		transactionManager:  transactionManager,
 		tokenGenerator:      tokenGenerator,
+		dispatcher:          dispatcher,
 	}
 }
 
@@ -113,11 +123,10 @@ func (m *userManager) Delete(username string) error {
 
 	// Delete both user and principal
 	transaction := m.transactionManager.New()
-	defer func() { _ = transaction.Commit() }()
 
-	if err := m.principalRepository.WithTransaction(transaction).Delete(principal); err != nil {
+	if err := deletePrincipal(transaction, principal, m.principalRepository, m.compiledRepository, m.compileJobManager); err != nil {
 		_ = transaction.Rollback()
-		return fmt.Errorf("cannot delete principal: %v", err)
+		return err
 	}
 
 	if err := m.GetRepository().WithTransaction(transaction).Delete(user); err != nil {
@@ -125,6 +134,17 @@ func (m *userManager) Delete(username string) error {
 		return fmt.Errorf("cannot delete user: %v", err)
 	}
 
+	if err := transaction.Commit(); err != nil {
+		return fmt.Errorf("unable to commit user deletion: %v", err)
+	}
+
+	if err := m.dispatcher.Dispatch(event.EventTypePrincipal, &event.ItemEvent{
+		Action: event.ItemActionDelete,
+		Data:   principal,
+	}); err != nil {
+		return fmt.Errorf("unable to dispatch event: %v", err)
+	}
+
 	return nil
 }
 
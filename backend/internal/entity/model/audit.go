@@ -10,7 +10,10 @@ type Audit struct {
 	ResourceValue string    `json:"resource_value"`
 	Action        string    `json:"action"`
 	IsAllowed     bool      `json:"is_allowed"`
+	Algorithm     string    `json:"algorithm"`
 	PolicyID      string    `json:"policy_id"`
+	CedarPolicyID string    `json:"cedar_policy_id"`
+	DelegationID  string    `json:"delegation_id"`
 }
 
 func (Audit) TableName() string {
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// CedarPolicy is a policy written in the Cedar language. It is evaluated at
+// check time next to compiled policies, "permit" and "forbid" statements
+// respectively having the allow and deny effects.
+type CedarPolicy struct {
+	ID           string       `json:"id" gorm:"primarykey"`
+	Source       string       `json:"source"`
+	Effect       PolicyEffect `json:"effect"`
+	ResourceKind string       `json:"resource_kind" gorm:"index"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+func (CedarPolicy) TableName() string {
+	return "authz_cedar_policies"
+}
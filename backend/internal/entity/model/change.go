@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ChangeItemTypeCompiled is the item type of the changes replacing all the
+// compiled policies at once, after a rebuild.
+const ChangeItemTypeCompiled = "compiled"
+
+// Change is an entry of the change log: an item that has been changed,
+// deleted or compiled. Agents are synchronized from the change log, so the
+// server only loads the decision data that changed.
+type Change struct {
+	ID        int64     `json:"id" gorm:"primarykey;autoIncrement"`
+	ItemType  string    `json:"item_type"`
+	ItemID    string    `json:"item_id"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+func (Change) TableName() string {
+	return "authz_changes"
+}
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+type CompileJobTargetType string
+
+const (
+	CompileJobTargetTypePolicy    CompileJobTargetType = "policy"
+	CompileJobTargetTypePrincipal CompileJobTargetType = "principal"
+	CompileJobTargetTypeResource  CompileJobTargetType = "resource"
+)
+
+type CompileJobStatus string
+
+const (
+	// CompileJobStatusPending is used for jobs waiting to be processed,
+	// either for the first time or to be retried after a failure.
+	CompileJobStatusPending CompileJobStatus = "pending"
+
+	// CompileJobStatusRunning is used for jobs currently being processed by a worker.
+	CompileJobStatusRunning CompileJobStatus = "running"
+
+	// CompileJobStatusSucceeded is used for jobs whose last compilation succeeded.
+	CompileJobStatusSucceeded CompileJobStatus = "succeeded"
+
+	// CompileJobStatusFailed is used for jobs that failed on all their attempts.
+	// They are processed again when their target changes or when retried manually.
+	CompileJobStatusFailed CompileJobStatus = "failed"
+)
+
+// CompileJob is the compilation of a policy, principal or resource into
+// compiled policies. There is a single job per target: it is reset each
+// time the target changes.
+//
+// PendingSince and PendingRevision are the date and the revision of the
+// oldest change of the target that is not compiled yet. They are only
+// meaningful while the job has not succeeded. Revision is the revision of the
+// last change of the target. Revisions are allocated from the compiled
+// policies versions, so they are comparable with consistency tokens.
+//
+// ClaimID identifies the last claim of the job, so a worker only updates the
+// job as long as it was not claimed again since. ClaimExpiresAt is renewed by
+// the instance running the job: once reached, the instance is considered as
+// stopped and the job is processed again.
+type CompileJob struct {
+	ID              int64                `json:"id" gorm:"primarykey;autoIncrement"`
+	TargetType      CompileJobTargetType `json:"target_type" gorm:"uniqueIndex:idx_authz_compile_jobs_target"`
+	TargetID        string               `json:"target_id" gorm:"uniqueIndex:idx_authz_compile_jobs_target"`
+	Status          CompileJobStatus     `json:"status" gorm:"index"`
+	Attempts        int                  `json:"attempts"`
+	LastError       string               `json:"last_error,omitempty"`
+	NextRunAt       time.Time            `json:"next_run_at" gorm:"index"`
+	PendingSince    time.Time            `json:"pending_since" gorm:"index"`
+	PendingRevision int64                `json:"pending_revision" gorm:"index"`
+	Revision        int64                `json:"revision"`
+	ClaimID         string               `json:"-"`
+	ClaimExpiresAt  *time.Time           `json:"-"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
+
+func (CompileJob) TableName() string {
+	return "authz_compile_jobs"
+}
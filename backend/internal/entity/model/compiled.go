@@ -2,12 +2,15 @@ package model
 
 import "time"
 
+// CompiledPolicy is a resource and action a policy applies to, for every
+// principal when compiled from the roles of the policy or for a principal when
+// compiled from an attribute rule. The lookup index covers decision lookups.
 type CompiledPolicy struct {
-	PolicyID      string    `json:"policy_id" gorm:"index"`
-	PrincipalID   string    `json:"principal_id" gorm:"index"`
-	ResourceKind  string    `json:"resource_kind" gorm:"index"`
-	ResourceValue string    `json:"resource_value" gorm:"index"`
-	ActionID      string    `json:"action_id" gorm:"index"`
+	PolicyID      string    `json:"policy_id" gorm:"index;index:,composite:lookup,priority:5"`
+	PrincipalID   string    `json:"principal_id" gorm:"index;index:,composite:lookup,priority:4"`
+	ResourceKind  string    `json:"resource_kind" gorm:"index;index:,composite:lookup,priority:1"`
+	ResourceValue string    `json:"resource_value" gorm:"index;index:,composite:lookup,priority:3"`
+	ActionID      string    `json:"action_id" gorm:"index;index:,composite:lookup,priority:2"`
 	Version       int64     `json:"version" gorm:"index"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
@@ -16,3 +19,14 @@ type CompiledPolicy struct {
 func (CompiledPolicy) TableName() string {
 	return "authz_compiled_policies"
 }
+
+// CompiledVersion allocates versions of compiled policies: each compilation
+// uses the identifier of a new row, greater than the ones allocated before.
+type CompiledVersion struct {
+	ID        int64     `json:"id" gorm:"primarykey;autoIncrement"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CompiledVersion) TableName() string {
+	return "authz_compiled_versions"
+}
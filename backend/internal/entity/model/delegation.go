@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type Delegation struct {
+	ID          string      `json:"id" gorm:"primarykey"`
+	DelegatorID string      `json:"delegator_id" gorm:"index"`
+	Delegator   *Principal  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	DelegateID  string      `json:"delegate_id" gorm:"index"`
+	Delegate    *Principal  `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Resources   []*Resource `json:"resources,omitempty" gorm:"many2many:authz_delegations_resources;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Actions     []*Action   `json:"actions,omitempty" gorm:"many2many:authz_delegations_actions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ExpiresAt   time.Time   `json:"expires_at" gorm:"index"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+func (Delegation) TableName() string {
+	return "authz_delegations"
+}
+
+// IsActive returns whether the delegation is still valid at the given time.
+func (d *Delegation) IsActive(now time.Time) bool {
+	return now.Before(d.ExpiresAt)
+}
@@ -2,5 +2,5 @@ package model
 
 // Models is a constraint interface that allows only authz library models.
 type Models interface {
-	Action | Audit | Attribute | Client | CompiledPolicy | Policy | Principal | Resource | Role | Stats | Token | User
+	Action | Audit | Attribute | CedarPolicy | Change | Client | CompileJob | CompiledPolicy | Delegation | Policy | Principal | Resource | ResourceKind | ReviewCampaign | ReviewItem | Role | Stats | Token | User
 }
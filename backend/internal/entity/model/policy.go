@@ -1,18 +1,34 @@
 package model
 
 import (
+	"sort"
 	"time"
 
+	"github.com/eko/authz/backend/internal/helper/schedule"
 	"gorm.io/datatypes"
 )
 
+type PolicyEffect string
+
+const (
+	PolicyEffectAllow PolicyEffect = "allow"
+	PolicyEffectDeny  PolicyEffect = "deny"
+)
+
 type Policy struct {
-	ID             string                       `json:"id" gorm:"primarykey"`
-	Resources      []*Resource                  `json:"resources,omitempty" gorm:"many2many:authz_policies_resources;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	Actions        []*Action                    `json:"actions,omitempty" gorm:"many2many:authz_policies_actions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	AttributeRules datatypes.JSONType[[]string] `json:"attribute_rules,omitempty" swaggertype:"object"`
-	CreatedAt      time.Time                    `json:"created_at"`
-	UpdatedAt      time.Time                    `json:"updated_at"`
+	ID               string                       `json:"id" gorm:"primarykey"`
+	Resources        []*Resource                  `json:"resources,omitempty" gorm:"many2many:authz_policies_resources;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Actions          []*Action                    `json:"actions,omitempty" gorm:"many2many:authz_policies_actions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	AttributeRules   datatypes.JSONType[[]string] `json:"attribute_rules,omitempty" swaggertype:"object"`
+	Effect           PolicyEffect                 `json:"effect" gorm:"default:allow"`
+	Priority         int                          `json:"priority"`
+	NotBefore        *time.Time                   `json:"not_before,omitempty"`
+	NotAfter         *time.Time                   `json:"not_after,omitempty"`
+	Schedule         string                       `json:"schedule,omitempty"`
+	ScheduleDuration string                       `json:"schedule_duration,omitempty"`
+	ScheduleTimezone string                       `json:"schedule_timezone,omitempty"`
+	CreatedAt        time.Time                    `json:"created_at"`
+	UpdatedAt        time.Time                    `json:"updated_at"`
 
 	Roles []*Role `json:"-" gorm:"many2many:authz_roles_policies"`
 }
@@ -20,3 +36,90 @@ type Policy struct {
 func (Policy) TableName() string {
 	return "authz_policies"
 }
+
+// IsDeny returns whether the policy denies access when it applies.
+func (p *Policy) IsDeny() bool {
+	return p.Effect == PolicyEffectDeny
+}
+
+// HasTimeConstraints returns whether the policy only applies during some time windows.
+func (p *Policy) HasTimeConstraints() bool {
+	return p.NotBefore != nil || p.NotAfter != nil || p.Schedule != ""
+}
+
+// IsActive returns whether the policy applies at the given time, regarding
+// its validity window (not before / not after) and its recurring schedule.
+func (p *Policy) IsActive(now time.Time) (bool, error) {
+	if p.NotBefore != nil && now.Before(*p.NotBefore) {
+		return false, nil
+	}
+
+	if p.NotAfter != nil && !now.Before(*p.NotAfter) {
+		return false, nil
+	}
+
+	if p.Schedule == "" {
+		return true, nil
+	}
+
+	policySchedule, err := schedule.Parse(p.Schedule, p.ScheduleDuration, p.ScheduleTimezone)
+	if err != nil {
+		return false, err
+	}
+
+	return policySchedule.IsActive(now), nil
+}
+
+// PolicyTransition is a time at which a policy becomes active or inactive.
+type PolicyTransition struct {
+	At       time.Time
+	IsActive bool
+}
+
+// Transitions returns, in order, the times in the (from, to] range at which the
+// policy becomes active or inactive, including time windows opening and closing
+// within the range.
+func (p *Policy) Transitions(from time.Time, to time.Time) ([]PolicyTransition, error) {
+	var boundaries = []time.Time{}
+
+	for _, limit := range []*time.Time{p.NotBefore, p.NotAfter} {
+		if limit != nil && limit.After(from) && !limit.After(to) {
+			boundaries = append(boundaries, *limit)
+		}
+	}
+
+	if p.Schedule != "" {
+		policySchedule, err := schedule.Parse(p.Schedule, p.ScheduleDuration, p.ScheduleTimezone)
+		if err != nil {
+			return nil, err
+		}
+
+		boundaries = append(boundaries, policySchedule.Boundaries(from, to)...)
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	wasActive, err := p.IsActive(from)
+	if err != nil {
+		return nil, err
+	}
+
+	var transitions = []PolicyTransition{}
+
+	// The policy state can only change at a boundary.
+	for _, boundary := range boundaries {
+		isActive, err := p.IsActive(boundary)
+		if err != nil {
+			return nil, err
+		}
+
+		if isActive != wasActive {
+			transitions = append(transitions, PolicyTransition{At: boundary, IsActive: isActive})
+			wasActive = isActive
+		}
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ResourceKind declares the actions allowed on the resources of a kind and the
+// JSON Schema their attributes must comply with.
+// Resources of a kind that is not declared are not restricted.
+type ResourceKind struct {
+	ID               string         `json:"id" gorm:"primarykey"`
+	Description      string         `json:"description,omitempty"`
+	Actions          []*Action      `json:"actions,omitempty" gorm:"many2many:authz_resource_kinds_actions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	AttributesSchema datatypes.JSON `json:"attributes_schema,omitempty" swaggertype:"object"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+func (ResourceKind) TableName() string {
+	return "authz_resource_kinds"
+}
+
+// AllowsAction returns whether the given action is declared on the resource kind.
+func (k *ResourceKind) AllowsAction(actionID string) bool {
+	for _, action := range k.Actions {
+		if action.ID == actionID {
+			return true
+		}
+	}
+
+	return false
+}
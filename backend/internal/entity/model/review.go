@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type ReviewCampaignStatus string
+
+const (
+	ReviewCampaignStatusOpen   ReviewCampaignStatus = "open"
+	ReviewCampaignStatusClosed ReviewCampaignStatus = "closed"
+)
+
+type ReviewDecision string
+
+const (
+	// ReviewDecisionPending is used for grants that have not been reviewed yet.
+	ReviewDecisionPending ReviewDecision = "pending"
+
+	// ReviewDecisionConfirmed is used for grants a reviewer confirmed.
+	ReviewDecisionConfirmed ReviewDecision = "confirmed"
+
+	// ReviewDecisionRevoked is used for grants a reviewer revoked, or that were
+	// automatically revoked because they were not reviewed before the deadline.
+	ReviewDecisionRevoked ReviewDecision = "revoked"
+
+	// ReviewDecisionExpired is used for grants that were not reviewed before
+	// the campaign was closed and that were kept.
+	ReviewDecisionExpired ReviewDecision = "expired"
+)
+
+// ReviewCampaign is an access review (certification) campaign in which reviewers
+// must confirm or revoke the roles given to principals before a deadline.
+type ReviewCampaign struct {
+	ID          string                       `json:"id" gorm:"primarykey"`
+	Description string                       `json:"description,omitempty"`
+	Reviewers   datatypes.JSONType[[]string] `json:"reviewers" swaggertype:"array,string"`
+	Deadline    time.Time                    `json:"deadline" gorm:"index"`
+	AutoRevoke  bool                         `json:"auto_revoke"`
+	Status      ReviewCampaignStatus         `json:"status" gorm:"index"`
+	RemindedAt  *time.Time                   `json:"reminded_at,omitempty"`
+	ClosedAt    *time.Time                   `json:"closed_at,omitempty"`
+	Items       []*ReviewItem                `json:"items,omitempty" gorm:"foreignKey:CampaignID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+}
+
+func (ReviewCampaign) TableName() string {
+	return "authz_review_campaigns"
+}
+
+// IsReviewer returns whether the given principal is a reviewer of the campaign.
+func (c *ReviewCampaign) IsReviewer(principalID string) bool {
+	for _, reviewer := range c.Reviewers.Data() {
+		if reviewer == principalID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReviewItem is a role given to a principal that has to be reviewed during a campaign.
+type ReviewItem struct {
+	CampaignID  string         `json:"campaign_id" gorm:"primarykey"`
+	PrincipalID string         `json:"principal_id" gorm:"primarykey"`
+	RoleID      string         `json:"role_id" gorm:"primarykey"`
+	Decision    ReviewDecision `json:"decision" gorm:"index"`
+	ReviewerID  string         `json:"reviewer_id,omitempty"`
+	Comment     string         `json:"comment,omitempty"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (ReviewItem) TableName() string {
+	return "authz_review_items"
+}
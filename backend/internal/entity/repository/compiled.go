@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+)
+
+// CompiledPolicyLookup is a resource and action to find the compiled policies of.
+type CompiledPolicyLookup struct {
+	ResourceKind  string
+	ResourceValue string
+	ActionID      string
+}
+
+// ApplicableCompiledPolicy is a compiled policy applying to a looked up resource
+// and action, along with the fields of its policy used to combine it.
+// RolePrincipalID is the looked up principal it applies to through one of its
+// roles, if any.
+type ApplicableCompiledPolicy struct {
+	model.CompiledPolicy
+	RolePrincipalID  string
+	Effect           model.PolicyEffect
+	Priority         int
+	NotBefore        *time.Time
+	NotAfter         *time.Time
+	Schedule         string
+	ScheduleDuration string
+	ScheduleTimezone string
+}
+
+// AppliesTo returns whether the compiled policy applies to the given check,
+// a compiled policy on the wildcard value applying to all the resources of the kind.
+func (a *ApplicableCompiledPolicy) AppliesTo(principalID string, resourceKind string, resourceValue string, actionID string) bool {
+	return (a.PrincipalID == principalID || a.RolePrincipalID == principalID) &&
+		a.ResourceKind == resourceKind &&
+		(a.ResourceValue == resourceValue || a.ResourceValue == "*") &&
+		a.ActionID == actionID
+}
+
+// Policy returns the policy the compiled policy comes from, without its relationships.
+func (a *ApplicableCompiledPolicy) Policy() *model.Policy {
+	return &model.Policy{
+		ID:               a.PolicyID,
+		Effect:           a.Effect,
+		Priority:         a.Priority,
+		NotBefore:        a.NotBefore,
+		NotAfter:         a.NotAfter,
+		Schedule:         a.Schedule,
+		ScheduleDuration: a.ScheduleDuration,
+		ScheduleTimezone: a.ScheduleTimezone,
+	}
+}
+
+type CompiledPolicy interface {
+	Base[model.CompiledPolicy]
+	FindApplicable(principalIDs []string, lookups []*CompiledPolicyLookup) ([]*ApplicableCompiledPolicy, error)
+}
+
+// compiledPolicy struct that allows contacting the database using Gorm.
+type compiledPolicy struct {
+	Base[model.CompiledPolicy]
+}
+
+// NewCompiledPolicy initializes a new compiled policy repository.
+func NewCompiledPolicy(repository Base[model.CompiledPolicy]) CompiledPolicy {
+	return &compiledPolicy{
+		repository,
+	}
+}
+
+// FindApplicable returns, in a single query, the compiled policies applying to
+// the given principals (directly or through their roles) on the given resources
+// (or the wildcard value of their kind) and actions.
+// Rows are returned for all the combinations of principals and lookups: callers
+// keep the ones applying to each of their checks.
+func (r *compiledPolicy) FindApplicable(principalIDs []string, lookups []*CompiledPolicyLookup) ([]*ApplicableCompiledPolicy, error) {
+	var result = make([]*ApplicableCompiledPolicy, 0)
+
+	if len(principalIDs) == 0 || len(lookups) == 0 {
+		return result, nil
+	}
+
+	var (
+		conditions = make([]string, 0, len(lookups))
+		values     = make([]any, 0, len(lookups)*3)
+	)
+
+	for _, lookup := range lookups {
+		resourceValues := []string{lookup.ResourceValue}
+		if lookup.ResourceValue != "*" {
+			resourceValues = append(resourceValues, "*")
+		}
+
+		conditions = append(conditions, "(authz_compiled_policies.resource_kind = ? AND authz_compiled_policies.action_id = ? AND authz_compiled_policies.resource_value IN ?)")
+		values = append(values, lookup.ResourceKind, lookup.ActionID, resourceValues)
+	}
+
+	err := r.DB().
+		Table(model.CompiledPolicy{}.TableName()).
+		Select(
+			"authz_compiled_policies.*",
+			"COALESCE(role_policies.principal_id, '') AS role_principal_id",
+			"authz_policies.effect",
+			"authz_policies.priority",
+			"authz_policies.not_before",
+			"authz_policies.not_after",
+			"authz_policies.schedule",
+			"authz_policies.schedule_duration",
+			"authz_policies.schedule_timezone",
+		).
+		Joins("INNER JOIN authz_policies ON authz_policies.id = authz_compiled_policies.policy_id").
+		Joins(
+			"LEFT JOIN (SELECT DISTINCT authz_roles_policies.policy_id, authz_principals_roles.principal_id FROM authz_roles_policies "+
+				"INNER JOIN authz_principals_roles ON authz_principals_roles.role_id = authz_roles_policies.role_id "+
+				"WHERE authz_principals_roles.principal_id IN ?) AS role_policies ON role_policies.policy_id = authz_compiled_policies.policy_id",
+			principalIDs,
+		).
+		Where("("+strings.Join(conditions, " OR ")+")", values...).
+		Where("(authz_compiled_policies.principal_id IN ? OR role_policies.principal_id IS NOT NULL)", principalIDs).
+		Scan(&result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -4,9 +4,30 @@ import (
 	"github.com/eko/authz/backend/internal/entity/model"
 )
 
+type PrincipalQueryOption func(*principalQueryOptions)
+
+type principalQueryOptions struct {
+	attributeValues []string
+	principalIDs    []string
+}
+
+func WithPrincipalIDs(principalIDs []string) PrincipalQueryOption {
+	return func(o *principalQueryOptions) {
+		o.principalIDs = principalIDs
+	}
+}
+
+// WithPrincipalAttributeValues restricts matching attributes lookups to the given values.
+func WithPrincipalAttributeValues(values []string) PrincipalQueryOption {
+	return func(o *principalQueryOptions) {
+		o.attributeValues = values
+	}
+}
+
 type Principal interface {
 	Base[model.Principal]
-	FindMatchingAttribute(principalAttribute string) ([]*PrincipalMatchingAttribute, error)
+	FindAttributeKeys() ([]string, error)
+	FindMatchingAttribute(principalAttribute string, options ...PrincipalQueryOption) ([]*PrincipalMatchingAttribute, error)
 }
 
 // besource struct that allows contacting the database using Gorm.
@@ -26,10 +47,28 @@ type PrincipalMatchingAttribute struct {
 	AttributeValue string
 }
 
-func (r *principal) FindMatchingAttribute(principalAttribute string) ([]*PrincipalMatchingAttribute, error) {
+func (r *principal) FindMatchingAttribute(
+	principalAttribute string,
+	options ...PrincipalQueryOption,
+) ([]*PrincipalMatchingAttribute, error) {
 	matches := []*PrincipalMatchingAttribute{}
 
-	err := r.DB().
+	opts := &principalQueryOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	tx := r.DB()
+
+	if len(opts.principalIDs) > 0 {
+		tx = tx.Where("authz_principals.id IN ?", opts.principalIDs)
+	}
+
+	if opts.attributeValues != nil {
+		tx = tx.Where("authz_attributes.value IN ?", opts.attributeValues)
+	}
+
+	err := tx.
 		Select("authz_principals.id AS principal_id, authz_attributes.value AS attribute_value").
 		Model(&model.Principal{}).
 		Joins("INNER JOIN authz_principals_attributes ON authz_principals_attributes.principal_id = authz_principals.id").
@@ -42,3 +81,21 @@ func (r *principal) FindMatchingAttribute(principalAttribute string) ([]*Princip
 
 	return matches, nil
 }
+
+// FindAttributeKeys returns the distinct attribute keys of existing principals.
+func (r *principal) FindAttributeKeys() ([]string, error) {
+	var keys []string
+
+	err := r.DB().
+		Model(&model.Principal{}).
+		Joins("INNER JOIN authz_principals_attributes ON authz_principals_attributes.principal_id = authz_principals.id").
+		Joins("INNER JOIN authz_attributes ON authz_principals_attributes.attribute_id = authz_attributes.id").
+		Distinct("authz_attributes.key_name").
+		Order("authz_attributes.key_name").
+		Pluck("authz_attributes.key_name", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
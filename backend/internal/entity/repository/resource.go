@@ -8,7 +8,16 @@ import (
 type ResourceQueryOption func(*resourceQueryOptions)
 
 type resourceQueryOptions struct {
-	resourceIDs []string
+	attributeValues  []string
+	resourceIDs      []string
+	withoutWildcards bool
+}
+
+// WithResourceAttributeValues restricts matching attributes lookups to the given values.
+func WithResourceAttributeValues(values []string) ResourceQueryOption {
+	return func(o *resourceQueryOptions) {
+		o.attributeValues = values
+	}
 }
 
 func WithResourceIDs(resourceIDs []string) ResourceQueryOption {
@@ -17,8 +26,17 @@ func WithResourceIDs(resourceIDs []string) ResourceQueryOption {
 	}
 }
 
+// WithoutWildcards excludes wildcard resources (having a "*" value).
+func WithoutWildcards() ResourceQueryOption {
+	return func(o *resourceQueryOptions) {
+		o.withoutWildcards = true
+	}
+}
+
 type Resource interface {
 	Base[model.Resource]
+	FindAttributeKeys() ([]string, error)
+	FindKinds(options ...ResourceQueryOption) ([]string, error)
 	FindMatchingAttribute(resourceAttribute string, options ...ResourceQueryOption) ([]*ResourceMatchingAttribute, error)
 }
 
@@ -34,6 +52,40 @@ func NewResource(repository Base[model.Resource]) Resource {
 	}
 }
 
+// FindAttributeKeys returns the distinct attribute keys of existing resources.
+func (r *resource) FindAttributeKeys() ([]string, error) {
+	var keys []string
+
+	err := r.DB().
+		Model(&model.Resource{}).
+		Joins("INNER JOIN authz_resources_attributes ON authz_resources.id = authz_resources_attributes.resource_id").
+		Joins("INNER JOIN authz_attributes ON authz_resources_attributes.attribute_id = authz_attributes.id").
+		Distinct("authz_attributes.key_name").
+		Order("authz_attributes.key_name").
+		Pluck("authz_attributes.key_name", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindKinds returns the distinct kinds of existing resources.
+func (r *resource) FindKinds(options ...ResourceQueryOption) ([]string, error) {
+	var kinds []string
+
+	err := applyResourceOptions(r.DB(), options).
+		Model(&model.Resource{}).
+		Distinct("kind").
+		Order("kind").
+		Pluck("kind", &kinds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return kinds, nil
+}
+
 type ResourceMatchingAttribute struct {
 	ResourceKind   string
 	ResourceValue  string
@@ -46,8 +98,17 @@ func (r *resource) FindMatchingAttribute(
 ) ([]*ResourceMatchingAttribute, error) {
 	matches := []*ResourceMatchingAttribute{}
 
+	opts := &resourceQueryOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
 	tx := applyResourceOptions(r.DB(), options)
 
+	if opts.attributeValues != nil {
+		tx = tx.Where("authz_attributes.value IN ?", opts.attributeValues)
+	}
+
 	err := tx.
 		Select("authz_resources.kind AS resource_kind, authz_resources.value AS resource_value, authz_attributes.value AS attribute_value").
 		Model(&model.Resource{}).
@@ -74,5 +135,9 @@ func applyResourceOptions(tx *gorm.DB, options []ResourceQueryOption) *gorm.DB {
 		tx = tx.Where("authz_resources.id IN ?", opts.resourceIDs)
 	}
 
+	if opts.withoutWildcards {
+		tx = tx.Where("authz_resources.value <> ?", "*")
+	}
+
 	return tx
 }
@@ -0,0 +1,121 @@
+package transformer
+
+import (
+	"time"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/pkg/authz"
+)
+
+type agentCompiledPolicy struct {
+	entity *model.CompiledPolicy
+}
+
+func NewAgentCompiledPolicy(entity *model.CompiledPolicy) *agentCompiledPolicy {
+	return &agentCompiledPolicy{
+		entity: entity,
+	}
+}
+
+func (t *agentCompiledPolicy) ToProto() *authz.AgentCompiledPolicy {
+	return &authz.AgentCompiledPolicy{
+		PolicyId:      t.entity.PolicyID,
+		PrincipalId:   t.entity.PrincipalID,
+		ResourceKind:  t.entity.ResourceKind,
+		ResourceValue: t.entity.ResourceValue,
+		ActionId:      t.entity.ActionID,
+	}
+}
+
+type agentPolicy struct {
+	entity *model.Policy
+}
+
+func NewAgentPolicy(entity *model.Policy) *agentPolicy {
+	return &agentPolicy{
+		entity: entity,
+	}
+}
+
+func (t *agentPolicy) ToProto() *authz.AgentPolicy {
+	return &authz.AgentPolicy{
+		Id:               t.entity.ID,
+		Effect:           string(t.entity.Effect),
+		Priority:         int64(t.entity.Priority),
+		NotBefore:        agentTime(t.entity.NotBefore),
+		NotAfter:         agentTime(t.entity.NotAfter),
+		Schedule:         t.entity.Schedule,
+		ScheduleDuration: t.entity.ScheduleDuration,
+		ScheduleTimezone: t.entity.ScheduleTimezone,
+	}
+}
+
+type agentResourceKind struct {
+	entity *model.ResourceKind
+}
+
+func NewAgentResourceKind(entity *model.ResourceKind) *agentResourceKind {
+	return &agentResourceKind{
+		entity: entity,
+	}
+}
+
+func (t *agentResourceKind) ToProto() *authz.AgentResourceKind {
+	return &authz.AgentResourceKind{
+		Id:      t.entity.ID,
+		Actions: NewActions(t.entity.Actions).ToStringSlice(),
+	}
+}
+
+type agentCedarPolicy struct {
+	entity *model.CedarPolicy
+}
+
+func NewAgentCedarPolicy(entity *model.CedarPolicy) *agentCedarPolicy {
+	return &agentCedarPolicy{
+		entity: entity,
+	}
+}
+
+func (t *agentCedarPolicy) ToProto() *authz.AgentCedarPolicy {
+	return &authz.AgentCedarPolicy{
+		Id:           t.entity.ID,
+		Source:       t.entity.Source,
+		Effect:       string(t.entity.Effect),
+		ResourceKind: t.entity.ResourceKind,
+	}
+}
+
+type agentDelegation struct {
+	entity *model.Delegation
+}
+
+func NewAgentDelegation(entity *model.Delegation) *agentDelegation {
+	return &agentDelegation{
+		entity: entity,
+	}
+}
+
+func (t *agentDelegation) ToProto() *authz.AgentDelegation {
+	var resources = []*authz.Resource{}
+	for _, resource := range t.entity.Resources {
+		resources = append(resources, NewResource(resource).ToProto())
+	}
+
+	return &authz.AgentDelegation{
+		Id:          t.entity.ID,
+		DelegatorId: t.entity.DelegatorID,
+		DelegateId:  t.entity.DelegateID,
+		Resources:   resources,
+		Actions:     NewActions(t.entity.Actions).ToStringSlice(),
+		ExpiresAt:   agentTime(&t.entity.ExpiresAt),
+	}
+}
+
+func agentTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339Nano)
+}
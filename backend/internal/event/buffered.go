@@ -0,0 +1,59 @@
+package event
+
+import "sync"
+
+type bufferedEvent struct {
+	eventType EventType
+	data      any
+}
+
+// BufferedDispatcher keeps dispatched events in memory until they are flushed
+// to the underlying dispatcher, for instance once a transaction is committed.
+type BufferedDispatcher struct {
+	Dispatcher
+
+	mutex  sync.Mutex
+	events []*bufferedEvent
+}
+
+// NewBufferedDispatcher returns a dispatcher buffering events before sending them
+// to the given one. Subscriptions are directly made on the given dispatcher.
+func NewBufferedDispatcher(dispatcher Dispatcher) *BufferedDispatcher {
+	return &BufferedDispatcher{
+		Dispatcher: dispatcher,
+		events:     make([]*bufferedEvent, 0),
+	}
+}
+
+func (d *BufferedDispatcher) Dispatch(eventType EventType, data any) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.events = append(d.events, &bufferedEvent{eventType: eventType, data: data})
+
+	return nil
+}
+
+// Flush sends buffered events, in dispatch order, to the underlying dispatcher.
+func (d *BufferedDispatcher) Flush() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, event := range d.events {
+		if err := d.Dispatcher.Dispatch(event.eventType, event.data); err != nil {
+			return err
+		}
+	}
+
+	d.events = make([]*bufferedEvent, 0)
+
+	return nil
+}
+
+// Discard drops buffered events, for instance when a transaction is rolled back.
+func (d *BufferedDispatcher) Discard() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.events = make([]*bufferedEvent, 0)
+}
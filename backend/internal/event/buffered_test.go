@@ -0,0 +1,46 @@
+package event
+
+import (
+	"testing"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferedDispatcher_Flush(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	dispatcher := NewMockDispatcher(ctrl)
+
+	gomock.InOrder(
+		dispatcher.EXPECT().Dispatch(EventTypeResource, "resource-data").Return(nil),
+		dispatcher.EXPECT().Dispatch(EventTypePolicy, "policy-data").Return(nil),
+	)
+
+	bufferedDispatcher := NewBufferedDispatcher(dispatcher)
+
+	// When - Then
+	assert.Nil(t, bufferedDispatcher.Dispatch(EventTypeResource, "resource-data"))
+	assert.Nil(t, bufferedDispatcher.Dispatch(EventTypePolicy, "policy-data"))
+
+	assert.Nil(t, bufferedDispatcher.Flush())
+	assert.Len(t, bufferedDispatcher.events, 0)
+}
+
+func TestBufferedDispatcher_Discard(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	dispatcher := NewMockDispatcher(ctrl)
+
+	bufferedDispatcher := NewBufferedDispatcher(dispatcher)
+
+	// When
+	assert.Nil(t, bufferedDispatcher.Dispatch(EventTypeResource, "resource-data"))
+
+	bufferedDispatcher.Discard()
+
+	// Then
+	assert.Nil(t, bufferedDispatcher.Flush())
+}
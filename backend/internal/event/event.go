@@ -5,11 +5,15 @@ import "github.com/eko/authz/backend/internal/entity/model"
 type EventType string
 
 const (
-	EventTypeCheck     EventType = "check"
-	EventTypePolicy    EventType = "policy"
-	EventTypePrincipal EventType = "principal"
-	EventTypeResource  EventType = "resource"
-	EventTypeRole      EventType = "role"
+	EventTypeCedarPolicy    EventType = "cedar_policy"
+	EventTypeCheck          EventType = "check"
+	EventTypeDelegation     EventType = "delegation"
+	EventTypePolicy         EventType = "policy"
+	EventTypePrincipal      EventType = "principal"
+	EventTypeResource       EventType = "resource"
+	EventTypeResourceKind   EventType = "resource_kind"
+	EventTypeReviewCampaign EventType = "review_campaign"
+	EventTypeRole           EventType = "role"
 )
 
 type Event struct {
@@ -17,20 +21,39 @@ type Event struct {
 	Timestamp int64
 }
 
+// CacheStatus tells whether the decision of a check comes from the decision
+// cache. It is empty when the decision cache is not used.
+type CacheStatus string
+
+const (
+	CacheStatusHit  CacheStatus = "hit"
+	CacheStatusMiss CacheStatus = "miss"
+)
+
 type CheckEvent struct {
 	Principal      string
 	ResourceKind   string
 	ResourceValue  string
 	Action         string
 	IsAllowed      bool
+	Algorithm      string
 	CompiledPolicy *model.CompiledPolicy
+	CedarPolicy    *model.CedarPolicy
+	Delegation     *model.Delegation
+	CacheStatus    CacheStatus
 }
 
 type ItemAction string
 
 const (
-	ItemActionCreate ItemAction = "create"
-	ItemActionUpdate ItemAction = "update"
+	ItemActionCreate      ItemAction = "create"
+	ItemActionUpdate      ItemAction = "update"
+	ItemActionDelete      ItemAction = "delete"
+	ItemActionWindowOpen  ItemAction = "window_open"
+	ItemActionWindowClose ItemAction = "window_close"
+	ItemActionExpire      ItemAction = "expire"
+	ItemActionRemind      ItemAction = "remind"
+	ItemActionClose       ItemAction = "close"
 )
 
 type ItemEvent struct {
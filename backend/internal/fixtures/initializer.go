@@ -18,16 +18,23 @@ const (
 
 var (
 	resources = map[string][]string{
-		"actions":    {"list", "get"},
-		"audits":     {"get"},
-		"clients":    {"list", "get", "create", "delete"},
-		"compiled":   {"list"},
-		"policies":   {"list", "get", "create", "update", "delete"},
-		"principals": {"list", "get", "create", "update", "delete"},
-		"resources":  {"list", "get", "create", "update", "delete"},
-		"roles":      {"list", "get", "create", "update", "delete"},
-		"stats":      {"get"},
-		"users":      {"list", "get", "create", "delete"},
+		"actions":          {"list", "get"},
+		"audits":           {"get"},
+		"bundles":          {"get", "plan", "apply"},
+		"cedar-policies":   {"list", "get", "create", "update", "delete"},
+		"clients":          {"list", "get", "create", "delete"},
+		"compile-jobs":     {"list", "get", "retry"},
+		"compiled":         {"list", "rebuild", "sync"},
+		"delegations":      {"list", "get", "create", "delete"},
+		"lint":             {"get"},
+		"policies":         {"list", "get", "create", "update", "delete"},
+		"principals":       {"list", "get", "create", "update", "delete"},
+		"resource-kinds":   {"list", "get", "create", "update", "delete"},
+		"resources":        {"list", "get", "create", "update", "delete"},
+		"review-campaigns": {"list", "get", "create", "decide", "close", "delete"},
+		"roles":            {"list", "get", "create", "update", "delete"},
+		"stats":            {"get"},
+		"users":            {"list", "get", "create", "delete"},
 	}
 )
 
@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	"github.com/eko/authz/backend/internal/agent/snapshot"
 	"github.com/eko/authz/backend/internal/grpc/handler"
 	"go.uber.org/fx"
 )
@@ -8,6 +9,9 @@ import (
 func FxModule() fx.Option {
 	return fx.Module("grpc",
 		fx.Provide(
+			snapshot.NewLoader,
+
+			handler.NewAgent,
 			handler.NewAuth,
 			handler.NewCheck,
 			handler.NewPrincipal,
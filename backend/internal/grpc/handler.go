@@ -6,6 +6,10 @@ import (
 	"github.com/eko/authz/backend/pkg/authz"
 )
 
+func (s *Server) AgentSync(req *authz.AgentSyncRequest, stream authz.Api_AgentSyncServer) error {
+	return s.agentHandler.AgentSync(req, stream)
+}
+
 func (s *Server) Authenticate(ctx context.Context, req *authz.AuthenticateRequest) (*authz.AuthenticateResponse, error) {
 	return s.authHandler.Authenticate(ctx, req)
 }
@@ -14,6 +18,10 @@ func (s *Server) Check(ctx context.Context, req *authz.CheckRequest) (*authz.Che
 	return s.checkHandler.Check(ctx, req)
 }
 
+func (s *Server) CheckStream(stream authz.Api_CheckStreamServer) error {
+	return s.checkHandler.CheckStream(stream)
+}
+
 func (s *Server) PolicyCreate(ctx context.Context, req *authz.PolicyCreateRequest) (*authz.PolicyCreateResponse, error) {
 	return s.policyHandler.PolicyCreate(ctx, req)
 }
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/agent/snapshot"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/pkg/authz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Agent interface {
+	AgentSync(req *authz.AgentSyncRequest, stream authz.Api_AgentSyncServer) error
+	Stop()
+}
+
+// agentSyncMaxChanges is the maximum number of changes read at once from the
+// change log: agents are sent all their data again past it.
+const agentSyncMaxChanges = 1000
+
+type agent struct {
+	loader        snapshot.Loader
+	consistency   compile.Consistency
+	changeManager manager.Change
+	syncDelay     time.Duration
+	stopped       chan struct{}
+}
+
+func NewAgent(
+	cfg *configs.GRPCServer,
+	loader snapshot.Loader,
+	consistency compile.Consistency,
+	changeManager manager.Change,
+) Agent {
+	return &agent{
+		loader:        loader,
+		consistency:   consistency,
+		changeManager: changeManager,
+		syncDelay:     cfg.AgentSyncDelay,
+		stopped:       make(chan struct{}),
+	}
+}
+
+// Stop closes the sync streams, which never end by themselves, so the server
+// can stop gracefully. Agents then reconnect to another server.
+func (h *agent) Stop() {
+	close(h.stopped)
+}
+
+// agentSession is what has been sent to an agent.
+type agentSession struct {
+	resourceKinds []string
+	cursor        *manager.ChangeCursor
+	state         *snapshot.State
+	// revision is the compiled policies revision of the last consistency
+	// token sent.
+	revision int64
+}
+
+// AgentSync sends the decision data of the requested resource kinds, then the
+// changes made on it, read from the change log every sync delay, until the
+// agent disconnects.
+func (h *agent) AgentSync(req *authz.AgentSyncRequest, stream authz.Api_AgentSyncServer) error {
+	if len(req.GetResourceKinds()) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one resource kind is required")
+	}
+
+	var (
+		ctx     = stream.Context()
+		ticker  = time.NewTicker(h.syncDelay)
+		session = &agentSession{resourceKinds: req.GetResourceKinds(), revision: -1}
+	)
+
+	defer ticker.Stop()
+
+	response, err := h.snapshot(ctx, session)
+
+	for {
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		if response != nil {
+			if err := stream.Send(response); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-h.stopped:
+			return status.Error(codes.Unavailable, "server is stopping")
+		}
+
+		response, err = h.next(ctx, session)
+	}
+}
+
+// snapshot returns the response sending all the data of the session.
+func (h *agent) snapshot(ctx context.Context, session *agentSession) (*authz.AgentSyncResponse, error) {
+	token := h.token(ctx, session)
+
+	// Changes recorded while loading are sent once more afterwards.
+	cursor, err := h.changeManager.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := h.loader.Load(session.resourceKinds)
+	if err != nil {
+		return nil, err
+	}
+
+	session.cursor = cursor
+	session.state = snapshot.NewState(data)
+
+	return &authz.AgentSyncResponse{
+		Snapshot:         true,
+		Upserted:         data,
+		Deleted:          &authz.AgentData{},
+		ConsistencyToken: token,
+	}, nil
+}
+
+// next returns the response sending the changes recorded since the previous
+// one, if any.
+func (h *agent) next(ctx context.Context, session *agentSession) (*authz.AgentSyncResponse, error) {
+	token := h.token(ctx, session)
+
+	records, err := h.changeManager.Next(session.cursor, agentSyncMaxChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := snapshot.NewChanges(records)
+
+	if len(records) == agentSyncMaxChanges {
+		// Changes left are taken into account by loading everything again.
+		if session.cursor, err = h.changeManager.Cursor(); err != nil {
+			return nil, err
+		}
+
+		changes.All = true
+	}
+
+	var upserted, deleted = &authz.AgentData{}, &authz.AgentData{}
+
+	if !changes.IsEmpty() {
+		upserted, deleted, err = h.loader.LoadChanges(session.resourceKinds, changes, session.state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if snapshot.IsEmpty(upserted) && snapshot.IsEmpty(deleted) && token == "" {
+		return nil, nil
+	}
+
+	return &authz.AgentSyncResponse{
+		Upserted:         upserted,
+		Deleted:          deleted,
+		ConsistencyToken: token,
+	}, nil
+}
+
+// token returns a consistency token once every change compiled since the
+// previous token is, so agents can wait for changes made on the server.
+// Changes are read afterwards, so they are sent along with the token.
+// Failures of previous changes do not prevent agents from synchronizing.
+func (h *agent) token(ctx context.Context, session *agentSession) string {
+	revision, err := h.consistency.Revision()
+	if err != nil || revision <= session.revision {
+		return ""
+	}
+
+	token := compile.FormatConsistencyToken(revision, revision)
+
+	if h.consistency.Wait(ctx, token) != nil {
+		return ""
+	}
+
+	session.revision = revision
+
+	return token
+}
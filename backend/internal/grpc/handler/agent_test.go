@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/agent/snapshot"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// agentSyncStream is an in-memory agent sync stream: responses are sent to
+// the responses channel.
+type agentSyncStream struct {
+	grpc.ServerStream
+
+	ctx       context.Context
+	responses chan *authz.AgentSyncResponse
+}
+
+func (s *agentSyncStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *agentSyncStream) Send(response *authz.AgentSyncResponse) error {
+	s.responses <- response
+	return nil
+}
+
+func newAgentHandler(ctrl *gomock.Controller) (*agent, *snapshot.MockLoader, *compile.MockConsistency, *manager.MockChange) {
+	loader := snapshot.NewMockLoader(ctrl)
+	consistency := compile.NewMockConsistency(ctrl)
+	changeManager := manager.NewMockChange(ctrl)
+
+	handler := NewAgent(
+		&configs.GRPCServer{AgentSyncDelay: time.Millisecond},
+		loader,
+		consistency,
+		changeManager,
+	).(*agent)
+
+	return handler, loader, consistency, changeManager
+}
+
+func TestAgentSync(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	handler, loader, consistency, changeManager := newAgentHandler(ctrl)
+
+	data := &authz.AgentData{
+		Principals: []*authz.Principal{{Id: "alice"}, {Id: "bob"}},
+	}
+	upserted := &authz.AgentData{
+		Principals: []*authz.Principal{{Id: "alice", Roles: []string{"readers"}}},
+	}
+	deleted := &authz.AgentData{
+		Principals: []*authz.Principal{{Id: "bob"}},
+	}
+
+	gomock.InOrder(
+		consistency.EXPECT().Revision().Return(int64(7), nil),
+		consistency.EXPECT().Revision().Return(int64(8), nil).AnyTimes(),
+	)
+	consistency.EXPECT().Wait(gomock.Any(), compile.FormatConsistencyToken(7, 7)).Return(nil)
+	consistency.EXPECT().Wait(gomock.Any(), compile.FormatConsistencyToken(8, 8)).Return(nil)
+
+	cursor := &manager.ChangeCursor{}
+
+	changeManager.EXPECT().Cursor().Return(cursor, nil)
+	gomock.InOrder(
+		changeManager.EXPECT().Next(cursor, agentSyncMaxChanges).Return([]*model.Change{
+			{ItemType: "principal", ItemID: "alice"},
+			{ItemType: "principal", ItemID: "bob"},
+		}, nil),
+		changeManager.EXPECT().Next(cursor, agentSyncMaxChanges).Return(nil, nil).AnyTimes(),
+	)
+
+	loader.EXPECT().Load([]string{"post"}).Return(data, nil)
+	loader.EXPECT().LoadChanges([]string{"post"}, &snapshot.Changes{
+		Policies:   map[string]bool{},
+		Principals: map[string]bool{"alice": true, "bob": true},
+		Resources:  map[string]bool{},
+	}, gomock.Any()).Return(upserted, deleted, nil)
+
+	stream := &agentSyncStream{
+		ctx:       context.Background(),
+		responses: make(chan *authz.AgentSyncResponse),
+	}
+
+	done := make(chan error)
+
+	// When
+	go func() {
+		done <- handler.AgentSync(&authz.AgentSyncRequest{ResourceKinds: []string{"post"}}, stream)
+	}()
+
+	snapshotResponse := <-stream.responses
+	changesResponse := <-stream.responses
+
+	// Nothing is sent while nothing changes.
+	var unchangedResponse *authz.AgentSyncResponse
+
+	select {
+	case unchangedResponse = <-stream.responses:
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	handler.Stop()
+
+	err := <-done
+
+	// Then
+	assert := assert.New(t)
+
+	assert.True(snapshotResponse.Snapshot)
+	assert.Equal(data, snapshotResponse.Upserted)
+	assert.Equal(compile.FormatConsistencyToken(7, 7), snapshotResponse.ConsistencyToken)
+
+	assert.False(changesResponse.Snapshot)
+	assert.Equal(upserted, changesResponse.Upserted)
+	assert.Equal(deleted, changesResponse.Deleted)
+	assert.Equal(compile.FormatConsistencyToken(8, 8), changesResponse.ConsistencyToken)
+
+	assert.Nil(unchangedResponse)
+
+	assert.Equal(codes.Unavailable, status.Code(err))
+}
+
+func TestAgentSync_WhenNoResourceKinds(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	handler, _, _, _ := newAgentHandler(ctrl)
+
+	// When
+	err := handler.AgentSync(&authz.AgentSyncRequest{}, &agentSyncStream{ctx: context.Background()})
+
+	// Then
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAgentSync_WhenChangesAreNotCompiled(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	handler, loader, consistency, changeManager := newAgentHandler(ctrl)
+
+	data := &authz.AgentData{}
+
+	consistency.EXPECT().Revision().Return(int64(7), nil)
+	consistency.EXPECT().Wait(gomock.Any(), compile.FormatConsistencyToken(7, 7)).Return(errors.New("compilation pending"))
+
+	changeManager.EXPECT().Cursor().Return(&manager.ChangeCursor{}, nil)
+
+	loader.EXPECT().Load([]string{"post"}).Return(data, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream := &agentSyncStream{
+		ctx:       ctx,
+		responses: make(chan *authz.AgentSyncResponse, 1),
+	}
+
+	// When
+	cancel()
+	err := handler.AgentSync(&authz.AgentSyncRequest{ResourceKinds: []string{"post"}}, stream)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Equal(codes.Canceled, status.Code(err))
+
+	response := <-stream.responses
+	assert.True(response.Snapshot)
+	assert.Equal("", response.ConsistencyToken)
+}
+
+func TestAgent_Next_WhenTooManyChanges(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	handler, loader, consistency, changeManager := newAgentHandler(ctrl)
+
+	var (
+		cursor  = &manager.ChangeCursor{}
+		fresh   = &manager.ChangeCursor{}
+		records = make([]*model.Change, agentSyncMaxChanges)
+	)
+
+	for i := range records {
+		records[i] = &model.Change{ItemType: "resource", ItemID: fmt.Sprintf("post.%d", i)}
+	}
+
+	consistency.EXPECT().Revision().Return(int64(7), nil)
+
+	changeManager.EXPECT().Next(cursor, agentSyncMaxChanges).Return(records, nil)
+	changeManager.EXPECT().Cursor().Return(fresh, nil)
+
+	upserted := &authz.AgentData{Resources: []*authz.Resource{{Id: "post.1"}}}
+
+	loader.EXPECT().LoadChanges([]string{"post"}, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ []string, changes *snapshot.Changes, _ *snapshot.State) (*authz.AgentData, *authz.AgentData, error) {
+			assert.True(t, changes.All)
+			return upserted, &authz.AgentData{}, nil
+		})
+
+	session := &agentSession{resourceKinds: []string{"post"}, cursor: cursor, revision: 7}
+
+	// When
+	response, err := handler.next(context.Background(), session)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(upserted, response.Upserted)
+	assert.Equal("", response.ConsistencyToken)
+	assert.Same(fresh, session.cursor)
+}
@@ -2,7 +2,12 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"io"
+	"sync"
 
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/pkg/authz"
@@ -13,41 +18,73 @@ import (
 
 type Check interface {
 	Check(ctx context.Context, req *authz.CheckRequest) (*authz.CheckResponse, error)
+	CheckStream(stream authz.Api_CheckStreamServer) error
 }
 
 type check struct {
-	compiledManager manager.CompiledPolicy
-	logger          *slog.Logger
-	dispatcher      event.Dispatcher
+	compiledManager   manager.CompiledPolicy
+	consistency       compile.Consistency
+	logger            *slog.Logger
+	dispatcher        event.Dispatcher
+	streamConcurrency int
 }
 
 func NewCheck(
+	cfg *configs.GRPCServer,
 	compiledManager manager.CompiledPolicy,
+	consistency compile.Consistency,
 	logger *slog.Logger,
 	dispatcher event.Dispatcher,
 ) Check {
+	streamConcurrency := cfg.CheckStreamConcurrency
+	if streamConcurrency < 1 {
+		streamConcurrency = 1
+	}
+
 	return &check{
-		compiledManager: compiledManager,
-		logger:          logger,
-		dispatcher:      dispatcher,
+		compiledManager:   compiledManager,
+		consistency:       consistency,
+		logger:            logger,
+		dispatcher:        dispatcher,
+		streamConcurrency: streamConcurrency,
 	}
 }
 
 func (h *check) Check(ctx context.Context, req *authz.CheckRequest) (*authz.CheckResponse, error) {
-	var checkAnswers = make([]*authz.CheckAnswer, len(req.GetChecks()))
+	if token := req.GetConsistencyToken(); token != "" {
+		if err := h.consistency.Wait(ctx, token); err != nil {
+			return nil, status.Error(consistencyErrorCode(err), err.Error())
+		}
+	}
+
+	var checks = make([]*manager.Check, len(req.GetChecks()))
 
 	for i, check := range req.GetChecks() {
-		isAllowed, err := h.compiledManager.IsAllowed(check.Principal, check.ResourceKind, check.ResourceValue, check.Action)
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
+		checks[i] = &manager.Check{
+			PrincipalID:   check.Principal,
+			ResourceKind:  check.ResourceKind,
+			ResourceValue: check.ResourceValue,
+			ActionID:      check.Action,
+			Context:       contextMap(check.GetContext()),
 		}
+	}
+
+	results, err := h.compiledManager.IsAllowedBulk(checks)
+	if errors.Is(err, manager.ErrUnknownAction) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var checkAnswers = make([]*authz.CheckAnswer, len(req.GetChecks()))
 
+	for i, check := range req.GetChecks() {
 		checkAnswers[i] = &authz.CheckAnswer{
 			Principal:     check.Principal,
 			ResourceKind:  check.ResourceKind,
 			ResourceValue: check.ResourceValue,
 			Action:        check.Action,
-			IsAllowed:     isAllowed,
+			IsAllowed:     results[i],
 		}
 	}
 
@@ -55,3 +92,99 @@ func (h *check) Check(ctx context.Context, req *authz.CheckRequest) (*authz.Chec
 		Checks: checkAnswers,
 	}, nil
 }
+
+// CheckStream answers the check requests received on the stream, each answer
+// having the identifier of its request. Requests are evaluated concurrently so
+// answers may be sent in a different order than requests.
+// Flow control: next requests are not read from the stream while the maximum
+// number of requests are being evaluated, so clients are slowed down by gRPC
+// flow control rather than the server buffering requests.
+func (h *check) CheckStream(stream authz.Api_CheckStreamServer) error {
+	var (
+		ctx       = stream.Context()
+		slots     = make(chan struct{}, h.streamConcurrency)
+		waitGroup sync.WaitGroup
+		sendMutex sync.Mutex
+		sendErr   error
+	)
+
+	// Answers can't be sent anymore once this method has returned.
+	defer waitGroup.Wait()
+
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			waitGroup.Wait()
+			return sendErr
+		} else if err != nil {
+			return err
+		}
+
+		waitGroup.Add(1)
+
+		go func() {
+			defer func() {
+				<-slots
+				waitGroup.Done()
+			}()
+
+			answer := h.answer(ctx, req)
+
+			sendMutex.Lock()
+			defer sendMutex.Unlock()
+
+			if sendErr != nil {
+				return
+			}
+
+			if err := stream.Send(answer); err != nil {
+				h.logger.Warn("unable to send check stream answer", err, slog.String("request_id", req.GetRequestId()))
+				sendErr = err
+			}
+		}()
+	}
+}
+
+// answer returns the answer of a check stream request. Errors are given back
+// in the answer as the stream remains open for next requests.
+func (h *check) answer(ctx context.Context, req *authz.CheckStreamRequest) *authz.CheckStreamResponse {
+	response, err := h.Check(ctx, &authz.CheckRequest{
+		Checks:           req.GetChecks(),
+		ConsistencyToken: req.GetConsistencyToken(),
+	})
+	if err != nil {
+		errorStatus := status.Convert(err)
+
+		return &authz.CheckStreamResponse{
+			RequestId: req.GetRequestId(),
+			Error: &authz.CheckStreamError{
+				Code:    uint32(errorStatus.Code()),
+				Message: errorStatus.Message(),
+			},
+		}
+	}
+
+	return &authz.CheckStreamResponse{
+		RequestId: req.GetRequestId(),
+		Checks:    response.GetChecks(),
+	}
+}
+
+func consistencyErrorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, compile.ErrInvalidConsistencyToken):
+		return codes.InvalidArgument
+	case errors.Is(err, compile.ErrCompilationFailed):
+		return codes.FailedPrecondition
+	case errors.Is(err, compile.ErrCompilationPending):
+		return codes.Unavailable
+	}
+
+	return codes.Internal
+}
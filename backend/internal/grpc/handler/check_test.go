@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// checkStream is an in-memory check stream: requests are received from the
+// requests channel and answers are kept in order.
+type checkStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	requests chan *authz.CheckStreamRequest
+
+	mutex   sync.Mutex
+	answers []*authz.CheckStreamResponse
+}
+
+func newCheckStream() *checkStream {
+	return &checkStream{
+		ctx:      context.Background(),
+		requests: make(chan *authz.CheckStreamRequest),
+	}
+}
+
+func (s *checkStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *checkStream) Recv() (*authz.CheckStreamRequest, error) {
+	req, ok := <-s.requests
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return req, nil
+}
+
+func (s *checkStream) Send(answer *authz.CheckStreamResponse) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.answers = append(s.answers, answer)
+
+	return nil
+}
+
+func (s *checkStream) answerByRequestID() map[string]*authz.CheckStreamResponse {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result = map[string]*authz.CheckStreamResponse{}
+	for _, answer := range s.answers {
+		result[answer.GetRequestId()] = answer
+	}
+
+	return result
+}
+
+func newStreamCheckRequest(requestID string, action string) *authz.CheckStreamRequest {
+	return &authz.CheckStreamRequest{
+		RequestId: requestID,
+		Checks: []*authz.Check{
+			{Principal: "user-1", ResourceKind: "post", ResourceValue: "1", Action: action},
+		},
+	}
+}
+
+func newCheckHandler(ctrl *gomock.Controller, compiledManager manager.CompiledPolicy, concurrency int) Check {
+	return NewCheck(
+		&configs.GRPCServer{CheckStreamConcurrency: concurrency},
+		compiledManager,
+		compile.NewMockConsistency(ctrl),
+		slog.New(log.NewNopHandler()),
+		event.NewMockDispatcher(ctrl),
+	)
+}
+
+func TestCheck_CheckStream(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	compiledManager := manager.NewMockCompiledPolicy(ctrl)
+	compiledManager.EXPECT().IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "user-1", ResourceKind: "post", ResourceValue: "1", ActionID: "read", Context: map[string]string{}},
+	}).Return([]bool{true}, nil)
+	compiledManager.EXPECT().IsAllowedBulk([]*manager.Check{
+		{PrincipalID: "user-1", ResourceKind: "post", ResourceValue: "1", ActionID: "unknown", Context: map[string]string{}},
+	}).Return(nil, fmt.Errorf("%w \"unknown\"", manager.ErrUnknownAction))
+
+	handler := newCheckHandler(ctrl, compiledManager, 4)
+
+	stream := newCheckStream()
+
+	go func() {
+		stream.requests <- newStreamCheckRequest("request-1", "read")
+		stream.requests <- newStreamCheckRequest("request-2", "unknown")
+		close(stream.requests)
+	}()
+
+	// When
+	err := handler.CheckStream(stream)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+
+	answers := stream.answerByRequestID()
+	assert.Len(answers, 2)
+
+	assert.Nil(answers["request-1"].GetError())
+	assert.Len(answers["request-1"].GetChecks(), 1)
+	assert.True(answers["request-1"].GetChecks()[0].GetIsAllowed())
+
+	assert.Empty(answers["request-2"].GetChecks())
+	assert.Equal(uint32(codes.InvalidArgument), answers["request-2"].GetError().GetCode())
+	assert.Equal(`unknown action "unknown"`, answers["request-2"].GetError().GetMessage())
+}
+
+func TestCheck_CheckStream_WhenConcurrencyIsReached(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	release := make(chan struct{})
+
+	compiledManager := manager.NewMockCompiledPolicy(ctrl)
+	compiledManager.EXPECT().IsAllowedBulk(gomock.Any()).DoAndReturn(func([]*manager.Check) ([]bool, error) {
+		<-release
+		return []bool{true}, nil
+	}).Times(3)
+
+	handler := newCheckHandler(ctrl, compiledManager, 2)
+
+	stream := newCheckStream()
+
+	done := make(chan error)
+	go func() {
+		done <- handler.CheckStream(stream)
+	}()
+
+	// When
+	stream.requests <- newStreamCheckRequest("request-1", "read")
+	stream.requests <- newStreamCheckRequest("request-2", "read")
+
+	// Then
+	assert := assert.New(t)
+
+	// The third request is not read while the two first ones are evaluated.
+	select {
+	case stream.requests <- newStreamCheckRequest("request-3", "read"):
+		assert.Fail("third request has been read while concurrency was reached")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	stream.requests <- newStreamCheckRequest("request-3", "read")
+	close(stream.requests)
+
+	assert.Nil(<-done)
+	assert.Len(stream.answerByRequestID(), 3)
+}
@@ -11,3 +11,13 @@ func attributesMap(attributes []*authz.Attribute) map[string]any {
 
 	return result
 }
+
+func contextMap(attributes []*authz.Attribute) map[string]string {
+	var result = map[string]string{}
+
+	for _, attribute := range attributes {
+		result[attribute.GetKey()] = attribute.GetValue()
+	}
+
+	return result
+}
@@ -14,6 +14,8 @@ var (
 	// ResourcesAndActionsByMethod maps the resource kind and action for each
 	// gRPC method available in the proto API.
 	ResourcesAndActionsByMethod = map[string][]string{
+		"/authz.Api/AgentSync": {"authz.compiled", "sync"},
+
 		"/authz.Api/PolicyCreate": {"authz.policies", "create"},
 		"/authz.Api/PolicyDelete": {"authz.policies", "delete"},
 		"/authz.Api/PolicyGet":    {"authz.policies", "get"},
@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/eko/authz/backend/internal/compile"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const ConsistencyTokenHeader = "x-authz-consistency-token"
+
+// ConsistencyTokenUnaryServerInterceptor returns a consistency token in the
+// response headers of successful write method calls, covering the changes
+// made since the call has been received.
+func ConsistencyTokenUnaryServerInterceptor(consistency compile.Consistency) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if resourceAction, ok := ResourcesAndActionsByMethod[info.FullMethod]; !ok || resourceAction[1] == "get" {
+			return handler(ctx, req)
+		}
+
+		since, err := consistency.Revision()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		until, err := consistency.Revision()
+		if err != nil {
+			return nil, err
+		}
+
+		token := compile.FormatConsistencyToken(since, until)
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(ConsistencyTokenHeader, token)); err != nil {
+			return nil, err
+		}
+
+		return resp, nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 
 	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/grpc/handler"
 	"github.com/eko/authz/backend/internal/grpc/interceptor"
@@ -21,6 +22,7 @@ import (
 type Server struct {
 	authz.UnimplementedApiServer
 
+	agentHandler     handler.Agent
 	authHandler      handler.Auth
 	checkHandler     handler.Check
 	policyHandler    handler.Policy
@@ -36,6 +38,8 @@ func NewServer(
 	cfg *configs.GRPCServer,
 	tokenManager jwt.Manager,
 	compiledManager manager.CompiledPolicy,
+	consistency compile.Consistency,
+	agentHandler handler.Agent,
 	authHandler handler.Auth,
 	checkHandler handler.Check,
 	policyHandler handler.Policy,
@@ -45,6 +49,7 @@ func NewServer(
 ) *Server {
 	server := &Server{
 		addr:             cfg.Addr,
+		agentHandler:     agentHandler,
 		authHandler:      authHandler,
 		checkHandler:     checkHandler,
 		policyHandler:    policyHandler,
@@ -68,6 +73,7 @@ func NewServer(
 				grpc_auth.UnaryServerInterceptor(authenticateFunc),
 			),
 			interceptor.AuthorizationUnaryServerInterceptor(authorizationFunc),
+			interceptor.ConsistencyTokenUnaryServerInterceptor(consistency),
 		),
 	)
 
@@ -99,6 +105,7 @@ func Run(lc fx.Lifecycle, logger *slog.Logger, server *Server) error {
 		OnStop: func(ctx context.Context) error {
 			logger.Info("Stopping gRPC server")
 
+			server.agentHandler.Stop()
 			server.GrpcServer.GracefulStop()
 			return nil
 		},
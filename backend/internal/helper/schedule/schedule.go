@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// Default time zone used to evaluate a schedule when none is given.
+	defaultTimezone = "UTC"
+)
+
+var (
+	// ErrInvalidDuration is returned when a schedule has no positive duration.
+	ErrInvalidDuration = errors.New("schedule duration must be a positive duration (for instance: 8h)")
+
+	parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+)
+
+// Schedule is a recurring time window: it opens each time the cron expression
+// matches and stays open for the given duration.
+type Schedule struct {
+	spec     cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// Parse parses a standard 5-fields cron expression (for instance: "0 9 * * MON-FRI"),
+// a window duration (for instance: "8h") and an optional IANA time zone.
+func Parse(expression string, duration string, timezone string) (*Schedule, error) {
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule time zone %q: %v", timezone, err)
+	}
+
+	spec, err := parser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule expression %q: %v", expression, err)
+	}
+
+	windowDuration, err := time.ParseDuration(duration)
+	if err != nil || windowDuration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	return &Schedule{
+		spec:     spec,
+		duration: windowDuration,
+		location: location,
+	}, nil
+}
+
+// IsActive returns whether the given time is inside one of the schedule windows.
+func (s *Schedule) IsActive(t time.Time) bool {
+	// The window is open if the last activation happened less than duration ago.
+	// As Next() returns the first activation strictly after the given time,
+	// we look for an activation in the (t - duration, t] range.
+	next := s.spec.Next(t.In(s.location).Add(-s.duration))
+
+	return !next.After(t)
+}
+
+// Boundaries returns, in order, the times in the (from, to] range at which a
+// window of the schedule opens or closes, so windows shorter than the range
+// are not missed. Overlapping windows may return times at which the schedule
+// stays active.
+func (s *Schedule) Boundaries(from time.Time, to time.Time) []time.Time {
+	var boundaries = []time.Time{}
+
+	// Windows still open at "from" have been activated less than duration before.
+	activation := s.spec.Next(from.In(s.location).Add(-s.duration))
+
+	// Next() returns a zero time when the expression never matches.
+	for !activation.IsZero() && !activation.After(to) {
+		if activation.After(from) {
+			boundaries = append(boundaries, activation)
+		}
+
+		if end := activation.Add(s.duration); end.After(from) && !end.After(to) {
+			boundaries = append(boundaries, end)
+		}
+
+		activation = s.spec.Next(activation)
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	return boundaries
+}
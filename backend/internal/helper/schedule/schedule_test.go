@@ -0,0 +1,103 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		duration   string
+		timezone   string
+		hasError   bool
+	}{
+		{name: "valid", expression: "0 9 * * MON-FRI", duration: "8h", timezone: "Europe/Paris"},
+		{name: "valid without time zone", expression: "@daily", duration: "1h"},
+		{name: "invalid expression", expression: "not a cron", duration: "1h", hasError: true},
+		{name: "invalid duration", expression: "@daily", duration: "tomorrow", hasError: true},
+		{name: "negative duration", expression: "@daily", duration: "-1h", hasError: true},
+		{name: "invalid time zone", expression: "@daily", duration: "1h", timezone: "Mars/Olympus", hasError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			schedule, err := Parse(testCase.expression, testCase.duration, testCase.timezone)
+
+			// Then
+			if testCase.hasError {
+				assert.Error(t, err)
+				assert.Nil(t, schedule)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, schedule)
+			}
+		})
+	}
+}
+
+func TestSchedule_IsActive(t *testing.T) {
+	// Given
+	schedule, err := Parse("0 9 * * MON-FRI", "8h", "Europe/Paris")
+	assert.NoError(t, err)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	// When - Then
+	assert := assert.New(t)
+
+	// Monday 2023-01-16
+	assert.False(schedule.IsActive(time.Date(2023, 1, 16, 8, 59, 0, 0, paris)))
+	assert.True(schedule.IsActive(time.Date(2023, 1, 16, 9, 0, 0, 0, paris)))
+	assert.True(schedule.IsActive(time.Date(2023, 1, 16, 16, 59, 0, 0, paris)))
+	assert.False(schedule.IsActive(time.Date(2023, 1, 16, 17, 0, 0, 0, paris)))
+
+	// Same instant expressed in UTC (Paris is UTC+1 in winter)
+	assert.True(schedule.IsActive(time.Date(2023, 1, 16, 8, 30, 0, 0, time.UTC)))
+	assert.False(schedule.IsActive(time.Date(2023, 1, 16, 7, 30, 0, 0, time.UTC)))
+
+	// Saturday 2023-01-21
+	assert.False(schedule.IsActive(time.Date(2023, 1, 21, 10, 0, 0, 0, paris)))
+}
+
+func TestSchedule_Boundaries(t *testing.T) {
+	// Given
+	schedule, err := Parse("*/10 * * * *", "30s", "Europe/Paris")
+	assert.NoError(t, err)
+
+	paris, _ := time.LoadLocation("Europe/Paris")
+
+	from := time.Date(2023, 1, 16, 9, 0, 10, 0, paris)
+	to := time.Date(2023, 1, 16, 9, 10, 10, 0, paris)
+
+	// When
+	boundaries := schedule.Boundaries(from, to)
+
+	// Then
+	assert.Equal(t, []time.Time{
+		time.Date(2023, 1, 16, 9, 0, 30, 0, paris),
+		time.Date(2023, 1, 16, 9, 10, 0, 0, paris),
+	}, boundaries)
+}
+
+func TestSchedule_Boundaries_WhenWindowIsShorterThanRange(t *testing.T) {
+	// Given
+	schedule, err := Parse("0 9 * * *", "10s", "")
+	assert.NoError(t, err)
+
+	from := time.Date(2023, 1, 16, 8, 59, 30, 0, time.UTC)
+	to := time.Date(2023, 1, 16, 9, 0, 30, 0, time.UTC)
+
+	// When
+	boundaries := schedule.Boundaries(from, to)
+
+	// Then
+	assert.Equal(t, []time.Time{
+		time.Date(2023, 1, 16, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 16, 9, 0, 10, 0, time.UTC),
+	}, boundaries)
+}
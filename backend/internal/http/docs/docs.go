@@ -16,7 +16,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/actions": {
+        "/scim/v2/Groups": {
             "get": {
                 "security": [
                     {
@@ -27,38 +27,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Action"
+                    "SCIM"
                 ],
-                "summary": "Lists actions",
+                "summary": "Lists groups (roles), optionally filtered",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "page number",
-                        "name": "page",
+                        "type": "string",
+                        "example": "displayName eq \"developer\"",
+                        "description": "SCIM filter",
+                        "name": "filter",
                         "in": "query"
                     },
                     {
-                        "maximum": 1000,
-                        "minimum": 1,
                         "type": "integer",
-                        "default": 100,
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "name:contains:something",
-                        "description": "filter on a field",
-                        "name": "filter",
+                        "example": 1,
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "example": "name:desc",
-                        "description": "sort field and order",
-                        "name": "sort",
+                        "maximum": 1000,
+                        "type": "integer",
+                        "description": "maximum number of results",
+                        "name": "count",
                         "in": "query"
                     }
                 ],
@@ -66,29 +57,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Action"
-                            }
+                            "$ref": "#/definitions/scim.ListResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
-            }
-        },
-        "/v1/actions/{identifier}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "Authentication": []
@@ -98,32 +78,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Action"
+                    "SCIM"
                 ],
-                "summary": "Retrieve an action",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Provisions the members of an existing role",
+                "parameters": [
+                    {
+                        "description": "SCIM group",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Action"
+                            "$ref": "#/definitions/scim.Group"
                         }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Group"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/audits": {
+        "/scim/v2/Groups/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -134,68 +119,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Check"
-                ],
-                "summary": "Retrieve audits for last days",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 1000,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 100,
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "kind:contains:something",
-                        "description": "filter on a field",
-                        "name": "filter",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "kind:desc",
-                        "description": "sort field and order",
-                        "name": "sort",
-                        "in": "query"
-                    }
+                    "SCIM"
                 ],
+                "summary": "Retrieves a group (role) and its user members",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Audit"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Group"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
-            }
-        },
-        "/v1/auth": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Authentication": []
@@ -205,17 +147,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "SCIM"
                 ],
-                "summary": "Authenticates a user",
+                "summary": "Replaces the members of a group (role)",
                 "parameters": [
                     {
-                        "description": "Authentication request",
+                        "description": "SCIM group",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.AuthRequest"
+                            "$ref": "#/definitions/scim.Group"
                         }
                     }
                 ],
@@ -223,26 +165,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AuthResponse"
+                            "$ref": "#/definitions/scim.Group"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
-            }
-        },
-        "/v1/check": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Authentication": []
@@ -252,17 +192,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Check"
+                    "SCIM"
                 ],
-                "summary": "Check if a principal has access to do action on resource",
+                "summary": "Removes all members of a group, the role itself is kept",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/scim.Error"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "Adds or removes members of a group (role)",
                 "parameters": [
                     {
-                        "description": "Check request",
+                        "description": "SCIM patch request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CheckRequest"
+                            "$ref": "#/definitions/scim.PatchRequest"
                         }
                     }
                 ],
@@ -270,25 +235,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.CheckResponse"
+                            "$ref": "#/definitions/scim.Group"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/clients": {
+        "/scim/v2/ServiceProviderConfig": {
             "get": {
                 "security": [
                     {
@@ -299,38 +264,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "SCIM"
                 ],
-                "summary": "Lists clients",
+                "summary": "Retrieves the SCIM service provider configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/scim.ServiceProviderConfig"
+                        }
+                    }
+                }
+            }
+        },
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SCIM"
+                ],
+                "summary": "Lists users, optionally filtered",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "page number",
-                        "name": "page",
+                        "type": "string",
+                        "example": "userName eq \"john.doe@acme.tld\"",
+                        "description": "SCIM filter",
+                        "name": "filter",
                         "in": "query"
                     },
                     {
-                        "maximum": 1000,
-                        "minimum": 1,
                         "type": "integer",
-                        "default": 100,
-                        "description": "page size",
-                        "name": "size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "name:contains:something",
-                        "description": "filter on a field",
-                        "name": "filter",
+                        "example": 1,
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "example": "name:desc",
-                        "description": "sort field and order",
-                        "name": "sort",
+                        "maximum": 1000,
+                        "type": "integer",
+                        "description": "maximum number of results",
+                        "name": "count",
                         "in": "query"
                     }
                 ],
@@ -338,22 +318,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Client"
-                            }
+                            "$ref": "#/definitions/scim.ListResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
@@ -368,43 +339,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "SCIM"
                 ],
-                "summary": "Creates a new client",
+                "summary": "Provisions a new user and its principal",
                 "parameters": [
                     {
-                        "description": "Client creation request",
+                        "description": "SCIM user",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ClientCreateRequest"
+                            "$ref": "#/definitions/scim.User"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Client"
+                            "$ref": "#/definitions/scim.User"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/clients/{identifier}": {
+        "/scim/v2/Users/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -415,31 +386,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "SCIM"
                 ],
-                "summary": "Retrieve a client",
+                "summary": "Retrieves a user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Client"
+                            "$ref": "#/definitions/scim.User"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Authentication": []
@@ -449,89 +414,113 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "SCIM"
+                ],
+                "summary": "Replaces a user, deactivated users lose their roles",
+                "parameters": [
+                    {
+                        "description": "SCIM user",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.User"
+                        }
+                    }
                 ],
-                "summary": "Deletes a client",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Client"
+                            "$ref": "#/definitions/scim.User"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
-            }
-        },
-        "/v1/oauth": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Authentication": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Auth"
+                    "SCIM"
                 ],
-                "summary": "Authenticates a user using an OAuth OpenID Connect provider",
+                "summary": "Deletes a user and its principal",
                 "responses": {
-                    "302": {
-                        "description": "Found"
+                    "204": {
+                        "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
-            }
-        },
-        "/v1/oauth/callback": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "Authentication": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Auth"
+                    "SCIM"
+                ],
+                "summary": "Patches a user, deactivated users lose their roles",
+                "parameters": [
+                    {
+                        "description": "SCIM patch request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/scim.PatchRequest"
+                        }
+                    }
                 ],
-                "summary": "Callback of the OAuth OpenID Connect provider authentication",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AuthResponse"
+                            "$ref": "#/definitions/scim.User"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/scim.Error"
                         }
                     }
                 }
             }
         },
-        "/v1/policies": {
+        "/v1/actions": {
             "get": {
                 "security": [
                     {
@@ -542,9 +531,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
+                    "Action"
                 ],
-                "summary": "Lists policies",
+                "summary": "Lists actions",
                 "parameters": [
                     {
                         "type": "integer",
@@ -564,14 +553,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "kind:contains:something",
+                        "example": "name:contains:something",
                         "description": "filter on a field",
                         "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "kind:desc",
+                        "example": "name:desc",
                         "description": "sort field and order",
                         "name": "sort",
                         "in": "query"
@@ -583,7 +572,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Policy"
+                                "$ref": "#/definitions/model.Action"
                             }
                         }
                     },
@@ -600,8 +589,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/actions/{identifier}": {
+            "get": {
                 "security": [
                     {
                         "Authentication": []
@@ -611,29 +602,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
-                ],
-                "summary": "Creates a new policy",
-                "parameters": [
-                    {
-                        "description": "Policy creation request",
-                        "name": "default",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreatePolicyRequest"
-                        }
-                    }
+                    "Action"
                 ],
+                "summary": "Retrieve an action",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Policy"
+                            "$ref": "#/definitions/model.Action"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -647,7 +627,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/policies/{identifier}": {
+        "/v1/audits": {
             "get": {
                 "security": [
                     {
@@ -658,18 +638,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
+                    "Check"
+                ],
+                "summary": "Retrieve audits for last days",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Retrieve a policy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Policy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Audit"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -681,8 +696,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/auth": {
+            "post": {
                 "security": [
                     {
                         "Authentication": []
@@ -692,17 +709,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
+                    "Auth"
                 ],
-                "summary": "Updates a policy",
+                "summary": "Authenticates a user",
                 "parameters": [
                     {
-                        "description": "Policy update request",
+                        "description": "Authentication request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdatePolicyRequest"
+                            "$ref": "#/definitions/handler.AuthRequest"
                         }
                     }
                 ],
@@ -710,7 +727,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Policy"
+                            "$ref": "#/definitions/handler.AuthResponse"
                         }
                     },
                     "400": {
@@ -726,25 +743,98 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/bundle": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "tags": [
+                    "Bundle"
+                ],
+                "summary": "Exports the current state as a bundle",
+                "parameters": [
+                    {
+                        "enum": [
+                            "yaml",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "yaml",
+                        "description": "bundle format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Bundle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/bundle/apply": {
+            "post": {
                 "security": [
                     {
                         "Authentication": []
                     }
                 ],
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
+                    "Bundle"
+                ],
+                "summary": "Applies a bundle in a single transaction",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "delete objects that are not in the bundle",
+                        "name": "prune",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Bundle to apply",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Bundle"
+                        }
+                    }
                 ],
-                "summary": "Deletes a policy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Policy"
+                            "$ref": "#/definitions/bundle.Plan"
                         }
                     },
                     "400": {
@@ -762,32 +852,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/policies/{identifier}/matches": {
-            "get": {
+        "/v1/bundle/plan": {
+            "post": {
                 "security": [
                     {
                         "Authentication": []
                     }
                 ],
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Policy"
+                    "Bundle"
+                ],
+                "summary": "Computes the changes needed to reach a bundle",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "delete objects that are not in the bundle",
+                        "name": "prune",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Bundle to plan",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/bundle.Bundle"
+                        }
+                    }
                 ],
-                "summary": "Retrieve compiled policies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.CompiledPolicy"
-                            }
+                            "$ref": "#/definitions/bundle.Plan"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -801,7 +909,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/principals": {
+        "/v1/cedar-policies": {
             "get": {
                 "security": [
                     {
@@ -812,9 +920,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Principal"
+                    "CedarPolicy"
                 ],
-                "summary": "Lists principals",
+                "summary": "Lists Cedar policies",
                 "parameters": [
                     {
                         "type": "integer",
@@ -834,14 +942,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "name:contains:something",
+                        "example": "resource_kind:contains:something",
                         "description": "filter on a field",
                         "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "name:desc",
+                        "example": "id:desc",
                         "description": "sort field and order",
                         "name": "sort",
                         "in": "query"
@@ -853,7 +961,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Principal"
+                                "$ref": "#/definitions/model.CedarPolicy"
                             }
                         }
                     },
@@ -881,17 +989,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Principal"
+                    "CedarPolicy"
                 ],
-                "summary": "Creates a new principal",
+                "summary": "Creates a new Cedar policy",
                 "parameters": [
                     {
-                        "description": "Principal creation request",
+                        "description": "Cedar policy creation request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreatePrincipalRequest"
+                            "$ref": "#/definitions/handler.CreateCedarPolicyRequest"
                         }
                     }
                 ],
@@ -899,7 +1007,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Principal"
+                            "$ref": "#/definitions/model.CedarPolicy"
                         }
                     },
                     "400": {
@@ -917,7 +1025,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/principals/{identifier}": {
+        "/v1/cedar-policies/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -928,14 +1036,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Principal"
+                    "CedarPolicy"
                 ],
-                "summary": "Retrieve a principal",
+                "summary": "Retrieve a Cedar policy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Principal"
+                            "$ref": "#/definitions/model.CedarPolicy"
                         }
                     },
                     "404": {
@@ -962,17 +1070,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Principal"
+                    "CedarPolicy"
                 ],
-                "summary": "Updates a principal",
+                "summary": "Updates a Cedar policy",
                 "parameters": [
                     {
-                        "description": "Principal update request",
+                        "description": "Cedar policy update request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdatePrincipalRequest"
+                            "$ref": "#/definitions/handler.UpdateCedarPolicyRequest"
                         }
                     }
                 ],
@@ -980,7 +1088,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Principal"
+                            "$ref": "#/definitions/model.CedarPolicy"
                         }
                     },
                     "400": {
@@ -1007,14 +1115,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Principal"
+                    "CedarPolicy"
                 ],
-                "summary": "Deletes a principal",
+                "summary": "Deletes a Cedar policy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Principal"
+                            "$ref": "#/definitions/model.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1032,7 +1140,66 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/resources": {
+        "/v1/check": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Check"
+                ],
+                "summary": "Check if a principal has access to do action on resource",
+                "parameters": [
+                    {
+                        "description": "Check request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CheckResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/clients": {
             "get": {
                 "security": [
                     {
@@ -1043,9 +1210,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resource"
+                    "Client"
                 ],
-                "summary": "Lists resources",
+                "summary": "Lists clients",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1065,14 +1232,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "kind:contains:something",
+                        "example": "name:contains:something",
                         "description": "filter on a field",
                         "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "kind:desc",
+                        "example": "name:desc",
                         "description": "sort field and order",
                         "name": "sort",
                         "in": "query"
@@ -1084,7 +1251,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Resource"
+                                "$ref": "#/definitions/model.Client"
                             }
                         }
                     },
@@ -1112,17 +1279,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resource"
+                    "Client"
                 ],
-                "summary": "Creates a new resource",
+                "summary": "Creates a new client",
                 "parameters": [
                     {
-                        "description": "Resource creation request",
+                        "description": "Client creation request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateResourceRequest"
+                            "$ref": "#/definitions/handler.ClientCreateRequest"
                         }
                     }
                 ],
@@ -1130,7 +1297,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Resource"
+                            "$ref": "#/definitions/model.Client"
                         }
                     },
                     "400": {
@@ -1148,7 +1315,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/resources/{identifier}": {
+        "/v1/clients/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -1159,14 +1326,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resource"
+                    "Client"
                 ],
-                "summary": "Retrieve a resource",
+                "summary": "Retrieve a client",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Resource"
+                            "$ref": "#/definitions/model.Client"
                         }
                     },
                     "404": {
@@ -1183,51 +1350,6 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "security": [
-                    {
-                        "Authentication": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Resource"
-                ],
-                "summary": "Updates a resource",
-                "parameters": [
-                    {
-                        "description": "Resource update request",
-                        "name": "default",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.UpdateResourceRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Resource"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    }
-                }
-            },
             "delete": {
                 "security": [
                     {
@@ -1238,14 +1360,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Resource"
+                    "Client"
                 ],
-                "summary": "Deletes a resource",
+                "summary": "Deletes a client",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Resource"
+                            "$ref": "#/definitions/model.Client"
                         }
                     },
                     "400": {
@@ -1263,7 +1385,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/roles": {
+        "/v1/compile-jobs": {
             "get": {
                 "security": [
                     {
@@ -1274,9 +1396,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "CompileJob"
                 ],
-                "summary": "Lists roles",
+                "summary": "Lists compile jobs with their status",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1296,14 +1418,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "kind:contains:something",
+                        "example": "status:contains:failed",
                         "description": "filter on a field",
                         "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "kind:desc",
+                        "example": "updated_at:desc",
                         "description": "sort field and order",
                         "name": "sort",
                         "in": "query"
@@ -1315,7 +1437,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Role"
+                                "$ref": "#/definitions/model.CompileJob"
                             }
                         }
                     },
@@ -1332,8 +1454,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/compile-jobs/{identifier}": {
+            "get": {
                 "security": [
                     {
                         "Authentication": []
@@ -1343,29 +1467,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
-                ],
-                "summary": "Creates a new role",
-                "parameters": [
-                    {
-                        "description": "Role creation request",
-                        "name": "default",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateRoleRequest"
-                        }
-                    }
+                    "CompileJob"
                 ],
+                "summary": "Retrieve a compile job with its status and last error",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Role"
+                            "$ref": "#/definitions/model.CompileJob"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -1379,8 +1492,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/roles/{identifier}": {
-            "get": {
+        "/v1/compile-jobs/{identifier}/retry": {
+            "post": {
                 "security": [
                     {
                         "Authentication": []
@@ -1390,14 +1503,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "CompileJob"
                 ],
-                "summary": "Retrieve a role",
+                "summary": "Sets a compile job back to pending so it gets processed again with all its attempts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Role"
+                            "$ref": "#/definitions/model.CompileJob"
                         }
                     },
                     "404": {
@@ -1413,8 +1526,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/compiled/rebuild": {
+            "get": {
                 "security": [
                     {
                         "Authentication": []
@@ -1424,42 +1539,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
-                ],
-                "summary": "Updates a role",
-                "parameters": [
-                    {
-                        "description": "Role update request",
-                        "name": "default",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.UpdateRoleRequest"
-                        }
-                    }
+                    "Policy"
                 ],
+                "summary": "Retrieve the progress of the current or last compiled policies rebuild",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Role"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
+                            "$ref": "#/definitions/compile.RebuildStatus"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "Authentication": []
@@ -1469,18 +1561,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Policy"
                 ],
-                "summary": "Deletes a role",
+                "summary": "Rebuild compiled policies from scratch, in background",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.Role"
+                            "$ref": "#/definitions/compile.RebuildStatus"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -1494,7 +1586,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/stats": {
+        "/v1/delegations": {
             "get": {
                 "security": [
                     {
@@ -1505,35 +1597,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Check"
+                    "Delegation"
                 ],
-                "summary": "Retrieve statistics for last days",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Stats"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
+                "summary": "Lists delegations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.ErrorResponse"
-                        }
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "delegate_id:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "expires_at:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Delegation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/v1/token": {
+            },
             "post": {
                 "security": [
                     {
@@ -1544,17 +1666,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Delegation"
                 ],
-                "summary": "Retrieve a client token",
+                "summary": "Creates a new delegation",
                 "parameters": [
                     {
-                        "description": "Token request",
+                        "description": "Delegation creation request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenRequest"
+                            "$ref": "#/definitions/handler.CreateDelegationRequest"
                         }
                     }
                 ],
@@ -1562,7 +1684,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenResponse"
+                            "$ref": "#/definitions/model.Delegation"
                         }
                     },
                     "400": {
@@ -1580,7 +1702,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users": {
+        "/v1/delegations/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -1591,9 +1713,166 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Delegation"
                 ],
-                "summary": "Lists users",
+                "summary": "Retrieve a delegation",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Delegation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delegation"
+                ],
+                "summary": "Deletes a delegation",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/lint": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lint"
+                ],
+                "summary": "Lints the authorization model and reports findings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/lint.Report"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/oauth": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Authenticates a user using an OAuth OpenID Connect provider",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/oauth/callback": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Callback of the OAuth OpenID Connect provider authentication",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/policies": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Lists policies",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1613,14 +1892,14 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "example": "name:contains:something",
+                        "example": "kind:contains:something",
                         "description": "filter on a field",
                         "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "name:desc",
+                        "example": "kind:desc",
                         "description": "sort field and order",
                         "name": "sort",
                         "in": "query"
@@ -1632,7 +1911,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.User"
+                                "$ref": "#/definitions/model.Policy"
                             }
                         }
                     },
@@ -1660,17 +1939,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Policy"
                 ],
-                "summary": "Creates a new user",
+                "summary": "Creates a new policy",
                 "parameters": [
                     {
-                        "description": "User creation request",
+                        "description": "Policy creation request",
                         "name": "default",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UserCreateRequest"
+                            "$ref": "#/definitions/handler.CreatePolicyRequest"
                         }
                     }
                 ],
@@ -1678,7 +1957,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.Policy"
                         }
                     },
                     "400": {
@@ -1696,7 +1975,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{identifier}": {
+        "/v1/policies/{identifier}": {
             "get": {
                 "security": [
                     {
@@ -1707,14 +1986,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Policy"
                 ],
-                "summary": "Retrieve a user",
+                "summary": "Retrieve a policy",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.Policy"
                         }
                     },
                     "404": {
@@ -1731,7 +2010,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "Authentication": []
@@ -1741,14 +2020,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Policy"
+                ],
+                "summary": "Updates a policy",
+                "parameters": [
+                    {
+                        "description": "Policy update request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdatePolicyRequest"
+                        }
+                    }
                 ],
-                "summary": "Deletes a user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.User"
+                            "$ref": "#/definitions/model.Policy"
                         }
                     },
                     "400": {
@@ -1764,80 +2054,2603 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handler.AttributeKeyValue": {
-            "type": "object",
-            "required": [
-                "key",
-                "value"
-            ],
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Deletes a policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Policy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/policies/{identifier}/matches": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Policy"
+                ],
+                "summary": "Retrieve compiled policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CompiledPolicy"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/principals": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Principal"
+                ],
+                "summary": "Lists principals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Principal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Principal"
+                ],
+                "summary": "Creates a new principal",
+                "parameters": [
+                    {
+                        "description": "Principal creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreatePrincipalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Principal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/principals/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Principal"
+                ],
+                "summary": "Retrieve a principal",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Principal"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Principal"
+                ],
+                "summary": "Updates a principal",
+                "parameters": [
+                    {
+                        "description": "Principal update request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdatePrincipalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Principal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Principal"
+                ],
+                "summary": "Deletes a principal",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Principal"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/resource-kinds": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ResourceKind"
+                ],
+                "summary": "Lists resource kinds",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "id:contains:post",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "id:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ResourceKind"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ResourceKind"
+                ],
+                "summary": "Declares a new resource kind with its allowed actions and attributes schema",
+                "parameters": [
+                    {
+                        "description": "Resource kind creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateResourceKindRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceKind"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/resource-kinds/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ResourceKind"
+                ],
+                "summary": "Retrieve a resource kind",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceKind"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ResourceKind"
+                ],
+                "summary": "Updates a resource kind",
+                "parameters": [
+                    {
+                        "description": "Resource kind update request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateResourceKindRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ResourceKind"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ResourceKind"
+                ],
+                "summary": "Deletes a resource kind, its resources are not restricted anymore",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/resources": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resource"
+                ],
+                "summary": "Lists resources",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resource"
+                ],
+                "summary": "Creates a new resource",
+                "parameters": [
+                    {
+                        "description": "Resource creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/resources/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resource"
+                ],
+                "summary": "Retrieve a resource",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Resource"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resource"
+                ],
+                "summary": "Updates a resource",
+                "parameters": [
+                    {
+                        "description": "Resource update request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Resource"
+                ],
+                "summary": "Deletes a resource",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Resource"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/review-campaigns": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Lists access review campaigns",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "status:contains:open",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "deadline:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ReviewCampaign"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Creates a new access review campaign from the roles currently given to principals",
+                "parameters": [
+                    {
+                        "description": "Review campaign creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateReviewCampaignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewCampaign"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/review-campaigns/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Retrieve an access review campaign with its items",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewCampaign"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Deletes an access review campaign",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/review-campaigns/{identifier}/close": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Closes an access review campaign before its deadline",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewCampaign"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/review-campaigns/{identifier}/decisions": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ReviewCampaign"
+                ],
+                "summary": "Confirms or revokes a role given to a principal, as a reviewer of the campaign",
+                "parameters": [
+                    {
+                        "description": "Review decision request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.DecideReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Lists roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "kind:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Creates a new role",
+                "parameters": [
+                    {
+                        "description": "Role creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/roles/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Retrieve a role",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Updates a role",
+                "parameters": [
+                    {
+                        "description": "Role update request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Deletes a role",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stats": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Check"
+                ],
+                "summary": "Retrieve statistics for last days",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Stats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/token": {
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Retrieve a client token",
+                "parameters": [
+                    {
+                        "description": "Token request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Lists users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "page size",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name:contains:something",
+                        "description": "filter on a field",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name:desc",
+                        "description": "sort field and order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Creates a new user",
+                "parameters": [
+                    {
+                        "description": "User creation request",
+                        "name": "default",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{identifier}": {
+            "get": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Retrieve a user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Authentication": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Deletes a user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "bundle.Bundle": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Policy"
+                    }
+                },
+                "principals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Principal"
+                    }
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Resource"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Role"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "bundle.Change": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "object": {
+                    "type": "string"
+                },
+                "operation": {
+                    "$ref": "#/definitions/bundle.Operation"
+                }
+            }
+        },
+        "bundle.Operation": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "OperationCreate",
+                "OperationUpdate",
+                "OperationDelete"
+            ]
+        },
+        "bundle.Plan": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/bundle.Change"
+                    }
+                }
+            }
+        },
+        "bundle.Policy": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attribute_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "effect": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "not_after": {
+                    "type": "string"
+                },
+                "not_before": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule": {
+                    "type": "string"
+                },
+                "schedule_duration": {
+                    "type": "string"
+                },
+                "schedule_timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.Principal": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "bundle.Resource": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "bundle.Role": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "compile.RebuildState": {
+            "type": "string",
+            "enum": [
+                "idle",
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "RebuildStateIdle",
+                "RebuildStateRunning",
+                "RebuildStateSucceeded",
+                "RebuildStateFailed"
+            ]
+        },
+        "compile.RebuildStatus": {
+            "type": "object",
+            "properties": {
+                "compiled_policies": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/compile.RebuildState"
+                },
+                "total_policies": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.AttributeKeyValue": {
+            "type": "object",
+            "required": [
+                "key",
+                "value"
+            ],
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "handler.AuthRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "mypassword"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "handler.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "handler.CheckRequest": {
+            "type": "object",
+            "required": [
+                "checks"
+            ],
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CheckRequestQuery"
+                    }
+                },
+                "consistency_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CheckRequestQuery": {
+            "type": "object",
+            "required": [
+                "action",
+                "principal",
+                "resource_kind",
+                "resource_value"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "context": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "resource_kind": {
+                    "type": "string"
+                },
+                "resource_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CheckResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CheckResponseQuery"
+                    }
+                }
+            }
+        },
+        "handler.CheckResponseQuery": {
+            "type": "object",
+            "required": [
+                "action",
+                "principal",
+                "resource_kind",
+                "resource_value"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "context": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "is_allowed": {
+                    "type": "boolean"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "resource_kind": {
+                    "type": "string"
+                },
+                "resource_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ClientCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "my-client"
+                }
+            }
+        },
+        "handler.CreateCedarPolicyRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "source"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "permit (principal in Role::\"editor\", action == Action::\"edit\", resource is post) when { resource.owner == principal.team };"
+                }
+            }
+        },
+        "handler.CreateDelegationRequest": {
+            "type": "object",
+            "required": [
+                "actions",
+                "delegate_id",
+                "delegator_id",
+                "expires_at",
+                "id",
+                "resources"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "delegate_id": {
+                    "type": "string"
+                },
+                "delegator_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.CreatePolicyRequest": {
+            "type": "object",
+            "required": [
+                "actions",
+                "id",
+                "resources"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attribute_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "not_after": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "not_before": {
+                    "type": "string",
+                    "example": "2023-12-01T00:00:00Z"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "0 9 * * MON-FRI"
+                },
+                "schedule_duration": {
+                    "type": "string",
+                    "example": "8h"
+                },
+                "schedule_timezone": {
+                    "type": "string",
+                    "example": "Europe/Paris"
+                }
+            }
+        },
+        "handler.CreatePrincipalRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.CreateResourceKindRequest": {
+            "type": "object",
+            "required": [
+                "actions",
+                "id"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attributes_schema": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateResourceRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "kind"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateReviewCampaignRequest": {
+            "type": "object",
+            "required": [
+                "deadline",
+                "id",
+                "reviewers"
+            ],
+            "properties": {
+                "auto_revoke": {
+                    "type": "boolean"
+                },
+                "deadline": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reviewers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "id",
+                "policies"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.DecideReviewRequest": {
+            "type": "object",
+            "required": [
+                "decision",
+                "principal_id",
+                "role_id"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "revoked"
+                    ],
+                    "example": "confirmed"
+                },
+                "principal_id": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TokenRequest": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string",
+                    "example": "0be4e0e0-6788-4b99-8e00-e0af5b4945b1"
+                },
+                "client_secret": {
+                    "type": "string",
+                    "example": "EXCAdNZjCz0qJ_8uYA2clkxVdp_f1tm7"
+                },
+                "grant_type": {
+                    "type": "string",
+                    "example": "client_credentials"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdateCedarPolicyRequest": {
+            "type": "object",
+            "required": [
+                "source"
+            ],
+            "properties": {
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdatePolicyRequest": {
+            "type": "object",
+            "required": [
+                "actions",
+                "resources"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attribute_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "effect": {
+                    "type": "string",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "example": "allow"
+                },
+                "not_after": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "not_before": {
+                    "type": "string",
+                    "example": "2023-12-01T00:00:00Z"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schedule": {
+                    "type": "string",
+                    "example": "0 9 * * MON-FRI"
+                },
+                "schedule_duration": {
+                    "type": "string",
+                    "example": "8h"
+                },
+                "schedule_timezone": {
+                    "type": "string",
+                    "example": "Europe/Paris"
+                }
+            }
+        },
+        "handler.UpdatePrincipalRequest": {
+            "type": "object",
             "properties": {
-                "key": {
-                    "type": "string"
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                    }
                 },
-                "value": {}
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
             }
         },
-        "handler.AuthRequest": {
+        "handler.UpdateResourceKindRequest": {
+            "type": "object",
+            "required": [
+                "actions"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "attributes_schema": {
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdateResourceRequest": {
             "type": "object",
+            "required": [
+                "kind"
+            ],
             "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "mypassword"
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                    }
+                },
+                "kind": {
+                    "type": "string"
                 },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "policies"
+            ],
+            "properties": {
+                "policies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.UserCreateRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
                 "username": {
                     "type": "string",
-                    "example": "john.doe"
+                    "example": "my-user"
                 }
             }
         },
-        "handler.AuthResponse": {
+        "lint.Finding": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "message": {
                     "type": "string"
                 },
-                "expires_in": {
-                    "type": "integer"
+                "object_id": {
+                    "type": "string"
                 },
-                "token_type": {
+                "object_type": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/model.User"
+                "rule": {
+                    "type": "string"
+                },
+                "severity": {
+                    "$ref": "#/definitions/lint.Severity"
                 }
             }
         },
-        "handler.CheckRequest": {
+        "lint.Report": {
             "type": "object",
-            "required": [
-                "checks"
-            ],
             "properties": {
-                "checks": {
+                "findings": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.CheckRequestQuery"
+                        "$ref": "#/definitions/lint.Finding"
                     }
                 }
             }
         },
-        "handler.CheckRequestQuery": {
+        "lint.Severity": {
+            "type": "string",
+            "enum": [
+                "error",
+                "warning"
+            ],
+            "x-enum-varnames": [
+                "SeverityError",
+                "SeverityWarning"
+            ]
+        },
+        "model.Action": {
             "type": "object",
-            "required": [
-                "action",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Attribute": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Audit": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "algorithm": {
+                    "type": "string"
+                },
+                "cedar_policy_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "delegation_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_allowed": {
+                    "type": "boolean"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "resource_kind": {
+                    "type": "string"
+                },
+                "resource_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CedarPolicy": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "effect": {
+                    "$ref": "#/definitions/model.PolicyEffect"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "resource_kind": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Client": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CompileJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "pending_revision": {
+                    "type": "integer"
+                },
+                "pending_since": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.CompileJobStatus"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "$ref": "#/definitions/model.CompileJobTargetType"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CompileJobStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "CompileJobStatusPending",
+                "CompileJobStatusRunning",
+                "CompileJobStatusSucceeded",
+                "CompileJobStatusFailed"
+            ]
+        },
+        "model.CompileJobTargetType": {
+            "type": "string",
+            "enum": [
+                "policy",
                 "principal",
-                "resource_kind",
-                "resource_value"
+                "resource"
             ],
+            "x-enum-varnames": [
+                "CompileJobTargetTypePolicy",
+                "CompileJobTargetTypePrincipal",
+                "CompileJobTargetTypeResource"
+            ]
+        },
+        "model.CompiledPolicy": {
+            "type": "object",
             "properties": {
-                "action": {
+                "action_id": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "principal": {
+                "policy_id": {
+                    "type": "string"
+                },
+                "principal_id": {
                     "type": "string"
                 },
                 "resource_kind": {
@@ -1845,299 +4658,398 @@ const docTemplate = `{
                 },
                 "resource_value": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "handler.CheckResponse": {
+        "model.Delegation": {
             "type": "object",
             "properties": {
-                "checks": {
+                "actions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.CheckResponseQuery"
+                        "$ref": "#/definitions/model.Action"
                     }
-                }
-            }
-        },
-        "handler.CheckResponseQuery": {
-            "type": "object",
-            "required": [
-                "action",
-                "principal",
-                "resource_kind",
-                "resource_value"
-            ],
-            "properties": {
-                "action": {
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "is_allowed": {
-                    "type": "boolean"
+                "delegate_id": {
+                    "type": "string"
                 },
-                "principal": {
+                "delegator_id": {
                     "type": "string"
                 },
-                "resource_kind": {
+                "expires_at": {
                     "type": "string"
                 },
-                "resource_value": {
+                "id": {
+                    "type": "string"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Resource"
+                    }
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "handler.ClientCreateRequest": {
+        "model.ErrorResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
-                    "type": "string",
-                    "example": "my-client"
+                "error": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "validation": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ValidateErrorResponse"
+                    }
                 }
             }
         },
-        "handler.CreatePolicyRequest": {
+        "model.Policy": {
             "type": "object",
-            "required": [
-                "actions",
-                "id",
-                "resources"
-            ],
             "properties": {
                 "actions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Action"
                     }
                 },
                 "attribute_rules": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effect": {
+                    "$ref": "#/definitions/model.PolicyEffect"
                 },
                 "id": {
                     "type": "string"
                 },
+                "not_after": {
+                    "type": "string"
+                },
+                "not_before": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
                 "resources": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Resource"
                     }
+                },
+                "schedule": {
+                    "type": "string"
+                },
+                "schedule_duration": {
+                    "type": "string"
+                },
+                "schedule_timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handler.CreatePrincipalRequest": {
-            "type": "object",
-            "required": [
-                "id"
+        "model.PolicyEffect": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "deny"
             ],
+            "x-enum-varnames": [
+                "PolicyEffectAllow",
+                "PolicyEffectDeny"
+            ]
+        },
+        "model.Principal": {
+            "type": "object",
             "properties": {
                 "attributes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                        "$ref": "#/definitions/model.Attribute"
                     }
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "is_locked": {
+                    "type": "boolean"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Role"
                     }
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handler.CreateResourceRequest": {
+        "model.Resource": {
             "type": "object",
-            "required": [
-                "id",
-                "kind"
-            ],
             "properties": {
                 "attributes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.AttributeKeyValue"
+                        "$ref": "#/definitions/model.Attribute"
                     }
                 },
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "is_locked": {
+                    "type": "boolean"
+                },
                 "kind": {
                     "type": "string"
                 },
+                "updated_at": {
+                    "type": "string"
+                },
                 "value": {
                     "type": "string"
                 }
             }
         },
-        "handler.CreateRoleRequest": {
+        "model.ResourceKind": {
             "type": "object",
-            "required": [
-                "id",
-                "policies"
-            ],
             "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "policies": {
+                "actions": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Action"
                     }
-                }
-            }
-        },
-        "handler.TokenRequest": {
-            "type": "object",
-            "properties": {
-                "client_id": {
-                    "type": "string",
-                    "example": "0be4e0e0-6788-4b99-8e00-e0af5b4945b1"
                 },
-                "client_secret": {
-                    "type": "string",
-                    "example": "EXCAdNZjCz0qJ_8uYA2clkxVdp_f1tm7"
+                "attributes_schema": {
+                    "type": "object"
                 },
-                "grant_type": {
-                    "type": "string",
-                    "example": "client_credentials"
+                "created_at": {
+                    "type": "string"
                 },
-                "refresh_token": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "handler.TokenResponse": {
+        "model.ReviewCampaign": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "auto_revoke": {
+                    "type": "boolean"
+                },
+                "closed_at": {
                     "type": "string"
                 },
-                "expires_in": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "refresh_token": {
+                "deadline": {
                     "type": "string"
                 },
-                "token_type": {
+                "description": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.UpdatePolicyRequest": {
-            "type": "object",
-            "required": [
-                "actions",
-                "resources"
-            ],
-            "properties": {
-                "actions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 },
-                "attribute_rules": {
+                "id": {
+                    "type": "string"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.ReviewItem"
                     }
                 },
-                "resources": {
+                "reminded_at": {
+                    "type": "string"
+                },
+                "reviewers": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "status": {
+                    "$ref": "#/definitions/model.ReviewCampaignStatus"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UpdatePrincipalRequest": {
+        "model.ReviewCampaignStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "closed"
+            ],
+            "x-enum-varnames": [
+                "ReviewCampaignStatusOpen",
+                "ReviewCampaignStatusClosed"
+            ]
+        },
+        "model.ReviewDecision": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "confirmed",
+                "revoked",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "ReviewDecisionPending",
+                "ReviewDecisionConfirmed",
+                "ReviewDecisionRevoked",
+                "ReviewDecisionExpired"
+            ]
+        },
+        "model.ReviewItem": {
             "type": "object",
             "properties": {
-                "attributes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.AttributeKeyValue"
-                    }
+                "campaign_id": {
+                    "type": "string"
                 },
-                "roles": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/model.ReviewDecision"
+                },
+                "principal_id": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Role": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "policies": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/model.Policy"
                     }
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UpdateResourceRequest": {
+        "model.Stats": {
             "type": "object",
-            "required": [
-                "kind"
-            ],
             "properties": {
-                "attributes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.AttributeKeyValue"
-                    }
+                "checks_allowed_number": {
+                    "type": "integer"
                 },
-                "kind": {
+                "checks_denied_number": {
+                    "type": "integer"
+                },
+                "date": {
                     "type": "string"
                 },
-                "value": {
+                "id": {
                     "type": "string"
                 }
             }
         },
-        "handler.UpdateRoleRequest": {
+        "model.SuccessResponse": {
             "type": "object",
-            "required": [
-                "policies"
-            ],
             "properties": {
-                "policies": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "handler.UserCreateRequest": {
+        "model.User": {
             "type": "object",
-            "required": [
-                "username"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Only used to display generated password after creation",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
                 "username": {
-                    "type": "string",
-                    "example": "my-user"
+                    "type": "string"
                 }
             }
         },
-        "model.Action": {
+        "model.ValidateErrorResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "field": {
                     "type": "string"
                 },
-                "id": {
+                "tag": {
                     "type": "string"
                 },
-                "updated_at": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "model.Attribute": {
+        "scim.Email": {
             "type": "object",
             "properties": {
-                "key": {
+                "primary": {
+                    "type": "boolean"
+                },
+                "type": {
                     "type": "string"
                 },
                 "value": {
@@ -2145,260 +5057,273 @@ const docTemplate = `{
                 }
             }
         },
-        "model.Audit": {
+        "scim.Error": {
             "type": "object",
             "properties": {
-                "action": {
+                "detail": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "is_allowed": {
-                    "type": "boolean"
-                },
-                "policy_id": {
-                    "type": "string"
-                },
-                "principal": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "resource_kind": {
+                "scimType": {
                     "type": "string"
                 },
-                "resource_value": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "model.Client": {
+        "scim.Group": {
             "type": "object",
             "properties": {
-                "client_id": {
+                "displayName": {
                     "type": "string"
                 },
-                "client_secret": {
+                "externalId": {
                     "type": "string"
                 },
-                "created_at": {
+                "id": {
                     "type": "string"
                 },
-                "data": {
-                    "type": "string"
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.Reference"
+                    }
                 },
-                "domain": {
-                    "type": "string"
+                "meta": {
+                    "$ref": "#/definitions/scim.Meta"
                 },
-                "name": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "scim.ListResponse": {
+            "type": "object",
+            "properties": {
+                "Resources": {},
+                "itemsPerPage": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
+                    "type": "integer"
                 }
             }
         },
-        "model.CompiledPolicy": {
+        "scim.Meta": {
             "type": "object",
             "properties": {
-                "action_id": {
+                "created": {
                     "type": "string"
                 },
-                "created_at": {
+                "lastModified": {
                     "type": "string"
                 },
-                "policy_id": {
+                "location": {
                     "type": "string"
                 },
-                "principal_id": {
+                "resourceType": {
                     "type": "string"
-                },
-                "resource_kind": {
+                }
+            }
+        },
+        "scim.Name": {
+            "type": "object",
+            "properties": {
+                "familyName": {
                     "type": "string"
                 },
-                "resource_value": {
+                "formatted": {
                     "type": "string"
                 },
-                "updated_at": {
+                "givenName": {
                     "type": "string"
-                },
-                "version": {
-                    "type": "integer"
                 }
             }
         },
-        "model.ErrorResponse": {
+        "scim.PatchOperation": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "boolean"
+                "op": {
+                    "type": "string"
                 },
-                "message": {
+                "path": {
                     "type": "string"
                 },
-                "validation": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.ValidateErrorResponse"
-                    }
-                }
+                "value": {}
             }
         },
-        "model.Policy": {
+        "scim.PatchRequest": {
             "type": "object",
             "properties": {
-                "actions": {
+                "Operations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Action"
+                        "$ref": "#/definitions/scim.PatchOperation"
                     }
                 },
-                "attribute_rules": {
-                    "type": "object"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "resources": {
+                "schemas": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Resource"
+                        "type": "string"
                     }
+                }
+            }
+        },
+        "scim.Reference": {
+            "type": "object",
+            "properties": {
+                "$ref": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "model.Principal": {
+        "scim.ServiceProviderConfig": {
             "type": "object",
             "properties": {
-                "attributes": {
+                "authenticationSchemes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Attribute"
+                        "$ref": "#/definitions/scim.authenticationScheme"
                     }
                 },
-                "created_at": {
-                    "type": "string"
+                "bulk": {
+                    "$ref": "#/definitions/scim.bulkSupported"
                 },
-                "id": {
-                    "type": "string"
+                "changePassword": {
+                    "$ref": "#/definitions/scim.supported"
                 },
-                "is_locked": {
-                    "type": "boolean"
+                "etag": {
+                    "$ref": "#/definitions/scim.supported"
                 },
-                "roles": {
+                "filter": {
+                    "$ref": "#/definitions/scim.filterSupported"
+                },
+                "patch": {
+                    "$ref": "#/definitions/scim.supported"
+                },
+                "schemas": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Role"
+                        "type": "string"
                     }
                 },
-                "updated_at": {
-                    "type": "string"
+                "sort": {
+                    "$ref": "#/definitions/scim.supported"
                 }
             }
         },
-        "model.Resource": {
+        "scim.User": {
             "type": "object",
             "properties": {
-                "attributes": {
+                "active": {
+                    "type": "boolean"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "emails": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Attribute"
+                        "$ref": "#/definitions/scim.Email"
                     }
                 },
-                "created_at": {
+                "externalId": {
                     "type": "string"
                 },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/scim.Reference"
+                    }
+                },
                 "id": {
                     "type": "string"
                 },
-                "is_locked": {
-                    "type": "boolean"
+                "meta": {
+                    "$ref": "#/definitions/scim.Meta"
                 },
-                "kind": {
-                    "type": "string"
+                "name": {
+                    "$ref": "#/definitions/scim.Name"
                 },
-                "updated_at": {
-                    "type": "string"
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "value": {
+                "userName": {
                     "type": "string"
                 }
             }
         },
-        "model.Role": {
+        "scim.authenticationScheme": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "description": {
                     "type": "string"
                 },
-                "id": {
+                "name": {
                     "type": "string"
                 },
-                "policies": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Policy"
-                    }
-                },
-                "updated_at": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "model.Stats": {
+        "scim.bulkSupported": {
             "type": "object",
             "properties": {
-                "checks_allowed_number": {
+                "maxOperations": {
                     "type": "integer"
                 },
-                "checks_denied_number": {
+                "maxPayloadSize": {
                     "type": "integer"
                 },
-                "date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "supported": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.User": {
+        "scim.filterSupported": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "password": {
-                    "description": "Only used to display generated password after creation",
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+                "maxResults": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string"
+                "supported": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.ValidateErrorResponse": {
+        "scim.supported": {
             "type": "object",
             "properties": {
-                "field": {
-                    "type": "string"
-                },
-                "tag": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
+                "supported": {
+                    "type": "boolean"
                 }
             }
         }
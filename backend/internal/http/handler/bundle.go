@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/gofiber/fiber/v2"
+)
+
+var bundleContentTypes = map[bundle.Format]string{
+	bundle.FormatJSON: fiber.MIMEApplicationJSON,
+	bundle.FormatYAML: "application/yaml",
+}
+
+// Exports the current state as a bundle.
+//
+//	@security	Authentication
+//	@Summary	Exports the current state as a bundle
+//	@Tags		Bundle
+//	@Produce	json,application/yaml
+//	@Param		format	query		string	false	"bundle format"	Enums(yaml, json)	default(yaml)
+//	@Success	200		{object}	bundle.Bundle
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/bundle [Get]
+func BundleExport(
+	bundleManager bundle.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, err := bundle.ParseFormat(c.Query("format"))
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		exported, err := bundleManager.Export()
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		data, err := bundle.Encode(exported, format)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		c.Set(fiber.HeaderContentType, bundleContentTypes[format])
+
+		return c.Send(data)
+	}
+}
+
+// Computes the changes needed to reach a bundle, without applying them.
+//
+//	@security	Authentication
+//	@Summary	Computes the changes needed to reach a bundle
+//	@Tags		Bundle
+//	@Accept		json,application/yaml
+//	@Produce	json
+//	@Param		prune	query		bool			false	"delete objects that are not in the bundle"
+//	@Param		default	body		bundle.Bundle	true	"Bundle to plan"
+//	@Success	200		{object}	bundle.Plan
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/bundle/plan [Post]
+func BundlePlan(
+	bundleManager bundle.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		desired, err := decodeBundleBody(c)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		plan, err := bundleManager.Plan(desired, c.QueryBool("prune"))
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(plan)
+	}
+}
+
+// Applies a bundle in a single transaction.
+//
+//	@security	Authentication
+//	@Summary	Applies a bundle in a single transaction
+//	@Tags		Bundle
+//	@Accept		json,application/yaml
+//	@Produce	json
+//	@Param		prune	query		bool			false	"delete objects that are not in the bundle"
+//	@Param		default	body		bundle.Bundle	true	"Bundle to apply"
+//	@Success	200		{object}	bundle.Plan
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/bundle/apply [Post]
+func BundleApply(
+	bundleManager bundle.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		desired, err := decodeBundleBody(c)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		plan, err := bundleManager.Apply(desired, c.QueryBool("prune"))
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(plan)
+	}
+}
+
+// decodeBundleBody reads the bundle from the request body, using JSON when the
+// request content type says so and YAML otherwise.
+func decodeBundleBody(c *fiber.Ctx) (*bundle.Bundle, error) {
+	format := bundle.FormatYAML
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		format = bundle.FormatJSON
+	}
+
+	return bundle.Decode(c.Body(), format)
+}
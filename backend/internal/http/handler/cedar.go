@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/http/handler/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CreateCedarPolicyRequest struct {
+	ID     string `json:"id" validate:"required,slug"`
+	Source string `json:"source" validate:"required" example:"permit (principal in Role::\"editor\", action == Action::\"edit\", resource is post) when { resource.owner == principal.team };"`
+}
+
+type UpdateCedarPolicyRequest struct {
+	Source string `json:"source" validate:"required"`
+}
+
+// Creates a new Cedar policy.
+//
+//	@security	Authentication
+//	@Summary	Creates a new Cedar policy
+//	@Tags		CedarPolicy
+//	@Produce	json
+//	@Param		default	body		CreateCedarPolicyRequest	true	"Cedar policy creation request"
+//	@Success	200		{object}	model.CedarPolicy
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/cedar-policies [Post]
+func CedarPolicyCreate(
+	validate *validator.Validate,
+	cedarPolicyManager manager.CedarPolicy,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &CreateCedarPolicyRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		// Create Cedar policy
+		cedarPolicy, err := cedarPolicyManager.Create(request.ID, request.Source)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(cedarPolicy)
+	}
+}
+
+// Lists Cedar policies.
+//
+//	@security	Authentication
+//	@Summary	Lists Cedar policies
+//	@Tags		CedarPolicy
+//	@Produce	json
+//	@Param		page	query		int		false	"page number"			example(1)
+//	@Param		size	query		int		false	"page size"				minimum(1)	maximum(1000)	default(100)
+//	@Param		filter	query		string	false	"filter on a field"		example(resource_kind:contains:something)
+//	@Param		sort	query		string	false	"sort field and order"	example(id:desc)
+//	@Success	200		{object}	[]model.CedarPolicy
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/cedar-policies [Get]
+func CedarPolicyList(
+	cedarPolicyManager manager.CedarPolicy,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, size, err := paginate(c)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		// List Cedar policies
+		cedarPolicies, total, err := cedarPolicyManager.GetRepository().Find(
+			repository.WithPage(page),
+			repository.WithSize(size),
+			repository.WithFilter(httpFilterToORM(c)),
+			repository.WithSort(httpSortToORM(c)),
+		)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.NewPaginated(cedarPolicies, total, page, size))
+	}
+}
+
+// Retrieve a Cedar policy.
+//
+//	@security	Authentication
+//	@Summary	Retrieve a Cedar policy
+//	@Tags		CedarPolicy
+//	@Produce	json
+//	@Success	200	{object}	model.CedarPolicy
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/cedar-policies/{identifier} [Get]
+func CedarPolicyGet(
+	cedarPolicyManager manager.CedarPolicy,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		// Retrieve Cedar policy
+		cedarPolicy, err := cedarPolicyManager.GetRepository().Get(identifier)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve cedar policy: %v", err),
+			)
+		}
+
+		return c.JSON(cedarPolicy)
+	}
+}
+
+// Updates a Cedar policy.
+//
+//	@security	Authentication
+//	@Summary	Updates a Cedar policy
+//	@Tags		CedarPolicy
+//	@Produce	json
+//	@Param		default	body		UpdateCedarPolicyRequest	true	"Cedar policy update request"
+//	@Success	200		{object}	model.CedarPolicy
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/cedar-policies/{identifier} [Put]
+func CedarPolicyUpdate(
+	validate *validator.Validate,
+	cedarPolicyManager manager.CedarPolicy,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		request := &UpdateCedarPolicyRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		// Update Cedar policy
+		cedarPolicy, err := cedarPolicyManager.Update(identifier, request.Source)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest,
+				fmt.Errorf("cannot update cedar policy: %v", err),
+			)
+		}
+
+		return c.JSON(cedarPolicy)
+	}
+}
+
+// Deletes a Cedar policy.
+//
+//	@security	Authentication
+//	@Summary	Deletes a Cedar policy
+//	@Tags		CedarPolicy
+//	@Produce	json
+//	@Success	200	{object}	model.SuccessResponse
+//	@Failure	400	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/cedar-policies/{identifier} [Delete]
+func CedarPolicyDelete(
+	cedarPolicyManager manager.CedarPolicy,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		if err := cedarPolicyManager.Delete(identifier); err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.SuccessResponse{Success: true})
+	}
+}
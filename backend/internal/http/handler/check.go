@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/go-playground/validator/v10"
@@ -11,10 +13,11 @@ import (
 )
 
 type CheckRequestQuery struct {
-	Principal     string `json:"principal" validate:"required,slug"`
-	ResourceKind  string `json:"resource_kind" validate:"required,slug"`
-	ResourceValue string `json:"resource_value" validate:"required,slug"`
-	Action        string `json:"action" validate:"required,slug"`
+	Principal     string            `json:"principal" validate:"required,slug"`
+	ResourceKind  string            `json:"resource_kind" validate:"required,slug"`
+	ResourceValue string            `json:"resource_value" validate:"required,slug"`
+	Action        string            `json:"action" validate:"required,slug"`
+	Context       map[string]string `json:"context,omitempty"`
 }
 
 type CheckResponseQuery struct {
@@ -23,7 +26,8 @@ type CheckResponseQuery struct {
 }
 
 type CheckRequest struct {
-	Checks []*CheckRequestQuery `json:"checks" validate:"required,dive"`
+	Checks           []*CheckRequestQuery `json:"checks" validate:"required,dive"`
+	ConsistencyToken string               `json:"consistency_token,omitempty"`
 }
 
 type CheckResponse struct {
@@ -39,12 +43,15 @@ type CheckResponse struct {
 //	@Param		default	body		CheckRequest	true	"Check request"
 //	@Success	200		{object}	CheckResponse
 //	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	412		{object}	model.ErrorResponse
 //	@Failure	500		{object}	model.ErrorResponse
+//	@Failure	503		{object}	model.ErrorResponse
 //	@Router		/v1/check [Post]
 func Check(
 	logger *slog.Logger,
 	validate *validator.Validate,
 	compiledManager manager.CompiledPolicy,
+	consistency compile.Consistency,
 	dispatcher event.Dispatcher,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -60,18 +67,39 @@ func Check(
 			return c.Status(fiber.StatusBadRequest).JSON(err)
 		}
 
-		// Create policy
-		var responseChecks = make([]*CheckResponseQuery, len(request.Checks))
+		// Wait for the compilation of changes made before the given token
+		if request.ConsistencyToken != "" {
+			if err := consistency.Wait(c.UserContext(), request.ConsistencyToken); err != nil {
+				return returnError(c, consistencyErrorStatus(err), err)
+			}
+		}
+
+		// Evaluate all checks at once
+		var checks = make([]*manager.Check, len(request.Checks))
 
 		for i, check := range request.Checks {
-			isAllowed, err := compiledManager.IsAllowed(check.Principal, check.ResourceKind, check.ResourceValue, check.Action)
-			if err != nil {
-				return returnError(c, http.StatusInternalServerError, err)
+			checks[i] = &manager.Check{
+				PrincipalID:   check.Principal,
+				ResourceKind:  check.ResourceKind,
+				ResourceValue: check.ResourceValue,
+				ActionID:      check.Action,
+				Context:       check.Context,
 			}
+		}
+
+		results, err := compiledManager.IsAllowedBulk(checks)
+		if errors.Is(err, manager.ErrUnknownAction) {
+			return returnError(c, http.StatusBadRequest, err)
+		} else if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
 
+		var responseChecks = make([]*CheckResponseQuery, len(request.Checks))
+
+		for i, check := range request.Checks {
 			responseChecks[i] = &CheckResponseQuery{
 				CheckRequestQuery: check,
-				IsAllowed:         isAllowed,
+				IsAllowed:         results[i],
 			}
 		}
 
@@ -80,3 +108,16 @@ func Check(
 		})
 	}
 }
+
+func consistencyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, compile.ErrInvalidConsistencyToken):
+		return http.StatusBadRequest
+	case errors.Is(err, compile.ErrCompilationFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, compile.ErrCompilationPending):
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
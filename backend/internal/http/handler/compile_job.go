@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/http/handler/model"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Lists compile jobs.
+//
+//	@security	Authentication
+//	@Summary	Lists compile jobs with their status
+//	@Tags		CompileJob
+//	@Produce	json
+//	@Param		page	query		int		false	"page number"			example(1)
+//	@Param		size	query		int		false	"page size"				minimum(1)	maximum(1000)	default(100)
+//	@Param		filter	query		string	false	"filter on a field"		example(status:contains:failed)
+//	@Param		sort	query		string	false	"sort field and order"	example(updated_at:desc)
+//	@Success	200		{object}	[]model.CompileJob
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/compile-jobs [Get]
+func CompileJobList(
+	compileJobManager manager.CompileJob,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, size, err := paginate(c)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		// List compile jobs
+		jobs, total, err := compileJobManager.GetRepository().Find(
+			repository.WithPage(page),
+			repository.WithSize(size),
+			repository.WithFilter(httpFilterToORM(c)),
+			repository.WithSort(httpSortToORM(c)),
+		)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.NewPaginated(jobs, total, page, size))
+	}
+}
+
+// Retrieve a compile job.
+//
+//	@security	Authentication
+//	@Summary	Retrieve a compile job with its status and last error
+//	@Tags		CompileJob
+//	@Produce	json
+//	@Success	200	{object}	model.CompileJob
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/compile-jobs/{identifier} [Get]
+func CompileJobGet(
+	compileJobManager manager.CompileJob,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		// Retrieve compile job
+		job, err := compileJobManager.GetRepository().Get(identifier)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve compile job: %v", err),
+			)
+		}
+
+		return c.JSON(job)
+	}
+}
+
+// Retries a compile job.
+//
+//	@security	Authentication
+//	@Summary	Sets a compile job back to pending so it gets processed again with all its attempts
+//	@Tags		CompileJob
+//	@Produce	json
+//	@Success	200	{object}	model.CompileJob
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/compile-jobs/{identifier}/retry [Post]
+func CompileJobRetry(
+	compileJobManager manager.CompileJob,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		if _, err := compileJobManager.GetRepository().Get(identifier); err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve compile job: %v", err),
+			)
+		}
+
+		job, err := compileJobManager.Retry(identifier)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(job)
+	}
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/entity/repository"
 	"github.com/eko/authz/backend/internal/http/handler/model"
@@ -42,3 +44,44 @@ func CompiledList(
 		return c.JSON(model.NewPaginated(compiledPolicies, total, page, size))
 	}
 }
+
+// Rebuild compiled policies from scratch
+//
+//	@security	Authentication
+//	@Summary	Rebuild compiled policies from scratch, in background
+//	@Tags		Policy
+//	@Produce	json
+//	@Success	202	{object}	compile.RebuildStatus
+//	@Failure	409	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/compiled/rebuild [Post]
+func CompiledRebuild(
+	rebuilder compile.Rebuilder,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status, err := rebuilder.Start()
+		if errors.Is(err, compile.ErrRebuildRunning) {
+			return returnError(c, http.StatusConflict, err)
+		} else if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.Status(http.StatusAccepted).JSON(status)
+	}
+}
+
+// Retrieve the progress of the compiled policies rebuild
+//
+//	@security	Authentication
+//	@Summary	Retrieve the progress of the current or last compiled policies rebuild
+//	@Tags		Policy
+//	@Produce	json
+//	@Success	200	{object}	compile.RebuildStatus
+//	@Router		/v1/compiled/rebuild [Get]
+func CompiledRebuildGet(
+	rebuilder compile.Rebuilder,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(rebuilder.Status())
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/http/handler/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CreateDelegationRequest struct {
+	ID          string    `json:"id" validate:"required,slug"`
+	DelegatorID string    `json:"delegator_id" validate:"required,slug"`
+	DelegateID  string    `json:"delegate_id" validate:"required,slug,nefield=DelegatorID"`
+	Resources   []string  `json:"resources" validate:"required,dive,slug"`
+	Actions     []string  `json:"actions" validate:"required,dive,slug"`
+	ExpiresAt   time.Time `json:"expires_at" validate:"required" example:"2030-01-01T00:00:00Z"`
+}
+
+// Creates a new delegation.
+//
+//	@security	Authentication
+//	@Summary	Creates a new delegation
+//	@Tags		Delegation
+//	@Produce	json
+//	@Param		default	body		CreateDelegationRequest	true	"Delegation creation request"
+//	@Success	200		{object}	model.Delegation
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/delegations [Post]
+func DelegationCreate(
+	validate *validator.Validate,
+	delegationManager manager.Delegation,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &CreateDelegationRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		// Create delegation
+		delegation, err := delegationManager.Create(
+			request.ID,
+			request.DelegatorID,
+			request.DelegateID,
+			request.Resources,
+			request.Actions,
+			request.ExpiresAt,
+		)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(delegation)
+	}
+}
+
+// Lists delegations.
+//
+//	@security	Authentication
+//	@Summary	Lists delegations
+//	@Tags		Delegation
+//	@Produce	json
+//	@Param		page	query		int		false	"page number"			example(1)
+//	@Param		size	query		int		false	"page size"				minimum(1)	maximum(1000)	default(100)
+//	@Param		filter	query		string	false	"filter on a field"		example(delegate_id:contains:something)
+//	@Param		sort	query		string	false	"sort field and order"	example(expires_at:desc)
+//	@Success	200		{object}	[]model.Delegation
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/delegations [Get]
+func DelegationList(
+	delegationManager manager.Delegation,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, size, err := paginate(c)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		// List delegations
+		delegations, total, err := delegationManager.GetRepository().Find(
+			repository.WithPreloads("Resources", "Actions"),
+			repository.WithPage(page),
+			repository.WithSize(size),
+			repository.WithFilter(httpFilterToORM(c)),
+			repository.WithSort(httpSortToORM(c)),
+		)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.NewPaginated(delegations, total, page, size))
+	}
+}
+
+// Retrieve a delegation.
+//
+//	@security	Authentication
+//	@Summary	Retrieve a delegation
+//	@Tags		Delegation
+//	@Produce	json
+//	@Success	200	{object}	model.Delegation
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/delegations/{identifier} [Get]
+func DelegationGet(
+	delegationManager manager.Delegation,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		// Retrieve delegation
+		delegation, err := delegationManager.GetRepository().Get(
+			identifier,
+			repository.WithPreloads("Resources", "Actions"),
+		)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve delegation: %v", err),
+			)
+		}
+
+		return c.JSON(delegation)
+	}
+}
+
+// Deletes a delegation.
+//
+//	@security	Authentication
+//	@Summary	Deletes a delegation
+//	@Tags		Delegation
+//	@Produce	json
+//	@Success	200	{object}	model.SuccessResponse
+//	@Failure	400	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/delegations/{identifier} [Delete]
+func DelegationDelete(
+	delegationManager manager.Delegation,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		if err := delegationManager.Delete(identifier); err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.SuccessResponse{Success: true})
+	}
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/bundle"
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/event"
 	"github.com/eko/authz/backend/internal/helper/token"
+	"github.com/eko/authz/backend/internal/lint"
 	"github.com/eko/authz/backend/internal/oauth/client"
+	"github.com/eko/authz/backend/internal/scim"
 	"github.com/eko/authz/backend/internal/security/jwt"
 	"github.com/go-oauth2/oauth2/v4/server"
 	"github.com/go-playground/validator/v10"
@@ -15,44 +19,86 @@ import (
 )
 
 const (
-	ActionGetKey         = "action-get"
-	ActionListKey        = "action-list"
-	AuditGetKey          = "audit-get"
-	AuthAuthenticateKey  = "auth-authenticate"
-	AuthTokenNewKey      = "auth-token-new"
-	CheckKey             = "check"
-	ClientCreateKey      = "client-create"
-	ClientDeleteKey      = "client-delete"
-	ClientGetKey         = "client-get"
-	ClientListKey        = "client-list"
-	CompiledListKey      = "compiled-list"
-	OAuthAuthenticateKey = "oauth-authenticate"
-	OAuthCallbackKey     = "oauth-callback"
-	PolicyCreateKey      = "policy-create"
-	PolicyDeleteKey      = "policy-delete"
-	PolicyGetKey         = "policy-get"
-	PolicyListKey        = "policy-list"
-	PolicyUpdateKey      = "policy-update"
-	PrincipalCreateKey   = "principal-create"
-	PrincipalDeleteKey   = "principal-delete"
-	PrincipalGetKey      = "principal-get"
-	PrincipalListKey     = "principal-list"
-	PrincipalUpdateKey   = "principal-update"
-	ResourceCreateKey    = "resource-create"
-	ResourceDeleteKey    = "resource-delete"
-	ResourceGetKey       = "resource-get"
-	ResourceListKey      = "resource-list"
-	ResourceUpdateKey    = "resource-update"
-	RoleCreateKey        = "role-create"
-	RoleDeleteKey        = "role-delete"
-	RoleGetKey           = "role-get"
-	RoleListKey          = "role-list"
-	RoleUpdateKey        = "role-update"
-	StatsGetKey          = "stats-get"
-	UserCreateKey        = "user-create"
-	UserDeleteKey        = "user-delete"
-	UserGetKey           = "user-get"
-	UserListKey          = "user-list"
+	ActionGetKey            = "action-get"
+	ActionListKey           = "action-list"
+	AuditGetKey             = "audit-get"
+	AuthAuthenticateKey     = "auth-authenticate"
+	AuthTokenNewKey         = "auth-token-new"
+	BundleApplyKey          = "bundle-apply"
+	BundleExportKey         = "bundle-export"
+	BundlePlanKey           = "bundle-plan"
+	CedarPolicyCreateKey    = "cedar-policy-create"
+	CedarPolicyDeleteKey    = "cedar-policy-delete"
+	CedarPolicyGetKey       = "cedar-policy-get"
+	CedarPolicyListKey      = "cedar-policy-list"
+	CedarPolicyUpdateKey    = "cedar-policy-update"
+	CheckKey                = "check"
+	ClientCreateKey         = "client-create"
+	ClientDeleteKey         = "client-delete"
+	ClientGetKey            = "client-get"
+	ClientListKey           = "client-list"
+	CompileJobGetKey        = "compile-job-get"
+	CompileJobListKey       = "compile-job-list"
+	CompileJobRetryKey      = "compile-job-retry"
+	CompiledListKey         = "compiled-list"
+	CompiledRebuildKey      = "compiled-rebuild"
+	CompiledRebuildGetKey   = "compiled-rebuild-get"
+	DelegationCreateKey     = "delegation-create"
+	DelegationDeleteKey     = "delegation-delete"
+	DelegationGetKey        = "delegation-get"
+	DelegationListKey       = "delegation-list"
+	LintGetKey              = "lint-get"
+	OAuthAuthenticateKey    = "oauth-authenticate"
+	OAuthCallbackKey        = "oauth-callback"
+	PolicyCreateKey         = "policy-create"
+	PolicyDeleteKey         = "policy-delete"
+	PolicyGetKey            = "policy-get"
+	PolicyListKey           = "policy-list"
+	PolicyUpdateKey         = "policy-update"
+	PrincipalCreateKey      = "principal-create"
+	PrincipalDeleteKey      = "principal-delete"
+	PrincipalGetKey         = "principal-get"
+	PrincipalListKey        = "principal-list"
+	PrincipalUpdateKey      = "principal-update"
+	ResourceCreateKey       = "resource-create"
+	ResourceDeleteKey       = "resource-delete"
+	ResourceGetKey          = "resource-get"
+	ResourceKindCreateKey   = "resource-kind-create"
+	ResourceKindDeleteKey   = "resource-kind-delete"
+	ResourceKindGetKey      = "resource-kind-get"
+	ResourceKindListKey     = "resource-kind-list"
+	ResourceKindUpdateKey   = "resource-kind-update"
+	ResourceListKey         = "resource-list"
+	ResourceUpdateKey       = "resource-update"
+	ReviewCampaignCloseKey  = "review-campaign-close"
+	ReviewCampaignCreateKey = "review-campaign-create"
+	ReviewCampaignDecideKey = "review-campaign-decide"
+	ReviewCampaignDeleteKey = "review-campaign-delete"
+	ReviewCampaignGetKey    = "review-campaign-get"
+	ReviewCampaignListKey   = "review-campaign-list"
+	RoleCreateKey           = "role-create"
+	RoleDeleteKey           = "role-delete"
+	RoleGetKey              = "role-get"
+	RoleListKey             = "role-list"
+	RoleUpdateKey           = "role-update"
+	SCIMGroupCreateKey      = "scim-group-create"
+	SCIMGroupDeleteKey      = "scim-group-delete"
+	SCIMGroupGetKey         = "scim-group-get"
+	SCIMGroupListKey        = "scim-group-list"
+	SCIMGroupPatchKey       = "scim-group-patch"
+	SCIMGroupReplaceKey     = "scim-group-replace"
+	SCIMConfigGetKey        = "scim-config-get"
+	SCIMUserCreateKey       = "scim-user-create"
+	SCIMUserDeleteKey       = "scim-user-delete"
+	SCIMUserGetKey          = "scim-user-get"
+	SCIMUserListKey         = "scim-user-list"
+	SCIMUserPatchKey        = "scim-user-patch"
+	SCIMUserReplaceKey      = "scim-user-replace"
+	StatsGetKey             = "stats-get"
+	UserCreateKey           = "user-create"
+	UserDeleteKey           = "user-delete"
+	UserGetKey              = "user-get"
+	UserListKey             = "user-list"
 )
 
 type Handler fiber.Handler
@@ -66,16 +112,27 @@ func NewHandlers(
 	actionManager manager.Action,
 	auditManager manager.Audit,
 	authCfg *configs.Auth,
+	bundleManager bundle.Manager,
+	cedarPolicyManager manager.CedarPolicy,
 	clientManager manager.Client,
+	compileJobManager manager.CompileJob,
 	compiledManager manager.CompiledPolicy,
+	consistency compile.Consistency,
+	delegationManager manager.Delegation,
 	dispatcher event.Dispatcher,
+	linter lint.Linter,
 	logger *slog.Logger,
 	oauthClientManager client.Manager,
+	oauthPrincipalSyncer client.PrincipalSyncer,
 	oauthServer *server.Server,
 	policyManager manager.Policy,
 	principalManager manager.Principal,
+	rebuilder compile.Rebuilder,
 	resourceManager manager.Resource,
+	resourceKindManager manager.ResourceKind,
+	reviewCampaignManager manager.ReviewCampaign,
 	roleManager manager.Role,
+	scimManager scim.Manager,
 	statsManager manager.Stats,
 	tokenGenerator token.Generator,
 	jwtManager jwt.Manager,
@@ -83,43 +140,85 @@ func NewHandlers(
 	validate *validator.Validate,
 ) Handlers {
 	return Handlers{
-		ActionGetKey:         ActionGet(actionManager),
-		ActionListKey:        ActionList(actionManager),
-		AuditGetKey:          AuditGet(auditManager),
-		AuthAuthenticateKey:  Authenticate(validate, userManager, jwtManager),
-		AuthTokenNewKey:      adaptor.HTTPHandlerFunc(TokenNew(oauthServer)),
-		CheckKey:             Check(logger, validate, compiledManager, dispatcher),
-		ClientCreateKey:      ClientCreate(validate, clientManager, authCfg),
-		ClientDeleteKey:      ClientDelete(clientManager),
-		ClientGetKey:         ClientGet(clientManager),
-		ClientListKey:        ClientList(clientManager),
-		CompiledListKey:      CompiledList(compiledManager),
-		OAuthAuthenticateKey: OAuthAuthenticate(oauthClientManager, tokenGenerator),
-		OAuthCallbackKey:     OAuthCallback(jwtManager, oauthClientManager, principalManager),
-		PolicyCreateKey:      PolicyCreate(validate, policyManager),
-		PolicyDeleteKey:      PolicyDelete(policyManager),
-		PolicyGetKey:         PolicyGet(policyManager),
-		PolicyListKey:        PolicyList(policyManager),
-		PolicyUpdateKey:      PolicyUpdate(validate, policyManager),
-		PrincipalCreateKey:   PrincipalCreate(validate, principalManager),
-		PrincipalDeleteKey:   PrincipalDelete(principalManager),
-		PrincipalGetKey:      PrincipalGet(principalManager),
-		PrincipalListKey:     PrincipalList(principalManager),
-		PrincipalUpdateKey:   PrincipalUpdate(validate, principalManager),
-		ResourceCreateKey:    ResourceCreate(validate, resourceManager),
-		ResourceDeleteKey:    ResourceDelete(resourceManager),
-		ResourceGetKey:       ResourceGet(resourceManager),
-		ResourceListKey:      ResourceList(resourceManager),
-		ResourceUpdateKey:    ResourceUpdate(validate, resourceManager),
-		RoleCreateKey:        RoleCreate(validate, roleManager),
-		RoleDeleteKey:        RoleDelete(roleManager),
-		RoleGetKey:           RoleGet(roleManager),
-		RoleListKey:          RoleList(roleManager),
-		RoleUpdateKey:        RoleUpdate(validate, roleManager),
-		StatsGetKey:          StatsGet(statsManager),
-		UserCreateKey:        UserCreate(validate, userManager),
-		UserDeleteKey:        UserDelete(userManager),
-		UserGetKey:           UserGet(userManager),
-		UserListKey:          UserList(userManager),
+		ActionGetKey:            ActionGet(actionManager),
+		ActionListKey:           ActionList(actionManager),
+		AuditGetKey:             AuditGet(auditManager),
+		AuthAuthenticateKey:     Authenticate(validate, userManager, jwtManager),
+		AuthTokenNewKey:         adaptor.HTTPHandlerFunc(TokenNew(oauthServer)),
+		BundleApplyKey:          BundleApply(bundleManager),
+		BundleExportKey:         BundleExport(bundleManager),
+		BundlePlanKey:           BundlePlan(bundleManager),
+		CedarPolicyCreateKey:    CedarPolicyCreate(validate, cedarPolicyManager),
+		CedarPolicyDeleteKey:    CedarPolicyDelete(cedarPolicyManager),
+		CedarPolicyGetKey:       CedarPolicyGet(cedarPolicyManager),
+		CedarPolicyListKey:      CedarPolicyList(cedarPolicyManager),
+		CedarPolicyUpdateKey:    CedarPolicyUpdate(validate, cedarPolicyManager),
+		CheckKey:                Check(logger, validate, compiledManager, consistency, dispatcher),
+		ClientCreateKey:         ClientCreate(validate, clientManager, authCfg),
+		ClientDeleteKey:         ClientDelete(clientManager),
+		ClientGetKey:            ClientGet(clientManager),
+		ClientListKey:           ClientList(clientManager),
+		CompileJobGetKey:        CompileJobGet(compileJobManager),
+		CompileJobListKey:       CompileJobList(compileJobManager),
+		CompileJobRetryKey:      CompileJobRetry(compileJobManager),
+		CompiledListKey:         CompiledList(compiledManager),
+		CompiledRebuildKey:      CompiledRebuild(rebuilder),
+		CompiledRebuildGetKey:   CompiledRebuildGet(rebuilder),
+		DelegationCreateKey:     DelegationCreate(validate, delegationManager),
+		DelegationDeleteKey:     DelegationDelete(delegationManager),
+		DelegationGetKey:        DelegationGet(delegationManager),
+		DelegationListKey:       DelegationList(delegationManager),
+		LintGetKey:              LintGet(linter),
+		OAuthAuthenticateKey:    OAuthAuthenticate(oauthClientManager, tokenGenerator),
+		OAuthCallbackKey:        OAuthCallback(jwtManager, oauthClientManager, oauthPrincipalSyncer),
+		PolicyCreateKey:         PolicyCreate(validate, policyManager),
+		PolicyDeleteKey:         PolicyDelete(policyManager),
+		PolicyGetKey:            PolicyGet(policyManager),
+		PolicyListKey:           PolicyList(policyManager),
+		PolicyUpdateKey:         PolicyUpdate(validate, policyManager),
+		PrincipalCreateKey:      PrincipalCreate(validate, principalManager),
+		PrincipalDeleteKey:      PrincipalDelete(principalManager),
+		PrincipalGetKey:         PrincipalGet(principalManager),
+		PrincipalListKey:        PrincipalList(principalManager),
+		PrincipalUpdateKey:      PrincipalUpdate(validate, principalManager),
+		ResourceCreateKey:       ResourceCreate(validate, resourceManager),
+		ResourceDeleteKey:       ResourceDelete(resourceManager),
+		ResourceGetKey:          ResourceGet(resourceManager),
+		ResourceKindCreateKey:   ResourceKindCreate(validate, resourceKindManager),
+		ResourceKindDeleteKey:   ResourceKindDelete(resourceKindManager),
+		ResourceKindGetKey:      ResourceKindGet(resourceKindManager),
+		ResourceKindListKey:     ResourceKindList(resourceKindManager),
+		ResourceKindUpdateKey:   ResourceKindUpdate(validate, resourceKindManager),
+		ResourceListKey:         ResourceList(resourceManager),
+		ResourceUpdateKey:       ResourceUpdate(validate, resourceManager),
+		ReviewCampaignCloseKey:  ReviewCampaignClose(reviewCampaignManager),
+		ReviewCampaignCreateKey: ReviewCampaignCreate(validate, reviewCampaignManager),
+		ReviewCampaignDecideKey: ReviewCampaignDecide(validate, reviewCampaignManager),
+		ReviewCampaignDeleteKey: ReviewCampaignDelete(reviewCampaignManager),
+		ReviewCampaignGetKey:    ReviewCampaignGet(reviewCampaignManager),
+		ReviewCampaignListKey:   ReviewCampaignList(reviewCampaignManager),
+		RoleCreateKey:           RoleCreate(validate, roleManager),
+		RoleDeleteKey:           RoleDelete(roleManager),
+		RoleGetKey:              RoleGet(roleManager),
+		RoleListKey:             RoleList(roleManager),
+		RoleUpdateKey:           RoleUpdate(validate, roleManager),
+		SCIMGroupCreateKey:      SCIMGroupCreate(scimManager),
+		SCIMGroupDeleteKey:      SCIMGroupDelete(scimManager),
+		SCIMGroupGetKey:         SCIMGroupGet(scimManager),
+		SCIMGroupListKey:        SCIMGroupList(scimManager),
+		SCIMGroupPatchKey:       SCIMGroupPatch(scimManager),
+		SCIMGroupReplaceKey:     SCIMGroupReplace(scimManager),
+		SCIMConfigGetKey:        SCIMServiceProviderConfig(),
+		SCIMUserCreateKey:       SCIMUserCreate(scimManager),
+		SCIMUserDeleteKey:       SCIMUserDelete(scimManager),
+		SCIMUserGetKey:          SCIMUserGet(scimManager),
+		SCIMUserListKey:         SCIMUserList(scimManager),
+		SCIMUserPatchKey:        SCIMUserPatch(scimManager),
+		SCIMUserReplaceKey:      SCIMUserReplace(scimManager),
+		StatsGetKey:             StatsGet(statsManager),
+		UserCreateKey:           UserCreate(validate, userManager),
+		UserDeleteKey:           UserDelete(userManager),
+		UserGetKey:              UserGet(userManager),
+		UserListKey:             UserList(userManager),
 	}
 }
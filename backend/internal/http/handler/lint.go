@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/eko/authz/backend/internal/lint"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Lints the authorization model.
+//
+//	@security	Authentication
+//	@Summary	Lints the authorization model and reports findings
+//	@Tags		Lint
+//	@Produce	json
+//	@Success	200	{object}	lint.Report
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/lint [Get]
+func LintGet(
+	linter lint.Linter,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report, err := linter.Lint()
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(report)
+	}
+}
@@ -8,13 +8,11 @@ import (
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
-	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/helper/token"
 	"github.com/eko/authz/backend/internal/oauth/client"
 	"github.com/eko/authz/backend/internal/security/jwt"
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
 const (
@@ -72,7 +70,7 @@ func OAuthAuthenticate(
 func OAuthCallback(
 	jwtManager jwt.Manager,
 	oauthClientManager client.Manager,
-	principalManager manager.Principal,
+	principalSyncer client.PrincipalSyncer,
 ) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		ctx := c.Context()
@@ -116,27 +114,13 @@ func OAuthCallback(
 			return returnError(c, http.StatusBadRequest, err)
 		}
 
-		nameValue, err := retrieveClaim(idTokenClaims, OAuthClaimNameKey)
-		if err != nil {
+		if _, err := retrieveClaim(idTokenClaims, OAuthClaimNameKey); err != nil {
 			return returnError(c, http.StatusBadRequest, err)
 		}
 
-		// Retrieve or create principal from user email.
-		_, err = principalManager.GetRepository().Get(
-			model.UserPrincipal(emailValue),
-		)
-		if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
-			if _, err = principalManager.Create(
-				model.UserPrincipal(emailValue),
-				[]string{},
-				map[string]any{
-					"name": nameValue,
-				},
-			); err != nil {
-				return returnError(c, http.StatusInternalServerError, fmt.Errorf("unable to create principal: %v", err))
-			}
-		} else if err != nil {
-			return returnError(c, http.StatusInternalServerError, fmt.Errorf("unable to retrieve principal: %v", err))
+		// Create or update principal from user email and mapped claims.
+		if _, err := principalSyncer.Sync(model.UserPrincipal(emailValue), idTokenClaims); err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
 		}
 
 		// Generate access token.
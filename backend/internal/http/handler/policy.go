@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/eko/authz/backend/internal/entity/manager"
+	entity_model "github.com/eko/authz/backend/internal/entity/model"
 	"github.com/eko/authz/backend/internal/entity/repository"
 	"github.com/eko/authz/backend/internal/http/handler/model"
 	"github.com/go-playground/validator/v10"
@@ -13,7 +15,27 @@ import (
 	"gorm.io/gorm"
 )
 
+type PolicySettingsRequest struct {
+	Effect           string     `json:"effect" validate:"omitempty,oneof=allow deny" example:"allow"`
+	Priority         int        `json:"priority" example:"10"`
+	NotBefore        *time.Time `json:"not_before" example:"2023-12-01T00:00:00Z"`
+	NotAfter         *time.Time `json:"not_after" example:"2024-01-01T00:00:00Z"`
+	Schedule         string     `json:"schedule" example:"0 9 * * MON-FRI"`
+	ScheduleDuration string     `json:"schedule_duration" validate:"required_with=Schedule" example:"8h"`
+	ScheduleTimezone string     `json:"schedule_timezone" example:"Europe/Paris"`
+}
+
+func (r PolicySettingsRequest) options() []manager.PolicyOption {
+	return []manager.PolicyOption{
+		manager.WithEffect(entity_model.PolicyEffect(r.Effect)),
+		manager.WithPriority(r.Priority),
+		manager.WithValidity(r.NotBefore, r.NotAfter),
+		manager.WithSchedule(r.Schedule, r.ScheduleDuration, r.ScheduleTimezone),
+	}
+}
+
 type CreatePolicyRequest struct {
+	PolicySettingsRequest
 	ID             string   `json:"id" validate:"required,slug"`
 	Resources      []string `json:"resources" validate:"required,dive,slug"`
 	Actions        []string `json:"actions" validate:"required,dive,slug"`
@@ -21,6 +43,7 @@ type CreatePolicyRequest struct {
 }
 
 type UpdatePolicyRequest struct {
+	PolicySettingsRequest
 	Resources      []string `json:"resources" validate:"required,dive,slug"`
 	Actions        []string `json:"actions" validate:"required,dive,slug"`
 	AttributeRules []string `json:"attribute_rules"`
@@ -60,6 +83,7 @@ func PolicyCreate(
 			request.Resources,
 			request.Actions,
 			request.AttributeRules,
+			request.options()...,
 		)
 		if err != nil {
 			return returnError(c, http.StatusInternalServerError, err)
@@ -182,6 +206,7 @@ func PolicyUpdate(
 			request.Resources,
 			request.Actions,
 			request.AttributeRules,
+			request.options()...,
 		)
 		if err != nil {
 			return returnError(c, http.StatusInternalServerError,
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/http/handler/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CreateResourceKindRequest struct {
+	ID string `json:"id" validate:"required,slug"`
+	UpdateResourceKindRequest
+}
+
+type UpdateResourceKindRequest struct {
+	Description      string         `json:"description"`
+	Actions          []string       `json:"actions" validate:"required,min=1,dive,slug"`
+	AttributesSchema map[string]any `json:"attributes_schema" swaggertype:"object"`
+}
+
+func (r UpdateResourceKindRequest) attributesSchema() ([]byte, error) {
+	if r.AttributesSchema == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(r.AttributesSchema)
+}
+
+// Declares a new resource kind.
+//
+//	@security	Authentication
+//	@Summary	Declares a new resource kind with its allowed actions and attributes schema
+//	@Tags		ResourceKind
+//	@Produce	json
+//	@Param		default	body		CreateResourceKindRequest	true	"Resource kind creation request"
+//	@Success	200		{object}	model.ResourceKind
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/resource-kinds [Post]
+func ResourceKindCreate(
+	validate *validator.Validate,
+	resourceKindManager manager.ResourceKind,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &CreateResourceKindRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		attributesSchema, err := request.attributesSchema()
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Create resource kind
+		resourceKind, err := resourceKindManager.Create(
+			request.ID,
+			request.Description,
+			request.Actions,
+			attributesSchema,
+		)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(resourceKind)
+	}
+}
+
+// Lists resource kinds.
+//
+//	@security	Authentication
+//	@Summary	Lists resource kinds
+//	@Tags		ResourceKind
+//	@Produce	json
+//	@Param		page	query		int		false	"page number"			example(1)
+//	@Param		size	query		int		false	"page size"				minimum(1)	maximum(1000)	default(100)
+//	@Param		filter	query		string	false	"filter on a field"		example(id:contains:post)
+//	@Param		sort	query		string	false	"sort field and order"	example(id:desc)
+//	@Success	200		{object}	[]model.ResourceKind
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/resource-kinds [Get]
+func ResourceKindList(
+	resourceKindManager manager.ResourceKind,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, size, err := paginate(c)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		// List resource kinds
+		resourceKinds, total, err := resourceKindManager.GetRepository().Find(
+			repository.WithPreloads("Actions"),
+			repository.WithPage(page),
+			repository.WithSize(size),
+			repository.WithFilter(httpFilterToORM(c)),
+			repository.WithSort(httpSortToORM(c)),
+		)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.NewPaginated(resourceKinds, total, page, size))
+	}
+}
+
+// Retrieve a resource kind.
+//
+//	@security	Authentication
+//	@Summary	Retrieve a resource kind
+//	@Tags		ResourceKind
+//	@Produce	json
+//	@Success	200	{object}	model.ResourceKind
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/resource-kinds/{identifier} [Get]
+func ResourceKindGet(
+	resourceKindManager manager.ResourceKind,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		// Retrieve resource kind
+		resourceKind, err := resourceKindManager.GetRepository().Get(
+			identifier,
+			repository.WithPreloads("Actions"),
+		)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve resource kind: %v", err),
+			)
+		}
+
+		return c.JSON(resourceKind)
+	}
+}
+
+// Updates a resource kind.
+//
+//	@security	Authentication
+//	@Summary	Updates a resource kind
+//	@Tags		ResourceKind
+//	@Produce	json
+//	@Param		default	body		UpdateResourceKindRequest	true	"Resource kind update request"
+//	@Success	200		{object}	model.ResourceKind
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/resource-kinds/{identifier} [Put]
+func ResourceKindUpdate(
+	validate *validator.Validate,
+	resourceKindManager manager.ResourceKind,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		request := &UpdateResourceKindRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		attributesSchema, err := request.attributesSchema()
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Update resource kind
+		resourceKind, err := resourceKindManager.Update(
+			identifier,
+			request.Description,
+			request.Actions,
+			attributesSchema,
+		)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest,
+				fmt.Errorf("cannot update resource kind: %v", err),
+			)
+		}
+
+		return c.JSON(resourceKind)
+	}
+}
+
+// Deletes a resource kind.
+//
+//	@security	Authentication
+//	@Summary	Deletes a resource kind, its resources are not restricted anymore
+//	@Tags		ResourceKind
+//	@Produce	json
+//	@Success	200	{object}	model.SuccessResponse
+//	@Failure	400	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/resource-kinds/{identifier} [Delete]
+func ResourceKindDelete(
+	resourceKindManager manager.ResourceKind,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		if err := resourceKindManager.Delete(identifier); err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.SuccessResponse{Success: true})
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eko/authz/backend/internal/entity/manager"
+	entity_model "github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/http/handler/model"
+	"github.com/eko/authz/backend/internal/http/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CreateReviewCampaignRequest struct {
+	ID          string    `json:"id" validate:"required,slug"`
+	Description string    `json:"description"`
+	Reviewers   []string  `json:"reviewers" validate:"required,dive,slug"`
+	Roles       []string  `json:"roles" validate:"dive,slug"`
+	Deadline    time.Time `json:"deadline" validate:"required" example:"2030-01-01T00:00:00Z"`
+	AutoRevoke  bool      `json:"auto_revoke"`
+}
+
+type DecideReviewRequest struct {
+	PrincipalID string `json:"principal_id" validate:"required,slug"`
+	RoleID      string `json:"role_id" validate:"required,slug"`
+	Decision    string `json:"decision" validate:"required,oneof=confirmed revoked" example:"confirmed"`
+	Comment     string `json:"comment"`
+}
+
+// Creates a new access review campaign.
+//
+//	@security	Authentication
+//	@Summary	Creates a new access review campaign from the roles currently given to principals
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Param		default	body		CreateReviewCampaignRequest	true	"Review campaign creation request"
+//	@Success	200		{object}	model.ReviewCampaign
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns [Post]
+func ReviewCampaignCreate(
+	validate *validator.Validate,
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &CreateReviewCampaignRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		// Create review campaign
+		campaign, err := reviewManager.Create(
+			request.ID,
+			request.Description,
+			request.Reviewers,
+			request.Roles,
+			request.Deadline,
+			request.AutoRevoke,
+		)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(campaign)
+	}
+}
+
+// Lists access review campaigns.
+//
+//	@security	Authentication
+//	@Summary	Lists access review campaigns
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Param		page	query		int		false	"page number"			example(1)
+//	@Param		size	query		int		false	"page size"				minimum(1)	maximum(1000)	default(100)
+//	@Param		filter	query		string	false	"filter on a field"		example(status:contains:open)
+//	@Param		sort	query		string	false	"sort field and order"	example(deadline:desc)
+//	@Success	200		{object}	[]model.ReviewCampaign
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns [Get]
+func ReviewCampaignList(
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, size, err := paginate(c)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		// List review campaigns
+		campaigns, total, err := reviewManager.GetRepository().Find(
+			repository.WithPage(page),
+			repository.WithSize(size),
+			repository.WithFilter(httpFilterToORM(c)),
+			repository.WithSort(httpSortToORM(c)),
+		)
+		if err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.NewPaginated(campaigns, total, page, size))
+	}
+}
+
+// Retrieve an access review campaign.
+//
+//	@security	Authentication
+//	@Summary	Retrieve an access review campaign with its items
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Success	200	{object}	model.ReviewCampaign
+//	@Failure	404	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns/{identifier} [Get]
+func ReviewCampaignGet(
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		// Retrieve review campaign
+		campaign, err := reviewManager.GetRepository().Get(
+			identifier,
+			repository.WithPreloads("Items"),
+		)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				statusCode = http.StatusNotFound
+			}
+
+			return returnError(c, statusCode,
+				fmt.Errorf("cannot retrieve review campaign: %v", err),
+			)
+		}
+
+		return c.JSON(campaign)
+	}
+}
+
+// Confirms or revokes a role given to a principal.
+//
+//	@security	Authentication
+//	@Summary	Confirms or revokes a role given to a principal, as a reviewer of the campaign
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Param		default	body		DecideReviewRequest	true	"Review decision request"
+//	@Success	200		{object}	model.ReviewItem
+//	@Failure	400		{object}	model.ErrorResponse
+//	@Failure	500		{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns/{identifier}/decisions [Post]
+func ReviewCampaignDecide(
+	validate *validator.Validate,
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		request := &DecideReviewRequest{}
+
+		// Parse request body
+		if err := c.BodyParser(request); err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		// Validate body
+		if err := validateStruct(validate, request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(err)
+		}
+
+		userID := c.UserContext().Value(middleware.UserIdentifierKey).(string)
+
+		// Record decision
+		item, err := reviewManager.Decide(
+			identifier,
+			entity_model.UserPrincipal(userID),
+			request.PrincipalID,
+			request.RoleID,
+			entity_model.ReviewDecision(request.Decision),
+			request.Comment,
+		)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(item)
+	}
+}
+
+// Closes an access review campaign.
+//
+//	@security	Authentication
+//	@Summary	Closes an access review campaign before its deadline
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Success	200	{object}	model.ReviewCampaign
+//	@Failure	400	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns/{identifier}/close [Post]
+func ReviewCampaignClose(
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		campaign, err := reviewManager.Close(identifier)
+		if err != nil {
+			return returnError(c, http.StatusBadRequest, err)
+		}
+
+		return c.JSON(campaign)
+	}
+}
+
+// Deletes an access review campaign.
+//
+//	@security	Authentication
+//	@Summary	Deletes an access review campaign
+//	@Tags		ReviewCampaign
+//	@Produce	json
+//	@Success	200	{object}	model.SuccessResponse
+//	@Failure	400	{object}	model.ErrorResponse
+//	@Failure	500	{object}	model.ErrorResponse
+//	@Router		/v1/review-campaigns/{identifier} [Delete]
+func ReviewCampaignDelete(
+	reviewManager manager.ReviewCampaign,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		identifier := c.Params("identifier")
+
+		if err := reviewManager.Delete(identifier); err != nil {
+			return returnError(c, http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(model.SuccessResponse{Success: true})
+	}
+}
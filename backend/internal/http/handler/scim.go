@@ -0,0 +1,426 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/eko/authz/backend/internal/scim"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Retrieves the SCIM service provider configuration.
+//
+//	@security	Authentication
+//	@Summary	Retrieves the SCIM service provider configuration
+//	@Tags		SCIM
+//	@Produce	json
+//	@Success	200	{object}	scim.ServiceProviderConfig
+//	@Router		/scim/v2/ServiceProviderConfig [Get]
+func SCIMServiceProviderConfig() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return scimResponse(c, http.StatusOK, scim.NewServiceProviderConfig())
+	}
+}
+
+// Provisions a new user.
+//
+//	@security	Authentication
+//	@Summary	Provisions a new user and its principal
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.User	true	"SCIM user"
+//	@Success	201		{object}	scim.User
+//	@Failure	400		{object}	scim.Error
+//	@Failure	409		{object}	scim.Error
+//	@Router		/scim/v2/Users [Post]
+func SCIMUserCreate(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.User{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		user, err := scimManager.CreateUser(request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusCreated, user)
+	}
+}
+
+// Lists provisioned users.
+//
+//	@security	Authentication
+//	@Summary	Lists users, optionally filtered
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		filter		query		string	false	"SCIM filter"							example(userName eq "john.doe@acme.tld")
+//	@Param		startIndex	query		int		false	"1-based index of the first result"	example(1)
+//	@Param		count		query		int		false	"maximum number of results"			maximum(1000)
+//	@Success	200			{object}	scim.ListResponse
+//	@Failure	400			{object}	scim.Error
+//	@Router		/scim/v2/Users [Get]
+func SCIMUserList(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter, startIndex, count, err := scimListParameters(c)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		users, err := scimManager.ListUsers(filter)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, scim.NewListResponse(users, startIndex, count))
+	}
+}
+
+// Retrieves a provisioned user.
+//
+//	@security	Authentication
+//	@Summary	Retrieves a user
+//	@Tags		SCIM
+//	@Produce	json
+//	@Success	200	{object}	scim.User
+//	@Failure	404	{object}	scim.Error
+//	@Router		/scim/v2/Users/{identifier} [Get]
+func SCIMUserGet(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := scimManager.GetUser(c.Params("identifier"))
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, user)
+	}
+}
+
+// Replaces a provisioned user.
+//
+//	@security	Authentication
+//	@Summary	Replaces a user, deactivated users lose their roles
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.User	true	"SCIM user"
+//	@Success	200		{object}	scim.User
+//	@Failure	400		{object}	scim.Error
+//	@Failure	404		{object}	scim.Error
+//	@Router		/scim/v2/Users/{identifier} [Put]
+func SCIMUserReplace(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.User{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		user, err := scimManager.ReplaceUser(c.Params("identifier"), request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, user)
+	}
+}
+
+// Patches a provisioned user.
+//
+//	@security	Authentication
+//	@Summary	Patches a user, deactivated users lose their roles
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.PatchRequest	true	"SCIM patch request"
+//	@Success	200		{object}	scim.User
+//	@Failure	400		{object}	scim.Error
+//	@Failure	404		{object}	scim.Error
+//	@Router		/scim/v2/Users/{identifier} [Patch]
+func SCIMUserPatch(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.PatchRequest{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		user, err := scimManager.PatchUser(c.Params("identifier"), request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, user)
+	}
+}
+
+// Deletes a provisioned user.
+//
+//	@security	Authentication
+//	@Summary	Deletes a user and its principal
+//	@Tags		SCIM
+//	@Produce	json
+//	@Success	204
+//	@Failure	404	{object}	scim.Error
+//	@Router		/scim/v2/Users/{identifier} [Delete]
+func SCIMUserDelete(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := scimManager.DeleteUser(c.Params("identifier")); err != nil {
+			return scimError(c, err)
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	}
+}
+
+// Provisions a group.
+//
+//	@security	Authentication
+//	@Summary	Provisions the members of an existing role
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.Group	true	"SCIM group"
+//	@Success	201		{object}	scim.Group
+//	@Failure	400		{object}	scim.Error
+//	@Router		/scim/v2/Groups [Post]
+func SCIMGroupCreate(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.Group{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		group, err := scimManager.CreateGroup(request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusCreated, group)
+	}
+}
+
+// Lists provisioned groups.
+//
+//	@security	Authentication
+//	@Summary	Lists groups (roles), optionally filtered
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		filter		query		string	false	"SCIM filter"							example(displayName eq "developer")
+//	@Param		startIndex	query		int		false	"1-based index of the first result"	example(1)
+//	@Param		count		query		int		false	"maximum number of results"			maximum(1000)
+//	@Success	200			{object}	scim.ListResponse
+//	@Failure	400			{object}	scim.Error
+//	@Router		/scim/v2/Groups [Get]
+func SCIMGroupList(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter, startIndex, count, err := scimListParameters(c)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		groups, err := scimManager.ListGroups(filter)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, scim.NewListResponse(groups, startIndex, count))
+	}
+}
+
+// Retrieves a provisioned group.
+//
+//	@security	Authentication
+//	@Summary	Retrieves a group (role) and its user members
+//	@Tags		SCIM
+//	@Produce	json
+//	@Success	200	{object}	scim.Group
+//	@Failure	404	{object}	scim.Error
+//	@Router		/scim/v2/Groups/{identifier} [Get]
+func SCIMGroupGet(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		group, err := scimManager.GetGroup(c.Params("identifier"))
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, group)
+	}
+}
+
+// Replaces a provisioned group.
+//
+//	@security	Authentication
+//	@Summary	Replaces the members of a group (role)
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.Group	true	"SCIM group"
+//	@Success	200		{object}	scim.Group
+//	@Failure	400		{object}	scim.Error
+//	@Failure	404		{object}	scim.Error
+//	@Router		/scim/v2/Groups/{identifier} [Put]
+func SCIMGroupReplace(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.Group{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		group, err := scimManager.ReplaceGroup(c.Params("identifier"), request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, group)
+	}
+}
+
+// Patches a provisioned group.
+//
+//	@security	Authentication
+//	@Summary	Adds or removes members of a group (role)
+//	@Tags		SCIM
+//	@Produce	json
+//	@Param		default	body		scim.PatchRequest	true	"SCIM patch request"
+//	@Success	200		{object}	scim.Group
+//	@Failure	400		{object}	scim.Error
+//	@Failure	404		{object}	scim.Error
+//	@Router		/scim/v2/Groups/{identifier} [Patch]
+func SCIMGroupPatch(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		request := &scim.PatchRequest{}
+
+		if err := scimParseBody(c, request); err != nil {
+			return scimError(c, err)
+		}
+
+		group, err := scimManager.PatchGroup(c.Params("identifier"), request)
+		if err != nil {
+			return scimError(c, err)
+		}
+
+		return scimResponse(c, http.StatusOK, group)
+	}
+}
+
+// Deletes a provisioned group.
+//
+//	@security	Authentication
+//	@Summary	Removes all members of a group, the role itself is kept
+//	@Tags		SCIM
+//	@Produce	json
+//	@Success	204
+//	@Failure	404	{object}	scim.Error
+//	@Router		/scim/v2/Groups/{identifier} [Delete]
+func SCIMGroupDelete(
+	scimManager scim.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := scimManager.DeleteGroup(c.Params("identifier")); err != nil {
+			return scimError(c, err)
+		}
+
+		return c.SendStatus(http.StatusNoContent)
+	}
+}
+
+// scimParseBody decodes the request body, whatever its content type as SCIM
+// clients send application/scim+json.
+func scimParseBody(c *fiber.Ctx, request any) error {
+	if err := json.Unmarshal(c.Body(), request); err != nil {
+		return fmt.Errorf("%w: unable to decode request body: %v", scim.ErrInvalidValue, err)
+	}
+
+	return nil
+}
+
+func scimListParameters(c *fiber.Ctx) (*scim.Filter, int, int, error) {
+	filter, err := scim.ParseFilter(c.Query("filter"))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	startIndex, err := strconv.Atoi(c.Query("startIndex", "1"))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: startIndex must be an integer", scim.ErrInvalidValue)
+	}
+
+	count, err := strconv.Atoi(c.Query("count", strconv.Itoa(scim.MaxResults)))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: count must be an integer", scim.ErrInvalidValue)
+	}
+
+	if count < 0 {
+		count = 0
+	} else if count > scim.MaxResults {
+		count = scim.MaxResults
+	}
+
+	return filter, startIndex, count, nil
+}
+
+func scimResponse(c *fiber.Ctx, statusCode int, response any) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, scim.ContentType)
+
+	return c.Status(statusCode).Send(body)
+}
+
+func scimError(c *fiber.Ctx, err error) error {
+	var (
+		statusCode = http.StatusInternalServerError
+		scimType   string
+	)
+
+	switch {
+	case errors.Is(err, scim.ErrNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, scim.ErrUniqueness):
+		statusCode, scimType = http.StatusConflict, "uniqueness"
+	case errors.Is(err, scim.ErrInvalidFilter):
+		statusCode, scimType = http.StatusBadRequest, "invalidFilter"
+	case errors.Is(err, scim.ErrInvalidPath):
+		statusCode, scimType = http.StatusBadRequest, "invalidPath"
+	case errors.Is(err, scim.ErrInvalidValue):
+		statusCode, scimType = http.StatusBadRequest, "invalidValue"
+	case errors.Is(err, scim.ErrMutability):
+		statusCode, scimType = http.StatusBadRequest, "mutability"
+	}
+
+	return scimResponse(c, statusCode, &scim.Error{
+		Schemas:  []string{scim.SchemaError},
+		Status:   strconv.Itoa(statusCode),
+		ScimType: scimType,
+		Detail:   err.Error(),
+	})
+}
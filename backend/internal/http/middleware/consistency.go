@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/gofiber/fiber/v2"
+)
+
+const ConsistencyTokenHeader = "X-Authz-Consistency-Token"
+
+// ConsistencyToken returns a consistency token on successful writes so that
+// clients can then check with an up-to-date compilation. The token covers the
+// changes made since the request has been received.
+func ConsistencyToken(consistency compile.Consistency) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodGet {
+			return c.Next()
+		}
+
+		since, err := consistency.Revision()
+		if err != nil {
+			return err
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() >= fiber.StatusBadRequest {
+			return nil
+		}
+
+		until, err := consistency.Revision()
+		if err != nil {
+			return err
+		}
+
+		c.Set(ConsistencyTokenHeader, compile.FormatConsistencyToken(since, until))
+
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsistencyToken(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	consistency := compile.NewMockConsistency(ctrl)
+	gomock.InOrder(
+		consistency.EXPECT().Revision().Return(int64(10), nil),
+		consistency.EXPECT().Revision().Return(int64(12), nil),
+	)
+
+	app := fiber.New()
+	app.Use(ConsistencyToken(consistency))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.JSON(map[string]any{"success": true})
+	})
+
+	// When
+	response, err := app.Test(httptest.NewRequest("POST", "/", nil))
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(fiber.StatusOK, response.StatusCode)
+	assert.Equal(compile.FormatConsistencyToken(10, 12), response.Header.Get(ConsistencyTokenHeader))
+}
+
+func TestConsistencyToken_WhenNotAWrite(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	consistency := compile.NewMockConsistency(ctrl)
+	consistency.EXPECT().Revision().Return(int64(10), nil)
+
+	app := fiber.New()
+	app.Use(ConsistencyToken(consistency))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.JSON(map[string]any{"success": true})
+	})
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusBadRequest).JSON(map[string]any{"success": false})
+	})
+
+	// When
+	getResponse, getErr := app.Test(httptest.NewRequest("GET", "/", nil))
+	postResponse, postErr := app.Test(httptest.NewRequest("POST", "/", nil))
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(getErr)
+	assert.Empty(getResponse.Header.Get(ConsistencyTokenHeader))
+
+	assert.Nil(postErr)
+	assert.Equal(fiber.StatusBadRequest, postResponse.StatusCode)
+	assert.Empty(postResponse.Header.Get(ConsistencyTokenHeader))
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/compile"
 	"github.com/eko/authz/backend/internal/entity/manager"
 	"github.com/eko/authz/backend/internal/security/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -10,6 +12,10 @@ import (
 const (
 	AuthenticationKey = "authentication"
 	AuthorizationKey  = "authorization"
+
+	ConsistencyTokenKey = "consistency-token"
+
+	SCIMAuthenticationKey = "scim-authentication"
 )
 
 type Middlewares map[string]fiber.Handler
@@ -19,12 +25,17 @@ func (m Middlewares) Get(name string) fiber.Handler {
 }
 
 func NewMiddlewares(
+	scimCfg *configs.SCIM,
 	logger *slog.Logger,
+	clientManager manager.Client,
 	compiledManager manager.CompiledPolicy,
+	consistency compile.Consistency,
 	tokenManager jwt.Manager,
 ) Middlewares {
 	return Middlewares{
-		AuthenticationKey: Authentication(logger, tokenManager),
-		AuthorizationKey:  Authorization(logger, compiledManager),
+		AuthenticationKey:     Authentication(logger, tokenManager),
+		AuthorizationKey:      Authorization(logger, compiledManager),
+		ConsistencyTokenKey:   ConsistencyToken(consistency),
+		SCIMAuthenticationKey: SCIMAuthentication(scimCfg, logger, clientManager, tokenManager),
 	}
 }
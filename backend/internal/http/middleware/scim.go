@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/scim"
+	"github.com/eko/authz/backend/internal/security/jwt"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/exp/slog"
+)
+
+// SCIMAuthentication only allows the dedicated SCIM client, authenticated
+// either with an access token issued to it or with its secret as a bearer
+// token, as most identity providers expect a long-lived token.
+func SCIMAuthentication(
+	cfg *configs.SCIM,
+	logger *slog.Logger,
+	clientManager manager.Client,
+	tokenManager jwt.Manager,
+) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if cfg.ClientID == "" {
+			return scimUnauthorized(c, fiber.StatusNotFound, "SCIM provisioning is disabled")
+		}
+
+		token, found := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+		if !found || token == "" {
+			return scimUnauthorized(c, fiber.StatusUnauthorized, "unauthorized")
+		}
+
+		if claims, err := tokenManager.Parse(token); err == nil {
+			if claims.Subject != cfg.ClientID {
+				return scimUnauthorized(c, fiber.StatusUnauthorized, "token was not issued to the SCIM client")
+			}
+
+			return c.Next()
+		}
+
+		client, err := clientManager.GetRepository().Get(cfg.ClientID)
+		if err != nil {
+			logger.Error("unable to retrieve SCIM client", err, slog.String("client_id", cfg.ClientID))
+
+			return scimUnauthorized(c, fiber.StatusUnauthorized, "unauthorized")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(client.Secret), []byte(token)) != 1 {
+			return scimUnauthorized(c, fiber.StatusUnauthorized, "unauthorized")
+		}
+
+		return c.Next()
+	}
+}
+
+func scimUnauthorized(c *fiber.Ctx, statusCode int, detail string) error {
+	body, err := json.Marshal(&scim.Error{
+		Schemas: []string{scim.SchemaError},
+		Status:  strconv.Itoa(statusCode),
+		Detail:  detail,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, scim.ContentType)
+
+	return c.Status(statusCode).Send(body)
+}
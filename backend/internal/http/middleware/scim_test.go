@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/internal/security/jwt"
+	"github.com/gofiber/fiber/v2"
+	lib_jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestSCIMAuthentication_WhenDisabled(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+
+	clientManager := manager.NewMockClient(ctrl)
+	jwtManager := jwt.NewMockManager(ctrl)
+
+	app := fiber.New()
+	app.Use(SCIMAuthentication(&configs.SCIM{}, logger, clientManager, jwtManager))
+
+	// When
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("Authorization", "Bearer token-123")
+
+	response, err := app.Test(req)
+	assert.Nil(t, err)
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, "application/scim+json", response.Header.Get("Content-Type"))
+	assert.Equal(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"404","detail":"SCIM provisioning is disabled"}`, string(bodyBytes))
+}
+
+func TestSCIMAuthentication_WhenTokenIssuedToAnotherClient(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+
+	clientManager := manager.NewMockClient(ctrl)
+
+	jwtManager := jwt.NewMockManager(ctrl)
+	jwtManager.EXPECT().Parse("token-123").Return(&jwt.Claims{
+		RegisteredClaims: lib_jwt.RegisteredClaims{
+			Subject: "admin",
+		},
+	}, nil)
+
+	app := fiber.New()
+	app.Use(SCIMAuthentication(&configs.SCIM{ClientID: "scim-client"}, logger, clientManager, jwtManager))
+
+	// When
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("Authorization", "Bearer token-123")
+
+	response, err := app.Test(req)
+	assert.Nil(t, err)
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Equal(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"401","detail":"token was not issued to the SCIM client"}`, string(bodyBytes))
+}
+
+func TestSCIMAuthentication_WhenValidToken(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	logger := slog.New(log.NewNopHandler())
+
+	clientManager := manager.NewMockClient(ctrl)
+
+	jwtManager := jwt.NewMockManager(ctrl)
+	jwtManager.EXPECT().Parse("token-123").Return(&jwt.Claims{
+		RegisteredClaims: lib_jwt.RegisteredClaims{
+			Subject: "scim-client",
+		},
+	}, nil)
+
+	app := fiber.New()
+	app.Use(SCIMAuthentication(&configs.SCIM{ClientID: "scim-client"}, logger, clientManager, jwtManager))
+	app.Get("/", func(c *fiber.Ctx) error {
+		_ = c.JSON(map[string]any{"success": true})
+		return nil
+	})
+
+	// When
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("Authorization", "Bearer token-123")
+
+	response, err := app.Test(req)
+	assert.Nil(t, err)
+
+	bodyBytes, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	// Then
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `{"success":true}`, string(bodyBytes))
+}
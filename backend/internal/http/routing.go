@@ -50,10 +50,17 @@ func (s *Server) setRoutes() {
 		base.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 
 		// Authz resources
-		authenticated := base.Use(s.middlewares.Get(middleware.AuthenticationKey))
+		authenticated := base.Use(
+			s.middlewares.Get(middleware.AuthenticationKey),
+		)
 
 		authenticated.Post("/check", s.handlers.Get(handler.CheckKey))
 
+		// Registered after checks, which are not writes.
+		authenticated.Use(
+			s.middlewares.Get(middleware.ConsistencyTokenKey),
+		)
+
 		actions := authenticated.Group("/actions")
 		actions.Get("", s.authorized("authz.actions", "list", s.handlers.Get(handler.ActionListKey))...)
 		actions.Get("/:identifier", s.authorized("authz.actions", "get", s.handlers.Get(handler.ActionGetKey))...)
@@ -61,14 +68,42 @@ func (s *Server) setRoutes() {
 		audits := authenticated.Group("/audits")
 		audits.Get("", s.authorized("authz.audits", "get", s.handlers.Get(handler.AuditGetKey))...)
 
+		bundles := authenticated.Group("/bundle")
+		bundles.Get("", s.authorized("authz.bundles", "get", s.handlers.Get(handler.BundleExportKey))...)
+		bundles.Post("/plan", s.authorized("authz.bundles", "plan", s.handlers.Get(handler.BundlePlanKey))...)
+		bundles.Post("/apply", s.authorized("authz.bundles", "apply", s.handlers.Get(handler.BundleApplyKey))...)
+
+		cedarPolicies := authenticated.Group("/cedar-policies")
+		cedarPolicies.Post("", s.authorized("authz.cedar-policies", "create", s.handlers.Get(handler.CedarPolicyCreateKey))...)
+		cedarPolicies.Get("", s.authorized("authz.cedar-policies", "list", s.handlers.Get(handler.CedarPolicyListKey))...)
+		cedarPolicies.Get("/:identifier", s.authorized("authz.cedar-policies", "get", s.handlers.Get(handler.CedarPolicyGetKey))...)
+		cedarPolicies.Put("/:identifier", s.authorized("authz.cedar-policies", "update", s.handlers.Get(handler.CedarPolicyUpdateKey))...)
+		cedarPolicies.Delete("/:identifier", s.authorized("authz.cedar-policies", "delete", s.handlers.Get(handler.CedarPolicyDeleteKey))...)
+
 		clients := authenticated.Group("/clients")
 		clients.Post("", s.authorized("authz.clients", "create", s.handlers.Get(handler.ClientCreateKey))...)
 		clients.Get("", s.authorized("authz.clients", "list", s.handlers.Get(handler.ClientListKey))...)
 		clients.Get("/:identifier", s.authorized("authz.clients", "get", s.handlers.Get(handler.ClientGetKey))...)
 		clients.Delete("/:identifier", s.authorized("authz.clients", "delete", s.handlers.Get(handler.ClientDeleteKey))...)
 
+		compileJobs := authenticated.Group("/compile-jobs")
+		compileJobs.Get("", s.authorized("authz.compile-jobs", "list", s.handlers.Get(handler.CompileJobListKey))...)
+		compileJobs.Get("/:identifier", s.authorized("authz.compile-jobs", "get", s.handlers.Get(handler.CompileJobGetKey))...)
+		compileJobs.Post("/:identifier/retry", s.authorized("authz.compile-jobs", "retry", s.handlers.Get(handler.CompileJobRetryKey))...)
+
 		compiled := authenticated.Group("/compiled")
 		compiled.Get("", s.authorized("authz.compiled", "list", s.handlers.Get(handler.CompiledListKey))...)
+		compiled.Post("/rebuild", s.authorized("authz.compiled", "rebuild", s.handlers.Get(handler.CompiledRebuildKey))...)
+		compiled.Get("/rebuild", s.authorized("authz.compiled", "rebuild", s.handlers.Get(handler.CompiledRebuildGetKey))...)
+
+		delegations := authenticated.Group("/delegations")
+		delegations.Post("", s.authorized("authz.delegations", "create", s.handlers.Get(handler.DelegationCreateKey))...)
+		delegations.Get("", s.authorized("authz.delegations", "list", s.handlers.Get(handler.DelegationListKey))...)
+		delegations.Get("/:identifier", s.authorized("authz.delegations", "get", s.handlers.Get(handler.DelegationGetKey))...)
+		delegations.Delete("/:identifier", s.authorized("authz.delegations", "delete", s.handlers.Get(handler.DelegationDeleteKey))...)
+
+		lint := authenticated.Group("/lint")
+		lint.Get("", s.authorized("authz.lint", "get", s.handlers.Get(handler.LintGetKey))...)
 
 		policies := authenticated.Group("/policies")
 		policies.Post("", s.authorized("authz.policies", "create", s.handlers.Get(handler.PolicyCreateKey))...)
@@ -91,6 +126,21 @@ func (s *Server) setRoutes() {
 		resources.Delete("/:identifier", s.authorized("authz.resources", "delete", s.handlers.Get(handler.ResourceDeleteKey))...)
 		resources.Put("/:identifier", s.authorized("authz.resources", "update", s.handlers.Get(handler.ResourceUpdateKey))...)
 
+		resourceKinds := authenticated.Group("/resource-kinds")
+		resourceKinds.Post("", s.authorized("authz.resource-kinds", "create", s.handlers.Get(handler.ResourceKindCreateKey))...)
+		resourceKinds.Get("", s.authorized("authz.resource-kinds", "list", s.handlers.Get(handler.ResourceKindListKey))...)
+		resourceKinds.Get("/:identifier", s.authorized("authz.resource-kinds", "get", s.handlers.Get(handler.ResourceKindGetKey))...)
+		resourceKinds.Delete("/:identifier", s.authorized("authz.resource-kinds", "delete", s.handlers.Get(handler.ResourceKindDeleteKey))...)
+		resourceKinds.Put("/:identifier", s.authorized("authz.resource-kinds", "update", s.handlers.Get(handler.ResourceKindUpdateKey))...)
+
+		reviewCampaigns := authenticated.Group("/review-campaigns")
+		reviewCampaigns.Post("", s.authorized("authz.review-campaigns", "create", s.handlers.Get(handler.ReviewCampaignCreateKey))...)
+		reviewCampaigns.Get("", s.authorized("authz.review-campaigns", "list", s.handlers.Get(handler.ReviewCampaignListKey))...)
+		reviewCampaigns.Get("/:identifier", s.authorized("authz.review-campaigns", "get", s.handlers.Get(handler.ReviewCampaignGetKey))...)
+		reviewCampaigns.Delete("/:identifier", s.authorized("authz.review-campaigns", "delete", s.handlers.Get(handler.ReviewCampaignDeleteKey))...)
+		reviewCampaigns.Post("/:identifier/decisions", s.authorized("authz.review-campaigns", "decide", s.handlers.Get(handler.ReviewCampaignDecideKey))...)
+		reviewCampaigns.Post("/:identifier/close", s.authorized("authz.review-campaigns", "close", s.handlers.Get(handler.ReviewCampaignCloseKey))...)
+
 		role := authenticated.Group("/roles")
 		role.Post("", s.authorized("authz.roles", "create", s.handlers.Get(handler.RoleCreateKey))...)
 		role.Get("", s.authorized("authz.roles", "list", s.handlers.Get(handler.RoleListKey))...)
@@ -107,6 +157,32 @@ func (s *Server) setRoutes() {
 		users.Get("/:identifier", s.authorized("authz.users", "get", s.handlers.Get(handler.UserGetKey))...)
 		users.Delete("/:identifier", s.authorized("authz.users", "delete", s.handlers.Get(handler.UserDeleteKey))...)
 	}
+
+	// SCIM provisioning, only allowed to the dedicated SCIM client
+	scim := s.app.Group(
+		"/scim/v2",
+		s.middlewares.Get(middleware.SCIMAuthenticationKey),
+		s.middlewares.Get(middleware.ConsistencyTokenKey),
+	)
+	{
+		scim.Get("/ServiceProviderConfig", s.handlers.Get(handler.SCIMConfigGetKey))
+
+		scimUsers := scim.Group("/Users")
+		scimUsers.Post("", s.handlers.Get(handler.SCIMUserCreateKey))
+		scimUsers.Get("", s.handlers.Get(handler.SCIMUserListKey))
+		scimUsers.Get("/:identifier", s.handlers.Get(handler.SCIMUserGetKey))
+		scimUsers.Put("/:identifier", s.handlers.Get(handler.SCIMUserReplaceKey))
+		scimUsers.Patch("/:identifier", s.handlers.Get(handler.SCIMUserPatchKey))
+		scimUsers.Delete("/:identifier", s.handlers.Get(handler.SCIMUserDeleteKey))
+
+		scimGroups := scim.Group("/Groups")
+		scimGroups.Post("", s.handlers.Get(handler.SCIMGroupCreateKey))
+		scimGroups.Get("", s.handlers.Get(handler.SCIMGroupListKey))
+		scimGroups.Get("/:identifier", s.handlers.Get(handler.SCIMGroupGetKey))
+		scimGroups.Put("/:identifier", s.handlers.Get(handler.SCIMGroupReplaceKey))
+		scimGroups.Patch("/:identifier", s.handlers.Get(handler.SCIMGroupPatchKey))
+		scimGroups.Delete("/:identifier", s.handlers.Get(handler.SCIMGroupDeleteKey))
+	}
 }
 
 func (s *Server) authorized(resourceKind string, action string, handler fiber.Handler) []fiber.Handler {
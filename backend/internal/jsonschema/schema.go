@@ -0,0 +1,483 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	TypeArray   = "array"
+	TypeBoolean = "boolean"
+	TypeInteger = "integer"
+	TypeNull    = "null"
+	TypeNumber  = "number"
+	TypeObject  = "object"
+	TypeString  = "string"
+)
+
+var (
+	// annotationKeywords are accepted but have no effect on validation.
+	annotationKeywords = map[string]bool{
+		"$comment":    true,
+		"$id":         true,
+		"$schema":     true,
+		"default":     true,
+		"description": true,
+		"examples":    true,
+		"title":       true,
+	}
+
+	types = map[string]bool{
+		TypeArray:   true,
+		TypeBoolean: true,
+		TypeInteger: true,
+		TypeNull:    true,
+		TypeNumber:  true,
+		TypeObject:  true,
+		TypeString:  true,
+	}
+)
+
+// Schema is a compiled JSON Schema supporting a subset of the specification:
+// type, enum, const, properties, required, additionalProperties, items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum and exclusiveMaximum.
+//
+// As attributes are stored as strings, a string holding a number or a boolean
+// is valid against the "number", "integer" and "boolean" types.
+type Schema struct {
+	Types                []string
+	Enum                 []any
+	Const                *any
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema
+	NoAdditional         bool
+	Items                *Schema
+	MinItems             *int
+	MaxItems             *int
+	MinLength            *int
+	MaxLength            *int
+	Pattern              *regexp.Regexp
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+}
+
+// Compile parses the given JSON Schema document.
+// Keywords that are not supported are reported as errors instead of being ignored.
+func Compile(document []byte) (*Schema, error) {
+	var raw any
+	if err := json.Unmarshal(document, &raw); err != nil {
+		return nil, fmt.Errorf("unable to parse schema: %v", err)
+	}
+
+	return compile(raw, "#")
+}
+
+func compile(raw any, path string) (*Schema, error) {
+	if allowed, ok := raw.(bool); ok {
+		if allowed {
+			return &Schema{}, nil
+		}
+
+		return &Schema{Enum: []any{}}, nil
+	}
+
+	keywords, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+	}
+
+	var (
+		schema = &Schema{}
+		err    error
+	)
+
+	for _, keyword := range sortedKeys(keywords) {
+		value := keywords[keyword]
+		keywordPath := path + "/" + keyword
+
+		switch keyword {
+		case "type":
+			schema.Types, err = compileTypes(value, keywordPath)
+
+		case "enum":
+			values, ok := value.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array", keywordPath)
+			}
+			schema.Enum = values
+
+		case "const":
+			schema.Const = &value
+
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", keywordPath)
+			}
+
+			schema.Properties = make(map[string]*Schema, len(properties))
+			for name, property := range properties {
+				if schema.Properties[name], err = compile(property, keywordPath+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+
+		case "required":
+			schema.Required, err = compileStrings(value, keywordPath)
+
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				schema.NoAdditional = !allowed
+				continue
+			}
+			schema.AdditionalProperties, err = compile(value, keywordPath)
+
+		case "items":
+			schema.Items, err = compile(value, keywordPath)
+
+		case "minItems":
+			schema.MinItems, err = compileCount(value, keywordPath)
+		case "maxItems":
+			schema.MaxItems, err = compileCount(value, keywordPath)
+		case "minLength":
+			schema.MinLength, err = compileCount(value, keywordPath)
+		case "maxLength":
+			schema.MaxLength, err = compileCount(value, keywordPath)
+
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", keywordPath)
+			}
+			if schema.Pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("%s: invalid pattern: %v", keywordPath, err)
+			}
+
+		case "minimum":
+			schema.Minimum, err = compileNumber(value, keywordPath)
+		case "maximum":
+			schema.Maximum, err = compileNumber(value, keywordPath)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = compileNumber(value, keywordPath)
+		case "exclusiveMaximum":
+			schema.ExclusiveMaximum, err = compileNumber(value, keywordPath)
+
+		default:
+			if !annotationKeywords[keyword] {
+				return nil, fmt.Errorf("%s: unsupported keyword", keywordPath)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schema, nil
+}
+
+// Validate returns an error listing every violation of the schema by the given value.
+func (s *Schema) Validate(value any) error {
+	var violations []string
+	s.validate(value, "", &violations)
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", strings.Join(violations, ", "))
+}
+
+func (s *Schema) validate(value any, path string, violations *[]string) {
+	report := func(format string, args ...any) {
+		location := path
+		if location == "" {
+			location = "value"
+		}
+
+		*violations = append(*violations, location+" "+fmt.Sprintf(format, args...))
+	}
+
+	if s.Enum != nil && !containsValue(s.Enum, value) {
+		report("must be one of %s", formatValues(s.Enum))
+	}
+
+	if s.Const != nil && !equalValues(*s.Const, value) {
+		report("must be %s", formatValues([]any{*s.Const}))
+	}
+
+	if len(s.Types) > 0 && !s.matchesType(value) {
+		report("must be of type %s", strings.Join(s.Types, " or "))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		s.validateObject(v, path, violations)
+
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			report("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			report("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, path+"["+strconv.Itoa(i)+"]", violations)
+			}
+		}
+
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			report("must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			report("must be at most %d characters long", *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(v) {
+			report("must match pattern %q", s.Pattern.String())
+		}
+		if number, err := strconv.ParseFloat(v, 64); err == nil && s.expectsNumber() {
+			s.validateNumber(number, report)
+		}
+
+	default:
+		if number, ok := toNumber(v); ok {
+			s.validateNumber(number, report)
+		}
+	}
+}
+
+func (s *Schema) validateObject(object map[string]any, path string, violations *[]string) {
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*violations = append(*violations, fmt.Sprintf("%s%s is required", prefix, name))
+		}
+	}
+
+	for _, name := range sortedKeys(object) {
+		if property, ok := s.Properties[name]; ok {
+			property.validate(object[name], prefix+name, violations)
+		} else if s.NoAdditional {
+			*violations = append(*violations, fmt.Sprintf("%s%s is not allowed", prefix, name))
+		} else if s.AdditionalProperties != nil {
+			s.AdditionalProperties.validate(object[name], prefix+name, violations)
+		}
+	}
+}
+
+func (s *Schema) validateNumber(number float64, report func(format string, args ...any)) {
+	if s.Minimum != nil && number < *s.Minimum {
+		report("must be greater than or equal to %v", *s.Minimum)
+	}
+	if s.Maximum != nil && number > *s.Maximum {
+		report("must be lower than or equal to %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && number <= *s.ExclusiveMinimum {
+		report("must be greater than %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && number >= *s.ExclusiveMaximum {
+		report("must be lower than %v", *s.ExclusiveMaximum)
+	}
+}
+
+func (s *Schema) expectsNumber() bool {
+	for _, t := range s.Types {
+		if t == TypeNumber || t == TypeInteger {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schema) matchesType(value any) bool {
+	for _, t := range s.Types {
+		if matchesType(t, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesType(t string, value any) bool {
+	switch t {
+	case TypeNull:
+		return value == nil
+	case TypeObject:
+		_, ok := value.(map[string]any)
+		return ok
+	case TypeArray:
+		_, ok := value.([]any)
+		return ok
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(v)
+			return err == nil
+		}
+	case TypeNumber, TypeInteger:
+		number, ok := toNumber(value)
+		if !ok {
+			if s, isString := value.(string); isString {
+				var err error
+				number, err = strconv.ParseFloat(s, 64)
+				ok = err == nil
+			}
+		}
+
+		return ok && (t == TypeNumber || number == math.Trunc(number))
+	}
+
+	return false
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+
+	return 0, false
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if equalValues(candidate, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalValues compares JSON values, a string being equal to the number or
+// boolean it holds.
+func equalValues(expected any, value any) bool {
+	expectedJSON, _ := json.Marshal(expected)
+	valueJSON, _ := json.Marshal(value)
+
+	if string(expectedJSON) == string(valueJSON) {
+		return true
+	}
+
+	if s, ok := value.(string); ok {
+		return string(expectedJSON) == s
+	}
+
+	return false
+}
+
+func formatValues(values []any) string {
+	var formatted = make([]string, 0, len(values))
+
+	for _, value := range values {
+		encoded, _ := json.Marshal(value)
+		formatted = append(formatted, string(encoded))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func compileTypes(value any, path string) ([]string, error) {
+	var result []string
+
+	switch v := value.(type) {
+	case string:
+		result = []string{v}
+	case []any:
+		values, err := compileStrings(v, path)
+		if err != nil {
+			return nil, err
+		}
+		result = values
+	default:
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", path)
+	}
+
+	for _, t := range result {
+		if !types[t] {
+			return nil, fmt.Errorf("%s: unknown type %q", path, t)
+		}
+	}
+
+	return result, nil
+}
+
+func compileStrings(value any, path string) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an array of strings", path)
+	}
+
+	var result = make([]string, 0, len(values))
+
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be an array of strings", path)
+		}
+
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+func compileCount(value any, path string) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", path)
+	}
+
+	count := int(number)
+
+	return &count, nil
+}
+
+func compileNumber(value any, path string) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", path)
+	}
+
+	return &number, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	var keys = make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile_WhenUnsupportedKeyword(t *testing.T) {
+	// When
+	schema, err := Compile([]byte(`{"type": "object", "properties": {"owner": {"$ref": "#/definitions/owner"}}}`))
+
+	// Then
+	assert.Nil(t, schema)
+	assert.EqualError(t, err, "#/properties/owner/$ref: unsupported keyword")
+}
+
+func TestCompile_WhenUnknownType(t *testing.T) {
+	// When
+	schema, err := Compile([]byte(`{"type": "text"}`))
+
+	// Then
+	assert.Nil(t, schema)
+	assert.EqualError(t, err, `#/type: unknown type "text"`)
+}
+
+func TestValidate(t *testing.T) {
+	// Given
+	schema, err := Compile([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"owner_id": {"type": "string", "pattern": "^user-[0-9]+$"},
+			"status": {"enum": ["draft", "published"]},
+			"words": {"type": "integer", "minimum": 0, "maximum": 10000},
+			"public": {"type": "boolean"}
+		},
+		"required": ["owner_id", "status"],
+		"additionalProperties": false
+	}`))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		attributes map[string]any
+		expected   string
+	}{
+		{
+			name:       "valid attributes",
+			attributes: map[string]any{"owner_id": "user-1", "status": "draft", "words": float64(120), "public": true},
+		},
+		{
+			name:       "valid attributes given as strings",
+			attributes: map[string]any{"owner_id": "user-1", "status": "draft", "words": "120", "public": "true"},
+		},
+		{
+			name:       "missing required attribute",
+			attributes: map[string]any{"owner_id": "user-1"},
+			expected:   "status is required",
+		},
+		{
+			name:       "invalid attributes",
+			attributes: map[string]any{"owner_id": "bob", "status": "deleted", "words": 12.5, "color": "red"},
+			expected:   `color is not allowed, owner_id must match pattern "^user-[0-9]+$", status must be one of "draft", "published", words must be of type integer`,
+		},
+		{
+			name:       "out of range",
+			attributes: map[string]any{"owner_id": "user-1", "status": "draft", "words": "20000"},
+			expected:   "words must be lower than or equal to 10000",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			err := schema.Validate(testCase.attributes)
+
+			// Then
+			if testCase.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	lib_ldap "github.com/go-ldap/ldap/v3"
+)
+
+// Entry is a directory entry with its attribute values.
+type Entry struct {
+	DN         string
+	attributes map[string][]string
+}
+
+// NewEntry initializes an entry. Attribute names are case-insensitive.
+func NewEntry(dn string, attributes map[string][]string) *Entry {
+	entry := &Entry{
+		DN:         dn,
+		attributes: map[string][]string{},
+	}
+
+	for name, values := range attributes {
+		key := strings.ToLower(name)
+		entry.attributes[key] = append(entry.attributes[key], values...)
+	}
+
+	return entry
+}
+
+// Values returns the values of the given attribute.
+func (e *Entry) Values(attribute string) []string {
+	return e.attributes[strings.ToLower(attribute)]
+}
+
+// Value returns the first value of the given attribute.
+func (e *Entry) Value(attribute string) string {
+	if values := e.Values(attribute); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+type Directory interface {
+	Connect() (Connection, error)
+}
+
+type Connection interface {
+	Search(baseDN string, filter string, attributes []string) ([]*Entry, error)
+	Close() error
+}
+
+type directory struct {
+	cfg *configs.LDAP
+}
+
+// NewDirectory initializes a directory connecting to the configured server
+// with the configured bind credentials.
+func NewDirectory(cfg *configs.LDAP) Directory {
+	return &directory{
+		cfg: cfg,
+	}
+}
+
+func (d *directory) Connect() (Connection, error) {
+	serverURL, err := url.Parse(d.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse LDAP URL: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverURL.Hostname(),
+		InsecureSkipVerify: d.cfg.InsecureSkipVerify,
+	}
+
+	conn, err := lib_ldap.DialURL(d.cfg.URL, lib_ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to LDAP server: %v", err)
+	}
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("unable to start TLS: %v", err)
+		}
+	}
+
+	if d.cfg.BindDN != "" {
+		err = conn.Bind(d.cfg.BindDN, d.cfg.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unable to bind to LDAP server: %v", err)
+	}
+
+	return &connection{
+		conn:     conn,
+		pageSize: d.cfg.PageSize,
+	}, nil
+}
+
+type connection struct {
+	conn     *lib_ldap.Conn
+	pageSize uint32
+}
+
+// Search returns all entries matching the filter in the subtree of the base DN.
+// Results are retrieved by pages when a page size is configured.
+func (c *connection) Search(baseDN string, filter string, attributes []string) ([]*Entry, error) {
+	request := lib_ldap.NewSearchRequest(
+		baseDN,
+		lib_ldap.ScopeWholeSubtree,
+		lib_ldap.NeverDerefAliases,
+		0, 0, false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	var (
+		result *lib_ldap.SearchResult
+		err    error
+	)
+
+	if c.pageSize > 0 {
+		result, err = c.conn.SearchWithPaging(request, c.pageSize)
+	} else {
+		result, err = c.conn.Search(request)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to search %q: %v", baseDN, err)
+	}
+
+	var entries = make([]*Entry, 0, len(result.Entries))
+
+	for _, item := range result.Entries {
+		values := map[string][]string{}
+		for _, attribute := range item.Attributes {
+			values[attribute.Name] = attribute.Values
+		}
+
+		entries = append(entries, NewEntry(item.DN, values))
+	}
+
+	return entries, nil
+}
+
+func (c *connection) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,91 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBindDN   = "cn=authz,ou=services,dc=acme,dc=tld"
+	testPassword = "s3cr3t"
+)
+
+func TestDirectory_Connect_WhenInvalidCredentials(t *testing.T) {
+	// Given
+	server := newTestServer(t, testBindDN, testPassword)
+
+	directoryInstance := NewDirectory(&configs.LDAP{
+		URL:          server.URL(),
+		BindDN:       testBindDN,
+		BindPassword: "invalid",
+	})
+
+	// When
+	connection, err := directoryInstance.Connect()
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(connection)
+	assert.ErrorContains(err, "unable to bind to LDAP server")
+}
+
+func TestDirectory_Search(t *testing.T) {
+	// Given
+	server := newTestServer(t, testBindDN, testPassword,
+		NewEntry("uid=john,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"john"},
+			"mail":        {"john@acme.tld"},
+		}),
+		NewEntry("uid=jane,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"jane"},
+		}),
+		NewEntry("uid=jack,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"jack"},
+		}),
+		NewEntry("cn=printer,ou=devices,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"device"},
+		}),
+		NewEntry("uid=bob,ou=people,dc=other,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"bob"},
+		}),
+	)
+
+	directoryInstance := NewDirectory(&configs.LDAP{
+		URL:          server.URL(),
+		BindDN:       testBindDN,
+		BindPassword: testPassword,
+		PageSize:     2,
+	})
+
+	connection, err := directoryInstance.Connect()
+	assert.Nil(t, err)
+
+	defer connection.Close()
+
+	// When
+	entries, err := connection.Search("dc=acme,dc=tld", "(objectClass=person)", []string{"uid", "mail"})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Len(entries, 3)
+
+	assert.Equal("uid=john,ou=people,dc=acme,dc=tld", entries[0].DN)
+	assert.Equal("john", entries[0].Value("UID"))
+	assert.Equal([]string{"john@acme.tld"}, entries[0].Values("mail"))
+
+	assert.Equal("jane", entries[1].Value("uid"))
+	assert.Equal("", entries[1].Value("mail"))
+
+	assert.Equal("jack", entries[2].Value("uid"))
+
+	assert.Equal(2, server.searches, "entries should be retrieved in two pages")
+}
@@ -0,0 +1,18 @@
+package ldap
+
+import (
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("ldap",
+		fx.Provide(
+			NewDirectory,
+			NewMapper,
+			NewSyncer,
+		),
+		fx.Invoke(
+			RunSyncer,
+		),
+	)
+}
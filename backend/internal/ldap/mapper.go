@@ -0,0 +1,165 @@
+package ldap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	lib_ldap "github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// DNAttribute is the principal attribute containing the DN of the entry
+	// a principal was synchronized from. It marks principals managed by the
+	// LDAP synchronization.
+	DNAttribute = "ldap_dn"
+
+	mappingSeparator = "="
+)
+
+// Mapper maps directory entries to principal attributes and roles.
+type Mapper struct {
+	attributes   map[string]string
+	groupsRoles  map[string][]string
+	managedRoles map[string]bool
+}
+
+// NewMapper initializes a mapper from the "ldapattribute=attribute" and
+// "group=role" mappings of the configuration.
+func NewMapper(cfg *configs.LDAP) (*Mapper, error) {
+	mapper := &Mapper{
+		attributes:   map[string]string{},
+		groupsRoles:  map[string][]string{},
+		managedRoles: map[string]bool{},
+	}
+
+	for _, mapping := range cfg.AttributesMapping {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		ldapAttribute, attribute := mapping, mapping
+		if index := strings.LastIndex(mapping, mappingSeparator); index != -1 {
+			ldapAttribute, attribute = mapping[:index], mapping[index+1:]
+		}
+
+		if ldapAttribute == "" || attribute == "" {
+			return nil, fmt.Errorf("invalid LDAP attribute mapping %q, expected ldapattribute or ldapattribute=attribute", mapping)
+		}
+
+		if attribute == DNAttribute {
+			return nil, fmt.Errorf("invalid LDAP attribute mapping %q, %q attribute is reserved", mapping, DNAttribute)
+		}
+
+		mapper.attributes[ldapAttribute] = attribute
+	}
+
+	for _, mapping := range cfg.GroupsRoles {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		group, role, found := strings.Cut(mapping, mappingSeparator)
+		if !found || group == "" || role == "" {
+			return nil, fmt.Errorf("invalid LDAP group role mapping %q, expected group=role", mapping)
+		}
+
+		group = strings.ToLower(group)
+
+		mapper.groupsRoles[group] = append(mapper.groupsRoles[group], role)
+		mapper.managedRoles[role] = true
+	}
+
+	return mapper, nil
+}
+
+// LDAPAttributes returns the names of the mapped directory attributes.
+func (m *Mapper) LDAPAttributes() []string {
+	var result = make([]string, 0, len(m.attributes))
+	for ldapAttribute := range m.attributes {
+		result = append(result, ldapAttribute)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Attributes returns the principal attributes of the given entry.
+// Multi-valued attributes are joined with commas.
+func (m *Mapper) Attributes(entry *Entry) map[string]any {
+	var attributes = map[string]any{
+		DNAttribute: entry.DN,
+	}
+
+	for ldapAttribute, attribute := range m.attributes {
+		values := entry.Values(ldapAttribute)
+		if len(values) == 0 {
+			continue
+		}
+
+		attributes[attribute] = strings.Join(values, ",")
+	}
+
+	return attributes
+}
+
+// IsManagedAttribute returns whether the given principal attribute is set by
+// the synchronization, so it has to be removed when missing from the entry.
+func (m *Mapper) IsManagedAttribute(attribute string) bool {
+	if attribute == DNAttribute {
+		return true
+	}
+
+	for _, mapped := range m.attributes {
+		if mapped == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Roles returns the roles mapped to the given group names, sorted and
+// without duplicates. Group names are case-insensitive.
+func (m *Mapper) Roles(groups []string) []string {
+	var (
+		roles = []string{}
+		seen  = map[string]bool{}
+	)
+
+	for _, group := range groups {
+		for _, role := range m.groupsRoles[strings.ToLower(group)] {
+			if seen[role] {
+				continue
+			}
+
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	sort.Strings(roles)
+
+	return roles
+}
+
+// IsManagedRole returns whether the given role is given by a group mapping,
+// so it has to be removed when the user leaves the group.
+func (m *Mapper) IsManagedRole(role string) bool {
+	return m.managedRoles[role]
+}
+
+// GroupName returns the common name of a group from its DN
+// (for instance "admins" for "cn=admins,ou=groups,dc=acme,dc=tld").
+func GroupName(dn string) string {
+	parsed, err := lib_ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return dn
+	}
+
+	return parsed.RDNs[0].Attributes[0].Value
+}
@@ -0,0 +1,104 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMapper_InvalidMappings(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           *configs.LDAP
+		expectedError string
+	}{
+		{
+			name:          "Group without role",
+			cfg:           &configs.LDAP{GroupsRoles: []string{"engineering"}},
+			expectedError: `invalid LDAP group role mapping "engineering", expected group=role`,
+		},
+		{
+			name:          "Empty attribute",
+			cfg:           &configs.LDAP{AttributesMapping: []string{"department="}},
+			expectedError: `invalid LDAP attribute mapping "department=", expected ldapattribute or ldapattribute=attribute`,
+		},
+		{
+			name:          "Reserved attribute",
+			cfg:           &configs.LDAP{AttributesMapping: []string{"distinguishedName=ldap_dn"}},
+			expectedError: `invalid LDAP attribute mapping "distinguishedName=ldap_dn", "ldap_dn" attribute is reserved`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// When
+			mapper, err := NewMapper(testCase.cfg)
+
+			// Then
+			assert.Nil(t, mapper)
+			assert.EqualError(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestMapper_Attributes(t *testing.T) {
+	// Given
+	mapper, err := NewMapper(&configs.LDAP{
+		AttributesMapping: []string{"mail=email", "department", "employeeType=type"},
+	})
+	assert.Nil(t, err)
+
+	entry := NewEntry("uid=john,ou=people,dc=acme,dc=tld", map[string][]string{
+		"Mail":        {"john@acme.tld"},
+		"department":  {"sales", "marketing"},
+		"telephone":   {"+33 1 23 45 67 89"},
+		"objectClass": {"person"},
+	})
+
+	// When
+	attributes := mapper.Attributes(entry)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Equal(map[string]any{
+		"ldap_dn":    "uid=john,ou=people,dc=acme,dc=tld",
+		"email":      "john@acme.tld",
+		"department": "sales,marketing",
+	}, attributes)
+
+	assert.Equal([]string{"department", "employeeType", "mail"}, mapper.LDAPAttributes())
+
+	assert.True(mapper.IsManagedAttribute("ldap_dn"))
+	assert.True(mapper.IsManagedAttribute("type"))
+	assert.False(mapper.IsManagedAttribute("telephone"))
+}
+
+func TestMapper_Roles(t *testing.T) {
+	// Given
+	mapper, err := NewMapper(&configs.LDAP{
+		GroupsRoles: []string{"Engineering=developer", "admins=admin", "admins=developer"},
+	})
+	assert.Nil(t, err)
+
+	// When
+	roles := mapper.Roles([]string{"engineering", "Admins", "marketing"})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Equal([]string{"admin", "developer"}, roles)
+	assert.Equal([]string{}, mapper.Roles(nil))
+
+	assert.True(mapper.IsManagedRole("developer"))
+	assert.False(mapper.IsManagedRole("viewer"))
+}
+
+func TestGroupName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("admins", GroupName("cn=admins,ou=groups,dc=acme,dc=tld"))
+	assert.Equal("Domain Users", GroupName("CN=Domain Users,CN=Users,DC=acme,DC=tld"))
+	assert.Equal("admins", GroupName("admins"))
+}
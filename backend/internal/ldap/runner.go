@@ -0,0 +1,69 @@
+package ldap
+
+import (
+	"context"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+// RunSyncer synchronizes the directory on startup and then periodically,
+// when an LDAP server is configured.
+func RunSyncer(lc fx.Lifecycle, cfg *configs.LDAP, logger *slog.Logger, syncer Syncer) {
+	if cfg.URL == "" {
+		return
+	}
+
+	var (
+		ticker = lib_time.NewTicker(cfg.SyncDelay)
+		done   = make(chan struct{})
+	)
+
+	sync := func() {
+		summary, err := syncer.Sync()
+		if err != nil {
+			logger.Error("LDAP: unable to synchronize directory", err)
+			return
+		}
+
+		logger.Info("LDAP: directory synchronized",
+			slog.Int("created", summary.Created),
+			slog.Int("updated", summary.Updated),
+			slog.Int("deleted", summary.Deleted),
+			slog.Int("unchanged", summary.Unchanged),
+			slog.Int("errors", summary.Errors),
+			slog.Duration("duration", summary.Duration),
+		)
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				sync()
+
+				for {
+					select {
+					case <-ticker.C:
+						sync()
+					case <-done:
+						return
+					}
+				}
+			}()
+
+			logger.Info("LDAP: directory synchronization started")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			ticker.Stop()
+			close(done)
+
+			logger.Info("LDAP: directory synchronization stopped")
+
+			return nil
+		},
+	})
+}
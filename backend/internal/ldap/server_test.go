@@ -0,0 +1,264 @@
+package ldap
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	lib_ldap "github.com/go-ldap/ldap/v3"
+)
+
+// testServer is a minimal in-process LDAP server supporting simple binds and
+// paged subtree searches with "and", "or", "not", equality and presence filters.
+type testServer struct {
+	listener net.Listener
+	bindDN   string
+	password string
+
+	mutex    sync.Mutex
+	entries  []*Entry
+	searches int
+}
+
+func newTestServer(t *testing.T, bindDN string, password string, entries ...*Entry) *testServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	server := &testServer{
+		listener: listener,
+		bindDN:   bindDN,
+		password: password,
+		entries:  entries,
+	}
+
+	go server.serve()
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return server
+}
+
+func (s *testServer) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *testServer) SetEntries(entries ...*Entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = entries
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		var controls []*ber.Packet
+		if len(packet.Children) > 2 {
+			controls = packet.Children[2].Children
+		}
+
+		switch request.Tag {
+		case lib_ldap.ApplicationBindRequest:
+			s.write(conn, messageID, s.bind(request), nil)
+		case lib_ldap.ApplicationSearchRequest:
+			entries, done, control := s.search(request, controls)
+			for _, entry := range entries {
+				s.write(conn, messageID, entry, nil)
+			}
+			s.write(conn, messageID, done, control)
+		case lib_ldap.ApplicationUnbindRequest:
+			return
+		default:
+			s.write(conn, messageID, result(lib_ldap.ApplicationExtendedResponse, lib_ldap.LDAPResultUnwillingToPerform), nil)
+		}
+	}
+}
+
+func (s *testServer) write(conn net.Conn, messageID int64, response *ber.Packet, control *ber.Packet) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	packet.AppendChild(response)
+
+	if control != nil {
+		controls := ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		controls.AppendChild(control)
+		packet.AppendChild(controls)
+	}
+
+	_, _ = conn.Write(packet.Bytes())
+}
+
+func (s *testServer) bind(request *ber.Packet) *ber.Packet {
+	name := request.Children[1].Data.String()
+	password := request.Children[2].Data.String()
+
+	if name == "" || (strings.EqualFold(name, s.bindDN) && password == s.password) {
+		return result(lib_ldap.ApplicationBindResponse, lib_ldap.LDAPResultSuccess)
+	}
+
+	return result(lib_ldap.ApplicationBindResponse, lib_ldap.LDAPResultInvalidCredentials)
+}
+
+func (s *testServer) search(request *ber.Packet, controls []*ber.Packet) ([]*ber.Packet, *ber.Packet, *ber.Packet) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.searches++
+
+	baseDN := strings.ToLower(request.Children[0].Data.String())
+	filter := request.Children[6]
+
+	var attributes []string
+	for _, attribute := range request.Children[7].Children {
+		attributes = append(attributes, attribute.Data.String())
+	}
+
+	var matching []*Entry
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.DN)
+		if (dn == baseDN || strings.HasSuffix(dn, ","+baseDN)) && matches(entry, filter) {
+			matching = append(matching, entry)
+		}
+	}
+
+	// Paging control: the cookie is the offset of the next page.
+	var control *ber.Packet
+
+	for _, packet := range controls {
+		decoded, err := lib_ldap.DecodeControl(packet)
+		if err != nil {
+			continue
+		}
+
+		paging, ok := decoded.(*lib_ldap.ControlPaging)
+		if !ok || paging.PagingSize == 0 {
+			continue
+		}
+
+		offset, _ := strconv.Atoi(string(paging.Cookie))
+		if offset > len(matching) {
+			offset = len(matching)
+		}
+
+		end := offset + int(paging.PagingSize)
+		if end >= len(matching) {
+			end = len(matching)
+		}
+
+		response := lib_ldap.NewControlPaging(0)
+		if end < len(matching) {
+			response.SetCookie([]byte(strconv.Itoa(end)))
+		}
+
+		matching = matching[offset:end]
+		control = response.Encode()
+	}
+
+	var entries []*ber.Packet
+	for _, entry := range matching {
+		entries = append(entries, encodeEntry(entry, attributes))
+	}
+
+	return entries, result(lib_ldap.ApplicationSearchResultDone, lib_ldap.LDAPResultSuccess), control
+}
+
+func result(application int, code uint16) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ber.Tag(application), nil, "Result")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result code"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic message"))
+
+	return packet
+}
+
+func encodeEntry(entry *Entry, attributes []string) *ber.Packet {
+	packet := ber.Encode(ber.ClassApplication, ber.TypeConstructed, lib_ldap.ApplicationSearchResultEntry, nil, "Search result entry")
+	packet.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+
+	for _, attribute := range attributes {
+		values := entry.Values(attribute)
+		if len(values) == 0 {
+			continue
+		}
+
+		item := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute, "Type"))
+
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+
+		item.AppendChild(set)
+		list.AppendChild(item)
+	}
+
+	packet.AppendChild(list)
+
+	return packet
+}
+
+func matches(entry *Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case lib_ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matches(entry, child) {
+				return false
+			}
+		}
+
+		return true
+	case lib_ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matches(entry, child) {
+				return true
+			}
+		}
+
+		return false
+	case lib_ldap.FilterNot:
+		return !matches(entry, filter.Children[0])
+	case lib_ldap.FilterEqualityMatch:
+		expected := filter.Children[1].Data.Bytes()
+
+		for _, value := range entry.Values(filter.Children[0].Data.String()) {
+			if bytes.EqualFold([]byte(value), expected) {
+				return true
+			}
+		}
+
+		return false
+	case lib_ldap.FilterPresent:
+		return len(entry.Values(filter.Data.String())) > 0
+	}
+
+	return false
+}
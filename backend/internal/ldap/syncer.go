@@ -0,0 +1,370 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"golang.org/x/exp/slog"
+	"gorm.io/gorm"
+)
+
+// Summary reports what a synchronization changed.
+type Summary struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Unchanged int
+	Errors    int
+	Duration  lib_time.Duration
+}
+
+type Syncer interface {
+	Sync() (*Summary, error)
+}
+
+type syncer struct {
+	cfg              *configs.LDAP
+	logger           *slog.Logger
+	clock            time.Clock
+	directory        Directory
+	mapper           *Mapper
+	principalManager manager.Principal
+	roleRepository   manager.RoleRepository
+}
+
+// NewSyncer initializes a syncer creating, updating and deleting principals
+// from the users of the directory.
+func NewSyncer(
+	cfg *configs.LDAP,
+	logger *slog.Logger,
+	clock time.Clock,
+	directory Directory,
+	mapper *Mapper,
+	principalManager manager.Principal,
+	roleRepository manager.RoleRepository,
+) Syncer {
+	return &syncer{
+		cfg:              cfg,
+		logger:           logger,
+		clock:            clock,
+		directory:        directory,
+		mapper:           mapper,
+		principalManager: principalManager,
+		roleRepository:   roleRepository,
+	}
+}
+
+// directoryUser is a directory user with the principal it is synchronized to.
+type directoryUser struct {
+	principalID string
+	attributes  map[string]any
+	roles       []string
+}
+
+// Sync reads the users of the configured base DNs and synchronizes their
+// principal: missing principals are created, existing ones get their mapped
+// attributes and roles updated and principals of users that left the
+// directory are deleted. Principals that were not created by the
+// synchronization are never deleted.
+func (s *syncer) Sync() (*Summary, error) {
+	var (
+		startedAt = s.clock.Now()
+		summary   = &Summary{}
+	)
+
+	roles, err := s.existingRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.readDirectory(roles, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	principals, _, err := s.principalManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "LIKE", Value: model.UserPrincipal("%")},
+		}),
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user principals: %v", err)
+	}
+
+	var principalsByID = make(map[string]*model.Principal, len(principals))
+	for _, principal := range principals {
+		principalsByID[principal.ID] = principal
+	}
+
+	for _, user := range users {
+		if err := s.syncUser(user, principalsByID[user.principalID], summary); err != nil {
+			s.logger.Error("LDAP: unable to synchronize principal", err, slog.String("principal_id", user.principalID))
+			summary.Errors++
+		}
+	}
+
+	s.deleteDeparted(users, principals, summary)
+
+	summary.Duration = s.clock.Now().Sub(startedAt)
+
+	return summary, nil
+}
+
+// readDirectory returns the users found in the directory by principal identifier,
+// with their mapped roles among the existing ones. Any search error aborts the
+// synchronization so that no principal gets deleted because of a partial read.
+func (s *syncer) readDirectory(roles map[string]bool, summary *Summary) (map[string]*directoryUser, error) {
+	connection, err := s.directory.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = connection.Close() }()
+
+	groupsByMember, err := s.readGroups(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes = []string{s.cfg.UserIDAttribute}
+	if s.cfg.GroupsAttribute != "" {
+		attributes = append(attributes, s.cfg.GroupsAttribute)
+	}
+	attributes = append(attributes, s.mapper.LDAPAttributes()...)
+
+	var users = map[string]*directoryUser{}
+
+	for _, baseDN := range s.cfg.BaseDNs {
+		entries, err := connection.Search(baseDN, s.cfg.UserFilter, attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			identifier := entry.Value(s.cfg.UserIDAttribute)
+			if identifier == "" {
+				s.logger.Warn("LDAP: user has no identifier attribute, skipping it",
+					slog.String("dn", entry.DN),
+					slog.String("attribute", s.cfg.UserIDAttribute),
+				)
+				summary.Errors++
+
+				continue
+			}
+
+			principalID := model.UserPrincipal(identifier)
+			if _, ok := users[principalID]; ok {
+				// Base DNs may overlap, the first entry found is kept.
+				continue
+			}
+
+			groups := slices.Clone(groupsByMember[strings.ToLower(entry.DN)])
+			for _, group := range entry.Values(s.cfg.GroupsAttribute) {
+				groups = append(groups, GroupName(group))
+			}
+
+			var userRoles = []string{}
+			for _, role := range s.mapper.Roles(groups) {
+				if !roles[role] {
+					s.logger.Warn("LDAP: mapped role does not exist",
+						slog.String("principal_id", principalID),
+						slog.String("role_id", role),
+					)
+					continue
+				}
+
+				userRoles = append(userRoles, role)
+			}
+
+			users[principalID] = &directoryUser{
+				principalID: principalID,
+				attributes:  s.mapper.Attributes(entry),
+				roles:       userRoles,
+			}
+		}
+	}
+
+	return users, nil
+}
+
+// readGroups returns the names of the groups of each member DN (lowercased),
+// when groups base DNs are configured.
+func (s *syncer) readGroups(connection Connection) (map[string][]string, error) {
+	var groupsByMember = map[string][]string{}
+
+	for _, baseDN := range s.cfg.GroupBaseDNs {
+		entries, err := connection.Search(baseDN, s.cfg.GroupFilter, []string{"cn", s.cfg.GroupMemberAttribute})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			name := entry.Value("cn")
+			if name == "" {
+				name = GroupName(entry.DN)
+			}
+
+			for _, member := range entry.Values(s.cfg.GroupMemberAttribute) {
+				member = strings.ToLower(member)
+				groupsByMember[member] = append(groupsByMember[member], name)
+			}
+		}
+	}
+
+	return groupsByMember, nil
+}
+
+// existingRoles returns which mapped roles exist, so a mapping mistake does
+// not prevent users from being synchronized.
+func (s *syncer) existingRoles() (map[string]bool, error) {
+	var result = map[string]bool{}
+
+	for role := range s.mapper.managedRoles {
+		_, err := s.roleRepository.Get(role)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve role %q: %v", role, err)
+		}
+
+		result[role] = true
+	}
+
+	return result, nil
+}
+
+func (s *syncer) syncUser(user *directoryUser, principal *model.Principal, summary *Summary) error {
+	if principal == nil {
+		if _, err := s.principalManager.Create(user.principalID, user.roles, user.attributes); err != nil {
+			return fmt.Errorf("unable to create principal: %v", err)
+		}
+
+		summary.Created++
+
+		return nil
+	}
+
+	if principal.IsLocked {
+		summary.Unchanged++
+		return nil
+	}
+
+	var (
+		attributes        = map[string]any{}
+		currentAttributes = map[string]any{}
+		roles             = slices.Clone(user.roles)
+		currentRoles      = []string{}
+	)
+
+	for key, value := range user.attributes {
+		attributes[key] = value
+	}
+
+	for _, attribute := range principal.Attributes {
+		currentAttributes[attribute.Key] = attribute.Value
+
+		if _, ok := attributes[attribute.Key]; !ok && !s.mapper.IsManagedAttribute(attribute.Key) {
+			attributes[attribute.Key] = attribute.Value
+		}
+	}
+
+	for _, role := range principal.Roles {
+		currentRoles = append(currentRoles, role.ID)
+
+		if !s.mapper.IsManagedRole(role.ID) {
+			roles = append(roles, role.ID)
+		}
+	}
+
+	sort.Strings(roles)
+	sort.Strings(currentRoles)
+
+	if slices.Equal(roles, currentRoles) && equalAttributes(attributes, currentAttributes) {
+		summary.Unchanged++
+		return nil
+	}
+
+	if _, err := s.principalManager.Update(principal.ID, roles, attributes); err != nil {
+		return fmt.Errorf("unable to update principal: %v", err)
+	}
+
+	summary.Updated++
+
+	return nil
+}
+
+// deleteDeparted deletes the principals synchronized from the directory whose
+// user was not found anymore.
+func (s *syncer) deleteDeparted(users map[string]*directoryUser, principals []*model.Principal, summary *Summary) {
+	var departed []*model.Principal
+
+	for _, principal := range principals {
+		if _, ok := users[principal.ID]; ok || principal.IsLocked || !isSynchronized(principal) {
+			continue
+		}
+
+		departed = append(departed, principal)
+	}
+
+	// An empty directory is much more likely a filter or permission mistake
+	// than everyone leaving the company.
+	if len(users) == 0 && len(departed) > 0 {
+		s.logger.Warn("LDAP: no user found in directory, skipping deletion of synchronized principals",
+			slog.Int("principals", len(departed)),
+		)
+		return
+	}
+
+	for _, principal := range departed {
+		if err := s.principalManager.Delete(principal.ID); err != nil {
+			s.logger.Error("LDAP: unable to delete departed principal", err, slog.String("principal_id", principal.ID))
+			summary.Errors++
+
+			continue
+		}
+
+		summary.Deleted++
+	}
+}
+
+func isSynchronized(principal *model.Principal) bool {
+	for _, attribute := range principal.Attributes {
+		if attribute.Key == DNAttribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equalAttributes(a map[string]any, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		valueString, _ := manager.CastAnyToString(value)
+		otherString, _ := manager.CastAnyToString(other)
+
+		if valueString != otherString {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,248 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func newTestSyncer(t *testing.T, cfg *configs.LDAP, principalManager manager.Principal) *syncer {
+	mapper, err := NewMapper(cfg)
+	assert.Nil(t, err)
+
+	return NewSyncer(
+		cfg,
+		slog.New(log.NewNopHandler()),
+		time.NewStaticClock(),
+		NewDirectory(cfg),
+		mapper,
+		principalManager,
+		nil,
+	).(*syncer)
+}
+
+func TestSyncer_ReadDirectory(t *testing.T) {
+	// Given
+	server := newTestServer(t, testBindDN, testPassword,
+		NewEntry("uid=john,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"john"},
+			"mail":        {"john@acme.tld"},
+			"memberOf":    {"cn=engineering,ou=groups,dc=acme,dc=tld"},
+		}),
+		NewEntry("uid=jane,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+			"uid":         {"jane"},
+		}),
+		NewEntry("cn=nobody,ou=people,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"person"},
+		}),
+		NewEntry("cn=admins,ou=groups,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {"admins"},
+			"member":      {"uid=jane,ou=people,dc=acme,dc=tld", "uid=john,ou=people,dc=acme,dc=tld"},
+		}),
+		NewEntry("cn=auditors,ou=groups,dc=acme,dc=tld", map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {"auditors"},
+			"member":      {"uid=jane,ou=people,dc=acme,dc=tld"},
+		}),
+	)
+
+	cfg := &configs.LDAP{
+		URL:                  server.URL(),
+		BindDN:               testBindDN,
+		BindPassword:         testPassword,
+		BaseDNs:              []string{"ou=people,dc=acme,dc=tld"},
+		UserFilter:           "(objectClass=person)",
+		UserIDAttribute:      "uid",
+		GroupsAttribute:      "memberOf",
+		GroupBaseDNs:         []string{"ou=groups,dc=acme,dc=tld"},
+		GroupFilter:          "(objectClass=groupOfNames)",
+		GroupMemberAttribute: "member",
+		AttributesMapping:    []string{"mail=email"},
+		GroupsRoles:          []string{"engineering=developer", "admins=admin", "auditors=auditor"},
+	}
+
+	syncerInstance := newTestSyncer(t, cfg, nil)
+	summary := &Summary{}
+
+	// When
+	users, err := syncerInstance.readDirectory(map[string]bool{"admin": true, "developer": true}, summary)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(err)
+	assert.Equal(1, summary.Errors, "entry without identifier should be reported")
+
+	assert.Equal(map[string]*directoryUser{
+		"authz-user-john": {
+			principalID: "authz-user-john",
+			attributes: map[string]any{
+				"ldap_dn": "uid=john,ou=people,dc=acme,dc=tld",
+				"email":   "john@acme.tld",
+			},
+			roles: []string{"admin", "developer"},
+		},
+		"authz-user-jane": {
+			principalID: "authz-user-jane",
+			attributes: map[string]any{
+				"ldap_dn": "uid=jane,ou=people,dc=acme,dc=tld",
+			},
+			roles: []string{"admin"},
+		},
+	}, users)
+}
+
+func TestSyncer_ReadDirectory_WhenSearchFails(t *testing.T) {
+	// Given
+	server := newTestServer(t, testBindDN, testPassword)
+
+	cfg := &configs.LDAP{
+		URL:          server.URL(),
+		BindDN:       testBindDN,
+		BindPassword: "invalid",
+		BaseDNs:      []string{"ou=people,dc=acme,dc=tld"},
+	}
+
+	syncerInstance := newTestSyncer(t, cfg, nil)
+
+	// When
+	users, err := syncerInstance.readDirectory(map[string]bool{}, &Summary{})
+
+	// Then
+	assert.Nil(t, users)
+	assert.ErrorContains(t, err, "unable to bind to LDAP server")
+}
+
+func TestSyncer_SyncUser(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.LDAP{
+		AttributesMapping: []string{"mail=email"},
+		GroupsRoles:       []string{"admins=admin", "engineering=developer"},
+	}
+
+	principalManager := manager.NewMockPrincipal(ctrl)
+
+	syncerInstance := newTestSyncer(t, cfg, principalManager)
+
+	user := &directoryUser{
+		principalID: "authz-user-john",
+		attributes: map[string]any{
+			"ldap_dn": "uid=john,ou=people,dc=acme,dc=tld",
+			"email":   "john@acme.tld",
+		},
+		roles: []string{"admin"},
+	}
+
+	// Mapped attributes and roles are updated, the other ones are kept.
+	principalManager.EXPECT().Update(
+		"authz-user-john",
+		[]string{"admin", "viewer"},
+		map[string]any{
+			"ldap_dn": "uid=john,ou=people,dc=acme,dc=tld",
+			"email":   "john@acme.tld",
+			"country": "fr",
+		},
+	).Return(&model.Principal{}, nil)
+
+	principalManager.EXPECT().Create(
+		"authz-user-john",
+		[]string{"admin"},
+		user.attributes,
+	).Return(&model.Principal{}, nil)
+
+	summary := &Summary{}
+
+	// When - Then
+	assert := assert.New(t)
+
+	assert.Nil(syncerInstance.syncUser(user, &model.Principal{
+		ID:    "authz-user-john",
+		Roles: []*model.Role{{ID: "developer"}, {ID: "viewer"}},
+		Attributes: model.Attributes{
+			{Key: "ldap_dn", Value: "uid=john,ou=people,dc=acme,dc=tld"},
+			{Key: "email", Value: "john@old.tld"},
+			{Key: "country", Value: "fr"},
+		},
+	}, summary))
+
+	assert.Nil(syncerInstance.syncUser(user, &model.Principal{
+		ID:    "authz-user-john",
+		Roles: []*model.Role{{ID: "admin"}},
+		Attributes: model.Attributes{
+			{Key: "ldap_dn", Value: "uid=john,ou=people,dc=acme,dc=tld"},
+			{Key: "email", Value: "john@acme.tld"},
+		},
+	}, summary))
+
+	assert.Nil(syncerInstance.syncUser(user, nil, summary))
+
+	assert.Equal(&Summary{Created: 1, Updated: 1, Unchanged: 1}, summary)
+}
+
+func TestSyncer_DeleteDeparted(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	principalManager := manager.NewMockPrincipal(ctrl)
+
+	syncerInstance := newTestSyncer(t, &configs.LDAP{}, principalManager)
+
+	synchronized := func(id string) *model.Principal {
+		return &model.Principal{
+			ID:         id,
+			Attributes: model.Attributes{{Key: DNAttribute, Value: "uid=" + id}},
+		}
+	}
+
+	principals := []*model.Principal{
+		synchronized("authz-user-john"),
+		synchronized("authz-user-jane"),
+		{ID: "authz-user-jack"},
+		{ID: "authz-user-admin", IsLocked: true},
+	}
+
+	principalManager.EXPECT().Delete("authz-user-jane").Return(nil)
+
+	summary := &Summary{}
+
+	// When
+	syncerInstance.deleteDeparted(map[string]*directoryUser{
+		"authz-user-john": {principalID: "authz-user-john"},
+	}, principals, summary)
+
+	// Then
+	assert.Equal(t, &Summary{Deleted: 1}, summary)
+}
+
+func TestSyncer_DeleteDeparted_WhenDirectoryIsEmpty(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	principalManager := manager.NewMockPrincipal(ctrl)
+
+	syncerInstance := newTestSyncer(t, &configs.LDAP{}, principalManager)
+
+	principals := []*model.Principal{
+		{ID: "authz-user-john", Attributes: model.Attributes{{Key: DNAttribute, Value: "uid=john"}}},
+	}
+
+	summary := &Summary{}
+
+	// When
+	syncerInstance.deleteDeparted(map[string]*directoryUser{}, principals, summary)
+
+	// Then
+	assert.Equal(t, &Summary{}, summary)
+}
@@ -0,0 +1,485 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/attribute"
+	"github.com/eko/authz/backend/internal/entity/model"
+)
+
+type Severity string
+
+const (
+	// SeverityError is used for findings that make the model behave differently
+	// than expected. They can block policies creation and update.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is used for findings that are probably mistakes or
+	// leftovers but do not change decisions by themselves.
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	RuleAdminEquivalentPrincipal = "admin-equivalent-principal"
+	RuleDuplicatePolicy          = "duplicate-policy"
+	RuleRoleWithoutPrincipals    = "role-without-principals"
+	RuleShadowedPolicy           = "shadowed-policy"
+	RuleUnattachedPolicy         = "unattached-policy"
+	RuleUnknownAttribute         = "unknown-attribute"
+	RuleVanishedResourceKind     = "vanished-resource-kind"
+)
+
+const (
+	ObjectTypePolicy    = "policy"
+	ObjectTypePrincipal = "principal"
+	ObjectTypeRole      = "role"
+
+	wildcardValue = "*"
+)
+
+// Finding is an issue reported on an object of the model.
+type Finding struct {
+	Rule       string   `json:"rule"`
+	Severity   Severity `json:"severity"`
+	ObjectType string   `json:"object_type"`
+	ObjectID   string   `json:"object_id"`
+	Message    string   `json:"message"`
+}
+
+// Report lists the findings of an analysis, errors first.
+type Report struct {
+	Findings []*Finding `json:"findings"`
+}
+
+// HasErrors returns whether the report contains at least one error.
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the findings having the error severity.
+func (r *Report) Errors() []*Finding {
+	var errors []*Finding
+
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			errors = append(errors, finding)
+		}
+	}
+
+	return errors
+}
+
+func (r *Report) add(rule string, severity Severity, objectType string, objectID string, format string, args ...any) {
+	r.Findings = append(r.Findings, &Finding{
+		Rule:       rule,
+		Severity:   severity,
+		ObjectType: objectType,
+		ObjectID:   objectID,
+		Message:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+
+		switch {
+		case a.Severity != b.Severity:
+			return a.Severity == SeverityError
+		case a.ObjectType != b.ObjectType:
+			return a.ObjectType < b.ObjectType
+		case a.ObjectID != b.ObjectID:
+			return a.ObjectID < b.ObjectID
+		default:
+			return a.Rule < b.Rule
+		}
+	})
+}
+
+// State is the part of the model inspected by the analysis.
+type State struct {
+	// Policies with their resources, actions and roles.
+	Policies []*model.Policy
+
+	// Roles, principals being members of a role are deduced from Principals.
+	Roles []*model.Role
+
+	// Principals with their roles.
+	Principals []*model.Principal
+
+	// ResourceKinds are the kinds having at least one resource that is not a wildcard.
+	ResourceKinds []string
+
+	PrincipalAttributeKeys []string
+	ResourceAttributeKeys  []string
+}
+
+type analyzer struct {
+	state          *State
+	policiesByRole map[string][]*model.Policy
+	resourceKinds  set
+	principalKeys  set
+	resourceKeys   set
+
+	// candidate is set when a single (maybe unsaved) policy is analyzed.
+	candidate bool
+}
+
+func newAnalyzer(state *State) *analyzer {
+	a := &analyzer{
+		state:          state,
+		policiesByRole: map[string][]*model.Policy{},
+		resourceKinds:  toSet(state.ResourceKinds),
+		principalKeys:  toSet(state.PrincipalAttributeKeys),
+		resourceKeys:   toSet(state.ResourceAttributeKeys),
+	}
+
+	for _, policy := range state.Policies {
+		for _, role := range policy.Roles {
+			a.policiesByRole[role.ID] = append(a.policiesByRole[role.ID], policy)
+		}
+	}
+
+	return a
+}
+
+// Analyze runs all the rules on the given state.
+func Analyze(state *State) *Report {
+	a := newAnalyzer(state)
+	report := &Report{Findings: []*Finding{}}
+
+	a.checkRoles(report)
+	a.checkPrincipals(report)
+
+	for _, policy := range state.Policies {
+		if len(policy.Roles) == 0 {
+			report.add(RuleUnattachedPolicy, SeverityWarning, ObjectTypePolicy, policy.ID,
+				"policy %q is not attached to any role", policy.ID)
+		}
+
+		a.checkPolicy(report, policy)
+	}
+
+	report.sort()
+
+	return report
+}
+
+// AnalyzePolicy runs the rules concerning the content of the given policy,
+// which may not be saved yet, against the other policies of the state.
+func AnalyzePolicy(state *State, policy *model.Policy) *Report {
+	a := newAnalyzer(state)
+	a.candidate = true
+
+	report := &Report{Findings: []*Finding{}}
+
+	a.checkPolicy(report, policy)
+
+	report.sort()
+
+	return report
+}
+
+func (a *analyzer) checkRoles(report *Report) {
+	var rolesWithPrincipals = map[string]bool{}
+
+	for _, principal := range a.state.Principals {
+		for _, role := range principal.Roles {
+			rolesWithPrincipals[role.ID] = true
+		}
+	}
+
+	for _, role := range a.state.Roles {
+		if !rolesWithPrincipals[role.ID] {
+			report.add(RuleRoleWithoutPrincipals, SeverityWarning, ObjectTypeRole, role.ID,
+				"role %q is not given to any principal", role.ID)
+		}
+	}
+}
+
+// checkPrincipals reports principals that are allowed to write policies and to
+// attach them to themselves, which means they can grant themselves anything.
+// Locked principals (such as the default admin user) are expected to do so.
+func (a *analyzer) checkPrincipals(report *Report) {
+	for _, principal := range a.state.Principals {
+		if principal.IsLocked {
+			continue
+		}
+
+		var policies []*model.Policy
+		for _, role := range principal.Roles {
+			policies = append(policies, a.policiesByRole[role.ID]...)
+		}
+
+		canWritePolicies := grants(policies, "policies", "create", "update")
+		canAttachPolicies := grants(policies, "roles", "update") || grants(policies, "principals", "update")
+
+		if canWritePolicies && canAttachPolicies {
+			report.add(RuleAdminEquivalentPrincipal, SeverityWarning, ObjectTypePrincipal, principal.ID,
+				"principal %q can write policies and attach them to itself, which is equivalent to admin rights", principal.ID)
+		}
+	}
+}
+
+func (a *analyzer) checkPolicy(report *Report, policy *model.Policy) {
+	a.checkVanishedResourceKinds(report, policy)
+	a.checkAttributeRules(report, policy)
+	a.checkRedundancy(report, policy)
+}
+
+func (a *analyzer) checkVanishedResourceKinds(report *Report, policy *model.Policy) {
+	if len(policy.Resources) == 0 {
+		return
+	}
+
+	var vanishedKinds []string
+
+	for _, resource := range policy.Resources {
+		// Locked wildcards are managed by Authz itself and have no other resources.
+		if resource.Value != wildcardValue || resource.IsLocked || a.resourceKinds[resource.Kind] {
+			return
+		}
+
+		vanishedKinds = append(vanishedKinds, resource.Kind)
+	}
+
+	report.add(RuleVanishedResourceKind, SeverityError, ObjectTypePolicy, policy.ID,
+		"policy %q only references wildcard resources of kinds without any resource left: %s",
+		policy.ID, strings.Join(sortedUnique(vanishedKinds), ", "))
+}
+
+func (a *analyzer) checkAttributeRules(report *Report, policy *model.Policy) {
+	for _, attributeRule := range policy.AttributeRules.Data() {
+		rule, err := attribute.ConvertStringToRuleOperator(attributeRule)
+		if err != nil {
+			continue
+		}
+
+		if rule.PrincipalAttribute != "" && !a.principalKeys[rule.PrincipalAttribute] {
+			report.add(RuleUnknownAttribute, SeverityError, ObjectTypePolicy, policy.ID,
+				"policy %q attribute rule %q references principal attribute %q that no principal has",
+				policy.ID, attributeRule, rule.PrincipalAttribute)
+		}
+
+		if rule.ResourceAttribute != "" && !a.resourceKeys[rule.ResourceAttribute] {
+			report.add(RuleUnknownAttribute, SeverityError, ObjectTypePolicy, policy.ID,
+				"policy %q attribute rule %q references resource attribute %q that no resource has",
+				policy.ID, attributeRule, rule.ResourceAttribute)
+		}
+	}
+}
+
+// checkRedundancy reports a policy that is identical to another one, or that
+// is covered by another one having the same effect, no attribute rules nor
+// time constraints and given to (at least) the same roles.
+func (a *analyzer) checkRedundancy(report *Report, policy *model.Policy) {
+	var others []*model.Policy
+
+	for _, other := range a.state.Policies {
+		if other.ID != policy.ID {
+			others = append(others, other)
+		}
+	}
+
+	sort.SliceStable(others, func(i, j int) bool {
+		return others[i].ID < others[j].ID
+	})
+
+	// When analyzing the whole model, a pair of duplicate (or mutually
+	// shadowing) policies is only reported on the policy sorted last.
+	isReported := func(other *model.Policy) bool {
+		return a.candidate || other.ID < policy.ID
+	}
+
+	for _, other := range others {
+		if signature(other) == signature(policy) && isReported(other) {
+			report.add(RuleDuplicatePolicy, SeverityWarning, ObjectTypePolicy, policy.ID,
+				"policy %q is a duplicate of policy %q", policy.ID, other.ID)
+
+			return
+		}
+	}
+
+	if len(policy.Roles) == 0 {
+		return
+	}
+
+	for _, other := range others {
+		if signature(other) == signature(policy) {
+			continue
+		}
+
+		if shadows(other, policy) && (!shadows(policy, other) || isReported(other)) {
+			report.add(RuleShadowedPolicy, SeverityWarning, ObjectTypePolicy, policy.ID,
+				"policy %q is shadowed by policy %q", policy.ID, other.ID)
+
+			return
+		}
+	}
+}
+
+// shadows returns whether the policy makes the other one useless.
+func shadows(policy *model.Policy, other *model.Policy) bool {
+	if effect(policy) != effect(other) ||
+		len(policy.AttributeRules.Data()) > 0 ||
+		policy.HasTimeConstraints() {
+		return false
+	}
+
+	if !toSet(roleIDs(policy.Roles)).containsAll(roleIDs(other.Roles)) {
+		return false
+	}
+
+	if !toSet(actionIDs(policy.Actions)).containsAll(actionIDs(other.Actions)) {
+		return false
+	}
+
+	for _, resource := range other.Resources {
+		if !coversResource(policy, resource) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func coversResource(policy *model.Policy, resource *model.Resource) bool {
+	for _, policyResource := range policy.Resources {
+		if policyResource.ID == resource.ID ||
+			(policyResource.Value == wildcardValue && policyResource.Kind == resource.Kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// grants returns whether one of the policies allows one of the actions on the
+// given Authz administration resource type.
+func grants(policies []*model.Policy, resourceType string, actions ...string) bool {
+	kind := fmt.Sprintf("%s.%s", configs.ApplicationName, resourceType)
+
+	for _, policy := range policies {
+		if policy.IsDeny() {
+			continue
+		}
+
+		var hasKind bool
+		for _, resource := range policy.Resources {
+			if resource.Kind == kind {
+				hasKind = true
+				break
+			}
+		}
+
+		if hasKind && toSet(actionIDs(policy.Actions)).containsAny(actions) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func signature(policy *model.Policy) string {
+	var resources []string
+	for _, resource := range policy.Resources {
+		resources = append(resources, resource.ID)
+	}
+
+	return strings.Join([]string{
+		strings.Join(sortedUnique(resources), ","),
+		strings.Join(sortedUnique(actionIDs(policy.Actions)), ","),
+		strings.Join(sortedUnique(policy.AttributeRules.Data()), ","),
+		string(effect(policy)),
+		fmt.Sprint(policy.Priority),
+		formatTime(policy.NotBefore),
+		formatTime(policy.NotAfter),
+		policy.Schedule,
+		policy.ScheduleDuration,
+		policy.ScheduleTimezone,
+	}, "|")
+}
+
+func effect(policy *model.Policy) model.PolicyEffect {
+	if policy.Effect == "" {
+		return model.PolicyEffectAllow
+	}
+
+	return policy.Effect
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func actionIDs(actions []*model.Action) []string {
+	var result = make([]string, 0, len(actions))
+
+	for _, action := range actions {
+		result = append(result, action.ID)
+	}
+
+	return result
+}
+
+func roleIDs(roles []*model.Role) []string {
+	var result = make([]string, 0, len(roles))
+
+	for _, role := range roles {
+		result = append(result, role.ID)
+	}
+
+	return result
+}
+
+type set map[string]bool
+
+func toSet(values []string) set {
+	var result = set{}
+
+	for _, value := range values {
+		result[value] = true
+	}
+
+	return result
+}
+
+func (s set) containsAll(values []string) bool {
+	for _, value := range values {
+		if !s[value] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s set) containsAny(values []string) bool {
+	for _, value := range values {
+		if s[value] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedUnique(values []string) []string {
+	var result = make([]string, 0, len(values))
+
+	for value := range toSet(values) {
+		result = append(result, value)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func newPolicy(id string, resources []*model.Resource, actions []string, roles ...string) *model.Policy {
+	policy := &model.Policy{ID: id, Resources: resources, Effect: model.PolicyEffectAllow}
+
+	for _, action := range actions {
+		policy.Actions = append(policy.Actions, &model.Action{ID: action})
+	}
+
+	for _, role := range roles {
+		policy.Roles = append(policy.Roles, &model.Role{ID: role})
+	}
+
+	return policy
+}
+
+func newResource(kind string, value string) *model.Resource {
+	return &model.Resource{ID: kind + "." + value, Kind: kind, Value: value}
+}
+
+func TestAnalyze(t *testing.T) {
+	// Given
+	ruled := newPolicy("post-owner", []*model.Resource{newResource("post", "*")}, []string{"edit"}, "editor")
+	ruled.AttributeRules = datatypes.NewJSONType([]string{
+		"resource.owner_id == principal.id",
+		"principal.team == blue",
+	})
+
+	lockedWildcard := newResource("authz.policies", "*")
+	lockedWildcard.IsLocked = true
+
+	state := &State{
+		Policies: []*model.Policy{
+			newPolicy("post-read", []*model.Resource{newResource("post", "*")}, []string{"read"}, "reader", "editor"),
+			newPolicy("post-read-123", []*model.Resource{newResource("post", "123")}, []string{"read"}, "editor"),
+			newPolicy("post-read-copy", []*model.Resource{newResource("post", "*")}, []string{"read"}, "other"),
+			newPolicy("comment-read", []*model.Resource{newResource("comment", "*")}, []string{"read"}),
+			newPolicy("policies-write", []*model.Resource{lockedWildcard}, []string{"create", "update"}, "ops"),
+			newPolicy("roles-write", []*model.Resource{newResource("authz.roles", "*")}, []string{"update"}, "ops"),
+			ruled,
+		},
+		Roles: []*model.Role{{ID: "editor"}, {ID: "ops"}, {ID: "other"}, {ID: "reader"}},
+		Principals: []*model.Principal{
+			{ID: "alice", Roles: []*model.Role{{ID: "editor"}, {ID: "ops"}}},
+			{ID: "bob", Roles: []*model.Role{{ID: "reader"}}},
+			{ID: "authz-user-admin", Roles: []*model.Role{{ID: "ops"}}, IsLocked: true},
+		},
+		ResourceKinds:          []string{"authz.roles", "post"},
+		PrincipalAttributeKeys: []string{"id"},
+		ResourceAttributeKeys:  []string{"owner_id"},
+	}
+
+	// When
+	report := Analyze(state)
+
+	// Then
+	assert.True(t, report.HasErrors())
+	assert.Equal(t, []*Finding{
+		{
+			Rule:       RuleVanishedResourceKind,
+			Severity:   SeverityError,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "comment-read",
+			Message:    `policy "comment-read" only references wildcard resources of kinds without any resource left: comment`,
+		},
+		{
+			Rule:       RuleUnknownAttribute,
+			Severity:   SeverityError,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "post-owner",
+			Message:    `policy "post-owner" attribute rule "principal.team == blue" references principal attribute "team" that no principal has`,
+		},
+		{
+			Rule:       RuleUnattachedPolicy,
+			Severity:   SeverityWarning,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "comment-read",
+			Message:    `policy "comment-read" is not attached to any role`,
+		},
+		{
+			Rule:       RuleShadowedPolicy,
+			Severity:   SeverityWarning,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "post-read-123",
+			Message:    `policy "post-read-123" is shadowed by policy "post-read"`,
+		},
+		{
+			Rule:       RuleDuplicatePolicy,
+			Severity:   SeverityWarning,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "post-read-copy",
+			Message:    `policy "post-read-copy" is a duplicate of policy "post-read"`,
+		},
+		{
+			Rule:       RuleAdminEquivalentPrincipal,
+			Severity:   SeverityWarning,
+			ObjectType: ObjectTypePrincipal,
+			ObjectID:   "alice",
+			Message:    `principal "alice" can write policies and attach them to itself, which is equivalent to admin rights`,
+		},
+		{
+			Rule:       RuleRoleWithoutPrincipals,
+			Severity:   SeverityWarning,
+			ObjectType: ObjectTypeRole,
+			ObjectID:   "other",
+			Message:    `role "other" is not given to any principal`,
+		},
+	}, report.Findings)
+}
+
+func TestAnalyzePolicy(t *testing.T) {
+	// Given
+	state := &State{
+		Policies: []*model.Policy{
+			newPolicy("post-read", []*model.Resource{newResource("post", "*")}, []string{"read"}, "reader"),
+		},
+		ResourceKinds: []string{"post"},
+	}
+
+	policy := newPolicy("post-read-again", []*model.Resource{newResource("post", "*")}, []string{"read"})
+	policy.AttributeRules = datatypes.NewJSONType([]string{"resource.owner_id == 123"})
+
+	// When
+	report := AnalyzePolicy(state, policy)
+
+	// Then
+	assert.Equal(t, []*Finding{
+		{
+			Rule:       RuleUnknownAttribute,
+			Severity:   SeverityError,
+			ObjectType: ObjectTypePolicy,
+			ObjectID:   "post-read-again",
+			Message:    `policy "post-read-again" attribute rule "resource.owner_id == 123" references resource attribute "owner_id" that no resource has`,
+		},
+	}, report.Findings)
+}
+
+func TestAnalyzePolicy_WhenDuplicate(t *testing.T) {
+	// Given
+	state := &State{
+		Policies: []*model.Policy{
+			newPolicy("post-read", []*model.Resource{newResource("post", "*")}, []string{"read"}, "reader"),
+		},
+		ResourceKinds: []string{"post"},
+	}
+
+	// When
+	report := AnalyzePolicy(state, newPolicy("a-post-read", []*model.Resource{newResource("post", "*")}, []string{"read"}))
+
+	// Then
+	assert.False(t, report.HasErrors())
+	assert.Len(t, report.Findings, 1)
+	assert.Equal(t, RuleDuplicatePolicy, report.Findings[0].Rule)
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+)
+
+type Linter interface {
+	Lint() (*Report, error)
+	ValidatePolicy(policy *model.Policy) error
+}
+
+type linter struct {
+	cfg                 *configs.App
+	policyRepository    repository.Base[model.Policy]
+	principalRepository repository.Principal
+	resourceRepository  repository.Resource
+	roleRepository      repository.Base[model.Role]
+}
+
+// NewLinter initializes a new linter inspecting the stored model.
+func NewLinter(
+	cfg *configs.App,
+	policyRepository repository.Base[model.Policy],
+	principalRepository repository.Principal,
+	resourceRepository repository.Resource,
+	roleRepository repository.Base[model.Role],
+) Linter {
+	return &linter{
+		cfg:                 cfg,
+		policyRepository:    policyRepository,
+		principalRepository: principalRepository,
+		resourceRepository:  resourceRepository,
+		roleRepository:      roleRepository,
+	}
+}
+
+// Lint analyzes the whole model.
+func (l *linter) Lint() (*Report, error) {
+	state, err := l.policyState()
+	if err != nil {
+		return nil, err
+	}
+
+	state.Roles, _, err = l.roleRepository.Find(repository.WithSkipPagination(), repository.WithSort("id asc"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve roles: %v", err)
+	}
+
+	state.Principals, _, err = l.principalRepository.Find(
+		repository.WithPreloads("Roles"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principals: %v", err)
+	}
+
+	return Analyze(state), nil
+}
+
+// ValidatePolicy returns an error when blocking is enabled and the analysis of
+// the given policy reports errors.
+func (l *linter) ValidatePolicy(policy *model.Policy) error {
+	if !l.cfg.PolicyLintBlocking {
+		return nil
+	}
+
+	state, err := l.policyState()
+	if err != nil {
+		return err
+	}
+
+	errors := AnalyzePolicy(state, policy).Errors()
+	if len(errors) == 0 {
+		return nil
+	}
+
+	var messages = make([]string, 0, len(errors))
+	for _, finding := range errors {
+		messages = append(messages, finding.Message)
+	}
+
+	return fmt.Errorf("policy has lint errors: %s", strings.Join(messages, ", "))
+}
+
+// policyState retrieves the part of the model needed to analyze policies.
+func (l *linter) policyState() (*State, error) {
+	policies, _, err := l.policyRepository.Find(
+		repository.WithPreloads("Resources", "Actions", "Roles"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve policies: %v", err)
+	}
+
+	resourceKinds, err := l.resourceRepository.FindKinds(repository.WithoutWildcards())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource kinds: %v", err)
+	}
+
+	principalAttributeKeys, err := l.principalRepository.FindAttributeKeys()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principal attribute keys: %v", err)
+	}
+
+	resourceAttributeKeys, err := l.resourceRepository.FindAttributeKeys()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve resource attribute keys: %v", err)
+	}
+
+	return &State{
+		Policies:               policies,
+		ResourceKinds:          resourceKinds,
+		PrincipalAttributeKeys: principalAttributeKeys,
+		ResourceAttributeKeys:  resourceAttributeKeys,
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/lint/linter.go
+
+// Package lint is a generated GoMock package.
+package lint
+
+import (
+	reflect "reflect"
+
+	model "github.com/eko/authz/backend/internal/entity/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLinter is a mock of Linter interface.
+type MockLinter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinterMockRecorder
+}
+
+// MockLinterMockRecorder is the mock recorder for MockLinter.
+type MockLinterMockRecorder struct {
+	mock *MockLinter
+}
+
+// NewMockLinter creates a new mock instance.
+func NewMockLinter(ctrl *gomock.Controller) *MockLinter {
+	mock := &MockLinter{ctrl: ctrl}
+	mock.recorder = &MockLinterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinter) EXPECT() *MockLinterMockRecorder {
+	return m.recorder
+}
+
+// Lint mocks base method.
+func (m *MockLinter) Lint() (*Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lint")
+	ret0, _ := ret[0].(*Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lint indicates an expected call of Lint.
+func (mr *MockLinterMockRecorder) Lint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lint", reflect.TypeOf((*MockLinter)(nil).Lint))
+}
+
+// ValidatePolicy mocks base method.
+func (m *MockLinter) ValidatePolicy(policy *model.Policy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidatePolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidatePolicy indicates an expected call of ValidatePolicy.
+func (mr *MockLinterMockRecorder) ValidatePolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePolicy", reflect.TypeOf((*MockLinter)(nil).ValidatePolicy), policy)
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	entitymanager "github.com/eko/authz/backend/internal/entity/manager"
+)
+
+const (
+	// NameClaim is the claim always copied to the "name" principal attribute.
+	NameClaim = "name"
+
+	mappingSeparator = "="
+)
+
+// ClaimsMapper maps ID token claims to principal attributes and roles.
+type ClaimsMapper struct {
+	attributes   map[string]string
+	groupsClaim  string
+	groupsRoles  map[string][]string
+	managedRoles map[string]bool
+}
+
+// NewClaimsMapper initializes a claims mapper from the "claim=attribute" and
+// "group=role" mappings of the configuration.
+func NewClaimsMapper(cfg *configs.OAuth) (*ClaimsMapper, error) {
+	mapper := &ClaimsMapper{
+		attributes:   map[string]string{NameClaim: NameClaim},
+		groupsClaim:  cfg.GroupsClaim,
+		groupsRoles:  map[string][]string{},
+		managedRoles: map[string]bool{},
+	}
+
+	for _, mapping := range cfg.ClaimsAttributes {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		claim, attribute := mapping, mapping
+		if index := strings.LastIndex(mapping, mappingSeparator); index != -1 {
+			claim, attribute = mapping[:index], mapping[index+1:]
+		}
+
+		if claim == "" || attribute == "" {
+			return nil, fmt.Errorf("invalid claim attribute mapping %q, expected claim or claim=attribute", mapping)
+		}
+
+		mapper.attributes[claim] = attribute
+	}
+
+	for _, mapping := range cfg.GroupsRoles {
+		mapping = strings.TrimSpace(mapping)
+		if mapping == "" {
+			continue
+		}
+
+		group, role, found := strings.Cut(mapping, mappingSeparator)
+		if !found || group == "" || role == "" {
+			return nil, fmt.Errorf("invalid group role mapping %q, expected group=role", mapping)
+		}
+
+		mapper.groupsRoles[group] = append(mapper.groupsRoles[group], role)
+		mapper.managedRoles[role] = true
+	}
+
+	return mapper, nil
+}
+
+// Attributes returns the principal attributes copied from the given claims.
+// List claims are joined with commas.
+func (m *ClaimsMapper) Attributes(claims map[string]any) map[string]any {
+	var attributes = map[string]any{}
+
+	for claim, attribute := range m.attributes {
+		value, ok := claims[claim]
+		if !ok {
+			continue
+		}
+
+		if stringValue, ok := claimToString(value); ok {
+			attributes[attribute] = stringValue
+		}
+	}
+
+	return attributes
+}
+
+// IsManagedAttribute returns whether the attribute is copied from claims, in
+// which case it is removed from the principal when the claim is missing.
+func (m *ClaimsMapper) IsManagedAttribute(attribute string) bool {
+	for _, mapped := range m.attributes {
+		if mapped == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Roles returns the roles mapped from the groups claim, sorted.
+func (m *ClaimsMapper) Roles(claims map[string]any) []string {
+	var (
+		roles = []string{}
+		seen  = map[string]bool{}
+	)
+
+	for _, group := range claimToStrings(claims[m.groupsClaim]) {
+		for _, role := range m.groupsRoles[group] {
+			if seen[role] {
+				continue
+			}
+
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	sort.Strings(roles)
+
+	return roles
+}
+
+// IsManagedRole returns whether the role is mapped from a group, in which case
+// it is removed from the principal when the user is not in the group anymore.
+// Other roles given to the principal are left untouched.
+func (m *ClaimsMapper) IsManagedRole(role string) bool {
+	return m.managedRoles[role]
+}
+
+func claimToString(value any) (string, bool) {
+	if values, ok := value.([]any); ok {
+		return strings.Join(claimToStrings(values), ","), true
+	}
+
+	result, err := entitymanager.CastAnyToString(value)
+
+	return result, err == nil
+}
+
+func claimToStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var result = make([]string, 0, len(v))
+		for _, item := range v {
+			if stringValue, err := entitymanager.CastAnyToString(item); err == nil {
+				result = append(result, stringValue)
+			}
+		}
+
+		return result
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClaimsMapper_InvalidGroupRole(t *testing.T) {
+	// Given
+	cfg := &configs.OAuth{
+		GroupsRoles: []string{"engineering"},
+	}
+
+	// When
+	mapper, err := NewClaimsMapper(cfg)
+
+	// Then
+	assert.Nil(t, mapper)
+	assert.EqualError(t, err, `invalid group role mapping "engineering", expected group=role`)
+}
+
+func TestClaimsMapper_Attributes(t *testing.T) {
+	// Given
+	cfg := &configs.OAuth{
+		ClaimsAttributes: []string{"email", "https://acme.tld/department=department", "groups"},
+	}
+
+	mapper, err := NewClaimsMapper(cfg)
+	assert.Nil(t, err)
+
+	claims := map[string]any{
+		"name":                        "John Doe",
+		"email":                       "john.doe@acme.tld",
+		"https://acme.tld/department": "sales",
+		"groups":                      []any{"engineering", "admins"},
+		"email_verified":              true,
+	}
+
+	// When
+	attributes := mapper.Attributes(claims)
+
+	// Then
+	assert.Equal(t, map[string]any{
+		"name":       "John Doe",
+		"email":      "john.doe@acme.tld",
+		"department": "sales",
+		"groups":     "engineering,admins",
+	}, attributes)
+
+	assert.True(t, mapper.IsManagedAttribute("department"))
+	assert.False(t, mapper.IsManagedAttribute("email_verified"))
+}
+
+func TestClaimsMapper_Roles(t *testing.T) {
+	// Given
+	cfg := &configs.OAuth{
+		GroupsClaim: "groups",
+		GroupsRoles: []string{"engineering=developer", "admins=admin", "admins=developer"},
+	}
+
+	mapper, err := NewClaimsMapper(cfg)
+	assert.Nil(t, err)
+
+	// When
+	roles := mapper.Roles(map[string]any{
+		"groups": []any{"admins", "engineering", "sales"},
+	})
+
+	// Then
+	assert.Equal(t, []string{"admin", "developer"}, roles)
+
+	assert.Equal(t, []string{"developer"}, mapper.Roles(map[string]any{"groups": "engineering"}))
+	assert.Equal(t, []string{}, mapper.Roles(map[string]any{}))
+
+	assert.True(t, mapper.IsManagedRole("admin"))
+	assert.False(t, mapper.IsManagedRole("auditor"))
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	entitymanager "github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"golang.org/x/exp/slog"
+	"gorm.io/gorm"
+)
+
+type PrincipalSyncer interface {
+	Sync(identifier string, claims map[string]any) (*model.Principal, error)
+}
+
+type principalSyncer struct {
+	mapper           *ClaimsMapper
+	logger           *slog.Logger
+	principalManager entitymanager.Principal
+	roleRepository   entitymanager.RoleRepository
+}
+
+// NewPrincipalSyncer initializes a syncer creating or updating principals from
+// the claims of their ID token.
+func NewPrincipalSyncer(
+	mapper *ClaimsMapper,
+	logger *slog.Logger,
+	principalManager entitymanager.Principal,
+	roleRepository entitymanager.RoleRepository,
+) PrincipalSyncer {
+	return &principalSyncer{
+		mapper:           mapper,
+		logger:           logger,
+		principalManager: principalManager,
+		roleRepository:   roleRepository,
+	}
+}
+
+// Sync creates the principal or updates its attributes and roles mapped from
+// the given claims. The principal is only updated (and a principal event
+// dispatched) when something changed.
+func (s *principalSyncer) Sync(identifier string, claims map[string]any) (*model.Principal, error) {
+	attributes := s.mapper.Attributes(claims)
+
+	mappedRoles, err := s.existingRoles(identifier, s.mapper.Roles(claims))
+	if err != nil {
+		return nil, err
+	}
+
+	principal, err := s.principalManager.GetRepository().Get(
+		identifier,
+		repository.WithPreloads("Roles", "Attributes"),
+	)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		principal, err = s.principalManager.Create(identifier, mappedRoles, attributes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create principal: %v", err)
+		}
+
+		return principal, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to retrieve principal: %v", err)
+	}
+
+	var (
+		currentAttributes = map[string]any{}
+		currentRoles      = []string{}
+		roles             = mappedRoles
+	)
+
+	for _, attribute := range principal.Attributes {
+		currentAttributes[attribute.Key] = attribute.Value
+
+		if _, ok := attributes[attribute.Key]; !ok && !s.mapper.IsManagedAttribute(attribute.Key) {
+			attributes[attribute.Key] = attribute.Value
+		}
+	}
+
+	for _, role := range principal.Roles {
+		currentRoles = append(currentRoles, role.ID)
+
+		if !s.mapper.IsManagedRole(role.ID) {
+			roles = append(roles, role.ID)
+		}
+	}
+
+	sort.Strings(roles)
+	sort.Strings(currentRoles)
+
+	if equalStrings(roles, currentRoles) && equalAttributes(attributes, currentAttributes) {
+		return principal, nil
+	}
+
+	principal, err = s.principalManager.Update(identifier, roles, attributes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update principal: %v", err)
+	}
+
+	return principal, nil
+}
+
+// existingRoles filters out mapped roles that do not exist, so a mapping
+// mistake does not prevent users from logging in.
+func (s *principalSyncer) existingRoles(identifier string, roles []string) ([]string, error) {
+	var result = make([]string, 0, len(roles))
+
+	for _, role := range roles {
+		_, err := s.roleRepository.Get(role)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("OAuth: mapped role does not exist",
+				slog.String("principal_id", identifier),
+				slog.String("role_id", role),
+			)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve role %q: %v", role, err)
+		}
+
+		result = append(result, role)
+	}
+
+	return result, nil
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalAttributes(a map[string]any, b map[string]any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok {
+			return false
+		}
+
+		valueString, _ := entitymanager.CastAnyToString(value)
+		otherString, _ := entitymanager.CastAnyToString(other)
+
+		if valueString != otherString {
+			return false
+		}
+	}
+
+	return true
+}
@@ -11,7 +11,9 @@ import (
 func FxModule() fx.Option {
 	return fx.Module("oauth",
 		fx.Provide(
+			client.NewClaimsMapper,
 			client.NewManager,
+			client.NewPrincipalSyncer,
 
 			server.NewClientStore,
 			server.NewManager,
@@ -2,6 +2,7 @@ package metric
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/eko/authz/backend/configs"
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,18 +16,39 @@ var (
 
 	itemCreatedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "authz_item_counter",
-		Help: "The total number of items (resource, policy, ...) created or updated in database",
+		Help: "The total number of items (resource, policy, ...) created, updated or deleted in database",
 	}, []string{"item_type", "action"})
+
+	decisionCacheCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "authz_decision_cache_counter",
+		Help: "The total number of checks answered (hit) or not (miss) from the decision cache",
+	}, []string{"result"})
+
+	compileJobsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "authz_compile_jobs",
+		Help: "The number of compile jobs in queue, by status",
+	}, []string{"status"})
+
+	compileDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "authz_compile_duration_seconds",
+		Help: "The duration of compilations of policies, principals and resources",
+	}, []string{"target_type", "succeeded"})
 )
 
 type Observer interface {
 	ObserveCheckCounter(resourceKind string, isAllowed bool)
 	ObserveItemCreatedCounter(itemType, action string)
+	ObserveCompileDuration(targetType string, succeeded bool, duration time.Duration)
+	ObserveCompileJobs(status string, total int64)
+	ObserveDecisionCache(result string)
 }
 
 type observer struct {
-	checkCounter       *prometheus.CounterVec
-	itemCreatedCounter *prometheus.CounterVec
+	checkCounter             *prometheus.CounterVec
+	itemCreatedCounter       *prometheus.CounterVec
+	compileDurationHistogram *prometheus.HistogramVec
+	compileJobsGauge         *prometheus.GaugeVec
+	decisionCacheCounter     *prometheus.CounterVec
 }
 
 func NewObserver(
@@ -37,8 +59,11 @@ func NewObserver(
 	}
 
 	observer := &observer{
-		checkCounter:       checkCounter,
-		itemCreatedCounter: itemCreatedCounter,
+		checkCounter:             checkCounter,
+		itemCreatedCounter:       itemCreatedCounter,
+		compileDurationHistogram: compileDurationHistogram,
+		compileJobsGauge:         compileJobsGauge,
+		decisionCacheCounter:     decisionCacheCounter,
 	}
 
 	if err := observer.initialize(); err != nil {
@@ -57,6 +82,18 @@ func (r *observer) initialize() (err error) {
 		return err
 	}
 
+	if err := prometheus.Register(compileDurationHistogram); err != nil {
+		return err
+	}
+
+	if err := prometheus.Register(compileJobsGauge); err != nil {
+		return err
+	}
+
+	if err := prometheus.Register(decisionCacheCounter); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -73,3 +110,22 @@ func (r *observer) ObserveItemCreatedCounter(itemType string, action string) {
 		action,
 	).Inc()
 }
+
+func (r *observer) ObserveCompileDuration(targetType string, succeeded bool, duration time.Duration) {
+	r.compileDurationHistogram.WithLabelValues(
+		targetType,
+		strconv.FormatBool(succeeded),
+	).Observe(duration.Seconds())
+}
+
+func (r *observer) ObserveCompileJobs(status string, total int64) {
+	r.compileJobsGauge.WithLabelValues(
+		status,
+	).Set(float64(total))
+}
+
+func (r *observer) ObserveDecisionCache(result string) {
+	r.decisionCacheCounter.WithLabelValues(
+		result,
+	).Inc()
+}
@@ -6,6 +6,7 @@ package metric
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -45,6 +46,42 @@ func (mr *MockObserverMockRecorder) ObserveCheckCounter(resourceKind, isAllowed
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveCheckCounter", reflect.TypeOf((*MockObserver)(nil).ObserveCheckCounter), resourceKind, isAllowed)
 }
 
+// ObserveCompileDuration mocks base method.
+func (m *MockObserver) ObserveCompileDuration(targetType string, succeeded bool, duration time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveCompileDuration", targetType, succeeded, duration)
+}
+
+// ObserveCompileDuration indicates an expected call of ObserveCompileDuration.
+func (mr *MockObserverMockRecorder) ObserveCompileDuration(targetType, succeeded, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveCompileDuration", reflect.TypeOf((*MockObserver)(nil).ObserveCompileDuration), targetType, succeeded, duration)
+}
+
+// ObserveCompileJobs mocks base method.
+func (m *MockObserver) ObserveCompileJobs(status string, total int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveCompileJobs", status, total)
+}
+
+// ObserveCompileJobs indicates an expected call of ObserveCompileJobs.
+func (mr *MockObserverMockRecorder) ObserveCompileJobs(status, total interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveCompileJobs", reflect.TypeOf((*MockObserver)(nil).ObserveCompileJobs), status, total)
+}
+
+// ObserveDecisionCache mocks base method.
+func (m *MockObserver) ObserveDecisionCache(result string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveDecisionCache", result)
+}
+
+// ObserveDecisionCache indicates an expected call of ObserveDecisionCache.
+func (mr *MockObserverMockRecorder) ObserveDecisionCache(result interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveDecisionCache", reflect.TypeOf((*MockObserver)(nil).ObserveDecisionCache), result)
+}
+
 // ObserveItemCreatedCounter mocks base method.
 func (m *MockObserver) ObserveItemCreatedCounter(itemType, action string) {
 	m.ctrl.T.Helper()
@@ -9,6 +9,15 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+// itemCountedActions are the actions of item events changing the database,
+// counted by the item counter. Other actions (such as delegations expiring or
+// policy time windows opening) are detected by the sweeper on unchanged items.
+var itemCountedActions = map[event.ItemAction]bool{
+	event.ItemActionCreate: true,
+	event.ItemActionUpdate: true,
+	event.ItemActionDelete: true,
+}
+
 type subscriber struct {
 	enabled    bool
 	logger     *slog.Logger
@@ -36,6 +45,7 @@ func (s *subscriber) subscribe(lc fx.Lifecycle) {
 	}
 
 	checkEventChan := s.dispatcher.Subscribe(event.EventTypeCheck)
+	delegationEventChan := s.dispatcher.Subscribe(event.EventTypeDelegation)
 	policyEventChan := s.dispatcher.Subscribe(event.EventTypePolicy)
 	principalEventChan := s.dispatcher.Subscribe(event.EventTypePrincipal)
 	resourceEventChan := s.dispatcher.Subscribe(event.EventTypeResource)
@@ -45,6 +55,7 @@ func (s *subscriber) subscribe(lc fx.Lifecycle) {
 		OnStart: func(context.Context) error {
 			go s.handleCheckEvents(checkEventChan)
 
+			go s.handleItemEvents(delegationEventChan, "delegation")
 			go s.handleItemEvents(policyEventChan, "policy")
 			go s.handleItemEvents(principalEventChan, "principal")
 			go s.handleItemEvents(resourceEventChan, "resource")
@@ -56,7 +67,11 @@ func (s *subscriber) subscribe(lc fx.Lifecycle) {
 		},
 		OnStop: func(_ context.Context) error {
 			close(checkEventChan)
+			close(delegationEventChan)
 			close(policyEventChan)
+			close(principalEventChan)
+			close(resourceEventChan)
+			close(roleEventChan)
 
 			s.logger.Info("Metric: subscription to event dispatcher stopped")
 
@@ -77,6 +92,10 @@ func (s *subscriber) handleCheckEvents(eventChan chan *event.Event) {
 		}
 
 		s.observer.ObserveCheckCounter(checkEvent.ResourceKind, checkEvent.IsAllowed)
+
+		if checkEvent.CacheStatus != "" {
+			s.observer.ObserveDecisionCache(string(checkEvent.CacheStatus))
+		}
 	}
 }
 
@@ -87,7 +106,7 @@ func (s *subscriber) handleItemEvents(eventChan chan *event.Event, itemType stri
 		}
 
 		itemEvent, ok := eventItem.Data.(*event.ItemEvent)
-		if !ok {
+		if !ok || !itemCountedActions[itemEvent.Action] {
 			continue
 		}
 
@@ -55,6 +55,8 @@ func TestHandleCheckEvents_WhenEnabled(t *testing.T) {
 	observer := NewMockObserver(ctrl)
 	observer.EXPECT().ObserveCheckCounter("post", true).Times(2)
 	observer.EXPECT().ObserveCheckCounter("post", false).Times(1)
+	observer.EXPECT().ObserveDecisionCache("hit").Times(1)
+	observer.EXPECT().ObserveDecisionCache("miss").Times(1)
 
 	subscriber := NewSubscriber(cfg, logger, dispatcher, observer)
 
@@ -68,11 +70,11 @@ func TestHandleCheckEvents_WhenEnabled(t *testing.T) {
 		}
 		eventChan <- &event.Event{
 			Timestamp: 123456,
-			Data:      &event.CheckEvent{Principal: "user1", ResourceKind: "post", ResourceValue: "2", Action: "edit", IsAllowed: false},
+			Data:      &event.CheckEvent{Principal: "user1", ResourceKind: "post", ResourceValue: "2", Action: "edit", IsAllowed: false, CacheStatus: event.CacheStatusMiss},
 		}
 		eventChan <- &event.Event{
 			Timestamp: 123457,
-			Data:      &event.CheckEvent{Principal: "user1", ResourceKind: "post", ResourceValue: "3", Action: "delete", IsAllowed: true},
+			Data:      &event.CheckEvent{Principal: "user1", ResourceKind: "post", ResourceValue: "3", Action: "delete", IsAllowed: true, CacheStatus: event.CacheStatusHit},
 		}
 
 		close(eventChan)
@@ -135,6 +137,7 @@ func TestHandleItemEvents_WhenEnabled(t *testing.T) {
 	observer := NewMockObserver(ctrl)
 	observer.EXPECT().ObserveItemCreatedCounter("resource", "create").Times(2)
 	observer.EXPECT().ObserveItemCreatedCounter("resource", "update").Times(1)
+	observer.EXPECT().ObserveItemCreatedCounter("resource", "delete").Times(1)
 
 	subscriber := NewSubscriber(cfg, logger, dispatcher, observer)
 
@@ -154,6 +157,10 @@ func TestHandleItemEvents_WhenEnabled(t *testing.T) {
 			Timestamp: 123456,
 			Data:      &event.ItemEvent{Action: event.ItemActionCreate, Data: &model.Resource{ID: "5"}},
 		}
+		eventChan <- &event.Event{
+			Timestamp: 123456,
+			Data:      &event.ItemEvent{Action: event.ItemActionDelete, Data: &model.Resource{ID: "4"}},
+		}
 
 		close(eventChan)
 	}()
@@ -161,6 +168,36 @@ func TestHandleItemEvents_WhenEnabled(t *testing.T) {
 	subscriber.handleItemEvents(eventChan, "resource")
 }
 
+func TestHandleItemEvents_WhenNotChangingTheDatabase(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		MetricsEnabled: true,
+	}
+
+	observer := NewMockObserver(ctrl)
+	observer.EXPECT().ObserveItemCreatedCounter("delegation", "delete").Times(1)
+
+	subscriber := NewSubscriber(cfg, slog.New(log.NewNopHandler()), event.NewMockDispatcher(ctrl), observer)
+
+	eventChan := make(chan *event.Event)
+
+	// When - Then
+	go func() {
+		for _, action := range []event.ItemAction{event.ItemActionExpire, event.ItemActionDelete} {
+			eventChan <- &event.Event{
+				Timestamp: 123456,
+				Data:      &event.ItemEvent{Action: action, Data: &model.Delegation{ID: "1"}},
+			}
+		}
+
+		close(eventChan)
+	}()
+
+	subscriber.handleItemEvents(eventChan, "delegation")
+}
+
 func TestHandleItemEvents_WhenNotEnabled(t *testing.T) {
 	// Given
 	ctrl := gomock.NewController(t)
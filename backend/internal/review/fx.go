@@ -0,0 +1,16 @@
+package review
+
+import (
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("review",
+		fx.Provide(
+			NewReminder,
+		),
+		fx.Invoke(
+			RunReminder,
+		),
+	)
+}
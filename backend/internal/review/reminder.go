@@ -0,0 +1,111 @@
+package review
+
+import (
+	"context"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+type reminder struct {
+	logger        *slog.Logger
+	clock         time.Clock
+	reviewManager manager.ReviewCampaign
+	checkDelay    lib_time.Duration
+	reminderDelay lib_time.Duration
+}
+
+func NewReminder(
+	cfg *configs.App,
+	logger *slog.Logger,
+	clock time.Clock,
+	reviewManager manager.ReviewCampaign,
+) *reminder {
+	return &reminder{
+		logger:        logger,
+		clock:         clock,
+		reviewManager: reviewManager,
+		checkDelay:    cfg.ReviewCheckDelay,
+		reminderDelay: cfg.ReviewReminderDelay,
+	}
+}
+
+// check closes the open campaigns whose deadline is reached and reminds the
+// reviewers of the other ones once per reminder delay.
+func (r *reminder) check() error {
+	campaigns, _, err := r.reviewManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"status": {Operator: "=", Value: model.ReviewCampaignStatusOpen},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+
+	for _, campaign := range campaigns {
+		if !now.Before(campaign.Deadline) {
+			if _, err := r.reviewManager.Close(campaign.ID); err != nil {
+				r.logger.Error("Review: unable to close campaign", err, slog.String("campaign_id", campaign.ID))
+			} else {
+				r.logger.Info("Review: campaign closed", slog.String("campaign_id", campaign.ID))
+			}
+
+			continue
+		}
+
+		if !r.shouldRemind(campaign, now) {
+			continue
+		}
+
+		if err := r.reviewManager.Remind(campaign.ID); err != nil {
+			r.logger.Error("Review: unable to remind campaign reviewers", err, slog.String("campaign_id", campaign.ID))
+		}
+	}
+
+	return nil
+}
+
+func (r *reminder) shouldRemind(campaign *model.ReviewCampaign, now lib_time.Time) bool {
+	lastReminder := campaign.CreatedAt
+	if campaign.RemindedAt != nil {
+		lastReminder = *campaign.RemindedAt
+	}
+
+	return now.Sub(lastReminder) >= r.reminderDelay
+}
+
+func RunReminder(lc fx.Lifecycle, reminder *reminder) {
+	ticker := lib_time.NewTicker(reminder.checkDelay)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				for range ticker.C {
+					if err := reminder.check(); err != nil {
+						reminder.logger.Error("Review: unable to check review campaigns", err)
+					}
+				}
+			}()
+
+			reminder.logger.Info("Review: campaigns reminder started")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			ticker.Stop()
+
+			reminder.logger.Info("Review: campaigns reminder stopped")
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,74 @@
+package review
+
+import (
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewReminder(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		ReviewCheckDelay:    1 * lib_time.Minute,
+		ReviewReminderDelay: 24 * lib_time.Hour,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+	clock := time.NewMockClock(ctrl)
+	reviewManager := manager.NewMockReviewCampaign(ctrl)
+
+	// When
+	reminderInstance := NewReminder(cfg, logger, clock, reviewManager)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(reminder), reminderInstance)
+
+	assert.Equal(logger, reminderInstance.logger)
+	assert.Equal(clock, reminderInstance.clock)
+	assert.Equal(reviewManager, reminderInstance.reviewManager)
+	assert.Equal(cfg.ReviewCheckDelay, reminderInstance.checkDelay)
+	assert.Equal(cfg.ReviewReminderDelay, reminderInstance.reminderDelay)
+}
+
+func TestShouldRemind(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		ReviewCheckDelay:    1 * lib_time.Minute,
+		ReviewReminderDelay: 24 * lib_time.Hour,
+	}
+
+	now := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+	remindedAt := now.Add(-2 * lib_time.Hour)
+
+	reminderInstance := NewReminder(cfg, slog.New(log.NewNopHandler()), time.NewMockClock(ctrl), manager.NewMockReviewCampaign(ctrl))
+
+	// When - Then
+	assert := assert.New(t)
+
+	assert.True(reminderInstance.shouldRemind(&model.ReviewCampaign{
+		CreatedAt: now.Add(-25 * lib_time.Hour),
+	}, now))
+
+	assert.False(reminderInstance.shouldRemind(&model.ReviewCampaign{
+		CreatedAt: now.Add(-1 * lib_time.Hour),
+	}, now))
+
+	assert.False(reminderInstance.shouldRemind(&model.ReviewCampaign{
+		CreatedAt:  now.Add(-48 * lib_time.Hour),
+		RemindedAt: &remindedAt,
+	}, now))
+}
@@ -0,0 +1,206 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidPath   = errors.New("invalid path")
+	ErrInvalidValue  = errors.New("invalid value")
+	ErrMutability    = errors.New("immutable attribute")
+	ErrNotFound      = errors.New("resource not found")
+	ErrUniqueness    = errors.New("resource already exists")
+)
+
+const (
+	// MaxResults is the maximum number of resources returned by list endpoints.
+	MaxResults = 1000
+
+	operatorPresent = "pr"
+)
+
+var operators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true, operatorPresent: true,
+}
+
+// Expression is a single "attribute operator value" comparison.
+type Expression struct {
+	Attribute string
+	Operator  string
+	Value     string
+}
+
+// Filter is a conjunction of expressions, as sent by identity providers to
+// look resources up (for instance: userName eq "john.doe@acme.tld").
+type Filter struct {
+	Expressions []*Expression
+}
+
+// ParseFilter parses a SCIM filter. Only comparison operators combined with
+// "and" are supported. A nil filter is returned for an empty expression.
+func ParseFilter(expression string) (*Filter, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	filter := &Filter{}
+
+	for len(tokens) > 0 {
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("%w: incomplete expression %q", ErrInvalidFilter, expression)
+		}
+
+		current := &Expression{
+			Attribute: tokens[0],
+			Operator:  strings.ToLower(tokens[1]),
+		}
+
+		if !operators[current.Operator] {
+			return nil, fmt.Errorf("%w: unsupported operator %q", ErrInvalidFilter, tokens[1])
+		}
+
+		tokens = tokens[2:]
+
+		if current.Operator != operatorPresent {
+			if len(tokens) == 0 {
+				return nil, fmt.Errorf("%w: missing value for attribute %q", ErrInvalidFilter, current.Attribute)
+			}
+
+			current.Value = tokens[0]
+			tokens = tokens[1:]
+		}
+
+		filter.Expressions = append(filter.Expressions, current)
+
+		if len(tokens) > 0 {
+			if !strings.EqualFold(tokens[0], "and") {
+				return nil, fmt.Errorf("%w: unsupported logical operator %q", ErrInvalidFilter, tokens[0])
+			}
+
+			tokens = tokens[1:]
+
+			if len(tokens) == 0 {
+				return nil, fmt.Errorf("%w: missing expression after \"and\"", ErrInvalidFilter)
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// Matches returns whether all expressions match, given a function returning
+// the values of an attribute. Comparisons are case-insensitive.
+func (f *Filter) Matches(values func(attribute string) []string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, expression := range f.Expressions {
+		if !expression.matches(values(expression.Attribute)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e *Expression) matches(values []string) bool {
+	if e.Operator == operatorPresent {
+		for _, value := range values {
+			if value != "" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if e.Operator == "ne" {
+		for _, value := range values {
+			if strings.EqualFold(value, e.Value) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	expected := strings.ToLower(e.Value)
+
+	for _, value := range values {
+		value = strings.ToLower(value)
+
+		switch e.Operator {
+		case "eq":
+			if value == expected {
+				return true
+			}
+		case "co":
+			if strings.Contains(value, expected) {
+				return true
+			}
+		case "sw":
+			if strings.HasPrefix(value, expected) {
+				return true
+			}
+		case "ew":
+			if strings.HasSuffix(value, expected) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tokenize splits the expression on spaces, keeping quoted values together
+// and unquoting them.
+func tokenize(expression string) ([]string, error) {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+
+	for _, char := range expression {
+		switch {
+		case escaped:
+			current.WriteRune(char)
+			escaped = false
+		case quoted && char == '\\':
+			escaped = true
+		case char == '"':
+			if quoted {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			quoted = !quoted
+		case !quoted && (char == ' ' || char == '\t'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(char)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated string in %q", ErrInvalidFilter, expression)
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
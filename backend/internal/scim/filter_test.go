@@ -0,0 +1,89 @@
+package scim
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	// Given
+	expression := `userName eq "John.Doe@acme.tld" and active pr`
+
+	// When
+	filter, err := ParseFilter(expression)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, &Filter{
+		Expressions: []*Expression{
+			{Attribute: "userName", Operator: "eq", Value: "John.Doe@acme.tld"},
+			{Attribute: "active", Operator: "pr"},
+		},
+	}, filter)
+}
+
+func TestParseFilter_WhenEmpty(t *testing.T) {
+	// When
+	filter, err := ParseFilter("")
+
+	// Then
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.Matches(func(string) []string { return nil }))
+}
+
+func TestParseFilter_WhenInvalid(t *testing.T) {
+	testCases := map[string]string{
+		`userName eq`:                        `invalid filter: missing value for attribute "userName"`,
+		`userName gt "a"`:                    `invalid filter: unsupported operator "gt"`,
+		`userName eq "a" or userName eq "b"`: `invalid filter: unsupported logical operator "or"`,
+		`userName eq "a`:                     `invalid filter: unterminated string in "userName eq \"a"`,
+	}
+
+	for expression, expected := range testCases {
+		t.Run(expression, func(t *testing.T) {
+			// When
+			filter, err := ParseFilter(expression)
+
+			// Then
+			assert.Nil(t, filter)
+			assert.True(t, errors.Is(err, ErrInvalidFilter))
+			assert.EqualError(t, err, expected)
+		})
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	// Given
+	values := func(attribute string) []string {
+		switch attribute {
+		case "userName":
+			return []string{"john.doe@acme.tld"}
+		case "emails":
+			return []string{"john@acme.tld", "jd@acme.tld"}
+		}
+
+		return nil
+	}
+
+	testCases := map[string]bool{
+		`userName eq "JOHN.DOE@acme.tld"`:               true,
+		`userName ne "john.doe@acme.tld"`:               false,
+		`userName sw "john" and userName ew ".tld"`:     true,
+		`userName co "doe" and emails eq "jd@acme.tld"`: true,
+		`emails eq "jane@acme.tld"`:                     false,
+		`externalId pr`:                                 false,
+	}
+
+	for expression, expected := range testCases {
+		t.Run(expression, func(t *testing.T) {
+			filter, err := ParseFilter(expression)
+			assert.Nil(t, err)
+
+			// When - Then
+			assert.Equal(t, expected, filter.Matches(values))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package scim
+
+import "go.uber.org/fx"
+
+func FxModule() fx.Option {
+	return fx.Module("scim",
+		fx.Provide(
+			NewManager,
+		),
+	)
+}
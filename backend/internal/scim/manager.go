@@ -0,0 +1,694 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	attributeActive     = "active"
+	attributeEmail      = "email"
+	attributeExternalID = "external_id"
+	attributeFamilyName = "family_name"
+	attributeGivenName  = "given_name"
+	attributeName       = "name"
+)
+
+// managedAttributes are the principal attributes provisioned from SCIM users.
+// Other principal attributes are left untouched.
+var managedAttributes = []string{
+	attributeActive,
+	attributeEmail,
+	attributeExternalID,
+	attributeFamilyName,
+	attributeGivenName,
+	attributeName,
+}
+
+type Manager interface {
+	CreateUser(user *User) (*User, error)
+	DeleteUser(identifier string) error
+	GetUser(identifier string) (*User, error)
+	ListUsers(filter *Filter) ([]*User, error)
+	PatchUser(identifier string, request *PatchRequest) (*User, error)
+	ReplaceUser(identifier string, user *User) (*User, error)
+
+	CreateGroup(group *Group) (*Group, error)
+	DeleteGroup(identifier string) error
+	GetGroup(identifier string) (*Group, error)
+	ListGroups(filter *Filter) ([]*Group, error)
+	PatchGroup(identifier string, request *PatchRequest) (*Group, error)
+	ReplaceGroup(identifier string, group *Group) (*Group, error)
+}
+
+type scimManager struct {
+	principalManager manager.Principal
+	roleManager      manager.Role
+	userManager      manager.User
+}
+
+// NewManager initializes a new SCIM manager, provisioning users as Authz
+// users and principals and groups as role memberships.
+func NewManager(
+	principalManager manager.Principal,
+	roleManager manager.Role,
+	userManager manager.User,
+) Manager {
+	return &scimManager{
+		principalManager: principalManager,
+		roleManager:      roleManager,
+		userManager:      userManager,
+	}
+}
+
+func (m *scimManager) CreateUser(user *User) (*User, error) {
+	if user.UserName == "" {
+		return nil, fmt.Errorf("%w: userName is required", ErrInvalidValue)
+	}
+
+	_, err := m.userManager.GetRepository().GetByFields(map[string]repository.FieldValue{
+		"username": {Operator: "=", Value: user.UserName},
+	})
+	if err == nil {
+		return nil, fmt.Errorf("%w: a user already exists with userName %q", ErrUniqueness, user.UserName)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("unable to check for existing user: %v", err)
+	}
+
+	// Users provisioned by the identity provider authenticate with it, so
+	// they are given a random password that is never returned.
+	if _, err := m.userManager.Create(user.UserName, ""); err != nil {
+		return nil, fmt.Errorf("unable to create user: %v", err)
+	}
+
+	if err := m.saveUser(user.UserName, user); err != nil {
+		_ = m.userManager.Delete(user.UserName)
+		return nil, err
+	}
+
+	return m.GetUser(user.UserName)
+}
+
+func (m *scimManager) DeleteUser(identifier string) error {
+	if _, err := m.GetUser(identifier); err != nil {
+		return err
+	}
+
+	if err := m.userManager.Delete(identifier); err != nil {
+		return fmt.Errorf("unable to delete user: %v", err)
+	}
+
+	return nil
+}
+
+func (m *scimManager) GetUser(identifier string) (*User, error) {
+	user, err := m.userManager.GetRepository().GetByFields(map[string]repository.FieldValue{
+		"username": {Operator: "=", Value: identifier},
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: user %q", ErrNotFound, identifier)
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user: %v", err)
+	}
+
+	principal, err := m.principalManager.GetRepository().Get(
+		model.UserPrincipal(identifier),
+		repository.WithPreloads("Roles", "Attributes"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user principal: %v", err)
+	}
+
+	// Users managed by Authz itself (such as admin) cannot be provisioned.
+	if principal.IsLocked {
+		return nil, fmt.Errorf("%w: user %q", ErrNotFound, identifier)
+	}
+
+	return toUser(user, principal), nil
+}
+
+func (m *scimManager) ListUsers(filter *Filter) ([]*User, error) {
+	users, _, err := m.userManager.GetRepository().Find(
+		repository.WithSkipPagination(),
+		repository.WithSort("username asc"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve users: %v", err)
+	}
+
+	principals, _, err := m.principalManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"id": {Operator: "LIKE", Value: model.UserPrincipal("%")},
+		}),
+		repository.WithPreloads("Roles", "Attributes"),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve user principals: %v", err)
+	}
+
+	var principalsByID = make(map[string]*model.Principal, len(principals))
+	for _, principal := range principals {
+		principalsByID[principal.ID] = principal
+	}
+
+	var result = []*User{}
+
+	for _, user := range users {
+		principal, ok := principalsByID[model.UserPrincipal(user.Username)]
+		if !ok || principal.IsLocked {
+			continue
+		}
+
+		scimUser := toUser(user, principal)
+
+		if filter.Matches(scimUser.values) {
+			result = append(result, scimUser)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *scimManager) PatchUser(identifier string, request *PatchRequest) (*User, error) {
+	user, err := m.GetUser(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyUserPatch(user, request); err != nil {
+		return nil, err
+	}
+
+	return m.ReplaceUser(identifier, user)
+}
+
+func (m *scimManager) ReplaceUser(identifier string, user *User) (*User, error) {
+	if _, err := m.GetUser(identifier); err != nil {
+		return nil, err
+	}
+
+	if user.UserName != "" && user.UserName != identifier {
+		return nil, fmt.Errorf("%w: userName cannot be changed", ErrMutability)
+	}
+
+	if err := m.saveUser(identifier, user); err != nil {
+		return nil, err
+	}
+
+	return m.GetUser(identifier)
+}
+
+// saveUser updates the user principal attributes. Deactivated users lose
+// their roles, so they do not have access to anything anymore.
+func (m *scimManager) saveUser(identifier string, user *User) error {
+	principalID := model.UserPrincipal(identifier)
+
+	principal, err := m.principalManager.GetRepository().Get(
+		principalID,
+		repository.WithPreloads("Roles", "Attributes"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve user principal: %v", err)
+	}
+
+	var attributes = map[string]any{}
+
+	for _, attribute := range principal.Attributes {
+		if !isManagedAttribute(attribute.Key) {
+			attributes[attribute.Key] = attribute.Value
+		}
+	}
+
+	for key, value := range userAttributes(user) {
+		attributes[key] = value
+	}
+
+	var roles = []string{}
+
+	if user.IsActive() {
+		roles = roleIDs(principal.Roles)
+	}
+
+	if _, err := m.principalManager.Update(principalID, roles, attributes); err != nil {
+		return fmt.Errorf("unable to update user principal: %v", err)
+	}
+
+	return nil
+}
+
+func (m *scimManager) CreateGroup(group *Group) (*Group, error) {
+	if group.DisplayName == "" {
+		return nil, fmt.Errorf("%w: displayName is required", ErrInvalidValue)
+	}
+
+	if isReserved(group.DisplayName) {
+		return nil, fmt.Errorf("%w: role %q is managed by Authz and cannot be provisioned", ErrInvalidValue, group.DisplayName)
+	}
+
+	if _, err := m.GetGroup(group.DisplayName); errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf(
+			"%w: role %q does not exist, it has to be created with its policies before being provisioned",
+			ErrInvalidValue, group.DisplayName,
+		)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := m.setMembers(group.DisplayName, referenceValues(group.Members)); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(group.DisplayName)
+}
+
+// DeleteGroup removes all members of the group. The role itself is kept as
+// its policies are managed in Authz.
+func (m *scimManager) DeleteGroup(identifier string) error {
+	if _, err := m.GetGroup(identifier); err != nil {
+		return err
+	}
+
+	return m.setMembers(identifier, nil)
+}
+
+func (m *scimManager) GetGroup(identifier string) (*Group, error) {
+	if isReserved(identifier) {
+		return nil, fmt.Errorf("%w: group %q", ErrNotFound, identifier)
+	}
+
+	role, err := m.roleManager.GetRepository().Get(
+		identifier,
+		repository.WithPreloads("Principals"),
+	)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: group %q", ErrNotFound, identifier)
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to retrieve role: %v", err)
+	}
+
+	return toGroup(role), nil
+}
+
+func (m *scimManager) ListGroups(filter *Filter) ([]*Group, error) {
+	roles, _, err := m.roleManager.GetRepository().Find(
+		repository.WithPreloads("Principals"),
+		repository.WithSkipPagination(),
+		repository.WithSort("id asc"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve roles: %v", err)
+	}
+
+	var result = []*Group{}
+
+	for _, role := range roles {
+		if isReserved(role.ID) {
+			continue
+		}
+
+		group := toGroup(role)
+
+		if filter.Matches(group.values) {
+			result = append(result, group)
+		}
+	}
+
+	return result, nil
+}
+
+func (m *scimManager) PatchGroup(identifier string, request *PatchRequest) (*Group, error) {
+	group, err := m.GetGroup(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := operations(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var members = referenceValues(group.Members)
+
+	for _, operation := range operations {
+		path, err := parsePath(operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch path.attribute {
+		case "displayname":
+			if operation.Op == opRemove || stringValue(operation.Value, false) != identifier {
+				return nil, fmt.Errorf("%w: displayName cannot be changed", ErrMutability)
+			}
+		case "members":
+			values, err := memberValues(operation.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			members, err = patchMembers(members, operation.Op, path, values)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := m.setMembers(identifier, members); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(identifier)
+}
+
+func (m *scimManager) ReplaceGroup(identifier string, group *Group) (*Group, error) {
+	if _, err := m.GetGroup(identifier); err != nil {
+		return nil, err
+	}
+
+	if group.DisplayName != "" && group.DisplayName != identifier {
+		return nil, fmt.Errorf("%w: displayName cannot be changed", ErrMutability)
+	}
+
+	if err := m.setMembers(identifier, referenceValues(group.Members)); err != nil {
+		return nil, err
+	}
+
+	return m.GetGroup(identifier)
+}
+
+// setMembers gives the role to the given users and removes it from other
+// users. Only changed principals are updated, which recompiles their policies.
+func (m *scimManager) setMembers(roleID string, usernames []string) error {
+	group, err := m.GetGroup(roleID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		current = map[string]bool{}
+		desired = map[string]bool{}
+	)
+
+	for _, username := range referenceValues(group.Members) {
+		current[username] = true
+	}
+
+	for _, username := range usernames {
+		if _, err := m.GetUser(username); errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("%w: member %q is not a user", ErrInvalidValue, username)
+		} else if err != nil {
+			return err
+		}
+
+		desired[username] = true
+	}
+
+	for _, username := range usernames {
+		if !current[username] {
+			if err := m.updateRoles(username, roleID, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	for username := range current {
+		if !desired[username] {
+			if err := m.updateRoles(username, roleID, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *scimManager) updateRoles(username string, roleID string, add bool) error {
+	principalID := model.UserPrincipal(username)
+
+	principal, err := m.principalManager.GetRepository().Get(
+		principalID,
+		repository.WithPreloads("Roles", "Attributes"),
+	)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve user principal: %v", err)
+	}
+
+	var roles = []string{}
+	for _, role := range roleIDs(principal.Roles) {
+		if role != roleID {
+			roles = append(roles, role)
+		}
+	}
+
+	if add {
+		roles = append(roles, roleID)
+	}
+
+	var attributes = make(map[string]any, len(principal.Attributes))
+	for _, attribute := range principal.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+
+	if _, err := m.principalManager.Update(principalID, roles, attributes); err != nil {
+		return fmt.Errorf("unable to update user principal: %v", err)
+	}
+
+	return nil
+}
+
+func patchMembers(members []string, op string, path *path, values []string) ([]string, error) {
+	switch op {
+	case opAdd:
+		return append(members, values...), nil
+	case opReplace:
+		return values, nil
+	}
+
+	// Remove either members given by a filter (members[value eq "john"]),
+	// members given as value or all members.
+	if path.filter == nil && values == nil {
+		return nil, nil
+	}
+
+	var result []string
+
+	for _, member := range members {
+		var removed bool
+
+		if path.filter != nil {
+			removed = path.filter.Matches(func(attribute string) []string {
+				if strings.EqualFold(attribute, "value") {
+					return []string{member}
+				}
+
+				return nil
+			})
+		}
+
+		for _, value := range values {
+			removed = removed || value == member
+		}
+
+		if !removed {
+			result = append(result, member)
+		}
+	}
+
+	return result, nil
+}
+
+func toUser(user *model.User, principal *model.Principal) *User {
+	var attributes = map[string]string{}
+	for _, attribute := range principal.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+
+	active := attributes[attributeActive] != strconv.FormatBool(false)
+
+	result := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          user.Username,
+		ExternalID:  attributes[attributeExternalID],
+		UserName:    user.Username,
+		DisplayName: attributes[attributeName],
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: ResourceTypeUser,
+			Created:      &user.CreatedAt,
+			LastModified: &principal.UpdatedAt,
+		},
+	}
+
+	if attributes[attributeGivenName] != "" || attributes[attributeFamilyName] != "" {
+		result.Name = &Name{
+			GivenName:  attributes[attributeGivenName],
+			FamilyName: attributes[attributeFamilyName],
+		}
+	}
+
+	if email := attributes[attributeEmail]; email != "" {
+		result.Emails = []*Email{{Value: email, Primary: true}}
+	}
+
+	for _, role := range roleIDs(principal.Roles) {
+		if !isReserved(role) {
+			result.Groups = append(result.Groups, &Reference{Value: role, Display: role})
+		}
+	}
+
+	return result
+}
+
+func userAttributes(user *User) map[string]any {
+	var attributes = map[string]any{
+		attributeActive: strconv.FormatBool(user.IsActive()),
+	}
+
+	displayName := user.DisplayName
+
+	if user.Name != nil {
+		if user.Name.GivenName != "" {
+			attributes[attributeGivenName] = user.Name.GivenName
+		}
+
+		if user.Name.FamilyName != "" {
+			attributes[attributeFamilyName] = user.Name.FamilyName
+		}
+
+		if displayName == "" {
+			displayName = user.Name.Formatted
+		}
+	}
+
+	if displayName != "" {
+		attributes[attributeName] = displayName
+	}
+
+	if email := user.PrimaryEmail(); email != "" {
+		attributes[attributeEmail] = email
+	}
+
+	if user.ExternalID != "" {
+		attributes[attributeExternalID] = user.ExternalID
+	}
+
+	return attributes
+}
+
+func (u *User) values(attribute string) []string {
+	switch strings.ToLower(attribute) {
+	case "id", "username":
+		return []string{u.UserName}
+	case "externalid":
+		return []string{u.ExternalID}
+	case "displayname":
+		return []string{u.DisplayName}
+	case "active":
+		return []string{strconv.FormatBool(u.IsActive())}
+	case "emails", "emails.value":
+		var result []string
+		for _, email := range u.Emails {
+			result = append(result, email.Value)
+		}
+
+		return result
+	case "name.givenname":
+		if u.Name != nil {
+			return []string{u.Name.GivenName}
+		}
+	case "name.familyname":
+		if u.Name != nil {
+			return []string{u.Name.FamilyName}
+		}
+	}
+
+	return nil
+}
+
+func toGroup(role *model.Role) *Group {
+	group := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          role.ID,
+		DisplayName: role.ID,
+		Meta: &Meta{
+			ResourceType: ResourceTypeGroup,
+			Created:      &role.CreatedAt,
+			LastModified: &role.UpdatedAt,
+		},
+	}
+
+	var usernames []string
+
+	for _, principal := range role.Principals {
+		if username, ok := strings.CutPrefix(principal.ID, model.UserPrincipal("")); ok && !principal.IsLocked {
+			usernames = append(usernames, username)
+		}
+	}
+
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		group.Members = append(group.Members, &Reference{Value: username, Display: username})
+	}
+
+	return group
+}
+
+func (g *Group) values(attribute string) []string {
+	switch strings.ToLower(attribute) {
+	case "id", "displayname":
+		return []string{g.DisplayName}
+	case "externalid":
+		return []string{g.ExternalID}
+	case "members", "members.value":
+		return referenceValues(g.Members)
+	}
+
+	return nil
+}
+
+func referenceValues(references []*Reference) []string {
+	var result []string
+	for _, reference := range references {
+		result = append(result, reference.Value)
+	}
+
+	return result
+}
+
+func roleIDs(roles []*model.Role) []string {
+	var result = []string{}
+	for _, role := range roles {
+		result = append(result, role.ID)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func isManagedAttribute(key string) bool {
+	for _, attribute := range managedAttributes {
+		if attribute == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isReserved returns whether the role is managed by Authz itself (such as
+// authz-admin), in which case it cannot be provisioned.
+func isReserved(identifier string) bool {
+	return strings.HasPrefix(identifier, configs.ApplicationName+"-")
+}
@@ -0,0 +1,334 @@
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	opAdd     = "add"
+	opRemove  = "remove"
+	opReplace = "replace"
+)
+
+// path is a parsed patch operation path, for instance
+// `emails[type eq "work"].value` or `members[value eq "john"]`.
+type path struct {
+	attribute    string
+	filter       *Filter
+	subAttribute string
+}
+
+func parsePath(value string) (*path, error) {
+	result := &path{attribute: value}
+
+	if start := strings.Index(value, "["); start != -1 {
+		end := strings.LastIndex(value, "]")
+		if end < start {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, value)
+		}
+
+		filter, err := ParseFilter(value[start+1 : end])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidPath, value, err)
+		}
+
+		result.attribute = value[:start]
+		result.filter = filter
+		result.subAttribute = strings.TrimPrefix(value[end+1:], ".")
+	} else if index := strings.Index(value, "."); index != -1 {
+		result.attribute = value[:index]
+		result.subAttribute = value[index+1:]
+	}
+
+	result.attribute = strings.ToLower(result.attribute)
+	result.subAttribute = strings.ToLower(result.subAttribute)
+
+	return result, nil
+}
+
+// operations normalizes patch operations: operations without path are split
+// into one operation per attribute of their value.
+func operations(request *PatchRequest) ([]*PatchOperation, error) {
+	var result []*PatchOperation
+
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+
+		if op != opAdd && op != opRemove && op != opReplace {
+			return nil, fmt.Errorf("%w: unsupported patch operation %q", ErrInvalidValue, operation.Op)
+		}
+
+		if operation.Path != "" {
+			result = append(result, &PatchOperation{Op: op, Path: operation.Path, Value: operation.Value})
+			continue
+		}
+
+		values, ok := operation.Value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: patch operation without path requires an object value", ErrInvalidValue)
+		}
+
+		for attribute, value := range values {
+			result = append(result, &PatchOperation{Op: op, Path: attribute, Value: value})
+		}
+	}
+
+	return result, nil
+}
+
+// applyUserPatch applies patch operations on the given user.
+// Attributes that are not stored by Authz are ignored.
+func applyUserPatch(user *User, request *PatchRequest) error {
+	operations, err := operations(request)
+	if err != nil {
+		return err
+	}
+
+	for _, operation := range operations {
+		path, err := parsePath(operation.Path)
+		if err != nil {
+			return err
+		}
+
+		remove := operation.Op == opRemove
+
+		switch path.attribute {
+		case "active":
+			active := true
+			if !remove {
+				if active, err = boolValue(operation.Value); err != nil {
+					return err
+				}
+			}
+
+			user.Active = &active
+		case "displayname":
+			user.DisplayName = stringValue(operation.Value, remove)
+		case "externalid":
+			user.ExternalID = stringValue(operation.Value, remove)
+		case "username":
+			user.UserName = stringValue(operation.Value, remove)
+		case "name":
+			if err := patchName(user, path.subAttribute, operation.Value, remove); err != nil {
+				return err
+			}
+		case "emails":
+			if err := patchEmails(user, path, operation.Value, remove); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func patchName(user *User, subAttribute string, value any, remove bool) error {
+	if user.Name == nil {
+		user.Name = &Name{}
+	}
+
+	switch subAttribute {
+	case "":
+		if remove {
+			user.Name = nil
+			return nil
+		}
+
+		values, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: name must be an object", ErrInvalidValue)
+		}
+
+		for key, value := range values {
+			if err := patchName(user, strings.ToLower(key), value, false); err != nil {
+				return err
+			}
+		}
+	case "formatted":
+		user.Name.Formatted = stringValue(value, remove)
+	case "givenname":
+		user.Name.GivenName = stringValue(value, remove)
+	case "familyname":
+		user.Name.FamilyName = stringValue(value, remove)
+	}
+
+	return nil
+}
+
+func patchEmails(user *User, path *path, value any, remove bool) error {
+	if path.filter == nil {
+		if remove {
+			user.Emails = nil
+			return nil
+		}
+
+		emails, err := emailsValue(value)
+		if err != nil {
+			return err
+		}
+
+		user.Emails = emails
+
+		return nil
+	}
+
+	var matching *Email
+	for _, email := range user.Emails {
+		if path.filter.Matches(email.values) {
+			matching = email
+			break
+		}
+	}
+
+	// Authz only keeps the primary email, so it is the one to update when
+	// the filter does not match it (for instance on its type).
+	if matching == nil && len(user.Emails) == 1 {
+		matching = user.Emails[0]
+	}
+
+	if remove {
+		var emails []*Email
+		for _, email := range user.Emails {
+			if email != matching {
+				emails = append(emails, email)
+			}
+		}
+
+		user.Emails = emails
+
+		return nil
+	}
+
+	if matching == nil {
+		matching = &Email{}
+
+		// Keep the type of the filter, for instance `emails[type eq "work"].value`.
+		for _, expression := range path.filter.Expressions {
+			if strings.EqualFold(expression.Attribute, "type") && expression.Operator == "eq" {
+				matching.Type = expression.Value
+			}
+		}
+
+		user.Emails = append(user.Emails, matching)
+	}
+
+	if path.subAttribute == "value" {
+		matching.Value = stringValue(value, false)
+	}
+
+	return nil
+}
+
+func (e *Email) values(attribute string) []string {
+	switch strings.ToLower(attribute) {
+	case "value":
+		return []string{e.Value}
+	case "type":
+		return []string{e.Type}
+	case "primary":
+		return []string{strconv.FormatBool(e.Primary)}
+	}
+
+	return nil
+}
+
+func emailsValue(value any) ([]*Email, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: emails must be an array", ErrInvalidValue)
+	}
+
+	var emails []*Email
+
+	for _, item := range values {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: emails must be an array of objects", ErrInvalidValue)
+		}
+
+		email := &Email{
+			Value: stringValue(fields["value"], false),
+			Type:  stringValue(fields["type"], false),
+		}
+
+		if fields["primary"] != nil {
+			primary, err := boolValue(fields["primary"])
+			if err != nil {
+				return nil, err
+			}
+
+			email.Primary = primary
+		}
+
+		emails = append(emails, email)
+	}
+
+	return emails, nil
+}
+
+// memberValues returns the member identifiers of a patch operation value,
+// given either as an array of members or as a single member.
+func memberValues(value any) ([]string, error) {
+	var members []any
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		members = v
+	case map[string]any:
+		members = []any{v}
+	default:
+		return nil, fmt.Errorf("%w: members must be an array of objects", ErrInvalidValue)
+	}
+
+	var result []string
+
+	for _, member := range members {
+		fields, ok := member.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: members must be an array of objects", ErrInvalidValue)
+		}
+
+		identifier := stringValue(fields["value"], false)
+		if identifier == "" {
+			return nil, fmt.Errorf("%w: member value is required", ErrInvalidValue)
+		}
+
+		result = append(result, identifier)
+	}
+
+	return result, nil
+}
+
+func stringValue(value any, remove bool) string {
+	if remove || value == nil {
+		return ""
+	}
+
+	if result, ok := value.(string); ok {
+		return result
+	}
+
+	return fmt.Sprint(value)
+}
+
+// boolValue accepts booleans and their string representation, as some
+// identity providers send "False" instead of false.
+func boolValue(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		result, err := strconv.ParseBool(strings.ToLower(v))
+		if err != nil {
+			return false, fmt.Errorf("%w: %q is not a boolean", ErrInvalidValue, v)
+		}
+
+		return result, nil
+	}
+
+	return false, fmt.Errorf("%w: %v is not a boolean", ErrInvalidValue, value)
+}
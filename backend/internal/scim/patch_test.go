@@ -0,0 +1,92 @@
+package scim
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	// Given
+	active := true
+
+	user := &User{
+		UserName:    "john",
+		DisplayName: "John",
+		Emails:      []*Email{{Value: "john@acme.tld", Primary: true}},
+		Active:      &active,
+	}
+
+	request := &PatchRequest{
+		Operations: []*PatchOperation{
+			{Op: "Replace", Value: map[string]any{"active": "False", "name.givenName": "John"}},
+			{Op: "replace", Path: `emails[type eq "work"].value`, Value: "jd@acme.tld"},
+			{Op: "add", Path: "name.familyName", Value: "Doe"},
+			{Op: "remove", Path: "displayName"},
+			{Op: "replace", Path: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", Value: "sales"},
+		},
+	}
+
+	// When
+	err := applyUserPatch(user, request)
+
+	// Then
+	assert.Nil(t, err)
+
+	assert.False(t, user.IsActive())
+	assert.Equal(t, "", user.DisplayName)
+	assert.Equal(t, &Name{GivenName: "John", FamilyName: "Doe"}, user.Name)
+	assert.Equal(t, "jd@acme.tld", user.PrimaryEmail())
+}
+
+func TestApplyUserPatch_WhenInvalidValue(t *testing.T) {
+	// Given
+	user := &User{UserName: "john"}
+
+	request := &PatchRequest{
+		Operations: []*PatchOperation{
+			{Op: "replace", Path: "active", Value: "maybe"},
+		},
+	}
+
+	// When
+	err := applyUserPatch(user, request)
+
+	// Then
+	assert.True(t, errors.Is(err, ErrInvalidValue))
+	assert.EqualError(t, err, `invalid value: "maybe" is not a boolean`)
+}
+
+func TestPatchMembers(t *testing.T) {
+	// Given
+	members := []string{"jane", "john"}
+
+	testCases := []struct {
+		name     string
+		op       string
+		path     string
+		values   []string
+		expected []string
+	}{
+		{name: "add", op: opAdd, path: "members", values: []string{"bob"}, expected: []string{"jane", "john", "bob"}},
+		{name: "replace", op: opReplace, path: "members", values: []string{"bob"}, expected: []string{"bob"}},
+		{name: "remove by filter", op: opRemove, path: `members[value eq "john"]`, expected: []string{"jane"}},
+		{name: "remove by value", op: opRemove, path: "members", values: []string{"jane"}, expected: []string{"john"}},
+		{name: "remove all", op: opRemove, path: "members", expected: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path, err := parsePath(testCase.path)
+			assert.Nil(t, err)
+
+			// When
+			result, err := patchMembers(append([]string{}, members...), testCase.op, path, testCase.values)
+
+			// Then
+			assert.Nil(t, err)
+			assert.Equal(t, testCase.expected, result)
+		})
+	}
+}
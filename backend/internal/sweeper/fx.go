@@ -0,0 +1,16 @@
+package sweeper
+
+import (
+	"go.uber.org/fx"
+)
+
+func FxModule() fx.Option {
+	return fx.Module("sweeper",
+		fx.Provide(
+			NewSweeper,
+		),
+		fx.Invoke(
+			RunSweeper,
+		),
+	)
+}
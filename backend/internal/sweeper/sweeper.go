@@ -0,0 +1,157 @@
+package sweeper
+
+import (
+	"context"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"go.uber.org/fx"
+	"golang.org/x/exp/slog"
+)
+
+type sweeper struct {
+	logger            *slog.Logger
+	clock             time.Clock
+	policyManager     manager.Policy
+	delegationManager manager.Delegation
+	dispatcher        event.Dispatcher
+	sweepDelay        lib_time.Duration
+	lastSweep         lib_time.Time
+}
+
+func NewSweeper(
+	cfg *configs.App,
+	logger *slog.Logger,
+	clock time.Clock,
+	policyManager manager.Policy,
+	delegationManager manager.Delegation,
+	dispatcher event.Dispatcher,
+) *sweeper {
+	return &sweeper{
+		logger:            logger,
+		clock:             clock,
+		policyManager:     policyManager,
+		delegationManager: delegationManager,
+		dispatcher:        dispatcher,
+		sweepDelay:        cfg.PolicySweepDelay,
+		lastSweep:         clock.Now(),
+	}
+}
+
+// sweep dispatches a policy event for each policy whose time window
+// opened or closed since the last sweep, and a delegation event for each
+// delegation that expired since the last sweep.
+func (s *sweeper) sweep() error {
+	now := s.clock.Now()
+
+	policies, _, err := s.policyManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"time_constraints": {Raw: "not_before IS NOT NULL OR not_after IS NOT NULL OR schedule <> ''"},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.dispatchTransitions(policies, s.lastSweep, now)
+
+	delegations, _, err := s.delegationManager.GetRepository().Find(
+		repository.WithFilter(map[string]repository.FieldValue{
+			"authz_delegations.expires_at": {Operator: ">", Value: s.lastSweep},
+			"expires_at":                   {Operator: "<=", Value: now},
+		}),
+		repository.WithSkipPagination(),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.dispatchExpirations(delegations)
+
+	s.lastSweep = now
+
+	return nil
+}
+
+// dispatchTransitions dispatches a policy event for each time a policy became
+// active or inactive between "from" and "to", so a time window opening and
+// closing between two sweeps is not missed.
+func (s *sweeper) dispatchTransitions(policies []*model.Policy, from lib_time.Time, to lib_time.Time) {
+	for _, policy := range policies {
+		transitions, err := policy.Transitions(from, to)
+		if err != nil {
+			s.logger.Warn("Sweeper: unable to evaluate policy time constraints", err, slog.String("policy_id", policy.ID))
+			continue
+		}
+
+		for _, transition := range transitions {
+			action := event.ItemActionWindowClose
+			if transition.IsActive {
+				action = event.ItemActionWindowOpen
+			}
+
+			if err := s.dispatcher.Dispatch(event.EventTypePolicy, &event.ItemEvent{
+				Action: action,
+				Data:   policy,
+			}); err != nil {
+				s.logger.Error("Sweeper: unable to dispatch policy event", err, slog.String("policy_id", policy.ID))
+			}
+		}
+	}
+}
+
+// dispatchExpirations dispatches a delegation event for each expired delegation,
+// so decisions it allowed are not kept in cache.
+func (s *sweeper) dispatchExpirations(delegations []*model.Delegation) {
+	for _, delegation := range delegations {
+		if err := s.dispatcher.Dispatch(event.EventTypeDelegation, &event.ItemEvent{
+			Action: event.ItemActionExpire,
+			Data:   delegation,
+		}); err != nil {
+			s.logger.Error("Sweeper: unable to dispatch delegation event", err, slog.String("delegation_id", delegation.ID))
+		}
+	}
+}
+
+func RunSweeper(lc fx.Lifecycle, sweeper *sweeper) {
+	var (
+		ticker = lib_time.NewTicker(sweeper.sweepDelay)
+		done   = make(chan struct{})
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+					}
+
+					if err := sweeper.sweep(); err != nil {
+						sweeper.logger.Error("Sweeper: unable to sweep policy time windows", err)
+					}
+				}
+			}()
+
+			sweeper.logger.Info("Sweeper: policy time windows sweeper started")
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			ticker.Stop()
+			close(done)
+
+			sweeper.logger.Info("Sweeper: policy time windows sweeper stopped")
+
+			return nil
+		},
+	})
+}
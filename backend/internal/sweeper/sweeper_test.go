@@ -0,0 +1,230 @@
+package sweeper
+
+import (
+	"path/filepath"
+	"testing"
+	lib_time "time"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/entity/manager"
+	"github.com/eko/authz/backend/internal/entity/model"
+	"github.com/eko/authz/backend/internal/entity/repository"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/helper/time"
+	"github.com/eko/authz/backend/internal/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/slog"
+)
+
+func TestNewSweeper(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		PolicySweepDelay: 1 * lib_time.Minute,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	now := lib_time.Date(2023, 1, 16, 8, 0, 0, 0, lib_time.UTC)
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(now)
+
+	policyManager := manager.NewMockPolicy(ctrl)
+	delegationManager := manager.NewMockDelegation(ctrl)
+	dispatcher := event.NewMockDispatcher(ctrl)
+
+	// When
+	sweeperInstance := NewSweeper(cfg, logger, clock, policyManager, delegationManager, dispatcher)
+
+	// Then
+	assert := assert.New(t)
+
+	assert.IsType(new(sweeper), sweeperInstance)
+
+	assert.Equal(logger, sweeperInstance.logger)
+	assert.Equal(clock, sweeperInstance.clock)
+	assert.Equal(policyManager, sweeperInstance.policyManager)
+	assert.Equal(delegationManager, sweeperInstance.delegationManager)
+	assert.Equal(dispatcher, sweeperInstance.dispatcher)
+	assert.Equal(cfg.PolicySweepDelay, sweeperInstance.sweepDelay)
+	assert.Equal(now, sweeperInstance.lastSweep)
+}
+
+func TestDispatchTransitions(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		PolicySweepDelay: 1 * lib_time.Minute,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	from := lib_time.Date(2023, 1, 16, 8, 59, 0, 0, lib_time.UTC)
+	to := lib_time.Date(2023, 1, 16, 9, 0, 0, 0, lib_time.UTC)
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(from)
+
+	opening := &model.Policy{ID: "opening", Schedule: "0 9 * * *", ScheduleDuration: "8h"}
+	closing := &model.Policy{ID: "closing", NotAfter: &to}
+	unchanged := &model.Policy{ID: "unchanged", NotBefore: &from}
+
+	policyManager := manager.NewMockPolicy(ctrl)
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+	dispatcher.EXPECT().Dispatch(event.EventTypePolicy, &event.ItemEvent{
+		Action: event.ItemActionWindowOpen,
+		Data:   opening,
+	}).Return(nil)
+	dispatcher.EXPECT().Dispatch(event.EventTypePolicy, &event.ItemEvent{
+		Action: event.ItemActionWindowClose,
+		Data:   closing,
+	}).Return(nil)
+
+	sweeperInstance := NewSweeper(cfg, logger, clock, policyManager, manager.NewMockDelegation(ctrl), dispatcher)
+
+	// When - Then
+	sweeperInstance.dispatchTransitions([]*model.Policy{opening, closing, unchanged}, from, to)
+}
+
+func TestDispatchTransitions_WhenWindowIsShorterThanSweepDelay(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		PolicySweepDelay: 1 * lib_time.Minute,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	from := lib_time.Date(2023, 1, 16, 8, 59, 30, 0, lib_time.UTC)
+	to := lib_time.Date(2023, 1, 16, 9, 0, 30, 0, lib_time.UTC)
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(from)
+
+	// Inactive both at "from" and "to", opening and closing in between.
+	short := &model.Policy{ID: "short", Schedule: "0 9 * * *", ScheduleDuration: "10s"}
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+	gomock.InOrder(
+		dispatcher.EXPECT().Dispatch(event.EventTypePolicy, &event.ItemEvent{
+			Action: event.ItemActionWindowOpen,
+			Data:   short,
+		}).Return(nil),
+		dispatcher.EXPECT().Dispatch(event.EventTypePolicy, &event.ItemEvent{
+			Action: event.ItemActionWindowClose,
+			Data:   short,
+		}).Return(nil),
+	)
+
+	sweeperInstance := NewSweeper(cfg, logger, clock, manager.NewMockPolicy(ctrl), manager.NewMockDelegation(ctrl), dispatcher)
+
+	// When - Then
+	sweeperInstance.dispatchTransitions([]*model.Policy{short}, from, to)
+}
+
+func TestDispatchExpirations(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		PolicySweepDelay: 1 * lib_time.Minute,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	now := lib_time.Date(2023, 1, 16, 9, 0, 0, 0, lib_time.UTC)
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(now)
+
+	expired := &model.Delegation{ID: "expired", DelegatorID: "alice", DelegateID: "bob", ExpiresAt: now}
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+	dispatcher.EXPECT().Dispatch(event.EventTypeDelegation, &event.ItemEvent{
+		Action: event.ItemActionExpire,
+		Data:   expired,
+	}).Return(nil)
+
+	sweeperInstance := NewSweeper(cfg, logger, clock, manager.NewMockPolicy(ctrl), manager.NewMockDelegation(ctrl), dispatcher)
+
+	// When - Then
+	sweeperInstance.dispatchExpirations([]*model.Delegation{expired})
+}
+
+func TestSweep_WhenDelegationsExpire(t *testing.T) {
+	// Given
+	ctrl := gomock.NewController(t)
+
+	cfg := &configs.App{
+		PolicySweepDelay: 1 * lib_time.Minute,
+	}
+
+	logger := slog.New(log.NewNopHandler())
+
+	db, err := database.New(&configs.Database{
+		Driver: configs.DriverSqlite,
+		Dbname: filepath.Join(t.TempDir(), "authz.db"),
+	}, logger, time.NewClock())
+	if err != nil {
+		t.Fatalf("unable to open database: %v", err)
+	}
+
+	assert := assert.New(t)
+
+	lastSweep := lib_time.Date(2023, 1, 16, 8, 59, 0, 0, lib_time.UTC)
+	now := lib_time.Date(2023, 1, 16, 9, 0, 0, 0, lib_time.UTC)
+
+	assert.Nil(db.Create([]*model.Principal{{ID: "alice"}, {ID: "bob"}}).Error)
+
+	delegationRepository := repository.New[model.Delegation](db)
+
+	for identifier, expiresAt := range map[string]lib_time.Time{
+		"already-expired": lastSweep,
+		"expired":         now,
+		"active":          now.Add(lib_time.Second),
+	} {
+		assert.Nil(delegationRepository.Create(&model.Delegation{
+			ID:          identifier,
+			DelegatorID: "alice",
+			DelegateID:  "bob",
+			ExpiresAt:   expiresAt,
+		}))
+	}
+
+	clock := time.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(lastSweep)
+	clock.EXPECT().Now().Return(now)
+
+	policyManager := manager.NewMockPolicy(ctrl)
+	policyManager.EXPECT().GetRepository().Return(repository.New[model.Policy](db))
+
+	delegationManager := manager.NewMockDelegation(ctrl)
+	delegationManager.EXPECT().GetRepository().Return(delegationRepository)
+
+	dispatcher := event.NewMockDispatcher(ctrl)
+	dispatcher.EXPECT().Dispatch(event.EventTypeDelegation, gomock.Any()).
+		DoAndReturn(func(_ event.EventType, data any) error {
+			itemEvent := data.(*event.ItemEvent)
+
+			assert.Equal(event.ItemActionExpire, itemEvent.Action)
+			assert.Equal("expired", itemEvent.Data.(*model.Delegation).ID)
+
+			return nil
+		})
+
+	sweeperInstance := NewSweeper(cfg, logger, clock, policyManager, delegationManager, dispatcher)
+
+	// When
+	err = sweeperInstance.sweep()
+
+	// Then
+	assert.Nil(err)
+	assert.Equal(now, sweeperInstance.lastSweep)
+}
@@ -399,6 +399,40 @@ func (m *Check) validate(all bool) error {
 
 	// no validation rules for Action
 
+	for idx, item := range m.GetContext() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, CheckValidationError{
+						field:  fmt.Sprintf("Context[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, CheckValidationError{
+						field:  fmt.Sprintf("Context[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CheckValidationError{
+					field:  fmt.Sprintf("Context[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return CheckMultiError(errors)
 	}
@@ -645,6 +679,8 @@ func (m *CheckRequest) validate(all bool) error {
 
 	}
 
+	// no validation rules for ConsistencyToken
+
 	if len(errors) > 0 {
 		return CheckRequestMultiError(errors)
 	}
@@ -856,6 +892,1634 @@ var _ interface {
 	ErrorName() string
 } = CheckResponseValidationError{}
 
+// Validate checks the field values on CheckStreamRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *CheckStreamRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CheckStreamRequest with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// CheckStreamRequestMultiError, or nil if none found.
+func (m *CheckStreamRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CheckStreamRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for RequestId
+
+	for idx, item := range m.GetChecks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, CheckStreamRequestValidationError{
+						field:  fmt.Sprintf("Checks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, CheckStreamRequestValidationError{
+						field:  fmt.Sprintf("Checks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CheckStreamRequestValidationError{
+					field:  fmt.Sprintf("Checks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for ConsistencyToken
+
+	if len(errors) > 0 {
+		return CheckStreamRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CheckStreamRequestMultiError is an error wrapping multiple validation errors
+// returned by CheckStreamRequest.ValidateAll() if the designated constraints
+// aren't met.
+type CheckStreamRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CheckStreamRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CheckStreamRequestMultiError) AllErrors() []error { return m }
+
+// CheckStreamRequestValidationError is the validation error returned by
+// CheckStreamRequest.Validate if the designated constraints aren't met.
+type CheckStreamRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CheckStreamRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CheckStreamRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CheckStreamRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CheckStreamRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CheckStreamRequestValidationError) ErrorName() string {
+	return "CheckStreamRequestValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CheckStreamRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCheckStreamRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CheckStreamRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CheckStreamRequestValidationError{}
+
+// Validate checks the field values on CheckStreamError with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *CheckStreamError) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CheckStreamError with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// CheckStreamErrorMultiError, or nil if none found.
+func (m *CheckStreamError) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CheckStreamError) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Code
+
+	// no validation rules for Message
+
+	if len(errors) > 0 {
+		return CheckStreamErrorMultiError(errors)
+	}
+
+	return nil
+}
+
+// CheckStreamErrorMultiError is an error wrapping multiple validation errors
+// returned by CheckStreamError.ValidateAll() if the designated constraints
+// aren't met.
+type CheckStreamErrorMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CheckStreamErrorMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CheckStreamErrorMultiError) AllErrors() []error { return m }
+
+// CheckStreamErrorValidationError is the validation error returned by
+// CheckStreamError.Validate if the designated constraints aren't met.
+type CheckStreamErrorValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CheckStreamErrorValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CheckStreamErrorValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CheckStreamErrorValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CheckStreamErrorValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CheckStreamErrorValidationError) ErrorName() string { return "CheckStreamErrorValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CheckStreamErrorValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCheckStreamError.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CheckStreamErrorValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CheckStreamErrorValidationError{}
+
+// Validate checks the field values on CheckStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the first error encountered is returned, or nil if there are no violations.
+func (m *CheckStreamResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CheckStreamResponse with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// CheckStreamResponseMultiError, or nil if none found.
+func (m *CheckStreamResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CheckStreamResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for RequestId
+
+	for idx, item := range m.GetChecks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, CheckStreamResponseValidationError{
+						field:  fmt.Sprintf("Checks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, CheckStreamResponseValidationError{
+						field:  fmt.Sprintf("Checks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return CheckStreamResponseValidationError{
+					field:  fmt.Sprintf("Checks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetError()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, CheckStreamResponseValidationError{
+					field:  "Error",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, CheckStreamResponseValidationError{
+					field:  "Error",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetError()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return CheckStreamResponseValidationError{
+				field:  "Error",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return CheckStreamResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CheckStreamResponseMultiError is an error wrapping multiple validation errors
+// returned by CheckStreamResponse.ValidateAll() if the designated constraints
+// aren't met.
+type CheckStreamResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CheckStreamResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CheckStreamResponseMultiError) AllErrors() []error { return m }
+
+// CheckStreamResponseValidationError is the validation error returned by
+// CheckStreamResponse.Validate if the designated constraints aren't met.
+type CheckStreamResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CheckStreamResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CheckStreamResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CheckStreamResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CheckStreamResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CheckStreamResponseValidationError) ErrorName() string {
+	return "CheckStreamResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e CheckStreamResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCheckStreamResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CheckStreamResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CheckStreamResponseValidationError{}
+
+// Validate checks the field values on AgentSyncRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AgentSyncRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentSyncRequest with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// AgentSyncRequestMultiError, or nil if none found.
+func (m *AgentSyncRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentSyncRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(errors) > 0 {
+		return AgentSyncRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentSyncRequestMultiError is an error wrapping multiple validation errors
+// returned by AgentSyncRequest.ValidateAll() if the designated constraints
+// aren't met.
+type AgentSyncRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentSyncRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentSyncRequestMultiError) AllErrors() []error { return m }
+
+// AgentSyncRequestValidationError is the validation error returned by
+// AgentSyncRequest.Validate if the designated constraints aren't met.
+type AgentSyncRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentSyncRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentSyncRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentSyncRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentSyncRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentSyncRequestValidationError) ErrorName() string { return "AgentSyncRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AgentSyncRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentSyncRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentSyncRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentSyncRequestValidationError{}
+
+// Validate checks the field values on AgentSyncResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AgentSyncResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentSyncResponse with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// AgentSyncResponseMultiError, or nil if none found.
+func (m *AgentSyncResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentSyncResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Snapshot
+
+	if all {
+		switch v := interface{}(m.GetUpserted()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AgentSyncResponseValidationError{
+					field:  "Upserted",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AgentSyncResponseValidationError{
+					field:  "Upserted",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUpserted()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AgentSyncResponseValidationError{
+				field:  "Upserted",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetDeleted()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, AgentSyncResponseValidationError{
+					field:  "Deleted",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, AgentSyncResponseValidationError{
+					field:  "Deleted",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetDeleted()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return AgentSyncResponseValidationError{
+				field:  "Deleted",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for ConsistencyToken
+
+	if len(errors) > 0 {
+		return AgentSyncResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentSyncResponseMultiError is an error wrapping multiple validation errors
+// returned by AgentSyncResponse.ValidateAll() if the designated constraints
+// aren't met.
+type AgentSyncResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentSyncResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentSyncResponseMultiError) AllErrors() []error { return m }
+
+// AgentSyncResponseValidationError is the validation error returned by
+// AgentSyncResponse.Validate if the designated constraints aren't met.
+type AgentSyncResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentSyncResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentSyncResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentSyncResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentSyncResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentSyncResponseValidationError) ErrorName() string {
+	return "AgentSyncResponseValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AgentSyncResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentSyncResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentSyncResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentSyncResponseValidationError{}
+
+// Validate checks the field values on AgentData with the rules defined in the
+// proto definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
+func (m *AgentData) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentData with the rules defined in
+// the proto definition for this message. If any rules are violated, the result
+// is a list of violation errors wrapped in AgentDataMultiError, or nil if none
+// found.
+func (m *AgentData) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentData) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	for idx, item := range m.GetCompiledPolicies() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("CompiledPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("CompiledPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("CompiledPolicies[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetPolicies() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Policies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Policies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("Policies[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetRoles() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Roles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Roles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("Roles[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetPrincipals() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Principals[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Principals[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("Principals[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetResources() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Resources[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Resources[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("Resources[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetResourceKinds() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("ResourceKinds[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("ResourceKinds[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("ResourceKinds[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetCedarPolicies() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("CedarPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("CedarPolicies[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("CedarPolicies[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	for idx, item := range m.GetDelegations() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Delegations[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDataValidationError{
+						field:  fmt.Sprintf("Delegations[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDataValidationError{
+					field:  fmt.Sprintf("Delegations[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return AgentDataMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentDataMultiError is an error wrapping multiple validation errors returned
+// by AgentData.ValidateAll() if the designated constraints aren't met.
+type AgentDataMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentDataMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentDataMultiError) AllErrors() []error { return m }
+
+// AgentDataValidationError is the validation error returned by
+// AgentData.Validate if the designated constraints aren't met.
+type AgentDataValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentDataValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentDataValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentDataValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentDataValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentDataValidationError) ErrorName() string { return "AgentDataValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AgentDataValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentData.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentDataValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentDataValidationError{}
+
+// Validate checks the field values on AgentCompiledPolicy with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the first error encountered is returned, or nil if there are no violations.
+func (m *AgentCompiledPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentCompiledPolicy with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// AgentCompiledPolicyMultiError, or nil if none found.
+func (m *AgentCompiledPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentCompiledPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for PolicyId
+
+	// no validation rules for PrincipalId
+
+	// no validation rules for ResourceKind
+
+	// no validation rules for ResourceValue
+
+	// no validation rules for ActionId
+
+	if len(errors) > 0 {
+		return AgentCompiledPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentCompiledPolicyMultiError is an error wrapping multiple validation errors
+// returned by AgentCompiledPolicy.ValidateAll() if the designated constraints
+// aren't met.
+type AgentCompiledPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentCompiledPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentCompiledPolicyMultiError) AllErrors() []error { return m }
+
+// AgentCompiledPolicyValidationError is the validation error returned by
+// AgentCompiledPolicy.Validate if the designated constraints aren't met.
+type AgentCompiledPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentCompiledPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentCompiledPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentCompiledPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentCompiledPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentCompiledPolicyValidationError) ErrorName() string {
+	return "AgentCompiledPolicyValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AgentCompiledPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentCompiledPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentCompiledPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentCompiledPolicyValidationError{}
+
+// Validate checks the field values on AgentPolicy with the rules defined in the
+// proto definition for this message. If any rules are violated, the first error
+// encountered is returned, or nil if there are no violations.
+func (m *AgentPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentPolicy with the rules defined in
+// the proto definition for this message. If any rules are violated, the result
+// is a list of violation errors wrapped in AgentPolicyMultiError, or nil if
+// none found.
+func (m *AgentPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Effect
+
+	// no validation rules for Priority
+
+	// no validation rules for NotBefore
+
+	// no validation rules for NotAfter
+
+	// no validation rules for Schedule
+
+	// no validation rules for ScheduleDuration
+
+	// no validation rules for ScheduleTimezone
+
+	if len(errors) > 0 {
+		return AgentPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentPolicyMultiError is an error wrapping multiple validation errors
+// returned by AgentPolicy.ValidateAll() if the designated constraints aren't
+// met.
+type AgentPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentPolicyMultiError) AllErrors() []error { return m }
+
+// AgentPolicyValidationError is the validation error returned by
+// AgentPolicy.Validate if the designated constraints aren't met.
+type AgentPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentPolicyValidationError) ErrorName() string { return "AgentPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AgentPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentPolicyValidationError{}
+
+// Validate checks the field values on AgentResourceKind with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AgentResourceKind) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentResourceKind with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// AgentResourceKindMultiError, or nil if none found.
+func (m *AgentResourceKind) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentResourceKind) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	if len(errors) > 0 {
+		return AgentResourceKindMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentResourceKindMultiError is an error wrapping multiple validation errors
+// returned by AgentResourceKind.ValidateAll() if the designated constraints
+// aren't met.
+type AgentResourceKindMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentResourceKindMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentResourceKindMultiError) AllErrors() []error { return m }
+
+// AgentResourceKindValidationError is the validation error returned by
+// AgentResourceKind.Validate if the designated constraints aren't met.
+type AgentResourceKindValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentResourceKindValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentResourceKindValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentResourceKindValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentResourceKindValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentResourceKindValidationError) ErrorName() string {
+	return "AgentResourceKindValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e AgentResourceKindValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentResourceKind.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentResourceKindValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentResourceKindValidationError{}
+
+// Validate checks the field values on AgentCedarPolicy with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AgentCedarPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentCedarPolicy with the rules
+// defined in the proto definition for this message. If any rules are violated,
+// the result is a list of violation errors wrapped in
+// AgentCedarPolicyMultiError, or nil if none found.
+func (m *AgentCedarPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentCedarPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for Source
+
+	// no validation rules for Effect
+
+	// no validation rules for ResourceKind
+
+	if len(errors) > 0 {
+		return AgentCedarPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentCedarPolicyMultiError is an error wrapping multiple validation errors
+// returned by AgentCedarPolicy.ValidateAll() if the designated constraints
+// aren't met.
+type AgentCedarPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentCedarPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentCedarPolicyMultiError) AllErrors() []error { return m }
+
+// AgentCedarPolicyValidationError is the validation error returned by
+// AgentCedarPolicy.Validate if the designated constraints aren't met.
+type AgentCedarPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentCedarPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentCedarPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentCedarPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentCedarPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentCedarPolicyValidationError) ErrorName() string { return "AgentCedarPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AgentCedarPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentCedarPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentCedarPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentCedarPolicyValidationError{}
+
+// Validate checks the field values on AgentDelegation with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *AgentDelegation) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AgentDelegation with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in AgentDelegationMultiError, or
+// nil if none found.
+func (m *AgentDelegation) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AgentDelegation) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Id
+
+	// no validation rules for DelegatorId
+
+	// no validation rules for DelegateId
+
+	for idx, item := range m.GetResources() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, AgentDelegationValidationError{
+						field:  fmt.Sprintf("Resources[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, AgentDelegationValidationError{
+						field:  fmt.Sprintf("Resources[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return AgentDelegationValidationError{
+					field:  fmt.Sprintf("Resources[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	// no validation rules for ExpiresAt
+
+	if len(errors) > 0 {
+		return AgentDelegationMultiError(errors)
+	}
+
+	return nil
+}
+
+// AgentDelegationMultiError is an error wrapping multiple validation errors
+// returned by AgentDelegation.ValidateAll() if the designated constraints
+// aren't met.
+type AgentDelegationMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m AgentDelegationMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m AgentDelegationMultiError) AllErrors() []error { return m }
+
+// AgentDelegationValidationError is the validation error returned by
+// AgentDelegation.Validate if the designated constraints aren't met.
+type AgentDelegationValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e AgentDelegationValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e AgentDelegationValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e AgentDelegationValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e AgentDelegationValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e AgentDelegationValidationError) ErrorName() string { return "AgentDelegationValidationError" }
+
+// Error satisfies the builtin error interface
+func (e AgentDelegationValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sAgentDelegation.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = AgentDelegationValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = AgentDelegationValidationError{}
+
 // Validate checks the field values on Policy with the rules defined in the
 // proto definition for this message. If any rules are violated, the first
 // error encountered is returned, or nil if there are no violations.
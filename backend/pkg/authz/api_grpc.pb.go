@@ -24,6 +24,8 @@ const _ = grpc.SupportPackageIsVersion7
 type ApiClient interface {
 	Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
 	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResponse, error)
+	CheckStream(ctx context.Context, opts ...grpc.CallOption) (Api_CheckStreamClient, error)
+	AgentSync(ctx context.Context, in *AgentSyncRequest, opts ...grpc.CallOption) (Api_AgentSyncClient, error)
 	PolicyCreate(ctx context.Context, in *PolicyCreateRequest, opts ...grpc.CallOption) (*PolicyCreateResponse, error)
 	PolicyGet(ctx context.Context, in *PolicyGetRequest, opts ...grpc.CallOption) (*PolicyGetResponse, error)
 	PolicyDelete(ctx context.Context, in *PolicyDeleteRequest, opts ...grpc.CallOption) (*PolicyDeleteResponse, error)
@@ -68,6 +70,69 @@ func (c *apiClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *apiClient) CheckStream(ctx context.Context, opts ...grpc.CallOption) (Api_CheckStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Api_ServiceDesc.Streams[0], "/authz.Api/CheckStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiCheckStreamClient{stream}
+	return x, nil
+}
+
+type Api_CheckStreamClient interface {
+	Send(*CheckStreamRequest) error
+	Recv() (*CheckStreamResponse, error)
+	grpc.ClientStream
+}
+
+type apiCheckStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiCheckStreamClient) Send(m *CheckStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *apiCheckStreamClient) Recv() (*CheckStreamResponse, error) {
+	m := new(CheckStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *apiClient) AgentSync(ctx context.Context, in *AgentSyncRequest, opts ...grpc.CallOption) (Api_AgentSyncClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Api_ServiceDesc.Streams[1], "/authz.Api/AgentSync", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiAgentSyncClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Api_AgentSyncClient interface {
+	Recv() (*AgentSyncResponse, error)
+	grpc.ClientStream
+}
+
+type apiAgentSyncClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiAgentSyncClient) Recv() (*AgentSyncResponse, error) {
+	m := new(AgentSyncResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *apiClient) PolicyCreate(ctx context.Context, in *PolicyCreateRequest, opts ...grpc.CallOption) (*PolicyCreateResponse, error) {
 	out := new(PolicyCreateResponse)
 	err := c.cc.Invoke(ctx, "/authz.Api/PolicyCreate", in, out, opts...)
@@ -218,6 +283,8 @@ func (c *apiClient) RoleUpdate(ctx context.Context, in *RoleUpdateRequest, opts
 type ApiServer interface {
 	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
 	Check(context.Context, *CheckRequest) (*CheckResponse, error)
+	CheckStream(Api_CheckStreamServer) error
+	AgentSync(*AgentSyncRequest, Api_AgentSyncServer) error
 	PolicyCreate(context.Context, *PolicyCreateRequest) (*PolicyCreateResponse, error)
 	PolicyGet(context.Context, *PolicyGetRequest) (*PolicyGetResponse, error)
 	PolicyDelete(context.Context, *PolicyDeleteRequest) (*PolicyDeleteResponse, error)
@@ -247,6 +314,12 @@ func (UnimplementedApiServer) Authenticate(context.Context, *AuthenticateRequest
 func (UnimplementedApiServer) Check(context.Context, *CheckRequest) (*CheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
 }
+func (UnimplementedApiServer) CheckStream(Api_CheckStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method CheckStream not implemented")
+}
+func (UnimplementedApiServer) AgentSync(*AgentSyncRequest, Api_AgentSyncServer) error {
+	return status.Errorf(codes.Unimplemented, "method AgentSync not implemented")
+}
 func (UnimplementedApiServer) PolicyCreate(context.Context, *PolicyCreateRequest) (*PolicyCreateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PolicyCreate not implemented")
 }
@@ -344,6 +417,53 @@ func _Api_Check_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_CheckStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ApiServer).CheckStream(&apiCheckStreamServer{stream})
+}
+
+type Api_CheckStreamServer interface {
+	Send(*CheckStreamResponse) error
+	Recv() (*CheckStreamRequest, error)
+	grpc.ServerStream
+}
+
+type apiCheckStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiCheckStreamServer) Send(m *CheckStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *apiCheckStreamServer) Recv() (*CheckStreamRequest, error) {
+	m := new(CheckStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Api_AgentSync_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AgentSyncRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServer).AgentSync(m, &apiAgentSyncServer{stream})
+}
+
+type Api_AgentSyncServer interface {
+	Send(*AgentSyncResponse) error
+	grpc.ServerStream
+}
+
+type apiAgentSyncServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiAgentSyncServer) Send(m *AgentSyncResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Api_PolicyCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PolicyCreateRequest)
 	if err := dec(in); err != nil {
@@ -712,6 +832,18 @@ var Api_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Api_RoleUpdate_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CheckStream",
+			Handler:       _Api_CheckStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AgentSync",
+			Handler:       _Api_AgentSync_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api.proto",
 }
@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/internal/changelog"
+	"github.com/eko/authz/backend/internal/compile"
+	"github.com/eko/authz/backend/internal/database"
+	"github.com/eko/authz/backend/internal/decision"
+	"github.com/eko/authz/backend/internal/entity"
+	"github.com/eko/authz/backend/internal/event"
+	"github.com/eko/authz/backend/internal/grpc/handler"
+	"github.com/eko/authz/backend/internal/helper"
+	internal_log "github.com/eko/authz/backend/internal/log"
+	"github.com/eko/authz/backend/internal/observability/metric"
+	"github.com/eko/authz/backend/internal/sweeper"
+	"github.com/eko/authz/backend/pkg/authz"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// Config represents the engine configuration values.
+type Config struct {
+	// DB is the database the engine works on. Its schema has to be created
+	// from schema files, except for sqlite databases which are migrated.
+	// An in-memory database is used when not set.
+	DB *gorm.DB
+
+	// Settings are the settings otherwise loaded from environment variables
+	// by the server (its database settings are ignored). Default settings are
+	// used when not set.
+	Settings *configs.Base
+}
+
+// Engine runs the decision engine in-process, without HTTP and gRPC servers.
+// Its methods behave like the gRPC API ones, without authentication, and
+// changes are compiled in background as on the server.
+type Engine interface {
+	Check(ctx context.Context, req *authz.CheckRequest) (*authz.CheckResponse, error)
+	IsAllowed(ctx context.Context, check *authz.Check) (bool, error)
+
+	PolicyCreate(ctx context.Context, req *authz.PolicyCreateRequest) (*authz.PolicyCreateResponse, error)
+	PolicyDelete(ctx context.Context, req *authz.PolicyDeleteRequest) (*authz.PolicyDeleteResponse, error)
+	PolicyGet(ctx context.Context, req *authz.PolicyGetRequest) (*authz.PolicyGetResponse, error)
+	PolicyUpdate(ctx context.Context, req *authz.PolicyUpdateRequest) (*authz.PolicyUpdateResponse, error)
+
+	PrincipalCreate(ctx context.Context, req *authz.PrincipalCreateRequest) (*authz.PrincipalCreateResponse, error)
+	PrincipalDelete(ctx context.Context, req *authz.PrincipalDeleteRequest) (*authz.PrincipalDeleteResponse, error)
+	PrincipalGet(ctx context.Context, req *authz.PrincipalGetRequest) (*authz.PrincipalGetResponse, error)
+	PrincipalUpdate(ctx context.Context, req *authz.PrincipalUpdateRequest) (*authz.PrincipalUpdateResponse, error)
+
+	ResourceCreate(ctx context.Context, req *authz.ResourceCreateRequest) (*authz.ResourceCreateResponse, error)
+	ResourceDelete(ctx context.Context, req *authz.ResourceDeleteRequest) (*authz.ResourceDeleteResponse, error)
+	ResourceGet(ctx context.Context, req *authz.ResourceGetRequest) (*authz.ResourceGetResponse, error)
+	ResourceUpdate(ctx context.Context, req *authz.ResourceUpdateRequest) (*authz.ResourceUpdateResponse, error)
+
+	RoleCreate(ctx context.Context, req *authz.RoleCreateRequest) (*authz.RoleCreateResponse, error)
+	RoleDelete(ctx context.Context, req *authz.RoleDeleteRequest) (*authz.RoleDeleteResponse, error)
+	RoleGet(ctx context.Context, req *authz.RoleGetRequest) (*authz.RoleGetResponse, error)
+	RoleUpdate(ctx context.Context, req *authz.RoleUpdateRequest) (*authz.RoleUpdateResponse, error)
+
+	// Wait blocks until every change made so far is compiled. It fails when a
+	// change made since the last successful call failed to compile.
+	Wait(ctx context.Context) error
+
+	// Close stops compiling changes and closes the in-memory database, if any.
+	Close(ctx context.Context) error
+}
+
+type engine struct {
+	handler.Policy
+	handler.Principal
+	handler.Resource
+	handler.Role
+
+	app          *fx.App
+	checkHandler handler.Check
+	consistency  compile.Consistency
+	db           *gorm.DB
+	inMemory     bool
+	waitedUntil  atomic.Int64
+}
+
+// inMemoryCount names in-memory databases so that engines never share one.
+var inMemoryCount atomic.Int64
+
+func New(cfg *Config) (Engine, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	settings := configs.New()
+	if cfg.Settings != nil {
+		copied := *cfg.Settings
+		settings = &copied
+	}
+
+	databaseModule := database.ExistingFxModule(cfg.DB)
+
+	if cfg.DB == nil {
+		settings.Database = &configs.Database{
+			Driver:   configs.DriverSqlite,
+			Dbname:   fmt.Sprintf("authz-engine-%d", inMemoryCount.Add(1)),
+			InMemory: true,
+		}
+
+		databaseModule = database.FxModule()
+	}
+
+	e := &engine{inMemory: cfg.DB == nil}
+
+	e.app = fx.New(
+		fx.NopLogger,
+		fx.Provide(context.Background),
+
+		changelog.FxModule(),
+		compile.FxModule(),
+		configs.StaticFxModule(settings),
+		databaseModule,
+		decision.FxModule(),
+		entity.FxModule(),
+		event.FxModule(),
+		helper.FxModule(),
+		internal_log.FxModule(),
+		sweeper.FxModule(),
+
+		fx.Provide(
+			// Metrics are registered globally, so they are left to the host
+			// application.
+			func() metric.Observer { return nil },
+
+			handler.NewCheck,
+			handler.NewPolicy,
+			handler.NewPrincipal,
+			handler.NewResource,
+			handler.NewRole,
+		),
+		fx.Populate(
+			&e.checkHandler,
+			&e.Policy,
+			&e.Principal,
+			&e.Resource,
+			&e.Role,
+			&e.consistency,
+			&e.db,
+		),
+	)
+
+	if err := e.app.Start(context.Background()); err != nil {
+		return nil, fmt.Errorf("unable to start engine: %v", err)
+	}
+
+	return e, nil
+}
+
+func (e *engine) Check(ctx context.Context, req *authz.CheckRequest) (*authz.CheckResponse, error) {
+	return e.checkHandler.Check(ctx, req)
+}
+
+func (e *engine) IsAllowed(ctx context.Context, check *authz.Check) (bool, error) {
+	if check == nil {
+		return false, nil
+	}
+
+	response, err := e.Check(ctx, &authz.CheckRequest{
+		Checks: []*authz.Check{check},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return response.GetChecks()[0].IsAllowed, nil
+}
+
+func (e *engine) Wait(ctx context.Context) error {
+	revision, err := e.consistency.Revision()
+	if err != nil {
+		return err
+	}
+
+	token := compile.FormatConsistencyToken(e.waitedUntil.Load(), revision)
+
+	if err := e.consistency.Wait(ctx, token); err != nil {
+		return err
+	}
+
+	e.waitedUntil.Store(revision)
+
+	return nil
+}
+
+func (e *engine) Close(ctx context.Context) error {
+	if err := e.app.Stop(ctx); err != nil {
+		return fmt.Errorf("unable to stop engine: %v", err)
+	}
+
+	if !e.inMemory {
+		return nil
+	}
+
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve in-memory database: %v", err)
+	}
+
+	return sqlDB.Close()
+}
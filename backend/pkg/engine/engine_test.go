@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/eko/authz/backend/configs"
+	"github.com/eko/authz/backend/pkg/authz"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newEngine(t *testing.T, cfg *Config) Engine {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	if cfg.Settings == nil {
+		cfg.Settings = configs.New()
+		cfg.Settings.Logger.Level = "ERROR"
+	}
+
+	e, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create engine: %v", err)
+	}
+
+	t.Cleanup(func() { _ = e.Close(context.Background()) })
+
+	return e
+}
+
+func givenPostReaders(t *testing.T, e Engine) {
+	ctx := context.Background()
+
+	_, err := e.ResourceCreate(ctx, &authz.ResourceCreateRequest{Id: "post.123", Kind: "post", Value: "123"})
+	assert.Nil(t, err)
+
+	_, err = e.PolicyCreate(ctx, &authz.PolicyCreateRequest{
+		Id:        "post-readers",
+		Resources: []string{"post.*"},
+		Actions:   []string{"read"},
+	})
+	assert.Nil(t, err)
+
+	_, err = e.RoleCreate(ctx, &authz.RoleCreateRequest{Id: "readers", Policies: []string{"post-readers"}})
+	assert.Nil(t, err)
+
+	_, err = e.PrincipalCreate(ctx, &authz.PrincipalCreateRequest{Id: "alice", Roles: []string{"readers"}})
+	assert.Nil(t, err)
+
+	_, err = e.PrincipalCreate(ctx, &authz.PrincipalCreateRequest{Id: "bob"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, e.Wait(ctx))
+}
+
+func TestEngine_IsAllowed(t *testing.T) {
+	// Given
+	e := newEngine(t, nil)
+	givenPostReaders(t, e)
+
+	ctx := context.Background()
+
+	// When
+	aliceAllowed, aliceErr := e.IsAllowed(ctx, &authz.Check{Principal: "alice", ResourceKind: "post", ResourceValue: "123", Action: "read"})
+	bobAllowed, bobErr := e.IsAllowed(ctx, &authz.Check{Principal: "bob", ResourceKind: "post", ResourceValue: "123", Action: "read"})
+
+	// Then
+	assert := assert.New(t)
+
+	assert.Nil(aliceErr)
+	assert.True(aliceAllowed)
+
+	assert.Nil(bobErr)
+	assert.False(bobAllowed)
+}
+
+func TestEngine_IsAllowed_WhenChanged(t *testing.T) {
+	// Given
+	e := newEngine(t, nil)
+	givenPostReaders(t, e)
+
+	ctx := context.Background()
+
+	_, err := e.PrincipalUpdate(ctx, &authz.PrincipalUpdateRequest{Id: "alice"})
+	assert.Nil(t, err)
+
+	// When
+	err = e.Wait(ctx)
+
+	// Then
+	assert.Nil(t, err)
+
+	allowed, err := e.IsAllowed(ctx, &authz.Check{Principal: "alice", ResourceKind: "post", ResourceValue: "123", Action: "read"})
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+}
+
+func TestEngine_WhenInMemory(t *testing.T) {
+	// Given
+	first := newEngine(t, nil)
+	givenPostReaders(t, first)
+
+	second := newEngine(t, nil)
+
+	// When
+	_, err := second.PrincipalGet(context.Background(), &authz.PrincipalGetRequest{Id: "alice"})
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestEngine_WhenDatabase(t *testing.T) {
+	// Given
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "authz.db")), &gorm.Config{})
+	assert.Nil(t, err)
+
+	givenPostReaders(t, newEngine(t, &Config{DB: db}))
+
+	// When
+	e := newEngine(t, &Config{DB: db})
+
+	// Then
+	response, err := e.Check(context.Background(), &authz.CheckRequest{
+		Checks: []*authz.Check{
+			{Principal: "alice", ResourceKind: "post", ResourceValue: "123", Action: "read"},
+			{Principal: "alice", ResourceKind: "post", ResourceValue: "123", Action: "edit"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, response.GetChecks()[0].IsAllowed)
+	assert.False(t, response.GetChecks()[1].IsAllowed)
+}
@@ -60,11 +60,50 @@ CREATE TABLE `authz_audit` (
   `resource_value` longtext,
   `action` longtext,
   `is_allowed` tinyint(1) DEFAULT NULL,
+  `algorithm` longtext,
   `policy_id` longtext,
+  `cedar_policy_id` longtext,
+  `delegation_id` longtext,
   PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `authz_cedar_policies`
+--
+
+DROP TABLE IF EXISTS `authz_cedar_policies`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_cedar_policies` (
+  `id` varchar(191) NOT NULL,
+  `source` longtext,
+  `effect` longtext,
+  `resource_kind` varchar(191) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_authz_cedar_policies_resource_kind` (`resource_kind`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_changes`
+--
+
+DROP TABLE IF EXISTS `authz_changes`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_changes` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `item_type` longtext,
+  `item_id` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_authz_changes_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `authz_clients`
 --
@@ -84,6 +123,37 @@ CREATE TABLE `authz_clients` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `authz_compile_jobs`
+--
+
+DROP TABLE IF EXISTS `authz_compile_jobs`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_compile_jobs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `target_type` varchar(191) DEFAULT NULL,
+  `target_id` varchar(191) DEFAULT NULL,
+  `status` varchar(191) DEFAULT NULL,
+  `attempts` bigint DEFAULT NULL,
+  `last_error` longtext,
+  `next_run_at` datetime(3) DEFAULT NULL,
+  `pending_since` datetime(3) DEFAULT NULL,
+  `pending_revision` bigint DEFAULT NULL,
+  `revision` bigint DEFAULT NULL,
+  `claim_id` longtext,
+  `claim_expires_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_authz_compile_jobs_target` (`target_type`,`target_id`),
+  KEY `idx_authz_compile_jobs_next_run_at` (`next_run_at`),
+  KEY `idx_authz_compile_jobs_pending_revision` (`pending_revision`),
+  KEY `idx_authz_compile_jobs_pending_since` (`pending_since`),
+  KEY `idx_authz_compile_jobs_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `authz_compiled_policies`
 --
@@ -105,7 +175,79 @@ CREATE TABLE `authz_compiled_policies` (
   KEY `idx_authz_compiled_policies_version` (`version`),
   KEY `idx_authz_compiled_policies_policy_id` (`policy_id`),
   KEY `idx_authz_compiled_policies_principal_id` (`principal_id`),
-  KEY `idx_authz_compiled_policies_resource_kind` (`resource_kind`)
+  KEY `idx_authz_compiled_policies_resource_kind` (`resource_kind`),
+  KEY `idx_authz_compiled_policies_lookup` (`resource_kind`,`action_id`,`resource_value`,`principal_id`,`policy_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_compiled_versions`
+--
+
+DROP TABLE IF EXISTS `authz_compiled_versions`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_compiled_versions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_delegations`
+--
+
+DROP TABLE IF EXISTS `authz_delegations`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_delegations` (
+  `id` varchar(191) NOT NULL,
+  `delegator_id` varchar(191) DEFAULT NULL,
+  `delegate_id` varchar(191) DEFAULT NULL,
+  `expires_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_authz_delegations_delegator_id` (`delegator_id`),
+  KEY `idx_authz_delegations_delegate_id` (`delegate_id`),
+  KEY `idx_authz_delegations_expires_at` (`expires_at`),
+  CONSTRAINT `fk_authz_delegations_delegate` FOREIGN KEY (`delegate_id`) REFERENCES `authz_principals` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT `fk_authz_delegations_delegator` FOREIGN KEY (`delegator_id`) REFERENCES `authz_principals` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_delegations_actions`
+--
+
+DROP TABLE IF EXISTS `authz_delegations_actions`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_delegations_actions` (
+  `delegation_id` varchar(191) NOT NULL,
+  `action_id` varchar(191) NOT NULL,
+  PRIMARY KEY (`delegation_id`,`action_id`),
+  KEY `fk_authz_delegations_actions_action` (`action_id`),
+  CONSTRAINT `fk_authz_delegations_actions_action` FOREIGN KEY (`action_id`) REFERENCES `authz_actions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT `fk_authz_delegations_actions_delegation` FOREIGN KEY (`delegation_id`) REFERENCES `authz_delegations` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_delegations_resources`
+--
+
+DROP TABLE IF EXISTS `authz_delegations_resources`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_delegations_resources` (
+  `delegation_id` varchar(191) NOT NULL,
+  `resource_id` varchar(191) NOT NULL,
+  PRIMARY KEY (`delegation_id`,`resource_id`),
+  KEY `fk_authz_delegations_resources_resource` (`resource_id`),
+  CONSTRAINT `fk_authz_delegations_resources_delegation` FOREIGN KEY (`delegation_id`) REFERENCES `authz_delegations` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT `fk_authz_delegations_resources_resource` FOREIGN KEY (`resource_id`) REFERENCES `authz_resources` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
@@ -139,6 +281,13 @@ DROP TABLE IF EXISTS `authz_policies`;
 CREATE TABLE `authz_policies` (
   `id` varchar(191) NOT NULL,
   `attribute_rules` json DEFAULT NULL,
+  `effect` varchar(191) DEFAULT 'allow',
+  `priority` bigint DEFAULT NULL,
+  `not_before` datetime(3) DEFAULT NULL,
+  `not_after` datetime(3) DEFAULT NULL,
+  `schedule` longtext,
+  `schedule_duration` longtext,
+  `schedule_timezone` longtext,
   `created_at` datetime(3) DEFAULT NULL,
   `updated_at` datetime(3) DEFAULT NULL,
   PRIMARY KEY (`id`)
@@ -229,6 +378,40 @@ CREATE TABLE `authz_principals_roles` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `authz_resource_kinds`
+--
+
+DROP TABLE IF EXISTS `authz_resource_kinds`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_resource_kinds` (
+  `id` varchar(191) NOT NULL,
+  `description` longtext,
+  `attributes_schema` json DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_resource_kinds_actions`
+--
+
+DROP TABLE IF EXISTS `authz_resource_kinds_actions`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_resource_kinds_actions` (
+  `resource_kind_id` varchar(191) NOT NULL,
+  `action_id` varchar(191) NOT NULL,
+  PRIMARY KEY (`resource_kind_id`,`action_id`),
+  KEY `fk_authz_resource_kinds_actions_action` (`action_id`),
+  CONSTRAINT `fk_authz_resource_kinds_actions_action` FOREIGN KEY (`action_id`) REFERENCES `authz_actions` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT `fk_authz_resource_kinds_actions_resource_kind` FOREIGN KEY (`resource_kind_id`) REFERENCES `authz_resource_kinds` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `authz_resources`
 --
@@ -264,6 +447,53 @@ CREATE TABLE `authz_resources_attributes` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `authz_review_campaigns`
+--
+
+DROP TABLE IF EXISTS `authz_review_campaigns`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_review_campaigns` (
+  `id` varchar(191) NOT NULL,
+  `description` longtext,
+  `reviewers` json DEFAULT NULL,
+  `deadline` datetime(3) DEFAULT NULL,
+  `auto_revoke` tinyint(1) DEFAULT NULL,
+  `status` varchar(191) DEFAULT NULL,
+  `reminded_at` datetime(3) DEFAULT NULL,
+  `closed_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_authz_review_campaigns_deadline` (`deadline`),
+  KEY `idx_authz_review_campaigns_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `authz_review_items`
+--
+
+DROP TABLE IF EXISTS `authz_review_items`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authz_review_items` (
+  `campaign_id` varchar(191) NOT NULL,
+  `principal_id` varchar(191) NOT NULL,
+  `role_id` varchar(191) NOT NULL,
+  `decision` varchar(191) DEFAULT NULL,
+  `reviewer_id` longtext,
+  `comment` longtext,
+  `decided_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`campaign_id`,`principal_id`,`role_id`),
+  KEY `idx_authz_review_items_decision` (`decision`),
+  CONSTRAINT `fk_authz_review_campaigns_items` FOREIGN KEY (`campaign_id`) REFERENCES `authz_review_campaigns` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `authz_roles`
 --
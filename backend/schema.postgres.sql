@@ -79,7 +79,10 @@ CREATE TABLE public.authz_audit (
     resource_value text,
     action text,
     is_allowed boolean,
-    policy_id text
+    algorithm text,
+    policy_id text,
+    cedar_policy_id text,
+    delegation_id text
 );
 
 
@@ -106,6 +109,57 @@ ALTER TABLE public.authz_audit_id_seq OWNER TO root;
 ALTER SEQUENCE public.authz_audit_id_seq OWNED BY public.authz_audit.id;
 
 
+--
+-- Name: authz_cedar_policies; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_cedar_policies (
+    id text NOT NULL,
+    source text,
+    effect text,
+    resource_kind text,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_cedar_policies OWNER TO root;
+
+--
+-- Name: authz_changes; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_changes (
+    id bigint NOT NULL,
+    item_type text,
+    item_id text,
+    created_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_changes OWNER TO root;
+
+--
+-- Name: authz_changes_id_seq; Type: SEQUENCE; Schema: public; Owner: root
+--
+
+CREATE SEQUENCE public.authz_changes_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.authz_changes_id_seq OWNER TO root;
+
+--
+-- Name: authz_changes_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: root
+--
+
+ALTER SEQUENCE public.authz_changes_id_seq OWNED BY public.authz_changes.id;
+
+
 --
 -- Name: authz_clients; Type: TABLE; Schema: public; Owner: root
 --
@@ -123,6 +177,51 @@ CREATE TABLE public.authz_clients (
 
 ALTER TABLE public.authz_clients OWNER TO root;
 
+--
+-- Name: authz_compile_jobs; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_compile_jobs (
+    id bigint NOT NULL,
+    target_type text,
+    target_id text,
+    status text,
+    attempts bigint,
+    last_error text,
+    next_run_at timestamp with time zone,
+    pending_since timestamp with time zone,
+    pending_revision bigint,
+    revision bigint,
+    claim_id text,
+    claim_expires_at timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_compile_jobs OWNER TO root;
+
+--
+-- Name: authz_compile_jobs_id_seq; Type: SEQUENCE; Schema: public; Owner: root
+--
+
+CREATE SEQUENCE public.authz_compile_jobs_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.authz_compile_jobs_id_seq OWNER TO root;
+
+--
+-- Name: authz_compile_jobs_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: root
+--
+
+ALTER SEQUENCE public.authz_compile_jobs_id_seq OWNED BY public.authz_compile_jobs.id;
+
+
 --
 -- Name: authz_compiled_policies; Type: TABLE; Schema: public; Owner: root
 --
@@ -141,6 +240,79 @@ CREATE TABLE public.authz_compiled_policies (
 
 ALTER TABLE public.authz_compiled_policies OWNER TO root;
 
+--
+-- Name: authz_compiled_versions; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_compiled_versions (
+    id bigint NOT NULL,
+    created_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_compiled_versions OWNER TO root;
+
+--
+-- Name: authz_compiled_versions_id_seq; Type: SEQUENCE; Schema: public; Owner: root
+--
+
+CREATE SEQUENCE public.authz_compiled_versions_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.authz_compiled_versions_id_seq OWNER TO root;
+
+--
+-- Name: authz_compiled_versions_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: root
+--
+
+ALTER SEQUENCE public.authz_compiled_versions_id_seq OWNED BY public.authz_compiled_versions.id;
+
+
+--
+-- Name: authz_delegations; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_delegations (
+    id text NOT NULL,
+    delegator_id text,
+    delegate_id text,
+    expires_at timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_delegations OWNER TO root;
+
+--
+-- Name: authz_delegations_actions; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_delegations_actions (
+    delegation_id text NOT NULL,
+    action_id text NOT NULL
+);
+
+
+ALTER TABLE public.authz_delegations_actions OWNER TO root;
+
+--
+-- Name: authz_delegations_resources; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_delegations_resources (
+    delegation_id text NOT NULL,
+    resource_id text NOT NULL
+);
+
+
+ALTER TABLE public.authz_delegations_resources OWNER TO root;
+
 --
 -- Name: authz_oauth_tokens; Type: TABLE; Schema: public; Owner: root
 --
@@ -187,6 +359,13 @@ ALTER SEQUENCE public.authz_oauth_tokens_id_seq OWNED BY public.authz_oauth_toke
 CREATE TABLE public.authz_policies (
     id text NOT NULL,
     attribute_rules jsonb,
+    effect text DEFAULT 'allow'::text,
+    priority bigint,
+    not_before timestamp with time zone,
+    not_after timestamp with time zone,
+    schedule text,
+    schedule_duration text,
+    schedule_timezone text,
     created_at timestamp with time zone,
     updated_at timestamp with time zone
 );
@@ -256,6 +435,33 @@ CREATE TABLE public.authz_principals_roles (
 
 ALTER TABLE public.authz_principals_roles OWNER TO root;
 
+--
+-- Name: authz_resource_kinds; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_resource_kinds (
+    id text NOT NULL,
+    description text,
+    attributes_schema jsonb,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_resource_kinds OWNER TO root;
+
+--
+-- Name: authz_resource_kinds_actions; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_resource_kinds_actions (
+    resource_kind_id text NOT NULL,
+    action_id text NOT NULL
+);
+
+
+ALTER TABLE public.authz_resource_kinds_actions OWNER TO root;
+
 --
 -- Name: authz_resources; Type: TABLE; Schema: public; Owner: root
 --
@@ -284,6 +490,45 @@ CREATE TABLE public.authz_resources_attributes (
 
 ALTER TABLE public.authz_resources_attributes OWNER TO root;
 
+--
+-- Name: authz_review_campaigns; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_review_campaigns (
+    id text NOT NULL,
+    description text,
+    reviewers jsonb,
+    deadline timestamp with time zone,
+    auto_revoke boolean,
+    status text,
+    reminded_at timestamp with time zone,
+    closed_at timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_review_campaigns OWNER TO root;
+
+--
+-- Name: authz_review_items; Type: TABLE; Schema: public; Owner: root
+--
+
+CREATE TABLE public.authz_review_items (
+    campaign_id text NOT NULL,
+    principal_id text NOT NULL,
+    role_id text NOT NULL,
+    decision text,
+    reviewer_id text,
+    comment text,
+    decided_at timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.authz_review_items OWNER TO root;
+
 --
 -- Name: authz_roles; Type: TABLE; Schema: public; Owner: root
 --
@@ -351,6 +596,27 @@ ALTER TABLE ONLY public.authz_attributes ALTER COLUMN id SET DEFAULT nextval('pu
 ALTER TABLE ONLY public.authz_audit ALTER COLUMN id SET DEFAULT nextval('public.authz_audit_id_seq'::regclass);
 
 
+--
+-- Name: authz_changes id; Type: DEFAULT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_changes ALTER COLUMN id SET DEFAULT nextval('public.authz_changes_id_seq'::regclass);
+
+
+--
+-- Name: authz_compile_jobs id; Type: DEFAULT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_compile_jobs ALTER COLUMN id SET DEFAULT nextval('public.authz_compile_jobs_id_seq'::regclass);
+
+
+--
+-- Name: authz_compiled_versions id; Type: DEFAULT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_compiled_versions ALTER COLUMN id SET DEFAULT nextval('public.authz_compiled_versions_id_seq'::regclass);
+
+
 --
 -- Name: authz_oauth_tokens id; Type: DEFAULT; Schema: public; Owner: root
 --
@@ -382,6 +648,22 @@ ALTER TABLE ONLY public.authz_audit
     ADD CONSTRAINT authz_audit_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: authz_cedar_policies authz_cedar_policies_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_cedar_policies
+    ADD CONSTRAINT authz_cedar_policies_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_changes authz_changes_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_changes
+    ADD CONSTRAINT authz_changes_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: authz_clients authz_clients_pkey; Type: CONSTRAINT; Schema: public; Owner: root
 --
@@ -390,6 +672,46 @@ ALTER TABLE ONLY public.authz_clients
     ADD CONSTRAINT authz_clients_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: authz_compile_jobs authz_compile_jobs_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_compile_jobs
+    ADD CONSTRAINT authz_compile_jobs_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_compiled_versions authz_compiled_versions_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_compiled_versions
+    ADD CONSTRAINT authz_compiled_versions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_delegations authz_delegations_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations
+    ADD CONSTRAINT authz_delegations_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_delegations_actions authz_delegations_actions_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_actions
+    ADD CONSTRAINT authz_delegations_actions_pkey PRIMARY KEY (delegation_id, action_id);
+
+
+--
+-- Name: authz_delegations_resources authz_delegations_resources_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_resources
+    ADD CONSTRAINT authz_delegations_resources_pkey PRIMARY KEY (delegation_id, resource_id);
+
+
 --
 -- Name: authz_oauth_tokens authz_oauth_tokens_pkey; Type: CONSTRAINT; Schema: public; Owner: root
 --
@@ -446,6 +768,22 @@ ALTER TABLE ONLY public.authz_principals_roles
     ADD CONSTRAINT authz_principals_roles_pkey PRIMARY KEY (role_id, principal_id);
 
 
+--
+-- Name: authz_resource_kinds authz_resource_kinds_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_resource_kinds
+    ADD CONSTRAINT authz_resource_kinds_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_resource_kinds_actions authz_resource_kinds_actions_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_resource_kinds_actions
+    ADD CONSTRAINT authz_resource_kinds_actions_pkey PRIMARY KEY (resource_kind_id, action_id);
+
+
 --
 -- Name: authz_resources_attributes authz_resources_attributes_pkey; Type: CONSTRAINT; Schema: public; Owner: root
 --
@@ -462,6 +800,22 @@ ALTER TABLE ONLY public.authz_resources
     ADD CONSTRAINT authz_resources_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: authz_review_campaigns authz_review_campaigns_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_review_campaigns
+    ADD CONSTRAINT authz_review_campaigns_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: authz_review_items authz_review_items_pkey; Type: CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_review_items
+    ADD CONSTRAINT authz_review_items_pkey PRIMARY KEY (campaign_id, principal_id, role_id);
+
+
 --
 -- Name: authz_roles authz_roles_pkey; Type: CONSTRAINT; Schema: public; Owner: root
 --
@@ -494,6 +848,55 @@ ALTER TABLE ONLY public.authz_users
     ADD CONSTRAINT authz_users_pkey PRIMARY KEY (username);
 
 
+--
+-- Name: idx_authz_cedar_policies_resource_kind; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_cedar_policies_resource_kind ON public.authz_cedar_policies USING btree (resource_kind);
+
+
+--
+-- Name: idx_authz_changes_created_at; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_changes_created_at ON public.authz_changes USING btree (created_at);
+
+
+--
+-- Name: idx_authz_compile_jobs_next_run_at; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_compile_jobs_next_run_at ON public.authz_compile_jobs USING btree (next_run_at);
+
+
+--
+-- Name: idx_authz_compile_jobs_pending_revision; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_compile_jobs_pending_revision ON public.authz_compile_jobs USING btree (pending_revision);
+
+
+--
+-- Name: idx_authz_compile_jobs_pending_since; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_compile_jobs_pending_since ON public.authz_compile_jobs USING btree (pending_since);
+
+
+--
+-- Name: idx_authz_compile_jobs_status; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_compile_jobs_status ON public.authz_compile_jobs USING btree (status);
+
+
+--
+-- Name: idx_authz_compile_jobs_target; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE UNIQUE INDEX idx_authz_compile_jobs_target ON public.authz_compile_jobs USING btree (target_type, target_id);
+
+
 --
 -- Name: idx_authz_compiled_policies_action_id; Type: INDEX; Schema: public; Owner: root
 --
@@ -501,6 +904,13 @@ ALTER TABLE ONLY public.authz_users
 CREATE INDEX idx_authz_compiled_policies_action_id ON public.authz_compiled_policies USING btree (action_id);
 
 
+--
+-- Name: idx_authz_compiled_policies_lookup; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_compiled_policies_lookup ON public.authz_compiled_policies USING btree (resource_kind, action_id, resource_value, principal_id, policy_id);
+
+
 --
 -- Name: idx_authz_compiled_policies_policy_id; Type: INDEX; Schema: public; Owner: root
 --
@@ -536,6 +946,96 @@ CREATE INDEX idx_authz_compiled_policies_resource_value ON public.authz_compiled
 CREATE INDEX idx_authz_compiled_policies_version ON public.authz_compiled_policies USING btree (version);
 
 
+--
+-- Name: idx_authz_delegations_delegate_id; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_delegations_delegate_id ON public.authz_delegations USING btree (delegate_id);
+
+
+--
+-- Name: idx_authz_delegations_delegator_id; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_delegations_delegator_id ON public.authz_delegations USING btree (delegator_id);
+
+
+--
+-- Name: idx_authz_delegations_expires_at; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_delegations_expires_at ON public.authz_delegations USING btree (expires_at);
+
+
+--
+-- Name: idx_authz_review_campaigns_deadline; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_review_campaigns_deadline ON public.authz_review_campaigns USING btree (deadline);
+
+
+--
+-- Name: idx_authz_review_campaigns_status; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_review_campaigns_status ON public.authz_review_campaigns USING btree (status);
+
+
+--
+-- Name: idx_authz_review_items_decision; Type: INDEX; Schema: public; Owner: root
+--
+
+CREATE INDEX idx_authz_review_items_decision ON public.authz_review_items USING btree (decision);
+
+
+--
+-- Name: authz_delegations fk_authz_delegations_delegate; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations
+    ADD CONSTRAINT fk_authz_delegations_delegate FOREIGN KEY (delegate_id) REFERENCES public.authz_principals(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_delegations fk_authz_delegations_delegator; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations
+    ADD CONSTRAINT fk_authz_delegations_delegator FOREIGN KEY (delegator_id) REFERENCES public.authz_principals(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_delegations_actions fk_authz_delegations_actions_action; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_actions
+    ADD CONSTRAINT fk_authz_delegations_actions_action FOREIGN KEY (action_id) REFERENCES public.authz_actions(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_delegations_actions fk_authz_delegations_actions_delegation; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_actions
+    ADD CONSTRAINT fk_authz_delegations_actions_delegation FOREIGN KEY (delegation_id) REFERENCES public.authz_delegations(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_delegations_resources fk_authz_delegations_resources_delegation; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_resources
+    ADD CONSTRAINT fk_authz_delegations_resources_delegation FOREIGN KEY (delegation_id) REFERENCES public.authz_delegations(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_delegations_resources fk_authz_delegations_resources_resource; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_delegations_resources
+    ADD CONSTRAINT fk_authz_delegations_resources_resource FOREIGN KEY (resource_id) REFERENCES public.authz_resources(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
 --
 -- Name: authz_policies_actions fk_authz_policies_actions_action; Type: FK CONSTRAINT; Schema: public; Owner: root
 --
@@ -600,6 +1100,22 @@ ALTER TABLE ONLY public.authz_principals_roles
     ADD CONSTRAINT fk_authz_principals_roles_role FOREIGN KEY (role_id) REFERENCES public.authz_roles(id) ON UPDATE CASCADE ON DELETE CASCADE;
 
 
+--
+-- Name: authz_resource_kinds_actions fk_authz_resource_kinds_actions_action; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_resource_kinds_actions
+    ADD CONSTRAINT fk_authz_resource_kinds_actions_action FOREIGN KEY (action_id) REFERENCES public.authz_actions(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
+--
+-- Name: authz_resource_kinds_actions fk_authz_resource_kinds_actions_resource_kind; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_resource_kinds_actions
+    ADD CONSTRAINT fk_authz_resource_kinds_actions_resource_kind FOREIGN KEY (resource_kind_id) REFERENCES public.authz_resource_kinds(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
 --
 -- Name: authz_resources_attributes fk_authz_resources_attributes_attribute; Type: FK CONSTRAINT; Schema: public; Owner: root
 --
@@ -616,6 +1132,14 @@ ALTER TABLE ONLY public.authz_resources_attributes
     ADD CONSTRAINT fk_authz_resources_attributes_resource FOREIGN KEY (resource_id) REFERENCES public.authz_resources(id) ON UPDATE CASCADE ON DELETE CASCADE;
 
 
+--
+-- Name: authz_review_items fk_authz_review_campaigns_items; Type: FK CONSTRAINT; Schema: public; Owner: root
+--
+
+ALTER TABLE ONLY public.authz_review_items
+    ADD CONSTRAINT fk_authz_review_campaigns_items FOREIGN KEY (campaign_id) REFERENCES public.authz_review_campaigns(id) ON UPDATE CASCADE ON DELETE CASCADE;
+
+
 --
 -- Name: authz_roles_policies fk_authz_roles_policies_policy; Type: FK CONSTRAINT; Schema: public; Owner: root
 --